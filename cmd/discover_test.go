@@ -2,7 +2,9 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/json"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -13,12 +15,12 @@ import (
 )
 
 type mockDiscoverRunner struct {
-	runFunc func(cmd *cobra.Command, projectPath string, interactive bool, force bool) error
+	runFunc func(cmd *cobra.Command, opts DiscoverOptions) error
 }
 
-func (m *mockDiscoverRunner) Run(cmd *cobra.Command, projectPath string, interactive bool, force bool) error {
+func (m *mockDiscoverRunner) Run(cmd *cobra.Command, opts DiscoverOptions) error {
 	if m.runFunc != nil {
-		return m.runFunc(cmd, projectPath, interactive, force)
+		return m.runFunc(cmd, opts)
 	}
 	return nil
 }
@@ -35,10 +37,10 @@ func TestDiscoverCommand(t *testing.T) {
 			name: "successful discovery",
 			args: []string{"discover", "--project-path", "/test/path"},
 			runner: &mockDiscoverRunner{
-				runFunc: func(cmd *cobra.Command, projectPath string, interactive bool, force bool) error {
-					assert.Equal(t, "/test/path", projectPath)
-					assert.False(t, interactive)
-					assert.False(t, force)
+				runFunc: func(cmd *cobra.Command, opts DiscoverOptions) error {
+					assert.Equal(t, "/test/path", opts.ProjectPath)
+					assert.False(t, opts.Interactive)
+					assert.False(t, opts.Force)
 					return nil
 				},
 			},
@@ -48,10 +50,10 @@ func TestDiscoverCommand(t *testing.T) {
 			name: "discovery with interactive mode",
 			args: []string{"discover", "--project-path", "/test/path", "--interactive"},
 			runner: &mockDiscoverRunner{
-				runFunc: func(cmd *cobra.Command, projectPath string, interactive bool, force bool) error {
-					assert.Equal(t, "/test/path", projectPath)
-					assert.True(t, interactive)
-					assert.False(t, force)
+				runFunc: func(cmd *cobra.Command, opts DiscoverOptions) error {
+					assert.Equal(t, "/test/path", opts.ProjectPath)
+					assert.True(t, opts.Interactive)
+					assert.False(t, opts.Force)
 					return nil
 				},
 			},
@@ -61,10 +63,32 @@ func TestDiscoverCommand(t *testing.T) {
 			name: "discovery with force flag",
 			args: []string{"discover", "--project-path", "/test/path", "--force"},
 			runner: &mockDiscoverRunner{
-				runFunc: func(cmd *cobra.Command, projectPath string, interactive bool, force bool) error {
-					assert.Equal(t, "/test/path", projectPath)
-					assert.False(t, interactive)
-					assert.True(t, force)
+				runFunc: func(cmd *cobra.Command, opts DiscoverOptions) error {
+					assert.Equal(t, "/test/path", opts.ProjectPath)
+					assert.False(t, opts.Interactive)
+					assert.True(t, opts.Force)
+					return nil
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "discovery with minimum confidence",
+			args: []string{"discover", "--project-path", "/test/path", "--min-confidence", "80"},
+			runner: &mockDiscoverRunner{
+				runFunc: func(cmd *cobra.Command, opts DiscoverOptions) error {
+					assert.Equal(t, 80, opts.MinConfidence)
+					return nil
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "discovery with framework filter",
+			args: []string{"discover", "--project-path", "/test/path", "--framework", "cobra,urfave/cli"},
+			runner: &mockDiscoverRunner{
+				runFunc: func(cmd *cobra.Command, opts DiscoverOptions) error {
+					assert.Equal(t, []string{"cobra", "urfave/cli"}, opts.Frameworks)
 					return nil
 				},
 			},
@@ -104,6 +128,52 @@ func TestDiscoverCommand(t *testing.T) {
 			}
 		})
 	}
+
+	// The suggested commands give the project path relative to the working
+	// directory when the project is below it
+	pathTests := []struct {
+		name     string
+		dir      string // project directory below the working directory, or "" for one outside it
+		relative bool   // pass the project path as given rather than as an absolute path
+		wantPath string // "" for the absolute project path
+	}{
+		{name: "absolute path below the working directory", dir: filepath.Join("apps", "tool"), wantPath: filepath.Join("apps", "tool")},
+		{name: "relative path", dir: filepath.Join("apps", "tool"), relative: true, wantPath: filepath.Join("apps", "tool")},
+		{name: "absolute path of the working directory", dir: ".", wantPath: "."},
+		{name: "absolute path outside the working directory"},
+	}
+	for _, tt := range pathTests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldRunner := discoverRunner
+			discoverRunner = NewDefaultDiscoverRunner()
+			defer func() { discoverRunner = oldRunner }()
+
+			workDir := t.TempDir()
+			project := t.TempDir()
+			if tt.dir != "" {
+				project = filepath.Join(workDir, tt.dir)
+			}
+			createTestCobraProject(t, project)
+			t.Chdir(workDir)
+
+			arg, wantPath := project, tt.wantPath
+			if tt.relative {
+				arg = tt.dir
+			}
+			if wantPath == "" {
+				wantPath = project
+			}
+
+			cmd := NewRootCmd()
+			cmd.SetArgs([]string{"discover", "--project-path", arg})
+			var buf bytes.Buffer
+			cmd.SetOut(&buf)
+			cmd.SetErr(&buf)
+
+			require.NoError(t, cmd.Execute())
+			assert.Contains(t, buf.String(), "Command: cliguard generate --project-path "+wantPath+" --entrypoint")
+		})
+	}
 }
 
 func TestDefaultDiscoverRunner(t *testing.T) {
@@ -117,7 +187,7 @@ func TestDefaultDiscoverRunner(t *testing.T) {
 		cmd.SetOut(&buf)
 
 		runner := NewDefaultDiscoverRunner()
-		err := runner.Run(cmd, tempDir, false, false)
+		err := runner.Run(cmd, DiscoverOptions{ProjectPath: tempDir})
 		require.NoError(t, err)
 
 		output := buf.String()
@@ -134,7 +204,7 @@ func TestDefaultDiscoverRunner(t *testing.T) {
 		cmd.SetOut(&buf)
 
 		runner := NewDefaultDiscoverRunner()
-		err := runner.Run(cmd, tempDir, false, false)
+		err := runner.Run(cmd, DiscoverOptions{ProjectPath: tempDir})
 		require.NoError(t, err)
 
 		output := buf.String()
@@ -151,17 +221,185 @@ func TestDefaultDiscoverRunner(t *testing.T) {
 		cmd.SetIn(strings.NewReader("1\n"))
 
 		runner := NewDefaultDiscoverRunner()
-		err := runner.Run(cmd, tempDir, true, false)
+		err := runner.Run(cmd, DiscoverOptions{ProjectPath: tempDir, Interactive: true})
 		require.NoError(t, err)
 
 		output := buf.String()
 		assert.Contains(t, output, "Selected entrypoint:")
 	})
 
+	t.Run("json output", func(t *testing.T) {
+		tempDir := t.TempDir()
+		createTestCobraProject(t, tempDir)
+
+		cmd := &cobra.Command{}
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+
+		runner := NewDefaultDiscoverRunner()
+		err := runner.Run(cmd, DiscoverOptions{ProjectPath: tempDir, Output: "json"})
+		require.NoError(t, err)
+
+		var candidates []map[string]interface{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &candidates))
+		require.NotEmpty(t, candidates)
+		assert.Equal(t, "cobra", candidates[0]["framework"])
+		assert.Contains(t, candidates[0]["command"], "cliguard generate")
+	})
+
+	t.Run("framework filter", func(t *testing.T) {
+		tempDir := t.TempDir()
+		createTestCobraProject(t, tempDir)
+
+		cmd := &cobra.Command{}
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+
+		runner := NewDefaultDiscoverRunner()
+		require.NoError(t, runner.Run(cmd, DiscoverOptions{ProjectPath: tempDir, Output: "json", Frameworks: []string{"kong", "flag"}}))
+		assert.Equal(t, "[]", strings.TrimSpace(buf.String()))
+
+		buf.Reset()
+		require.NoError(t, runner.Run(cmd, DiscoverOptions{ProjectPath: tempDir, Output: "json", Frameworks: []string{"cobra"}}))
+		assert.Contains(t, buf.String(), `"framework": "cobra"`)
+	})
+
+	t.Run("unknown framework", func(t *testing.T) {
+		runner := NewDefaultDiscoverRunner()
+		err := runner.Run(&cobra.Command{}, DiscoverOptions{ProjectPath: t.TempDir(), Frameworks: []string{"cobra", "clap"}})
+		require.Error(t, err)
+		assert.Equal(t, "unknown framework 'clap' (supported: cobra, urfave/cli, flag, kingpin, kong)", err.Error())
+	})
+
+	t.Run("verbose output", func(t *testing.T) {
+		tempDir := t.TempDir()
+		createTestCobraProject(t, tempDir)
+
+		cmd := &cobra.Command{}
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+
+		runner := NewDefaultDiscoverRunner()
+		err := runner.Run(cmd, DiscoverOptions{ProjectPath: tempDir, Verbose: true})
+		require.NoError(t, err)
+
+		output := buf.String()
+		assert.Contains(t, output, "Found")
+		assert.Contains(t, output, "Discovery diagnostics:")
+		assert.Contains(t, output, "Go files scanned:")
+	})
+
+	t.Run("minimum confidence", func(t *testing.T) {
+		tempDir := t.TempDir()
+		createTestCobraProject(t, tempDir)
+
+		cmd := &cobra.Command{}
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+
+		runner := NewDefaultDiscoverRunner()
+		require.NoError(t, runner.Run(cmd, DiscoverOptions{ProjectPath: tempDir, Output: "json"}))
+		var all []map[string]interface{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &all))
+
+		buf.Reset()
+		require.NoError(t, runner.Run(cmd, DiscoverOptions{ProjectPath: tempDir, Output: "json", MinConfidence: 95}))
+		var confident []map[string]interface{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &confident))
+		require.NotEmpty(t, confident)
+		assert.Less(t, len(confident), len(all))
+		for _, candidate := range confident {
+			assert.GreaterOrEqual(t, candidate["confidence"], float64(95))
+		}
+
+		err := runner.Run(cmd, DiscoverOptions{ProjectPath: tempDir, MinConfidence: 101})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "--min-confidence must be between 0 and 100")
+	})
+
+	t.Run("verbose json output keeps diagnostics off stdout", func(t *testing.T) {
+		tempDir := t.TempDir()
+		createTestCobraProject(t, tempDir)
+
+		cmd := &cobra.Command{}
+		var stdout, stderr bytes.Buffer
+		cmd.SetOut(&stdout)
+		cmd.SetErr(&stderr)
+
+		runner := NewDefaultDiscoverRunner()
+		err := runner.Run(cmd, DiscoverOptions{ProjectPath: tempDir, Output: "json", Verbose: true})
+		require.NoError(t, err)
+
+		var candidates []map[string]interface{}
+		require.NoError(t, json.Unmarshal(stdout.Bytes(), &candidates))
+		assert.Contains(t, stderr.String(), "Discovery diagnostics:")
+	})
+
+	t.Run("output script", func(t *testing.T) {
+		tempDir := t.TempDir()
+		createTestCobraProject(t, tempDir)
+
+		cmd := &cobra.Command{}
+		var stdout, stderr bytes.Buffer
+		cmd.SetOut(&stdout)
+		cmd.SetErr(&stderr)
+
+		runner := NewDefaultDiscoverRunner()
+		err := runner.Run(cmd, DiscoverOptions{ProjectPath: tempDir, Verbose: true, OutputScript: true})
+		require.NoError(t, err)
+
+		output := stdout.String()
+		assert.True(t, strings.HasPrefix(output, "#!/usr/bin/env bash\n"), "output: %s", output)
+		assert.Contains(t, output, "set -e\n")
+		assert.Contains(t, output, "cliguard generate --project-path . --entrypoint")
+		assert.NotContains(t, output, "Searching for CLI entrypoints")
+		assert.Contains(t, stderr.String(), "Discovery diagnostics:")
+
+		err = runner.Run(cmd, DiscoverOptions{ProjectPath: tempDir, Output: "json", OutputScript: true})
+		assert.ErrorContains(t, err, "--output-script cannot be combined")
+	})
+
+	t.Run("json output with interactive mode", func(t *testing.T) {
+		cmd := &cobra.Command{}
+		runner := NewDefaultDiscoverRunner()
+		err := runner.Run(cmd, DiscoverOptions{ProjectPath: t.TempDir(), Interactive: true, Output: "json"})
+		assert.Error(t, err)
+	})
+
+	t.Run("relative project path from a subdirectory", func(t *testing.T) {
+		tempDir := t.TempDir()
+		projectDir := filepath.Join(tempDir, "project")
+		require.NoError(t, os.MkdirAll(filepath.Join(tempDir, "work", "sub"), 0755))
+		require.NoError(t, os.MkdirAll(projectDir, 0755))
+		createTestCobraProject(t, projectDir)
+		t.Chdir(filepath.Join(tempDir, "work", "sub"))
+
+		for _, output := range []string{"", "json"} {
+			cmd := &cobra.Command{}
+			var buf bytes.Buffer
+			cmd.SetOut(&buf)
+
+			runner := NewDefaultDiscoverRunner()
+			err := runner.Run(cmd, DiscoverOptions{ProjectPath: "../../project", Output: output})
+			require.NoError(t, err)
+			assert.Contains(t, buf.String(), "--project-path "+projectDir+" ")
+			assert.NotContains(t, buf.String(), "--project-path ../../project")
+		}
+
+		cmd := &cobra.Command{}
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+		cmd.SetIn(strings.NewReader("1\n"))
+
+		runner := NewDefaultDiscoverRunner()
+		require.NoError(t, runner.Run(cmd, DiscoverOptions{ProjectPath: "../../project", Interactive: true}))
+		assert.Contains(t, buf.String(), "--project-path "+projectDir+" --entrypoint")
+	})
+
 	t.Run("project path does not exist", func(t *testing.T) {
 		cmd := &cobra.Command{}
 		runner := NewDefaultDiscoverRunner()
-		err := runner.Run(cmd, "/nonexistent/path", false, false)
+		err := runner.Run(cmd, DiscoverOptions{ProjectPath: "/nonexistent/path"})
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "no such file or directory")
 	})
@@ -1,15 +1,40 @@
 package cmd
 
 import (
+	"bufio"
+	"bytes"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
+	"net/http"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/hiAndrewQuinn/cliguard/internal/analysis"
+	"github.com/hiAndrewQuinn/cliguard/internal/audit"
+	"github.com/hiAndrewQuinn/cliguard/internal/benchmark"
+	"github.com/hiAndrewQuinn/cliguard/internal/config"
+	"github.com/hiAndrewQuinn/cliguard/internal/contract"
 	"github.com/hiAndrewQuinn/cliguard/internal/discovery"
+	"github.com/hiAndrewQuinn/cliguard/internal/doctor"
+	cliguarderrors "github.com/hiAndrewQuinn/cliguard/internal/errors"
+	"github.com/hiAndrewQuinn/cliguard/internal/executor"
+	"github.com/hiAndrewQuinn/cliguard/internal/filesystem"
+	"github.com/hiAndrewQuinn/cliguard/internal/formatter"
+	"github.com/hiAndrewQuinn/cliguard/internal/github"
+	"github.com/hiAndrewQuinn/cliguard/internal/output"
+	"github.com/hiAndrewQuinn/cliguard/internal/repl"
+	"github.com/hiAndrewQuinn/cliguard/internal/semver"
 	"github.com/hiAndrewQuinn/cliguard/internal/service"
+	"github.com/hiAndrewQuinn/cliguard/internal/validator"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 // Execute runs the root command
@@ -20,9 +45,35 @@ func Execute() {
 // ExecuteWithWriter runs the root command with a custom writer for testing
 func ExecuteWithWriter(errWriter io.Writer) {
 	if err := NewRootCmd().Execute(); err != nil {
-		fmt.Fprintln(errWriter, err)
+		// The validation report has already been printed
+		if errors.Is(err, cliguarderrors.ErrBreakingChanges) {
+			os.Exit(2)
+		}
+		if !errors.Is(err, cliguarderrors.ErrValidationFailed) {
+			fmt.Fprintln(errWriter, err)
+		}
+		os.Exit(1)
+	}
+}
+
+// exitOnValidationFailure makes commands exit with status 1 once a failed
+// validation report has been printed. The REPL disables it so that the
+// session keeps running.
+var exitOnValidationFailure = true
+
+// exitOnFailure exits the process if err reports a validation failure
+// (status 1) or breaking changes (status 2), otherwise it returns err
+func exitOnFailure(err error) error {
+	if !exitOnValidationFailure {
+		return err
+	}
+	if errors.Is(err, cliguarderrors.ErrBreakingChanges) {
+		os.Exit(2)
+	}
+	if errors.Is(err, cliguarderrors.ErrValidationFailed) {
 		os.Exit(1)
 	}
+	return err
 }
 
 var (
@@ -32,6 +83,119 @@ var (
 	timeout      time.Duration
 	interactive  bool
 	force        bool
+	dryRun       bool
+	debug        bool
+
+	includeHiddenCommands  bool
+	includeHidden          bool
+	includeDeprecated      bool
+	includePersistentFlags bool
+	withExamples           bool
+	extractGodoc           bool
+	withValidation         bool
+	cobraVersion           string
+	outputContractVersion  int
+	outputEncoding         string
+	generateOutput         string
+	fromBinary             string
+	fromOpenAPI            string
+	toolName               string
+	stripDefaults          bool
+	stripRules             []string
+	stripHelpCommand       bool
+	stripCompletionCommand bool
+	outputFile             string
+	omitUnchanged          bool
+	cobraUseNameOnly       bool
+	normalizeUse           bool
+	strictUse              bool
+	headerComment          string
+	noHeader               bool
+	groupBySubpackage      bool
+	runnableOnly           bool
+	inspectorTimeout       time.Duration
+
+	validateOutput        string
+	validateOutputFile    string
+	validateWatch         bool
+	strictSortOrder       bool
+	expandedContract      bool
+	githubComment         bool
+	sarifPath             string
+	discoverOutput        string
+	discoverVerbose       bool
+	discoverScript        bool
+	discoverMinConfidence int
+	discoverFrameworks    []string
+
+	contractEntrypoint  string
+	flipContract        bool
+	strictContract      bool
+	expectVersion       bool
+	versionCommand      string
+	ignoreCommandsRegex []string
+	focusPaths          []string
+	allowExtraCommands  bool
+	allowExtraFlags     bool
+	strictMode          bool
+	warnOnly            bool
+	failFast            bool
+	noFailFast          bool
+	summarize           bool
+	top                 int
+	contractHTTPHeaders []string
+	noContractCache     bool
+	generateOnMismatch  bool
+	maxAutoUpdates      int
+	semverCheck         bool
+	outputBumpLevel     string
+	skipBuild           bool
+	cliSnapshot         string
+	writeSnapshot       string
+	annotateContract    bool
+	clearAnnotations    bool
+	ignoreShort         bool
+	ignoreLong          bool
+	static              bool
+
+	batchConfigPath string
+
+	showFormat string
+
+	inspectPretty  bool
+	inspectCompact bool
+	jqFilter       string
+
+	oldProjectPath string
+	oldEntrypoint  string
+	newProjectPath string
+	newEntrypoint  string
+
+	diffFrom     string
+	diffTo       string
+	diffExitCode bool
+
+	updateYes bool
+
+	initYes bool
+
+	doctorFix bool
+
+	migrateInput  string
+	migrateOutput string
+
+	graphOutput       string
+	graphFormat       string
+	graphIncludeFlags bool
+
+	importsCommand string
+
+	lintFix    bool
+	lintOutput string
+
+	benchmarkIterations int
+	benchmarkOutput     string
+	benchmarkContract   string
 )
 
 func NewRootCmd() *cobra.Command {
@@ -40,8 +204,27 @@ func NewRootCmd() *cobra.Command {
 		Short: "A contract-based validation tool for Cobra CLIs",
 		Long: `Cliguard validates Cobra command structures against a YAML contract file.
 It ensures your CLI commands, flags, and structure remain consistent over time.`,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if dryRun && cmd.Name() != "generate" {
+				return fmt.Errorf("--dry-run is only supported by generate")
+			}
+			if err := applyConfig(cmd); err != nil {
+				return err
+			}
+			// Without --debug only warnings are logged, so that log lines
+			// don't break up the progress spinner
+			level := slog.LevelWarn
+			if debug {
+				level = slog.LevelDebug
+			}
+			slog.SetLogLoggerLevel(level)
+			return nil
+		},
 	}
 
+	rootCmd.PersistentFlags().BoolVar(&debug, "debug", false, "Log each step of CLI inspection to stderr")
+	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "Print the commands cliguard would run instead of running them (generate only)")
+
 	validateCmd := &cobra.Command{
 		Use:   "validate",
 		Short: "Validate a Cobra CLI against a contract file",
@@ -52,10 +235,61 @@ and flags match the expected specification.`,
 	}
 
 	validateCmd.Flags().StringVar(&projectPath, "project-path", "", "Path to the root of the target Go project (defaults to current directory)")
-	validateCmd.Flags().StringVar(&contractPath, "contract", "", "Path to the contract file (defaults to cliguard.yaml in project path)")
+	validateCmd.Flags().StringVar(&contractPath, "contract", "", "Path or http(s):// URL of the contract file (defaults to cliguard.yaml in project path)")
+	validateCmd.Flags().StringArrayVar(&contractHTTPHeaders, "contract-http-header", nil, "HTTP header to send when --contract is a URL, as 'Name: value' (e.g. 'Authorization: Bearer $TOKEN'); can be repeated")
+	validateCmd.Flags().BoolVar(&noContractCache, "no-contract-cache", false, "Download a --contract URL each time it is read instead of once per run")
+	validateCmd.Flags().BoolVar(&generateOnMismatch, "generate-on-mismatch", false, "If the CLI only has commands or flags the contract lacks, regenerate the contract file and validate again; removed commands or flags still fail")
+	validateCmd.Flags().IntVar(&maxAutoUpdates, "max-auto-updates", 1, "Most times --generate-on-mismatch regenerates the contract in one run")
+	validateCmd.Flags().BoolVar(&semverCheck, "semver-check", false, "Suggest the version bump the differences need (major for removed commands or flags and changed flag types, minor for new ones, patch for other changes) instead of failing")
+	validateCmd.Flags().StringVar(&outputBumpLevel, "output-bump-level", "", "With --semver-check, also write the suggested bump (none, patch, minor or major) to this file")
+	validateCmd.Flags().BoolVar(&skipBuild, "skip-build", false, "Don't build the project; validate the CLI snapshot given with --cli-snapshot instead")
+	validateCmd.Flags().BoolVar(&static, "static", false, "Read the CLI structure from the project source without building or running it; flag defaults, completions and flag groups are not validated")
+	validateCmd.Flags().StringVar(&cliSnapshot, "cli-snapshot", "", "Validate the CLI recorded in this JSON snapshot, written by generate --write-snapshot or inspect, instead of inspecting the project")
+	validateCmd.Flags().BoolVar(&annotateContract, "annotate-contract", false, "Write each validation error as a comment above its line in the contract file")
+	validateCmd.Flags().BoolVar(&clearAnnotations, "clear-annotations", false, "Remove the comments written by --annotate-contract from the contract file, without validating")
+	validateCmd.MarkFlagsMutuallyExclusive("annotate-contract", "clear-annotations")
 	validateCmd.Flags().StringVar(&entrypoint, "entrypoint", "", "The function that returns the root command (e.g., github.com/user/repo/cmd.NewRootCmd)")
 	validateCmd.Flags().DurationVar(&timeout, "timeout", 30*time.Second, "Timeout for CLI inspection (e.g., 30s, 2m, 5m)")
+	validateCmd.Flags().DurationVar(&inspectorTimeout, "inspector-timeout", service.DefaultInspectorTimeout, "Timeout for CLI inspection when --timeout is 0, so a build that never finishes can't hang validation")
 	validateCmd.Flags().BoolVar(&force, "force", false, "Force operation even with unsupported CLI frameworks")
+	validateCmd.Flags().StringVar(&validateOutput, "output", validator.ReportFormatText, "Report format: text, json, yaml, markdown, sarif or junit (also --report-format)")
+	validateCmd.Flags().StringVar(&validateOutputFile, "output-file", "", "Write the report to this file instead of stdout (requires a format other than text)")
+	validateCmd.Flags().BoolVar(&validateWatch, "watch", false, "Validate again each time a Go file of the project or the contract changes, until interrupted with Ctrl+C")
+	validateCmd.Flags().StringVar(&sarifPath, "emit-sarif", "", "Also write the result as a SARIF 2.1.0 file, for GitHub code scanning")
+	validateCmd.Flags().BoolVar(&githubComment, "github-comment", false, "Post the report as a markdown comment on the pull request given by GITHUB_REPOSITORY and GITHUB_PR_NUMBER, authenticated with GITHUB_TOKEN")
+	validateCmd.Flags().BoolVar(&strictSortOrder, "strict-sort-order", false, "Check that commands are listed in the order given by their sort_order in the contract")
+	validateCmd.Flags().BoolVar(&expandedContract, "expanded-contract", false, "Validate a contract generated with --include-persistent-flags, where each command lists the persistent flags it inherits")
+	validateCmd.Flags().StringVar(&contractEntrypoint, "contract-from-entrypoint", "", "Generate the contract from this entrypoint, e.g. a previous version of the CLI, instead of loading a contract file")
+	validateCmd.Flags().BoolVar(&flipContract, "flip", false, "With --contract-from-entrypoint, validate that CLI against a contract generated from --entrypoint instead")
+	validateCmd.Flags().BoolVar(&strictContract, "strict-contract", false, "Fail if the contract has fields cliguard does not recognize, instead of printing a notice")
+	validateCmd.Flags().BoolVar(&expectVersion, "expect-version", false, "Also check that the version the CLI prints matches the contract's version field")
+	validateCmd.Flags().StringVar(&versionCommand, "version-command", "", "Arguments that make the CLI print its version, e.g. 'version --short' (defaults to --version, or the version subcommand)")
+	validateCmd.Flags().StringArrayVar(&ignoreCommandsRegex, "ignore-commands-regex", nil, "RE2 pattern of command paths such as 'myapp db migrate' to leave out of validation, with their subcommands (repeatable)")
+	validateCmd.Flags().StringSliceVar(&focusPaths, "path", nil, "Validate only the command at this dot-separated path, e.g. 'create.user', and its subcommands (repeatable)")
+	validateCmd.Flags().BoolVar(&allowExtraCommands, "allow-extra-commands", false, "Don't fail on commands the contract doesn't list; they are still reported")
+	validateCmd.Flags().BoolVar(&allowExtraFlags, "allow-extra-flags", false, "Don't fail on flags the contract doesn't list; they are still reported")
+	validateCmd.Flags().BoolVar(&strictMode, "strict", false, "Fail on every command and flag the contract doesn't list, including hidden and deprecated ones")
+	validateCmd.MarkFlagsMutuallyExclusive("strict", "allow-extra-commands")
+	validateCmd.MarkFlagsMutuallyExclusive("strict", "allow-extra-flags")
+	validateCmd.Flags().BoolVar(&warnOnly, "warn-only", false, "Report validation errors as warnings and exit 0, e.g. while a CLI is brought in line with its contract")
+	validateCmd.Flags().BoolVar(&ignoreShort, "ignore-short", false, "Don't compare the short descriptions of the commands")
+	validateCmd.Flags().BoolVar(&ignoreLong, "ignore-long", false, "Don't compare the long descriptions of the commands")
+	validateCmd.Flags().BoolVar(&failFast, "fail-fast", false, "Stop validation at the first error")
+	validateCmd.Flags().BoolVar(&noFailFast, "no-fail-fast", false, "Report every validation error (the default)")
+	validateCmd.Flags().BoolVar(&summarize, "summarize", false, "Print only the error counts by type and the most critical errors")
+	validateCmd.Flags().IntVar(&top, "top", 0, "Report only the first N errors, or the N most critical with --summarize (0 for all)")
+	validateCmd.MarkFlagsMutuallyExclusive("fail-fast", "no-fail-fast")
+	validateCmd.Flags().BoolVar(&strictUse, "strict-use", false, "Compare each command's full Use, including its argument pattern, instead of only the command names")
+	validateCmd.Flags().BoolVar(&cobraUseNameOnly, "cobra-use-name-only", false, "Compare only the command names of the Use fields")
+	validateCmd.Flags().MarkDeprecated("cobra-use-name-only", "only command names are compared by default; use --strict-use to compare argument patterns too")
+	validateCmd.MarkFlagsMutuallyExclusive("strict-use", "cobra-use-name-only")
+	// --report-format is another name for --output
+	validateCmd.Flags().SetNormalizeFunc(func(f *pflag.FlagSet, name string) pflag.NormalizedName {
+		if name == "report-format" {
+			name = "output"
+		}
+		return pflag.NormalizedName(name)
+	})
 
 	rootCmd.AddCommand(validateCmd)
 
@@ -72,10 +306,358 @@ creating an initial contract from an existing CLI.`,
 	generateCmd.Flags().StringVar(&projectPath, "project-path", "", "Path to the root of the target Go project (defaults to current directory)")
 	generateCmd.Flags().StringVar(&entrypoint, "entrypoint", "", "The function that returns the root command (e.g., github.com/user/repo/cmd.NewRootCmd)")
 	generateCmd.Flags().DurationVar(&timeout, "timeout", 30*time.Second, "Timeout for CLI inspection (e.g., 30s, 2m, 5m)")
+	generateCmd.Flags().DurationVar(&inspectorTimeout, "inspector-timeout", service.DefaultInspectorTimeout, "Timeout for CLI inspection when --timeout is 0, so a build that never finishes can't hang generation")
 	generateCmd.Flags().BoolVar(&force, "force", false, "Force operation even with unsupported CLI frameworks")
+	generateCmd.Flags().BoolVar(&includeHiddenCommands, "include-hidden-commands", false, "Include hidden commands in the generated contract")
+	generateCmd.Flags().BoolVar(&includeHidden, "include-hidden", false, "Include flags hidden with MarkHidden in the generated contract, marked hidden")
+	generateCmd.Flags().BoolVar(&includeDeprecated, "include-deprecated", true, "Include deprecated flags with their deprecation messages (--include-deprecated=false omits them)")
+	generateCmd.Flags().BoolVar(&runnableOnly, "runnable-only", false, "Omit commands that run nothing: no Run, RunE, PreRun, PostRun, PreRunE or PostRunE and no runnable subcommands")
+	generateCmd.Flags().BoolVar(&includePersistentFlags, "include-persistent-flags", false, "List inherited persistent flags on every subcommand (validate the result with --expanded-contract)")
+	generateCmd.Flags().BoolVar(&withExamples, "with-examples", false, "Populate command examples from CLI invocations found in *_test.go files")
+	generateCmd.Flags().StringVar(&writeSnapshot, "write-snapshot", "", "Also write the inspected CLI as JSON to this file, for validate --skip-build --cli-snapshot")
+	generateCmd.Flags().BoolVar(&extractGodoc, "extract-godoc", false, "Fill in each command's empty long description from the doc comment of the function or variable that builds it")
+	generateCmd.Flags().BoolVar(&withValidation, "with-validation", false, "Record the values each flag's completion function offers as its enum (runs the completion functions)")
+	generateCmd.Flags().StringVar(&cobraVersion, "cobra-version", "", "Cobra version to target, e.g. v1.6.0 (defaults to the version in the project's go.mod)")
+	generateCmd.Flags().IntVar(&outputContractVersion, "output-contract-version", 1, "Contract format to generate: 1 (single root) or 2 (multi-root)")
+	generateCmd.Flags().StringVar(&generateOutput, "output", output.FormatText, "Contract format: text or yaml for the YAML contract, or json for the contract as JSON without the header comment")
+	generateCmd.Flags().StringVar(&outputEncoding, "output-encoding", service.EncodingUTF8, "Output encoding: utf8, ascii (escape non-ASCII characters) or utf8bom")
+	generateCmd.Flags().StringVar(&fromBinary, "from-binary", "", "Generate from a compiled CLI's --help output instead of the project source")
+	generateCmd.Flags().BoolVar(&static, "static", false, "Read the CLI structure from the project source without building or running it; flag defaults, completions and flag groups are not recorded")
+	generateCmd.Flags().StringVar(&fromOpenAPI, "from-openapi", "", "Generate from the OpenAPI 3.0 spec of an API whose CLI was generated from it")
+	generateCmd.Flags().StringVar(&toolName, "tool-name", "", "Root command name of the CLI generated with --from-openapi (defaults to the spec title)")
+	generateCmd.Flags().StringVar(&outputFile, "output-file", "", "Write the contract to this file instead of stdout (unchanged files are not rewritten)")
+	generateCmd.Flags().BoolVar(&omitUnchanged, "omit-unchanged", false, "With --output-file, also leave the file alone if only its formatting, comments or flag and command order differ")
+	generateCmd.Flags().BoolVar(&stripDefaults, "strip-defaults", false, "Omit the --help and --version flags and completion command that Cobra adds")
+	generateCmd.Flags().StringArrayVar(&stripRules, "strip-rule", nil, "Additional 'flag:<regex>' or 'command:<regex>' rule matching flag usage or command short text to omit")
+	generateCmd.Flags().BoolVar(&stripHelpCommand, "strip-help-command", true, "Omit the help command that Cobra adds, at every level of the command tree")
+	generateCmd.Flags().BoolVar(&stripCompletionCommand, "strip-completion-command", true, "Omit the completion command that Cobra adds, with its subcommands")
+	generateCmd.Flags().BoolVar(&normalizeUse, "normalize-use", true, "Write only the command name in each Use field, without the argument pattern (--normalize-use=false keeps it; validate with --strict-use)")
+	generateCmd.Flags().BoolVar(&cobraUseNameOnly, "cobra-use-name-only", false, "Write only the command name in each Use field")
+	generateCmd.Flags().MarkDeprecated("cobra-use-name-only", "command names only are written by default; see --normalize-use")
+	generateCmd.Flags().StringVar(&headerComment, "header-comment", "", "Go template for the comment at the top of the contract, using {{.Version}}, {{.Project}}, {{.Entrypoint}} and {{.Timestamp}}")
+	generateCmd.Flags().BoolVar(&noHeader, "no-header", false, "Leave the header comment out of the contract")
+	generateCmd.Flags().BoolVar(&groupBySubpackage, "group-by-subpackage", false, "With --output-file, write the commands of each Go package to its own file next to the contract, which includes them")
 
 	rootCmd.AddCommand(generateCmd)
 
+	// Show command
+	showCmd := &cobra.Command{
+		Use:   "show",
+		Short: "Print the structure of a Cobra CLI without a contract",
+		Long: `Show inspects a Go project's Cobra command structure and prints it as a
+tree of commands and flags. No contract is needed, which makes it useful for
+exploring a CLI or for debugging differences between a CLI and its contract.
+
+Use --format json to print the raw inspection result for other tools.`,
+		RunE: runShow,
+	}
+
+	showCmd.Flags().StringVar(&projectPath, "project-path", "", "Path to the root of the target Go project (defaults to current directory)")
+	showCmd.Flags().StringVar(&entrypoint, "entrypoint", "", "The function that returns the root command (e.g., github.com/user/repo/cmd.NewRootCmd)")
+	showCmd.Flags().DurationVar(&timeout, "timeout", 30*time.Second, "Timeout for CLI inspection (e.g., 30s, 2m, 5m)")
+	showCmd.Flags().StringVar(&showFormat, "format", service.ShowFormatTree, "Output format: tree or json")
+
+	rootCmd.AddCommand(showCmd)
+
+	// Inspect command
+	inspectCmd := &cobra.Command{
+		Use:   "inspect",
+		Short: "Print the raw inspection result of a Cobra CLI as JSON",
+		Long: `Inspect prints the raw JSON the inspector extracts from a Go project's Cobra
+commands, for piping into other tools. It is the same JSON as show --format json,
+without the environment variable bindings found in the source.
+
+The output is indented unless --compact (or --pretty=false) is given. Use
+--jq-filter to pick values out of it without installing jq, e.g.
+
+  cliguard inspect --entrypoint github.com/user/repo/cmd.NewRootCmd --jq-filter '.commands[].use'
+
+The filter is run by gojq, a Go implementation of the jq language, so it can
+use the full jq syntax and builtins such as select, map and sort_by. Each
+value it outputs is printed as JSON.`,
+		RunE: runInspect,
+	}
+
+	inspectCmd.Flags().StringVar(&projectPath, "project-path", "", "Path to the root of the target Go project (defaults to current directory)")
+	inspectCmd.Flags().StringVar(&entrypoint, "entrypoint", "", "The function that returns the root command (e.g., github.com/user/repo/cmd.NewRootCmd)")
+	inspectCmd.Flags().DurationVar(&timeout, "timeout", 30*time.Second, "Timeout for CLI inspection (e.g., 30s, 2m, 5m)")
+	inspectCmd.Flags().BoolVar(&inspectPretty, "pretty", true, "Indent the JSON output")
+	inspectCmd.Flags().BoolVar(&inspectCompact, "compact", false, "Print each JSON value on one line (overrides --pretty)")
+	inspectCmd.Flags().StringVar(&jqFilter, "jq-filter", "", "jq filter to apply to the output, e.g. '.commands[].use'")
+
+	rootCmd.AddCommand(inspectCmd)
+
+	// Compare command
+	compareCmd := &cobra.Command{
+		Use:   "compare",
+		Short: "Compare the structure of two Cobra CLIs without a contract",
+		Long: `Compare inspects two Go projects and reports how the new CLI differs from
+the old one, without writing a contract first. This is useful before and after
+a refactor or a framework migration.
+
+Exits with 0 if the CLIs are identical, 1 if they differ only in non-breaking
+ways (additions, description changes) and 2 if the new CLI removes or changes
+something users of the old one rely on.`,
+		RunE: runCompare,
+	}
+
+	compareCmd.Flags().StringVar(&oldProjectPath, "old-project", "", "Path to the project containing the old CLI (required)")
+	compareCmd.Flags().StringVar(&oldEntrypoint, "old-entrypoint", "", "The function that returns the old root command (required)")
+	compareCmd.Flags().StringVar(&newProjectPath, "new-project", "", "Path to the project containing the new CLI (required)")
+	compareCmd.Flags().StringVar(&newEntrypoint, "new-entrypoint", "", "The function that returns the new root command (required)")
+	compareCmd.Flags().DurationVar(&timeout, "timeout", 30*time.Second, "Timeout for each CLI inspection (e.g., 30s, 2m, 5m)")
+
+	_ = compareCmd.MarkFlagRequired("old-project")
+	_ = compareCmd.MarkFlagRequired("old-entrypoint")
+	_ = compareCmd.MarkFlagRequired("new-project")
+	_ = compareCmd.MarkFlagRequired("new-entrypoint")
+
+	rootCmd.AddCommand(compareCmd)
+
+	// Diff command
+	diffCmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Show the structural changes between two contract files",
+		Long: `Diff compares two contract files, such as the one committed to main and the
+one on a branch, and lists the commands and flags added, removed or modified
+in the second, like git diff: '-' lines for removed elements and old values,
+'+' lines for added elements and new values.
+
+Exits with 0 even when the contracts differ, unless --exit-code is given, in
+which case it exits with 1 if there are differences.`,
+		RunE: runDiff,
+	}
+
+	diffCmd.Flags().StringVar(&diffFrom, "from", "", "Path to the contract to compare against (required)")
+	diffCmd.Flags().StringVar(&diffTo, "to", "", "Path to the changed contract (required)")
+	diffCmd.Flags().BoolVar(&diffExitCode, "exit-code", false, "Exit with status 1 if the contracts differ")
+
+	_ = diffCmd.MarkFlagRequired("from")
+	_ = diffCmd.MarkFlagRequired("to")
+
+	rootCmd.AddCommand(diffCmd)
+
+	// Update command
+	updateCmd := &cobra.Command{
+		Use:   "update",
+		Short: "Regenerate a contract from the CLI and review the changes",
+		Long: `Update generates the CLI's current contract and merges it into the existing
+contract file. The commands and flags come from the CLI, while the fields
+generate never writes, the version and each command's sort_order, are kept.
+The contract is generated with the options it appears to have been generated
+with, e.g. with hidden flags if it lists some.
+
+The changes are listed like the output of diff, and written after
+confirmation, or at once with --yes. Contracts that include other files or
+extend commands, and v2 contracts, can't be updated.`,
+		RunE: runUpdate,
+	}
+
+	updateCmd.Flags().StringVar(&projectPath, "project-path", "", "Path to the root of the target Go project (defaults to current directory)")
+	updateCmd.Flags().StringVar(&contractPath, "contract", "", "Path to the contract file to update (defaults to cliguard.yaml in project path)")
+	updateCmd.Flags().StringVar(&entrypoint, "entrypoint", "", "The function that returns the root command (e.g., github.com/user/repo/cmd.NewRootCmd)")
+	updateCmd.Flags().DurationVar(&timeout, "timeout", 30*time.Second, "Timeout for CLI inspection (e.g., 30s, 2m, 5m)")
+	updateCmd.Flags().BoolVarP(&updateYes, "yes", "y", false, "Write the changes without asking for confirmation")
+
+	_ = updateCmd.MarkFlagRequired("entrypoint")
+
+	rootCmd.AddCommand(updateCmd)
+
+	// Init command
+	initCmd := &cobra.Command{
+		Use:   "init",
+		Short: "Set up cliguard for a project, step by step",
+		Long: `Init guides you through setting up cliguard for a Cobra CLI. It discovers
+the CLI's entrypoints and asks which one to use, generates the contract and
+shows it, then writes it to cliguard.yaml in the project once you confirm.
+
+If the project has a Makefile or GitHub Actions workflows, init then offers to
+add a step running validate to them. With --yes, the entrypoint discover
+finds most likely is used and every step is taken without asking.`,
+		RunE: runInit,
+	}
+
+	initCmd.Flags().StringVar(&projectPath, "project-path", "", "Path to the root of the target Go project (defaults to current directory)")
+	initCmd.Flags().StringVar(&entrypoint, "entrypoint", "", "The function that returns the root command, instead of choosing one of the discovered entrypoints")
+	initCmd.Flags().DurationVar(&timeout, "timeout", 30*time.Second, "Timeout for CLI inspection (e.g., 30s, 2m, 5m)")
+	initCmd.Flags().BoolVarP(&initYes, "yes", "y", false, "Use the most likely entrypoint and accept every step without asking")
+
+	rootCmd.AddCommand(initCmd)
+
+	// Validate-all command
+	validateAllCmd := &cobra.Command{
+		Use:   "validate-all",
+		Short: "Validate multiple Cobra CLIs against their contracts",
+		Long: `Validate-all validates every project listed in a batch config file
+concurrently and prints a summary of the results. The command exits with a
+non-zero status if any project fails validation.
+
+Example config:
+
+  projects:
+    - name: service-a
+      project_path: ./service-a
+      entrypoint: github.com/org/service-a/cmd.NewRootCmd
+    - project_path: ./service-b
+      contract: ./contracts/service-b.yaml
+      entrypoint: github.com/org/service-b/cmd.NewRootCmd`,
+		RunE: runValidateAll,
+	}
+
+	validateAllCmd.Flags().StringVar(&batchConfigPath, "config", "", "Path to the batch config file listing the projects to validate (required)")
+	validateAllCmd.Flags().DurationVar(&timeout, "timeout", 30*time.Second, "Timeout for each CLI inspection (e.g., 30s, 2m, 5m)")
+
+	_ = validateAllCmd.MarkFlagRequired("config")
+
+	rootCmd.AddCommand(validateAllCmd)
+
+	// Completion-check command
+	completionCheckCmd := &cobra.Command{
+		Use:   "completion-check",
+		Short: "Validate flag shell completions against a contract file",
+		Long: `Completion-check inspects a Go project's Cobra command structure and checks
+that every flag with a 'completion' entry in the contract registers the
+expected shell completion:
+
+  file    cmd.MarkFlagFilename
+  dir     cmd.MarkFlagDirname
+  custom  cmd.RegisterFlagCompletionFunc
+  none    no completion registered
+
+Missing or unexpected commands and flags are not reported; use validate for
+a full structural check.`,
+		RunE: runCompletionCheck,
+	}
+
+	completionCheckCmd.Flags().StringVar(&projectPath, "project-path", "", "Path to the root of the target Go project (defaults to current directory)")
+	completionCheckCmd.Flags().StringVar(&contractPath, "contract", "", "Path to the contract file (defaults to cliguard.yaml in project path)")
+	completionCheckCmd.Flags().StringVar(&entrypoint, "entrypoint", "", "The function that returns the root command (e.g., github.com/user/repo/cmd.NewRootCmd)")
+	completionCheckCmd.Flags().DurationVar(&timeout, "timeout", 30*time.Second, "Timeout for CLI inspection (e.g., 30s, 2m, 5m)")
+
+	rootCmd.AddCommand(completionCheckCmd)
+
+	// Audit command
+	auditCmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Scan a contract for security and documentation anti-patterns",
+		Long: `Audit checks a contract file for common CLI anti-patterns, such as
+secrets passed as plain string flags (visible to other users via ps), flags
+without usage descriptions, and global flags like --verbose that are not
+persistent. Each finding has a severity and a recommendation.`,
+		RunE: runAudit,
+	}
+
+	auditCmd.Flags().StringVar(&contractPath, "contract", "", "Path to the contract file (defaults to cliguard.yaml in the current directory)")
+
+	rootCmd.AddCommand(auditCmd)
+
+	// Lint command
+	lintCmd := &cobra.Command{
+		Use:   "lint",
+		Short: "Check a contract for style issues",
+		Long: `Lint checks a contract file for style issues: short descriptions that are
+not capitalized, end with a period or are longer than 80 characters, flag
+names that are not kebab-case, and flags without usage strings.
+
+Use --fix to repair them. Fixes are applied from least to most destructive:
+placeholder usage strings are added, short descriptions are capitalized,
+stripped of trailing periods and truncated with "...", and finally flags are
+renamed to kebab-case. The fixed contract is written back to the contract
+file, or to --output.`,
+		RunE: runLint,
+	}
+
+	lintCmd.Flags().StringVar(&contractPath, "contract", "", "Path to the contract file (defaults to cliguard.yaml in the current directory)")
+	lintCmd.Flags().BoolVar(&lintFix, "fix", false, "Automatically fix the issues and write the fixed contract")
+	lintCmd.Flags().StringVar(&lintOutput, "output", "", "Write the fixed contract to this file instead of the contract file (requires --fix)")
+
+	rootCmd.AddCommand(lintCmd)
+
+	// Doctor command
+	doctorCmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Diagnose common cliguard setup problems",
+		Long: `Doctor checks that Go is installed, that the project's go.mod and go.sum are
+up to date, that a cliguard.yaml exists, that cliguard is on $PATH and that
+its cache directory exists.
+
+Use --fix to repair what can be repaired: run go mod tidy, create a template
+cliguard.yaml, print $PATH instructions and create the cache directory.`,
+		RunE: runDoctor,
+	}
+
+	doctorCmd.Flags().StringVar(&projectPath, "project-path", "", "Path to the root of the target Go project (defaults to current directory)")
+	doctorCmd.Flags().BoolVar(&doctorFix, "fix", false, "Automatically fix the problems that can be fixed")
+
+	rootCmd.AddCommand(doctorCmd)
+
+	// Migrate contract command
+	migrateCmd := &cobra.Command{
+		Use:   "migrate-contract",
+		Short: "Convert a v1 contract to the v2 format",
+		Long: `Migrate-contract converts a v1 contract to the v2 format: the contract becomes
+the root named 'default', and the version, schema and tags fields are added.
+Migrating a v2 contract only adds the fields it is missing, so the command can
+be run more than once.
+
+The result is printed to stdout unless --output is given.`,
+		RunE: runMigrateContract,
+	}
+
+	migrateCmd.Flags().StringVar(&migrateInput, "input", "", "Path to the contract to migrate (required)")
+	migrateCmd.Flags().StringVar(&migrateOutput, "output", "", "Path to write the migrated contract to (defaults to stdout)")
+	_ = migrateCmd.MarkFlagRequired("input")
+
+	rootCmd.AddCommand(migrateCmd)
+
+	// Contract graph command
+	contractGraphCmd := &cobra.Command{
+		Use:   "contract-graph",
+		Short: "Render a contract's command tree as a diagram",
+		Long: `Contract-graph renders the commands of a contract as a diagram for
+documentation. Each command is a node labelled with its use line, short
+description and number of flags, connected to its parent command. Use
+--include-flags to also show each flag as a leaf node.
+
+Supported formats:
+
+  dot      Graphviz (render with: dot -Tsvg graph.dot -o graph.svg)
+  mermaid  Mermaid flowchart, which GitHub renders in markdown
+  puml     PlantUML
+
+The diagram is printed to stdout unless --output is given.`,
+		RunE: runContractGraph,
+	}
+
+	contractGraphCmd.Flags().StringVar(&contractPath, "contract", "", "Path to the contract file (defaults to cliguard.yaml in the current directory)")
+	contractGraphCmd.Flags().StringVar(&graphOutput, "output", "", "Path to write the diagram to (defaults to stdout)")
+	contractGraphCmd.Flags().StringVar(&graphFormat, "format", formatter.GraphFormatDOT, "Diagram format: dot, mermaid or puml")
+	contractGraphCmd.Flags().BoolVar(&graphIncludeFlags, "include-flags", false, "Show flags as leaf nodes of their commands")
+
+	rootCmd.AddCommand(contractGraphCmd)
+
+	// Imports command
+	importsCmd := &cobra.Command{
+		Use:   "imports",
+		Short: "List the Go files and packages that define a command",
+		Long: `Imports finds the Go source files that contribute to a command: the file
+with its cobra.Command literal, the files that define its flags, and the file
+that adds it to its parent command, with their packages. It helps find the code
+to change, and its owners, when a command's interface changes.
+
+The source is analyzed without building the project, by following AddCommand
+calls from the command returned by --entrypoint. Commands built at runtime,
+such as in loops, are not found.`,
+		RunE: runImports,
+	}
+
+	importsCmd.Flags().StringVar(&projectPath, "project-path", "", "Path to the root of the target Go project (defaults to current directory)")
+	importsCmd.Flags().StringVar(&entrypoint, "entrypoint", "", "The function that returns the root command (e.g., github.com/user/repo/cmd.NewRootCmd)")
+	importsCmd.Flags().StringVar(&importsCommand, "command", "", "Path of the command below the root, e.g. 'db migrate' (defaults to the root command)")
+	_ = importsCmd.MarkFlagRequired("entrypoint")
+
+	rootCmd.AddCommand(importsCmd)
+
 	// Discover command
 	discoverCmd := &cobra.Command{
 		Use:   "discover",
@@ -89,203 +671,2073 @@ This helps you quickly identify where commands are defined in unfamiliar codebas
 	discoverCmd.Flags().StringVar(&projectPath, "project-path", "", "Path to the root of the target Go project (required)")
 	discoverCmd.Flags().BoolVarP(&interactive, "interactive", "i", false, "Interactive mode: prompt to select from multiple candidates")
 	discoverCmd.Flags().BoolVar(&force, "force", false, "Force operation even with unsupported CLI frameworks")
+	discoverCmd.Flags().StringVar(&discoverOutput, "output", "text", "Output format: text or json")
+	discoverCmd.Flags().BoolVarP(&discoverVerbose, "verbose", "v", false, "Print discovery diagnostics: files scanned, skipped directories and parse errors")
+	discoverCmd.Flags().BoolVar(&discoverScript, "output-script", false, "Print a bash script that generates a contract for each entrypoint with a confidence of 70% or more")
+	discoverCmd.Flags().IntVar(&discoverMinConfidence, "min-confidence", 0, "Only report entrypoints with a confidence of at least this percentage (0-100)")
+	discoverCmd.Flags().StringSliceVar(&discoverFrameworks, "framework", nil, "Only report entrypoints of these frameworks, comma-separated: "+strings.Join(discovery.FrameworkNames(), ", "))
 
 	_ = discoverCmd.MarkFlagRequired("project-path")
 
 	rootCmd.AddCommand(discoverCmd)
 
-	return rootCmd
-}
-
-// ValidateRunner interface for dependency injection
-type ValidateRunner interface {
-	Run(cmd *cobra.Command, projectPath, contractPath, entrypoint string, timeout time.Duration, force bool) error
-}
+	// Benchmark command
+	benchmarkCmd := &cobra.Command{
+		Use:   "benchmark",
+		Short: "Measure how long inspecting and validating a project takes",
+		Long: `Benchmark runs the inspection cycle several times and reports the minimum,
+median, 95th percentile and maximum time of each phase as a markdown table:
 
-// DefaultValidateRunner is the default implementation
-type DefaultValidateRunner struct {
-	service *service.ValidateService
-}
+  go_build          setting up the inspector module and resolving dependencies
+  go_run_inspector  compiling and running the inspector program
+  json_parse        decoding the inspector's output
+  validation        comparing the CLI with the contract (with --with-contract)
 
-// NewDefaultValidateRunner creates a new default runner
-func NewDefaultValidateRunner() *DefaultValidateRunner {
-	return &DefaultValidateRunner{
-		service: service.NewValidateService(),
+No contract is needed: only inspection is measured unless --with-contract is
+given. Use --output to also write the results as JSON.`,
+		RunE: runBenchmark,
 	}
-}
 
-// Run executes the validation
-func (r *DefaultValidateRunner) Run(cmd *cobra.Command, projectPath, contractPath, entrypoint string, timeout time.Duration, force bool) error {
-	// Check if entrypoint is provided and detect framework
-	if entrypoint != "" {
-		framework, err := discovery.DetectEntrypointFramework(projectPath, entrypoint, nil)
-		if err == nil && framework != "" && framework != "cobra" {
-			if !force {
-				return fmt.Errorf("Error: cliguard currently only supports Cobra CLIs. Support for %s is coming soon!\nUse --force to proceed anyway (may produce unexpected results)", framework)
-			}
-			cmd.Printf("⚠️  Warning: Proceeding with unsupported framework %s. Results may be unreliable.\n\n", framework)
-		}
-	}
+	benchmarkCmd.Flags().StringVar(&projectPath, "project-path", "", "Path to the root of the target Go project (defaults to current directory)")
+	benchmarkCmd.Flags().StringVar(&entrypoint, "entrypoint", "", "The function that returns the root command (e.g., github.com/user/repo/cmd.NewRootCmd)")
+	benchmarkCmd.Flags().IntVar(&benchmarkIterations, "iterations", benchmark.DefaultIterations, "Number of times to run the inspection cycle")
+	benchmarkCmd.Flags().StringVar(&benchmarkOutput, "output", "", "Also write the results as JSON to this file")
+	benchmarkCmd.Flags().StringVar(&benchmarkContract, "with-contract", "", "Also measure validation against this contract")
+	benchmarkCmd.Flags().DurationVar(&timeout, "timeout", 30*time.Second, "Timeout for each inspection (e.g., 30s, 2m, 5m)")
 
-	opts := service.ValidateOptions{
-		ProjectPath:  projectPath,
-		ContractPath: contractPath,
-		Entrypoint:   entrypoint,
-		Timeout:      timeout,
-	}
+	rootCmd.AddCommand(benchmarkCmd)
 
-	// Print progress messages
-	if contractPath == "" {
-		contractPath = "cliguard.yaml in project path"
-	}
-	cmd.Printf("Loading contract from: %s\n", contractPath)
-	cmd.Printf("Inspecting CLI structure in: %s\n", projectPath)
-	cmd.Println("Validating CLI structure against contract...")
+	// REPL command
+	replCmd := &cobra.Command{
+		Use:   "repl",
+		Short: "Start an interactive cliguard session",
+		Long: `Repl starts an interactive session for exploring and validating CLIs.
+Type cliguard commands without the 'cliguard' prefix; --project-path,
+--entrypoint and --contract are remembered once given, so you can generate a
+contract, edit it and re-run validate without retyping them.
 
-	// Run validation
-	result, err := r.service.Validate(opts)
-	if err != nil {
-		return err
+Commands run in the same process, and history is saved to
+~/.config/cliguard/history.`,
+		RunE: runRepl,
 	}
 
-	// Report results
-	if result.Success {
-		cmd.Println("✅ Validation passed! CLI structure matches the contract.")
-		return nil
-	}
+	rootCmd.AddCommand(replCmd)
 
-	// Print validation errors
-	cmd.Println("❌ Validation failed!")
-	cmd.Println()
-	result.Result.PrintReport()
+	// Cobra adds the completion command when the CLI runs; add it now to
+	// document how to install the scripts
+	rootCmd.InitDefaultCompletionCmd()
+	if completionCmd, _, err := rootCmd.Find([]string{"completion"}); err == nil && completionCmd != rootCmd {
+		completionCmd.Long = completionLong
+	}
+	registerFlagCompletions(rootCmd)
 
-	os.Exit(1)
-	return nil
+	return rootCmd
 }
 
-// Global runner for testing
-var validateRunner ValidateRunner = NewDefaultValidateRunner()
+// completionLong is the long description of the completion command
+const completionLong = `Completion prints a script that makes your shell complete cliguard's
+commands and flags. Project paths complete to directories, and entrypoints to
+the ones discover finds in the project.
 
-func runValidate(cmd *cobra.Command, args []string) error {
-	// Default to current directory if no project path specified
-	path := projectPath
-	if path == "" {
-		var err error
-		path, err = os.Getwd()
-		if err != nil {
-			return fmt.Errorf("failed to get current directory: %w", err)
-		}
-	}
-	return validateRunner.Run(cmd, path, contractPath, entrypoint, timeout, force)
-}
+To load completions:
 
-// GenerateRunner interface for dependency injection
-type GenerateRunner interface {
-	Run(cmd *cobra.Command, projectPath, entrypoint string, timeout time.Duration, force bool) error
-}
+Bash, with the bash-completion package installed:
 
-// DefaultGenerateRunner is the default implementation
-type DefaultGenerateRunner struct {
-	service *service.GenerateService
+  # In the current shell
+  source <(cliguard completion bash)
+
+  # In every new shell, on Linux
+  cliguard completion bash > /etc/bash_completion.d/cliguard
+
+  # In every new shell, on macOS
+  cliguard completion bash > $(brew --prefix)/etc/bash_completion.d/cliguard
+
+Zsh:
+
+  # If shell completion isn't enabled yet, enable it once
+  echo "autoload -U compinit; compinit" >> ~/.zshrc
+
+  cliguard completion zsh > "${fpath[1]}/_cliguard"
+
+Fish:
+
+  cliguard completion fish > ~/.config/fish/completions/cliguard.fish
+
+PowerShell:
+
+  cliguard completion powershell | Out-String | Invoke-Expression
+
+  # Add that line to your PowerShell profile to load completions in every
+  # new session
+
+Start a new shell for the completions to take effect.`
+
+// entrypointFlags pairs each flag taking an entrypoint with the flag giving
+// the project it is found in
+var entrypointFlags = []struct{ project, entrypoint string }{
+	{"project-path", "entrypoint"},
+	{"project-path", "contract-from-entrypoint"},
+	{"old-project", "old-entrypoint"},
+	{"new-project", "new-entrypoint"},
 }
 
-// NewDefaultGenerateRunner creates a new default runner
-func NewDefaultGenerateRunner() *DefaultGenerateRunner {
-	return &DefaultGenerateRunner{
-		service: service.NewGenerateService(),
+// registerFlagCompletions completes the project path flags of cmd and its
+// subcommands with directories, and their entrypoint flags with the
+// entrypoints discovered in the project
+func registerFlagCompletions(cmd *cobra.Command) {
+	for _, f := range entrypointFlags {
+		if cmd.Flags().Lookup(f.project) != nil {
+			_ = cmd.MarkFlagDirname(f.project)
+		}
+		if cmd.Flags().Lookup(f.entrypoint) != nil {
+			_ = cmd.RegisterFlagCompletionFunc(f.entrypoint, completeEntrypoints(f.project))
+		}
+	}
+	for _, sub := range cmd.Commands() {
+		registerFlagCompletions(sub)
 	}
 }
 
-// Run executes the generation
-func (r *DefaultGenerateRunner) Run(cmd *cobra.Command, projectPath, entrypoint string, timeout time.Duration, force bool) error {
-	// Check if entrypoint is provided and detect framework
-	if entrypoint != "" {
-		framework, err := discovery.DetectEntrypointFramework(projectPath, entrypoint, nil)
-		if err == nil && framework != "" && framework != "cobra" {
-			if !force {
-				return fmt.Errorf("Error: cliguard currently only supports Cobra CLIs. Support for %s is coming soon!\nUse --force to proceed anyway (may produce unexpected results)", framework)
+// completeEntrypoints returns a completion function suggesting the
+// entrypoints discover finds in the project given by projectFlag, or in the
+// current directory if the command has no such flag or it isn't set. Each
+// suggestion is described by its framework and confidence.
+func completeEntrypoints(projectFlag string) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		path := "."
+		if flag := cmd.Flags().Lookup(projectFlag); flag != nil && flag.Value.String() != "" {
+			path = flag.Value.String()
+		}
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		result, err := discovery.NewDiscoverer(absPath, nil).DiscoverEntrypoints()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		var completions []cobra.Completion
+		seen := make(map[string]bool)
+		for _, candidate := range result.Candidates {
+			entrypoint := candidate.Entrypoint()
+			if entrypoint == "" || seen[entrypoint] || !strings.HasPrefix(entrypoint, toComplete) {
+				continue
 			}
-			cmd.Printf("⚠️  Warning: Proceeding with unsupported framework %s. Results may be unreliable.\n\n", framework)
+			seen[entrypoint] = true
+			completions = append(completions, cobra.CompletionWithDesc(entrypoint, fmt.Sprintf("%s, %d%% confidence", candidate.Framework, candidate.Confidence)))
 		}
+		return completions, cobra.ShellCompDirectiveNoFileComp
 	}
+}
 
-	opts := service.GenerateOptions{
-		ProjectPath: projectPath,
-		Entrypoint:  entrypoint,
-		Timeout:     timeout,
+// applyConfig sets the flags of cmd that weren't given on the command line to
+// the defaults of the .cliguard.yaml in the project path, or else in the
+// home directory
+func applyConfig(cmd *cobra.Command) error {
+	dir := projectPath
+	if dir == "" {
+		dir = "."
 	}
-
-	// Run generation
-	yamlContent, err := r.service.Generate(opts)
+	cfg, err := config.LoadConfig(dir)
 	if err != nil {
 		return err
 	}
 
-	// Print YAML to stdout
-	fmt.Print(yamlContent)
+	defaults := map[string]string{
+		"project-path": cfg.ProjectPath,
+		"entrypoint":   cfg.Entrypoint,
+		"contract":     cfg.Contract,
+	}
+	if cfg.Timeout != 0 {
+		defaults["timeout"] = cfg.Timeout.String()
+	}
+	if cfg.Strict {
+		defaults["strict"] = "true"
+	}
+	if cfg.WarnOnly {
+		defaults["warn-only"] = "true"
+	}
+	if cfg.MinConfidence != 0 {
+		defaults["min-confidence"] = strconv.Itoa(cfg.MinConfidence)
+	}
+	for name, value := range defaults {
+		flag := cmd.Flags().Lookup(name)
+		if value == "" || flag == nil || flag.Changed || exclusiveFlagChanged(cmd, flag) {
+			continue
+		}
+		// Setting the value directly leaves the flag unchanged, so that
+		// cobra's flag group checks only see the command line
+		if err := flag.Value.Set(value); err != nil {
+			return fmt.Errorf("invalid %s in config '%s': %w", name, cfg.Path, err)
+		}
+	}
 	return nil
 }
 
-// Global runner for testing
-var generateRunner GenerateRunner = NewDefaultGenerateRunner()
+// mutuallyExclusiveAnnotation is the annotation cobra's
+// MarkFlagsMutuallyExclusive adds to each flag of a group, with the names
+// of the group's flags separated by spaces
+const mutuallyExclusiveAnnotation = "cobra_annotation_mutually_exclusive"
 
-func runGenerate(cmd *cobra.Command, args []string) error {
-	// Default to current directory if no project path specified
-	path := projectPath
-	if path == "" {
-		var err error
-		path, err = os.Getwd()
-		if err != nil {
-			return fmt.Errorf("failed to get current directory: %w", err)
+// exclusiveFlagChanged reports whether a flag that is mutually exclusive with
+// flag was given on the command line, e.g. --allow-extra-commands for
+// --strict, in which case a config default for flag is not applied
+func exclusiveFlagChanged(cmd *cobra.Command, flag *pflag.Flag) bool {
+	for _, group := range flag.Annotations[mutuallyExclusiveAnnotation] {
+		for _, name := range strings.Fields(group) {
+			if other := cmd.Flags().Lookup(name); other != nil && other != flag && other.Changed {
+				return true
+			}
 		}
 	}
-	return generateRunner.Run(cmd, path, entrypoint, timeout, force)
+	return false
 }
 
-// DiscoverRunner interface for dependency injection
-type DiscoverRunner interface {
-	Run(cmd *cobra.Command, projectPath string, interactive bool, force bool) error
+// ValidateRunner interface for dependency injection
+type ValidateRunner interface {
+	Run(cmd *cobra.Command, opts service.ValidateOptions, report ValidateReportOptions) error
+	Watch(opts service.ValidateOptions, onChange func()) error
 }
 
-// DefaultDiscoverRunner is the default implementation
-type DefaultDiscoverRunner struct{}
+// ValidateReportOptions configures how the validate command runs a
+// validation and reports it, and what is done with its result besides, as
+// opposed to service.ValidateOptions, which configures the validation itself
+type ValidateReportOptions struct {
+	// Output is the report format: text (the default), json, yaml,
+	// markdown, sarif or junit
+	Output string
 
-// NewDefaultDiscoverRunner creates a new default runner
-func NewDefaultDiscoverRunner() *DefaultDiscoverRunner {
-	return &DefaultDiscoverRunner{}
-}
+	// GitHubComment posts the report to the pull request being built
+	GitHubComment bool
 
-// Run executes the discovery
-func (r *DefaultDiscoverRunner) Run(cmd *cobra.Command, projectPath string, interactive bool, force bool) error {
-	// Convert to absolute path if needed
-	absPath, err := filepath.Abs(projectPath)
-	if err != nil {
-		return fmt.Errorf("failed to resolve project path: %w", err)
-	}
+	// SARIFPath is a file to write a SARIF log to, besides the report
+	SARIFPath string
 
-	discoverer := discovery.NewDiscoverer(absPath, nil)
+	// Summarize prints only the error counts by type and the Top most
+	// critical errors (validator.DefaultSummaryTop if Top is 0)
+	Summarize bool
 
-	fmt.Fprintf(cmd.OutOrStdout(), "Searching for CLI entrypoints in: %s\n\n", projectPath)
+	// Top reports only the first Top errors, or the most critical with
+	// Summarize (0 for all)
+	Top int
 
-	candidates, err := discoverer.DiscoverEntrypoints()
-	if err != nil {
-		return fmt.Errorf("failed to discover entrypoints: %w", err)
-	}
+	// GenerateOnMismatch regenerates the contract file and validates again
+	// when the CLI only has commands or flags the contract lacks, at most
+	// MaxAutoUpdates times
+	GenerateOnMismatch bool
+	MaxAutoUpdates     int
 
-	// Handle interactive mode
-	if interactive && len(candidates) > 1 {
-		selector := discovery.NewInteractiveSelector(cmd.InOrStdin(), cmd.OutOrStdout())
-		selected, err := selector.SelectCandidate(candidates)
-		if err != nil {
-			return err
-		}
+	// SemverCheck suggests the version bump the differences need instead
+	// of failing, and also writes it to BumpLevelPath if set
+	SemverCheck   bool
+	BumpLevelPath string
+
+	// AnnotateContract writes each validation error as a comment above its
+	// line in the contract file; ClearAnnotations removes those comments
+	// without validating
+	AnnotateContract bool
+	ClearAnnotations bool
+
+	// OutputFile is a file to write a machine-readable report to instead
+	// of stdout
+	OutputFile string
+
+	// Force validates CLIs of frameworks other than Cobra, with a warning
+	Force bool
+
+	// InspectorTimeout is the timeout for CLI inspection when
+	// service.ValidateOptions.Timeout is 0
+	InspectorTimeout time.Duration
+}
+
+// PRCommenter posts comments to a pull request
+type PRCommenter interface {
+	PostPRComment(body string) error
+}
+
+// DefaultValidateRunner is the default implementation
+type DefaultValidateRunner struct {
+	service *service.ValidateService
+
+	// NewPRCommenter creates the commenter used by --github-comment
+	NewPRCommenter func() (PRCommenter, error)
+
+	// NewProgress creates the indicator shown while the project builds
+	NewProgress func(w io.Writer) output.Progress
+
+	// GenerateContract writes the contract generated with opts to
+	// outputPath, for --generate-on-mismatch. Defaults to
+	// GenerateService.GenerateToFile.
+	GenerateContract func(opts service.GenerateOptions, outputPath string) (bool, error)
+
+	// NewWatcher creates the watcher used by --watch
+	NewWatcher func() filesystem.Watcher
+
+	// Interrupts returns the channel Watch stops on, and a function to call
+	// when it is done with it. Defaults to the process's interrupt signals.
+	Interrupts func() (<-chan os.Signal, func())
+
+	// WatchDelay is how long Watch waits after a change for more before
+	// calling onChange
+	WatchDelay time.Duration
+}
+
+// watchDebounce is how long validate --watch waits for changes to settle, so
+// saving several files validates once
+const watchDebounce = 500 * time.Millisecond
+
+// NewDefaultValidateRunner creates a new default runner
+func NewDefaultValidateRunner() *DefaultValidateRunner {
+	return &DefaultValidateRunner{
+		service: service.NewValidateService(),
+		NewPRCommenter: func() (PRCommenter, error) {
+			client, err := github.NewClientFromEnv(os.Getenv)
+			if err != nil {
+				return nil, err
+			}
+			return client, nil
+		},
+		NewProgress:      output.NewProgress,
+		GenerateContract: service.NewGenerateService().GenerateToFile,
+		NewWatcher: func() filesystem.Watcher {
+			return filesystem.NewFSNotifyWatcher()
+		},
+		Interrupts: func() (<-chan os.Signal, func()) {
+			interrupts := make(chan os.Signal, 1)
+			signal.Notify(interrupts, os.Interrupt)
+			return interrupts, func() { signal.Stop(interrupts) }
+		},
+		WatchDelay: watchDebounce,
+	}
+}
+
+// Watch calls onChange after the Go files of opts.ProjectPath, other than
+// tests and those in vendor and hidden directories, or the contract at
+// opts.ContractPath change, collecting the changes made within WatchDelay of
+// each other into one call. It returns when interrupted.
+func (r *DefaultValidateRunner) Watch(opts service.ValidateOptions, onChange func()) error {
+	projectPath, err := filepath.Abs(opts.ProjectPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve project path '%s': %w", opts.ProjectPath, err)
+	}
+	contractFile := opts.ContractPath
+	if contractFile == "" {
+		contractFile = filepath.Join(projectPath, "cliguard.yaml")
+	}
+	paths := []string{projectPath}
+	if contract.IsURL(contractFile) {
+		contractFile = ""
+	} else if contractFile, err = filepath.Abs(contractFile); err != nil {
+		return fmt.Errorf("failed to resolve contract path '%s': %w", opts.ContractPath, err)
+	} else if _, err := os.Stat(contractFile); err == nil && !withinDir(projectPath, contractFile) {
+		paths = append(paths, contractFile)
+	}
+
+	watcher := r.NewWatcher()
+	events, err := watcher.Watch(paths)
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+	interrupts, stop := r.Interrupts()
+	defer stop()
+
+	var settled <-chan time.Time
+	for {
+		select {
+		case <-interrupts:
+			return nil
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if event.Path == contractFile || isWatchedGoFile(projectPath, event.Path) {
+				settled = time.After(r.WatchDelay)
+			}
+		case <-settled:
+			settled = nil
+			onChange()
+		}
+	}
+}
+
+// isWatchedGoFile reports whether path is a Go file of the project at
+// projectPath, other than a test or one in a vendor or hidden directory
+func isWatchedGoFile(projectPath, path string) bool {
+	if filepath.Ext(path) != ".go" || strings.HasSuffix(path, "_test.go") {
+		return false
+	}
+	rel, err := filepath.Rel(projectPath, path)
+	if err != nil {
+		return false
+	}
+	for _, dir := range strings.Split(filepath.ToSlash(filepath.Dir(rel)), "/") {
+		if dir == "vendor" || dir == ".." || (dir != "." && strings.HasPrefix(dir, ".")) {
+			return false
+		}
+	}
+	return true
+}
+
+// withinDir reports whether path is dir or below it
+func withinDir(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// Run executes the validation
+func (r *DefaultValidateRunner) Run(cmd *cobra.Command, opts service.ValidateOptions, report ValidateReportOptions) error {
+	switch report.Output {
+	case "", validator.ReportFormatText, validator.ReportFormatJSON, validator.ReportFormatYAML, validator.ReportFormatMarkdown, validator.ReportFormatSARIF, validator.ReportFormatJUnit:
+	default:
+		return fmt.Errorf("invalid output format '%s' (supported: text, json, yaml, markdown, sarif, junit)", report.Output)
+	}
+	if report.OutputFile != "" && !isMachineReadable(report.Output) {
+		return fmt.Errorf("--output-file requires --output json, yaml, markdown, sarif or junit")
+	}
+	if opts.Flip && opts.ContractEntrypoint == "" {
+		return fmt.Errorf("--flip requires --contract-from-entrypoint")
+	}
+	if opts.VersionCommand != "" && !opts.ExpectVersion {
+		return fmt.Errorf("--version-command requires --expect-version")
+	}
+	if report.Top < 0 {
+		return fmt.Errorf("--top must be 0 or more, got %d", report.Top)
+	}
+	if opts.FailFast && (opts.AllowExtraCommands || opts.AllowExtraFlags) {
+		// The first error could be an allowed one, hiding the others
+		return fmt.Errorf("--fail-fast cannot be used with --allow-extra-commands or --allow-extra-flags")
+	}
+	if report.SARIFPath != "" && opts.ContractEntrypoint != "" {
+		// SARIF results point at lines of a contract file
+		return fmt.Errorf("--emit-sarif cannot be used with --contract-from-entrypoint")
+	}
+	if report.Output == validator.ReportFormatSARIF && opts.ContractEntrypoint != "" {
+		return fmt.Errorf("--output sarif cannot be used with --contract-from-entrypoint")
+	}
+	if report.BumpLevelPath != "" && !report.SemverCheck {
+		return fmt.Errorf("--output-bump-level requires --semver-check")
+	}
+	if report.AnnotateContract || report.ClearAnnotations {
+		name := "--annotate-contract"
+		if report.ClearAnnotations {
+			name = "--clear-annotations"
+		}
+		switch {
+		case opts.ContractEntrypoint != "":
+			return fmt.Errorf("%s cannot be used with --contract-from-entrypoint", name)
+		case contract.IsURL(opts.ContractPath):
+			return fmt.Errorf("%s cannot update a contract downloaded from a URL", name)
+		}
+	}
+	if report.ClearAnnotations {
+		if opts.ContractPath == "" {
+			opts.ContractPath = filepath.Join(opts.ProjectPath, "cliguard.yaml")
+		}
+		cleared, err := updateContractFile(opts.ContractPath, func(data []byte) ([]byte, error) {
+			return contract.ClearAnnotations(data), nil
+		})
+		if err != nil {
+			return err
+		}
+		if cleared {
+			cmd.Printf("Removed validation annotations from %s\n", opts.ContractPath)
+		} else {
+			cmd.Printf("No validation annotations in %s\n", opts.ContractPath)
+		}
+		return nil
+	}
+	if report.SemverCheck {
+		switch {
+		case opts.FailFast:
+			// The bump depends on every difference
+			return fmt.Errorf("--semver-check cannot be used with --fail-fast")
+		case report.GenerateOnMismatch:
+			return fmt.Errorf("--semver-check cannot be used with --generate-on-mismatch")
+		}
+	}
+	if report.GenerateOnMismatch {
+		switch {
+		case opts.ContractEntrypoint != "":
+			return fmt.Errorf("--generate-on-mismatch cannot be used with --contract-from-entrypoint")
+		case contract.IsURL(opts.ContractPath):
+			return fmt.Errorf("--generate-on-mismatch cannot update a contract downloaded from a URL")
+		case opts.FailFast:
+			// Only a full report tells whether every error is a new command or flag
+			return fmt.Errorf("--generate-on-mismatch cannot be used with --fail-fast")
+		case opts.CLISnapshot != "":
+			// Regenerating the contract needs the project built
+			return fmt.Errorf("--generate-on-mismatch cannot be used with --cli-snapshot")
+		case opts.Static:
+			// A contract generated statically would lose its flag defaults,
+			// completions and groups
+			return fmt.Errorf("--generate-on-mismatch cannot be used with --static")
+		case report.MaxAutoUpdates < 1:
+			return fmt.Errorf("--max-auto-updates must be 1 or more, got %d", report.MaxAutoUpdates)
+		}
+	}
+
+	// Check the GitHub environment before spending time on inspection
+	var commenter PRCommenter
+	if report.GitHubComment {
+		var err error
+		if commenter, err = r.NewPRCommenter(); err != nil {
+			return err
+		}
+	}
+
+	// Check if entrypoint is provided and detect framework
+	if opts.Entrypoint != "" {
+		framework, err := discovery.DetectEntrypointFramework(opts.ProjectPath, opts.Entrypoint, nil)
+		if err == nil && framework != "" && framework != "cobra" {
+			if !report.Force {
+				return fmt.Errorf("Error: cliguard currently only supports Cobra CLIs. Support for %s is coming soon!\nUse --force to proceed anyway (may produce unexpected results)", framework)
+			}
+			cmd.Printf("⚠️  Warning: Proceeding with unsupported framework %s. Results may be unreliable.\n\n", framework)
+		}
+	}
+
+	// Print progress messages
+	if opts.ContractEntrypoint != "" {
+		expected := opts.ContractEntrypoint
+		if opts.Flip {
+			expected = opts.Entrypoint
+		}
+		cmd.Printf("Generating contract from entrypoint: %s\n", expected)
+	} else {
+		shown := opts.ContractPath
+		if shown == "" {
+			shown = "cliguard.yaml in project path"
+		}
+		cmd.Printf("Loading contract from: %s\n", shown)
+	}
+	if opts.CLISnapshot != "" {
+		cmd.Printf("Loading CLI snapshot from: %s\n", opts.CLISnapshot)
+	} else if opts.Static {
+		cmd.Printf("Reading CLI structure from the source in: %s\n", opts.ProjectPath)
+	} else {
+		cmd.Printf("Inspecting CLI structure in: %s\n", opts.ProjectPath)
+	}
+	cmd.Println("Validating CLI structure against contract...")
+
+	// Run validation. Nothing is built for a snapshot or static inspection.
+	r.service.InspectorTimeout = report.InspectorTimeout
+	progress := buildProgress(cmd, r.NewProgress, isMachineReadable(report.Output) || opts.CLISnapshot != "" || opts.Static)
+	progress.Start()
+	result, err := r.service.Validate(opts)
+	progress.Stop(err)
+	if err != nil {
+		return err
+	}
+
+	// With --generate-on-mismatch, a contract that only lacks new commands
+	// or flags is regenerated from the CLI and validated again
+	for updates := 0; report.GenerateOnMismatch && updates < report.MaxAutoUpdates; updates++ {
+		failing := opts.FailingErrors(result.Result.Errors)
+		if len(failing) == 0 || !allOfType(failing, validator.ErrorTypeUnexpected) {
+			break
+		}
+		if result.RootName != "" {
+			return fmt.Errorf("--generate-on-mismatch cannot update v2 contracts")
+		}
+		if _, err := r.GenerateContract(service.GenerateOptions{
+			ProjectPath:            opts.ProjectPath,
+			Entrypoint:             opts.Entrypoint,
+			Timeout:                opts.Timeout,
+			ExpandPersistentFlags:  opts.ExpandedContract,
+			UseNameOnly:            opts.UseNameOnly,
+			StripHelpCommand:       true,
+			StripCompletionCommand: true,
+		}, result.ContractPath); err != nil {
+			return fmt.Errorf("failed to update contract: %w", err)
+		}
+		cmd.Printf("📝 Updated %s with %d new commands or flags; validating again...\n", result.ContractPath, len(failing))
+
+		progress.Start()
+		result, err = r.service.Validate(opts)
+		progress.Stop(err)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(result.UnknownFields) > 0 {
+		unrecognized := false
+		for _, field := range result.UnknownFields {
+			cmd.Printf("⚠️  %s (line %d)\n", field, field.Line)
+			unrecognized = unrecognized || field.ReplacedBy == ""
+		}
+		if unrecognized {
+			cmd.Println("Unrecognized fields are ignored; use --strict-contract to make them an error.")
+		}
+	}
+
+	for _, warning := range result.Result.Warnings {
+		found := warning.Actual
+		if found == "" {
+			found = "none"
+		}
+		cmd.Printf("⚠️  %s: %s (contract: %s, found: %s)\n", warning.Path, warning.Message, warning.Expected, found)
+	}
+
+	if report.SARIFPath != "" {
+		if err := writeSARIF(report.SARIFPath, result); err != nil {
+			return err
+		}
+		cmd.Printf("SARIF report written to %s\n", report.SARIFPath)
+	}
+
+	if report.AnnotateContract {
+		// Annotating a valid contract removes the annotations of earlier runs
+		annotated, err := annotateContractFile(result)
+		if err != nil {
+			return err
+		}
+		if annotated {
+			cmd.Printf("📝 Annotated %s with %d validation errors\n", result.ContractPath, len(result.Result.Errors))
+		}
+	}
+
+	if commenter != nil {
+		if err := commenter.PostPRComment(result.Result.FormatMarkdown()); err != nil {
+			return err
+		}
+		cmd.Println("Posted the validation report to the pull request.")
+	}
+
+	// Allowed differences are still reported, but don't fail validation.
+	// With --semver-check the differences only decide the bump.
+	failed := len(opts.FailingErrors(result.Result.Errors)) > 0 && !report.SemverCheck
+	printBump := func() error {
+		if !report.SemverCheck {
+			return nil
+		}
+		changes := semver.CountChanges(result.Result)
+		cmd.Printf("Suggested version bump: %s (%s)\n", changes.Bump(), changes)
+		if report.BumpLevelPath != "" {
+			if err := os.WriteFile(report.BumpLevelPath, []byte(string(changes.Bump())+"\n"), 0644); err != nil {
+				return fmt.Errorf("failed to write bump level: %w", err)
+			}
+		}
+		return nil
+	}
+
+	// With --summarize or --top, reports list only some of the errors
+	shown := result.Result
+	summaryTop := report.Top
+	if report.Summarize {
+		if summaryTop == 0 {
+			summaryTop = validator.DefaultSummaryTop
+		}
+		shown = result.Result.Critical(summaryTop)
+	} else if report.Top > 0 {
+		shown = result.Result.Top(report.Top)
+	}
+	printReport := func() {
+		if report.Summarize {
+			fmt.Fprint(cmd.OutOrStdout(), result.Result.Summarize(summaryTop))
+			return
+		}
+		shown.PrintReport()
+	}
+
+	// Machine-readable reports go to stdout, or --output-file; progress
+	// messages stay on stderr
+	if isMachineReadable(report.Output) {
+		data, err := formatMachineReadable(result, shown, report.Output)
+		if err != nil {
+			return err
+		}
+		if report.OutputFile != "" {
+			if err := os.WriteFile(report.OutputFile, data, 0644); err != nil {
+				return fmt.Errorf("failed to write report to '%s': %w", report.OutputFile, err)
+			}
+			cmd.Printf("Report written to %s\n", report.OutputFile)
+		} else {
+			cmd.OutOrStdout().Write(data)
+		}
+		if err := printBump(); err != nil {
+			return err
+		}
+		if failed && !opts.WarnOnly {
+			printStopped(cmd, result)
+			return validationFailure(cmd, result.Result.Errors, report.GenerateOnMismatch)
+		}
+		return nil
+	}
+
+	// With --warn-only the errors are reported, but validation passes
+	if opts.WarnOnly && failed {
+		cmd.Println("⚠️  Validation found differences; --warn-only reports them as warnings.")
+		cmd.Println()
+		printReport()
+		printStopped(cmd, result)
+		if count := len(result.Result.Errors); count == 1 {
+			cmd.Println("1 warning found")
+		} else {
+			cmd.Printf("%d warnings found\n", count)
+		}
+		return nil
+	}
+
+	// Report results
+	if result.Result.IsValid() {
+		cmd.Println("✅ Validation passed! CLI structure matches the contract.")
+		return printBump()
+	}
+	if report.SemverCheck {
+		cmd.Println("CLI structure differs from the contract:")
+		cmd.Println()
+		printReport()
+		return printBump()
+	}
+	if !failed {
+		cmd.Println("✅ Validation passed with extra commands or flags allowed by --allow-extra-commands or --allow-extra-flags.")
+		cmd.Println()
+		printReport()
+		return nil
+	}
+
+	// Print validation errors
+	cmd.Println("❌ Validation failed!")
+	cmd.Println()
+	printReport()
+	printStopped(cmd, result)
+
+	return validationFailure(cmd, result.Result.Errors, report.GenerateOnMismatch)
+}
+
+// validationFailure returns the error for a failed validation. With
+// --generate-on-mismatch it first explains why the contract was not
+// updated, and contract commands or flags missing from the CLI are
+// ErrBreakingChanges, since they break the invocations that use them.
+func validationFailure(cmd *cobra.Command, errs []validator.ValidationError, generateOnMismatch bool) error {
+	if !generateOnMismatch {
+		return cliguarderrors.ErrValidationFailed
+	}
+	for _, err := range errs {
+		if err.Type == validator.ErrorTypeMissing {
+			cmd.Println("The contract was not updated: the CLI no longer has commands or flags it lists, which is a breaking change. Update the contract with generate if the removal is intended.")
+			return cliguarderrors.ErrBreakingChanges
+		}
+	}
+	cmd.Println("The contract was not updated: it differs from the CLI by more than new commands and flags.")
+	return cliguarderrors.ErrValidationFailed
+}
+
+// allOfType reports whether all the errors are of the given type
+func allOfType(errs []validator.ValidationError, errorType validator.ErrorType) bool {
+	for _, err := range errs {
+		if err.Type != errorType {
+			return false
+		}
+	}
+	return true
+}
+
+// printStopped notes that the report is incomplete if validation stopped at
+// its first error
+func printStopped(cmd *cobra.Command, result *service.ValidateResult) {
+	if result.Result.Stopped {
+		cmd.Println("Validation stopped after first error (use --no-fail-fast for full report)")
+	}
+}
+
+// isMachineReadable reports whether a validate report format is meant for
+// other programs
+func isMachineReadable(format string) bool {
+	switch format {
+	case validator.ReportFormatJSON, validator.ReportFormatYAML, validator.ReportFormatMarkdown, validator.ReportFormatSARIF, validator.ReportFormatJUnit:
+		return true
+	}
+	return false
+}
+
+// formatMachineReadable renders the errors of shown, all or some of those of
+// result, in a machine-readable format. JUnit test suites are named after
+// the contract's root command.
+func formatMachineReadable(result *service.ValidateResult, shown *validator.ValidationResult, format string) ([]byte, error) {
+	switch format {
+	case validator.ReportFormatSARIF:
+		return formatSARIF(result, shown)
+	case validator.ReportFormatJUnit:
+		return output.FormatJUnit(shown, result.Use)
+	}
+	report, err := shown.FormatReport(format)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(report), nil
+}
+
+// buildProgress returns the indicator to show on cmd's error output while
+// the target project is built and inspected. Nothing is shown when quiet is
+// set or with --debug, whose log lines would break up the spinner.
+func buildProgress(cmd *cobra.Command, newProgress func(io.Writer) output.Progress, quiet bool) output.Progress {
+	if quiet || debug || newProgress == nil {
+		return output.NopProgress{}
+	}
+	return newProgress(cmd.ErrOrStderr())
+}
+
+// writeSARIF writes the validation result to path as SARIF (see formatSARIF)
+func writeSARIF(path string, result *service.ValidateResult) error {
+	data, err := formatSARIF(result, result.Result)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write SARIF report to '%s': %w", path, err)
+	}
+	return nil
+}
+
+// formatSARIF renders the errors of shown, all or some of those of result,
+// as SARIF, locating each at its line in the contract. The contract is
+// referenced relative to the current directory, which is the repository
+// root in CI.
+func formatSARIF(result *service.ValidateResult, shown *validator.ValidationResult) ([]byte, error) {
+	lines, err := contract.LoadLineIndex(result.ContractPath, result.RootName)
+	if err != nil {
+		return nil, err
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current directory: %w", err)
+	}
+	return output.FormatSARIF(shown, output.ArtifactURI(result.ContractPath, cwd), lines)
+}
+
+// annotateContractFile writes the validation errors of result as comments
+// into the contract that was validated. It reports whether the file changed.
+func annotateContractFile(result *service.ValidateResult) (bool, error) {
+	return updateContractFile(result.ContractPath, func(data []byte) ([]byte, error) {
+		return output.AnnotateContract(data, result.RootName, result.Result)
+	})
+}
+
+// updateContractFile rewrites the contract at path with what update returns
+// for its content, keeping its permissions. It reports whether the content
+// changed; an unchanged file is not rewritten.
+func updateContractFile(path string, update func([]byte) ([]byte, error)) (bool, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false, fmt.Errorf("failed to read contract: %w", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false, fmt.Errorf("failed to read contract: %w", err)
+	}
+	updated, err := update(data)
+	if err != nil {
+		return false, fmt.Errorf("failed to annotate contract: %w", err)
+	}
+	if bytes.Equal(updated, data) {
+		return false, nil
+	}
+	if err := os.WriteFile(path, updated, info.Mode().Perm()); err != nil {
+		return false, fmt.Errorf("failed to write contract: %w", err)
+	}
+	return true, nil
+}
+
+// Global runner for testing
+var validateRunner ValidateRunner = NewDefaultValidateRunner()
+
+func runValidate(cmd *cobra.Command, args []string) error {
+	// Default to current directory if no project path specified
+	path := projectPath
+	if path == "" {
+		var err error
+		path, err = os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get current directory: %w", err)
+		}
+	}
+
+	if skipBuild && cliSnapshot == "" {
+		return fmt.Errorf("--skip-build requires --cli-snapshot")
+	}
+
+	// Contract URLs are downloaded by the contract package's loaders
+	header := http.Header{}
+	for _, h := range contractHTTPHeaders {
+		name, value, err := contract.ParseHTTPHeader(h)
+		if err != nil {
+			return err
+		}
+		header.Add(name, value)
+	}
+	contract.DefaultFetcher.Header = header
+	contract.DefaultFetcher.NoCache = noContractCache
+
+	opts := service.ValidateOptions{
+		ProjectPath:         path,
+		ContractPath:        contractPath,
+		Entrypoint:          entrypoint,
+		Timeout:             timeout,
+		StrictSortOrder:     strictSortOrder,
+		ExpandedContract:    expandedContract,
+		ContractEntrypoint:  contractEntrypoint,
+		Flip:                flipContract,
+		StrictContract:      strictContract,
+		UseNameOnly:         cobraUseNameOnly || !strictUse,
+		ExpectVersion:       expectVersion,
+		VersionCommand:      versionCommand,
+		IgnoreCommandsRegex: ignoreCommandsRegex,
+		AllowExtraCommands:  allowExtraCommands,
+		AllowExtraFlags:     allowExtraFlags,
+		FailFast:            failFast && !noFailFast,
+		CLISnapshot:         cliSnapshot,
+		IgnoreShort:         ignoreShort,
+		IgnoreLong:          ignoreLong,
+		Static:              static,
+		StrictMode:          strictMode,
+		WarnOnly:            warnOnly,
+		FocusPaths:          focusPaths,
+	}
+	report := ValidateReportOptions{
+		Output:             validateOutput,
+		GitHubComment:      githubComment,
+		SARIFPath:          sarifPath,
+		Summarize:          summarize,
+		Top:                top,
+		GenerateOnMismatch: generateOnMismatch,
+		MaxAutoUpdates:     maxAutoUpdates,
+		SemverCheck:        semverCheck,
+		BumpLevelPath:      outputBumpLevel,
+		AnnotateContract:   annotateContract,
+		ClearAnnotations:   clearAnnotations,
+		OutputFile:         validateOutputFile,
+		Force:              force,
+		InspectorTimeout:   inspectorTimeout,
+	}
+	validate := func() error {
+		return validateRunner.Run(cmd, opts, report)
+	}
+	var err error
+	if validateWatch {
+		err = watchValidate(cmd, path, validate)
+	} else {
+		err = validate()
+	}
+	// Before exitOnFailure, which can exit without running deferred calls
+	contract.DefaultFetcher.Cleanup()
+	return exitOnFailure(err)
+}
+
+// watchValidate validates, then validates again after each change to the
+// project or the contract until interrupted. Failed validations are reported
+// and watching goes on.
+func watchValidate(cmd *cobra.Command, path string, validate func() error) error {
+	switch {
+	case cliSnapshot != "":
+		return fmt.Errorf("--watch cannot be used with --cli-snapshot, which doesn't change")
+	case contractEntrypoint != "":
+		return fmt.Errorf("--watch cannot be used with --contract-from-entrypoint")
+	case githubComment, generateOnMismatch, annotateContract, clearAnnotations:
+		return fmt.Errorf("--watch cannot be used with --github-comment, --generate-on-mismatch, --annotate-contract or --clear-annotations")
+	}
+
+	run := func() {
+		cmd.Printf("\n[%s] Validating %s\n", time.Now().Format("15:04:05"), path)
+		err := validate()
+		if err != nil && !errors.Is(err, cliguarderrors.ErrValidationFailed) && !errors.Is(err, cliguarderrors.ErrBreakingChanges) {
+			cmd.PrintErrf("Error: %v\n", err)
+		}
+	}
+	run()
+
+	cmd.Printf("\n👀 Watching %s for changes (press Ctrl+C to stop)\n", path)
+	if err := validateRunner.Watch(service.ValidateOptions{ProjectPath: path, ContractPath: contractPath}, run); err != nil {
+		return err
+	}
+	cmd.Println("\nStopped watching.")
+	return nil
+}
+
+// ValidateAllRunner interface for dependency injection
+type ValidateAllRunner interface {
+	Run(cmd *cobra.Command, configPath string, timeout time.Duration) error
+}
+
+// DefaultValidateAllRunner is the default implementation
+type DefaultValidateAllRunner struct {
+	service *service.ValidateService
+}
+
+// NewDefaultValidateAllRunner creates a new default runner
+func NewDefaultValidateAllRunner() *DefaultValidateAllRunner {
+	return &DefaultValidateAllRunner{
+		service: service.NewValidateService(),
+	}
+}
+
+// Run validates all projects in the batch config and prints a summary
+func (r *DefaultValidateAllRunner) Run(cmd *cobra.Command, configPath string, timeout time.Duration) error {
+	config, err := service.LoadBatchConfig(configPath)
+	if err != nil {
+		return err
+	}
+
+	opts := make([]service.ValidateOptions, len(config.Projects))
+	for i, project := range config.Projects {
+		opts[i] = service.ValidateOptions{
+			ProjectPath:  project.ProjectPath,
+			ContractPath: project.Contract,
+			Entrypoint:   project.Entrypoint,
+			Timeout:      timeout,
+		}
+	}
+
+	cmd.Printf("Validating %d project(s) from: %s\n\n", len(opts), configPath)
+
+	// Per-project errors are reported in the summary below
+	results, _ := r.service.ValidateMultiple(opts)
+
+	failed := 0
+	for i, result := range results {
+		name := config.Projects[i].DisplayName()
+		switch {
+		case result.Error != nil:
+			failed++
+			cmd.Printf("❌ %s (error)\n", name)
+		case !result.Success:
+			failed++
+			cmd.Printf("❌ %s (%d errors)\n", name, len(result.Result.Errors))
+		default:
+			cmd.Printf("✅ %s\n", name)
+		}
+	}
+
+	if failed == 0 {
+		cmd.Printf("\n✅ All %d project(s) passed validation.\n", len(results))
+		return nil
+	}
+
+	// Print details for each failed project
+	for i, result := range results {
+		if result.Success {
+			continue
+		}
+		cmd.Printf("\n--- %s ---\n", config.Projects[i].DisplayName())
+		if result.Error != nil {
+			cmd.Println(result.Error)
+			continue
+		}
+		result.Result.PrintReport()
+	}
+
+	cmd.Printf("\n❌ %d of %d project(s) failed validation.\n", failed, len(results))
+	return cliguarderrors.ErrValidationFailed
+}
+
+// Global runner for testing
+var validateAllRunner ValidateAllRunner = NewDefaultValidateAllRunner()
+
+func runValidateAll(cmd *cobra.Command, args []string) error {
+	err := validateAllRunner.Run(cmd, batchConfigPath, timeout)
+	return exitOnFailure(err)
+}
+
+// GenerateRunner interface for dependency injection
+type GenerateRunner interface {
+	Run(cmd *cobra.Command, opts service.GenerateOptions, force bool, outputFile string, inspectorTimeout time.Duration) error
+}
+
+// DefaultGenerateRunner is the default implementation
+type DefaultGenerateRunner struct {
+	service *service.GenerateService
+
+	// NewProgress creates the indicator shown while the project builds
+	NewProgress func(w io.Writer) output.Progress
+}
+
+// NewDefaultGenerateRunner creates a new default runner
+func NewDefaultGenerateRunner() *DefaultGenerateRunner {
+	return &DefaultGenerateRunner{
+		service:     service.NewGenerateService(),
+		NewProgress: output.NewProgress,
+	}
+}
+
+// Run executes the generation
+func (r *DefaultGenerateRunner) Run(cmd *cobra.Command, opts service.GenerateOptions, force bool, outputFile string, inspectorTimeout time.Duration) error {
+	if opts.FromBinary != "" {
+		cmd.Printf("⚠️  Warning: Generating from %s --help output. Help output omits the root short description when a long one is set, hidden commands and flag completions; review the contract before using it.\n\n", opts.FromBinary)
+	} else if opts.FromOpenAPI != "" {
+		cmd.Printf("⚠️  Warning: Generating from the OpenAPI spec %s. Generators name commands and flags differently; review the contract and validate it against the real CLI project.\n\n", opts.FromOpenAPI)
+	} else if opts.Static {
+		cmd.Printf("⚠️  Warning: Generating statically from the project source. Flag defaults, completions and groups are not recorded, and commands and flags built from computed values are missed; review the contract before using it.\n\n")
+	} else if opts.Entrypoint != "" {
+		// Detect the framework used by the entrypoint
+		framework, err := discovery.DetectEntrypointFramework(opts.ProjectPath, opts.Entrypoint, nil)
+		if err == nil && framework != "" && framework != "cobra" {
+			if !force {
+				return fmt.Errorf("Error: cliguard currently only supports Cobra CLIs. Support for %s is coming soon!\nUse --force to proceed anyway (may produce unexpected results)", framework)
+			}
+			cmd.Printf("⚠️  Warning: Proceeding with unsupported framework %s. Results may be unreliable.\n\n", framework)
+		}
+	}
+
+	r.service.InspectorTimeout = inspectorTimeout
+	if dryRunExecutor, ok := opts.Executor.(*executor.DryRunExecutor); ok {
+		// Grouping runs no commands, and needs a file to write to
+		opts.GroupBySubpackage = false
+		if _, err := r.service.Generate(opts); err != nil {
+			return err
+		}
+		for _, record := range dryRunExecutor.Records() {
+			fmt.Fprintf(cmd.OutOrStdout(), "Would run: %s\n", record)
+		}
+		return nil
+	}
+
+	// Only inspecting project source, without --static, builds anything
+	progress := buildProgress(cmd, r.NewProgress, opts.FromBinary != "" || opts.FromOpenAPI != "" || opts.Static)
+
+	if outputFile != "" {
+		progress.Start()
+		written, err := r.service.GenerateToFile(opts, outputFile)
+		progress.Stop(err)
+		if err != nil {
+			return err
+		}
+		if !written {
+			cmd.Printf("Contract unchanged: %s\n", outputFile)
+			return nil
+		}
+		cmd.Printf("✅ Contract written to %s\n", outputFile)
+		return nil
+	}
+
+	// Run generation
+	progress.Start()
+	yamlContent, err := r.service.Generate(opts)
+	progress.Stop(err)
+	if err != nil {
+		return err
+	}
+
+	// Print YAML to stdout
+	fmt.Fprint(cmd.OutOrStdout(), yamlContent)
+	return nil
+}
+
+// Global runner for testing
+var generateRunner GenerateRunner = NewDefaultGenerateRunner()
+
+func runGenerate(cmd *cobra.Command, args []string) error {
+	// Default to current directory if no project path specified
+	path := projectPath
+	if path == "" {
+		var err error
+		path, err = os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get current directory: %w", err)
+		}
+	}
+	opts := service.GenerateOptions{
+		ProjectPath:            path,
+		Entrypoint:             entrypoint,
+		Timeout:                timeout,
+		IncludeHiddenCommands:  includeHiddenCommands,
+		IncludeHiddenFlags:     includeHidden,
+		OmitDeprecatedFlags:    !includeDeprecated,
+		ExpandPersistentFlags:  includePersistentFlags,
+		WithExamples:           withExamples,
+		ExtractGodoc:           extractGodoc,
+		WriteSnapshot:          writeSnapshot,
+		WithValidation:         withValidation,
+		CobraVersion:           cobraVersion,
+		ContractVersion:        outputContractVersion,
+		OutputEncoding:         outputEncoding,
+		OutputFormat:           generateOutput,
+		FromBinary:             fromBinary,
+		FromOpenAPI:            fromOpenAPI,
+		Static:                 static,
+		ToolName:               toolName,
+		StripDefaults:          stripDefaults,
+		StripRules:             stripRules,
+		StripHelpCommand:       stripHelpCommand,
+		StripCompletionCommand: stripCompletionCommand,
+		OmitUnchanged:          omitUnchanged,
+		UseNameOnly:            normalizeUse || cobraUseNameOnly,
+		HeaderComment:          headerComment,
+		NoHeader:               noHeader,
+		GroupBySubpackage:      groupBySubpackage,
+		RunnableOnly:           runnableOnly,
+	}
+	if omitUnchanged && outputFile == "" {
+		return fmt.Errorf("--omit-unchanged requires --output-file")
+	}
+	if groupBySubpackage && outputFile == "" {
+		return fmt.Errorf("--group-by-subpackage requires --output-file")
+	}
+	if dryRun {
+		// The inspector parses the output of its last command as JSON
+		opts.Executor = executor.NewDryRunExecutor([]byte("{}"))
+	}
+	return generateRunner.Run(cmd, opts, force, outputFile, inspectorTimeout)
+}
+
+// ShowRunner interface for dependency injection
+type ShowRunner interface {
+	Run(cmd *cobra.Command, opts service.ShowOptions) error
+}
+
+// DefaultShowRunner is the default implementation
+type DefaultShowRunner struct {
+	service *service.ShowService
+}
+
+// NewDefaultShowRunner creates a new default runner
+func NewDefaultShowRunner() *DefaultShowRunner {
+	return &DefaultShowRunner{
+		service: service.NewShowService(),
+	}
+}
+
+// Run inspects the CLI and prints its structure
+func (r *DefaultShowRunner) Run(cmd *cobra.Command, opts service.ShowOptions) error {
+	output, err := r.service.Show(opts)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprint(cmd.OutOrStdout(), output)
+	return nil
+}
+
+// Global runner for testing
+var showRunner ShowRunner = NewDefaultShowRunner()
+
+func runShow(cmd *cobra.Command, args []string) error {
+	// Default to current directory if no project path specified
+	path := projectPath
+	if path == "" {
+		var err error
+		path, err = os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get current directory: %w", err)
+		}
+	}
+	opts := service.ShowOptions{
+		ProjectPath: path,
+		Entrypoint:  entrypoint,
+		Timeout:     timeout,
+		Format:      showFormat,
+	}
+	return showRunner.Run(cmd, opts)
+}
+
+// InspectRunner interface for dependency injection
+type InspectRunner interface {
+	Run(cmd *cobra.Command, opts service.InspectOptions) error
+}
+
+// DefaultInspectRunner is the default implementation
+type DefaultInspectRunner struct {
+	service *service.InspectService
+}
+
+// NewDefaultInspectRunner creates a new default runner
+func NewDefaultInspectRunner() *DefaultInspectRunner {
+	return &DefaultInspectRunner{
+		service: service.NewInspectService(),
+	}
+}
+
+// Run inspects the CLI and prints the result as JSON
+func (r *DefaultInspectRunner) Run(cmd *cobra.Command, opts service.InspectOptions) error {
+	output, err := r.service.Inspect(opts)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprint(cmd.OutOrStdout(), output)
+	return nil
+}
+
+// Global runner for testing
+var inspectRunner InspectRunner = NewDefaultInspectRunner()
+
+func runInspect(cmd *cobra.Command, args []string) error {
+	// Default to current directory if no project path specified
+	path := projectPath
+	if path == "" {
+		var err error
+		path, err = os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get current directory: %w", err)
+		}
+	}
+	opts := service.InspectOptions{
+		ProjectPath: path,
+		Entrypoint:  entrypoint,
+		Timeout:     timeout,
+		Compact:     inspectCompact || !inspectPretty,
+		Filter:      jqFilter,
+	}
+	return inspectRunner.Run(cmd, opts)
+}
+
+// CompareRunner interface for dependency injection
+type CompareRunner interface {
+	Run(cmd *cobra.Command, opts service.CompareOptions) error
+}
+
+// DefaultCompareRunner is the default implementation
+type DefaultCompareRunner struct {
+	service *service.CompareService
+}
+
+// NewDefaultCompareRunner creates a new default runner
+func NewDefaultCompareRunner() *DefaultCompareRunner {
+	return &DefaultCompareRunner{
+		service: service.NewCompareService(),
+	}
+}
+
+// Run compares the two CLIs and prints their differences
+func (r *DefaultCompareRunner) Run(cmd *cobra.Command, opts service.CompareOptions) error {
+	cmd.Printf("Comparing %s (%s) with %s (%s)\n\n", opts.OldProjectPath, opts.OldEntrypoint, opts.NewProjectPath, opts.NewEntrypoint)
+
+	result, err := r.service.Compare(opts)
+	if err != nil {
+		return err
+	}
+
+	result.WriteDiff(cmd.OutOrStdout())
+
+	switch {
+	case result.HasBreaking():
+		return cliguarderrors.ErrBreakingChanges
+	case !result.Identical():
+		return cliguarderrors.ErrValidationFailed
+	}
+	return nil
+}
+
+// Global runner for testing
+var compareRunner CompareRunner = NewDefaultCompareRunner()
+
+func runCompare(cmd *cobra.Command, args []string) error {
+	opts := service.CompareOptions{
+		OldProjectPath: oldProjectPath,
+		OldEntrypoint:  oldEntrypoint,
+		NewProjectPath: newProjectPath,
+		NewEntrypoint:  newEntrypoint,
+		Timeout:        timeout,
+	}
+	err := compareRunner.Run(cmd, opts)
+	return exitOnFailure(err)
+}
+
+// DiffRunner interface for dependency injection
+type DiffRunner interface {
+	Run(cmd *cobra.Command, opts service.DiffOptions, exitCode bool) error
+}
+
+// DefaultDiffRunner is the default implementation
+type DefaultDiffRunner struct {
+	service *service.DiffService
+}
+
+// NewDefaultDiffRunner creates a new default runner
+func NewDefaultDiffRunner() *DefaultDiffRunner {
+	return &DefaultDiffRunner{
+		service: service.NewDiffService(),
+	}
+}
+
+// Run compares the two contracts and prints their differences. With
+// exitCode, differences are reported as a failure.
+func (r *DefaultDiffRunner) Run(cmd *cobra.Command, opts service.DiffOptions, exitCode bool) error {
+	result, err := r.service.Diff(opts)
+	if err != nil {
+		return err
+	}
+
+	result.WriteDiff(cmd.OutOrStdout())
+
+	if exitCode && !result.Identical() {
+		return cliguarderrors.ErrValidationFailed
+	}
+	return nil
+}
+
+// Global runner for testing
+var diffRunner DiffRunner = NewDefaultDiffRunner()
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	opts := service.DiffOptions{
+		From: diffFrom,
+		To:   diffTo,
+	}
+	err := diffRunner.Run(cmd, opts, diffExitCode)
+	return exitOnFailure(err)
+}
+
+// UpdateRunner interface for dependency injection
+type UpdateRunner interface {
+	Run(cmd *cobra.Command, opts service.UpdateOptions, yes bool) error
+}
+
+// DefaultUpdateRunner is the default implementation
+type DefaultUpdateRunner struct {
+	service *service.UpdateService
+}
+
+// NewDefaultUpdateRunner creates a new default runner
+func NewDefaultUpdateRunner() *DefaultUpdateRunner {
+	return &DefaultUpdateRunner{
+		service: service.NewUpdateService(),
+	}
+}
+
+// Run prints the changes the CLI makes to the contract and, once confirmed
+// on the command's input or with yes, writes them
+func (r *DefaultUpdateRunner) Run(cmd *cobra.Command, opts service.UpdateOptions, yes bool) error {
+	cmd.Printf("Inspecting CLI structure in: %s\n", opts.ProjectPath)
+	result, err := r.service.Update(opts)
+	if err != nil {
+		return err
+	}
+
+	if !result.Changed() {
+		cmd.Printf("✅ %s is up to date with the CLI.\n", result.ContractPath)
+		return nil
+	}
+	result.Diff.WriteDiff(cmd.OutOrStdout())
+
+	if !yes {
+		cmd.Printf("\nWrite these changes to %s? [y/N] ", result.ContractPath)
+		answer, _ := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+		if answer := strings.ToLower(strings.TrimSpace(answer)); answer != "y" && answer != "yes" {
+			cmd.Println("Contract not updated.")
+			return nil
+		}
+	}
+
+	if err := r.service.Write(result); err != nil {
+		return err
+	}
+	cmd.Printf("✅ Contract written to %s\n", result.ContractPath)
+	return nil
+}
+
+// Global runner for testing
+var updateRunner UpdateRunner = NewDefaultUpdateRunner()
+
+func runUpdate(cmd *cobra.Command, args []string) error {
+	// Default to current directory if no project path specified
+	path := projectPath
+	if path == "" {
+		var err error
+		path, err = os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get current directory: %w", err)
+		}
+	}
+
+	opts := service.UpdateOptions{
+		ProjectPath:  path,
+		ContractPath: contractPath,
+		Entrypoint:   entrypoint,
+		Timeout:      timeout,
+	}
+	err := updateRunner.Run(cmd, opts, updateYes)
+	return exitOnFailure(err)
+}
+
+// InitRunner interface for dependency injection
+type InitRunner interface {
+	Run(cmd *cobra.Command, opts service.GenerateOptions, yes bool) error
+}
+
+// DefaultInitRunner is the default implementation
+type DefaultInitRunner struct {
+	// Discover finds the entrypoint candidates of a project. Defaults to
+	// Discoverer.DiscoverEntrypoints.
+	Discover func(projectPath string) (*discovery.DiscovererResult, error)
+
+	// Generate generates the contract. Defaults to GenerateService.Generate.
+	Generate func(opts service.GenerateOptions) (string, error)
+}
+
+// NewDefaultInitRunner creates a new default runner
+func NewDefaultInitRunner() *DefaultInitRunner {
+	return &DefaultInitRunner{
+		Discover: func(projectPath string) (*discovery.DiscovererResult, error) {
+			return discovery.NewDiscoverer(projectPath, nil).DiscoverEntrypoints()
+		},
+		Generate: service.NewGenerateService().Generate,
+	}
+}
+
+// Run selects the entrypoint unless opts has one, previews the contract and
+// writes it once confirmed, then offers to add validate to the project's CI.
+// The answers are read from the command's input; with yes, every step is
+// taken without asking.
+func (r *DefaultInitRunner) Run(cmd *cobra.Command, opts service.GenerateOptions, yes bool) error {
+	// One reader for every prompt, so that none reads ahead of the others
+	in := bufio.NewReader(cmd.InOrStdin())
+	confirm := func(question string) bool {
+		if yes {
+			return true
+		}
+		cmd.Printf("%s [y/N] ", question)
+		answer, _ := in.ReadString('\n')
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		return answer == "y" || answer == "yes"
+	}
+
+	if opts.Entrypoint == "" {
+		candidate, err := r.selectEntrypoint(cmd, opts.ProjectPath, in, yes)
+		if err != nil {
+			return err
+		}
+		opts.Entrypoint = candidate.Entrypoint()
+		cmd.Printf("Using entrypoint %s\n", opts.Entrypoint)
+	}
+
+	cmd.Printf("\nGenerating the contract of %s...\n\n", opts.Entrypoint)
+	content, err := r.Generate(opts)
+	if err != nil {
+		return err
+	}
+	fmt.Fprint(cmd.OutOrStdout(), content)
+
+	contractFile := filepath.Join(opts.ProjectPath, "cliguard.yaml")
+	question := fmt.Sprintf("\nWrite this contract to %s?", contractFile)
+	if _, err := os.Stat(contractFile); err == nil {
+		question = fmt.Sprintf("\n%s already exists. Replace it with this contract?", contractFile)
+	}
+	if !confirm(question) {
+		cmd.Println("Contract not written.")
+		return nil
+	}
+	if err := os.WriteFile(contractFile, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write contract to '%s': %w", contractFile, err)
+	}
+	cmd.Printf("✅ Contract written to %s\n", contractFile)
+
+	for _, step := range service.DetectCISteps(opts.ProjectPath, opts.Entrypoint) {
+		if !confirm(fmt.Sprintf("Add %s?", step.Description)) {
+			continue
+		}
+		if err := service.AddCIStep(step); err != nil {
+			return err
+		}
+		cmd.Printf("✅ Added %s\n", step.Description)
+	}
+
+	cmd.Printf("\nRun 'cliguard validate --entrypoint %s' to check the CLI against its contract.\n", opts.Entrypoint)
+	return nil
+}
+
+// selectEntrypoint discovers the Cobra entrypoints of the project and asks
+// which one to use, or with yes, takes the most likely one
+func (r *DefaultInitRunner) selectEntrypoint(cmd *cobra.Command, projectPath string, in io.Reader, yes bool) (*discovery.EntrypointCandidate, error) {
+	cmd.Printf("🔍 Looking for CLI entrypoints in %s...\n", projectPath)
+	result, err := r.Discover(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover entrypoints: %w", err)
+	}
+
+	// Generate only supports Cobra. Several patterns can match one function,
+	// so each entrypoint is offered once, as its most likely candidate.
+	var candidates []discovery.EntrypointCandidate
+	seen := make(map[string]bool)
+	for _, candidate := range result.Candidates {
+		if candidate.Framework == "cobra" && !seen[candidate.Entrypoint()] {
+			seen[candidate.Entrypoint()] = true
+			candidates = append(candidates, candidate)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("no Cobra entrypoints found in %s; give the function that returns the root command with --entrypoint", projectPath)
+	}
+	if yes {
+		return &candidates[0], nil
+	}
+	return discovery.NewInteractiveSelector(in, cmd.OutOrStdout()).SelectCandidate(candidates)
+}
+
+// Global runner for testing
+var initRunner InitRunner = NewDefaultInitRunner()
+
+func runInit(cmd *cobra.Command, args []string) error {
+	// Default to current directory if no project path specified
+	path := projectPath
+	if path == "" {
+		var err error
+		path, err = os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get current directory: %w", err)
+		}
+	}
+
+	// The defaults of generate
+	opts := service.GenerateOptions{
+		ProjectPath:            path,
+		Entrypoint:             entrypoint,
+		Timeout:                timeout,
+		StripHelpCommand:       true,
+		StripCompletionCommand: true,
+		UseNameOnly:            true,
+	}
+	return initRunner.Run(cmd, opts, initYes)
+}
+
+// CompletionCheckRunner interface for dependency injection
+type CompletionCheckRunner interface {
+	Run(cmd *cobra.Command, opts service.ValidateOptions) error
+}
+
+// DefaultCompletionCheckRunner is the default implementation
+type DefaultCompletionCheckRunner struct {
+	service *service.ValidateService
+}
+
+// NewDefaultCompletionCheckRunner creates a new default runner
+func NewDefaultCompletionCheckRunner() *DefaultCompletionCheckRunner {
+	return &DefaultCompletionCheckRunner{
+		service: service.NewValidateService(),
+	}
+}
+
+// Run checks the flag completions and prints a report
+func (r *DefaultCompletionCheckRunner) Run(cmd *cobra.Command, opts service.ValidateOptions) error {
+	opts.CompletionsOnly = true
+
+	cmd.Printf("Inspecting CLI structure in: %s\n", opts.ProjectPath)
+	cmd.Println("Checking flag completions against contract...")
+
+	result, err := r.service.Validate(opts)
+	if err != nil {
+		return err
+	}
+
+	if result.Success {
+		cmd.Println("✅ Completion check passed! All flag completions match the contract.")
+		return nil
+	}
+
+	cmd.Println("❌ Completion check failed!")
+	cmd.Println()
+	result.Result.PrintReport()
+
+	return cliguarderrors.ErrValidationFailed
+}
+
+// Global runner for testing
+var completionCheckRunner CompletionCheckRunner = NewDefaultCompletionCheckRunner()
+
+func runCompletionCheck(cmd *cobra.Command, args []string) error {
+	// Default to current directory if no project path specified
+	path := projectPath
+	if path == "" {
+		var err error
+		path, err = os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get current directory: %w", err)
+		}
+	}
+	opts := service.ValidateOptions{
+		ProjectPath:  path,
+		ContractPath: contractPath,
+		Entrypoint:   entrypoint,
+		Timeout:      timeout,
+	}
+	err := completionCheckRunner.Run(cmd, opts)
+	return exitOnFailure(err)
+}
+
+// AuditRunner interface for dependency injection
+type AuditRunner interface {
+	Run(cmd *cobra.Command, contractPath string) error
+}
+
+// DefaultAuditRunner is the default implementation
+type DefaultAuditRunner struct {
+	ContractLoader func(string) (*contract.Contract, error)
+}
+
+// NewDefaultAuditRunner creates a new default runner
+func NewDefaultAuditRunner() *DefaultAuditRunner {
+	return &DefaultAuditRunner{
+		ContractLoader: contract.Load,
+	}
+}
+
+// severityIcons maps audit severities to the marker printed in front of findings
+var severityIcons = map[audit.Severity]string{
+	audit.SeverityHigh:   "🔴",
+	audit.SeverityMedium: "🟠",
+	audit.SeverityLow:    "🟡",
+	audit.SeverityInfo:   "🔵",
+}
+
+// Run loads the contract and prints the audit findings
+func (r *DefaultAuditRunner) Run(cmd *cobra.Command, contractPath string) error {
+	c, err := r.ContractLoader(contractPath)
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Auditing contract: %s\n", contractPath)
+
+	findings := audit.Audit(c)
+	if len(findings) == 0 {
+		fmt.Fprintln(out, "\n✅ No issues found.")
+		return nil
+	}
+
+	counts := make(map[audit.Severity]int)
+	for _, finding := range findings {
+		counts[finding.Severity]++
+		fmt.Fprintf(out, "\n%s [%s] %s\n", severityIcons[finding.Severity], finding.Severity, finding.Path)
+		fmt.Fprintf(out, "   %s\n", finding.Message)
+		fmt.Fprintf(out, "   Recommendation: %s\n", finding.Recommendation)
+	}
+
+	fmt.Fprintf(out, "\nFound %d issue(s): %d high, %d medium, %d low, %d info\n",
+		len(findings), counts[audit.SeverityHigh], counts[audit.SeverityMedium],
+		counts[audit.SeverityLow], counts[audit.SeverityInfo])
+	return nil
+}
+
+// Global runner for testing
+var auditRunner AuditRunner = NewDefaultAuditRunner()
+
+func runAudit(cmd *cobra.Command, args []string) error {
+	path := contractPath
+	if path == "" {
+		path = "cliguard.yaml"
+	}
+	return auditRunner.Run(cmd, path)
+}
+
+// LintRunner interface for dependency injection
+type LintRunner interface {
+	Run(cmd *cobra.Command, contractPath string, fix bool, outputPath string) error
+}
+
+// DefaultLintRunner is the default implementation
+type DefaultLintRunner struct {
+	service *service.LintService
+}
+
+// NewDefaultLintRunner creates a new default runner
+func NewDefaultLintRunner() *DefaultLintRunner {
+	return &DefaultLintRunner{
+		service: service.NewLintService(),
+	}
+}
+
+// Run prints the lint warnings for the contract or, if fix is set, fixes
+// them and prints a summary of the changes
+func (r *DefaultLintRunner) Run(cmd *cobra.Command, contractPath string, fix bool, outputPath string) error {
+	if outputPath != "" && !fix {
+		return fmt.Errorf("--output requires --fix")
+	}
+	out := cmd.OutOrStdout()
+
+	if !fix {
+		warnings, err := r.service.Lint(contractPath)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Linting contract: %s\n", contractPath)
+		if len(warnings) == 0 {
+			fmt.Fprintln(out, "\n✅ No issues found.")
+			return nil
+		}
+		fmt.Fprintln(out)
+		for _, warning := range warnings {
+			fmt.Fprintf(out, "⚠️  [%s] %s: %s\n", warning.Rule, warning.Path, warning.Message)
+		}
+		fmt.Fprintf(out, "\nFound %d issue(s). Run 'cliguard lint --fix' to fix them.\n", len(warnings))
+		return nil
+	}
+
+	if outputPath == "" {
+		outputPath = contractPath
+	}
+	applied, err := r.service.Fix(contractPath, outputPath)
+	if err != nil {
+		return err
+	}
+	if len(applied) == 0 {
+		fmt.Fprintln(out, "✅ No issues found.")
+		return nil
+	}
+
+	fixed := 0
+	for _, change := range applied {
+		fixed += len(change.Warnings)
+		fmt.Fprintf(out, "🔧 %s (%d)\n", change.Fixer.Description(), len(change.Warnings))
+		for _, warning := range change.Warnings {
+			fmt.Fprintf(out, "   %s\n", warning.Path)
+		}
+	}
+	fmt.Fprintf(out, "\n✅ Fixed %d issue(s); contract written to %s\n", fixed, outputPath)
+	return nil
+}
+
+// Global runner for testing
+var lintRunner LintRunner = NewDefaultLintRunner()
+
+func runLint(cmd *cobra.Command, args []string) error {
+	path := contractPath
+	if path == "" {
+		path = "cliguard.yaml"
+	}
+	return lintRunner.Run(cmd, path, lintFix, lintOutput)
+}
+
+// DoctorRunner interface for dependency injection
+type DoctorRunner interface {
+	Run(cmd *cobra.Command, projectPath string, fix bool) error
+}
+
+// DefaultDoctorRunner is the default implementation
+type DefaultDoctorRunner struct {
+	// config holds the doctor's dependencies; ProjectPath is set by Run
+	config doctor.Config
+}
+
+// NewDefaultDoctorRunner creates a new default runner
+func NewDefaultDoctorRunner() *DefaultDoctorRunner {
+	return &DefaultDoctorRunner{}
+}
+
+// statusIcons are printed before each diagnostic
+var statusIcons = map[doctor.Status]string{
+	doctor.StatusOK:      "✅",
+	doctor.StatusWarning: "⚠️ ",
+	doctor.StatusError:   "❌",
+}
+
+// Run diagnoses the project and, if fix is set, repairs what it can
+func (r *DefaultDoctorRunner) Run(cmd *cobra.Command, projectPath string, fix bool) error {
+	config := r.config
+	config.ProjectPath = projectPath
+	d := doctor.New(config)
+
+	out := cmd.OutOrStdout()
+	results := d.Diagnose()
+	for _, result := range results {
+		fmt.Fprintf(out, "%s %s: %s\n", statusIcons[result.Status], result.Check, result.Message)
+	}
+
+	if !fix {
+		fixable, unresolved := 0, 0
+		for _, result := range results {
+			if result.Status == doctor.StatusOK {
+				continue
+			}
+			if d.FixerFor(result) != nil {
+				fixable++
+			} else if result.Status == doctor.StatusError {
+				unresolved++
+			}
+		}
+		if fixable > 0 {
+			fmt.Fprintf(out, "\nRun 'cliguard doctor --fix' to fix %d problem(s).\n", fixable)
+		}
+		if unresolved > 0 {
+			return fmt.Errorf("doctor found %d problem(s) that cannot be fixed automatically", unresolved)
+		}
+		return nil
+	}
+
+	outcomes := d.Fix(results)
+	if len(outcomes) == 0 {
+		return nil
+	}
+
+	fmt.Fprintln(out)
+	unresolved := 0
+	for _, outcome := range outcomes {
+		check := outcome.Result.Check
+		switch {
+		case outcome.Fixer == nil:
+			fmt.Fprintf(out, "❌ %s: cannot be fixed automatically: %s\n", check, outcome.Result.Message)
+			if outcome.Result.Status == doctor.StatusError {
+				unresolved++
+			}
+		case outcome.Err != nil:
+			fmt.Fprintf(out, "❌ %s: fix failed: %v\n", check, outcome.Err)
+			unresolved++
+		default:
+			fmt.Fprintf(out, "🔧 %s: %s\n", check, outcome.Fixer.Description())
+		}
+	}
+	if unresolved > 0 {
+		return fmt.Errorf("doctor could not fix %d problem(s)", unresolved)
+	}
+	return nil
+}
+
+// Global runner for testing
+var doctorRunner DoctorRunner = NewDefaultDoctorRunner()
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	// Default to current directory if no project path specified
+	path := projectPath
+	if path == "" {
+		var err error
+		path, err = os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get current directory: %w", err)
+		}
+	}
+	return doctorRunner.Run(cmd, path, doctorFix)
+}
+
+// DiscoverRunner interface for dependency injection
+type DiscoverRunner interface {
+	Run(cmd *cobra.Command, opts DiscoverOptions) error
+}
+
+// DiscoverOptions configures a discovery
+type DiscoverOptions struct {
+	// ProjectPath is the root of the Go project to search
+	ProjectPath string
+
+	// Interactive prompts to select one of several candidates
+	Interactive bool
+
+	// Force suggests commands even for unsupported CLI frameworks
+	Force bool
+
+	// Output is the format of the candidates: text (the default) or json
+	Output string
+
+	// Verbose prints diagnostics: files scanned, skipped directories and
+	// parse errors
+	Verbose bool
+
+	// OutputScript prints a bash script that generates a contract for each
+	// entrypoint with a confidence of 70% or more
+	OutputScript bool
+
+	// MinConfidence drops candidates with a lower confidence percentage
+	MinConfidence int
+
+	// Frameworks are the frameworks to report candidates of, or all if empty
+	Frameworks []string
+}
+
+// DefaultDiscoverRunner is the default implementation
+type DefaultDiscoverRunner struct{}
+
+// NewDefaultDiscoverRunner creates a new default runner
+func NewDefaultDiscoverRunner() *DefaultDiscoverRunner {
+	return &DefaultDiscoverRunner{}
+}
+
+// Run executes the discovery
+func (r *DefaultDiscoverRunner) Run(cmd *cobra.Command, opts DiscoverOptions) error {
+	switch opts.Output {
+	case "", "text":
+	case "json":
+		if opts.Interactive {
+			return fmt.Errorf("--interactive cannot be combined with --output json")
+		}
+	default:
+		return fmt.Errorf("invalid output format '%s' (supported: text, json)", opts.Output)
+	}
+	if opts.OutputScript && (opts.Interactive || opts.Output == "json") {
+		return fmt.Errorf("--output-script cannot be combined with --interactive or --output json")
+	}
+	if opts.MinConfidence < 0 || opts.MinConfidence > 100 {
+		return fmt.Errorf("--min-confidence must be between 0 and 100, got %d", opts.MinConfidence)
+	}
+	supported := discovery.FrameworkNames()
+	for _, framework := range opts.Frameworks {
+		if !slices.Contains(supported, framework) {
+			return fmt.Errorf("unknown framework '%s' (supported: %s)", framework, strings.Join(supported, ", "))
+		}
+	}
+
+	// Convert to absolute path if needed
+	absPath, err := filepath.Abs(opts.ProjectPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve project path: %w", err)
+	}
+
+	discoverer := discovery.NewDiscoverer(absPath, nil)
+	// discover finds the entrypoints of opts.Frameworks, without those
+	// below opts.MinConfidence
+	discover := func() (*discovery.DiscovererResult, error) {
+		result, err := discoverer.DiscoverEntrypoints()
+		if err != nil {
+			return nil, fmt.Errorf("failed to discover entrypoints: %w", err)
+		}
+		result.Candidates = discovery.FilterByConfidence(result.Candidates, opts.MinConfidence)
+		result.Candidates = discovery.FilterByFramework(result.Candidates, opts.Frameworks)
+		return result, nil
+	}
+
+	if opts.OutputScript {
+		result, err := discover()
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(cmd.OutOrStdout(), discovery.FormatAsShellScript(result.Candidates))
+		// Keep stdout a valid script
+		if opts.Verbose {
+			discovery.PrintDiagnostics(cmd.ErrOrStderr(), result)
+		}
+		return nil
+	}
+
+	if opts.Output == "json" {
+		result, err := discover()
+		if err != nil {
+			return err
+		}
+		data, err := discovery.FormatCandidatesJSON(result.Candidates, absPath)
+		if err != nil {
+			return err
+		}
+		cmd.OutOrStdout().Write(data)
+		// Keep stdout valid JSON
+		if opts.Verbose {
+			discovery.PrintDiagnostics(cmd.ErrOrStderr(), result)
+		}
+		return nil
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Searching for CLI entrypoints in: %s\n\n", opts.ProjectPath)
+
+	result, err := discover()
+	if err != nil {
+		return err
+	}
+
+	// Handle interactive mode
+	if opts.Interactive && len(result.Candidates) > 1 {
+		selector := discovery.NewInteractiveSelector(cmd.InOrStdin(), cmd.OutOrStdout())
+		selected, err := selector.SelectCandidate(result.Candidates)
+		if err != nil {
+			return err
+		}
 
 		fmt.Fprintf(cmd.OutOrStdout(), "\nSelected entrypoint:\n%s\n",
-			discovery.FormatSelectedEntrypoint(selected))
+			discovery.FormatSelectedEntrypoint(selected, absPath))
 		return nil
 	}
 
-	discovery.PrintCandidates(cmd.OutOrStdout(), candidates, projectPath, force)
+	// The suggested commands use the absolute path, made relative when the
+	// project is below the working directory
+	discovery.PrintCandidates(cmd.OutOrStdout(), result, absPath, opts.Force, opts.Verbose)
 	return nil
 }
 
@@ -293,5 +2745,279 @@ func (r *DefaultDiscoverRunner) Run(cmd *cobra.Command, projectPath string, inte
 var discoverRunner DiscoverRunner = NewDefaultDiscoverRunner()
 
 func runDiscover(cmd *cobra.Command, args []string) error {
-	return discoverRunner.Run(cmd, projectPath, interactive, force)
+	return discoverRunner.Run(cmd, DiscoverOptions{
+		ProjectPath:   projectPath,
+		Interactive:   interactive,
+		Force:         force,
+		Output:        discoverOutput,
+		Verbose:       discoverVerbose,
+		OutputScript:  discoverScript,
+		MinConfidence: discoverMinConfidence,
+		Frameworks:    discoverFrameworks,
+	})
+}
+
+// ReplRunner interface for dependency injection
+type ReplRunner interface {
+	Run(cmd *cobra.Command) error
+}
+
+// DefaultReplRunner is the default implementation
+type DefaultReplRunner struct {
+	HistoryPath string
+}
+
+// NewDefaultReplRunner creates a new default runner
+func NewDefaultReplRunner() *DefaultReplRunner {
+	return &DefaultReplRunner{
+		HistoryPath: repl.DefaultHistoryPath(),
+	}
+}
+
+// Run starts the interactive session
+func (r *DefaultReplRunner) Run(cmd *cobra.Command) error {
+	if replActive {
+		return fmt.Errorf("already in an interactive session")
+	}
+	replActive = true
+	exitOnValidationFailure = false
+	defer func() {
+		replActive = false
+		exitOnValidationFailure = true
+	}()
+
+	session := repl.New(repl.Config{
+		In:          cmd.InOrStdin(),
+		Out:         cmd.OutOrStdout(),
+		NewRoot:     NewRootCmd,
+		HistoryPath: r.HistoryPath,
+	})
+	return session.Run()
+}
+
+// replActive is set while a REPL session is running, to prevent nesting
+var replActive bool
+
+// Global runner for testing
+var replRunner ReplRunner = NewDefaultReplRunner()
+
+func runRepl(cmd *cobra.Command, args []string) error {
+	return replRunner.Run(cmd)
+}
+
+// MigrateContractRunner interface for dependency injection
+type MigrateContractRunner interface {
+	Run(cmd *cobra.Command, inputPath, outputPath string) error
+}
+
+// DefaultMigrateContractRunner is the default implementation
+type DefaultMigrateContractRunner struct {
+	service *service.MigrateService
+}
+
+// NewDefaultMigrateContractRunner creates a new default runner
+func NewDefaultMigrateContractRunner() *DefaultMigrateContractRunner {
+	return &DefaultMigrateContractRunner{
+		service: service.NewMigrateService(),
+	}
+}
+
+// Run migrates the contract and writes it to outputPath, or stdout if empty
+func (r *DefaultMigrateContractRunner) Run(cmd *cobra.Command, inputPath, outputPath string) error {
+	if outputPath != "" {
+		if err := r.service.MigrateToFile(inputPath, outputPath); err != nil {
+			return err
+		}
+		cmd.Printf("✅ Contract written to %s\n", outputPath)
+		return nil
+	}
+
+	content, err := r.service.Migrate(inputPath)
+	if err != nil {
+		return err
+	}
+	fmt.Fprint(cmd.OutOrStdout(), content)
+	return nil
+}
+
+// Global runner for testing
+var migrateContractRunner MigrateContractRunner = NewDefaultMigrateContractRunner()
+
+func runMigrateContract(cmd *cobra.Command, args []string) error {
+	return migrateContractRunner.Run(cmd, migrateInput, migrateOutput)
+}
+
+// ContractGraphRunner interface for dependency injection
+type ContractGraphRunner interface {
+	Run(cmd *cobra.Command, contractPath, outputPath, format string, includeFlags bool) error
+}
+
+// DefaultContractGraphRunner is the default implementation
+type DefaultContractGraphRunner struct {
+	ContractLoader func(string) (*contract.Contract, error)
+}
+
+// NewDefaultContractGraphRunner creates a new default runner
+func NewDefaultContractGraphRunner() *DefaultContractGraphRunner {
+	return &DefaultContractGraphRunner{
+		ContractLoader: contract.Load,
+	}
+}
+
+// Run renders the contract's command tree and writes it to outputPath, or
+// stdout if empty
+func (r *DefaultContractGraphRunner) Run(cmd *cobra.Command, contractPath, outputPath, format string, includeFlags bool) error {
+	c, err := r.ContractLoader(contractPath)
+	if err != nil {
+		return err
+	}
+
+	graph, err := formatter.Graph{IncludeFlags: includeFlags}.Render(c, format)
+	if err != nil {
+		return err
+	}
+
+	if outputPath == "" {
+		fmt.Fprint(cmd.OutOrStdout(), graph)
+		return nil
+	}
+	if err := os.WriteFile(outputPath, []byte(graph), 0644); err != nil {
+		return fmt.Errorf("failed to write graph to '%s': %w", outputPath, err)
+	}
+	cmd.Printf("✅ Graph written to %s\n", outputPath)
+	return nil
+}
+
+// Global runner for testing
+var contractGraphRunner ContractGraphRunner = NewDefaultContractGraphRunner()
+
+func runContractGraph(cmd *cobra.Command, args []string) error {
+	path := contractPath
+	if path == "" {
+		path = "cliguard.yaml"
+	}
+	return contractGraphRunner.Run(cmd, path, graphOutput, graphFormat, graphIncludeFlags)
+}
+
+// ImportsRunner interface for dependency injection
+type ImportsRunner interface {
+	Run(cmd *cobra.Command, projectPath, entrypoint, commandPath string) error
+}
+
+// DefaultImportsRunner is the default implementation
+type DefaultImportsRunner struct {
+	SourceFinder func(projectPath, entrypoint, commandPath string) (*analysis.Sources, error)
+}
+
+// NewDefaultImportsRunner creates a new default runner
+func NewDefaultImportsRunner() *DefaultImportsRunner {
+	return &DefaultImportsRunner{
+		SourceFinder: analysis.FindCommandSources,
+	}
+}
+
+// Run prints the source files and packages of the command
+func (r *DefaultImportsRunner) Run(cmd *cobra.Command, projectPath, entrypoint, commandPath string) error {
+	sources, err := r.SourceFinder(projectPath, entrypoint, commandPath)
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Files defining %s:\n", sources.Command)
+	for _, file := range sources.Files {
+		fmt.Fprintf(out, "  %s (%s)\n", file.Path, strings.Join(file.Roles, ", "))
+	}
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Packages:")
+	for _, pkg := range sources.Packages() {
+		fmt.Fprintf(out, "  %s\n", pkg)
+	}
+	return nil
+}
+
+// Global runner for testing
+var importsRunner ImportsRunner = NewDefaultImportsRunner()
+
+func runImports(cmd *cobra.Command, args []string) error {
+	path := projectPath
+	if path == "" {
+		var err error
+		path, err = os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get current directory: %w", err)
+		}
+	}
+	return importsRunner.Run(cmd, path, entrypoint, importsCommand)
+}
+
+// BenchmarkRunner interface for dependency injection
+type BenchmarkRunner interface {
+	Run(cmd *cobra.Command, opts benchmark.Options, outputPath string) error
+}
+
+// DefaultBenchmarkRunner is the default implementation
+type DefaultBenchmarkRunner struct {
+	// Benchmark runs the benchmark. Defaults to benchmark.Run
+	Benchmark func(benchmark.Options) (*benchmark.Result, error)
+}
+
+// NewDefaultBenchmarkRunner creates a new default runner
+func NewDefaultBenchmarkRunner() *DefaultBenchmarkRunner {
+	return &DefaultBenchmarkRunner{
+		Benchmark: benchmark.Run,
+	}
+}
+
+// Run runs the benchmark, prints the results as a markdown table and, if
+// outputPath is set, writes them there as JSON
+func (r *DefaultBenchmarkRunner) Run(cmd *cobra.Command, opts benchmark.Options, outputPath string) error {
+	if opts.Iterations < 1 {
+		return fmt.Errorf("--iterations must be at least 1")
+	}
+
+	cmd.Printf("Benchmarking CLI inspection in: %s\n", opts.ProjectPath)
+	opts.OnIteration = func(n int) {
+		cmd.Printf("Running iteration %d/%d...\n", n, opts.Iterations)
+	}
+	result, err := r.Benchmark(opts)
+	if err != nil {
+		return err
+	}
+
+	if outputPath != "" {
+		data, err := result.FormatJSON()
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(outputPath, data, 0644); err != nil {
+			return fmt.Errorf("failed to write benchmark results: %w", err)
+		}
+		cmd.Printf("Benchmark results written to %s\n", outputPath)
+	}
+
+	fmt.Fprint(cmd.OutOrStdout(), result.FormatMarkdown())
+	return nil
+}
+
+// Global runner for testing
+var benchmarkRunner BenchmarkRunner = NewDefaultBenchmarkRunner()
+
+func runBenchmark(cmd *cobra.Command, args []string) error {
+	// Default to current directory if no project path specified
+	path := projectPath
+	if path == "" {
+		var err error
+		path, err = os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get current directory: %w", err)
+		}
+	}
+	return benchmarkRunner.Run(cmd, benchmark.Options{
+		ProjectPath:  path,
+		Entrypoint:   entrypoint,
+		Iterations:   benchmarkIterations,
+		ContractPath: benchmarkContract,
+		Timeout:      timeout,
+	}, benchmarkOutput)
 }
@@ -2,16 +2,28 @@ package cmd
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/hiAndrewQuinn/cliguard/internal/analysis"
+	"github.com/hiAndrewQuinn/cliguard/internal/benchmark"
 	"github.com/hiAndrewQuinn/cliguard/internal/contract"
+	"github.com/hiAndrewQuinn/cliguard/internal/discovery"
+	"github.com/hiAndrewQuinn/cliguard/internal/doctor"
+	cliguarderrors "github.com/hiAndrewQuinn/cliguard/internal/errors"
+	"github.com/hiAndrewQuinn/cliguard/internal/executor"
+	"github.com/hiAndrewQuinn/cliguard/internal/filesystem"
 	"github.com/hiAndrewQuinn/cliguard/internal/inspector"
+	"github.com/hiAndrewQuinn/cliguard/internal/output"
 	"github.com/hiAndrewQuinn/cliguard/internal/service"
 	"github.com/hiAndrewQuinn/cliguard/internal/validator"
 	"github.com/spf13/cobra"
@@ -19,28 +31,30 @@ import (
 
 // MockValidateRunner for testing
 type MockValidateRunner struct {
-	RunFunc func(cmd *cobra.Command, projectPath, contractPath, entrypoint string, timeout time.Duration, force bool) error
+	RunFunc func(cmd *cobra.Command, opts service.ValidateOptions, report ValidateReportOptions) error
 	Calls   []MockCall
+
+	WatchFunc  func(opts service.ValidateOptions, onChange func()) error
+	WatchCalls []service.ValidateOptions
 }
 
 type MockCall struct {
-	ProjectPath  string
-	ContractPath string
-	Entrypoint   string
-	Timeout      time.Duration
-	Force        bool
+	Opts   service.ValidateOptions
+	Report ValidateReportOptions
 }
 
-func (m *MockValidateRunner) Run(cmd *cobra.Command, projectPath, contractPath, entrypoint string, timeout time.Duration, force bool) error {
-	m.Calls = append(m.Calls, MockCall{
-		ProjectPath:  projectPath,
-		ContractPath: contractPath,
-		Entrypoint:   entrypoint,
-		Timeout:      timeout,
-		Force:        force,
-	})
+func (m *MockValidateRunner) Run(cmd *cobra.Command, opts service.ValidateOptions, report ValidateReportOptions) error {
+	m.Calls = append(m.Calls, MockCall{Opts: opts, Report: report})
 	if m.RunFunc != nil {
-		return m.RunFunc(cmd, projectPath, contractPath, entrypoint, timeout, force)
+		return m.RunFunc(cmd, opts, report)
+	}
+	return nil
+}
+
+func (m *MockValidateRunner) Watch(opts service.ValidateOptions, onChange func()) error {
+	m.WatchCalls = append(m.WatchCalls, opts)
+	if m.WatchFunc != nil {
+		return m.WatchFunc(opts, onChange)
 	}
 	return nil
 }
@@ -52,7 +66,7 @@ func TestExecute(t *testing.T) {
 
 	// Create a mock runner
 	mockRunner := &MockValidateRunner{
-		RunFunc: func(cmd *cobra.Command, projectPath, contractPath, entrypoint string, timeout time.Duration, force bool) error {
+		RunFunc: func(cmd *cobra.Command, opts service.ValidateOptions, report ValidateReportOptions) error {
 			return nil
 		},
 	}
@@ -110,7 +124,7 @@ func TestRunValidate_Success(t *testing.T) {
 
 	// Create mock runner that returns success
 	mockRunner := &MockValidateRunner{
-		RunFunc: func(cmd *cobra.Command, projectPath, contractPath, entrypoint string, timeout time.Duration, force bool) error {
+		RunFunc: func(cmd *cobra.Command, opts service.ValidateOptions, report ValidateReportOptions) error {
 			cmd.Println("✅ Validation passed! CLI structure matches the contract.")
 			return nil
 		},
@@ -148,426 +162,3722 @@ func TestRunValidate_Success(t *testing.T) {
 	}
 
 	call := mockRunner.Calls[0]
-	if call.ProjectPath != "/tmp/test" {
-		t.Errorf("ProjectPath = %q, want %q", call.ProjectPath, "/tmp/test")
+	if call.Opts.ProjectPath != "/tmp/test" {
+		t.Errorf("ProjectPath = %q, want %q", call.Opts.ProjectPath, "/tmp/test")
 	}
-	if call.ContractPath != "/tmp/test/contract.yaml" {
-		t.Errorf("ContractPath = %q, want %q", call.ContractPath, "/tmp/test/contract.yaml")
+	if call.Opts.ContractPath != "/tmp/test/contract.yaml" {
+		t.Errorf("ContractPath = %q, want %q", call.Opts.ContractPath, "/tmp/test/contract.yaml")
 	}
-	if call.Entrypoint != "test.Func" {
-		t.Errorf("Entrypoint = %q, want %q", call.Entrypoint, "test.Func")
+	if call.Opts.Entrypoint != "test.Func" {
+		t.Errorf("Entrypoint = %q, want %q", call.Opts.Entrypoint, "test.Func")
 	}
 }
 
-func TestDefaultValidateRunner(t *testing.T) {
-	// Create a test service with mocked dependencies
-	runner := NewDefaultValidateRunner()
+func TestRunValidate_StrictSortOrder(t *testing.T) {
+	originalRunner := validateRunner
+	defer func() { validateRunner = originalRunner }()
 
-	// Mock the service dependencies
-	mockContractLoader := func(path string) (*contract.Contract, error) {
-		if path == "/test/contract.yaml" {
-			return &contract.Contract{
-				Use:   "myapp",
-				Short: "My app",
-			}, nil
+	for _, args := range [][]string{
+		{"validate", "--entrypoint", "test.Func"},
+		{"validate", "--entrypoint", "test.Func", "--strict-sort-order"},
+	} {
+		mockRunner := &MockValidateRunner{}
+		validateRunner = mockRunner
+
+		cmd := NewRootCmd()
+		cmd.SetOut(new(bytes.Buffer))
+		cmd.SetErr(new(bytes.Buffer))
+		cmd.SetArgs(args)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("Execute(%v) error = %v", args, err)
 		}
-		return nil, errors.New("contract not found")
-	}
 
-	mockInspector := func(projectPath, entrypoint string) (*inspector.InspectedCLI, error) {
-		if projectPath == "/test/project" {
-			return &inspector.InspectedCLI{
-				Use:   "myapp",
-				Short: "My app",
-			}, nil
+		want := len(args) == 4
+		if len(mockRunner.Calls) != 1 || mockRunner.Calls[0].Opts.StrictSortOrder != want {
+			t.Errorf("Execute(%v) calls = %+v, want StrictSortOrder %v", args, mockRunner.Calls, want)
 		}
-		return nil, errors.New("project not found")
 	}
+}
 
-	runner.service.ContractLoader = mockContractLoader
-	runner.service.Inspector = mockInspector
+func TestRunValidate_ReportFormatFlag(t *testing.T) {
+	originalRunner := validateRunner
+	defer func() { validateRunner = originalRunner }()
 
-	// Test successful validation
-	t.Run("success", func(t *testing.T) {
-		// Create a temp directory that actually exists
-		tmpDir := t.TempDir()
-		contractPath := filepath.Join(tmpDir, "contract.yaml")
+	mockRunner := &MockValidateRunner{}
+	validateRunner = mockRunner
 
-		// Update mocks to use the real paths
-		runner.service.ContractLoader = func(path string) (*contract.Contract, error) {
-			if path == contractPath {
-				return &contract.Contract{
-					Use:   "myapp",
-					Short: "My app",
-				}, nil
-			}
-			return nil, errors.New("contract not found")
-		}
+	cmd := NewRootCmd()
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{"validate", "--entrypoint", "test.Func", "--report-format", "markdown", "--github-comment"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
 
-		runner.service.Inspector = func(projectPath, entrypoint string) (*inspector.InspectedCLI, error) {
-			if projectPath == tmpDir {
-				return &inspector.InspectedCLI{
-					Use:   "myapp",
-					Short: "My app",
-				}, nil
-			}
-			return nil, errors.New("project not found")
-		}
+	if len(mockRunner.Calls) != 1 {
+		t.Fatalf("calls = %+v, want 1", mockRunner.Calls)
+	}
+	if call := mockRunner.Calls[0]; call.Report.Output != validator.ReportFormatMarkdown || !call.Report.GitHubComment {
+		t.Errorf("call = %+v, want markdown output and GitHubComment", call)
+	}
 
-		cmd := &cobra.Command{}
-		buf := new(bytes.Buffer)
-		cmd.SetOut(buf)
+	// --report-format is an alias, so help lists --output only
+	var help bytes.Buffer
+	cmd = NewRootCmd()
+	cmd.SetOut(&help)
+	cmd.SetArgs([]string{"validate", "--help"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if contains(help.String(), "--report-format string") || !contains(help.String(), "--output string") {
+		t.Errorf("help lists --report-format as a flag of its own:\n%s", help.String())
+	}
+}
 
-		err := runner.Run(cmd, tmpDir, contractPath, "test.Func", 30*time.Second, false)
-		if err != nil {
-			t.Errorf("Run() error = %v, want nil", err)
-		}
+func TestRunValidate_ExpandedContractFlag(t *testing.T) {
+	originalRunner := validateRunner
+	defer func() { validateRunner = originalRunner }()
 
-		output := buf.String()
-		if !contains(output, "Validation passed") {
-			t.Errorf("Expected success message in output, got: %q", output)
-		}
-	})
+	mockRunner := &MockValidateRunner{}
+	validateRunner = mockRunner
 
-	// Test error cases
-	t.Run("project not found", func(t *testing.T) {
-		cmd := &cobra.Command{}
-		buf := new(bytes.Buffer)
-		cmd.SetOut(buf)
+	cmd := NewRootCmd()
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{"validate", "--entrypoint", "test.Func", "--expanded-contract"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
 
-		err := runner.Run(cmd, "/nonexistent", "/test/contract.yaml", "test.Func", 30*time.Second, false)
-		if err == nil {
-			t.Error("Expected error for nonexistent project")
-		}
-		if !contains(err.Error(), "Project path does not exist") {
-			t.Errorf("Error = %q, want to contain 'Project path does not exist'", err.Error())
-		}
-	})
+	if len(mockRunner.Calls) != 1 || !mockRunner.Calls[0].Opts.ExpandedContract {
+		t.Errorf("calls = %+v, want one call with ExpandedContract", mockRunner.Calls)
+	}
+}
 
-	t.Run("contract not found", func(t *testing.T) {
-		// Create a temp directory for the project
-		tmpDir := t.TempDir()
+func TestRunValidate_StrictContractFlag(t *testing.T) {
+	originalRunner := validateRunner
+	defer func() { validateRunner = originalRunner }()
 
-		cmd := &cobra.Command{}
-		buf := new(bytes.Buffer)
-		cmd.SetOut(buf)
+	mockRunner := &MockValidateRunner{}
+	validateRunner = mockRunner
 
-		err := runner.Run(cmd, tmpDir, "/nonexistent/contract.yaml", "test.Func", 30*time.Second, false)
-		if err == nil {
-			t.Error("Expected error for nonexistent contract")
-		}
-		if !contains(err.Error(), "failed to load contract") {
-			t.Errorf("Error = %q, want to contain 'failed to load contract'", err.Error())
-		}
-	})
+	cmd := NewRootCmd()
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{"validate", "--entrypoint", "test.Func", "--strict-contract"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if len(mockRunner.Calls) != 1 || !mockRunner.Calls[0].Opts.StrictContract {
+		t.Errorf("calls = %+v, want one call with StrictContract", mockRunner.Calls)
+	}
 }
 
-func TestValidateServiceIntegration(t *testing.T) {
-	// Test the actual ValidateService
-	svc := service.NewValidateService()
+func TestRunValidate_CobraUseNameOnlyFlag(t *testing.T) {
+	originalRunner := validateRunner
+	defer func() { validateRunner = originalRunner }()
 
-	// Test with nonexistent project path
-	t.Run("nonexistent project", func(t *testing.T) {
-		opts := service.ValidateOptions{
-			ProjectPath: "/nonexistent/path",
-		}
+	mockRunner := &MockValidateRunner{}
+	validateRunner = mockRunner
 
-		_, err := svc.Validate(opts)
-		if err == nil {
-			t.Error("Expected error for nonexistent project")
-		}
-		if !contains(err.Error(), "Project path does not exist") {
-			t.Errorf("Error = %q, want to contain 'Project path does not exist'", err.Error())
-		}
-	})
+	cmd := NewRootCmd()
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{"validate", "--entrypoint", "test.Func", "--cobra-use-name-only"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
 
-	// Test with existing but invalid project
-	t.Run("invalid project", func(t *testing.T) {
-		tmpDir := t.TempDir()
+	if len(mockRunner.Calls) != 1 || !mockRunner.Calls[0].Opts.UseNameOnly {
+		t.Errorf("calls = %+v, want one call with UseNameOnly", mockRunner.Calls)
+	}
+}
 
-		// Create a dummy contract file
-		contractPath := filepath.Join(tmpDir, "cliguard.yaml")
-		contractContent := `use: test
-short: Test CLI
-`
-		os.WriteFile(contractPath, []byte(contractContent), 0644)
+func TestRunValidate_StrictUseFlag(t *testing.T) {
+	originalRunner := validateRunner
+	defer func() { validateRunner = originalRunner }()
 
-		opts := service.ValidateOptions{
-			ProjectPath:  tmpDir,
-			ContractPath: contractPath,
-			Entrypoint:   "test.Func",
-		}
+	tests := []struct {
+		args            []string
+		wantUseNameOnly bool
+		wantErr         bool
+	}{
+		{args: nil, wantUseNameOnly: true},
+		{args: []string{"--strict-use"}, wantUseNameOnly: false},
+		{args: []string{"--strict-use", "--cobra-use-name-only"}, wantErr: true},
+	}
 
-		_, err := svc.Validate(opts)
-		if err == nil {
-			t.Error("Expected error for invalid project")
-		}
-		// The error will be from the inspector trying to run go commands
-		if !contains(err.Error(), "Failed to inspect project") {
-			t.Errorf("Error = %q, want to contain 'Failed to inspect project'", err.Error())
-		}
-	})
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.args), func(t *testing.T) {
+			mockRunner := &MockValidateRunner{}
+			validateRunner = mockRunner
+
+			cmd := NewRootCmd()
+			cmd.SetOut(new(bytes.Buffer))
+			cmd.SetErr(new(bytes.Buffer))
+			cmd.SetArgs(append([]string{"validate", "--entrypoint", "test.Func"}, tt.args...))
+			err := cmd.Execute()
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Execute() error = nil, want the flags rejected")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if len(mockRunner.Calls) != 1 || mockRunner.Calls[0].Opts.UseNameOnly != tt.wantUseNameOnly {
+				t.Errorf("calls = %+v, want UseNameOnly %v", mockRunner.Calls, tt.wantUseNameOnly)
+			}
+		})
+	}
 }
 
-func TestCommandFlags(t *testing.T) {
-	cmd := NewRootCmd()
-	validateCmd, _, _ := cmd.Find([]string{"validate"})
-
-	// Test flag defaults
-	t.Run("flag defaults", func(t *testing.T) {
-		// Reset flags
-		projectPath = ""
-		contractPath = ""
-		entrypoint = ""
+func TestRunValidate_StrictFlag(t *testing.T) {
+	originalRunner := validateRunner
+	defer func() { validateRunner = originalRunner }()
 
-		if projectPath != "" {
-			t.Errorf("projectPath default = %q, want empty", projectPath)
-		}
-		if contractPath != "" {
-			t.Errorf("contractPath default = %q, want empty", contractPath)
-		}
-		if entrypoint != "" {
-			t.Errorf("entrypoint default = %q, want empty", entrypoint)
-		}
-	})
+	tests := []struct {
+		args       []string
+		wantStrict bool
+		wantErr    bool
+	}{
+		{args: nil, wantStrict: false},
+		{args: []string{"--strict"}, wantStrict: true},
+		{args: []string{"--strict", "--allow-extra-commands"}, wantErr: true},
+		{args: []string{"--strict", "--allow-extra-flags"}, wantErr: true},
+	}
 
-	// Test flag parsing
-	t.Run("flag parsing", func(t *testing.T) {
-		// Parse flags
-		validateCmd.ParseFlags([]string{
-			"--project-path", "/test/path",
-			"--contract", "/test/contract.yaml",
-			"--entrypoint", "test.NewCmd",
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.args), func(t *testing.T) {
+			mockRunner := &MockValidateRunner{}
+			validateRunner = mockRunner
+
+			cmd := NewRootCmd()
+			cmd.SetOut(new(bytes.Buffer))
+			cmd.SetErr(new(bytes.Buffer))
+			cmd.SetArgs(append([]string{"validate", "--entrypoint", "test.Func"}, tt.args...))
+			err := cmd.Execute()
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Execute() error = nil, want the flags rejected")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if len(mockRunner.Calls) != 1 || mockRunner.Calls[0].Opts.StrictMode != tt.wantStrict {
+				t.Errorf("calls = %+v, want Strict %v", mockRunner.Calls, tt.wantStrict)
+			}
 		})
+	}
+}
 
-		// Check values were set
-		if flag := validateCmd.Flag("project-path"); flag.Value.String() != "/test/path" {
-			t.Errorf("project-path = %q, want %q", flag.Value.String(), "/test/path")
-		}
-		if flag := validateCmd.Flag("contract"); flag.Value.String() != "/test/contract.yaml" {
-			t.Errorf("contract = %q, want %q", flag.Value.String(), "/test/contract.yaml")
+func TestRunValidate_InspectorTimeoutFlag(t *testing.T) {
+	originalRunner := validateRunner
+	defer func() { validateRunner = originalRunner }()
+
+	for _, tt := range []struct {
+		args []string
+		want time.Duration
+	}{
+		{args: nil, want: service.DefaultInspectorTimeout},
+		{args: []string{"--inspector-timeout", "2m"}, want: 2 * time.Minute},
+	} {
+		mockRunner := &MockValidateRunner{}
+		validateRunner = mockRunner
+
+		cmd := NewRootCmd()
+		cmd.SetOut(new(bytes.Buffer))
+		cmd.SetErr(new(bytes.Buffer))
+		cmd.SetArgs(append([]string{"validate", "--entrypoint", "test.Func"}, tt.args...))
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("Execute() error = %v", err)
 		}
-		if flag := validateCmd.Flag("entrypoint"); flag.Value.String() != "test.NewCmd" {
-			t.Errorf("entrypoint = %q, want %q", flag.Value.String(), "test.NewCmd")
+
+		if len(mockRunner.Calls) != 1 || mockRunner.Calls[0].Report.InspectorTimeout != tt.want {
+			t.Errorf("args %v: calls = %+v, want one call with InspectorTimeout %v", tt.args, mockRunner.Calls, tt.want)
 		}
-	})
+	}
 }
 
-func TestValidationErrors(t *testing.T) {
-	// Save original runner
+func TestRunValidate_ExpectVersionFlags(t *testing.T) {
 	originalRunner := validateRunner
 	defer func() { validateRunner = originalRunner }()
 
-	// Create a custom service that simulates validation failure
-	runner := NewDefaultValidateRunner()
-	runner.service.ContractLoader = func(path string) (*contract.Contract, error) {
-		return &contract.Contract{
-			Use:   "expected",
-			Short: "Expected CLI",
-		}, nil
+	mockRunner := &MockValidateRunner{}
+	validateRunner = mockRunner
+
+	cmd := NewRootCmd()
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{"validate", "--entrypoint", "test.Func", "--expect-version", "--version-command", "version --short"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
 	}
-	runner.service.Inspector = func(projectPath, entrypoint string) (*inspector.InspectedCLI, error) {
-		return &inspector.InspectedCLI{
-			Use:   "actual",
-			Short: "Actual CLI",
-		}, nil
+
+	if len(mockRunner.Calls) != 1 {
+		t.Fatalf("calls = %+v, want one call", mockRunner.Calls)
+	}
+	if call := mockRunner.Calls[0]; !call.Opts.ExpectVersion || call.Opts.VersionCommand != "version --short" {
+		t.Errorf("call = %+v, want ExpectVersion with VersionCommand \"version --short\"", call)
 	}
 
-	validateRunner = runner
+	err := NewDefaultValidateRunner().Run(&cobra.Command{}, service.ValidateOptions{
+		ProjectPath:    t.TempDir(),
+		Entrypoint:     "test.Func",
+		Timeout:        30 * time.Second,
+		VersionCommand: "version",
+	}, ValidateReportOptions{})
+	if err == nil || !contains(err.Error(), "--version-command requires --expect-version") {
+		t.Errorf("Run() error = %v, want --version-command requires --expect-version", err)
+	}
+}
+
+func TestRunValidate_IgnoreCommandsRegexFlag(t *testing.T) {
+	originalRunner := validateRunner
+	defer func() { validateRunner = originalRunner }()
+
+	mockRunner := &MockValidateRunner{}
+	validateRunner = mockRunner
+
+	cmd := NewRootCmd()
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{"validate", "--entrypoint", "test.Func", "--ignore-commands-regex", "-deprecated$", "--ignore-commands-regex", "^app debug{1,2}"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if len(mockRunner.Calls) != 1 {
+		t.Fatalf("calls = %+v, want one call", mockRunner.Calls)
+	}
+	// Repeated values are kept whole, commas included
+	if got := mockRunner.Calls[0].Opts.IgnoreCommandsRegex; len(got) != 2 || got[0] != "-deprecated$" || got[1] != "^app debug{1,2}" {
+		t.Errorf("IgnoreCommandsRegex = %q, want both patterns", got)
+	}
+}
+
+func TestRunValidate_PathFlag(t *testing.T) {
+	originalRunner := validateRunner
+	defer func() { validateRunner = originalRunner }()
+
+	mockRunner := &MockValidateRunner{}
+	validateRunner = mockRunner
+
+	cmd := NewRootCmd()
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{"validate", "--entrypoint", "test.Func", "--path", "create.user", "--path", "db.migrate,version"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if len(mockRunner.Calls) != 1 {
+		t.Fatalf("calls = %+v, want one call", mockRunner.Calls)
+	}
+	if got := mockRunner.Calls[0].Opts.FocusPaths; len(got) != 3 || got[0] != "create.user" || got[1] != "db.migrate" || got[2] != "version" {
+		t.Errorf("FocusPaths = %q, want the three paths", got)
+	}
+}
+
+func TestRunValidate_AllowExtraFlags(t *testing.T) {
+	originalRunner := validateRunner
+	defer func() { validateRunner = originalRunner }()
+
+	mockRunner := &MockValidateRunner{}
+	validateRunner = mockRunner
+
+	cmd := NewRootCmd()
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{"validate", "--entrypoint", "test.Func", "--allow-extra-commands", "--allow-extra-flags"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if len(mockRunner.Calls) != 1 {
+		t.Fatalf("calls = %+v, want one call", mockRunner.Calls)
+	}
+	if call := mockRunner.Calls[0]; !call.Opts.AllowExtraCommands || !call.Opts.AllowExtraFlags {
+		t.Errorf("AllowExtraCommands = %v, AllowExtraFlags = %v, want both set", call.Opts.AllowExtraCommands, call.Opts.AllowExtraFlags)
+	}
+}
+
+func TestRunValidate_FailFastFlags(t *testing.T) {
+	originalRunner := validateRunner
+	defer func() { validateRunner = originalRunner }()
+
+	tests := []struct {
+		args         []string
+		wantFailFast bool
+		wantErr      string
+	}{
+		{args: nil, wantFailFast: false},
+		{args: []string{"--fail-fast"}, wantFailFast: true},
+		{args: []string{"--no-fail-fast"}, wantFailFast: false},
+		{args: []string{"--fail-fast", "--no-fail-fast"}, wantErr: "none of the others can be"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.args), func(t *testing.T) {
+			failFast, noFailFast = false, false
+			mockRunner := &MockValidateRunner{}
+			validateRunner = mockRunner
+
+			cmd := NewRootCmd()
+			cmd.SetOut(new(bytes.Buffer))
+			cmd.SetErr(new(bytes.Buffer))
+			cmd.SetArgs(append([]string{"validate", "--entrypoint", "test.Func"}, tt.args...))
+			err := cmd.Execute()
+			if tt.wantErr != "" {
+				if err == nil || !contains(err.Error(), tt.wantErr) {
+					t.Errorf("Execute() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if len(mockRunner.Calls) != 1 || mockRunner.Calls[0].Opts.FailFast != tt.wantFailFast {
+				t.Errorf("calls = %+v, want FailFast %v", mockRunner.Calls, tt.wantFailFast)
+			}
+		})
+	}
+}
+
+func TestRunValidate_IgnoreDescriptionFlags(t *testing.T) {
+	originalRunner := validateRunner
+	defer func() { validateRunner = originalRunner }()
+	defer func() { ignoreShort, ignoreLong = false, false }()
+
+	tests := []struct {
+		args            []string
+		wantIgnoreShort bool
+		wantIgnoreLong  bool
+	}{
+		{args: nil},
+		{args: []string{"--ignore-short"}, wantIgnoreShort: true},
+		{args: []string{"--ignore-long"}, wantIgnoreLong: true},
+		{args: []string{"--ignore-short", "--ignore-long"}, wantIgnoreShort: true, wantIgnoreLong: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.args), func(t *testing.T) {
+			ignoreShort, ignoreLong = false, false
+			mockRunner := &MockValidateRunner{}
+			validateRunner = mockRunner
+
+			cmd := NewRootCmd()
+			cmd.SetOut(new(bytes.Buffer))
+			cmd.SetErr(new(bytes.Buffer))
+			cmd.SetArgs(append([]string{"validate", "--entrypoint", "test.Func"}, tt.args...))
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if len(mockRunner.Calls) != 1 || mockRunner.Calls[0].Opts.IgnoreShort != tt.wantIgnoreShort || mockRunner.Calls[0].Opts.IgnoreLong != tt.wantIgnoreLong {
+				t.Errorf("calls = %+v, want IgnoreShort %v and IgnoreLong %v", mockRunner.Calls, tt.wantIgnoreShort, tt.wantIgnoreLong)
+			}
+		})
+	}
+}
+
+func TestRunValidate_StaticFlag(t *testing.T) {
+	originalRunner := validateRunner
+	defer func() { validateRunner = originalRunner }()
+	defer func() { static = false }()
+
+	mockRunner := &MockValidateRunner{}
+	validateRunner = mockRunner
+
+	cmd := NewRootCmd()
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{"validate", "--entrypoint", "test.Func", "--static"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if len(mockRunner.Calls) != 1 || !mockRunner.Calls[0].Opts.Static {
+		t.Errorf("calls = %+v, want Static set", mockRunner.Calls)
+	}
+
+	// A contract regenerated statically would lose what static inspection
+	// doesn't find
+	err := NewDefaultValidateRunner().Run(new(cobra.Command), service.ValidateOptions{ProjectPath: t.TempDir(), Entrypoint: "test.Func", Static: true}, ValidateReportOptions{GenerateOnMismatch: true, MaxAutoUpdates: 1})
+	if err == nil || !contains(err.Error(), "--generate-on-mismatch cannot be used with --static") {
+		t.Errorf("Run() error = %v, want --generate-on-mismatch rejected", err)
+	}
+}
+
+func TestRunValidate_SummarizeFlags(t *testing.T) {
+	originalRunner := validateRunner
+	defer func() { validateRunner = originalRunner }()
+	defer func() { summarize, top = false, 0 }()
+
+	mockRunner := &MockValidateRunner{}
+	validateRunner = mockRunner
+
+	cmd := NewRootCmd()
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{"validate", "--entrypoint", "test.Func", "--summarize", "--top", "3"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if len(mockRunner.Calls) != 1 || !mockRunner.Calls[0].Report.Summarize || mockRunner.Calls[0].Report.Top != 3 {
+		t.Errorf("calls = %+v, want Summarize and Top 3", mockRunner.Calls)
+	}
+}
+
+func TestRunValidate_GenerateOnMismatchFlags(t *testing.T) {
+	originalRunner := validateRunner
+	defer func() { validateRunner = originalRunner }()
+	defer func() { generateOnMismatch = false }()
+
+	mockRunner := &MockValidateRunner{}
+	validateRunner = mockRunner
+
+	for _, args := range [][]string{
+		{"validate", "--entrypoint", "test.Func"},
+		{"validate", "--entrypoint", "test.Func", "--generate-on-mismatch", "--max-auto-updates", "3"},
+	} {
+		cmd := NewRootCmd()
+		cmd.SetOut(new(bytes.Buffer))
+		cmd.SetErr(new(bytes.Buffer))
+		cmd.SetArgs(args)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+	}
+	if len(mockRunner.Calls) != 2 {
+		t.Fatalf("calls = %+v, want two calls", mockRunner.Calls)
+	}
+	if call := mockRunner.Calls[0]; call.Report.GenerateOnMismatch || call.Report.MaxAutoUpdates != 1 {
+		t.Errorf("default call = %+v, want no GenerateOnMismatch and MaxAutoUpdates 1", call)
+	}
+	if call := mockRunner.Calls[1]; !call.Report.GenerateOnMismatch || call.Report.MaxAutoUpdates != 3 {
+		t.Errorf("call = %+v, want GenerateOnMismatch and MaxAutoUpdates 3", call)
+	}
+}
+
+func TestRunValidate_SemverCheckFlags(t *testing.T) {
+	originalRunner := validateRunner
+	defer func() { validateRunner = originalRunner }()
+	defer func() { semverCheck, outputBumpLevel = false, "" }()
+
+	mockRunner := &MockValidateRunner{}
+	validateRunner = mockRunner
+
+	cmd := NewRootCmd()
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{"validate", "--entrypoint", "test.Func", "--semver-check", "--output-bump-level", "/tmp/bump.txt"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if len(mockRunner.Calls) != 1 {
+		t.Fatalf("calls = %+v, want one call", mockRunner.Calls)
+	}
+	if call := mockRunner.Calls[0]; !call.Report.SemverCheck || call.Report.BumpLevelPath != "/tmp/bump.txt" {
+		t.Errorf("call = %+v, want SemverCheck and BumpLevelPath /tmp/bump.txt", call)
+	}
+}
+
+func TestRunValidate_SkipBuild(t *testing.T) {
+	originalRunner := validateRunner
+	defer func() { validateRunner = originalRunner }()
+	defer func() { skipBuild, cliSnapshot = false, "" }()
+
+	mockRunner := &MockValidateRunner{}
+	validateRunner = mockRunner
+
+	run := func(args ...string) error {
+		cmd := NewRootCmd()
+		cmd.SetOut(new(bytes.Buffer))
+		cmd.SetErr(new(bytes.Buffer))
+		cmd.SetArgs(append([]string{"validate", "--entrypoint", "test.Func"}, args...))
+		return cmd.Execute()
+	}
+
+	if err := run("--skip-build"); err == nil || !contains(err.Error(), "--skip-build requires --cli-snapshot") {
+		t.Errorf("Execute() error = %v, want --cli-snapshot required", err)
+	}
+	if len(mockRunner.Calls) != 0 {
+		t.Errorf("calls = %+v, want none", mockRunner.Calls)
+	}
+
+	if err := run("--skip-build", "--cli-snapshot", "cli.json"); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if len(mockRunner.Calls) != 1 || mockRunner.Calls[0].Opts.CLISnapshot != "cli.json" {
+		t.Errorf("calls = %+v, want one with CLISnapshot cli.json", mockRunner.Calls)
+	}
+}
+
+func TestRunValidate_AnnotationFlags(t *testing.T) {
+	originalRunner := validateRunner
+	defer func() { validateRunner = originalRunner }()
+	defer func() { annotateContract, clearAnnotations = false, false }()
+
+	mockRunner := &MockValidateRunner{}
+	validateRunner = mockRunner
+
+	run := func(args ...string) error {
+		cmd := NewRootCmd()
+		cmd.SetOut(new(bytes.Buffer))
+		cmd.SetErr(new(bytes.Buffer))
+		cmd.SetArgs(append([]string{"validate", "--entrypoint", "test.Func"}, args...))
+		return cmd.Execute()
+	}
+
+	if err := run("--annotate-contract", "--clear-annotations"); err == nil {
+		t.Error("Execute() succeeded with both --annotate-contract and --clear-annotations")
+	}
+	annotateContract, clearAnnotations = false, false
+
+	if err := run("--annotate-contract"); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if len(mockRunner.Calls) != 1 || !mockRunner.Calls[0].Report.AnnotateContract || mockRunner.Calls[0].Report.ClearAnnotations {
+		t.Errorf("calls = %+v, want one with AnnotateContract", mockRunner.Calls)
+	}
+}
+
+func TestRunValidate_ContractHTTPHeaders(t *testing.T) {
+	originalRunner := validateRunner
+	defer func() { validateRunner = originalRunner }()
+	originalFetcher := contract.DefaultFetcher
+	defer func() { contract.DefaultFetcher = originalFetcher }()
+	contract.DefaultFetcher = &contract.Fetcher{}
+
+	validateRunner = &MockValidateRunner{}
+
+	cmd := NewRootCmd()
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{"validate", "--entrypoint", "test.Func", "--contract", "https://example.com/cliguard.yaml",
+		"--contract-http-header", "Authorization: Bearer abc123", "--contract-http-header", "X-Team:cli", "--no-contract-cache"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if got := contract.DefaultFetcher.Header.Get("Authorization"); got != "Bearer abc123" {
+		t.Errorf("Authorization header = %q, want Bearer abc123", got)
+	}
+	if got := contract.DefaultFetcher.Header.Get("X-Team"); got != "cli" {
+		t.Errorf("X-Team header = %q, want cli", got)
+	}
+	if !contract.DefaultFetcher.NoCache {
+		t.Error("NoCache = false, want true with --no-contract-cache")
+	}
+
+	cmd = NewRootCmd()
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{"validate", "--entrypoint", "test.Func", "--contract-http-header", "Bearer abc123"})
+	if err := cmd.Execute(); err == nil || !contains(err.Error(), "invalid HTTP header 'Bearer abc123'") {
+		t.Errorf("Execute() error = %v, want invalid HTTP header", err)
+	}
+}
+
+func TestRunValidate_ContractFromEntrypointFlags(t *testing.T) {
+	originalRunner := validateRunner
+	defer func() { validateRunner = originalRunner }()
+
+	mockRunner := &MockValidateRunner{}
+	validateRunner = mockRunner
+
+	cmd := NewRootCmd()
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{"validate", "--entrypoint", "cmd.NewRootCmd", "--contract-from-entrypoint", "v1.NewRootCmd", "--flip"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if len(mockRunner.Calls) != 1 {
+		t.Fatalf("calls = %+v, want one call", mockRunner.Calls)
+	}
+	if call := mockRunner.Calls[0]; call.Opts.ContractEntrypoint != "v1.NewRootCmd" || !call.Opts.Flip {
+		t.Errorf("call = %+v, want ContractEntrypoint v1.NewRootCmd and Flip", call)
+	}
+}
+
+func TestRootCmd_DebugFlag(t *testing.T) {
+	originalRunner := validateRunner
+	defer func() { validateRunner = originalRunner }()
+	defer slog.SetLogLoggerLevel(slog.SetLogLoggerLevel(slog.LevelInfo))
+	defer func() { debug = false }()
+
+	validateRunner = &MockValidateRunner{}
+
+	cmd := NewRootCmd()
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{"--debug", "validate", "--entrypoint", "test.Func"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if !slog.Default().Enabled(context.Background(), slog.LevelDebug) {
+		t.Error("--debug should enable debug logging")
+	}
+}
+
+// fakeProgress records how often it is started and the errors it is
+// stopped with
+type fakeProgress struct {
+	starts int
+	stops  []error
+}
+
+func (f *fakeProgress) Start() { f.starts++ }
+
+func (f *fakeProgress) Stop(err error) { f.stops = append(f.stops, err) }
+
+// fakePRCommenter records the comments it is asked to post
+type fakePRCommenter struct {
+	bodies []string
+}
+
+func (f *fakePRCommenter) PostPRComment(body string) error {
+	f.bodies = append(f.bodies, body)
+	return nil
+}
+
+func TestDefaultValidateRunner(t *testing.T) {
+	// Create a test service with mocked dependencies
+	runner := NewDefaultValidateRunner()
+
+	// Mock the service dependencies
+	mockContractLoader := func(path string) (*contract.Contract, error) {
+		if path == "/test/contract.yaml" {
+			return &contract.Contract{
+				Use:   "myapp",
+				Short: "My app",
+			}, nil
+		}
+		return nil, errors.New("contract not found")
+	}
+
+	mockInspector := func(projectPath, entrypoint string) (*inspector.InspectedCLI, error) {
+		if projectPath == "/test/project" {
+			return &inspector.InspectedCLI{
+				Use:   "myapp",
+				Short: "My app",
+			}, nil
+		}
+		return nil, errors.New("project not found")
+	}
+
+	runner.service.FieldChecker = nil // the contract file is mocked
+	runner.service.ContractLoader = mockContractLoader
+	runner.service.Inspector = mockInspector
+
+	// Test successful validation
+	t.Run("success", func(t *testing.T) {
+		// Create a temp directory that actually exists
+		tmpDir := t.TempDir()
+		contractPath := filepath.Join(tmpDir, "contract.yaml")
+
+		// Update mocks to use the real paths
+		runner.service.ContractLoader = func(path string) (*contract.Contract, error) {
+			if path == contractPath {
+				return &contract.Contract{
+					Use:   "myapp",
+					Short: "My app",
+				}, nil
+			}
+			return nil, errors.New("contract not found")
+		}
+
+		runner.service.Inspector = func(projectPath, entrypoint string) (*inspector.InspectedCLI, error) {
+			if projectPath == tmpDir {
+				return &inspector.InspectedCLI{
+					Use:   "myapp",
+					Short: "My app",
+				}, nil
+			}
+			return nil, errors.New("project not found")
+		}
+		runner.service.InspectorWithTimeout = func(projectPath, entrypoint string, timeout time.Duration) (*inspector.InspectedCLI, error) {
+			return runner.service.Inspector(projectPath, entrypoint)
+		}
+
+		cmd := &cobra.Command{}
+		buf := new(bytes.Buffer)
+		cmd.SetOut(buf)
+
+		err := runner.Run(cmd, service.ValidateOptions{
+			ProjectPath:  tmpDir,
+			ContractPath: contractPath,
+			Entrypoint:   "test.Func",
+			Timeout:      30 * time.Second,
+		}, ValidateReportOptions{})
+		if err != nil {
+			t.Errorf("Run() error = %v, want nil", err)
+		}
+
+		output := buf.String()
+		if !contains(output, "Validation passed") {
+			t.Errorf("Expected success message in output, got: %q", output)
+		}
+	})
+
+	t.Run("validation failure", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		contractPath := filepath.Join(tmpDir, "contract.yaml")
+
+		runner := NewDefaultValidateRunner()
+		runner.service.FieldChecker = nil // the contract file is mocked
+		runner.service.ContractLoader = func(path string) (*contract.Contract, error) {
+			return &contract.Contract{
+				Use:   "expected",
+				Short: "Expected CLI",
+			}, nil
+		}
+		runner.service.InspectorWithTimeout = func(projectPath, entrypoint string, timeout time.Duration) (*inspector.InspectedCLI, error) {
+			return &inspector.InspectedCLI{
+				Use:   "actual",
+				Short: "Actual CLI",
+			}, nil
+		}
+
+		cmd := &cobra.Command{}
+		buf := new(bytes.Buffer)
+		cmd.SetOut(buf)
+
+		// Silence the report, which is printed to stdout
+		oldStdout := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+
+		err := runner.Run(cmd, service.ValidateOptions{
+			ProjectPath:  tmpDir,
+			ContractPath: contractPath,
+			Entrypoint:   "test.Func",
+			Timeout:      30 * time.Second,
+		}, ValidateReportOptions{})
+
+		w.Close()
+		os.Stdout = oldStdout
+		io.Copy(io.Discard, r)
+
+		if !errors.Is(err, cliguarderrors.ErrValidationFailed) {
+			t.Errorf("Run() error = %v, want %v", err, cliguarderrors.ErrValidationFailed)
+		}
+
+		output := buf.String()
+		if !contains(output, "Validation failed") {
+			t.Errorf("Expected failure message in output, got: %q", output)
+		}
+	})
+
+	t.Run("yaml report", func(t *testing.T) {
+		runner := NewDefaultValidateRunner()
+		runner.service.FieldChecker = nil // the contract file is mocked
+		runner.service.ContractLoader = func(path string) (*contract.Contract, error) {
+			return &contract.Contract{Use: "expected"}, nil
+		}
+		runner.service.InspectorWithTimeout = func(projectPath, entrypoint string, timeout time.Duration) (*inspector.InspectedCLI, error) {
+			return &inspector.InspectedCLI{Use: "actual"}, nil
+		}
+
+		cmd := &cobra.Command{}
+		buf := new(bytes.Buffer)
+		cmd.SetOut(buf)
+
+		err := runner.Run(cmd, service.ValidateOptions{
+			ProjectPath:  t.TempDir(),
+			ContractPath: "contract.yaml",
+			Entrypoint:   "test.Func",
+			Timeout:      30 * time.Second,
+		}, ValidateReportOptions{Output: "yaml"})
+		if !errors.Is(err, cliguarderrors.ErrValidationFailed) {
+			t.Errorf("Run() error = %v, want %v", err, cliguarderrors.ErrValidationFailed)
+		}
+
+		output := buf.String()
+		for _, want := range []string{"cliguard_version:", "valid: false", "error_count: 1"} {
+			if !contains(output, want) {
+				t.Errorf("Expected %q in output, got: %q", want, output)
+			}
+		}
+	})
+
+	t.Run("allow extra commands and flags", func(t *testing.T) {
+		runner := NewDefaultValidateRunner()
+		runner.service.FieldChecker = nil // the contract file is mocked
+		runner.service.ContractLoader = func(path string) (*contract.Contract, error) {
+			return &contract.Contract{Use: "app", Short: "App"}, nil
+		}
+		extraCommand := inspector.InspectedCommand{Use: "serve", Short: "Serve"}
+		extraFlag := inspector.InspectedFlag{Name: "verbose", Type: "bool", Usage: "Verbose output"}
+
+		tests := []struct {
+			name               string
+			commands           []inspector.InspectedCommand
+			flags              []inspector.InspectedFlag
+			allowExtraCommands bool
+			allowExtraFlags    bool
+			wantFailure        bool
+		}{
+			{name: "extra command", commands: []inspector.InspectedCommand{extraCommand}, wantFailure: true},
+			{name: "extra command allowed", commands: []inspector.InspectedCommand{extraCommand}, allowExtraCommands: true},
+			{name: "extra command with flags allowed", commands: []inspector.InspectedCommand{extraCommand}, allowExtraFlags: true, wantFailure: true},
+			{name: "extra flag", flags: []inspector.InspectedFlag{extraFlag}, wantFailure: true},
+			{name: "extra flag allowed", flags: []inspector.InspectedFlag{extraFlag}, allowExtraFlags: true},
+			{name: "extra flag with commands allowed", flags: []inspector.InspectedFlag{extraFlag}, allowExtraCommands: true, wantFailure: true},
+			{name: "both", commands: []inspector.InspectedCommand{extraCommand}, flags: []inspector.InspectedFlag{extraFlag}, allowExtraCommands: true, wantFailure: true},
+			{name: "both allowed", commands: []inspector.InspectedCommand{extraCommand}, flags: []inspector.InspectedFlag{extraFlag}, allowExtraCommands: true, allowExtraFlags: true},
+		}
+
+		for _, tt := range tests {
+			for _, format := range []string{"", "json"} {
+				t.Run(tt.name+"/"+format, func(t *testing.T) {
+					runner.service.InspectorWithTimeout = func(projectPath, entrypoint string, timeout time.Duration) (*inspector.InspectedCLI, error) {
+						return &inspector.InspectedCLI{Use: "app", Short: "App", Commands: tt.commands, Flags: tt.flags}, nil
+					}
+
+					cmd := &cobra.Command{}
+					buf := new(bytes.Buffer)
+					cmd.SetOut(buf)
+
+					// Silence the report, which is printed to stdout
+					oldStdout := os.Stdout
+					r, w, _ := os.Pipe()
+					os.Stdout = w
+
+					err := runner.Run(cmd, service.ValidateOptions{
+						ProjectPath:        t.TempDir(),
+						ContractPath:       "contract.yaml",
+						Entrypoint:         "test.Func",
+						Timeout:            30 * time.Second,
+						AllowExtraCommands: tt.allowExtraCommands,
+						AllowExtraFlags:    tt.allowExtraFlags,
+					}, ValidateReportOptions{Output: format})
+
+					w.Close()
+					os.Stdout = oldStdout
+					io.Copy(io.Discard, r)
+
+					if tt.wantFailure {
+						if !errors.Is(err, cliguarderrors.ErrValidationFailed) {
+							t.Errorf("Run() error = %v, want %v", err, cliguarderrors.ErrValidationFailed)
+						}
+						return
+					}
+					if err != nil {
+						t.Errorf("Run() error = %v, want nil", err)
+					}
+					// Allowed differences are still reported
+					if format == "json" && !contains(buf.String(), `"type": "unexpected"`) {
+						t.Errorf("Expected the allowed error in the report, got: %q", buf.String())
+					}
+					if format == "" && !contains(buf.String(), "--allow-extra-") {
+						t.Errorf("Expected the allowed differences to be noted, got: %q", buf.String())
+					}
+				})
+			}
+		}
+	})
+
+	t.Run("fail fast", func(t *testing.T) {
+		runner := NewDefaultValidateRunner()
+		runner.service.FieldChecker = nil // the contract file is mocked
+		runner.service.ContractLoader = func(path string) (*contract.Contract, error) {
+			return &contract.Contract{Use: "expected", Short: "Expected CLI"}, nil
+		}
+		runner.service.InspectorWithTimeout = func(projectPath, entrypoint string, timeout time.Duration) (*inspector.InspectedCLI, error) {
+			return &inspector.InspectedCLI{Use: "actual", Short: "Actual CLI"}, nil
+		}
+
+		cmd := &cobra.Command{}
+		buf := new(bytes.Buffer)
+		cmd.SetOut(buf)
+
+		err := runner.Run(cmd, service.ValidateOptions{
+			ProjectPath:  t.TempDir(),
+			ContractPath: "contract.yaml",
+			Entrypoint:   "test.Func",
+			Timeout:      30 * time.Second,
+			FailFast:     true,
+		}, ValidateReportOptions{Output: "json"})
+		if !errors.Is(err, cliguarderrors.ErrValidationFailed) {
+			t.Errorf("Run() error = %v, want %v", err, cliguarderrors.ErrValidationFailed)
+		}
+		output := buf.String()
+		for _, want := range []string{`"error_count": 1`, "Validation stopped after first error (use --no-fail-fast for full report)"} {
+			if !contains(output, want) {
+				t.Errorf("Expected %q in output, got: %q", want, output)
+			}
+		}
+
+		err = runner.Run(cmd, service.ValidateOptions{
+			ProjectPath:        t.TempDir(),
+			ContractPath:       "contract.yaml",
+			Entrypoint:         "test.Func",
+			Timeout:            30 * time.Second,
+			AllowExtraCommands: true,
+			FailFast:           true,
+		}, ValidateReportOptions{})
+		if err == nil || !contains(err.Error(), "--fail-fast cannot be used with --allow-extra-commands") {
+			t.Errorf("Run() error = %v, want --allow-extra-commands rejected", err)
+		}
+	})
+
+	t.Run("summarize and top", func(t *testing.T) {
+		runner := NewDefaultValidateRunner()
+		runner.service.FieldChecker = nil // the contract file is mocked
+		runner.service.ContractLoader = func(path string) (*contract.Contract, error) {
+			return &contract.Contract{
+				Use:   "app",
+				Short: "App",
+				Commands: []contract.Command{
+					{Use: "db", Short: "Database", Commands: []contract.Command{{Use: "migrate", Short: "Migrate"}}},
+				},
+			}, nil
+		}
+		runner.service.InspectorWithTimeout = func(projectPath, entrypoint string, timeout time.Duration) (*inspector.InspectedCLI, error) {
+			return &inspector.InspectedCLI{
+				Use:   "app",
+				Short: "Another app",
+				Commands: []inspector.InspectedCommand{
+					{Use: "db", Short: "Database", Flags: []inspector.InspectedFlag{{Name: "dsn", Type: "string"}}},
+					{Use: "serve", Short: "Serve"},
+				},
+			}, nil
+		}
+		run := func(format string, summarize bool, top int) (string, error) {
+			cmd := &cobra.Command{}
+			buf := new(bytes.Buffer)
+			cmd.SetOut(buf)
+			err := runner.Run(cmd, service.ValidateOptions{
+				ProjectPath:  t.TempDir(),
+				ContractPath: "contract.yaml",
+				Entrypoint:   "test.Func",
+				Timeout:      30 * time.Second,
+			}, ValidateReportOptions{Output: format, Summarize: summarize, Top: top})
+			return buf.String(), err
+		}
+
+		output, err := run("", true, 0)
+		if !errors.Is(err, cliguarderrors.ErrValidationFailed) {
+			t.Errorf("Run() error = %v, want %v", err, cliguarderrors.ErrValidationFailed)
+		}
+		for _, want := range []string{
+			"Missing: 1 command. Unexpected: 1 command, 1 flag. Mismatches: 1 command. Total: 4 errors.",
+			"Most critical errors:\n   • Mismatch in short description: root\n   • Unexpected command: serve\n",
+		} {
+			if !contains(output, want) {
+				t.Errorf("Expected %q in output, got: %q", want, output)
+			}
+		}
+
+		output, _ = run("json", true, 1)
+		for _, want := range []string{`"error_count": 4`, `"summary": {`, `"path": "root"`} {
+			if !contains(output, want) {
+				t.Errorf("Expected %q in output, got: %q", want, output)
+			}
+		}
+		if contains(output, `"path": "serve"`) {
+			t.Errorf("Expected only the most critical error, got: %q", output)
+		}
+
+		output, _ = run("json", false, 2)
+		if !contains(output, `"error_count": 4`) || !contains(output, `"total": 4`) || contains(output, `"path": "db migrate"`) {
+			t.Errorf("Expected the first 2 of 4 errors, got: %q", output)
+		}
+
+		if _, err := run("", false, -1); err == nil || !contains(err.Error(), "--top must be 0 or more") {
+			t.Errorf("Run() error = %v, want negative --top rejected", err)
+		}
+	})
+
+	t.Run("generate on mismatch", func(t *testing.T) {
+		app := &contract.Contract{Use: "app", Short: "App", Commands: []contract.Command{{Use: "db", Short: "Database"}}}
+		withServe := &contract.Contract{Use: "app", Short: "App", Commands: []contract.Command{{Use: "db", Short: "Database"}, {Use: "serve", Short: "Serve"}}}
+
+		run := func(current *contract.Contract, inspected *inspector.InspectedCLI, regenerated *contract.Contract, maxAutoUpdates int) (string, int, error) {
+			runner := NewDefaultValidateRunner()
+			runner.service.FieldChecker = nil // the contract file is mocked
+			runner.service.ContractLoader = func(path string) (*contract.Contract, error) {
+				return current, nil
+			}
+			runner.service.InspectorWithTimeout = func(projectPath, entrypoint string, timeout time.Duration) (*inspector.InspectedCLI, error) {
+				return inspected, nil
+			}
+			generated := 0
+			runner.GenerateContract = func(opts service.GenerateOptions, outputPath string) (bool, error) {
+				generated++
+				if opts.Entrypoint != "test.Func" || !contains(outputPath, "contract.yaml") {
+					t.Errorf("GenerateContract(%+v, %s)", opts, outputPath)
+				}
+				current = regenerated
+				return true, nil
+			}
+
+			cmd := &cobra.Command{}
+			buf := new(bytes.Buffer)
+			cmd.SetOut(buf)
+			err := runner.Run(cmd, service.ValidateOptions{
+				ProjectPath:  t.TempDir(),
+				ContractPath: "contract.yaml",
+				Entrypoint:   "test.Func",
+				Timeout:      30 * time.Second,
+			}, ValidateReportOptions{Output: "json", GenerateOnMismatch: true, MaxAutoUpdates: maxAutoUpdates})
+			return buf.String(), generated, err
+		}
+		cli := &inspector.InspectedCLI{Use: "app", Short: "App", Commands: []inspector.InspectedCommand{{Use: "db", Short: "Database"}, {Use: "serve", Short: "Serve"}}}
+
+		output, generated, err := run(app, cli, withServe, 1)
+		if err != nil || generated != 1 || !contains(output, "📝 Updated") {
+			t.Errorf("new command: Run() error = %v, generated %d times, output: %q", err, generated, output)
+		}
+
+		// The update doesn't fix the contract, so it is tried only once
+		output, generated, err = run(app, cli, app, 1)
+		if !errors.Is(err, cliguarderrors.ErrValidationFailed) || generated != 1 {
+			t.Errorf("unfixed contract: Run() error = %v, generated %d times, output: %q", err, generated, output)
+		}
+		_, generated, _ = run(app, cli, app, 3)
+		if generated != 3 {
+			t.Errorf("--max-auto-updates 3: generated %d times, want 3", generated)
+		}
+
+		// Removed commands are a breaking change, and the contract is kept
+		removed := &inspector.InspectedCLI{Use: "app", Short: "App", Commands: []inspector.InspectedCommand{{Use: "serve", Short: "Serve"}}}
+		output, generated, err = run(app, removed, withServe, 1)
+		if !errors.Is(err, cliguarderrors.ErrBreakingChanges) || generated != 0 || !contains(output, "which is a breaking change") {
+			t.Errorf("removed command: Run() error = %v, generated %d times, output: %q", err, generated, output)
+		}
+
+		// Other differences are left for the user to fix
+		changed := &inspector.InspectedCLI{Use: "app", Short: "Changed", Commands: []inspector.InspectedCommand{{Use: "db", Short: "Database"}, {Use: "serve", Short: "Serve"}}}
+		output, generated, err = run(app, changed, withServe, 1)
+		if !errors.Is(err, cliguarderrors.ErrValidationFailed) || generated != 0 || !contains(output, "differs from the CLI by more than new commands and flags") {
+			t.Errorf("changed description: Run() error = %v, generated %d times, output: %q", err, generated, output)
+		}
+
+		if _, _, err := run(app, cli, withServe, 0); err == nil || !contains(err.Error(), "--max-auto-updates must be 1 or more") {
+			t.Errorf("Run() error = %v, want --max-auto-updates 0 rejected", err)
+		}
+	})
+
+	t.Run("semver check", func(t *testing.T) {
+		app := &contract.Contract{Use: "app", Short: "App", Commands: []contract.Command{{Use: "db", Short: "Database"}}}
+
+		run := func(inspected *inspector.InspectedCLI, format, bumpLevelPath string) (string, error) {
+			runner := NewDefaultValidateRunner()
+			runner.service.FieldChecker = nil // the contract file is mocked
+			runner.service.ContractLoader = func(path string) (*contract.Contract, error) {
+				return app, nil
+			}
+			runner.service.InspectorWithTimeout = func(projectPath, entrypoint string, timeout time.Duration) (*inspector.InspectedCLI, error) {
+				return inspected, nil
+			}
+
+			cmd := &cobra.Command{}
+			buf := new(bytes.Buffer)
+			cmd.SetOut(buf)
+			err := runner.Run(cmd, service.ValidateOptions{
+				ProjectPath:  t.TempDir(),
+				ContractPath: "contract.yaml",
+				Entrypoint:   "test.Func",
+				Timeout:      30 * time.Second,
+			}, ValidateReportOptions{Output: format, SemverCheck: true, BumpLevelPath: bumpLevelPath})
+			return buf.String(), err
+		}
+
+		// New commands and flags don't fail validation, but need a minor bump
+		added := &inspector.InspectedCLI{Use: "app", Short: "App", Commands: []inspector.InspectedCommand{
+			{Use: "db", Short: "Database", Flags: []inspector.InspectedFlag{{Name: "dsn", Type: "string"}}},
+			{Use: "serve", Short: "Serve"},
+		}}
+		bumpFile := filepath.Join(t.TempDir(), "bump.txt")
+		output, err := run(added, "", bumpFile)
+		if err != nil || !contains(output, "Suggested version bump: minor (2 additions, 0 breaking changes)") {
+			t.Errorf("additions: Run() error = %v, output: %q", err, output)
+		}
+		if data, err := os.ReadFile(bumpFile); err != nil || string(data) != "minor\n" {
+			t.Errorf("bump file = %q, %v, want minor", data, err)
+		}
+
+		removed := &inspector.InspectedCLI{Use: "app", Short: "App", Commands: []inspector.InspectedCommand{{Use: "serve", Short: "Serve"}}}
+		output, err = run(removed, "json", "")
+		if err != nil || !contains(output, "Suggested version bump: major (1 addition, 1 breaking change)") {
+			t.Errorf("removal: Run() error = %v, output: %q", err, output)
+		}
+
+		same := &inspector.InspectedCLI{Use: "app", Short: "App", Commands: []inspector.InspectedCommand{{Use: "db", Short: "Database"}}}
+		output, err = run(same, "", "")
+		if err != nil || !contains(output, "Suggested version bump: none (0 additions, 0 breaking changes)") {
+			t.Errorf("no changes: Run() error = %v, output: %q", err, output)
+		}
+	})
+
+	t.Run("annotate contract", func(t *testing.T) {
+		dir := t.TempDir()
+		contractFile := filepath.Join(dir, "cliguard.yaml")
+		original := "use: app\nshort: App\nflags:\n  - name: verbose\n    usage: Verbose output\n    type: bool\n"
+		if err := os.WriteFile(contractFile, []byte(original), 0644); err != nil {
+			t.Fatal(err)
+		}
+		runner := NewDefaultValidateRunner()
+		runner.service.InspectorWithTimeout = func(projectPath, entrypoint string, timeout time.Duration) (*inspector.InspectedCLI, error) {
+			return &inspector.InspectedCLI{Use: "app", Short: "App"}, nil
+		}
+		run := func(annotate, clear bool) (string, error) {
+			cmd := &cobra.Command{}
+			buf := new(bytes.Buffer)
+			cmd.SetOut(buf)
+
+			// Silence the report, which is printed to stdout
+			oldStdout := os.Stdout
+			r, w, _ := os.Pipe()
+			os.Stdout = w
+			err := runner.Run(cmd, service.ValidateOptions{
+				ProjectPath:  dir,
+				ContractPath: contractFile,
+				Entrypoint:   "test.Func",
+				Timeout:      30 * time.Second,
+			}, ValidateReportOptions{AnnotateContract: annotate, ClearAnnotations: clear})
+			w.Close()
+			os.Stdout = oldStdout
+			io.Copy(io.Discard, r)
+			return buf.String(), err
+		}
+
+		output, err := run(true, false)
+		if !errors.Is(err, cliguarderrors.ErrValidationFailed) || !contains(output, "Annotated "+contractFile+" with 1 validation errors") {
+			t.Fatalf("Run() error = %v, output: %q", err, output)
+		}
+		data, _ := os.ReadFile(contractFile)
+		want := "use: app\nshort: App\nflags:\n  # ❌ MISSING in implementation: --verbose flag\n  - name: verbose\n    usage: Verbose output\n    type: bool\n"
+		if string(data) != want {
+			t.Errorf("annotated contract =\n%s\nwant:\n%s", data, want)
+		}
+
+		output, err = run(false, true)
+		if err != nil || !contains(output, "Removed validation annotations from "+contractFile) {
+			t.Fatalf("Run() error = %v, output: %q", err, output)
+		}
+		if data, _ := os.ReadFile(contractFile); string(data) != original {
+			t.Errorf("cleared contract =\n%s\nwant the original:\n%s", data, original)
+		}
+	})
+
+	t.Run("annotate contract from entrypoint", func(t *testing.T) {
+		cmd := &cobra.Command{}
+		cmd.SetOut(new(bytes.Buffer))
+		err := NewDefaultValidateRunner().Run(cmd, service.ValidateOptions{
+			ProjectPath:        t.TempDir(),
+			Entrypoint:         "test.Func",
+			Timeout:            30 * time.Second,
+			ContractEntrypoint: "test.Old",
+		}, ValidateReportOptions{AnnotateContract: true})
+		if err == nil || !contains(err.Error(), "--annotate-contract cannot be used with --contract-from-entrypoint") {
+			t.Errorf("Run() error = %v", err)
+		}
+	})
+
+	t.Run("output bump level requires semver check", func(t *testing.T) {
+		cmd := &cobra.Command{}
+		cmd.SetOut(new(bytes.Buffer))
+		err := NewDefaultValidateRunner().Run(cmd, service.ValidateOptions{
+			ProjectPath:  t.TempDir(),
+			ContractPath: "contract.yaml",
+			Entrypoint:   "test.Func",
+			Timeout:      30 * time.Second,
+		}, ValidateReportOptions{BumpLevelPath: "bump.txt"})
+		if err == nil || !contains(err.Error(), "--output-bump-level requires --semver-check") {
+			t.Errorf("Run() error = %v", err)
+		}
+	})
+
+	t.Run("unknown contract fields", func(t *testing.T) {
+		dir := t.TempDir()
+		contractFile := filepath.Join(dir, "cliguard.yaml")
+		if err := os.WriteFile(contractFile, []byte("use: app\nshort: App\nusage_example: app run\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		runner := NewDefaultValidateRunner()
+		runner.service.InspectorWithTimeout = func(projectPath, entrypoint string, timeout time.Duration) (*inspector.InspectedCLI, error) {
+			return &inspector.InspectedCLI{Use: "app", Short: "App"}, nil
+		}
+
+		cmd := &cobra.Command{}
+		buf := new(bytes.Buffer)
+		cmd.SetOut(buf)
+
+		if err := runner.Run(cmd, service.ValidateOptions{
+			ProjectPath:  dir,
+			ContractPath: contractFile,
+			Entrypoint:   "test.Func",
+			Timeout:      30 * time.Second,
+		}, ValidateReportOptions{}); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		output := buf.String()
+		for _, want := range []string{
+			"⚠️  Contract has unrecognized field 'usage_example' at root (line 3)",
+			"--strict-contract",
+			"Validation passed",
+		} {
+			if !contains(output, want) {
+				t.Errorf("Expected %q in output, got: %q", want, output)
+			}
+		}
+
+		err := runner.Run(cmd, service.ValidateOptions{
+			ProjectPath:    dir,
+			ContractPath:   contractFile,
+			Entrypoint:     "test.Func",
+			Timeout:        30 * time.Second,
+			StrictContract: true,
+		}, ValidateReportOptions{})
+		if err == nil || !contains(err.Error(), "field usage_example not found") {
+			t.Errorf("Run(strict) error = %v, want unknown field error", err)
+		}
+	})
+
+	t.Run("build progress", func(t *testing.T) {
+		for format, wantProgress := range map[string]bool{"": true, "json": false} {
+			progress := &fakeProgress{}
+			runner := NewDefaultValidateRunner()
+			runner.NewProgress = func(io.Writer) output.Progress { return progress }
+			runner.service.FieldChecker = nil // the contract file is mocked
+			runner.service.ContractLoader = func(path string) (*contract.Contract, error) {
+				return &contract.Contract{Use: "myapp"}, nil
+			}
+			runner.service.InspectorWithTimeout = func(projectPath, entrypoint string, timeout time.Duration) (*inspector.InspectedCLI, error) {
+				return &inspector.InspectedCLI{Use: "myapp"}, nil
+			}
+
+			cmd := &cobra.Command{}
+			cmd.SetOut(new(bytes.Buffer))
+			if err := runner.Run(cmd, service.ValidateOptions{
+				ProjectPath:  t.TempDir(),
+				ContractPath: "contract.yaml",
+				Entrypoint:   "test.Func",
+				Timeout:      30 * time.Second,
+			}, ValidateReportOptions{Output: format}); err != nil {
+				t.Fatalf("Run(%q) error = %v", format, err)
+			}
+
+			if wantProgress && (progress.starts != 1 || len(progress.stops) != 1 || progress.stops[0] != nil) {
+				t.Errorf("Run(%q) progress = %+v, want started once and stopped without error", format, progress)
+			}
+			if !wantProgress && (progress.starts != 0 || len(progress.stops) != 0) {
+				t.Errorf("Run(%q) progress = %+v, want no progress for machine-readable output", format, progress)
+			}
+		}
+	})
+
+	t.Run("markdown report posted as a pull request comment", func(t *testing.T) {
+		commenter := &fakePRCommenter{}
+		runner := NewDefaultValidateRunner()
+		runner.service.FieldChecker = nil // the contract file is mocked
+		runner.service.ContractLoader = func(path string) (*contract.Contract, error) {
+			return &contract.Contract{Use: "expected"}, nil
+		}
+		runner.service.InspectorWithTimeout = func(projectPath, entrypoint string, timeout time.Duration) (*inspector.InspectedCLI, error) {
+			return &inspector.InspectedCLI{Use: "actual"}, nil
+		}
+		runner.NewPRCommenter = func() (PRCommenter, error) {
+			return commenter, nil
+		}
+
+		cmd := &cobra.Command{}
+		buf := new(bytes.Buffer)
+		cmd.SetOut(buf)
+		cmd.SetErr(new(bytes.Buffer))
+
+		err := runner.Run(cmd, service.ValidateOptions{
+			ProjectPath:  t.TempDir(),
+			ContractPath: "contract.yaml",
+			Entrypoint:   "test.Func",
+			Timeout:      30 * time.Second,
+		}, ValidateReportOptions{Output: "markdown", GitHubComment: true})
+		if !errors.Is(err, cliguarderrors.ErrValidationFailed) {
+			t.Errorf("Run() error = %v, want %v", err, cliguarderrors.ErrValidationFailed)
+		}
+		if !contains(buf.String(), "## ❌ cliguard: validation failed with 1 error(s)") {
+			t.Errorf("Expected a markdown report on stdout, got: %q", buf.String())
+		}
+		if len(commenter.bodies) != 1 || !contains(buf.String(), commenter.bodies[0]) {
+			t.Errorf("posted comments = %q, want the markdown report", commenter.bodies)
+		}
+	})
+
+	t.Run("warn-only reports errors without failing", func(t *testing.T) {
+		runner := NewDefaultValidateRunner()
+		runner.service.FieldChecker = nil // the contract file is mocked
+		runner.service.ContractLoader = func(path string) (*contract.Contract, error) {
+			return &contract.Contract{Use: "expected", Short: "Expected"}, nil
+		}
+		runner.service.InspectorWithTimeout = func(projectPath, entrypoint string, timeout time.Duration) (*inspector.InspectedCLI, error) {
+			return &inspector.InspectedCLI{Use: "actual"}, nil
+		}
+
+		cmd := &cobra.Command{}
+		buf := new(bytes.Buffer)
+		cmd.SetOut(buf)
+		cmd.SetErr(new(bytes.Buffer))
+
+		err := runner.Run(cmd, service.ValidateOptions{
+			ProjectPath:  t.TempDir(),
+			ContractPath: "contract.yaml",
+			Entrypoint:   "test.Func",
+			Timeout:      30 * time.Second,
+			WarnOnly:     true,
+		}, ValidateReportOptions{})
+		if err != nil {
+			t.Errorf("Run() error = %v, want nil with --warn-only", err)
+		}
+		if !contains(buf.String(), "--warn-only reports them as warnings") || !contains(buf.String(), "2 warnings found") {
+			t.Errorf("output = %q, want the errors counted as warnings", buf.String())
+		}
+		if contains(buf.String(), "Validation failed") {
+			t.Errorf("output = %q, want no failure", buf.String())
+		}
+
+		// Machine-readable reports pass too
+		err = runner.Run(cmd, service.ValidateOptions{
+			ProjectPath:  t.TempDir(),
+			ContractPath: "contract.yaml",
+			Entrypoint:   "test.Func",
+			Timeout:      30 * time.Second,
+			WarnOnly:     true,
+		}, ValidateReportOptions{Output: "json"})
+		if err != nil {
+			t.Errorf("Run(json) error = %v, want nil with --warn-only", err)
+		}
+	})
+
+	t.Run("sarif report", func(t *testing.T) {
+		dir := t.TempDir()
+		contractFile := filepath.Join(dir, "cliguard.yaml")
+		if err := os.WriteFile(contractFile, []byte("use: app\nflags:\n  - name: verbose\n    type: bool\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		sarifFile := filepath.Join(dir, "results.sarif")
+
+		runner := NewDefaultValidateRunner()
+		runner.service.InspectorWithTimeout = func(projectPath, entrypoint string, timeout time.Duration) (*inspector.InspectedCLI, error) {
+			return &inspector.InspectedCLI{Use: "app"}, nil
+		}
+
+		cmd := &cobra.Command{}
+		buf := new(bytes.Buffer)
+		cmd.SetOut(buf)
+
+		err := runner.Run(cmd, service.ValidateOptions{
+			ProjectPath:  dir,
+			ContractPath: contractFile,
+			Entrypoint:   "test.Func",
+			Timeout:      30 * time.Second,
+		}, ValidateReportOptions{SARIFPath: sarifFile})
+		if !errors.Is(err, cliguarderrors.ErrValidationFailed) {
+			t.Errorf("Run() error = %v, want %v", err, cliguarderrors.ErrValidationFailed)
+		}
+		if !contains(buf.String(), "SARIF report written to "+sarifFile) {
+			t.Errorf("output = %q, want a SARIF confirmation", buf.String())
+		}
+
+		data, err := os.ReadFile(sarifFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range []string{`"version": "2.1.0"`, `"ruleId": "missing"`, `"startLine": 3`} {
+			if !contains(string(data), want) {
+				t.Errorf("SARIF report = %s, want to contain %q", data, want)
+			}
+		}
+	})
+
+	t.Run("sarif output", func(t *testing.T) {
+		dir := t.TempDir()
+		contractFile := filepath.Join(dir, "cliguard.yaml")
+		if err := os.WriteFile(contractFile, []byte("use: app\nflags:\n  - name: verbose\n    type: bool\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		runner := NewDefaultValidateRunner()
+		runner.service.InspectorWithTimeout = func(projectPath, entrypoint string, timeout time.Duration) (*inspector.InspectedCLI, error) {
+			return &inspector.InspectedCLI{Use: "app"}, nil
+		}
+
+		cmd := &cobra.Command{}
+		buf := new(bytes.Buffer)
+		cmd.SetOut(buf)
+
+		err := runner.Run(cmd, service.ValidateOptions{
+			ProjectPath:  dir,
+			ContractPath: contractFile,
+			Entrypoint:   "test.Func",
+			Timeout:      30 * time.Second,
+		}, ValidateReportOptions{Output: "sarif"})
+		if !errors.Is(err, cliguarderrors.ErrValidationFailed) {
+			t.Errorf("Run() error = %v, want %v", err, cliguarderrors.ErrValidationFailed)
+		}
+		// Progress messages go to the same buffer, before the report
+		report := buf.String()
+		report = report[strings.Index(report, "{"):]
+		var log output.SARIFLog
+		if err := json.Unmarshal([]byte(report), &log); err != nil {
+			t.Fatalf("report is not a SARIF log: %v\n%s", err, report)
+		}
+		if len(log.Runs) != 1 || len(log.Runs[0].Results) != 1 || log.Runs[0].Results[0].RuleID != "missing" {
+			t.Errorf("SARIF log = %s, want the missing --verbose", report)
+		}
+
+		err = runner.Run(cmd, service.ValidateOptions{
+			ProjectPath:        dir,
+			ContractPath:       contractFile,
+			Entrypoint:         "test.Func",
+			Timeout:            30 * time.Second,
+			ContractEntrypoint: "github.com/org/repo/v1.NewRootCmd",
+		}, ValidateReportOptions{Output: "sarif"})
+		if err == nil || !contains(err.Error(), "--output sarif cannot be used with --contract-from-entrypoint") {
+			t.Errorf("Run() error = %v, want --contract-from-entrypoint rejected", err)
+		}
+	})
+
+	t.Run("junit output file", func(t *testing.T) {
+		dir := t.TempDir()
+		contractFile := filepath.Join(dir, "cliguard.yaml")
+		if err := os.WriteFile(contractFile, []byte("use: app\nflags:\n  - name: verbose\n    type: bool\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		reportFile := filepath.Join(dir, "junit.xml")
+
+		runner := NewDefaultValidateRunner()
+		runner.service.InspectorWithTimeout = func(projectPath, entrypoint string, timeout time.Duration) (*inspector.InspectedCLI, error) {
+			return &inspector.InspectedCLI{Use: "app"}, nil
+		}
+
+		cmd := &cobra.Command{}
+		buf := new(bytes.Buffer)
+		cmd.SetOut(buf)
+
+		err := runner.Run(cmd, service.ValidateOptions{
+			ProjectPath:  dir,
+			ContractPath: contractFile,
+			Entrypoint:   "test.Func",
+			Timeout:      30 * time.Second,
+		}, ValidateReportOptions{Output: "junit", OutputFile: reportFile})
+		if !errors.Is(err, cliguarderrors.ErrValidationFailed) {
+			t.Errorf("Run() error = %v, want %v", err, cliguarderrors.ErrValidationFailed)
+		}
+		if !contains(buf.String(), "Report written to "+reportFile) || contains(buf.String(), "<testsuites") {
+			t.Errorf("output = %q, want only a confirmation", buf.String())
+		}
+		data, err := os.ReadFile(reportFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range []string{`<testsuite name="app" tests="1" failures="1">`, `<testcase name="--verbose" classname="missing">`, `<failure message="Missing flag: --verbose" type="missing">`} {
+			if !contains(string(data), want) {
+				t.Errorf("JUnit report = %s, want to contain %q", data, want)
+			}
+		}
+
+		err = runner.Run(cmd, service.ValidateOptions{
+			ProjectPath:  dir,
+			ContractPath: contractFile,
+			Entrypoint:   "test.Func",
+			Timeout:      30 * time.Second,
+		}, ValidateReportOptions{OutputFile: reportFile})
+		if err == nil || !contains(err.Error(), "--output-file requires") {
+			t.Errorf("Run() error = %v, want --output-file rejected with text output", err)
+		}
+	})
+
+	t.Run("github comment without environment", func(t *testing.T) {
+		runner := NewDefaultValidateRunner()
+		runner.NewPRCommenter = func() (PRCommenter, error) {
+			return nil, errors.New("posting a GitHub comment requires GITHUB_TOKEN to be set")
+		}
+		runner.service.FieldChecker = nil // the contract file is mocked
+		runner.service.ContractLoader = func(path string) (*contract.Contract, error) {
+			t.Error("validation should not run when the GitHub environment is missing")
+			return nil, errors.New("unexpected")
+		}
+
+		cmd := &cobra.Command{}
+		cmd.SetOut(new(bytes.Buffer))
+		cmd.SetErr(new(bytes.Buffer))
+
+		err := runner.Run(cmd, service.ValidateOptions{
+			ProjectPath:  t.TempDir(),
+			ContractPath: "contract.yaml",
+			Entrypoint:   "test.Func",
+			Timeout:      30 * time.Second,
+		}, ValidateReportOptions{GitHubComment: true})
+		if err == nil || !contains(err.Error(), "GITHUB_TOKEN") {
+			t.Errorf("Run() error = %v, want missing GITHUB_TOKEN", err)
+		}
+	})
+
+	t.Run("invalid output format", func(t *testing.T) {
+		cmd := &cobra.Command{}
+		cmd.SetOut(new(bytes.Buffer))
+
+		err := runner.Run(cmd, service.ValidateOptions{
+			ProjectPath:  t.TempDir(),
+			ContractPath: "contract.yaml",
+			Entrypoint:   "test.Func",
+			Timeout:      30 * time.Second,
+		}, ValidateReportOptions{Output: "xml"})
+		if err == nil || !contains(err.Error(), "invalid output format") {
+			t.Errorf("Run() error = %v, want invalid output format", err)
+		}
+	})
+
+	t.Run("flip without contract entrypoint", func(t *testing.T) {
+		cmd := &cobra.Command{}
+		cmd.SetOut(new(bytes.Buffer))
+
+		err := runner.Run(cmd, service.ValidateOptions{
+			ProjectPath: t.TempDir(),
+			Entrypoint:  "test.Func",
+			Timeout:     30 * time.Second,
+			Flip:        true,
+		}, ValidateReportOptions{})
+		if err == nil || !contains(err.Error(), "--flip requires --contract-from-entrypoint") {
+			t.Errorf("Run() error = %v, want --flip to require --contract-from-entrypoint", err)
+		}
+	})
+
+	t.Run("sarif with contract entrypoint", func(t *testing.T) {
+		cmd := &cobra.Command{}
+		cmd.SetOut(new(bytes.Buffer))
+
+		err := runner.Run(cmd, service.ValidateOptions{
+			ProjectPath:        t.TempDir(),
+			Entrypoint:         "test.Func",
+			Timeout:            30 * time.Second,
+			ContractEntrypoint: "v1.Func",
+		}, ValidateReportOptions{SARIFPath: "out.sarif"})
+		if err == nil || !contains(err.Error(), "--emit-sarif cannot be used with --contract-from-entrypoint") {
+			t.Errorf("Run() error = %v, want --emit-sarif to be rejected", err)
+		}
+	})
+
+	// Test error cases
+	t.Run("project not found", func(t *testing.T) {
+		cmd := &cobra.Command{}
+		buf := new(bytes.Buffer)
+		cmd.SetOut(buf)
+
+		err := runner.Run(cmd, service.ValidateOptions{
+			ProjectPath:  "/nonexistent",
+			ContractPath: "/test/contract.yaml",
+			Entrypoint:   "test.Func",
+			Timeout:      30 * time.Second,
+		}, ValidateReportOptions{})
+		if err == nil {
+			t.Error("Expected error for nonexistent project")
+		}
+		if !contains(err.Error(), "Project path does not exist") {
+			t.Errorf("Error = %q, want to contain 'Project path does not exist'", err.Error())
+		}
+	})
+
+	t.Run("contract not found", func(t *testing.T) {
+		// Create a temp directory for the project
+		tmpDir := t.TempDir()
+
+		cmd := &cobra.Command{}
+		buf := new(bytes.Buffer)
+		cmd.SetOut(buf)
+
+		err := runner.Run(cmd, service.ValidateOptions{
+			ProjectPath:  tmpDir,
+			ContractPath: "/nonexistent/contract.yaml",
+			Entrypoint:   "test.Func",
+			Timeout:      30 * time.Second,
+		}, ValidateReportOptions{})
+		if err == nil {
+			t.Error("Expected error for nonexistent contract")
+		}
+		if !contains(err.Error(), "failed to load contract") {
+			t.Errorf("Error = %q, want to contain 'failed to load contract'", err.Error())
+		}
+	})
+}
+
+func TestValidateServiceIntegration(t *testing.T) {
+	// Test the actual ValidateService
+	svc := service.NewValidateService()
+
+	// Test with nonexistent project path
+	t.Run("nonexistent project", func(t *testing.T) {
+		opts := service.ValidateOptions{
+			ProjectPath: "/nonexistent/path",
+		}
+
+		_, err := svc.Validate(opts)
+		if err == nil {
+			t.Error("Expected error for nonexistent project")
+		}
+		if !contains(err.Error(), "Project path does not exist") {
+			t.Errorf("Error = %q, want to contain 'Project path does not exist'", err.Error())
+		}
+	})
+
+	// Test with existing but invalid project
+	t.Run("invalid project", func(t *testing.T) {
+		tmpDir := t.TempDir()
+
+		// Create a dummy contract file
+		contractPath := filepath.Join(tmpDir, "cliguard.yaml")
+		contractContent := `use: test
+short: Test CLI
+`
+		os.WriteFile(contractPath, []byte(contractContent), 0644)
+
+		opts := service.ValidateOptions{
+			ProjectPath:  tmpDir,
+			ContractPath: contractPath,
+			Entrypoint:   "test.Func",
+		}
+
+		_, err := svc.Validate(opts)
+		if err == nil {
+			t.Error("Expected error for invalid project")
+		}
+		// The error will be from the inspector trying to run go commands
+		if !contains(err.Error(), "Failed to inspect project") {
+			t.Errorf("Error = %q, want to contain 'Failed to inspect project'", err.Error())
+		}
+	})
+}
+
+func TestCommandFlags(t *testing.T) {
+	cmd := NewRootCmd()
+	validateCmd, _, _ := cmd.Find([]string{"validate"})
+
+	// Test flag defaults
+	t.Run("flag defaults", func(t *testing.T) {
+		// Reset flags
+		projectPath = ""
+		contractPath = ""
+		entrypoint = ""
+
+		if projectPath != "" {
+			t.Errorf("projectPath default = %q, want empty", projectPath)
+		}
+		if contractPath != "" {
+			t.Errorf("contractPath default = %q, want empty", contractPath)
+		}
+		if entrypoint != "" {
+			t.Errorf("entrypoint default = %q, want empty", entrypoint)
+		}
+	})
+
+	// Test flag parsing
+	t.Run("flag parsing", func(t *testing.T) {
+		// Parse flags
+		validateCmd.ParseFlags([]string{
+			"--project-path", "/test/path",
+			"--contract", "/test/contract.yaml",
+			"--entrypoint", "test.NewCmd",
+		})
+
+		// Check values were set
+		if flag := validateCmd.Flag("project-path"); flag.Value.String() != "/test/path" {
+			t.Errorf("project-path = %q, want %q", flag.Value.String(), "/test/path")
+		}
+		if flag := validateCmd.Flag("contract"); flag.Value.String() != "/test/contract.yaml" {
+			t.Errorf("contract = %q, want %q", flag.Value.String(), "/test/contract.yaml")
+		}
+		if flag := validateCmd.Flag("entrypoint"); flag.Value.String() != "test.NewCmd" {
+			t.Errorf("entrypoint = %q, want %q", flag.Value.String(), "test.NewCmd")
+		}
+	})
+}
+
+func TestValidationErrors(t *testing.T) {
+	// Save original runner
+	originalRunner := validateRunner
+	defer func() { validateRunner = originalRunner }()
+
+	// Create a custom service that simulates validation failure
+	runner := NewDefaultValidateRunner()
+	runner.service.FieldChecker = nil // the contract file is mocked
+	runner.service.ContractLoader = func(path string) (*contract.Contract, error) {
+		return &contract.Contract{
+			Use:   "expected",
+			Short: "Expected CLI",
+		}, nil
+	}
+	runner.service.Inspector = func(projectPath, entrypoint string) (*inspector.InspectedCLI, error) {
+		return &inspector.InspectedCLI{
+			Use:   "actual",
+			Short: "Actual CLI",
+		}, nil
+	}
+
+	validateRunner = runner
+
+	// Run command - runValidate will exit(1) on the validation failure,
+	// so we can't test the full flow here. Runner-level behaviour is
+	// covered by TestDefaultValidateRunner.
+	cmd := NewRootCmd()
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+
+	// Create temp dir
+	tmpDir := t.TempDir()
+	contractPath := filepath.Join(tmpDir, "contract.yaml")
+	os.WriteFile(contractPath, []byte("use: test\nshort: Test"), 0644)
+
+	// The actual test would need to handle os.Exit
+	// For now we just verify the command is set up correctly
+	cmd.SetArgs([]string{
+		"validate",
+		"--project-path", tmpDir,
+		"--contract", contractPath,
+	})
+
+	// We can't easily test this without handling os.Exit
+	// The integration tests cover this scenario
+}
+
+func TestValidationResultTypes(t *testing.T) {
+	// Test that our types work correctly
+	result := &validator.ValidationResult{
+		Valid: true,
+	}
+
+	if !result.IsValid() {
+		t.Error("Expected valid result")
+	}
+
+	// Add an error
+	result.AddError(
+		validator.ErrorTypeMismatch,
+		"test",
+		"expected",
+		"actual",
+		"Test mismatch",
+	)
+
+	if result.IsValid() {
+		t.Error("Expected invalid result after adding error")
+	}
+
+	// Test PrintReport doesn't panic
+	buf := new(bytes.Buffer)
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	result.PrintReport()
+
+	w.Close()
+	os.Stdout = oldStdout
+	io.Copy(buf, r)
+
+	output := buf.String()
+	if !contains(output, "Test mismatch") {
+		t.Errorf("PrintReport output = %q, want to contain 'Test mismatch'", output)
+	}
+}
+
+// MockGenerateRunner for testing the generate command
+type MockGenerateRunner struct {
+	RunFunc func(cmd *cobra.Command, opts service.GenerateOptions, force bool, outputFile string, inspectorTimeout time.Duration) error
+}
+
+func (m *MockGenerateRunner) Run(cmd *cobra.Command, opts service.GenerateOptions, force bool, outputFile string, inspectorTimeout time.Duration) error {
+	if m.RunFunc != nil {
+		return m.RunFunc(cmd, opts, force, outputFile, inspectorTimeout)
+	}
+	return nil
+}
+
+func TestGenerateCommand(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		setupMock func(*MockGenerateRunner)
+		wantErr   bool
+		checkFunc func(t *testing.T, output string)
+	}{
+		{
+			name: "successful generation",
+			args: []string{"generate", "--project-path", "/test/project", "--entrypoint", "cmd.NewRootCmd"},
+			setupMock: func(m *MockGenerateRunner) {
+				m.RunFunc = func(cmd *cobra.Command, opts service.GenerateOptions, force bool, outputFile string, inspectorTimeout time.Duration) error {
+					if opts.ProjectPath != "/test/project" {
+						t.Errorf("projectPath = %q, want %q", opts.ProjectPath, "/test/project")
+					}
+					if opts.Entrypoint != "cmd.NewRootCmd" {
+						t.Errorf("entrypoint = %q, want %q", opts.Entrypoint, "cmd.NewRootCmd")
+					}
+					// Simulate the YAML output that would be printed by the real runner
+					fmt.Fprint(cmd.OutOrStdout(), "# Cliguard contract file\nuse: testapp\n")
+					return nil
+				}
+			},
+			wantErr: false,
+			checkFunc: func(t *testing.T, output string) {
+				if !contains(output, "# Cliguard contract file") {
+					t.Errorf("output = %q, want to contain %q", output, "# Cliguard contract file")
+				}
+				if !contains(output, "use: testapp") {
+					t.Errorf("output = %q, want to contain %q", output, "use: testapp")
+				}
+			},
+		},
+		{
+			name: "default project-path to current directory",
+			args: []string{"generate"},
+			setupMock: func(m *MockGenerateRunner) {
+				m.RunFunc = func(cmd *cobra.Command, opts service.GenerateOptions, force bool, outputFile string, inspectorTimeout time.Duration) error {
+					// Check that projectPath is set to current directory
+					cwd, _ := os.Getwd()
+					if opts.ProjectPath != cwd {
+						t.Errorf("projectPath = %q, want %q (current directory)", opts.ProjectPath, cwd)
+					}
+					fmt.Fprint(cmd.OutOrStdout(), "# Cliguard contract file\nuse: testapp\n")
+					return nil
+				}
+			},
+			wantErr: false,
+			checkFunc: func(t *testing.T, output string) {
+				if !contains(output, "# Cliguard contract file") {
+					t.Errorf("output = %q, want to contain %q", output, "# Cliguard contract file")
+				}
+			},
+		},
+		{
+			name: "generation error",
+			args: []string{"generate", "--project-path", "/test/project"},
+			setupMock: func(m *MockGenerateRunner) {
+				m.RunFunc = func(cmd *cobra.Command, opts service.GenerateOptions, force bool, outputFile string, inspectorTimeout time.Duration) error {
+					return errors.New("generation failed")
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "output file",
+			args: []string{"generate", "--project-path", "/test/project", "--output-file", "contract.yaml"},
+			setupMock: func(m *MockGenerateRunner) {
+				m.RunFunc = func(cmd *cobra.Command, opts service.GenerateOptions, force bool, outputFile string, inspectorTimeout time.Duration) error {
+					if outputFile != "contract.yaml" {
+						t.Errorf("outputFile = %q, want %q", outputFile, "contract.yaml")
+					}
+					return nil
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "dry run",
+			args: []string{"--dry-run", "generate", "--project-path", "/test/project"},
+			setupMock: func(m *MockGenerateRunner) {
+				m.RunFunc = func(cmd *cobra.Command, opts service.GenerateOptions, force bool, outputFile string, inspectorTimeout time.Duration) error {
+					if _, ok := opts.Executor.(*executor.DryRunExecutor); !ok {
+						t.Errorf("Executor = %T, want *executor.DryRunExecutor", opts.Executor)
+					}
+					return nil
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "inspector timeout",
+			args: []string{"generate", "--project-path", "/test/project", "--inspector-timeout", "90s"},
+			setupMock: func(m *MockGenerateRunner) {
+				m.RunFunc = func(cmd *cobra.Command, opts service.GenerateOptions, force bool, outputFile string, inspectorTimeout time.Duration) error {
+					if inspectorTimeout != 90*time.Second {
+						t.Errorf("inspectorTimeout = %v, want 90s", inspectorTimeout)
+					}
+					return nil
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "header comment",
+			args: []string{"generate", "--project-path", "/test/project", "--header-comment", "Generated at {{.Timestamp}}", "--no-header"},
+			setupMock: func(m *MockGenerateRunner) {
+				m.RunFunc = func(cmd *cobra.Command, opts service.GenerateOptions, force bool, outputFile string, inspectorTimeout time.Duration) error {
+					if opts.HeaderComment != "Generated at {{.Timestamp}}" || !opts.NoHeader {
+						t.Errorf("HeaderComment = %q, NoHeader = %v; want the flag values", opts.HeaderComment, opts.NoHeader)
+					}
+					return nil
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "group by subpackage",
+			args: []string{"generate", "--project-path", "/test/project", "--group-by-subpackage", "--output-file", "cliguard.yaml"},
+			setupMock: func(m *MockGenerateRunner) {
+				m.RunFunc = func(cmd *cobra.Command, opts service.GenerateOptions, force bool, outputFile string, inspectorTimeout time.Duration) error {
+					if !opts.GroupBySubpackage {
+						t.Error("GroupBySubpackage = false, want true")
+					}
+					return nil
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name:      "group by subpackage without output file",
+			args:      []string{"generate", "--project-path", "/test/project", "--group-by-subpackage"},
+			setupMock: func(m *MockGenerateRunner) {},
+			wantErr:   true,
+		},
+		{
+			name: "runnable only",
+			args: []string{"generate", "--project-path", "/test/project", "--runnable-only"},
+			setupMock: func(m *MockGenerateRunner) {
+				m.RunFunc = func(cmd *cobra.Command, opts service.GenerateOptions, force bool, outputFile string, inspectorTimeout time.Duration) error {
+					if !opts.RunnableOnly {
+						t.Error("RunnableOnly = false, want true")
+					}
+					return nil
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "cobra use name only",
+			args: []string{"generate", "--project-path", "/test/project", "--cobra-use-name-only"},
+			setupMock: func(m *MockGenerateRunner) {
+				m.RunFunc = func(cmd *cobra.Command, opts service.GenerateOptions, force bool, outputFile string, inspectorTimeout time.Duration) error {
+					if !opts.UseNameOnly {
+						t.Error("UseNameOnly = false, want true")
+					}
+					return nil
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "normalize use by default",
+			args: []string{"generate", "--project-path", "/test/project"},
+			setupMock: func(m *MockGenerateRunner) {
+				m.RunFunc = func(cmd *cobra.Command, opts service.GenerateOptions, force bool, outputFile string, inspectorTimeout time.Duration) error {
+					if !opts.UseNameOnly {
+						t.Error("UseNameOnly = false, want true")
+					}
+					return nil
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "keep argument patterns",
+			args: []string{"generate", "--project-path", "/test/project", "--normalize-use=false"},
+			setupMock: func(m *MockGenerateRunner) {
+				m.RunFunc = func(cmd *cobra.Command, opts service.GenerateOptions, force bool, outputFile string, inspectorTimeout time.Duration) error {
+					if opts.UseNameOnly {
+						t.Error("UseNameOnly = true, want false")
+					}
+					return nil
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "include hidden and deprecated flags",
+			args: []string{"generate", "--project-path", "/test/project", "--include-hidden", "--include-deprecated"},
+			setupMock: func(m *MockGenerateRunner) {
+				m.RunFunc = func(cmd *cobra.Command, opts service.GenerateOptions, force bool, outputFile string, inspectorTimeout time.Duration) error {
+					if !opts.IncludeHiddenFlags || opts.OmitDeprecatedFlags {
+						t.Errorf("IncludeHiddenFlags = %v, OmitDeprecatedFlags = %v, want true, false", opts.IncludeHiddenFlags, opts.OmitDeprecatedFlags)
+					}
+					return nil
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "json output",
+			args: []string{"generate", "--project-path", "/test/project", "--output", "json"},
+			setupMock: func(m *MockGenerateRunner) {
+				m.RunFunc = func(cmd *cobra.Command, opts service.GenerateOptions, force bool, outputFile string, inspectorTimeout time.Duration) error {
+					if opts.OutputFormat != "json" {
+						t.Errorf("OutputFormat = %q, want json", opts.OutputFormat)
+					}
+					return nil
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "omit deprecated flags",
+			args: []string{"generate", "--project-path", "/test/project", "--include-deprecated=false"},
+			setupMock: func(m *MockGenerateRunner) {
+				m.RunFunc = func(cmd *cobra.Command, opts service.GenerateOptions, force bool, outputFile string, inspectorTimeout time.Duration) error {
+					if opts.IncludeHiddenFlags || !opts.OmitDeprecatedFlags {
+						t.Errorf("IncludeHiddenFlags = %v, OmitDeprecatedFlags = %v, want false, true", opts.IncludeHiddenFlags, opts.OmitDeprecatedFlags)
+					}
+					return nil
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "static",
+			args: []string{"generate", "--project-path", "/test/project", "--static"},
+			setupMock: func(m *MockGenerateRunner) {
+				m.RunFunc = func(cmd *cobra.Command, opts service.GenerateOptions, force bool, outputFile string, inspectorTimeout time.Duration) error {
+					if !opts.Static {
+						t.Error("Static = false, want true")
+					}
+					return nil
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "write snapshot",
+			args: []string{"generate", "--project-path", "/test/project", "--write-snapshot", "cli.json"},
+			setupMock: func(m *MockGenerateRunner) {
+				m.RunFunc = func(cmd *cobra.Command, opts service.GenerateOptions, force bool, outputFile string, inspectorTimeout time.Duration) error {
+					if opts.WriteSnapshot != "cli.json" {
+						t.Errorf("WriteSnapshot = %q, want cli.json", opts.WriteSnapshot)
+					}
+					return nil
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name:      "dry run rejected by other commands",
+			args:      []string{"--dry-run", "show", "--project-path", "/test/project"},
+			setupMock: func(m *MockGenerateRunner) {},
+			wantErr:   true,
+		},
+		{
+			name: "no entrypoint specified",
+			args: []string{"generate", "--project-path", "/test/project"},
+			setupMock: func(m *MockGenerateRunner) {
+				m.RunFunc = func(cmd *cobra.Command, opts service.GenerateOptions, force bool, outputFile string, inspectorTimeout time.Duration) error {
+					if opts.Entrypoint != "" {
+						t.Errorf("entrypoint = %q, want empty string", opts.Entrypoint)
+					}
+					fmt.Fprint(cmd.OutOrStdout(), "# Cliguard contract file\nuse: testapp\n")
+					return nil
+				}
+			},
+			wantErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Save original runner
+			originalRunner := generateRunner
+			defer func() { generateRunner = originalRunner }()
+
+			// Create and set mock
+			mockRunner := &MockGenerateRunner{}
+			if tt.setupMock != nil {
+				tt.setupMock(mockRunner)
+			}
+			generateRunner = mockRunner
+
+			// Create command and capture output
+			rootCmd := NewRootCmd()
+			buf := new(bytes.Buffer)
+			rootCmd.SetOut(buf)
+			rootCmd.SetErr(buf)
+			rootCmd.SetArgs(tt.args)
+
+			err := rootCmd.Execute()
+
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error but got nil")
+				}
+			} else {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+			}
+
+			if tt.checkFunc != nil {
+				tt.checkFunc(t, buf.String())
+			}
+		})
+	}
+}
+
+func TestDefaultValidateAllRunner(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"service-a", "service-b"} {
+		if err := os.Mkdir(filepath.Join(dir, name), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	configPath := filepath.Join(dir, "multi.yaml")
+	config := `projects:
+  - project_path: service-a
+  - name: renamed-b
+    project_path: service-b
+`
+	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	newRunner := func(failing string) *DefaultValidateAllRunner {
+		runner := NewDefaultValidateAllRunner()
+		runner.service.FieldChecker = nil // the contract file is mocked
+		runner.service.ContractLoader = func(path string) (*contract.Contract, error) {
+			return &contract.Contract{Use: "app", Short: "App"}, nil
+		}
+		runner.service.InspectorWithTimeout = func(projectPath, entrypoint string, timeout time.Duration) (*inspector.InspectedCLI, error) {
+			if filepath.Base(projectPath) == failing {
+				return &inspector.InspectedCLI{Use: "other", Short: "Other"}, nil
+			}
+			return &inspector.InspectedCLI{Use: "app", Short: "App"}, nil
+		}
+		return runner
+	}
+
+	t.Run("all pass", func(t *testing.T) {
+		cmd := &cobra.Command{}
+		buf := new(bytes.Buffer)
+		cmd.SetOut(buf)
+
+		err := newRunner("").Run(cmd, configPath, time.Second)
+		if err != nil {
+			t.Errorf("Run() error = %v, want nil", err)
+		}
+
+		output := buf.String()
+		for _, want := range []string{"✅ service-a", "✅ renamed-b", "All 2 project(s) passed"} {
+			if !contains(output, want) {
+				t.Errorf("output = %q, want to contain %q", output, want)
+			}
+		}
+	})
+
+	t.Run("one fails", func(t *testing.T) {
+		cmd := &cobra.Command{}
+		buf := new(bytes.Buffer)
+		cmd.SetOut(buf)
+
+		// Silence the report, which is printed to stdout
+		oldStdout := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+
+		err := newRunner("service-b").Run(cmd, configPath, time.Second)
+
+		w.Close()
+		os.Stdout = oldStdout
+		io.Copy(io.Discard, r)
+
+		if !errors.Is(err, cliguarderrors.ErrValidationFailed) {
+			t.Errorf("Run() error = %v, want %v", err, cliguarderrors.ErrValidationFailed)
+		}
+
+		output := buf.String()
+		for _, want := range []string{"✅ service-a", "❌ renamed-b (2 errors)", "1 of 2 project(s) failed"} {
+			if !contains(output, want) {
+				t.Errorf("output = %q, want to contain %q", output, want)
+			}
+		}
+	})
+
+	t.Run("missing config", func(t *testing.T) {
+		cmd := &cobra.Command{}
+		cmd.SetOut(new(bytes.Buffer))
+
+		err := newRunner("").Run(cmd, filepath.Join(dir, "missing.yaml"), time.Second)
+		if err == nil || errors.Is(err, cliguarderrors.ErrValidationFailed) {
+			t.Errorf("Run() error = %v, want config read error", err)
+		}
+	})
+}
+
+func TestDefaultGenerateRunner_DryRun(t *testing.T) {
+	projectPath, err := filepath.Abs("..")
+	if err != nil {
+		t.Fatalf("failed to resolve module root: %v", err)
+	}
+
+	cmd := &cobra.Command{}
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+
+	err = NewDefaultGenerateRunner().Run(cmd, service.GenerateOptions{
+		ProjectPath: projectPath,
+		Entrypoint:  "github.com/hiAndrewQuinn/cliguard/cmd.NewRootCmd",
+		Executor:    executor.NewDryRunExecutor([]byte("{}")),
+	}, false, filepath.Join(t.TempDir(), "contract.yaml"), 0)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{"Would run: go mod init cliguard-inspector\n", "Would run: go run inspector.go\n"} {
+		if !contains(output, want) {
+			t.Errorf("output = %q, want to contain %q", output, want)
+		}
+	}
+	if contains(output, "Contract written") {
+		t.Errorf("dry run should not write the contract, got %q", output)
+	}
+}
+
+func TestDefaultGenerateRunner_Progress(t *testing.T) {
+	run := func(t *testing.T, inspectorErr error) (*fakeProgress, error) {
+		progress := &fakeProgress{}
+		runner := NewDefaultGenerateRunner()
+		runner.NewProgress = func(io.Writer) output.Progress { return progress }
+
+		cmd := &cobra.Command{}
+		cmd.SetOut(new(bytes.Buffer))
+		err := runner.Run(cmd, service.GenerateOptions{
+			ProjectPath: t.TempDir(),
+			Executor: &executor.MockExecutor{Results: map[string]executor.MockResult{
+				"go mod init cliguard-inspector": {},
+				"go mod tidy -e":                 {},
+				"go run inspector.go":            {Output: []byte(`{"use": "app"}`), Error: inspectorErr},
+			}},
+		}, false, "", 0)
+		return progress, err
+	}
+
+	t.Run("done", func(t *testing.T) {
+		progress, err := run(t, nil)
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		if progress.starts != 1 || len(progress.stops) != 1 || progress.stops[0] != nil {
+			t.Errorf("progress = %+v, want started once and stopped without error", progress)
+		}
+	})
+
+	t.Run("build failed", func(t *testing.T) {
+		progress, err := run(t, errors.New("exit status 1"))
+		if err == nil {
+			t.Fatal("Run() error = nil, want error")
+		}
+		if progress.starts != 1 || len(progress.stops) != 1 || progress.stops[0] == nil {
+			t.Errorf("progress = %+v, want started once and stopped with the error", progress)
+		}
+	})
+}
+
+func TestDefaultGenerateRunner_OmitUnchanged(t *testing.T) {
+	dir := t.TempDir()
+	specPath := filepath.Join(dir, "openapi.yaml")
+	if err := os.WriteFile(specPath, []byte("openapi: 3.0.3\ninfo:\n  title: Pet Store\npaths: {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	contractPath := filepath.Join(dir, "cliguard.yaml")
+	if err := os.WriteFile(contractPath, []byte("# Reviewed\nuse: petctl\nshort: 'Pet Store'\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := &cobra.Command{}
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	opts := service.GenerateOptions{FromOpenAPI: specPath, ToolName: "petctl", OmitUnchanged: true}
+	if err := NewDefaultGenerateRunner().Run(cmd, opts, false, contractPath, 0); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !contains(buf.String(), "Contract unchanged: "+contractPath) {
+		t.Errorf("output = %q, want the contract reported unchanged", buf.String())
+	}
+
+	// The flag needs a file to compare against
+	root := NewRootCmd()
+	root.SetOut(new(bytes.Buffer))
+	root.SetErr(new(bytes.Buffer))
+	root.SetArgs([]string{"generate", "--from-openapi", specPath, "--omit-unchanged"})
+	if err := root.Execute(); err == nil || err.Error() != "--omit-unchanged requires --output-file" {
+		t.Errorf("Execute() error = %v, want --omit-unchanged requires --output-file", err)
+	}
+}
+
+// MockShowRunner for testing the show command
+type MockShowRunner struct {
+	RunFunc func(cmd *cobra.Command, opts service.ShowOptions) error
+}
+
+func (m *MockShowRunner) Run(cmd *cobra.Command, opts service.ShowOptions) error {
+	if m.RunFunc != nil {
+		return m.RunFunc(cmd, opts)
+	}
+	return nil
+}
+
+func TestShowCommand(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		wantOpts   service.ShowOptions
+		defaultCwd bool
+	}{
+		{
+			name: "tree format by default",
+			args: []string{"show", "--project-path", "/test/project", "--entrypoint", "cmd.NewRootCmd"},
+			wantOpts: service.ShowOptions{
+				ProjectPath: "/test/project",
+				Entrypoint:  "cmd.NewRootCmd",
+				Timeout:     30 * time.Second,
+				Format:      service.ShowFormatTree,
+			},
+		},
+		{
+			name: "json format",
+			args: []string{"show", "--project-path", "/test/project", "--format", "json", "--timeout", "1m"},
+			wantOpts: service.ShowOptions{
+				ProjectPath: "/test/project",
+				Timeout:     time.Minute,
+				Format:      service.ShowFormatJSON,
+			},
+		},
+		{
+			name:       "default project-path to current directory",
+			args:       []string{"show"},
+			defaultCwd: true,
+			wantOpts: service.ShowOptions{
+				Timeout: 30 * time.Second,
+				Format:  service.ShowFormatTree,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			originalRunner := showRunner
+			defer func() { showRunner = originalRunner }()
+
+			// Reset flag globals shared between commands
+			projectPath = ""
+			entrypoint = ""
+
+			if tt.defaultCwd {
+				tt.wantOpts.ProjectPath, _ = os.Getwd()
+			}
+
+			var gotOpts service.ShowOptions
+			showRunner = &MockShowRunner{
+				RunFunc: func(cmd *cobra.Command, opts service.ShowOptions) error {
+					gotOpts = opts
+					return nil
+				},
+			}
+
+			rootCmd := NewRootCmd()
+			rootCmd.SetOut(new(bytes.Buffer))
+			rootCmd.SetArgs(tt.args)
+
+			if err := rootCmd.Execute(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if gotOpts != tt.wantOpts {
+				t.Errorf("opts = %+v, want %+v", gotOpts, tt.wantOpts)
+			}
+		})
+	}
+}
+
+func TestDefaultShowRunner(t *testing.T) {
+	runner := &DefaultShowRunner{
+		service: &service.ShowService{
+			Inspector: func(inspector.Config) (*inspector.InspectedCLI, error) {
+				return &inspector.InspectedCLI{
+					Use:      "testapp",
+					Short:    "Test app",
+					Commands: []inspector.InspectedCommand{{Use: "serve", Short: "Start the server"}},
+				}, nil
+			},
+		},
+	}
+
+	cmd := &cobra.Command{}
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+
+	err := runner.Run(cmd, service.ShowOptions{ProjectPath: "/test/project"})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	want := "testapp: Test app\n└── serve: Start the server\n"
+	if buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+}
+
+// MockInspectRunner for testing the inspect command
+type MockInspectRunner struct {
+	RunFunc func(cmd *cobra.Command, opts service.InspectOptions) error
+}
+
+func (m *MockInspectRunner) Run(cmd *cobra.Command, opts service.InspectOptions) error {
+	if m.RunFunc != nil {
+		return m.RunFunc(cmd, opts)
+	}
+	return nil
+}
+
+func TestInspectCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantOpts service.InspectOptions
+	}{
+		{
+			name: "pretty by default",
+			args: []string{"inspect", "--project-path", "/test/project", "--entrypoint", "cmd.NewRootCmd"},
+			wantOpts: service.InspectOptions{
+				ProjectPath: "/test/project",
+				Entrypoint:  "cmd.NewRootCmd",
+				Timeout:     30 * time.Second,
+			},
+		},
+		{
+			name: "compact with a filter",
+			args: []string{"inspect", "--project-path", "/test/project", "--compact", "--jq-filter", ".commands[].use"},
+			wantOpts: service.InspectOptions{
+				ProjectPath: "/test/project",
+				Timeout:     30 * time.Second,
+				Compact:     true,
+				Filter:      ".commands[].use",
+			},
+		},
+		{
+			name: "pretty turned off",
+			args: []string{"inspect", "--project-path", "/test/project", "--pretty=false"},
+			wantOpts: service.InspectOptions{
+				ProjectPath: "/test/project",
+				Timeout:     30 * time.Second,
+				Compact:     true,
+			},
+		},
+		{
+			name: "compact overrides pretty",
+			args: []string{"inspect", "--project-path", "/test/project", "--pretty", "--compact"},
+			wantOpts: service.InspectOptions{
+				ProjectPath: "/test/project",
+				Timeout:     30 * time.Second,
+				Compact:     true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			originalRunner := inspectRunner
+			defer func() { inspectRunner = originalRunner }()
+
+			// Reset flag globals shared between commands
+			projectPath = ""
+			entrypoint = ""
+
+			var gotOpts service.InspectOptions
+			inspectRunner = &MockInspectRunner{
+				RunFunc: func(cmd *cobra.Command, opts service.InspectOptions) error {
+					gotOpts = opts
+					return nil
+				},
+			}
+
+			rootCmd := NewRootCmd()
+			rootCmd.SetOut(new(bytes.Buffer))
+			rootCmd.SetArgs(tt.args)
+
+			if err := rootCmd.Execute(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if gotOpts != tt.wantOpts {
+				t.Errorf("opts = %+v, want %+v", gotOpts, tt.wantOpts)
+			}
+		})
+	}
+}
+
+func TestDefaultInspectRunner(t *testing.T) {
+	runner := &DefaultInspectRunner{
+		service: &service.InspectService{
+			Inspector: func(inspector.Config) (*inspector.InspectedCLI, error) {
+				return &inspector.InspectedCLI{
+					Use:      "testapp",
+					Commands: []inspector.InspectedCommand{{Use: "serve"}, {Use: "migrate"}},
+				}, nil
+			},
+		},
+	}
+
+	cmd := &cobra.Command{}
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+
+	err := runner.Run(cmd, service.InspectOptions{ProjectPath: "/test/project", Filter: ".commands[].use"})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	want := "\"serve\"\n\"migrate\"\n"
+	if buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+}
+
+// MockCompareRunner for testing the compare command
+type MockCompareRunner struct {
+	RunFunc func(cmd *cobra.Command, opts service.CompareOptions) error
+}
+
+func (m *MockCompareRunner) Run(cmd *cobra.Command, opts service.CompareOptions) error {
+	if m.RunFunc != nil {
+		return m.RunFunc(cmd, opts)
+	}
+	return nil
+}
+
+func TestCompareCommand(t *testing.T) {
+	originalRunner := compareRunner
+	defer func() { compareRunner = originalRunner }()
+
+	var gotOpts service.CompareOptions
+	compareRunner = &MockCompareRunner{
+		RunFunc: func(cmd *cobra.Command, opts service.CompareOptions) error {
+			gotOpts = opts
+			return nil
+		},
+	}
+
+	rootCmd := NewRootCmd()
+	rootCmd.SetOut(new(bytes.Buffer))
+	rootCmd.SetArgs([]string{"compare",
+		"--old-project", ".", "--old-entrypoint", "cmd.NewRootCmd",
+		"--new-project", "../new-version", "--new-entrypoint", "cmd.NewRoot"})
+
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := service.CompareOptions{
+		OldProjectPath: ".",
+		OldEntrypoint:  "cmd.NewRootCmd",
+		NewProjectPath: "../new-version",
+		NewEntrypoint:  "cmd.NewRoot",
+		Timeout:        30 * time.Second,
+	}
+	if gotOpts != want {
+		t.Errorf("opts = %+v, want %+v", gotOpts, want)
+	}
+}
+
+func TestDefaultCompareRunner(t *testing.T) {
+	oldCLI := &inspector.InspectedCLI{
+		Use:      "testapp",
+		Short:    "Test app",
+		Commands: []inspector.InspectedCommand{{Use: "serve", Short: "Start the server"}},
+	}
+
+	tests := []struct {
+		name       string
+		newCLI     *inspector.InspectedCLI
+		wantErr    error
+		wantOutput string
+	}{
+		{
+			name:       "identical",
+			newCLI:     oldCLI,
+			wantOutput: "No differences found.",
+		},
+		{
+			name: "non-breaking",
+			newCLI: &inspector.InspectedCLI{
+				Use:   "testapp",
+				Short: "Test app",
+				Commands: []inspector.InspectedCommand{
+					{Use: "serve", Short: "Start the server"},
+					{Use: "stop", Short: "Stop the server"},
+				},
+			},
+			wantErr:    cliguarderrors.ErrValidationFailed,
+			wantOutput: "+ stop (command added)",
+		},
+		{
+			name:       "breaking",
+			newCLI:     &inspector.InspectedCLI{Use: "testapp", Short: "Test app"},
+			wantErr:    cliguarderrors.ErrBreakingChanges,
+			wantOutput: "- serve (command removed)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &DefaultCompareRunner{
+				service: &service.CompareService{
+					Inspector: func(config inspector.Config) (*inspector.InspectedCLI, error) {
+						if config.ProjectPath == "/old" {
+							return oldCLI, nil
+						}
+						return tt.newCLI, nil
+					},
+				},
+			}
+
+			cmd := &cobra.Command{}
+			buf := new(bytes.Buffer)
+			cmd.SetOut(buf)
+
+			err := runner.Run(cmd, service.CompareOptions{OldProjectPath: "/old", NewProjectPath: "/new"})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Run() error = %v, want %v", err, tt.wantErr)
+			}
+			if !contains(buf.String(), tt.wantOutput) {
+				t.Errorf("output = %q, want to contain %q", buf.String(), tt.wantOutput)
+			}
+		})
+	}
+}
+
+// MockDiffRunner for testing the diff command
+type MockDiffRunner struct {
+	RunFunc func(cmd *cobra.Command, opts service.DiffOptions, exitCode bool) error
+}
+
+func (m *MockDiffRunner) Run(cmd *cobra.Command, opts service.DiffOptions, exitCode bool) error {
+	if m.RunFunc != nil {
+		return m.RunFunc(cmd, opts, exitCode)
+	}
+	return nil
+}
+
+func TestDiffCommand(t *testing.T) {
+	originalRunner := diffRunner
+	defer func() { diffRunner = originalRunner }()
+
+	tests := []struct {
+		name         string
+		args         []string
+		wantExitCode bool
+		wantErr      bool
+	}{
+		{name: "contracts", args: []string{"--from", "main.yaml", "--to", "cliguard.yaml"}},
+		{name: "exit code", args: []string{"--from", "main.yaml", "--to", "cliguard.yaml", "--exit-code"}, wantExitCode: true},
+		{name: "missing to", args: []string{"--from", "main.yaml"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotOpts service.DiffOptions
+			var gotExitCode, called bool
+			diffRunner = &MockDiffRunner{
+				RunFunc: func(cmd *cobra.Command, opts service.DiffOptions, exitCode bool) error {
+					gotOpts, gotExitCode, called = opts, exitCode, true
+					return nil
+				},
+			}
+
+			rootCmd := NewRootCmd()
+			rootCmd.SetOut(new(bytes.Buffer))
+			rootCmd.SetErr(new(bytes.Buffer))
+			rootCmd.SetArgs(append([]string{"diff"}, tt.args...))
+			err := rootCmd.Execute()
+			if tt.wantErr {
+				if err == nil || called {
+					t.Errorf("Execute() error = %v, want the missing flag rejected", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			want := service.DiffOptions{From: "main.yaml", To: "cliguard.yaml"}
+			if gotOpts != want || gotExitCode != tt.wantExitCode {
+				t.Errorf("opts = %+v, exitCode = %v, want %+v, %v", gotOpts, gotExitCode, want, tt.wantExitCode)
+			}
+		})
+	}
+}
+
+func TestDefaultDiffRunner(t *testing.T) {
+	contracts := map[string]*contract.Contract{
+		"main.yaml": {Use: "testapp", Short: "Test app", Commands: []contract.Command{{Use: "serve", Short: "Start the server"}}},
+		"same.yaml": {Use: "testapp", Short: "Test app", Commands: []contract.Command{{Use: "serve", Short: "Start the server"}}},
+		"branch.yaml": {Use: "testapp", Short: "Test app", Commands: []contract.Command{
+			{Use: "serve", Short: "Start the server"},
+			{Use: "stop", Short: "Stop the server"},
+		}},
+	}
+
+	tests := []struct {
+		name       string
+		to         string
+		exitCode   bool
+		wantErr    error
+		wantOutput string
+	}{
+		{name: "identical", to: "same.yaml", exitCode: true, wantOutput: "No differences found."},
+		{name: "differences", to: "branch.yaml", wantOutput: "+command stop"},
+		{name: "differences with exit code", to: "branch.yaml", exitCode: true, wantErr: cliguarderrors.ErrValidationFailed, wantOutput: "+command stop"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &DefaultDiffRunner{
+				service: &service.DiffService{
+					ContractLoader: func(path string) (*contract.Contract, error) {
+						return contracts[path], nil
+					},
+				},
+			}
+
+			cmd := &cobra.Command{}
+			buf := new(bytes.Buffer)
+			cmd.SetOut(buf)
+
+			err := runner.Run(cmd, service.DiffOptions{From: "main.yaml", To: tt.to}, tt.exitCode)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Run() error = %v, want %v", err, tt.wantErr)
+			}
+			if !contains(buf.String(), tt.wantOutput) {
+				t.Errorf("output = %q, want to contain %q", buf.String(), tt.wantOutput)
+			}
+		})
+	}
+}
+
+type MockUpdateRunner struct {
+	RunFunc func(cmd *cobra.Command, opts service.UpdateOptions, yes bool) error
+}
+
+func (m *MockUpdateRunner) Run(cmd *cobra.Command, opts service.UpdateOptions, yes bool) error {
+	if m.RunFunc != nil {
+		return m.RunFunc(cmd, opts, yes)
+	}
+	return nil
+}
+
+func TestUpdateCommand(t *testing.T) {
+	originalRunner := updateRunner
+	defer func() { updateRunner = originalRunner }()
+	defer func() { projectPath, contractPath, entrypoint = "", "", "" }()
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantYes bool
+		wantErr bool
+	}{
+		{name: "confirm", args: []string{"--project-path", "app", "--contract", "app/cliguard.yaml", "--entrypoint", "test.Func"}},
+		{name: "yes", args: []string{"--project-path", "app", "--contract", "app/cliguard.yaml", "--entrypoint", "test.Func", "-y"}, wantYes: true},
+		{name: "missing entrypoint", args: []string{"--project-path", "app"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotOpts service.UpdateOptions
+			var gotYes, called bool
+			updateRunner = &MockUpdateRunner{
+				RunFunc: func(cmd *cobra.Command, opts service.UpdateOptions, yes bool) error {
+					gotOpts, gotYes, called = opts, yes, true
+					return nil
+				},
+			}
+
+			rootCmd := NewRootCmd()
+			rootCmd.SetOut(new(bytes.Buffer))
+			rootCmd.SetErr(new(bytes.Buffer))
+			rootCmd.SetArgs(append([]string{"update"}, tt.args...))
+			err := rootCmd.Execute()
+			if tt.wantErr {
+				if err == nil || called {
+					t.Errorf("Execute() error = %v, want the missing flag rejected", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			want := service.UpdateOptions{ProjectPath: "app", ContractPath: "app/cliguard.yaml", Entrypoint: "test.Func", Timeout: 30 * time.Second}
+			if gotOpts != want || gotYes != tt.wantYes {
+				t.Errorf("opts = %+v, yes = %v, want %+v, %v", gotOpts, gotYes, want, tt.wantYes)
+			}
+		})
+	}
+}
+
+func TestDefaultInitRunner(t *testing.T) {
+	candidates := []discovery.EntrypointCandidate{
+		{FilePath: "cmd/root.go", LineNumber: 7, Framework: "cobra", Confidence: 95, FunctionSignature: "func NewRootCmd() *cobra.Command", PackagePath: "example.com/app/cmd"},
+		{FilePath: "cmd/root.go", LineNumber: 8, Framework: "cobra", Confidence: 90, FunctionSignature: "func NewRootCmd() *cobra.Command", PackagePath: "example.com/app/cmd"},
+		{FilePath: "main.go", LineNumber: 5, Framework: "urfave/cli", Confidence: 90, PackagePath: "example.com/app"},
+		{FilePath: "tools/gen/main.go", LineNumber: 12, Framework: "cobra", Confidence: 85, FunctionSignature: "func NewGenCmd() *cobra.Command", PackagePath: "example.com/app/tools/gen"},
+	}
+	const generated = "use: app\nshort: An app\n"
+
+	tests := []struct {
+		name           string
+		entrypoint     string
+		yes            bool
+		input          string
+		wantEntrypoint string
+		wantWritten    bool
+		wantMakeTarget bool
+		wantOutput     string
+	}{
+		{name: "selected and confirmed", input: "2\ny\ny\n", wantEntrypoint: "example.com/app/tools/gen.NewGenCmd", wantWritten: true, wantMakeTarget: true, wantOutput: "Added a validate-cli target to Makefile"},
+		{name: "confirmed without CI step", input: "1\ny\nn\n", wantEntrypoint: "example.com/app/cmd.NewRootCmd", wantWritten: true, wantOutput: "Run 'cliguard validate --entrypoint example.com/app/cmd.NewRootCmd'"},
+		{name: "declined", input: "1\nn\n", wantEntrypoint: "example.com/app/cmd.NewRootCmd", wantOutput: "Contract not written."},
+		{name: "entrypoint given", entrypoint: "example.com/app/cmd.Root", input: "y\nn\n", wantEntrypoint: "example.com/app/cmd.Root", wantWritten: true},
+		{name: "yes", yes: true, wantEntrypoint: "example.com/app/cmd.NewRootCmd", wantWritten: true, wantMakeTarget: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "Makefile"), []byte("test:\n\tgo test ./...\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			var gotEntrypoint string
+			runner := &DefaultInitRunner{
+				Discover: func(projectPath string) (*discovery.DiscovererResult, error) {
+					if tt.entrypoint != "" {
+						t.Error("entrypoints should not be discovered when one is given")
+					}
+					return &discovery.DiscovererResult{Candidates: candidates}, nil
+				},
+				Generate: func(opts service.GenerateOptions) (string, error) {
+					gotEntrypoint = opts.Entrypoint
+					return generated, nil
+				},
+			}
+
+			cmd := &cobra.Command{}
+			buf := new(bytes.Buffer)
+			cmd.SetOut(buf)
+			cmd.SetIn(strings.NewReader(tt.input))
+			if err := runner.Run(cmd, service.GenerateOptions{ProjectPath: dir, Entrypoint: tt.entrypoint}, tt.yes); err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+
+			if gotEntrypoint != tt.wantEntrypoint {
+				t.Errorf("generated entrypoint = %q, want %q", gotEntrypoint, tt.wantEntrypoint)
+			}
+			if !tt.yes && tt.entrypoint == "" && !contains(buf.String(), "Enter selection (1-2)") {
+				t.Errorf("output = %q, want the two Cobra entrypoints offered", buf.String())
+			}
+			if !contains(buf.String(), generated) {
+				t.Errorf("output = %q, want the contract previewed", buf.String())
+			}
+			data, err := os.ReadFile(filepath.Join(dir, "cliguard.yaml"))
+			if written := err == nil; written != tt.wantWritten || (written && string(data) != generated) {
+				t.Errorf("cliguard.yaml = %q (%v), want written %v", data, err, tt.wantWritten)
+			}
+			makefile, err := os.ReadFile(filepath.Join(dir, "Makefile"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := contains(string(makefile), "validate-cli:"); got != tt.wantMakeTarget {
+				t.Errorf("Makefile = %q, want a validate-cli target %v", makefile, tt.wantMakeTarget)
+			}
+			if !contains(buf.String(), tt.wantOutput) {
+				t.Errorf("output = %q, want to contain %q", buf.String(), tt.wantOutput)
+			}
+		})
+	}
+
+	runner := &DefaultInitRunner{
+		Discover: func(projectPath string) (*discovery.DiscovererResult, error) {
+			return &discovery.DiscovererResult{Candidates: candidates[2:3]}, nil
+		},
+	}
+	err := runner.Run(&cobra.Command{}, service.GenerateOptions{ProjectPath: t.TempDir()}, false)
+	if err == nil || !contains(err.Error(), "no Cobra entrypoints found") {
+		t.Errorf("Run() error = %v, want no Cobra entrypoints reported", err)
+	}
+}
+
+func TestDefaultUpdateRunner(t *testing.T) {
+	const existing = "use: testapp\nshort: Test app\ncommands:\n  - use: serve\n    short: Start the server\n"
+	generated := &contract.Contract{Use: "testapp", Short: "Test app", Commands: []contract.Command{
+		{Use: "serve", Short: "Start the server"},
+		{Use: "stop", Short: "Stop the server"},
+	}}
+
+	tests := []struct {
+		name        string
+		generated   *contract.Contract
+		yes         bool
+		input       string
+		wantWritten bool
+		wantOutput  string
+	}{
+		{name: "up to date", generated: &contract.Contract{Use: "testapp", Short: "Test app", Commands: []contract.Command{{Use: "serve", Short: "Start the server"}}}, wantOutput: "is up to date with the CLI"},
+		{name: "confirmed", generated: generated, input: "y\n", wantWritten: true, wantOutput: "+command stop"},
+		{name: "declined", generated: generated, input: "n\n", wantOutput: "Contract not updated."},
+		{name: "no answer", generated: generated, wantOutput: "Contract not updated."},
+		{name: "yes", generated: generated, yes: true, wantWritten: true, wantOutput: "Contract written to"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "cliguard.yaml")
+			if err := os.WriteFile(path, []byte(existing), 0644); err != nil {
+				t.Fatal(err)
+			}
+			runner := &DefaultUpdateRunner{
+				service: &service.UpdateService{
+					ContractLoader: contract.Load,
+					Generator: func(opts service.GenerateOptions) (string, *contract.Contract, error) {
+						return "", tt.generated, nil
+					},
+				},
+			}
+
+			cmd := &cobra.Command{}
+			buf := new(bytes.Buffer)
+			cmd.SetOut(buf)
+			cmd.SetIn(strings.NewReader(tt.input))
+
+			if err := runner.Run(cmd, service.UpdateOptions{ProjectPath: dir, Entrypoint: "test.Func"}, tt.yes); err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if !contains(buf.String(), tt.wantOutput) {
+				t.Errorf("output = %q, want to contain %q", buf.String(), tt.wantOutput)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if written := string(data) != existing; written != tt.wantWritten {
+				t.Errorf("contract written = %v, want %v:\n%s", written, tt.wantWritten, data)
+			}
+		})
+	}
+}
+
+func TestDefaultDoctorRunner(t *testing.T) {
+	newRunner := func(fs *filesystem.MockFileSystem, created *string) *DefaultDoctorRunner {
+		return &DefaultDoctorRunner{
+			config: doctor.Config{
+				CacheDir:   "/home/test/.cache/cliguard",
+				FileSystem: fs,
+				Executor: &executor.MockExecutor{
+					Results: map[string]executor.MockResult{"go mod tidy -diff": {}},
+				},
+				LookPath: func(name string) (string, error) {
+					return "/usr/local/bin/" + name, nil
+				},
+				MkdirAll: func(path string, perm os.FileMode) error {
+					*created = path
+					return nil
+				},
+			},
+		}
+	}
+
+	t.Run("diagnose only", func(t *testing.T) {
+		fs := filesystem.NewMockFileSystem()
+		fs.Files["/project/go.mod"] = []byte("module github.com/test/mycli\n")
+		var created string
+
+		cmd := &cobra.Command{}
+		buf := new(bytes.Buffer)
+		cmd.SetOut(buf)
+
+		if err := newRunner(fs, &created).Run(cmd, "/project", false); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		output := buf.String()
+		for _, want := range []string{"✅ go: found /usr/local/bin/go", "contract: /project/cliguard.yaml is missing", "Run 'cliguard doctor --fix' to fix 2 problem(s)."} {
+			if !contains(output, want) {
+				t.Errorf("output = %q, want to contain %q", output, want)
+			}
+		}
+		if _, ok := fs.Files["/project/cliguard.yaml"]; ok || created != "" {
+			t.Error("doctor without --fix should not change anything")
+		}
+	})
+
+	t.Run("fix", func(t *testing.T) {
+		fs := filesystem.NewMockFileSystem()
+		fs.Files["/project/go.mod"] = []byte("module github.com/test/mycli\n")
+		var created string
+
+		cmd := &cobra.Command{}
+		buf := new(bytes.Buffer)
+		cmd.SetOut(buf)
+
+		if err := newRunner(fs, &created).Run(cmd, "/project", true); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		output := buf.String()
+		for _, want := range []string{"🔧 contract: created a template /project/cliguard.yaml", "🔧 cache: created /home/test/.cache/cliguard"} {
+			if !contains(output, want) {
+				t.Errorf("output = %q, want to contain %q", output, want)
+			}
+		}
+		if !contains(string(fs.Files["/project/cliguard.yaml"]), "use: mycli") {
+			t.Errorf("template contract = %q", fs.Files["/project/cliguard.yaml"])
+		}
+		if created != "/home/test/.cache/cliguard" {
+			t.Errorf("created = %q", created)
+		}
+	})
+
+	t.Run("unfixable", func(t *testing.T) {
+		fs := filesystem.NewMockFileSystem()
+		fs.Files["/project/go.mod"] = []byte("module github.com/test/mycli\n")
+		var created string
+		runner := newRunner(fs, &created)
+		runner.config.LookPath = func(string) (string, error) { return "", errors.New("not found") }
+
+		cmd := &cobra.Command{}
+		buf := new(bytes.Buffer)
+		cmd.SetOut(buf)
+
+		err := runner.Run(cmd, "/project", true)
+		if err == nil || !contains(err.Error(), "could not fix 1 problem(s)") {
+			t.Errorf("Run() error = %v, want unresolved problem", err)
+		}
+		if !contains(buf.String(), "❌ go: cannot be fixed automatically") {
+			t.Errorf("output = %q", buf.String())
+		}
+	})
+}
+
+func TestDefaultAuditRunner(t *testing.T) {
+	tests := []struct {
+		name        string
+		contract    *contract.Contract
+		loadErr     error
+		wantErr     bool
+		wantOutputs []string
+	}{
+		{
+			name: "findings",
+			contract: &contract.Contract{
+				Use: "app",
+				Flags: []contract.Flag{
+					{Name: "password", Type: "string", Usage: "Database password"},
+					{Name: "output", Type: "string"},
+				},
+			},
+			wantOutputs: []string{
+				"Auditing contract: cliguard.yaml",
+				"🔴 [high] app --password",
+				"🟡 [low] app --output",
+				"Recommendation:",
+				"Found 2 issue(s): 1 high, 0 medium, 1 low, 0 info",
+			},
+		},
+		{
+			name: "no findings",
+			contract: &contract.Contract{
+				Use:   "app",
+				Flags: []contract.Flag{{Name: "port", Type: "int", Usage: "Port"}},
+			},
+			wantOutputs: []string{"✅ No issues found."},
+		},
+		{
+			name:    "contract load failure",
+			loadErr: errors.New("file not found"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &DefaultAuditRunner{
+				ContractLoader: func(path string) (*contract.Contract, error) {
+					if path != "cliguard.yaml" {
+						t.Errorf("contract path = %q, want %q", path, "cliguard.yaml")
+					}
+					return tt.contract, tt.loadErr
+				},
+			}
+
+			cmd := &cobra.Command{}
+			buf := new(bytes.Buffer)
+			cmd.SetOut(buf)
+
+			err := runner.Run(cmd, "cliguard.yaml")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for _, want := range tt.wantOutputs {
+				if !contains(buf.String(), want) {
+					t.Errorf("output = %q, want to contain %q", buf.String(), want)
+				}
+			}
+		})
+	}
+}
+
+func TestDefaultContractGraphRunner(t *testing.T) {
+	runner := &DefaultContractGraphRunner{
+		ContractLoader: func(string) (*contract.Contract, error) {
+			return &contract.Contract{
+				Use:      "app",
+				Short:    "App",
+				Flags:    []contract.Flag{{Name: "verbose", Type: "bool"}},
+				Commands: []contract.Command{{Use: "serve", Short: "Start the server"}},
+			}, nil
+		},
+	}
+
+	t.Run("stdout", func(t *testing.T) {
+		cmd := &cobra.Command{}
+		buf := new(bytes.Buffer)
+		cmd.SetOut(buf)
+
+		if err := runner.Run(cmd, "cliguard.yaml", "", "mermaid", true); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		for _, want := range []string{
+			"flowchart LR",
+			`cmd0["app<br/>App<br/>1 flag"]`,
+			`flag1(["--verbose (bool)"])`,
+			`cmd2["serve<br/>Start the server<br/>0 flags"]`,
+		} {
+			if !contains(buf.String(), want) {
+				t.Errorf("output = %q, want to contain %q", buf.String(), want)
+			}
+		}
+	})
+
+	t.Run("output file", func(t *testing.T) {
+		outputPath := filepath.Join(t.TempDir(), "graph.dot")
+		cmd := &cobra.Command{}
+		buf := new(bytes.Buffer)
+		cmd.SetOut(buf)
+
+		if err := runner.Run(cmd, "cliguard.yaml", outputPath, "dot", false); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		if !contains(buf.String(), "Graph written to "+outputPath) {
+			t.Errorf("output = %q, want the output path", buf.String())
+		}
+		data, err := os.ReadFile(outputPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !contains(string(data), `cmd1 [label="serve\nStart the server\n0 flags"];`) || contains(string(data), "verbose") {
+			t.Errorf("graph = %q, want commands without flag nodes", data)
+		}
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		err := runner.Run(&cobra.Command{}, "cliguard.yaml", "", "svg", false)
+		if err == nil || !contains(err.Error(), "unsupported graph format 'svg'") {
+			t.Errorf("Run() error = %v, want unsupported format", err)
+		}
+	})
+}
+
+func TestDefaultImportsRunner(t *testing.T) {
+	var gotArgs []string
+	runner := &DefaultImportsRunner{
+		SourceFinder: func(projectPath, entrypoint, commandPath string) (*analysis.Sources, error) {
+			gotArgs = []string{projectPath, entrypoint, commandPath}
+			return &analysis.Sources{
+				Command: "app db migrate",
+				Files: []analysis.SourceFile{
+					{Path: "cmd/db/db.go", Package: "example.com/app/cmd/db", Roles: []string{analysis.RoleRegistration}},
+					{Path: "cmd/db/migrate.go", Package: "example.com/app/cmd/db", Roles: []string{analysis.RoleCommand, analysis.RoleFlags}},
+				},
+			}, nil
+		},
+	}
+
+	cmd := &cobra.Command{}
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+
+	if err := runner.Run(cmd, "/project", "example.com/app/cmd.NewRootCmd", "db migrate"); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if fmt.Sprint(gotArgs) != "[/project example.com/app/cmd.NewRootCmd db migrate]" {
+		t.Errorf("SourceFinder args = %q", gotArgs)
+	}
+	want := `Files defining app db migrate:
+  cmd/db/db.go (registration)
+  cmd/db/migrate.go (command, flags)
+
+Packages:
+  example.com/app/cmd/db
+`
+	if buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestDefaultReplRunner(t *testing.T) {
+	historyPath := filepath.Join(t.TempDir(), "history")
+
+	originalRunner := replRunner
+	defer func() { replRunner = originalRunner }()
+	replRunner = &DefaultReplRunner{HistoryPath: historyPath}
+
+	rootCmd := NewRootCmd()
+	buf := new(bytes.Buffer)
+	rootCmd.SetOut(buf)
+	rootCmd.SetErr(buf)
+	rootCmd.SetIn(bytes.NewBufferString("set project-path /test/project\ncontext\nrepl\nexit\n"))
+	rootCmd.SetArgs([]string{"repl"})
+
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{
+		"cliguard (/test/project)> ",
+		"--project-path /test/project",
+		"Error: already in an interactive session",
+	} {
+		if !contains(output, want) {
+			t.Errorf("output = %q, want to contain %q", output, want)
+		}
+	}
+	if !exitOnValidationFailure || replActive {
+		t.Error("REPL session state was not restored")
+	}
+	if _, err := os.Stat(historyPath); err != nil {
+		t.Errorf("history file not written: %v", err)
+	}
+}
+
+func TestDefaultMigrateContractRunner(t *testing.T) {
+	inputPath := filepath.Join(t.TempDir(), "v1.yaml")
+	if err := os.WriteFile(inputPath, []byte("use: app\nshort: My app\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runner := NewDefaultMigrateContractRunner()
+
+	t.Run("stdout", func(t *testing.T) {
+		cmd := &cobra.Command{}
+		buf := new(bytes.Buffer)
+		cmd.SetOut(buf)
+
+		if err := runner.Run(cmd, inputPath, ""); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		if !contains(buf.String(), "roots:\n    default:\n        use: app") {
+			t.Errorf("output = %q, want the contract as roots.default", buf.String())
+		}
+	})
+
+	t.Run("output file", func(t *testing.T) {
+		outputPath := filepath.Join(t.TempDir(), "v2.yaml")
+		cmd := &cobra.Command{}
+		buf := new(bytes.Buffer)
+		cmd.SetOut(buf)
+		cmd.SetErr(buf)
+
+		if err := runner.Run(cmd, inputPath, outputPath); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		if !contains(buf.String(), "✅ Contract written to "+outputPath) {
+			t.Errorf("output = %q, want a confirmation", buf.String())
+		}
+		if !contract.IsV2File(outputPath) {
+			t.Error("output file should be a v2 contract")
+		}
+	})
+
+	t.Run("missing input", func(t *testing.T) {
+		if err := runner.Run(&cobra.Command{}, filepath.Join(t.TempDir(), "missing.yaml"), ""); err == nil {
+			t.Error("Run() should fail for a missing input file")
+		}
+	})
+}
 
-	// Run command - this will exit(1) so we can't test the full flow
-	// But we can verify the setup works
-	cmd := NewRootCmd()
-	buf := new(bytes.Buffer)
-	cmd.SetOut(buf)
-	cmd.SetErr(buf)
+func TestDefaultLintRunner(t *testing.T) {
+	writeContract := func(t *testing.T) string {
+		path := filepath.Join(t.TempDir(), "cliguard.yaml")
+		if err := os.WriteFile(path, []byte("use: app\nshort: my app.\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
 
-	// Create temp dir
-	tmpDir := t.TempDir()
-	contractPath := filepath.Join(tmpDir, "contract.yaml")
-	os.WriteFile(contractPath, []byte("use: test\nshort: Test"), 0644)
+	t.Run("lint", func(t *testing.T) {
+		path := writeContract(t)
+		cmd := &cobra.Command{}
+		buf := new(bytes.Buffer)
+		cmd.SetOut(buf)
 
-	// The actual test would need to handle os.Exit
-	// For now we just verify the command is set up correctly
-	cmd.SetArgs([]string{
-		"validate",
-		"--project-path", tmpDir,
-		"--contract", contractPath,
+		if err := NewDefaultLintRunner().Run(cmd, path, false, ""); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		for _, want := range []string{
+			"⚠️  [short-capitalization] app: Short description should start with a capital letter",
+			"⚠️  [short-trailing-period] app",
+			"Found 2 issue(s). Run 'cliguard lint --fix' to fix them.",
+		} {
+			if !contains(buf.String(), want) {
+				t.Errorf("output = %q, want to contain %q", buf.String(), want)
+			}
+		}
 	})
 
-	// We can't easily test this without handling os.Exit
-	// The integration tests cover this scenario
+	t.Run("fix in place", func(t *testing.T) {
+		path := writeContract(t)
+		cmd := &cobra.Command{}
+		buf := new(bytes.Buffer)
+		cmd.SetOut(buf)
+
+		if err := NewDefaultLintRunner().Run(cmd, path, true, ""); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		for _, want := range []string{
+			"🔧 capitalized short descriptions (1)",
+			"🔧 removed trailing periods from short descriptions (1)",
+			"✅ Fixed 2 issue(s); contract written to " + path,
+		} {
+			if !contains(buf.String(), want) {
+				t.Errorf("output = %q, want to contain %q", buf.String(), want)
+			}
+		}
+		if c, err := contract.Load(path); err != nil || c.Short != "My app" {
+			t.Errorf("fixed contract = %+v, %v; want short 'My app'", c, err)
+		}
+	})
+
+	t.Run("output requires fix", func(t *testing.T) {
+		err := NewDefaultLintRunner().Run(&cobra.Command{}, "cliguard.yaml", false, "out.yaml")
+		if err == nil || !contains(err.Error(), "--output requires --fix") {
+			t.Errorf("Run() error = %v, want --output requires --fix", err)
+		}
+	})
 }
 
-func TestValidationResultTypes(t *testing.T) {
-	// Test that our types work correctly
-	result := &validator.ValidationResult{
-		Valid: true,
+func TestDefaultBenchmarkRunner(t *testing.T) {
+	fakeResult := &benchmark.Result{
+		Iterations: 2,
+		Phases: []benchmark.PhaseStats{{
+			Phase:   inspector.PhaseBuild,
+			Samples: []time.Duration{time.Second, 2 * time.Second},
+			Min:     time.Second,
+			Median:  1500 * time.Millisecond,
+			P95:     2 * time.Second,
+			Max:     2 * time.Second,
+		}},
 	}
-
-	if !result.IsValid() {
-		t.Error("Expected valid result")
+	newRunner := func(got *benchmark.Options) *DefaultBenchmarkRunner {
+		return &DefaultBenchmarkRunner{
+			Benchmark: func(opts benchmark.Options) (*benchmark.Result, error) {
+				*got = opts
+				for n := 1; n <= opts.Iterations; n++ {
+					opts.OnIteration(n)
+				}
+				return fakeResult, nil
+			},
+		}
 	}
 
-	// Add an error
-	result.AddError(
-		validator.ErrorTypeMismatch,
-		"test",
-		"expected",
-		"actual",
-		"Test mismatch",
-	)
+	t.Run("prints markdown table", func(t *testing.T) {
+		var got benchmark.Options
+		cmd := &cobra.Command{}
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
 
-	if result.IsValid() {
-		t.Error("Expected invalid result after adding error")
-	}
+		opts := benchmark.Options{ProjectPath: "/project", Entrypoint: "cmd.NewRootCmd", Iterations: 2}
+		if err := newRunner(&got).Run(cmd, opts, ""); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		if got.ProjectPath != "/project" || got.Entrypoint != "cmd.NewRootCmd" {
+			t.Errorf("options = %+v", got)
+		}
+		if !contains(buf.String(), "| go_build | 1s | 1.5s | 2s | 2s |") {
+			t.Errorf("output = %q, want the markdown table", buf.String())
+		}
+		if !contains(buf.String(), "Running iteration 2/2...") {
+			t.Errorf("output = %q, want iteration progress", buf.String())
+		}
+	})
 
-	// Test PrintReport doesn't panic
-	buf := new(bytes.Buffer)
-	oldStdout := os.Stdout
-	r, w, _ := os.Pipe()
-	os.Stdout = w
+	t.Run("writes JSON output", func(t *testing.T) {
+		var got benchmark.Options
+		cmd := &cobra.Command{}
+		cmd.SetOut(new(bytes.Buffer))
+		cmd.SetErr(new(bytes.Buffer))
 
-	result.PrintReport()
+		outputPath := filepath.Join(t.TempDir(), "benchmark.json")
+		if err := newRunner(&got).Run(cmd, benchmark.Options{ProjectPath: "/project", Iterations: 2}, outputPath); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		data, err := os.ReadFile(outputPath)
+		if err != nil {
+			t.Fatalf("output not written: %v", err)
+		}
+		if !contains(string(data), `"median_ms": 1500`) {
+			t.Errorf("output = %s, want JSON results", data)
+		}
+	})
 
-	w.Close()
-	os.Stdout = oldStdout
-	io.Copy(buf, r)
+	t.Run("invalid iterations", func(t *testing.T) {
+		var got benchmark.Options
+		cmd := &cobra.Command{}
+		err := newRunner(&got).Run(cmd, benchmark.Options{ProjectPath: "/project"}, "")
+		if err == nil || !contains(err.Error(), "--iterations must be at least 1") {
+			t.Errorf("Run() error = %v, want invalid iterations", err)
+		}
+	})
+}
 
-	output := buf.String()
-	if !contains(output, "Test mismatch") {
-		t.Errorf("PrintReport output = %q, want to contain 'Test mismatch'", output)
-	}
+// mockBenchmarkRunner records the options it was run with
+type mockBenchmarkRunner struct {
+	opts       benchmark.Options
+	outputPath string
 }
 
-// MockGenerateRunner for testing the generate command
-type MockGenerateRunner struct {
-	RunFunc func(cmd *cobra.Command, projectPath, entrypoint string, timeout time.Duration, force bool) error
+func (m *mockBenchmarkRunner) Run(cmd *cobra.Command, opts benchmark.Options, outputPath string) error {
+	m.opts = opts
+	m.outputPath = outputPath
+	return nil
 }
 
-func (m *MockGenerateRunner) Run(cmd *cobra.Command, projectPath, entrypoint string, timeout time.Duration, force bool) error {
-	if m.RunFunc != nil {
-		return m.RunFunc(cmd, projectPath, entrypoint, timeout, force)
+func TestRunBenchmark_Flags(t *testing.T) {
+	originalRunner := benchmarkRunner
+	defer func() { benchmarkRunner = originalRunner }()
+
+	mockRunner := &mockBenchmarkRunner{}
+	benchmarkRunner = mockRunner
+
+	cmd := NewRootCmd()
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{"benchmark", "--project-path", "/project", "--entrypoint", "cmd.NewRootCmd",
+		"--iterations", "3", "--with-contract", "cliguard.yaml", "--output", "benchmark.json"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	want := benchmark.Options{
+		ProjectPath:  "/project",
+		Entrypoint:   "cmd.NewRootCmd",
+		Iterations:   3,
+		ContractPath: "cliguard.yaml",
+		Timeout:      30 * time.Second,
+	}
+	if mockRunner.opts.ProjectPath != want.ProjectPath || mockRunner.opts.Entrypoint != want.Entrypoint ||
+		mockRunner.opts.Iterations != want.Iterations || mockRunner.opts.ContractPath != want.ContractPath ||
+		mockRunner.opts.Timeout != want.Timeout {
+		t.Errorf("options = %+v, want %+v", mockRunner.opts, want)
+	}
+	if mockRunner.outputPath != "benchmark.json" {
+		t.Errorf("output path = %q, want benchmark.json", mockRunner.outputPath)
 	}
-	return nil
 }
 
-func TestGenerateCommand(t *testing.T) {
+func TestDefaultValidateRunner_Watch(t *testing.T) {
+	dir := t.TempDir()
+	contractFile := filepath.Join(t.TempDir(), "cliguard.yaml")
+	if err := os.WriteFile(contractFile, []byte("use: app\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	watcher := filesystem.NewMockWatcher(
+		filesystem.Event{Path: filepath.Join(dir, "vendor", "lib", "lib.go"), Op: filesystem.Write},
+		filesystem.Event{Path: filepath.Join(dir, ".git", "hooks.go"), Op: filesystem.Write},
+		filesystem.Event{Path: filepath.Join(dir, "cmd", "root_test.go"), Op: filesystem.Write},
+		filesystem.Event{Path: filepath.Join(dir, "README.md"), Op: filesystem.Write},
+		filesystem.Event{Path: filepath.Join(dir, "cmd", "root.go"), Op: filesystem.Write},
+		filesystem.Event{Path: filepath.Join(dir, "main.go"), Op: filesystem.Create},
+	)
+	interrupts := make(chan os.Signal, 1)
+	stopped := false
+	runner := NewDefaultValidateRunner()
+	runner.NewWatcher = func() filesystem.Watcher { return watcher }
+	runner.Interrupts = func() (<-chan os.Signal, func()) {
+		return interrupts, func() { stopped = true }
+	}
+	runner.WatchDelay = 10 * time.Millisecond
+
+	changes := make(chan struct{}, 10)
+	done := make(chan error)
+	go func() {
+		done <- runner.Watch(service.ValidateOptions{ProjectPath: dir, ContractPath: contractFile}, func() {
+			changes <- struct{}{}
+		})
+	}()
+
+	// The Go files changed together are validated once
+	<-changes
+	watcher.Send(filesystem.Event{Path: contractFile, Op: filesystem.Write})
+	<-changes
+	interrupts <- os.Interrupt
+	if err := <-done; err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+
+	if len(changes) != 0 {
+		t.Errorf("onChange called %d more times, want the ignored files skipped", len(changes))
+	}
+	if len(watcher.Paths) != 2 || watcher.Paths[0] != dir || watcher.Paths[1] != contractFile {
+		t.Errorf("watched paths = %v, want the project and the contract outside it", watcher.Paths)
+	}
+	if !stopped {
+		t.Error("the interrupt channel was not released")
+	}
+}
+
+func TestIsWatchedGoFile(t *testing.T) {
+	project := filepath.Join("/src", "app")
 	tests := []struct {
-		name      string
-		args      []string
-		setupMock func(*MockGenerateRunner)
-		wantErr   bool
-		checkFunc func(t *testing.T, output string)
+		path string
+		want bool
 	}{
-		{
-			name: "successful generation",
-			args: []string{"generate", "--project-path", "/test/project", "--entrypoint", "cmd.NewRootCmd"},
-			setupMock: func(m *MockGenerateRunner) {
-				m.RunFunc = func(cmd *cobra.Command, projectPath, entrypoint string, timeout time.Duration, force bool) error {
-					if projectPath != "/test/project" {
-						t.Errorf("projectPath = %q, want %q", projectPath, "/test/project")
-					}
-					if entrypoint != "cmd.NewRootCmd" {
-						t.Errorf("entrypoint = %q, want %q", entrypoint, "cmd.NewRootCmd")
-					}
-					// Simulate the YAML output that would be printed by the real runner
-					fmt.Fprint(cmd.OutOrStdout(), "# Cliguard contract file\nuse: testapp\n")
-					return nil
-				}
-			},
-			wantErr: false,
-			checkFunc: func(t *testing.T, output string) {
-				if !contains(output, "# Cliguard contract file") {
-					t.Errorf("output = %q, want to contain %q", output, "# Cliguard contract file")
-				}
-				if !contains(output, "use: testapp") {
-					t.Errorf("output = %q, want to contain %q", output, "use: testapp")
-				}
-			},
-		},
-		{
-			name: "default project-path to current directory",
-			args: []string{"generate"},
-			setupMock: func(m *MockGenerateRunner) {
-				m.RunFunc = func(cmd *cobra.Command, projectPath, entrypoint string, timeout time.Duration, force bool) error {
-					// Check that projectPath is set to current directory
-					cwd, _ := os.Getwd()
-					if projectPath != cwd {
-						t.Errorf("projectPath = %q, want %q (current directory)", projectPath, cwd)
-					}
-					fmt.Fprint(cmd.OutOrStdout(), "# Cliguard contract file\nuse: testapp\n")
-					return nil
-				}
-			},
-			wantErr: false,
-			checkFunc: func(t *testing.T, output string) {
-				if !contains(output, "# Cliguard contract file") {
-					t.Errorf("output = %q, want to contain %q", output, "# Cliguard contract file")
-				}
-			},
-		},
-		{
-			name: "generation error",
-			args: []string{"generate", "--project-path", "/test/project"},
-			setupMock: func(m *MockGenerateRunner) {
-				m.RunFunc = func(cmd *cobra.Command, projectPath, entrypoint string, timeout time.Duration, force bool) error {
-					return errors.New("generation failed")
-				}
-			},
-			wantErr: true,
-		},
-		{
-			name: "no entrypoint specified",
-			args: []string{"generate", "--project-path", "/test/project"},
-			setupMock: func(m *MockGenerateRunner) {
-				m.RunFunc = func(cmd *cobra.Command, projectPath, entrypoint string, timeout time.Duration, force bool) error {
-					if entrypoint != "" {
-						t.Errorf("entrypoint = %q, want empty string", entrypoint)
-					}
-					fmt.Fprint(cmd.OutOrStdout(), "# Cliguard contract file\nuse: testapp\n")
-					return nil
-				}
-			},
-			wantErr: false,
+		{filepath.Join(project, "main.go"), true},
+		{filepath.Join(project, "cmd", "root.go"), true},
+		{filepath.Join(project, "cmd", "root_test.go"), false},
+		{filepath.Join(project, "cliguard.yaml"), false},
+		{filepath.Join(project, "vendor", "github.com", "spf13", "cobra", "command.go"), false},
+		{filepath.Join(project, ".cache", "gen.go"), false},
+		{filepath.Join("/src", "other", "main.go"), false},
+	}
+	for _, tt := range tests {
+		if got := isWatchedGoFile(project, tt.path); got != tt.want {
+			t.Errorf("isWatchedGoFile(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestRunValidate_Watch(t *testing.T) {
+	originalRunner := validateRunner
+	defer func() { validateRunner = originalRunner }()
+
+	runs := 0
+	mockRunner := &MockValidateRunner{
+		RunFunc: func(cmd *cobra.Command, opts service.ValidateOptions, report ValidateReportOptions) error {
+			runs++
+			return cliguarderrors.ErrValidationFailed
 		},
 	}
+	mockRunner.WatchFunc = func(opts service.ValidateOptions, onChange func()) error {
+		onChange()
+		return nil
+	}
+	validateRunner = mockRunner
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			// Save original runner
-			originalRunner := generateRunner
-			defer func() { generateRunner = originalRunner }()
+	cmd := NewRootCmd()
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	cmd.SetArgs([]string{"validate", "--project-path", "/src/app", "--contract", "/src/app/api.yaml", "--entrypoint", "test.Func", "--watch"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v, want failed validations to keep watching", err)
+	}
 
-			// Create and set mock
-			mockRunner := &MockGenerateRunner{}
-			if tt.setupMock != nil {
-				tt.setupMock(mockRunner)
-			}
-			generateRunner = mockRunner
+	if runs != 2 {
+		t.Errorf("validated %d times, want once at the start and once per change", runs)
+	}
+	if len(mockRunner.WatchCalls) != 1 || mockRunner.WatchCalls[0].ProjectPath != "/src/app" || mockRunner.WatchCalls[0].ContractPath != "/src/app/api.yaml" {
+		t.Errorf("watch calls = %+v, want the project and contract", mockRunner.WatchCalls)
+	}
+	for _, want := range []string{"] Validating /src/app", "Watching /src/app for changes (press Ctrl+C to stop)", "Stopped watching."} {
+		if !contains(buf.String(), want) {
+			t.Errorf("output = %q, want to contain %q", buf.String(), want)
+		}
+	}
 
-			// Create command and capture output
-			rootCmd := NewRootCmd()
-			buf := new(bytes.Buffer)
-			rootCmd.SetOut(buf)
-			rootCmd.SetErr(buf)
-			rootCmd.SetArgs(tt.args)
+	cmd = NewRootCmd()
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{"validate", "--entrypoint", "test.Func", "--watch", "--annotate-contract"})
+	if err := cmd.Execute(); err == nil || !contains(err.Error(), "--watch cannot be used with") {
+		t.Errorf("Execute() error = %v, want --annotate-contract rejected", err)
+	}
+}
 
-			err := rootCmd.Execute()
+func TestApplyConfig(t *testing.T) {
+	originalRunner := validateRunner
+	defer func() { validateRunner = originalRunner }()
+	originalDiscoverRunner := discoverRunner
+	defer func() { discoverRunner = originalDiscoverRunner }()
+
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, ".cliguard.yaml")
+	if err := os.WriteFile(configFile, []byte("entrypoint: github.com/org/repo/cmd.NewRootCmd\ncontract: api.yaml\ntimeout: 2m\nstrict: true\nwarn-only: true\nmin-confidence: 80\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
 
-			if tt.wantErr {
-				if err == nil {
-					t.Error("expected error but got nil")
-				}
-			} else {
-				if err != nil {
-					t.Errorf("unexpected error: %v", err)
-				}
-			}
+	mockRunner := &MockValidateRunner{}
+	validateRunner = mockRunner
+	cmd := NewRootCmd()
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{"validate", "--project-path", dir, "--timeout", "5s"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if len(mockRunner.Calls) != 1 {
+		t.Fatalf("calls = %+v, want one call", mockRunner.Calls)
+	}
+	call := mockRunner.Calls[0]
+	if call.Opts.Entrypoint != "github.com/org/repo/cmd.NewRootCmd" || call.Opts.ContractPath != filepath.Join(dir, "api.yaml") || !call.Opts.StrictMode || !call.Opts.WarnOnly {
+		t.Errorf("call = %+v, want the config's defaults", call)
+	}
+	if call.Opts.Timeout != 5*time.Second {
+		t.Errorf("Timeout = %v, want --timeout to override the config", call.Opts.Timeout)
+	}
 
-			if tt.checkFunc != nil {
-				tt.checkFunc(t, buf.String())
-			}
-		})
+	// --allow-extra-commands overrides the config's strict, which it can't
+	// be combined with on the command line
+	mockRunner = &MockValidateRunner{}
+	validateRunner = mockRunner
+	cmd = NewRootCmd()
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{"validate", "--project-path", dir, "--allow-extra-commands"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() with --allow-extra-commands error = %v", err)
+	}
+	if len(mockRunner.Calls) != 1 {
+		t.Fatalf("calls = %+v, want one call", mockRunner.Calls)
+	}
+	call = mockRunner.Calls[0]
+	if call.Opts.StrictMode || !call.Opts.AllowExtraCommands || !call.Opts.WarnOnly {
+		t.Errorf("call = %+v, want --allow-extra-commands instead of the config's strict", call)
+	}
+
+	var gotMinConfidence int
+	discoverRunner = &mockDiscoverRunner{
+		runFunc: func(cmd *cobra.Command, opts DiscoverOptions) error {
+			gotMinConfidence = opts.MinConfidence
+			return nil
+		},
+	}
+	cmd = NewRootCmd()
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{"discover", "--project-path", dir})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if gotMinConfidence != 80 {
+		t.Errorf("minConfidence = %d, want 80 from the config", gotMinConfidence)
+	}
+
+	if err := os.WriteFile(configFile, []byte("strictness: high\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cmd = NewRootCmd()
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{"validate", "--project-path", dir})
+	if err := cmd.Execute(); err == nil || !contains(err.Error(), "failed to parse config") {
+		t.Errorf("Execute() error = %v, want the unknown key reported", err)
 	}
 }
@@ -4,9 +4,12 @@ import (
 	"bytes"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/hiAndrewQuinn/cliguard/internal/contract"
+	"github.com/hiAndrewQuinn/cliguard/internal/service"
 	"github.com/spf13/cobra"
 )
 
@@ -52,6 +55,60 @@ func TestNewRootCmd(t *testing.T) {
 	}
 }
 
+func TestCompletion(t *testing.T) {
+	cmd := NewRootCmd()
+	completionCmd, _, err := cmd.Find([]string{"completion"})
+	if err != nil || completionCmd.Name() != "completion" {
+		t.Fatalf("Could not find completion command: %v", err)
+	}
+	if !strings.Contains(completionCmd.Long, "source <(cliguard completion bash)") {
+		t.Errorf("completion Long = %q, want the installation steps", completionCmd.Long)
+	}
+	for _, shell := range []string{"bash", "zsh", "fish", "powershell"} {
+		if sub, _, err := completionCmd.Find([]string{shell}); err != nil || sub.Name() != shell {
+			t.Errorf("Could not find completion %s command: %v", shell, err)
+		}
+	}
+
+	validateCmd, _, _ := cmd.Find([]string{"validate"})
+	if got := validateCmd.Flag("project-path").Annotations[cobra.BashCompSubdirsInDir]; got == nil {
+		t.Error("project-path flag should complete directories")
+	}
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module example.com/app\n",
+		"cmd/root.go": `package cmd
+
+import "github.com/spf13/cobra"
+
+func NewRootCmd() *cobra.Command {
+	return &cobra.Command{Use: "app"}
+}
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{cobra.ShellCompRequestCmd, "validate", "--project-path", dir, "--entrypoint", "example.com/"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), "example.com/app/cmd.NewRootCmd\tcobra, ") {
+		t.Errorf("entrypoint completions = %q, want the discovered entrypoint", buf.String())
+	}
+}
+
 func TestRunValidate_Errors(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -164,6 +221,33 @@ func TestIntegration_ValidateCommand(t *testing.T) {
 	}
 }
 
+func TestIntegration_ValidateHiddenCommands(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	fixturePath := setupNamedTestFixture(t, "hidden-cli")
+	contractPath := filepath.Join(fixturePath, "cliguard.yaml")
+
+	cmd := &cobra.Command{}
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+
+	runner := NewDefaultValidateRunner()
+	err := runner.Run(cmd, service.ValidateOptions{
+		ProjectPath:  fixturePath,
+		ContractPath: contractPath,
+		Entrypoint:   "github.com/test/hidden-cli/cmd.NewRootCmd",
+	}, ValidateReportOptions{})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if !contains(buf.String(), "Validation passed") {
+		t.Errorf("Expected validation to pass, got: %q", buf.String())
+	}
+}
+
 func TestValidateCommand_DefaultProjectPath(t *testing.T) {
 	// Test that validate command uses current directory when project-path is not provided
 
@@ -174,8 +258,8 @@ func TestValidateCommand_DefaultProjectPath(t *testing.T) {
 	// Mock runner to capture the project path
 	var capturedPath string
 	mockRunner := &mockValidateRunner{
-		runFunc: func(cmd *cobra.Command, projectPath, contractPath, entrypoint string, timeout time.Duration, force bool) error {
-			capturedPath = projectPath
+		runFunc: func(cmd *cobra.Command, opts service.ValidateOptions, report ValidateReportOptions) error {
+			capturedPath = opts.ProjectPath
 			return nil
 		},
 	}
@@ -202,12 +286,238 @@ func TestValidateCommand_DefaultProjectPath(t *testing.T) {
 
 // mockValidateRunner for testing
 type mockValidateRunner struct {
-	runFunc func(cmd *cobra.Command, projectPath, contractPath, entrypoint string, timeout time.Duration, force bool) error
+	runFunc func(cmd *cobra.Command, opts service.ValidateOptions, report ValidateReportOptions) error
 }
 
-func (m *mockValidateRunner) Run(cmd *cobra.Command, projectPath, contractPath, entrypoint string, timeout time.Duration, force bool) error {
+func (m *mockValidateRunner) Run(cmd *cobra.Command, opts service.ValidateOptions, report ValidateReportOptions) error {
 	if m.runFunc != nil {
-		return m.runFunc(cmd, projectPath, contractPath, entrypoint, timeout, force)
+		return m.runFunc(cmd, opts, report)
 	}
 	return nil
 }
+
+func (m *mockValidateRunner) Watch(opts service.ValidateOptions, onChange func()) error {
+	return nil
+}
+
+func TestIntegration_CompletionCheck(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	fixturePath := setupNamedTestFixture(t, "completion-cli")
+	const fixtureEntrypoint = "github.com/test/completion-cli/cmd.NewRootCmd"
+
+	t.Run("contract matches", func(t *testing.T) {
+		cmd := &cobra.Command{}
+		buf := new(bytes.Buffer)
+		cmd.SetOut(buf)
+
+		runner := NewDefaultCompletionCheckRunner()
+		err := runner.Run(cmd, service.ValidateOptions{
+			ProjectPath:  fixturePath,
+			ContractPath: filepath.Join(fixturePath, "cliguard.yaml"),
+			Entrypoint:   fixtureEntrypoint,
+		})
+		if err != nil {
+			t.Fatalf("Run() error = %v, output: %s", err, buf.String())
+		}
+		if !contains(buf.String(), "Completion check passed") {
+			t.Errorf("Expected completion check to pass, got: %q", buf.String())
+		}
+	})
+
+	t.Run("completion mismatch", func(t *testing.T) {
+		contractPath := filepath.Join(t.TempDir(), "cliguard.yaml")
+		err := os.WriteFile(contractPath, []byte(`use: completion-cli
+short: A test CLI with shell completion annotations
+commands:
+  - use: export
+    short: Export data
+    flags:
+      - name: name
+        usage: export name
+        type: string
+        completion: file
+`), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		svc := service.NewValidateService()
+		result, err := svc.Validate(service.ValidateOptions{
+			ProjectPath:     fixturePath,
+			ContractPath:    contractPath,
+			Entrypoint:      fixtureEntrypoint,
+			CompletionsOnly: true,
+		})
+		if err != nil {
+			t.Fatalf("Validate() error = %v", err)
+		}
+		if result.Success || len(result.Result.Errors) != 1 {
+			t.Fatalf("Validate() errors = %+v, want exactly one completion mismatch", result.Result.Errors)
+		}
+		got := result.Result.Errors[0]
+		if got.Path != "export --name" || got.Expected != "file" || got.Actual != "none" {
+			t.Errorf("error = %+v, want file/none mismatch on export --name", got)
+		}
+	})
+}
+
+func TestIntegration_ExpectVersion(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	fixturePath := setupNamedTestFixture(t, "version-cli")
+	const fixtureEntrypoint = "github.com/test/version-cli/cmd.NewRootCmd"
+
+	t.Run("--version matches", func(t *testing.T) {
+		cmd := &cobra.Command{}
+		buf := new(bytes.Buffer)
+		cmd.SetOut(buf)
+
+		runner := NewDefaultValidateRunner()
+		err := runner.Run(cmd, service.ValidateOptions{
+			ProjectPath:   fixturePath,
+			ContractPath:  filepath.Join(fixturePath, "cliguard.yaml"),
+			Entrypoint:    fixtureEntrypoint,
+			Timeout:       30 * time.Second,
+			ExpectVersion: true,
+		}, ValidateReportOptions{})
+		if err != nil {
+			t.Fatalf("Run() error = %v, output: %s", err, buf.String())
+		}
+	})
+
+	t.Run("version subcommand mismatch", func(t *testing.T) {
+		svc := service.NewValidateService()
+		result, err := svc.Validate(service.ValidateOptions{
+			ProjectPath:    fixturePath,
+			ContractPath:   filepath.Join(fixturePath, "cliguard.yaml"),
+			Entrypoint:     fixtureEntrypoint,
+			ExpectVersion:  true,
+			VersionCommand: "version",
+		})
+		if err != nil {
+			t.Fatalf("Validate() error = %v", err)
+		}
+		if result.Success || len(result.Result.Errors) != 1 {
+			t.Fatalf("Validate() errors = %+v, want exactly one version mismatch", result.Result.Errors)
+		}
+		got := result.Result.Errors[0]
+		if got.Expected != "1.4.2" || got.Actual != "v1.5.0-rc.1" {
+			t.Errorf("error = %+v, want 1.4.2/v1.5.0-rc.1 mismatch", got)
+		}
+	})
+}
+
+func TestIntegration_GroupBySubpackage(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	fixturePath := setupNamedTestFixture(t, "subpackage-cli")
+	const fixtureEntrypoint = "github.com/test/subpackage-cli/cmd.NewRootCmd"
+
+	outputDir := t.TempDir()
+	outputFile := filepath.Join(outputDir, "cliguard.yaml")
+	cmd := &cobra.Command{}
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+
+	runner := NewDefaultGenerateRunner()
+	err := runner.Run(cmd, service.GenerateOptions{
+		ProjectPath:       fixturePath,
+		Entrypoint:        fixtureEntrypoint,
+		NoHeader:          true,
+		GroupBySubpackage: true,
+	}, false, outputFile, service.DefaultInspectorTimeout)
+	if err != nil {
+		t.Fatalf("Run() error = %v, output: %s", err, buf.String())
+	}
+
+	// The generated files match the fixture's contract
+	for _, name := range []string{"cliguard.yaml", "db-contract.yaml", "backup-contract.yaml", "user-contract.yaml"} {
+		got, err := os.ReadFile(filepath.Join(outputDir, name))
+		if err != nil {
+			t.Fatalf("generated file %s: %v", name, err)
+		}
+		want, err := os.ReadFile(filepath.Join(fixturePath, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != string(want) {
+			t.Errorf("%s =\n%s\nwant:\n%s", name, got, want)
+		}
+	}
+
+	// Validating resolves the includes
+	svc := service.NewValidateService()
+	result, err := svc.Validate(service.ValidateOptions{
+		ProjectPath:  fixturePath,
+		ContractPath: outputFile,
+		Entrypoint:   fixtureEntrypoint,
+	})
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if !result.Success {
+		t.Errorf("Validate() errors = %+v", result.Result.Errors)
+	}
+}
+
+func TestIntegration_RunnableOnly(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	fixturePath := setupNamedTestFixture(t, "subpackage-cli")
+	const fixtureEntrypoint = "github.com/test/subpackage-cli/cmd.NewRootCmd"
+
+	outputFile := filepath.Join(t.TempDir(), "cliguard.yaml")
+	cmd := &cobra.Command{}
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+
+	err := NewDefaultGenerateRunner().Run(cmd, service.GenerateOptions{
+		ProjectPath:  fixturePath,
+		Entrypoint:   fixtureEntrypoint,
+		NoHeader:     true,
+		RunnableOnly: true,
+	}, false, outputFile, service.DefaultInspectorTimeout)
+	if err != nil {
+		t.Fatalf("Run() error = %v, output: %s", err, buf.String())
+	}
+
+	c, err := contract.Load(outputFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	runnable := make(map[string]bool)
+	for _, sub := range c.Commands {
+		if sub.Runnable == nil {
+			t.Fatalf("%s has no runnable field", sub.Use)
+		}
+		runnable[sub.Use] = *sub.Runnable
+	}
+	// db and user only group their subcommands
+	for use, want := range map[string]bool{"db": false, "user": false, "version": true} {
+		if got, ok := runnable[use]; !ok || got != want {
+			t.Errorf("%s runnable = %v (present: %v), want %v", use, got, ok, want)
+		}
+	}
+
+	svc := service.NewValidateService()
+	result, err := svc.Validate(service.ValidateOptions{
+		ProjectPath:  fixturePath,
+		ContractPath: outputFile,
+		Entrypoint:   fixtureEntrypoint,
+	})
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if !result.Success {
+		t.Errorf("Validate() errors = %+v", result.Result.Errors)
+	}
+}
@@ -15,7 +15,13 @@ func contains(s, substr string) bool {
 // setupTestFixtures ensures test fixtures are properly initialized
 func setupTestFixtures(t *testing.T) string {
 	t.Helper()
-	fixtureDir := filepath.Join("..", "test", "fixtures", "simple-cli")
+	return setupNamedTestFixture(t, "simple-cli")
+}
+
+// setupNamedTestFixture ensures the named fixture under test/fixtures is properly initialized
+func setupNamedTestFixture(t *testing.T, name string) string {
+	t.Helper()
+	fixtureDir := filepath.Join("..", "test", "fixtures", name)
 
 	// Ensure go.mod is tidy
 	cmd := exec.Command("go", "mod", "tidy")
@@ -4,14 +4,24 @@ go 1.24.4
 
 require (
 	github.com/spf13/cobra v1.9.1
-  
 	github.com/stretchr/testify v1.10.0
 	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
+	github.com/fsnotify/fsnotify v1.10.1
+	github.com/itchyny/gojq v0.12.19
+	github.com/peterh/liner v1.2.2
+	github.com/spf13/pflag v1.0.6
+)
+
+require (
+	github.com/clipperhouse/stringish v0.1.1 // indirect
+	github.com/clipperhouse/uax29/v2 v2.3.0 // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
+	github.com/itchyny/timefmt-go v0.1.8 // indirect
+	github.com/mattn/go-runewidth v0.0.19 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	github.com/spf13/pflag v1.0.6 // indirect
-)
\ No newline at end of file
+	golang.org/x/sys v0.38.0 // indirect
+)
@@ -0,0 +1,318 @@
+// Package adapters converts descriptions of CLIs from other formats into
+// cliguard contracts.
+//
+// Example:
+//
+//	data, _ := os.ReadFile("openapi.yaml")
+//	spec, err := adapters.OpenAPIToContract(data, "mycli")
+package adapters
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/contract"
+	"github.com/hiAndrewQuinn/cliguard/internal/lint"
+	"gopkg.in/yaml.v3"
+)
+
+// openAPISpec is the subset of an OpenAPI 3.0 document the mapper reads
+type openAPISpec struct {
+	OpenAPI    string                 `yaml:"openapi"`
+	Info       openAPIInfo            `yaml:"info"`
+	Paths      map[string]openAPIPath `yaml:"paths"`
+	Components openAPIComponents      `yaml:"components"`
+}
+
+type openAPIInfo struct {
+	Title       string `yaml:"title"`
+	Description string `yaml:"description"`
+}
+
+type openAPIComponents struct {
+	Parameters map[string]openAPIParameter `yaml:"parameters"`
+	Schemas    map[string]openAPISchema    `yaml:"schemas"`
+}
+
+// openAPIPath is a path item. Parameters apply to every operation on the path.
+type openAPIPath struct {
+	Parameters []openAPIParameter `yaml:"parameters"`
+	Get        *openAPIOperation  `yaml:"get"`
+	Put        *openAPIOperation  `yaml:"put"`
+	Post       *openAPIOperation  `yaml:"post"`
+	Delete     *openAPIOperation  `yaml:"delete"`
+	Options    *openAPIOperation  `yaml:"options"`
+	Head       *openAPIOperation  `yaml:"head"`
+	Patch      *openAPIOperation  `yaml:"patch"`
+	Trace      *openAPIOperation  `yaml:"trace"`
+}
+
+// operations returns the path's operations keyed by HTTP method
+func (p openAPIPath) operations() map[string]*openAPIOperation {
+	operations := make(map[string]*openAPIOperation)
+	for method, operation := range map[string]*openAPIOperation{
+		"get": p.Get, "put": p.Put, "post": p.Post, "delete": p.Delete,
+		"options": p.Options, "head": p.Head, "patch": p.Patch, "trace": p.Trace,
+	} {
+		if operation != nil {
+			operations[method] = operation
+		}
+	}
+	return operations
+}
+
+type openAPIOperation struct {
+	OperationID string             `yaml:"operationId"`
+	Summary     string             `yaml:"summary"`
+	Description string             `yaml:"description"`
+	Parameters  []openAPIParameter `yaml:"parameters"`
+}
+
+type openAPIParameter struct {
+	Ref         string         `yaml:"$ref"`
+	Name        string         `yaml:"name"`
+	In          string         `yaml:"in"`
+	Description string         `yaml:"description"`
+	Required    bool           `yaml:"required"`
+	Schema      *openAPISchema `yaml:"schema"`
+}
+
+type openAPISchema struct {
+	Ref     string         `yaml:"$ref"`
+	Type    string         `yaml:"type"`
+	Format  string         `yaml:"format"`
+	Items   *openAPISchema `yaml:"items"`
+	Default interface{}    `yaml:"default"`
+}
+
+// OpenAPIToContract maps an OpenAPI 3.0 document (YAML or JSON) to a contract
+// for a CLI generated from it. Each operation becomes a subcommand of
+// toolName named after its operationId in kebab-case (e.g. listPets becomes
+// list-pets), or after its method and path if it has none. Each query
+// parameter becomes a flag of the matching type, marked required if the
+// parameter is. Other parameters are usually positional arguments or
+// configuration, which contracts do not describe, so they are skipped.
+//
+// If toolName is empty, the kebab-cased info.title is used.
+func OpenAPIToContract(data []byte, toolName string) (*contract.Contract, error) {
+	var spec openAPISpec
+	if err := yaml.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI spec: %w", err)
+	}
+	if !strings.HasPrefix(spec.OpenAPI, "3.") {
+		if spec.OpenAPI == "" {
+			return nil, fmt.Errorf("not an OpenAPI document: missing 'openapi' version field")
+		}
+		return nil, fmt.Errorf("unsupported OpenAPI version %s (supported: 3.x)", spec.OpenAPI)
+	}
+
+	if toolName == "" {
+		toolName = lint.KebabCase(spec.Info.Title)
+	}
+	if toolName == "" {
+		return nil, fmt.Errorf("tool name is required when the spec has no info.title")
+	}
+
+	result := &contract.Contract{
+		Use:   toolName,
+		Short: spec.Info.Title,
+	}
+
+	seen := make(map[string]string)
+	for path, item := range spec.Paths {
+		for method, operation := range item.operations() {
+			command, err := spec.operationToCommand(method, path, item.Parameters, operation)
+			if err != nil {
+				return nil, err
+			}
+			location := strings.ToUpper(method) + " " + path
+			if previous, ok := seen[command.Use]; ok {
+				return nil, fmt.Errorf("operations %s and %s both map to command '%s'", previous, location, command.Use)
+			}
+			seen[command.Use] = location
+			result.Commands = append(result.Commands, command)
+		}
+	}
+
+	sort.Slice(result.Commands, func(i, j int) bool {
+		return result.Commands[i].Use < result.Commands[j].Use
+	})
+	return result, nil
+}
+
+// operationToCommand maps an operation to a subcommand. Operation parameters
+// override path parameters with the same name and location.
+func (s *openAPISpec) operationToCommand(method, path string, pathParameters []openAPIParameter, operation *openAPIOperation) (contract.Command, error) {
+	name := operation.OperationID
+	if name == "" {
+		name = method + " " + strings.NewReplacer("{", "", "}", "").Replace(path)
+	}
+	command := contract.Command{
+		Use:   lint.KebabCase(name),
+		Short: operation.Summary,
+		Long:  operation.Description,
+	}
+	if command.Use == "" {
+		return command, fmt.Errorf("cannot derive a command name for %s %s", strings.ToUpper(method), path)
+	}
+
+	var parameters []openAPIParameter
+	index := make(map[string]int)
+	for _, parameter := range append(append([]openAPIParameter{}, pathParameters...), operation.Parameters...) {
+		resolved, err := s.resolveParameter(parameter)
+		if err != nil {
+			return command, err
+		}
+		key := resolved.In + ":" + resolved.Name
+		if i, ok := index[key]; ok {
+			parameters[i] = resolved
+			continue
+		}
+		index[key] = len(parameters)
+		parameters = append(parameters, resolved)
+	}
+
+	flagNames := make(map[string]string)
+	for _, parameter := range parameters {
+		if parameter.In != "query" {
+			continue
+		}
+		flag, err := s.parameterToFlag(parameter)
+		if err != nil {
+			return command, fmt.Errorf("%s %s: %w", strings.ToUpper(method), path, err)
+		}
+		if previous, ok := flagNames[flag.Name]; ok {
+			return command, fmt.Errorf("%s %s: parameters '%s' and '%s' both map to flag --%s",
+				strings.ToUpper(method), path, previous, parameter.Name, flag.Name)
+		}
+		flagNames[flag.Name] = parameter.Name
+		command.Flags = append(command.Flags, flag)
+	}
+	return command, nil
+}
+
+// parameterToFlag maps a query parameter to a flag
+func (s *openAPISpec) parameterToFlag(parameter openAPIParameter) (contract.Flag, error) {
+	flag := contract.Flag{
+		Name:     lint.KebabCase(parameter.Name),
+		Usage:    parameter.Description,
+		Type:     "string",
+		Required: parameter.Required,
+	}
+	if flag.Name == "" {
+		return flag, fmt.Errorf("query parameter has no name")
+	}
+	if parameter.Schema == nil {
+		return flag, nil
+	}
+
+	schema, err := s.resolveSchema(*parameter.Schema)
+	if err != nil {
+		return flag, err
+	}
+	if schema.Type == "array" {
+		var items openAPISchema
+		if schema.Items != nil {
+			if items, err = s.resolveSchema(*schema.Items); err != nil {
+				return flag, err
+			}
+		}
+		flag.Type = sliceFlagType(items)
+	} else {
+		flag.Type = scalarFlagType(schema)
+	}
+	flag.Default = formatDefault(schema.Default)
+	return flag, nil
+}
+
+// resolveParameter follows a #/components/parameters/ reference
+func (s *openAPISpec) resolveParameter(parameter openAPIParameter) (openAPIParameter, error) {
+	if parameter.Ref == "" {
+		return parameter, nil
+	}
+	name, ok := strings.CutPrefix(parameter.Ref, "#/components/parameters/")
+	if !ok {
+		return parameter, fmt.Errorf("unsupported parameter reference %s (only #/components/parameters/ is supported)", parameter.Ref)
+	}
+	resolved, ok := s.Components.Parameters[name]
+	if !ok {
+		return parameter, fmt.Errorf("parameter reference %s not found", parameter.Ref)
+	}
+	if resolved.Ref != "" {
+		return parameter, fmt.Errorf("nested parameter reference %s is not supported", resolved.Ref)
+	}
+	return resolved, nil
+}
+
+// resolveSchema follows #/components/schemas/ references
+func (s *openAPISpec) resolveSchema(schema openAPISchema) (openAPISchema, error) {
+	seen := make(map[string]bool)
+	for schema.Ref != "" {
+		if seen[schema.Ref] {
+			return schema, fmt.Errorf("circular schema reference %s", schema.Ref)
+		}
+		seen[schema.Ref] = true
+		name, ok := strings.CutPrefix(schema.Ref, "#/components/schemas/")
+		if !ok {
+			return schema, fmt.Errorf("unsupported schema reference %s (only #/components/schemas/ is supported)", schema.Ref)
+		}
+		resolved, ok := s.Components.Schemas[name]
+		if !ok {
+			return schema, fmt.Errorf("schema reference %s not found", schema.Ref)
+		}
+		schema = resolved
+	}
+	return schema, nil
+}
+
+// scalarFlagType maps a schema type and format to the pflag type a generator
+// would use. Unknown types, such as objects, are passed as strings.
+func scalarFlagType(schema openAPISchema) string {
+	switch schema.Type {
+	case "integer":
+		switch schema.Format {
+		case "int32":
+			return "int32"
+		case "int64":
+			return "int64"
+		}
+		return "int"
+	case "number":
+		if schema.Format == "float" {
+			return "float32"
+		}
+		return "float64"
+	case "boolean":
+		return "bool"
+	}
+	return "string"
+}
+
+// sliceFlagType maps an array's item schema to a pflag slice type
+func sliceFlagType(items openAPISchema) string {
+	switch itemType := scalarFlagType(items); itemType {
+	case "int", "int32", "int64", "float32", "float64", "bool":
+		return itemType + "Slice"
+	}
+	return "stringSlice"
+}
+
+// formatDefault formats a schema default the way pflag prints it, using the
+// bracket form for arrays
+func formatDefault(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case []interface{}:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = formatDefault(item)
+		}
+		return "[" + strings.Join(items, ",") + "]"
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
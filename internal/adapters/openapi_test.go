@@ -0,0 +1,191 @@
+package adapters
+
+import (
+	"testing"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/contract"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const petStoreSpec = `openapi: 3.0.3
+info:
+  title: Pet Store
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      summary: List all pets
+      parameters:
+        - name: limit
+          in: query
+          description: How many pets to return
+          required: true
+          schema:
+            type: integer
+            format: int32
+        - name: tags
+          in: query
+          schema:
+            type: array
+            items:
+              type: string
+            default: [cat, dog]
+        - $ref: '#/components/parameters/Verbose'
+    post:
+      operationId: create_pet
+      summary: Create a pet
+  /pets/{petId}:
+    parameters:
+      - name: petId
+        in: path
+        required: true
+        schema:
+          type: string
+    get:
+      summary: Show a pet
+      parameters:
+        - name: maxAge
+          in: query
+          schema:
+            $ref: '#/components/schemas/Age'
+components:
+  parameters:
+    Verbose:
+      name: verbose
+      in: query
+      schema:
+        type: boolean
+        default: false
+  schemas:
+    Age:
+      type: number
+      default: 2.5
+`
+
+func TestOpenAPIToContract(t *testing.T) {
+	c, err := OpenAPIToContract([]byte(petStoreSpec), "petctl")
+	require.NoError(t, err)
+
+	assert.Equal(t, "petctl", c.Use)
+	assert.Equal(t, "Pet Store", c.Short)
+	require.Len(t, c.Commands, 3)
+	assert.Equal(t, "create-pet", c.Commands[0].Use)
+	assert.Equal(t, "get-pets-pet-id", c.Commands[1].Use)
+	assert.Equal(t, "list-pets", c.Commands[2].Use)
+
+	assert.Empty(t, c.Commands[0].Flags)
+	assert.Equal(t, []contract.Flag{
+		{Name: "max-age", Type: "float64", Default: "2.5"},
+	}, c.Commands[1].Flags, "path parameters do not become flags")
+	assert.Equal(t, "List all pets", c.Commands[2].Short)
+	assert.Equal(t, []contract.Flag{
+		{Name: "limit", Usage: "How many pets to return", Type: "int32", Required: true},
+		{Name: "tags", Type: "stringSlice", Default: "[cat,dog]"},
+		{Name: "verbose", Type: "bool", Default: "false"},
+	}, c.Commands[2].Flags)
+}
+
+func TestOpenAPIToContract_JSON(t *testing.T) {
+	spec := `{"openapi": "3.0.0", "info": {"title": "Weather API"}, "paths": {"/forecast": {"get": {"operationId": "getForecast", "parameters": [{"name": "days", "in": "query", "schema": {"type": "integer", "format": "int64"}}]}}}}`
+
+	c, err := OpenAPIToContract([]byte(spec), "")
+	require.NoError(t, err)
+	assert.Equal(t, "weather-api", c.Use, "tool name defaults to the kebab-cased title")
+	require.Len(t, c.Commands, 1)
+	assert.Equal(t, []contract.Flag{{Name: "days", Type: "int64"}}, c.Commands[0].Flags)
+
+	_, err = OpenAPIToContract([]byte(`{"openapi": "3.0.0", "info": {}}`), "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "tool name is required")
+}
+
+func TestOpenAPIToContract_OperationOverridesPathParameter(t *testing.T) {
+	spec := `openapi: 3.0.0
+info: {title: API}
+paths:
+  /items:
+    parameters:
+      - {name: page, in: query, schema: {type: string}}
+    get:
+      operationId: listItems
+      parameters:
+        - {name: page, in: query, required: true, schema: {type: integer}}
+`
+	c, err := OpenAPIToContract([]byte(spec), "api")
+	require.NoError(t, err)
+	assert.Equal(t, []contract.Flag{{Name: "page", Type: "int", Required: true}}, c.Commands[0].Flags)
+}
+
+func TestOpenAPIToContract_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		wantErr string
+	}{
+		{
+			name:    "invalid YAML",
+			spec:    "openapi: [",
+			wantErr: "failed to parse OpenAPI spec",
+		},
+		{
+			name:    "swagger 2.0",
+			spec:    "swagger: '2.0'\ninfo: {title: API}\n",
+			wantErr: "missing 'openapi' version field",
+		},
+		{
+			name:    "OpenAPI 2",
+			spec:    "openapi: 2.0.0\n",
+			wantErr: "unsupported OpenAPI version 2.0.0",
+		},
+		{
+			name: "duplicate commands",
+			spec: `openapi: 3.0.0
+paths:
+  /a: {get: {operationId: listItems}}
+  /b: {get: {operationId: list_items}}
+`,
+			wantErr: "both map to command 'list-items'",
+		},
+		{
+			name: "duplicate flags",
+			spec: `openapi: 3.0.0
+paths:
+  /a:
+    get:
+      parameters:
+        - {name: pageSize, in: query}
+        - {name: page_size, in: query}
+`,
+			wantErr: "both map to flag --page-size",
+		},
+		{
+			name: "missing reference",
+			spec: `openapi: 3.0.0
+paths:
+  /a: {get: {parameters: [{$ref: '#/components/parameters/Nope'}]}}
+`,
+			wantErr: "parameter reference #/components/parameters/Nope not found",
+		},
+		{
+			name: "circular schema",
+			spec: `openapi: 3.0.0
+paths:
+  /a: {get: {parameters: [{name: x, in: query, schema: {$ref: '#/components/schemas/A'}}]}}
+components:
+  schemas:
+    A: {$ref: '#/components/schemas/B'}
+    B: {$ref: '#/components/schemas/A'}
+`,
+			wantErr: "circular schema reference",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := OpenAPIToContract([]byte(tt.spec), "api")
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
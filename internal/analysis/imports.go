@@ -0,0 +1,683 @@
+// Package analysis statically analyzes the Go source of a Cobra CLI, to
+// relate the commands of its contract to the code that defines them.
+//
+// Example:
+//
+//	sources, err := analysis.FindCommandSources(".", "github.com/org/repo/cmd.NewRootCmd", "db migrate")
+//	if err != nil {
+//	    return err
+//	}
+//	for _, file := range sources.Files {
+//	    fmt.Printf("%s (%s)\n", file.Path, strings.Join(file.Roles, ", "))
+//	}
+package analysis
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/discovery"
+)
+
+// cobraImportPath is the import path of the Cobra package
+const cobraImportPath = "github.com/spf13/cobra"
+
+// Roles of a source file, as listed in SourceFile.Roles
+const (
+	// RoleCommand is the file with the cobra.Command literal of the command
+	RoleCommand = "command"
+	// RoleFlags is a file that defines or configures the command's flags
+	RoleFlags = "flags"
+	// RoleRegistration is the file that adds the command to its parent
+	RoleRegistration = "registration"
+)
+
+// Sources are the Go files that contribute to a command, as found by
+// FindCommandSources
+type Sources struct {
+	// Command is the full path of the command, e.g. "myapp db migrate"
+	Command string
+
+	// Files are the files that define the command, its flags and its
+	// registration with its parent, sorted by path
+	Files []SourceFile
+}
+
+// SourceFile is a Go file that contributes to a command
+type SourceFile struct {
+	// Path is the slash-separated path of the file, relative to the project
+	Path string
+
+	// Package is the import path of the file's package
+	Package string
+
+	// Roles are what the file contributes: RoleCommand, RoleFlags or
+	// RoleRegistration, in that order
+	Roles []string
+}
+
+// Packages returns the import paths of the files' packages, sorted and
+// without duplicates
+func (s *Sources) Packages() []string {
+	seen := make(map[string]bool)
+	var packages []string
+	for _, file := range s.Files {
+		if !seen[file.Package] {
+			seen[file.Package] = true
+			packages = append(packages, file.Package)
+		}
+	}
+	sort.Strings(packages)
+	return packages
+}
+
+// FindCommandSources finds the Go files in the project that contribute to
+// the command at commandPath, the names of the commands leading to it
+// below the root command separated by spaces, e.g. "db migrate". An empty
+// path is the root command, and the root command's name may lead the path.
+//
+// The command tree is traced from the function named by entrypoint, such as
+// github.com/org/repo/cmd.NewRootCmd, without building the project: the
+// source is parsed and AddCommand calls are followed from the cobra.Command
+// literal the function returns. Commands are found when they are assigned to
+// variables, returned by constructor functions in the project, or passed to
+// AddCommand directly; a flag belongs to a command when it is defined
+// through the command's Flags() or PersistentFlags(), directly or through a
+// variable holding the flag set. Commands built at runtime, such as in loops
+// or by methods, are not found.
+func FindCommandSources(projectPath, entrypoint, commandPath string) (*Sources, error) {
+	importPath, function, err := splitEntrypoint(entrypoint)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &analyzer{
+		modulePath: modulePath(projectPath),
+		literals:   make(map[*ast.CompositeLit]*commandDef),
+		vars:       make(map[scopedName]*commandDef),
+		ctors:      make(map[scopedName]*commandDef),
+		returned:   make(map[scopedName]string),
+	}
+	var files []*sourceFile
+	err = discovery.WalkGoFiles(projectPath, func(path string, node *ast.File) error {
+		rel, err := filepath.Rel(projectPath, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, a.newSourceFile(filepath.ToSlash(rel), node))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read project source: %w", err)
+	}
+
+	for _, file := range files {
+		a.collectCommands(file)
+	}
+	a.resolveReturns()
+	for _, file := range files {
+		a.collectUses(file)
+	}
+
+	root := a.ctors[scopedName{dir: a.packageDir(importPath), name: function}]
+	if root == nil {
+		return nil, fmt.Errorf("no cobra.Command returned by %s was found in the project source", entrypoint)
+	}
+	return root.sources(a, commandPath)
+}
+
+// commandDef is a cobra.Command literal in the project source
+type commandDef struct {
+	// name is the first word of the literal's Use, empty if it isn't constant
+	name string
+	file *sourceFile
+
+	children []registration
+	// flagFiles are the files that define the command's flags, in the order
+	// found
+	flagFiles []*sourceFile
+}
+
+// registration is a command added to its parent with AddCommand in file
+type registration struct {
+	cmd  *commandDef
+	file *sourceFile
+}
+
+// sources walks commandPath down from the root command c and returns the
+// files of the command found
+func (c *commandDef) sources(a *analyzer, commandPath string) (*Sources, error) {
+	names := strings.Fields(commandPath)
+	if len(names) > 0 && names[0] == c.name && c.child(names[0]) == nil {
+		names = names[1:]
+	}
+
+	cmd := c
+	var registeredIn *sourceFile
+	fullPath := []string{c.name}
+	for _, name := range names {
+		child := cmd.child(name)
+		if child == nil {
+			return nil, fmt.Errorf("command '%s' not found under '%s' in the project source", name, strings.Join(fullPath, " "))
+		}
+		cmd, registeredIn = child.cmd, child.file
+		fullPath = append(fullPath, name)
+	}
+
+	roles := make(map[*sourceFile][]string)
+	var order []*sourceFile
+	addRole := func(file *sourceFile, role string) {
+		if _, found := roles[file]; !found {
+			order = append(order, file)
+		}
+		for _, r := range roles[file] {
+			if r == role {
+				return
+			}
+		}
+		roles[file] = append(roles[file], role)
+	}
+	addRole(cmd.file, RoleCommand)
+	for _, file := range cmd.flagFiles {
+		addRole(file, RoleFlags)
+	}
+	if registeredIn != nil {
+		addRole(registeredIn, RoleRegistration)
+	}
+
+	result := &Sources{Command: strings.TrimSpace(strings.Join(fullPath, " "))}
+	for _, file := range order {
+		result.Files = append(result.Files, SourceFile{
+			Path:    file.path,
+			Package: a.importPath(file.dir),
+			Roles:   roles[file],
+		})
+	}
+	sort.Slice(result.Files, func(i, j int) bool {
+		return result.Files[i].Path < result.Files[j].Path
+	})
+	return result, nil
+}
+
+// child returns the first subcommand registered with the name
+func (c *commandDef) child(name string) *registration {
+	for i := range c.children {
+		if c.children[i].cmd.name == name {
+			return &c.children[i]
+		}
+	}
+	return nil
+}
+
+// sourceFile is a parsed Go file of the project
+type sourceFile struct {
+	// path is slash-separated and relative to the project, and dir is its
+	// directory, "." for the project root
+	path string
+	dir  string
+	node *ast.File
+
+	// cobraName is the name Cobra is imported under, empty if it isn't
+	cobraName string
+	// imports maps import names to the directories of the project's
+	// packages they import
+	imports map[string]string
+}
+
+// scopedName names a variable or function. Package-level names have an
+// empty function.
+type scopedName struct {
+	dir      string
+	function string
+	name     string
+}
+
+// analyzer indexes the commands of a project
+type analyzer struct {
+	modulePath string
+
+	literals map[*ast.CompositeLit]*commandDef
+	// vars maps variables to the commands assigned to them
+	vars map[scopedName]*commandDef
+	// ctors maps functions, with an empty scopedName.function, to the
+	// command they return
+	ctors map[scopedName]*commandDef
+	// returned maps functions to the variable they return, until it is
+	// resolved to a command
+	returned map[scopedName]string
+}
+
+// newSourceFile returns the file with its imports resolved
+func (a *analyzer) newSourceFile(path string, node *ast.File) *sourceFile {
+	file := &sourceFile{path: path, dir: filepath.ToSlash(filepath.Dir(path)), node: node, imports: make(map[string]string)}
+	for _, imp := range node.Imports {
+		p, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			continue
+		}
+		name := p[strings.LastIndex(p, "/")+1:]
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		if p == cobraImportPath {
+			file.cobraName = name
+		} else if dir, ok := a.projectDir(p); ok {
+			file.imports[name] = dir
+		}
+	}
+	return file
+}
+
+// collectCommands indexes the command literals of the file, with the
+// variables they are assigned to and the functions that return them
+func (a *analyzer) collectCommands(file *sourceFile) {
+	for _, decl := range file.node.Decls {
+		switch decl := decl.(type) {
+		case *ast.GenDecl:
+			a.collectValueSpecs(file, "", decl)
+		case *ast.FuncDecl:
+			if decl.Recv != nil || decl.Body == nil {
+				continue
+			}
+			a.collectFunction(file, decl)
+		}
+	}
+}
+
+// collectValueSpecs indexes the commands assigned in a var declaration,
+// in function or at package level if function is empty
+func (a *analyzer) collectValueSpecs(file *sourceFile, function string, decl *ast.GenDecl) {
+	if decl.Tok != token.VAR {
+		return
+	}
+	for _, spec := range decl.Specs {
+		vs, ok := spec.(*ast.ValueSpec)
+		if !ok || len(vs.Names) != len(vs.Values) {
+			continue
+		}
+		for i, name := range vs.Names {
+			if cmd := a.literal(file, vs.Values[i]); cmd != nil {
+				a.vars[scopedName{dir: file.dir, function: function, name: name.Name}] = cmd
+			}
+		}
+	}
+}
+
+// collectFunction indexes the commands assigned to variables in the
+// function, and the command it returns
+func (a *analyzer) collectFunction(file *sourceFile, fn *ast.FuncDecl) {
+	function := fn.Name.Name
+	local := localNames(fn)
+
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			// Commands returned by closures aren't the function's
+			ast.Inspect(n.Body, func(n ast.Node) bool {
+				if assign, ok := n.(*ast.AssignStmt); ok {
+					a.collectAssign(file, function, local, assign)
+				}
+				return true
+			})
+			return false
+		case *ast.DeclStmt:
+			if decl, ok := n.Decl.(*ast.GenDecl); ok {
+				a.collectValueSpecs(file, function, decl)
+			}
+		case *ast.AssignStmt:
+			a.collectAssign(file, function, local, n)
+		case *ast.ReturnStmt:
+			if len(n.Results) != 1 {
+				return true
+			}
+			key := scopedName{dir: file.dir, name: function}
+			if cmd := a.literal(file, n.Results[0]); cmd != nil {
+				a.ctors[key] = cmd
+			} else if ident, ok := n.Results[0].(*ast.Ident); ok {
+				a.returned[key] = ident.Name
+			}
+		}
+		return true
+	})
+}
+
+// collectAssign indexes the commands assigned in function. Assignments to
+// names not declared in the function are to package-level variables.
+func (a *analyzer) collectAssign(file *sourceFile, function string, local map[string]bool, assign *ast.AssignStmt) {
+	if len(assign.Lhs) != len(assign.Rhs) {
+		return
+	}
+	for i, lhs := range assign.Lhs {
+		ident, ok := lhs.(*ast.Ident)
+		if !ok {
+			continue
+		}
+		cmd := a.literal(file, assign.Rhs[i])
+		if cmd == nil {
+			continue
+		}
+		scope := function
+		if assign.Tok != token.DEFINE && !local[ident.Name] {
+			scope = ""
+		}
+		a.vars[scopedName{dir: file.dir, function: scope, name: ident.Name}] = cmd
+	}
+}
+
+// resolveReturns resolves the functions that return a variable holding a
+// command
+func (a *analyzer) resolveReturns() {
+	for key, name := range a.returned {
+		if _, found := a.ctors[key]; found {
+			continue
+		}
+		if cmd := a.variable(key.dir, key.name, name); cmd != nil {
+			a.ctors[key] = cmd
+		}
+	}
+}
+
+// collectUses records the AddCommand calls and flag definitions of the
+// file's functions
+func (a *analyzer) collectUses(file *sourceFile) {
+	for _, decl := range file.node.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv != nil || fn.Body == nil {
+			continue
+		}
+		function := fn.Name.Name
+		// flagSets maps variables holding a command's flag set, such as
+		// flags := cmd.Flags(), to the command
+		flagSets := make(map[string]*commandDef)
+
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.AssignStmt:
+				if len(n.Lhs) != 1 || len(n.Rhs) != 1 {
+					return true
+				}
+				ident, ok := n.Lhs[0].(*ast.Ident)
+				if !ok {
+					return true
+				}
+				if cmd := a.flagSetOwner(file, function, n.Rhs[0]); cmd != nil {
+					flagSets[ident.Name] = cmd
+				}
+			case *ast.CallExpr:
+				sel, ok := n.Fun.(*ast.SelectorExpr)
+				if !ok {
+					return true
+				}
+				if sel.Sel.Name == "AddCommand" {
+					a.collectAddCommand(file, function, sel.X, n.Args)
+					return true
+				}
+				if isFlagMethod(sel.Sel.Name) {
+					if cmd := a.resolve(file, function, sel.X); cmd != nil {
+						cmd.addFlagFile(file)
+					}
+					return true
+				}
+				owner := a.flagSetOwner(file, function, sel.X)
+				if ident, ok := sel.X.(*ast.Ident); ok && owner == nil {
+					owner = flagSets[ident.Name]
+				}
+				if owner != nil {
+					owner.addFlagFile(file)
+				}
+			}
+			return true
+		})
+	}
+}
+
+// collectAddCommand records parent.AddCommand(args...) in file
+func (a *analyzer) collectAddCommand(file *sourceFile, function string, parent ast.Expr, args []ast.Expr) {
+	parentCmd := a.resolve(file, function, parent)
+	if parentCmd == nil {
+		return
+	}
+	for _, arg := range args {
+		if child := a.resolve(file, function, arg); child != nil {
+			parentCmd.children = append(parentCmd.children, registration{cmd: child, file: file})
+		}
+	}
+}
+
+// addFlagFile records that file defines some of the command's flags
+func (c *commandDef) addFlagFile(file *sourceFile) {
+	for _, f := range c.flagFiles {
+		if f == file {
+			return
+		}
+	}
+	c.flagFiles = append(c.flagFiles, file)
+}
+
+// flagSetOwner returns the command of an expression such as cmd.Flags()
+// or cmd.PersistentFlags()
+func (a *analyzer) flagSetOwner(file *sourceFile, function string, expr ast.Expr) *commandDef {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) != 0 {
+		return nil
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return nil
+	}
+	switch sel.Sel.Name {
+	case "Flags", "PersistentFlags", "LocalFlags", "InheritedFlags":
+		return a.resolve(file, function, sel.X)
+	}
+	return nil
+}
+
+// isFlagMethod reports whether a cobra.Command method configures flags
+func isFlagMethod(name string) bool {
+	switch name {
+	case "MarkFlagRequired", "MarkPersistentFlagRequired", "MarkFlagFilename", "MarkFlagDirname",
+		"MarkFlagsMutuallyExclusive", "MarkFlagsRequiredTogether", "MarkFlagsOneRequired",
+		"RegisterFlagCompletionFunc":
+		return true
+	}
+	return false
+}
+
+// resolve returns the command an expression in function refers to: a
+// command literal, a variable holding one, or a call to a function
+// returning one
+func (a *analyzer) resolve(file *sourceFile, function string, expr ast.Expr) *commandDef {
+	if cmd := a.literal(file, expr); cmd != nil {
+		return cmd
+	}
+	switch expr := expr.(type) {
+	case *ast.ParenExpr:
+		return a.resolve(file, function, expr.X)
+	case *ast.Ident:
+		return a.variable(file.dir, function, expr.Name)
+	case *ast.SelectorExpr:
+		if pkg, ok := expr.X.(*ast.Ident); ok {
+			if dir, ok := file.imports[pkg.Name]; ok {
+				return a.vars[scopedName{dir: dir, name: expr.Sel.Name}]
+			}
+		}
+	case *ast.CallExpr:
+		switch fun := expr.Fun.(type) {
+		case *ast.Ident:
+			return a.ctors[scopedName{dir: file.dir, name: fun.Name}]
+		case *ast.SelectorExpr:
+			if pkg, ok := fun.X.(*ast.Ident); ok {
+				if dir, ok := file.imports[pkg.Name]; ok {
+					return a.ctors[scopedName{dir: dir, name: fun.Sel.Name}]
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// variable returns the command held by the variable name in function,
+// or by the package-level variable if the function has none
+func (a *analyzer) variable(dir, function, name string) *commandDef {
+	if cmd := a.vars[scopedName{dir: dir, function: function, name: name}]; cmd != nil {
+		return cmd
+	}
+	return a.vars[scopedName{dir: dir, name: name}]
+}
+
+// literal returns the command of a cobra.Command literal, or of its
+// address, or nil if expr is neither
+func (a *analyzer) literal(file *sourceFile, expr ast.Expr) *commandDef {
+	if unary, ok := expr.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+		expr = unary.X
+	}
+	lit, ok := expr.(*ast.CompositeLit)
+	if !ok {
+		return nil
+	}
+	sel, ok := lit.Type.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Command" {
+		return nil
+	}
+	if pkg, ok := sel.X.(*ast.Ident); !ok || file.cobraName == "" || pkg.Name != file.cobraName {
+		return nil
+	}
+
+	if cmd, found := a.literals[lit]; found {
+		return cmd
+	}
+	cmd := &commandDef{name: literalName(lit), file: file}
+	a.literals[lit] = cmd
+	return cmd
+}
+
+// literalName returns the first word of a command literal's Use, or "" if
+// it isn't constant
+func literalName(lit *ast.CompositeLit) string {
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		if key, ok := kv.Key.(*ast.Ident); !ok || key.Name != "Use" {
+			continue
+		}
+		value, ok := kv.Value.(*ast.BasicLit)
+		if !ok || value.Kind != token.STRING {
+			return ""
+		}
+		use, err := strconv.Unquote(value.Value)
+		if err != nil {
+			return ""
+		}
+		if fields := strings.Fields(use); len(fields) > 0 {
+			return fields[0]
+		}
+	}
+	return ""
+}
+
+// localNames returns the names the function declares: its parameters,
+// results and the variables it defines
+func localNames(fn *ast.FuncDecl) map[string]bool {
+	local := make(map[string]bool)
+	for _, list := range []*ast.FieldList{fn.Type.Params, fn.Type.Results} {
+		if list == nil {
+			continue
+		}
+		for _, field := range list.List {
+			for _, name := range field.Names {
+				local[name.Name] = true
+			}
+		}
+	}
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			if n.Tok != token.DEFINE {
+				return true
+			}
+			for _, lhs := range n.Lhs {
+				if ident, ok := lhs.(*ast.Ident); ok {
+					local[ident.Name] = true
+				}
+			}
+		case *ast.ValueSpec:
+			for _, name := range n.Names {
+				local[name.Name] = true
+			}
+		}
+		return true
+	})
+	return local
+}
+
+// splitEntrypoint splits an entrypoint such as
+// github.com/org/repo/cmd.NewRootCmd into its import path and function
+func splitEntrypoint(entrypoint string) (string, string, error) {
+	i := strings.LastIndex(entrypoint, ".")
+	if i <= 0 || i == len(entrypoint)-1 || strings.HasSuffix(entrypoint[:i], "/") {
+		return "", "", fmt.Errorf("invalid entrypoint '%s': expected a package path and function, e.g. github.com/org/repo/cmd.NewRootCmd", entrypoint)
+	}
+	return entrypoint[:i], entrypoint[i+1:], nil
+}
+
+// projectDir returns the directory of a package of the project, given its
+// import path
+func (a *analyzer) projectDir(importPath string) (string, bool) {
+	if a.modulePath == "" {
+		return "", false
+	}
+	if importPath == a.modulePath {
+		return ".", true
+	}
+	if rest, ok := strings.CutPrefix(importPath, a.modulePath+"/"); ok {
+		return rest, true
+	}
+	return "", false
+}
+
+// packageDir returns the directory of the entrypoint's package. Besides
+// import paths of the module, "main" is the project root and other paths
+// are taken to be directories relative to it, e.g. "cmd".
+func (a *analyzer) packageDir(importPath string) string {
+	if dir, ok := a.projectDir(importPath); ok {
+		return dir
+	}
+	if importPath == "main" {
+		return "."
+	}
+	return importPath
+}
+
+// importPath returns the import path of the package in dir
+func (a *analyzer) importPath(dir string) string {
+	if a.modulePath == "" {
+		return dir
+	}
+	if dir == "." {
+		return a.modulePath
+	}
+	return a.modulePath + "/" + dir
+}
+
+// modulePath returns the module path in the go.mod of the project, or "" if
+// it can't be read
+func modulePath(projectPath string) string {
+	data, err := os.ReadFile(filepath.Join(projectPath, "go.mod"))
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if fields := strings.Fields(line); len(fields) >= 2 && fields[0] == "module" {
+			return strings.Trim(fields[1], `"`)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,148 @@
+package analysis
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeProject writes the files of a project with module example.com/app
+func writeProject(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	files["go.mod"] = "module example.com/app\n\ngo 1.24\n"
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	return dir
+}
+
+func TestFindCommandSources(t *testing.T) {
+	projectDir := writeProject(t, map[string]string{
+		"cmd/root.go": `package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"example.com/app/cmd/db"
+)
+
+func NewRootCmd() *cobra.Command {
+	root := &cobra.Command{Use: "app", Short: "App"}
+	root.PersistentFlags().String("config", "", "Config file")
+	root.AddCommand(db.NewDBCmd(), newServeCmd())
+	root.AddCommand(&cobra.Command{Use: "version", Short: "Print the version"})
+	return root
+}
+`,
+		"cmd/serve.go": `package cmd
+
+import "github.com/spf13/cobra"
+
+func newServeCmd() *cobra.Command {
+	return &cobra.Command{Use: "serve", Short: "Serve"}
+}
+`,
+		"cmd/db/db.go": `package db
+
+import "github.com/spf13/cobra"
+
+func NewDBCmd() *cobra.Command {
+	cmd := &cobra.Command{Use: "db", Short: "Database"}
+	cmd.AddCommand(migrateCmd)
+	return cmd
+}
+`,
+		"cmd/db/migrate.go": `package db
+
+import "github.com/spf13/cobra"
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate [version]",
+	Short: "Run migrations",
+}
+
+func init() {
+	flags := migrateCmd.Flags()
+	flags.Bool("dry-run", false, "Print the migrations")
+}
+`,
+		"cmd/db/migrate_flags.go": `package db
+
+func init() {
+	migrateCmd.Flags().Int("steps", 0, "Steps to run")
+	_ = migrateCmd.MarkFlagRequired("steps")
+}
+`,
+		"cmd/db/migrate_test.go": `package db
+
+func init() {
+	migrateCmd.Flags().Bool("test-only", false, "")
+}
+`,
+		"internal/other/other.go": `package other
+
+import "github.com/spf13/cobra"
+
+var migrateCmd = &cobra.Command{Use: "migrate"}
+`,
+	})
+
+	t.Run("subcommand in another package", func(t *testing.T) {
+		sources, err := FindCommandSources(projectDir, "example.com/app/cmd.NewRootCmd", "db migrate")
+		require.NoError(t, err)
+		assert.Equal(t, "app db migrate", sources.Command)
+		assert.Equal(t, []SourceFile{
+			{Path: "cmd/db/db.go", Package: "example.com/app/cmd/db", Roles: []string{RoleRegistration}},
+			{Path: "cmd/db/migrate.go", Package: "example.com/app/cmd/db", Roles: []string{RoleCommand, RoleFlags}},
+			{Path: "cmd/db/migrate_flags.go", Package: "example.com/app/cmd/db", Roles: []string{RoleFlags}},
+		}, sources.Files)
+		assert.Equal(t, []string{"example.com/app/cmd/db"}, sources.Packages())
+	})
+
+	t.Run("constructor in the same package", func(t *testing.T) {
+		sources, err := FindCommandSources(projectDir, "example.com/app/cmd.NewRootCmd", "app serve")
+		require.NoError(t, err)
+		assert.Equal(t, []SourceFile{
+			{Path: "cmd/root.go", Package: "example.com/app/cmd", Roles: []string{RoleRegistration}},
+			{Path: "cmd/serve.go", Package: "example.com/app/cmd", Roles: []string{RoleCommand}},
+		}, sources.Files)
+	})
+
+	t.Run("literal passed to AddCommand", func(t *testing.T) {
+		sources, err := FindCommandSources(projectDir, "example.com/app/cmd.NewRootCmd", "version")
+		require.NoError(t, err)
+		assert.Equal(t, []SourceFile{
+			{Path: "cmd/root.go", Package: "example.com/app/cmd", Roles: []string{RoleCommand, RoleRegistration}},
+		}, sources.Files)
+	})
+
+	t.Run("root command", func(t *testing.T) {
+		sources, err := FindCommandSources(projectDir, "cmd.NewRootCmd", "")
+		require.NoError(t, err)
+		assert.Equal(t, "app", sources.Command)
+		assert.Equal(t, []SourceFile{
+			{Path: "cmd/root.go", Package: "example.com/app/cmd", Roles: []string{RoleCommand, RoleFlags}},
+		}, sources.Files)
+	})
+
+	t.Run("unknown command", func(t *testing.T) {
+		_, err := FindCommandSources(projectDir, "example.com/app/cmd.NewRootCmd", "db seed")
+		assert.EqualError(t, err, "command 'seed' not found under 'app db' in the project source")
+	})
+
+	t.Run("unknown entrypoint", func(t *testing.T) {
+		_, err := FindCommandSources(projectDir, "example.com/app/cmd.NewCLI", "db")
+		assert.EqualError(t, err, "no cobra.Command returned by example.com/app/cmd.NewCLI was found in the project source")
+	})
+
+	t.Run("invalid entrypoint", func(t *testing.T) {
+		_, err := FindCommandSources(projectDir, "NewRootCmd", "db")
+		assert.ErrorContains(t, err, "invalid entrypoint 'NewRootCmd'")
+	})
+}
@@ -0,0 +1,172 @@
+// Package audit scans cliguard contracts for security and documentation
+// anti-patterns, such as secrets passed as plain string flags.
+//
+// Example:
+//
+//	c, err := contract.Load("cliguard.yaml")
+//	if err != nil {
+//	    return err
+//	}
+//	for _, finding := range audit.Audit(c) {
+//	    fmt.Printf("[%s] %s: %s\n", finding.Severity, finding.Path, finding.Message)
+//	}
+package audit
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/contract"
+)
+
+// Severity indicates how serious a finding is
+type Severity string
+
+const (
+	SeverityHigh   Severity = "high"
+	SeverityMedium Severity = "medium"
+	SeverityLow    Severity = "low"
+	SeverityInfo   Severity = "info"
+)
+
+// Rule identifies the check that produced a finding
+type Rule string
+
+const (
+	RuleSecretInArgs       Rule = "secret-in-args"
+	RuleMissingUsage       Rule = "missing-usage"
+	RuleNoFlags            Rule = "no-flags"
+	RuleShouldBePersistent Rule = "should-be-persistent"
+)
+
+// Finding is a single audit result
+type Finding struct {
+	Rule           Rule
+	Severity       Severity
+	Path           string
+	Message        string
+	Recommendation string
+}
+
+// secretWords are flag name components that suggest the value is a secret
+var secretWords = map[string]bool{
+	"password":   true,
+	"passwd":     true,
+	"token":      true,
+	"secret":     true,
+	"key":        true,
+	"apikey":     true,
+	"credential": true,
+}
+
+// globalFlagNames are flags that usually apply to every subcommand
+var globalFlagNames = map[string]bool{
+	"verbose": true,
+	"debug":   true,
+}
+
+// Audit checks the contract for security and documentation anti-patterns and
+// returns the findings in contract order: root flags, then each command
+// depth-first.
+func Audit(c *contract.Contract) []Finding {
+	var findings []Finding
+
+	// Persistence only matters for root flags when there are subcommands
+	findings = append(findings, auditFlags(c.Use, c.Flags, len(c.Commands) > 0)...)
+	for _, cmd := range c.Commands {
+		findings = append(findings, auditCommand(c.Use, cmd)...)
+	}
+
+	return findings
+}
+
+// auditCommand checks a subcommand and its children
+func auditCommand(parentPath string, cmd contract.Command) []Finding {
+	var findings []Finding
+	path := contract.JoinPath(parentPath, cmd.Use)
+
+	// Commands that only group subcommands commonly have no flags
+	if len(cmd.Flags) == 0 && len(cmd.Commands) == 0 {
+		findings = append(findings, Finding{
+			Rule:           RuleNoFlags,
+			Severity:       SeverityInfo,
+			Path:           path,
+			Message:        "Command defines no flags",
+			Recommendation: "Confirm the contract is complete; add the command's flags if it has any",
+		})
+	}
+
+	findings = append(findings, auditFlags(path, cmd.Flags, false)...)
+	for _, sub := range cmd.Commands {
+		findings = append(findings, auditCommand(path, sub)...)
+	}
+
+	return findings
+}
+
+// auditFlags checks the flags defined on a single command
+func auditFlags(cmdPath string, flags []contract.Flag, checkPersistence bool) []Finding {
+	var findings []Finding
+
+	for _, flag := range flags {
+		path := contract.JoinPath(cmdPath, "--"+flag.Name)
+
+		if flag.Type == "string" && isSecretName(flag.Name) {
+			findings = append(findings, Finding{
+				Rule:     RuleSecretInArgs,
+				Severity: SeverityHigh,
+				Path:     path,
+				Message:  fmt.Sprintf("Flag '%s' appears to take a secret as a plain string argument", flag.Name),
+				Recommendation: "Command-line arguments are visible to other users via `ps`; " +
+					"read the secret from an environment variable, a file, or stdin instead",
+			})
+		}
+
+		if strings.TrimSpace(flag.Usage) == "" {
+			findings = append(findings, Finding{
+				Rule:           RuleMissingUsage,
+				Severity:       SeverityLow,
+				Path:           path,
+				Message:        fmt.Sprintf("Flag '%s' has no usage description", flag.Name),
+				Recommendation: "Add a usage string so the flag is documented in --help",
+			})
+		}
+
+		if checkPersistence && !flag.Persistent && globalFlagNames[flag.Name] {
+			findings = append(findings, Finding{
+				Rule:           RuleShouldBePersistent,
+				Severity:       SeverityMedium,
+				Path:           path,
+				Message:        fmt.Sprintf("Flag '%s' is local to the root command", flag.Name),
+				Recommendation: "Define it with PersistentFlags() so subcommands accept it too",
+			})
+		}
+	}
+
+	return findings
+}
+
+// referenceSuffixes mark flags that name where a secret lives rather than the
+// secret itself, e.g. "--password-file"
+var referenceSuffixes = map[string]bool{
+	"file": true,
+	"path": true,
+	"env":  true,
+}
+
+// isSecretName reports whether any component of the flag name suggests a secret,
+// e.g. "password", "api-key" or "github_token"
+func isSecretName(name string) bool {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return r == '-' || r == '_' || r == '.'
+	})
+	if len(words) == 0 || referenceSuffixes[words[len(words)-1]] {
+		return false
+	}
+	for _, word := range words {
+		if secretWords[word] {
+			return true
+		}
+	}
+	return secretWords[strings.Join(words, "")]
+}
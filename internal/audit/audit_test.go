@@ -0,0 +1,132 @@
+package audit
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/contract"
+)
+
+func TestAudit(t *testing.T) {
+	tests := []struct {
+		name     string
+		contract *contract.Contract
+		want     []Finding
+	}{
+		{
+			name: "clean contract",
+			contract: &contract.Contract{
+				Use: "app",
+				Flags: []contract.Flag{
+					{Name: "verbose", Type: "bool", Usage: "Verbose output", Persistent: true},
+				},
+				Commands: []contract.Command{
+					{Use: "serve", Flags: []contract.Flag{{Name: "port", Type: "int", Usage: "Port"}}},
+				},
+			},
+			want: nil,
+		},
+		{
+			name: "secret passed as string",
+			contract: &contract.Contract{
+				Use: "app",
+				Flags: []contract.Flag{
+					{Name: "api-key", Type: "string", Usage: "API key"},
+					{Name: "password-file", Type: "string", Usage: "File containing the password"},
+					{Name: "keyboard", Type: "string", Usage: "Keyboard layout"},
+					{Name: "token", Type: "bool", Usage: "Print a token"},
+				},
+			},
+			want: []Finding{
+				{Rule: RuleSecretInArgs, Severity: SeverityHigh, Path: "app --api-key"},
+			},
+		},
+		{
+			name: "missing usage",
+			contract: &contract.Contract{
+				Use:   "app",
+				Flags: []contract.Flag{{Name: "output", Type: "string"}},
+			},
+			want: []Finding{
+				{Rule: RuleMissingUsage, Severity: SeverityLow, Path: "app --output"},
+			},
+		},
+		{
+			name: "leaf command without flags",
+			contract: &contract.Contract{
+				Use: "app",
+				Commands: []contract.Command{
+					{Use: "db", Commands: []contract.Command{{Use: "migrate [version]"}}},
+				},
+			},
+			want: []Finding{
+				{Rule: RuleNoFlags, Severity: SeverityInfo, Path: "app db migrate [version]"},
+			},
+		},
+		{
+			name: "local verbose flag on root with subcommands",
+			contract: &contract.Contract{
+				Use: "app",
+				Flags: []contract.Flag{
+					{Name: "verbose", Type: "bool", Usage: "Verbose output"},
+					{Name: "debug", Type: "bool", Usage: "Debug output", Persistent: true},
+				},
+				Commands: []contract.Command{
+					{Use: "serve", Flags: []contract.Flag{{Name: "port", Type: "int", Usage: "Port"}}},
+				},
+			},
+			want: []Finding{
+				{Rule: RuleShouldBePersistent, Severity: SeverityMedium, Path: "app --verbose"},
+			},
+		},
+		{
+			name: "local verbose flag without subcommands",
+			contract: &contract.Contract{
+				Use:   "app",
+				Flags: []contract.Flag{{Name: "verbose", Type: "bool", Usage: "Verbose output"}},
+			},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Audit(tt.contract)
+
+			// Compare only the identifying fields; messages are free text
+			var summary []Finding
+			for _, f := range got {
+				if f.Message == "" || f.Recommendation == "" {
+					t.Errorf("finding %s at %s has empty message or recommendation", f.Rule, f.Path)
+				}
+				summary = append(summary, Finding{Rule: f.Rule, Severity: f.Severity, Path: f.Path})
+			}
+			if !reflect.DeepEqual(summary, tt.want) {
+				t.Errorf("Audit() = %+v, want %+v", summary, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsSecretName(t *testing.T) {
+	tests := map[string]bool{
+		"password":      true,
+		"db-password":   true,
+		"github_token":  true,
+		"client.secret": true,
+		"api-key":       true,
+		"apikey":        true,
+		"API_KEY":       true,
+		"key":           true,
+		"keyboard":      false,
+		"password-file": false,
+		"token_path":    false,
+		"tokenizer":     false,
+		"config":        false,
+	}
+	for name, want := range tests {
+		if got := isSecretName(name); got != want {
+			t.Errorf("isSecretName(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,282 @@
+// Package benchmark measures how long each phase of inspecting and
+// validating a project takes, to find which step slows down a CI pipeline.
+//
+// Like a testing.B benchmark, Run repeats the whole cycle a number of times
+// and summarizes the time of each phase over all iterations.
+//
+// Example:
+//
+//	result, err := benchmark.Run(benchmark.Options{
+//	    ProjectPath: ".",
+//	    Entrypoint:  "github.com/org/repo/cmd.NewRootCmd",
+//	    Iterations:  5,
+//	})
+//	if err != nil {
+//	    return err
+//	}
+//	fmt.Print(result.FormatMarkdown())
+package benchmark
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/contract"
+	"github.com/hiAndrewQuinn/cliguard/internal/errors"
+	"github.com/hiAndrewQuinn/cliguard/internal/inspector"
+	"github.com/hiAndrewQuinn/cliguard/internal/validator"
+)
+
+// DefaultIterations is the number of iterations run when none is given
+const DefaultIterations = 5
+
+// PhaseValidation compares the inspected CLI with the contract. It is only
+// measured when Options.ContractPath is set.
+const PhaseValidation = "validation"
+
+// phases lists the phases in the order they run, which is the order they
+// are reported in
+var phases = []string{inspector.PhaseBuild, inspector.PhaseRun, inspector.PhaseParse, PhaseValidation}
+
+// Options configures a benchmark
+type Options struct {
+	// ProjectPath is the path to the Go project to inspect (required)
+	ProjectPath string
+
+	// Entrypoint is the function that creates the root command
+	Entrypoint string
+
+	// Iterations is the number of times to run the cycle. Defaults to
+	// DefaultIterations.
+	Iterations int
+
+	// ContractPath is a contract to validate the CLI against in each
+	// iteration (optional). Without it, only inspection is measured.
+	ContractPath string
+
+	// Timeout for each inspection (optional)
+	Timeout time.Duration
+
+	// Inspect runs one inspection, reporting its phases to config.OnPhase.
+	// Defaults to inspector.NewInspector(config).Inspect
+	Inspect func(config inspector.Config) (*inspector.InspectedCLI, error)
+
+	// OnIteration, if set, is called as each iteration starts, numbered
+	// from 1
+	OnIteration func(iteration int)
+}
+
+// PhaseStats summarizes the time a phase took over all iterations
+type PhaseStats struct {
+	Phase   string
+	Samples []time.Duration
+
+	Min    time.Duration
+	Max    time.Duration
+	Median time.Duration
+	P95    time.Duration
+}
+
+// Result is the outcome of a benchmark
+type Result struct {
+	Iterations int
+
+	// Phases are the phases that were measured, in the order they run
+	Phases []PhaseStats
+}
+
+// Run inspects the project, and validates it if a contract is given,
+// opts.Iterations times, timing each phase. It stops at the first iteration
+// that fails.
+func Run(opts Options) (*Result, error) {
+	if opts.Iterations == 0 {
+		opts.Iterations = DefaultIterations
+	}
+	if opts.Iterations < 0 {
+		return nil, fmt.Errorf("iterations must be at least 1, got %d", opts.Iterations)
+	}
+	if opts.Inspect == nil {
+		opts.Inspect = func(config inspector.Config) (*inspector.InspectedCLI, error) {
+			return inspector.NewInspector(config).Inspect()
+		}
+	}
+
+	absProjectPath, err := filepath.Abs(opts.ProjectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve project path '%s': %w", opts.ProjectPath, err)
+	}
+	if _, err := os.Stat(absProjectPath); os.IsNotExist(err) {
+		return nil, errors.ProjectNotFoundError{Path: absProjectPath}
+	}
+
+	// Load the contract once; only comparing it is measured
+	var contractSpec *contract.Contract
+	var contractV2 *contract.ContractV2
+	if opts.ContractPath != "" {
+		if contract.IsV2File(opts.ContractPath) {
+			contractV2, err = contract.LoadV2(opts.ContractPath)
+		} else {
+			contractSpec, err = contract.Load(opts.ContractPath)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to load contract: %w", err)
+		}
+	}
+
+	samples := make(map[string][]time.Duration)
+	record := func(phase string, elapsed time.Duration) {
+		samples[phase] = append(samples[phase], elapsed)
+	}
+
+	for n := 1; n <= opts.Iterations; n++ {
+		if opts.OnIteration != nil {
+			opts.OnIteration(n)
+		}
+
+		inspected, err := opts.Inspect(inspector.Config{
+			ProjectPath: absProjectPath,
+			Entrypoint:  opts.Entrypoint,
+			Timeout:     opts.Timeout,
+			OnPhase:     record,
+		})
+		if err != nil {
+			return nil, errors.InspectionError{
+				ProjectPath: absProjectPath,
+				Entrypoint:  opts.Entrypoint,
+				Err:         err,
+			}
+		}
+
+		if contractSpec == nil && contractV2 == nil {
+			continue
+		}
+		spec := contractSpec
+		if contractV2 != nil {
+			if spec, err = contractV2.Root(inspected.Use); err != nil {
+				return nil, fmt.Errorf("failed to select contract root: %w", err)
+			}
+		}
+		start := time.Now()
+		validator.Validate(spec, inspected)
+		record(PhaseValidation, time.Since(start))
+	}
+
+	result := &Result{Iterations: opts.Iterations}
+	for _, phase := range phases {
+		if phaseSamples, ok := samples[phase]; ok {
+			result.Phases = append(result.Phases, newPhaseStats(phase, phaseSamples))
+		}
+	}
+	return result, nil
+}
+
+// newPhaseStats summarizes the samples of a phase
+func newPhaseStats(phase string, samples []time.Duration) PhaseStats {
+	sorted := append([]time.Duration(nil), samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return PhaseStats{
+		Phase:   phase,
+		Samples: samples,
+		Min:     sorted[0],
+		Max:     sorted[len(sorted)-1],
+		Median:  median(sorted),
+		P95:     percentile(sorted, 95),
+	}
+}
+
+// median returns the middle of the sorted samples, or the mean of the two
+// middle samples if there is an even number of them
+func median(sorted []time.Duration) time.Duration {
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[mid]
+	}
+	return (sorted[mid-1] + sorted[mid]) / 2
+}
+
+// percentile returns the p-th percentile of the sorted samples, using the
+// nearest-rank method: the smallest sample that at least p percent of the
+// samples are no greater than
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// FormatMarkdown renders the result as a markdown table with one row per
+// phase
+func (r *Result) FormatMarkdown() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "## Benchmark (%d iterations)\n\n", r.Iterations)
+	sb.WriteString("| Phase | Min | Median | P95 | Max |\n")
+	sb.WriteString("|-------|-----|--------|-----|-----|\n")
+	for _, stats := range r.Phases {
+		fmt.Fprintf(&sb, "| %s | %s | %s | %s | %s |\n", stats.Phase,
+			formatDuration(stats.Min), formatDuration(stats.Median),
+			formatDuration(stats.P95), formatDuration(stats.Max))
+	}
+	return sb.String()
+}
+
+// formatDuration rounds d to the millisecond for display, keeping
+// microseconds for phases that take less than a millisecond
+func formatDuration(d time.Duration) string {
+	if d < time.Millisecond {
+		return d.Round(time.Microsecond).String()
+	}
+	return d.Round(time.Millisecond).String()
+}
+
+// resultJSON is the JSON form of a Result. Durations are in milliseconds.
+type resultJSON struct {
+	Iterations int         `json:"iterations"`
+	Phases     []phaseJSON `json:"phases"`
+}
+
+// phaseJSON is the JSON form of PhaseStats
+type phaseJSON struct {
+	Phase     string    `json:"phase"`
+	MinMS     float64   `json:"min_ms"`
+	MedianMS  float64   `json:"median_ms"`
+	P95MS     float64   `json:"p95_ms"`
+	MaxMS     float64   `json:"max_ms"`
+	SamplesMS []float64 `json:"samples_ms"`
+}
+
+// FormatJSON renders the result as indented JSON, with durations in
+// milliseconds
+func (r *Result) FormatJSON() ([]byte, error) {
+	out := resultJSON{Iterations: r.Iterations, Phases: []phaseJSON{}}
+	for _, stats := range r.Phases {
+		samples := make([]float64, len(stats.Samples))
+		for i, sample := range stats.Samples {
+			samples[i] = milliseconds(sample)
+		}
+		out.Phases = append(out.Phases, phaseJSON{
+			Phase:     stats.Phase,
+			MinMS:     milliseconds(stats.Min),
+			MedianMS:  milliseconds(stats.Median),
+			P95MS:     milliseconds(stats.P95),
+			MaxMS:     milliseconds(stats.Max),
+			SamplesMS: samples,
+		})
+	}
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal benchmark result: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// milliseconds converts d to fractional milliseconds
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
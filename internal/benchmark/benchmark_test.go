@@ -0,0 +1,197 @@
+package benchmark
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/inspector"
+)
+
+// fakeInspect reports the given phase durations for each call, one call per
+// element
+func fakeInspect(t *testing.T, calls []map[string]time.Duration) func(inspector.Config) (*inspector.InspectedCLI, error) {
+	n := 0
+	return func(config inspector.Config) (*inspector.InspectedCLI, error) {
+		if n >= len(calls) {
+			t.Fatalf("Inspect called %d times, want %d", n+1, len(calls))
+		}
+		for _, phase := range []string{inspector.PhaseBuild, inspector.PhaseRun, inspector.PhaseParse} {
+			config.OnPhase(phase, calls[n][phase])
+		}
+		n++
+		return &inspector.InspectedCLI{Use: "app"}, nil
+	}
+}
+
+func TestRun(t *testing.T) {
+	ms := time.Millisecond
+	var calls []map[string]time.Duration
+	for _, build := range []time.Duration{500 * ms, 100 * ms, 300 * ms, 200 * ms} {
+		calls = append(calls, map[string]time.Duration{
+			inspector.PhaseBuild: build,
+			inspector.PhaseRun:   build / 10,
+			inspector.PhaseParse: ms,
+		})
+	}
+
+	var iterations []int
+	result, err := Run(Options{
+		ProjectPath: t.TempDir(),
+		Entrypoint:  "cmd.NewRootCmd",
+		Iterations:  4,
+		Inspect:     fakeInspect(t, calls),
+		OnIteration: func(n int) { iterations = append(iterations, n) },
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if len(iterations) != 4 || iterations[0] != 1 || iterations[3] != 4 {
+		t.Errorf("OnIteration calls = %v, want 1 to 4", iterations)
+	}
+	if len(result.Phases) != 3 {
+		t.Fatalf("Phases = %+v, want build, run and parse only without a contract", result.Phases)
+	}
+
+	build := result.Phases[0]
+	if build.Phase != inspector.PhaseBuild {
+		t.Errorf("first phase = %s, want %s", build.Phase, inspector.PhaseBuild)
+	}
+	if build.Min != 100*ms || build.Max != 500*ms || build.Median != 250*ms || build.P95 != 500*ms {
+		t.Errorf("build stats = min %v, median %v, p95 %v, max %v; want 100ms, 250ms, 500ms, 500ms",
+			build.Min, build.Median, build.P95, build.Max)
+	}
+	if len(build.Samples) != 4 || build.Samples[0] != 500*ms {
+		t.Errorf("build samples = %v, want them in iteration order", build.Samples)
+	}
+}
+
+func TestRun_WithContract(t *testing.T) {
+	dir := t.TempDir()
+	contractPath := filepath.Join(dir, "cliguard.yaml")
+	if err := os.WriteFile(contractPath, []byte("use: app\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	calls := make([]map[string]time.Duration, 2)
+	result, err := Run(Options{
+		ProjectPath:  dir,
+		Iterations:   2,
+		ContractPath: contractPath,
+		Inspect:      fakeInspect(t, calls),
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	last := result.Phases[len(result.Phases)-1]
+	if last.Phase != PhaseValidation || len(last.Samples) != 2 {
+		t.Errorf("last phase = %+v, want 2 validation samples", last)
+	}
+}
+
+func TestRun_Errors(t *testing.T) {
+	t.Run("missing project", func(t *testing.T) {
+		_, err := Run(Options{ProjectPath: "/nonexistent/project", Inspect: fakeInspect(t, nil)})
+		if err == nil || !strings.Contains(err.Error(), "Project path does not exist") {
+			t.Errorf("Run() error = %v, want project not found", err)
+		}
+	})
+
+	t.Run("missing contract", func(t *testing.T) {
+		_, err := Run(Options{ProjectPath: t.TempDir(), ContractPath: "/nonexistent/cliguard.yaml", Inspect: fakeInspect(t, nil)})
+		if err == nil || !strings.Contains(err.Error(), "failed to load contract") {
+			t.Errorf("Run() error = %v, want contract load failure", err)
+		}
+	})
+
+	t.Run("negative iterations", func(t *testing.T) {
+		if _, err := Run(Options{ProjectPath: t.TempDir(), Iterations: -1}); err == nil {
+			t.Error("Run() error = nil, want error")
+		}
+	})
+
+	t.Run("inspection failure", func(t *testing.T) {
+		_, err := Run(Options{
+			ProjectPath: t.TempDir(),
+			Inspect: func(inspector.Config) (*inspector.InspectedCLI, error) {
+				return nil, errors.New("build failed")
+			},
+		})
+		if err == nil || !strings.Contains(err.Error(), "build failed") {
+			t.Errorf("Run() error = %v, want the inspection error", err)
+		}
+	})
+}
+
+func TestPercentile(t *testing.T) {
+	var sorted []time.Duration
+	for i := 1; i <= 20; i++ {
+		sorted = append(sorted, time.Duration(i)*time.Second)
+	}
+	if got := percentile(sorted, 95); got != 19*time.Second {
+		t.Errorf("percentile(95) = %v, want 19s", got)
+	}
+	if got := percentile(sorted[:1], 95); got != time.Second {
+		t.Errorf("percentile(95) of one sample = %v, want 1s", got)
+	}
+	if got := median(sorted[:3]); got != 2*time.Second {
+		t.Errorf("median of 3 = %v, want 2s", got)
+	}
+}
+
+func TestResult_Format(t *testing.T) {
+	result := &Result{
+		Iterations: 2,
+		Phases: []PhaseStats{
+			{
+				Phase:   inspector.PhaseBuild,
+				Samples: []time.Duration{1500 * time.Millisecond, 2500 * time.Millisecond},
+				Min:     1500 * time.Millisecond,
+				Median:  2 * time.Second,
+				P95:     2500 * time.Millisecond,
+				Max:     2500 * time.Millisecond,
+			},
+			{
+				Phase:   PhaseValidation,
+				Samples: []time.Duration{250 * time.Microsecond},
+				Min:     250 * time.Microsecond,
+				Median:  250 * time.Microsecond,
+				P95:     250 * time.Microsecond,
+				Max:     250 * time.Microsecond,
+			},
+		},
+	}
+
+	markdown := result.FormatMarkdown()
+	for _, want := range []string{
+		"## Benchmark (2 iterations)",
+		"| Phase | Min | Median | P95 | Max |",
+		"| go_build | 1.5s | 2s | 2.5s | 2.5s |",
+		"| validation | 250µs | 250µs | 250µs | 250µs |",
+	} {
+		if !strings.Contains(markdown, want) {
+			t.Errorf("FormatMarkdown() missing %q:\n%s", want, markdown)
+		}
+	}
+
+	data, err := result.FormatJSON()
+	if err != nil {
+		t.Fatalf("FormatJSON() error = %v", err)
+	}
+	var decoded resultJSON
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("FormatJSON() is not valid JSON: %v", err)
+	}
+	if decoded.Iterations != 2 || len(decoded.Phases) != 2 {
+		t.Fatalf("FormatJSON() = %s", data)
+	}
+	if got := decoded.Phases[0]; got.MedianMS != 2000 || len(got.SamplesMS) != 2 || got.SamplesMS[0] != 1500 {
+		t.Errorf("build phase = %+v, want durations in milliseconds", got)
+	}
+}
@@ -0,0 +1,102 @@
+// Package config loads the .cliguard.yaml file that sets defaults for
+// cliguard's flags, so they needn't be repeated on each invocation.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/contract"
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the name of the config file, looked for in the project's root
+// and then in the home directory
+const FileName = ".cliguard.yaml"
+
+// Config holds defaults for cliguard's flags. The keys are the names of the
+// flags; CLI flags override them. Fields left out, or set to their zero
+// value, leave the flag's own default.
+//
+// Example YAML:
+//
+//	project-path: ./cli
+//	entrypoint: github.com/org/repo/cmd.NewRootCmd
+//	contract: ./cli/cliguard.yaml
+//	timeout: 2m
+//	strict: true
+//	warn-only: false
+//	min-confidence: 80
+type Config struct {
+	// ProjectPath is the path to the Go project. Relative paths are
+	// resolved against the config file's directory.
+	ProjectPath string `yaml:"project-path,omitempty"`
+
+	// Entrypoint is the function that returns the root command
+	Entrypoint string `yaml:"entrypoint,omitempty"`
+
+	// Contract is the path or http(s):// URL of the contract file. Relative
+	// paths are resolved against the config file's directory.
+	Contract string `yaml:"contract,omitempty"`
+
+	// Timeout is the timeout for CLI inspection, e.g. 2m
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+
+	// Strict is the default of validate --strict
+	Strict bool `yaml:"strict,omitempty"`
+
+	// WarnOnly is the default of validate --warn-only
+	WarnOnly bool `yaml:"warn-only,omitempty"`
+
+	// MinConfidence is the default of discover --min-confidence
+	MinConfidence int `yaml:"min-confidence,omitempty"`
+
+	// Path is the file the config was loaded from, empty if there was none
+	Path string `yaml:"-"`
+}
+
+// LoadConfig reads the FileName in projectPath, or if there is none, the one
+// in the home directory. If neither exists it returns an empty Config. Keys
+// that aren't the name of a setting are an error, so that typos don't go
+// unnoticed.
+func LoadConfig(projectPath string) (*Config, error) {
+	paths := []string{filepath.Join(projectPath, FileName)}
+	if home, err := os.UserHomeDir(); err == nil {
+		paths = append(paths, filepath.Join(home, FileName))
+	}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config '%s': %w", path, err)
+		}
+		return parse(path, data)
+	}
+	return &Config{}, nil
+}
+
+// parse decodes the config file at path, resolving its relative paths
+func parse(path string, data []byte) (*Config, error) {
+	config := &Config{Path: path}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(config); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse config '%s': %w", path, err)
+	}
+
+	baseDir := filepath.Dir(path)
+	if config.ProjectPath != "" && !filepath.IsAbs(config.ProjectPath) {
+		config.ProjectPath = filepath.Join(baseDir, config.ProjectPath)
+	}
+	if config.Contract != "" && !filepath.IsAbs(config.Contract) && !contract.IsURL(config.Contract) {
+		config.Contract = filepath.Join(baseDir, config.Contract)
+	}
+	return config, nil
+}
@@ -0,0 +1,112 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeConfig writes content as the FileName in dir
+func writeConfig(t *testing.T, dir, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, FileName), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	writeConfig(t, dir, `project-path: ./cli
+entrypoint: github.com/org/repo/cmd.NewRootCmd
+contract: contracts/cliguard.yaml
+timeout: 2m
+strict: true
+warn-only: true
+min-confidence: 80
+`)
+
+	config, err := LoadConfig(dir)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	want := Config{
+		ProjectPath:   filepath.Join(dir, "cli"),
+		Entrypoint:    "github.com/org/repo/cmd.NewRootCmd",
+		Contract:      filepath.Join(dir, "contracts", "cliguard.yaml"),
+		Timeout:       2 * time.Minute,
+		Strict:        true,
+		WarnOnly:      true,
+		MinConfidence: 80,
+		Path:          filepath.Join(dir, FileName),
+	}
+	if *config != want {
+		t.Errorf("LoadConfig() = %+v, want %+v", *config, want)
+	}
+}
+
+func TestLoadConfig_HomeFallback(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeConfig(t, home, "contract: https://example.com/cliguard.yaml\n")
+
+	config, err := LoadConfig(t.TempDir())
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if config.Path != filepath.Join(home, FileName) || config.Contract != "https://example.com/cliguard.yaml" {
+		t.Errorf("LoadConfig() = %+v, want the home config with the URL kept", config)
+	}
+
+	// The project's config takes precedence
+	dir := t.TempDir()
+	writeConfig(t, dir, "entrypoint: cmd.NewRootCmd\n")
+	config, err = LoadConfig(dir)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if config.Path != filepath.Join(dir, FileName) || config.Contract != "" {
+		t.Errorf("LoadConfig() = %+v, want only the project config", config)
+	}
+}
+
+func TestLoadConfig_Missing(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	config, err := LoadConfig(t.TempDir())
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if *config != (Config{}) {
+		t.Errorf("LoadConfig() = %+v, want an empty config", config)
+	}
+}
+
+func TestLoadConfig_Errors(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "unknown key", content: "entry-point: cmd.NewRootCmd\n", wantErr: "field entry-point not found"},
+		{name: "invalid timeout", content: "timeout: soon\n", wantErr: "failed to parse config"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeConfig(t, dir, tt.content)
+			_, err := LoadConfig(dir)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadConfig() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	dir := t.TempDir()
+	writeConfig(t, dir, "")
+	if _, err := LoadConfig(dir); err != nil {
+		t.Errorf("LoadConfig() error = %v, want an empty file accepted", err)
+	}
+}
@@ -0,0 +1,82 @@
+package contract
+
+import "reflect"
+
+// deprecatedFields are the old names that contract files may still use for
+// some fields, keyed by the type that accepts them and then by the old name,
+// with the name of the field they load into. CheckFields reports them as
+// deprecated rather than unknown.
+var deprecatedFields = map[reflect.Type]map[string]string{
+	reflect.TypeOf(Contract{}): {"description": "long"},
+	reflect.TypeOf(Command{}):  {"description": "long"},
+	reflect.TypeOf(Flag{}):     {"description": "usage"},
+}
+
+// The plain types have the fields of the contract types but not their
+// UnmarshalYAML methods, so that those methods can decode into them
+type (
+	plainContract Contract
+	plainCommand  Command
+	plainFlag     Flag
+)
+
+// contractYAML is a contract as written in a file, with its deprecated
+// fields
+type contractYAML struct {
+	plainContract `yaml:",inline"`
+	Description   string `yaml:"description,omitempty"`
+}
+
+// commandYAML is a command as written in a file, with its deprecated fields
+type commandYAML struct {
+	plainCommand `yaml:",inline"`
+	Description  string `yaml:"description,omitempty"`
+}
+
+// flagYAML is a flag as written in a file, with its deprecated fields
+type flagYAML struct {
+	plainFlag   `yaml:",inline"`
+	Description string `yaml:"description,omitempty"`
+}
+
+// UnmarshalYAML decodes a contract, loading the deprecated description
+// field into Long if Long is not set
+func (c *Contract) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var raw contractYAML
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+	*c = Contract(raw.plainContract)
+	if c.Long == "" {
+		c.Long = raw.Description
+	}
+	return nil
+}
+
+// UnmarshalYAML decodes a command, loading the deprecated description
+// field into Long if Long is not set
+func (c *Command) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var raw commandYAML
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+	*c = Command(raw.plainCommand)
+	if c.Long == "" {
+		c.Long = raw.Description
+	}
+	return nil
+}
+
+// UnmarshalYAML decodes a flag, loading the deprecated description field
+// into Usage if Usage is not set
+func (f *Flag) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var raw flagYAML
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+	*f = Flag(raw.plainFlag)
+	if f.Usage == "" {
+		f.Usage = raw.Description
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package contract
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoad_DeprecatedDescription(t *testing.T) {
+	canonical := `use: app
+short: App
+long: The app
+flags:
+  - name: verbose
+    type: bool
+    usage: Verbose output
+commands:
+  - use: serve
+    short: Serve
+    long: Serve the app
+    flags:
+      - name: port
+        type: int
+        usage: Port to listen on
+`
+	deprecated := `use: app
+short: App
+description: The app
+flags:
+  - name: verbose
+    type: bool
+    description: Verbose output
+commands:
+  - use: serve
+    short: Serve
+    description: Serve the app
+    flags:
+      - name: port
+        type: int
+        description: Port to listen on
+`
+	dir := t.TempDir()
+	writeContractFiles(t, dir, map[string]string{
+		"canonical.yaml":  canonical,
+		"deprecated.yaml": deprecated,
+	})
+
+	want, err := Load(filepath.Join(dir, "canonical.yaml"))
+	if err != nil {
+		t.Fatalf("Load(canonical) error = %v", err)
+	}
+	got, err := Load(filepath.Join(dir, "deprecated.yaml"))
+	if err != nil {
+		t.Fatalf("Load(deprecated) error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Load(deprecated) = %+v, want %+v", got, want)
+	}
+
+	for _, strict := range []bool{false, true} {
+		fields, err := CheckFields(filepath.Join(dir, "deprecated.yaml"), strict)
+		if err != nil {
+			t.Fatalf("CheckFields(strict=%v) error = %v", strict, err)
+		}
+		wantFields := []UnknownField{
+			{Name: "description", Path: "root", Line: 3, ReplacedBy: "long"},
+			{Name: "description", Path: "root.flags[0]", Line: 7, ReplacedBy: "usage"},
+			{Name: "description", Path: "root.commands[0]", Line: 11, ReplacedBy: "long"},
+			{Name: "description", Path: "root.commands[0].flags[0]", Line: 15, ReplacedBy: "usage"},
+		}
+		if !reflect.DeepEqual(fields, wantFields) {
+			t.Errorf("CheckFields(strict=%v) = %+v, want %+v", strict, fields, wantFields)
+		}
+	}
+}
+
+func TestLoad_DeprecatedDescriptionWithLong(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cliguard.yaml")
+	content := "use: app\nshort: App\nlong: The long text\ndescription: The old text\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if c.Long != "The long text" {
+		t.Errorf("Long = %q, want the long field to win", c.Long)
+	}
+}
+
+func TestLoadV2_DeprecatedDescription(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cliguard.yaml")
+	content := "version: 2.0.0\nroots:\n  app:\n    use: app\n    short: App\n    description: The app\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := LoadV2(path)
+	if err != nil {
+		t.Fatalf("LoadV2() error = %v", err)
+	}
+	if long := c.Roots["app"].Long; long != "The app" {
+		t.Errorf("Long = %q, want the description", long)
+	}
+}
@@ -0,0 +1,79 @@
+package contract
+
+import (
+	"bytes"
+	"strings"
+)
+
+// AnnotationPrefix starts the comments Annotate writes into a contract.
+// ClearAnnotations removes the comment lines that start with it.
+const AnnotationPrefix = "# ❌ "
+
+// utf8BOM is the byte order mark generate --output-encoding utf8bom writes
+var utf8BOM = []byte("\xef\xbb\xbf")
+
+// Annotation is a comment to write above the line defining Path, a path of
+// a LineIndex
+type Annotation struct {
+	Path string
+	Text string
+}
+
+// Annotate returns the contract document with each annotation written as a
+// comment line above the line that defines its path (see LineIndex.Line),
+// indented like it. Annotations already in the document are removed first,
+// so annotating again replaces them. Other lines are left as they are,
+// keeping the formatting and comments of hand-written contracts. For v2
+// contracts, rootName selects the root the paths are in.
+func Annotate(data []byte, rootName string, annotations []Annotation) ([]byte, error) {
+	data = ClearAnnotations(data)
+	bom := bytes.HasPrefix(data, utf8BOM)
+	data = bytes.TrimPrefix(data, utf8BOM)
+
+	lines, err := IndexLines(data, rootName)
+	if err != nil {
+		return nil, err
+	}
+	comments := make(map[int][]string)
+	for _, annotation := range annotations {
+		if line := lines.Line(annotation.Path); line > 0 {
+			// Comments are one line each
+			text := strings.Join(strings.Fields(annotation.Text), " ")
+			comments[line] = append(comments[line], text)
+		}
+	}
+
+	var out bytes.Buffer
+	if bom {
+		out.Write(utf8BOM)
+	}
+	docLines := strings.SplitAfter(string(data), "\n")
+	for i, line := range docLines {
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		ending := "\n"
+		if strings.HasSuffix(line, "\r\n") {
+			ending = "\r\n"
+		}
+		for _, text := range comments[i+1] {
+			out.WriteString(indent + AnnotationPrefix + text + ending)
+		}
+		out.WriteString(line)
+	}
+	return out.Bytes(), nil
+}
+
+// ClearAnnotations returns the contract document without the comment lines
+// Annotate wrote
+func ClearAnnotations(data []byte) []byte {
+	var out bytes.Buffer
+	for _, line := range strings.SplitAfter(string(data), "\n") {
+		if strings.HasPrefix(strings.TrimLeft(strings.TrimPrefix(line, string(utf8BOM)), " \t"), AnnotationPrefix) {
+			if strings.HasPrefix(line, string(utf8BOM)) {
+				out.Write(utf8BOM)
+			}
+			continue
+		}
+		out.WriteString(line)
+	}
+	return out.Bytes()
+}
@@ -0,0 +1,72 @@
+package contract
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAnnotate(t *testing.T) {
+	annotations := []Annotation{
+		{Path: "db migrate [version] --dry-run", Text: "MISSING in implementation: db migrate [version] --dry-run flag"},
+		{Path: "db status", Text: "UNEXPECTED in implementation: db status command"},
+		{Path: "root", Text: "MISMATCH at root: Mismatch in short description\n(contract: \"My app\")"},
+	}
+	got, err := Annotate([]byte(linesContract), "", annotations)
+	if err != nil {
+		t.Fatalf("Annotate() error = %v", err)
+	}
+	want := `# ❌ MISMATCH at root: Mismatch in short description (contract: "My app")
+use: app
+short: My app
+flags:
+  - name: verbose
+    type: bool
+commands:
+  # ❌ UNEXPECTED in implementation: db status command
+  - use: db
+    short: Database commands
+    commands:
+      - use: migrate [version]
+        flags:
+          # ❌ MISSING in implementation: db migrate [version] --dry-run flag
+          - name: dry-run
+            type: bool
+  - use: serve
+`
+	if string(got) != want {
+		t.Errorf("Annotate() =\n%s\nwant:\n%s", got, want)
+	}
+
+	// Annotating again replaces the annotations
+	again, err := Annotate(got, "", annotations[:1])
+	if err != nil {
+		t.Fatalf("Annotate() error = %v", err)
+	}
+	if strings.Count(string(again), AnnotationPrefix) != 1 {
+		t.Errorf("Annotate() of an annotated contract =\n%s\nwant one annotation", again)
+	}
+
+	if cleared := ClearAnnotations(got); string(cleared) != linesContract {
+		t.Errorf("ClearAnnotations() =\n%s\nwant the original:\n%s", cleared, linesContract)
+	}
+}
+
+func TestAnnotate_KeepsEncoding(t *testing.T) {
+	original := "\xef\xbb\xbfuse: app\r\nshort: App\r\n"
+	got, err := Annotate([]byte(original), "", []Annotation{{Path: "root", Text: "MISMATCH"}})
+	if err != nil {
+		t.Fatalf("Annotate() error = %v", err)
+	}
+	if want := "\xef\xbb\xbf# ❌ MISMATCH\r\nuse: app\r\nshort: App\r\n"; string(got) != want {
+		t.Errorf("Annotate() = %q, want %q", got, want)
+	}
+	if cleared := ClearAnnotations(got); string(cleared) != original {
+		t.Errorf("ClearAnnotations() = %q, want %q", cleared, original)
+	}
+}
+
+func TestAnnotate_InvalidYAML(t *testing.T) {
+	if _, err := Annotate([]byte("use: [app"), "", nil); err == nil {
+		t.Error("Annotate() succeeded for invalid YAML")
+	}
+}
@@ -0,0 +1,97 @@
+package contract
+
+// CommandCount returns the number of commands under the root command, at
+// any depth. The root command itself is not counted.
+func (c *Contract) CommandCount() int {
+	return contractTree.CountCommands(c.Commands)
+}
+
+// FlagCount returns the number of flags of the root command and of every
+// command under it. With countPersistent, each command also counts the
+// persistent flags it inherits from its ancestors, as its help lists them;
+// otherwise each flag is counted once, on the command that defines it.
+func (c *Contract) FlagCount(countPersistent bool) int {
+	return contractTree.CountFlags(c.Flags, c.Commands, countPersistent)
+}
+
+// MaxDepth returns the nesting level of the deepest command: 0 for a root
+// command without subcommands, 1 if it only has direct subcommands, and so on
+func (c *Contract) MaxDepth() int {
+	return contractTree.MaxDepth(c.Commands)
+}
+
+// CommandTree walks trees of commands of type C with flags of type F, so
+// that contracts and inspected CLIs are counted the same way
+type CommandTree[C, F any] struct {
+	Commands   func(C) []C
+	Flags      func(C) []F
+	FlagName   func(F) string
+	Persistent func(F) bool
+}
+
+// contractTree walks the commands of contracts
+var contractTree = CommandTree[Command, Flag]{
+	Commands:   func(cmd Command) []Command { return cmd.Commands },
+	Flags:      func(cmd Command) []Flag { return cmd.Flags },
+	FlagName:   func(flag Flag) string { return flag.Name },
+	Persistent: func(flag Flag) bool { return flag.Persistent },
+}
+
+// CountCommands counts the commands and their subcommands, recursively
+func (t CommandTree[C, F]) CountCommands(commands []C) int {
+	n := len(commands)
+	for _, cmd := range commands {
+		n += t.CountCommands(t.Commands(cmd))
+	}
+	return n
+}
+
+// CountFlags counts the flags of a root command and of its subcommands, as
+// Contract.FlagCount does
+func (t CommandTree[C, F]) CountFlags(flags []F, commands []C, countPersistent bool) int {
+	return t.countFlags(flags, commands, nil, countPersistent)
+}
+
+// countFlags counts the flags of a command and of its subcommands,
+// recursively. inherited are the names of the persistent flags the command
+// inherits, which are only counted with countPersistent; a flag the command
+// defines itself shadows an inherited flag of the same name.
+func (t CommandTree[C, F]) countFlags(flags []F, commands []C, inherited map[string]bool, countPersistent bool) int {
+	n := len(flags)
+	var childInherited map[string]bool
+	if countPersistent {
+		local := make(map[string]bool, len(flags))
+		for _, flag := range flags {
+			local[t.FlagName(flag)] = true
+		}
+		childInherited = make(map[string]bool, len(inherited)+len(flags))
+		for name := range inherited {
+			if !local[name] {
+				n++
+			}
+			childInherited[name] = true
+		}
+		for _, flag := range flags {
+			if t.Persistent(flag) {
+				childInherited[t.FlagName(flag)] = true
+			}
+		}
+	}
+
+	for _, cmd := range commands {
+		n += t.countFlags(t.Flags(cmd), t.Commands(cmd), childInherited, countPersistent)
+	}
+	return n
+}
+
+// MaxDepth returns the nesting level of the deepest of the commands, or 0
+// if there are none
+func (t CommandTree[C, F]) MaxDepth(commands []C) int {
+	depth := 0
+	for _, cmd := range commands {
+		if d := 1 + t.MaxDepth(t.Commands(cmd)); d > depth {
+			depth = d
+		}
+	}
+	return depth
+}
@@ -0,0 +1,47 @@
+package contract
+
+import "testing"
+
+func TestContractCounts(t *testing.T) {
+	c := &Contract{
+		Use: "myapp",
+		Flags: []Flag{
+			{Name: "config", Persistent: true},
+			{Name: "verbose", Persistent: true},
+			{Name: "output"},
+		},
+		Commands: []Command{
+			{Use: "serve", Flags: []Flag{{Name: "port"}, {Name: "config"}}, Commands: []Command{
+				{Use: "http", Flags: []Flag{{Name: "tls", Persistent: true}}, Commands: []Command{
+					{Use: "h2"},
+				}},
+			}},
+			{Use: "db", Commands: []Command{
+				{Use: "migrate", Flags: []Flag{{Name: "dry-run"}}},
+			}},
+		},
+	}
+
+	if got := c.CommandCount(); got != 5 {
+		t.Errorf("CommandCount() = %d, want 5", got)
+	}
+	// myapp 3, serve 2, http 1, migrate 1
+	if got := c.FlagCount(false); got != 7 {
+		t.Errorf("FlagCount(false) = %d, want 7", got)
+	}
+	// myapp 3, serve 2 + --verbose (its --config shadows the root's),
+	// http 1 + 2, h2 3 inherited, db 2 inherited, migrate 1 + 2
+	if got := c.FlagCount(true); got != 17 {
+		t.Errorf("FlagCount(true) = %d, want 17", got)
+	}
+	if got := c.MaxDepth(); got != 3 {
+		t.Errorf("MaxDepth() = %d, want 3", got)
+	}
+}
+
+func TestContractCounts_RootOnly(t *testing.T) {
+	c := &Contract{Use: "tiny", Flags: []Flag{{Name: "verbose", Persistent: true}}}
+	if c.CommandCount() != 0 || c.FlagCount(true) != 1 || c.FlagCount(false) != 1 || c.MaxDepth() != 0 {
+		t.Errorf("counts = %d, %d, %d, %d; want 0, 1, 1, 0", c.CommandCount(), c.FlagCount(true), c.FlagCount(false), c.MaxDepth())
+	}
+}
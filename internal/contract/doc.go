@@ -56,6 +56,33 @@
 //   - intSlice: Arrays of integers
 //   - boolSlice: Arrays of booleans
 //
+// # Stripping Cobra Defaults
+//
+// StripDefaults removes flags and commands matched by a list of StripRule
+// values, each a regular expression and an action. DefaultStripRules removes
+// what Cobra adds to every CLI:
+//   - flag rule ^help for \S+$: the --help flag
+//   - flag rule ^version for \S+$: the --version flag
+//   - command rule ^Generate the autocompletion script for: the completion
+//     command and its subcommands
+//
+// Flag rules match the flag's usage; command rules match the command's short
+// description. Additional rules can be parsed with ParseStripRule from
+// "action:pattern" strings such as "flag:^Enable debug output$". Rules with
+// a CommandUse, such as HelpCommandRule and CompletionCommandRule, match
+// commands by name instead.
+//
+// # Patching Contracts
+//
+// Contract.Apply returns a copy of a contract with the changes of a
+// ContractPatch, for tools that update contracts without editing the YAML:
+//
+//	patch := NewPatch().SetVersion("2.0.0").DeprecateFlag("", "verbose", "use --log-level")
+//	updated := contract.Apply(patch)
+//
+// Commands are given by the space-separated names of the commands below the
+// root, e.g. "db migrate".
+//
 // # Validation
 //
 // Contracts are validated against actual CLI implementations using the
@@ -0,0 +1,178 @@
+package contract
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// UnknownField is a field in a contract file that the contract format does
+// not define, such as a misspelled "usage_example" instead of "example".
+// yaml.v3 silently drops such fields when loading a contract.
+type UnknownField struct {
+	// Name is the field's key
+	Name string
+
+	// Path is the object the field is in, e.g. "root" for the top of the
+	// file or "root.commands[0].flags[1]" for the second flag of the first
+	// command
+	Path string
+
+	// Line is the line of the field in the file
+	Line int
+
+	// ReplacedBy is the field that Name is a deprecated name of, such as
+	// "long" for "description". Deprecated fields are still loaded. Empty
+	// for a field the format does not define.
+	ReplacedBy string
+}
+
+// String describes the field for a notice
+func (f UnknownField) String() string {
+	if f.ReplacedBy != "" {
+		return fmt.Sprintf("Contract field '%s' at %s is deprecated; use '%s' instead", f.Name, f.Path, f.ReplacedBy)
+	}
+	return fmt.Sprintf("Contract has unrecognized field '%s' at %s", f.Name, f.Path)
+}
+
+// CheckFields reads the contract at contractPath, v1 or v2, and returns the
+// fields it has that the contract format does not define. The recognized
+// fields are those in the yaml tags of Contract, ContractV2 and the types
+// they contain.
+//
+// With strict, the contract is decoded with yaml.v3's KnownFields first,
+// and any unknown field is an error. Deprecated fields are returned, with
+// ReplacedBy set, either way.
+func CheckFields(contractPath string, strict bool) ([]UnknownField, error) {
+	absPath, err := localPath(contractPath)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(absPath)
+	if err != nil {
+		return nil, errors.WrapContractNotFound(absPath, err)
+	}
+
+	var target interface{} = &Contract{}
+	if DetectVersion(data) == 2 {
+		target = &ContractV2{}
+	}
+
+	if strict {
+		expanded, err := expandIncludeData(data, absPath)
+		if err != nil {
+			return nil, err
+		}
+		decoder := yaml.NewDecoder(bytes.NewReader(expanded))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(target); err != nil && err != io.EOF {
+			return nil, errors.ContractParseError{
+				Path:    absPath,
+				Err:     err,
+				Content: string(data),
+			}
+		}
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, errors.ContractParseError{
+			Path:    absPath,
+			Err:     err,
+			Content: string(data),
+		}
+	}
+	var unknown []UnknownField
+	if len(doc.Content) > 0 {
+		findUnknownFields(doc.Content[0], reflect.TypeOf(target), "root", &unknown)
+	}
+	return unknown, nil
+}
+
+// findUnknownFields walks node as a value of type t, appending the keys of
+// mappings decoded into structs that the struct has no field for
+func findUnknownFields(node *yaml.Node, t reflect.Type, path string, unknown *[]UnknownField) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t.Kind() == reflect.Struct && node.Kind == yaml.MappingNode:
+		fields := yamlFields(t)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i]
+			field, ok := fields[key.Value]
+			if !ok {
+				replacedBy, deprecated := deprecatedFields[t][key.Value]
+				*unknown = append(*unknown, UnknownField{Name: key.Value, Path: path, Line: key.Line, ReplacedBy: replacedBy})
+				if !deprecated {
+					continue
+				}
+				field = fields[replacedBy]
+			}
+			findUnknownFields(node.Content[i+1], field.Type, path+"."+key.Value, unknown)
+		}
+	case t.Kind() == reflect.Slice && node.Kind == yaml.SequenceNode:
+		for i, item := range node.Content {
+			findUnknownFields(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i), unknown)
+		}
+	case t.Kind() == reflect.Map && node.Kind == yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			findUnknownFields(node.Content[i+1], t.Elem(), path+"."+node.Content[i].Value, unknown)
+		}
+	}
+}
+
+// yamlFields returns the fields of a struct type keyed by the name yaml.v3
+// decodes them from: the name in the field's yaml tag, or the lowercased
+// field name if the tag has none
+func yamlFields(t reflect.Type) map[string]reflect.StructField {
+	fields := make(map[string]reflect.StructField, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue // unexported
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		fields[name] = field
+	}
+	return fields
+}
+
+// expandIncludeData returns the YAML data of the contract at absPath with
+// its IncludeTag entries replaced, so that KnownFields checks the included
+// commands too. Data with no IncludeTag is returned as is, keeping the line
+// numbers of decoding errors.
+func expandIncludeData(data []byte, absPath string) ([]byte, error) {
+	if !bytes.Contains(data, []byte(IncludeTag)) {
+		return data, nil
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, errors.ContractParseError{
+			Path:    absPath,
+			Err:     err,
+			Content: string(data),
+		}
+	}
+	if err := expandIncludeTags(&doc, filepath.Dir(absPath), []string{absPath}); err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(&doc)
+}
@@ -0,0 +1,119 @@
+package contract
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCheckFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []UnknownField
+	}{
+		{
+			name: "known fields only",
+			content: `use: app
+short: App
+flags:
+  - name: verbose
+    type: bool
+    usage: Verbose output
+commands:
+  - use: serve
+    short: Serve
+    group_id: main
+    sort_order: 1
+`,
+		},
+		{
+			name: "unknown fields at each level",
+			content: `use: app
+short: App
+summary: not a field
+commands:
+  - use: serve
+    short: Serve
+    usage_example: app serve
+    flags:
+      - name: port
+        type: int
+        usage: Port
+        env_var: PORT
+`,
+			want: []UnknownField{
+				{Name: "summary", Path: "root", Line: 3},
+				{Name: "usage_example", Path: "root.commands[0]", Line: 7},
+				{Name: "env_var", Path: "root.commands[0].flags[0]", Line: 12},
+			},
+		},
+		{
+			name: "v2 contract",
+			content: `version: 2.0.0
+owner: team
+roots:
+  app:
+    use: app
+    shrt: App
+`,
+			want: []UnknownField{
+				{Name: "owner", Path: "root", Line: 2},
+				{Name: "shrt", Path: "root.roots.app", Line: 6},
+			},
+		},
+		{
+			name:    "empty file",
+			content: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "cliguard.yaml")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := CheckFields(path, false)
+			if err != nil {
+				t.Fatalf("CheckFields() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CheckFields() = %+v, want %+v", got, tt.want)
+			}
+
+			_, err = CheckFields(path, true)
+			if (err != nil) != (len(tt.want) > 0) {
+				t.Errorf("CheckFields(strict) error = %v, want error: %v", err, len(tt.want) > 0)
+			}
+			if err != nil && !strings.Contains(err.Error(), "field "+tt.want[0].Name+" not found") {
+				t.Errorf("CheckFields(strict) error = %v, want it to name %q", err, tt.want[0].Name)
+			}
+		})
+	}
+}
+
+func TestCheckFields_MissingFile(t *testing.T) {
+	if _, err := CheckFields(filepath.Join(t.TempDir(), "missing.yaml"), false); err == nil {
+		t.Error("CheckFields() error = nil, want error for missing file")
+	}
+}
+
+func TestUnknownField_String(t *testing.T) {
+	field := UnknownField{Name: "usage_example", Path: "root.commands[0]", Line: 7}
+	want := "Contract has unrecognized field 'usage_example' at root.commands[0]"
+	if got := field.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestUnknownField_StringDeprecated(t *testing.T) {
+	field := UnknownField{Name: "description", Path: "root.flags[0]", Line: 7, ReplacedBy: "usage"}
+	want := "Contract field 'description' at root.flags[0] is deprecated; use 'usage' instead"
+	if got := field.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
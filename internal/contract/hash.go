@@ -0,0 +1,108 @@
+package contract
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// NormalizedHash returns a SHA-256 hash of the contract's content that does
+// not depend on how its file is written: comments, whitespace and quoting
+// are lost when the file is parsed, and flags, commands, enum values and
+// flag groups, whose order validation ignores, are sorted. Two contracts with the same
+// hash validate a CLI identically.
+func NormalizedHash(c *Contract) (string, error) {
+	return hashYAML(normalize(c))
+}
+
+// NormalizedHashV2 is NormalizedHash for a v2 contract. Roots are hashed in
+// name order.
+func NormalizedHashV2(c *ContractV2) (string, error) {
+	roots := make(map[string]*Contract, len(c.Roots))
+	for name, root := range c.Roots {
+		roots[name] = normalize(root)
+	}
+	tags := append([]string(nil), c.Tags...)
+	sort.Strings(tags)
+	return hashYAML(&ContractV2{Version: c.Version, Schema: c.Schema, Tags: tags, Roots: roots})
+}
+
+// hashYAML hashes the YAML encoding of v. yaml.v3 writes map keys in sorted
+// order, so the encoding is deterministic.
+func hashYAML(v interface{}) (string, error) {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal contract: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// normalize returns a copy of the contract with its flags and commands
+// sorted, recursively. The original contract is not modified.
+func normalize(c *Contract) *Contract {
+	if c == nil {
+		return nil
+	}
+	normalized := *c
+	normalized.Flags = normalizeFlags(c.Flags)
+	normalized.MutuallyExclusive = normalizeFlagGroups(c.MutuallyExclusive)
+	normalized.RequiredTogether = normalizeFlagGroups(c.RequiredTogether)
+	normalized.Commands = normalizeCommands(c.Commands)
+	return &normalized
+}
+
+// normalizeCommands returns a sorted copy of the commands for normalize
+func normalizeCommands(commands []Command) []Command {
+	if commands == nil {
+		return nil
+	}
+	result := make([]Command, len(commands))
+	for i, cmd := range commands {
+		cmd.Flags = normalizeFlags(cmd.Flags)
+		cmd.MutuallyExclusive = normalizeFlagGroups(cmd.MutuallyExclusive)
+		cmd.RequiredTogether = normalizeFlagGroups(cmd.RequiredTogether)
+		cmd.Commands = normalizeCommands(cmd.Commands)
+		result[i] = cmd
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Use < result[j].Use })
+	return result
+}
+
+// normalizeFlags returns a sorted copy of the flags for normalize
+func normalizeFlags(flags []Flag) []Flag {
+	if flags == nil {
+		return nil
+	}
+	result := make([]Flag, len(flags))
+	for i, flag := range flags {
+		if flag.Enum != nil {
+			flag.Enum = append([]string(nil), flag.Enum...)
+			sort.Strings(flag.Enum)
+		}
+		result[i] = flag
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// normalizeFlagGroups returns a copy of the flag groups for normalize, with
+// the flags of each group and the groups sorted
+func normalizeFlagGroups(groups [][]string) [][]string {
+	if groups == nil {
+		return nil
+	}
+	result := make([][]string, len(groups))
+	for i, group := range groups {
+		result[i] = append([]string(nil), group...)
+		sort.Strings(result[i])
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return strings.Join(result[i], " ") < strings.Join(result[j], " ")
+	})
+	return result
+}
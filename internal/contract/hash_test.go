@@ -0,0 +1,115 @@
+package contract
+
+import (
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestNormalizedHash(t *testing.T) {
+	parse := func(t *testing.T, data string) *Contract {
+		t.Helper()
+		var c Contract
+		if err := yaml.Unmarshal([]byte(data), &c); err != nil {
+			t.Fatal(err)
+		}
+		return &c
+	}
+	hash := func(t *testing.T, data string) string {
+		t.Helper()
+		h, err := NormalizedHash(parse(t, data))
+		if err != nil {
+			t.Fatalf("NormalizedHash() error = %v", err)
+		}
+		return h
+	}
+
+	original := `use: app
+short: My app
+flags:
+  - name: verbose
+    usage: Verbose output
+    type: bool
+  - name: region
+    usage: Region
+    type: string
+    enum: [us, eu]
+commands:
+  - use: serve
+    short: Start the server
+  - use: migrate
+    short: Run migrations
+`
+	reformatted := `# Hand-edited contract
+use:    "app"
+short: 'My app'
+
+commands:
+- use: migrate
+  short: Run migrations   # comes first now
+- short: Start the server
+  use: serve
+flags:
+- {name: region, usage: Region, type: string, enum: [eu, us]}
+- name: verbose
+  type: bool
+  usage: Verbose output
+`
+	if hash(t, original) != hash(t, reformatted) {
+		t.Error("NormalizedHash() differs for a reformatted contract")
+	}
+	if got := len(hash(t, original)); got != 64 {
+		t.Errorf("len(NormalizedHash()) = %d, want 64 hex digits", got)
+	}
+
+	changed := `use: app
+short: My app
+flags:
+  - name: verbose
+    usage: Verbose output
+    type: bool
+    persistent: true
+`
+	if hash(t, original) == hash(t, changed) {
+		t.Error("NormalizedHash() is the same for a changed contract")
+	}
+
+	// Flag groups are compared as sets too
+	if hash(t, original+"mutually_exclusive: [[json, yaml], [quiet, verbose]]\n") != hash(t, original+"mutually_exclusive: [[verbose, quiet], [yaml, json]]\n") {
+		t.Error("NormalizedHash() differs for reordered flag groups")
+	}
+
+	// Normalizing does not reorder the original contract
+	c := parse(t, original)
+	if _, err := NormalizedHash(c); err != nil {
+		t.Fatal(err)
+	}
+	if c.Flags[0].Name != "verbose" || c.Commands[0].Use != "serve" || !reflect.DeepEqual(c.Flags[1].Enum, []string{"us", "eu"}) {
+		t.Errorf("NormalizedHash() modified the contract: %+v", c)
+	}
+}
+
+func TestNormalizedHashV2(t *testing.T) {
+	a := &ContractV2{Version: ContractV2Version, Tags: []string{"public", "stable"}, Roots: map[string]*Contract{
+		"app":   {Use: "app", Flags: []Flag{{Name: "b", Type: "bool"}, {Name: "a", Type: "bool"}}},
+		"admin": {Use: "admin"},
+	}}
+	b := &ContractV2{Version: ContractV2Version, Tags: []string{"stable", "public"}, Roots: map[string]*Contract{
+		"admin": {Use: "admin"},
+		"app":   {Use: "app", Flags: []Flag{{Name: "a", Type: "bool"}, {Name: "b", Type: "bool"}}},
+	}}
+	hashA, errA := NormalizedHashV2(a)
+	hashB, errB := NormalizedHashV2(b)
+	if errA != nil || errB != nil {
+		t.Fatalf("NormalizedHashV2() errors = %v, %v", errA, errB)
+	}
+	if hashA != hashB {
+		t.Error("NormalizedHashV2() differs for reordered roots, flags and tags")
+	}
+
+	b.Roots["admin"].Short = "Administration"
+	if hashB, _ = NormalizedHashV2(b); hashA == hashB {
+		t.Error("NormalizedHashV2() is the same for a changed root")
+	}
+}
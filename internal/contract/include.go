@@ -0,0 +1,146 @@
+package contract
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// Include is an entry of a contract's or command's include directive. It
+// references a Fragment whose commands are added to the including command's
+// subcommands when the contract is loaded.
+//
+// Example YAML:
+//
+//	use: myapp
+//	short: My application
+//	include:
+//	  - $ref: ./db-contract.yaml
+type Include struct {
+	// Ref is the path of the fragment file, relative to the directory of
+	// the file that includes it
+	Ref string `json:"$ref" yaml:"$ref"`
+}
+
+// Fragment is a contract file holding commands for other contracts to
+// include, such as the commands one Go package of a large CLI defines (see
+// generate --group-by-subpackage). Its commands may include further
+// fragments.
+//
+// Example YAML:
+//
+//	commands:
+//	  - use: db
+//	    short: Manage the database
+type Fragment struct {
+	Commands []Command `json:"commands,omitempty" yaml:"commands,omitempty"`
+}
+
+// resolveIncludes returns commands with the commands of the fragments in
+// includes appended, resolving the includes of every command. Refs are
+// relative to dir, the directory of the file being resolved, and stack holds
+// the files being included, to detect cycles.
+func resolveIncludes(commands []Command, includes []Include, dir string, stack []string) ([]Command, error) {
+	for i := range commands {
+		var err error
+		commands[i].Commands, err = resolveIncludes(commands[i].Commands, commands[i].Include, dir, stack)
+		if err != nil {
+			return nil, err
+		}
+		commands[i].Include = nil
+	}
+
+	for _, include := range includes {
+		if include.Ref == "" {
+			return nil, errors.InvalidContractError{
+				Path:    stack[len(stack)-1],
+				Message: "include entry has no $ref",
+			}
+		}
+		path := include.Ref
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		for _, including := range stack {
+			if including == path {
+				return nil, errors.InvalidContractError{
+					Path:    stack[len(stack)-1],
+					Message: fmt.Sprintf("include cycle: %s", strings.Join(append(stack, path), " -> ")),
+				}
+			}
+		}
+
+		fragment, err := loadFragment(path)
+		if err != nil {
+			return nil, err
+		}
+		included, err := resolveIncludes(fragment.Commands, nil, filepath.Dir(path), append(stack, path))
+		if err != nil {
+			return nil, err
+		}
+		commands = append(commands, included...)
+	}
+	return commands, nil
+}
+
+// resolveContractIncludes resolves the includes of the contract loaded from
+// absPath, leaving no Include set
+func resolveContractIncludes(contract *Contract, absPath string) error {
+	commands, err := resolveIncludes(contract.Commands, contract.Include, filepath.Dir(absPath), []string{absPath})
+	if err != nil {
+		return err
+	}
+	contract.Commands = commands
+	contract.Include = nil
+	return nil
+}
+
+// loadFragment reads and parses the fragment file at path
+func loadFragment(path string) (*Fragment, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.WrapContractNotFound(path, err)
+	}
+
+	var fragment Fragment
+	if err := decodeContractFile(data, path, &fragment); err != nil {
+		return nil, err
+	}
+	return &fragment, nil
+}
+
+// UsesReferences reports whether the contract document includes other files,
+// with include or IncludeTag, or has commands that extend others. Writing
+// back the contract Load returns for such a document would inline them.
+func UsesReferences(data []byte) (bool, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return false, err
+	}
+	return nodeUsesReferences(&doc), nil
+}
+
+// nodeUsesReferences reports whether node or a node under it is an
+// IncludeTag entry or has an include or extends key
+func nodeUsesReferences(node *yaml.Node) bool {
+	if node.Tag == IncludeTag {
+		return true
+	}
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if key := node.Content[i].Value; key == "include" || key == "extends" {
+				return true
+			}
+		}
+	}
+	for _, child := range node.Content {
+		if nodeUsesReferences(child) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,184 @@
+package contract
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// IncludeTag is the YAML tag of an entry in a commands list that is
+// replaced by the commands of another contract or fragment file when the
+// contract is loaded. The path is relative to the directory of the file
+// with the tag. A commands list can also be included whole.
+//
+// Example YAML:
+//
+//	use: myapp
+//	short: My application
+//	commands:
+//	  - use: version
+//	    short: Print the version
+//	  - !include ./submodule/cliguard.yaml
+const IncludeTag = "!include"
+
+// maxIncludeDepth is how deeply IncludeTag entries may nest
+const maxIncludeDepth = 10
+
+// decodeContractFile parses the YAML data of the file at absPath into out,
+// replacing its IncludeTag entries first
+func decodeContractFile(data []byte, absPath string, out interface{}) error {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return errors.ContractParseError{
+			Path:    absPath,
+			Err:     err,
+			Content: string(data),
+		}
+	}
+	if doc.Kind == 0 {
+		return nil // empty file
+	}
+	if err := expandIncludeTags(&doc, filepath.Dir(absPath), []string{absPath}); err != nil {
+		return err
+	}
+	if err := doc.Decode(out); err != nil {
+		return errors.ContractParseError{
+			Path:    absPath,
+			Err:     err,
+			Content: string(data),
+		}
+	}
+	return nil
+}
+
+// expandIncludeTags replaces the IncludeTag nodes under node with the
+// commands of the files they name. Paths are relative to dir, the directory
+// of the file being expanded, and stack holds the files being included, to
+// detect cycles.
+func expandIncludeTags(node *yaml.Node, dir string, stack []string) error {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			if err := expandIncludeTags(child, dir, stack); err != nil {
+				return err
+			}
+		}
+	case yaml.MappingNode:
+		for i := 1; i < len(node.Content); i += 2 {
+			value := node.Content[i]
+			if value.Tag != IncludeTag {
+				if err := expandIncludeTags(value, dir, stack); err != nil {
+					return err
+				}
+				continue
+			}
+			commands, err := includedCommands(value, dir, stack)
+			if err != nil {
+				return err
+			}
+			node.Content[i] = commands
+		}
+	case yaml.SequenceNode:
+		var content []*yaml.Node
+		for _, item := range node.Content {
+			if item.Tag != IncludeTag {
+				if err := expandIncludeTags(item, dir, stack); err != nil {
+					return err
+				}
+				content = append(content, item)
+				continue
+			}
+			commands, err := includedCommands(item, dir, stack)
+			if err != nil {
+				return err
+			}
+			content = append(content, commands.Content...)
+		}
+		node.Content = content
+	}
+	return nil
+}
+
+// includedCommands loads the file an IncludeTag node names and returns its
+// commands list, with its own IncludeTag entries replaced. The file may be a
+// contract or a fragment; only its commands are used.
+func includedCommands(tag *yaml.Node, dir string, stack []string) (*yaml.Node, error) {
+	including := stack[len(stack)-1]
+	if tag.Kind != yaml.ScalarNode || tag.Value == "" {
+		return nil, errors.InvalidContractError{
+			Path:    including,
+			Message: fmt.Sprintf("line %d: %s needs the path of a file", tag.Line, IncludeTag),
+		}
+	}
+
+	path := tag.Value
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	for _, file := range stack {
+		if file == path {
+			return nil, errors.InvalidContractError{
+				Path:    including,
+				Message: fmt.Sprintf("include cycle: %s", strings.Join(append(stack, path), " -> ")),
+			}
+		}
+	}
+	if len(stack) > maxIncludeDepth {
+		return nil, errors.InvalidContractError{
+			Path:    including,
+			Message: fmt.Sprintf("%s entries are nested more than %d levels deep", IncludeTag, maxIncludeDepth),
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.WrapContractNotFound(path, err)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, errors.ContractParseError{
+			Path:    path,
+			Err:     err,
+			Content: string(data),
+		}
+	}
+
+	commands := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return commands, nil
+	}
+	root := doc.Content[0]
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == "commands" {
+			commands = root.Content[i+1]
+			break
+		}
+	}
+
+	if err := expandIncludeTags(commands, filepath.Dir(path), append(stack, path)); err != nil {
+		return nil, err
+	}
+	absoluteRefs(commands, filepath.Dir(path))
+	return commands, nil
+}
+
+// absoluteRefs makes the $ref paths of the include directives under node,
+// which are relative to dir, absolute, so that they still resolve once the
+// commands are moved into another file
+func absoluteRefs(node *yaml.Node, dir string) {
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Value == "$ref" && value.Kind == yaml.ScalarNode && value.Value != "" && !filepath.IsAbs(value.Value) {
+				value.Value = filepath.Join(dir, value.Value)
+			}
+		}
+	}
+	for _, child := range node.Content {
+		absoluteRefs(child, dir)
+	}
+}
@@ -0,0 +1,160 @@
+package contract
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoad_IncludeTag(t *testing.T) {
+	dir := t.TempDir()
+	writeContractFiles(t, dir, map[string]string{
+		"cliguard.yaml": `use: app
+short: App
+commands:
+  - use: version
+    short: Print the version
+  - !include ./db/cliguard.yaml
+  - use: serve
+    short: Serve
+`,
+		"db/cliguard.yaml": `use: db-tool
+short: Standalone database tool
+commands:
+  - use: db
+    short: Manage the database
+    commands: !include migrate.yaml
+    include:
+      - $ref: backup.yaml
+`,
+		"db/migrate.yaml": `commands:
+  - use: migrate
+    short: Run migrations
+  - use: rollback
+    short: Roll back migrations
+`,
+		"db/backup.yaml": `commands:
+  - use: backup
+    short: Back up the database
+`,
+	})
+
+	c, err := Load(filepath.Join(dir, "cliguard.yaml"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	var uses []string
+	for _, cmd := range c.Commands {
+		uses = append(uses, cmd.Use)
+	}
+	if got := strings.Join(uses, ","); got != "version,db,serve" {
+		t.Fatalf("commands = %s, want version,db,serve", got)
+	}
+	uses = nil
+	for _, cmd := range c.Commands[1].Commands {
+		uses = append(uses, cmd.Use)
+	}
+	if got := strings.Join(uses, ","); got != "migrate,rollback,backup" {
+		t.Errorf("db commands = %s, want migrate,rollback,backup", got)
+	}
+
+	if _, err := CheckFields(filepath.Join(dir, "cliguard.yaml"), true); err != nil {
+		t.Errorf("CheckFields() strict error = %v", err)
+	}
+}
+
+func TestLoadV2_IncludeTag(t *testing.T) {
+	dir := t.TempDir()
+	writeContractFiles(t, dir, map[string]string{
+		"cliguard.yaml": `version: 2.0.0
+roots:
+  app:
+    use: app
+    short: App
+    commands:
+      - !include db.yaml
+`,
+		"db.yaml": "commands:\n  - use: db\n    short: Manage the database\n",
+	})
+
+	c, err := LoadV2(filepath.Join(dir, "cliguard.yaml"))
+	if err != nil {
+		t.Fatalf("LoadV2() error = %v", err)
+	}
+	if commands := c.Roots["app"].Commands; len(commands) != 1 || commands[0].Use != "db" {
+		t.Errorf("app commands = %+v, want the included db", commands)
+	}
+}
+
+func TestLoad_IncludeTagErrors(t *testing.T) {
+	deep := map[string]string{
+		"cliguard.yaml": "use: app\nshort: App\ncommands:\n  - !include f1.yaml\n",
+	}
+	for i := 1; i <= maxIncludeDepth+1; i++ {
+		deep[fmt.Sprintf("f%d.yaml", i)] = fmt.Sprintf("commands:\n  - use: c%d\n    short: C\n    commands:\n      - !include f%d.yaml\n", i, i+1)
+	}
+	deep[fmt.Sprintf("f%d.yaml", maxIncludeDepth+2)] = "commands: []\n"
+
+	tests := []struct {
+		name    string
+		files   map[string]string
+		wantErr string
+	}{
+		{
+			name: "missing file",
+			files: map[string]string{
+				"cliguard.yaml": "use: app\nshort: App\ncommands:\n  - !include missing.yaml\n",
+			},
+			wantErr: "missing.yaml",
+		},
+		{
+			name: "missing file relative to including file",
+			files: map[string]string{
+				"cliguard.yaml": "use: app\nshort: App\ncommands:\n  - !include sub/a.yaml\n",
+				"sub/a.yaml":    "commands:\n  - !include sub/b.yaml\n",
+				"sub/b.yaml":    "commands:\n  - use: b\n    short: B\n",
+			},
+			wantErr: filepath.Join("sub", "sub", "b.yaml"),
+		},
+		{
+			name: "empty path",
+			files: map[string]string{
+				"cliguard.yaml": "use: app\nshort: App\ncommands:\n  - !include \"\"\n",
+			},
+			wantErr: "!include needs the path of a file",
+		},
+		{
+			name: "cycle",
+			files: map[string]string{
+				"cliguard.yaml": "use: app\nshort: App\ncommands:\n  - !include a.yaml\n",
+				"a.yaml":        "commands:\n  - !include b.yaml\n",
+				"b.yaml":        "commands:\n  - !include a.yaml\n",
+			},
+			wantErr: "include cycle",
+		},
+		{
+			name: "self include",
+			files: map[string]string{
+				"cliguard.yaml": "use: app\nshort: App\ncommands:\n  - !include cliguard.yaml\n",
+			},
+			wantErr: "include cycle",
+		},
+		{
+			name:    "too deep",
+			files:   deep,
+			wantErr: "nested more than 10 levels deep",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeContractFiles(t, dir, tt.files)
+			_, err := Load(filepath.Join(dir, "cliguard.yaml"))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Load() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
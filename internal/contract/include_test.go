@@ -0,0 +1,172 @@
+package contract
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeContractFiles writes files, keyed by path relative to dir, to dir
+func writeContractFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLoad_Include(t *testing.T) {
+	dir := t.TempDir()
+	writeContractFiles(t, dir, map[string]string{
+		"cliguard.yaml": `use: app
+short: App
+commands:
+  - use: version
+    short: Print the version
+include:
+  - $ref: ./db-contract.yaml
+`,
+		"db-contract.yaml": `commands:
+  - use: db
+    short: Manage the database
+    commands:
+      - use: migrate
+        short: Run migrations
+    include:
+      - $ref: backup/backup-contract.yaml
+`,
+		"backup/backup-contract.yaml": `commands:
+  - use: backup
+    short: Back up the database
+    flags:
+      - name: output
+        usage: Output file
+        type: string
+`,
+	})
+
+	c, err := Load(filepath.Join(dir, "cliguard.yaml"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if c.Include != nil {
+		t.Errorf("Include = %+v, want it resolved", c.Include)
+	}
+	if len(c.Commands) != 2 || c.Commands[0].Use != "version" || c.Commands[1].Use != "db" {
+		t.Fatalf("Commands = %+v, want version then the included db", c.Commands)
+	}
+	db := c.Commands[1]
+	if db.Include != nil || len(db.Commands) != 2 || db.Commands[0].Use != "migrate" || db.Commands[1].Use != "backup" {
+		t.Fatalf("db commands = %+v, want migrate then the included backup", db.Commands)
+	}
+	if flags := db.Commands[1].Flags; len(flags) != 1 || flags[0].Name != "output" {
+		t.Errorf("backup flags = %+v, want --output", flags)
+	}
+}
+
+func TestLoadV2_Include(t *testing.T) {
+	dir := t.TempDir()
+	writeContractFiles(t, dir, map[string]string{
+		"cliguard.yaml": `version: 2.0.0
+roots:
+  app:
+    use: app
+    short: App
+    include:
+      - $ref: db-contract.yaml
+`,
+		"db-contract.yaml": `commands:
+  - use: db
+    short: Manage the database
+`,
+	})
+
+	c, err := LoadV2(filepath.Join(dir, "cliguard.yaml"))
+	if err != nil {
+		t.Fatalf("LoadV2() error = %v", err)
+	}
+	if commands := c.Roots["app"].Commands; len(commands) != 1 || commands[0].Use != "db" {
+		t.Errorf("app commands = %+v, want the included db", commands)
+	}
+}
+
+func TestLoad_IncludeErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		wantErr string
+	}{
+		{
+			name: "missing fragment",
+			files: map[string]string{
+				"cliguard.yaml": "use: app\nshort: App\ninclude:\n  - $ref: missing.yaml\n",
+			},
+			wantErr: "missing.yaml",
+		},
+		{
+			name: "empty ref",
+			files: map[string]string{
+				"cliguard.yaml": "use: app\nshort: App\ninclude:\n  - {}\n",
+			},
+			wantErr: "include entry has no $ref",
+		},
+		{
+			name: "cycle",
+			files: map[string]string{
+				"cliguard.yaml": "use: app\nshort: App\ninclude:\n  - $ref: a.yaml\n",
+				"a.yaml":        "commands:\n  - use: a\n    short: A\n    include:\n      - $ref: b.yaml\n",
+				"b.yaml":        "commands:\n  - use: b\n    short: B\n    include:\n      - $ref: a.yaml\n",
+			},
+			wantErr: "include cycle",
+		},
+		{
+			name: "invalid included command",
+			files: map[string]string{
+				"cliguard.yaml": "use: app\nshort: App\ninclude:\n  - $ref: a.yaml\n",
+				"a.yaml":        "commands:\n  - short: No use\n",
+			},
+			wantErr: "'use' field cannot be empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeContractFiles(t, dir, tt.files)
+			_, err := Load(filepath.Join(dir, "cliguard.yaml"))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Load() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUsesReferences(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want bool
+	}{
+		{name: "plain", data: "use: app\nshort: An app\ncommands:\n  - use: serve\n    short: Uses include and extends in text\n", want: false},
+		{name: "include", data: "use: app\ninclude:\n  - $ref: ./db.yaml\n", want: true},
+		{name: "include tag", data: "use: app\ncommands:\n  - !include ./db.yaml\n", want: true},
+		{name: "extends", data: "use: app\ncommands:\n  - use: a\n    extends: b\n", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UsesReferences([]byte(tt.data))
+			if err != nil {
+				t.Fatalf("UsesReferences() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("UsesReferences() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package contract
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RootPath is the path of the root command in a LineIndex
+const RootPath = "root"
+
+// LineIndex maps the paths used in validation errors to the line of the
+// contract file that defines them. Paths are "root" for the root command,
+// the command names joined by spaces for subcommands (e.g. "db migrate"),
+// and the command path followed by "--" and the flag name for flags (e.g.
+// "db migrate --dry-run", or "--verbose" for a root flag).
+type LineIndex map[string]int
+
+// LoadLineIndex reads the contract at contractPath and indexes the lines of
+// its commands and flags. For v2 contracts, rootName selects the root to
+// index; it is ignored for v1 contracts.
+func LoadLineIndex(contractPath, rootName string) (LineIndex, error) {
+	path, err := localPath(contractPath)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read contract: %w", err)
+	}
+	return IndexLines(data, rootName)
+}
+
+// IndexLines indexes the lines of the commands and flags in a contract
+// document. For v2 contracts, rootName selects the root to index.
+func IndexLines(data []byte, rootName string) (LineIndex, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse contract: %w", err)
+	}
+	index := make(LineIndex)
+	if len(doc.Content) == 0 {
+		return index, nil
+	}
+
+	root := doc.Content[0]
+	if roots := mappingValue(root, "roots"); roots != nil {
+		root = mappingValue(roots, rootName)
+		if root == nil {
+			return nil, fmt.Errorf("contract has no root named '%s'", rootName)
+		}
+	}
+	if root.Kind != yaml.MappingNode {
+		return index, nil
+	}
+
+	index[RootPath] = keyLine(root, "use")
+	index.addFlags("", mappingValue(root, "flags"))
+	index.addCommands("", "", mappingValue(root, "commands"))
+	return index, nil
+}
+
+// Line returns the line defining path. Paths that are not in the contract,
+// such as unexpected commands and flags, resolve to their closest parent
+// command, and finally to the root command. It returns 0 if the index is
+// empty.
+func (l LineIndex) Line(path string) int {
+	for path != "" {
+		if line, ok := l[path]; ok {
+			return line
+		}
+		i := strings.LastIndex(path, " ")
+		if i < 0 {
+			break
+		}
+		path = path[:i]
+	}
+	return l[RootPath]
+}
+
+// addCommands indexes a sequence of commands and their children, under
+// paths of their Use fields and, for errors from validating only command
+// names, paths of their names
+func (l LineIndex) addCommands(parentPath, parentNames string, commands *yaml.Node) {
+	if commands == nil || commands.Kind != yaml.SequenceNode {
+		return
+	}
+	for _, cmd := range commands.Content {
+		use := mappingValue(cmd, "use")
+		if use == nil {
+			continue
+		}
+		path := use.Value
+		if parentPath != "" {
+			path = parentPath + " " + strings.TrimSpace(use.Value)
+		}
+		names := CommandName(use.Value)
+		if parentNames != "" {
+			names = parentNames + " " + names
+		}
+		l[path] = cmd.Line
+		l.addFlags(path, mappingValue(cmd, "flags"))
+		if _, ok := l[names]; !ok {
+			l[names] = cmd.Line
+			l.addFlags(names, mappingValue(cmd, "flags"))
+		}
+		l.addCommands(path, names, mappingValue(cmd, "commands"))
+	}
+}
+
+// addFlags indexes a sequence of flags of the command at cmdPath
+func (l LineIndex) addFlags(cmdPath string, flags *yaml.Node) {
+	if flags == nil || flags.Kind != yaml.SequenceNode {
+		return
+	}
+	for _, flag := range flags.Content {
+		name := mappingValue(flag, "name")
+		if name == nil {
+			continue
+		}
+		path := "--" + name.Value
+		if cmdPath != "" {
+			path = cmdPath + " " + path
+		}
+		l[path] = flag.Line
+	}
+}
+
+// mappingValue returns the value of key in a mapping node, or nil
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// keyLine returns the line of key in a mapping node, or of the node itself
+// if the key is missing
+func keyLine(node *yaml.Node, key string) int {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i].Line
+		}
+	}
+	return node.Line
+}
@@ -0,0 +1,114 @@
+package contract
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const linesContract = `use: app
+short: My app
+flags:
+  - name: verbose
+    type: bool
+commands:
+  - use: db
+    short: Database commands
+    commands:
+      - use: migrate [version]
+        flags:
+          - name: dry-run
+            type: bool
+  - use: serve
+`
+
+func TestIndexLines(t *testing.T) {
+	index, err := IndexLines([]byte(linesContract), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := LineIndex{
+		"root":                           1,
+		"--verbose":                      4,
+		"db":                             7,
+		"db migrate [version]":           10,
+		"db migrate [version] --dry-run": 12,
+		"serve":                          14,
+
+		// Paths of the command names, for errors from validating names only
+		"db migrate":           10,
+		"db migrate --dry-run": 12,
+	}
+	if !reflect.DeepEqual(index, want) {
+		t.Errorf("IndexLines() = %v, want %v", index, want)
+	}
+}
+
+func TestLineIndex_Line(t *testing.T) {
+	index, err := IndexLines([]byte(linesContract), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]int{
+		"root":                 1,
+		"--verbose":            4,
+		"serve":                14,
+		"serve --port":         14, // unexpected flag: its command
+		"db extra":             7,  // unexpected command: its parent
+		"--unexpected":         1,  // unexpected root flag: the root
+		"unknown":              1,
+		"db migrate [version]": 10,
+	}
+	for path, want := range tests {
+		if got := index.Line(path); got != want {
+			t.Errorf("Line(%q) = %d, want %d", path, got, want)
+		}
+	}
+
+	if got := LineIndex(nil).Line("root"); got != 0 {
+		t.Errorf("Line() of an empty index = %d, want 0", got)
+	}
+}
+
+func TestIndexLines_V2(t *testing.T) {
+	data := `version: 2.0.0
+roots:
+  app:
+    use: app
+  admin:
+    use: admin
+    flags:
+      - name: force
+        type: bool
+`
+	index, err := IndexLines([]byte(data), "admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if index.Line("root") != 6 || index.Line("--force") != 8 {
+		t.Errorf("IndexLines() = %v, want the admin root", index)
+	}
+
+	if _, err := IndexLines([]byte(data), "missing"); err == nil || !strings.Contains(err.Error(), "no root named 'missing'") {
+		t.Errorf("IndexLines() error = %v, want missing root", err)
+	}
+}
+
+func TestLoadLineIndex(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cliguard.yaml")
+	if err := os.WriteFile(path, []byte(linesContract), 0644); err != nil {
+		t.Fatal(err)
+	}
+	index, err := LoadLineIndex(path, "")
+	if err != nil || index.Line("serve") != 14 {
+		t.Errorf("LoadLineIndex() = %v, %v", index, err)
+	}
+
+	if _, err := LoadLineIndex(filepath.Join(t.TempDir(), "missing.yaml"), ""); err == nil {
+		t.Error("LoadLineIndex() of a missing file should fail")
+	}
+}
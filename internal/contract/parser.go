@@ -2,22 +2,26 @@ package contract
 
 import (
 	"fmt"
+	"net"
 	"os"
-	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/hiAndrewQuinn/cliguard/internal/errors"
-	"gopkg.in/yaml.v3"
 )
 
-// Load reads and parses a contract file
+// Load reads and parses a contract file, adding the commands of the
+// fragments it includes and the flags and subcommands of the commands its
+// commands extend
 func Load(contractPath string) (*Contract, error) {
 	if contractPath == "" {
 		return nil, fmt.Errorf("contract path cannot be empty")
 	}
 
-	absPath, err := filepath.Abs(contractPath)
+	absPath, err := localPath(contractPath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to resolve contract path: %w", err)
+		return nil, err
 	}
 
 	data, err := os.ReadFile(absPath)
@@ -26,17 +30,20 @@ func Load(contractPath string) (*Contract, error) {
 	}
 
 	var contract Contract
-	if err := yaml.Unmarshal(data, &contract); err != nil {
-		return nil, errors.ContractParseError{
-			Path:    absPath,
-			Err:     err,
-			Content: string(data),
-		}
+	if err := decodeContractFile(data, absPath, &contract); err != nil {
+		return nil, err
+	}
+
+	if err := resolveContractIncludes(&contract, absPath); err != nil {
+		return nil, err
+	}
+	if err := resolveContractExtends(&contract, absPath); err != nil {
+		return nil, err
 	}
 
 	if err := validate(&contract); err != nil {
 		return nil, errors.InvalidContractError{
-			Path:    absPath,
+			Path:    displayPath(contractPath, absPath),
 			Message: err.Error(),
 		}
 	}
@@ -44,6 +51,16 @@ func Load(contractPath string) (*Contract, error) {
 	return &contract, nil
 }
 
+// displayPath is the path to show in errors about the contract at
+// contractPath, read from absPath: the URL it was downloaded from, or the
+// file itself
+func displayPath(contractPath, absPath string) string {
+	if IsURL(contractPath) {
+		return contractPath
+	}
+	return absPath
+}
+
 // validate performs basic validation on the contract
 func validate(contract *Contract) error {
 	if contract.Use == "" {
@@ -54,6 +71,13 @@ func validate(contract *Contract) error {
 	if err := validateFlags(contract.Flags); err != nil {
 		return fmt.Errorf("root command flags: %w", err)
 	}
+	if err := validateFlagGroups(contract.MutuallyExclusive, contract.RequiredTogether); err != nil {
+		return fmt.Errorf("root command: %w", err)
+	}
+
+	if err := validateSortOrders(contract.Commands); err != nil {
+		return fmt.Errorf("root command subcommands: %w", err)
+	}
 
 	// Validate all subcommands recursively
 	for _, cmd := range contract.Commands {
@@ -75,6 +99,13 @@ func validateCommand(cmd *Command, parentPath string) error {
 	if err := validateFlags(cmd.Flags); err != nil {
 		return fmt.Errorf("command '%s' flags: %w", currentPath, err)
 	}
+	if err := validateFlagGroups(cmd.MutuallyExclusive, cmd.RequiredTogether); err != nil {
+		return fmt.Errorf("command '%s': %w", currentPath, err)
+	}
+
+	if err := validateSortOrders(cmd.Commands); err != nil {
+		return fmt.Errorf("command '%s' subcommands: %w", currentPath, err)
+	}
 
 	for _, subcmd := range cmd.Commands {
 		if err := validateCommand(&subcmd, currentPath); err != nil {
@@ -85,6 +116,48 @@ func validateCommand(cmd *Command, parentPath string) error {
 	return nil
 }
 
+// validateSortOrders checks that sibling commands do not share a sort order
+func validateSortOrders(commands []Command) error {
+	seen := make(map[int]string)
+	for _, cmd := range commands {
+		if cmd.SortOrder == 0 {
+			continue
+		}
+		if cmd.SortOrder < 0 {
+			return fmt.Errorf("command '%s': sort_order must be positive, got %d", cmd.Use, cmd.SortOrder)
+		}
+		if other, ok := seen[cmd.SortOrder]; ok {
+			return fmt.Errorf("commands '%s' and '%s' have the same sort_order %d", other, cmd.Use, cmd.SortOrder)
+		}
+		seen[cmd.SortOrder] = cmd.Use
+	}
+	return nil
+}
+
+// validateFlagGroups checks that each mutually exclusive and required
+// together group names at least two different flags. The flags may be
+// inherited, so they are not looked up.
+func validateFlagGroups(mutuallyExclusive, requiredTogether [][]string) error {
+	for _, kind := range []struct {
+		field  string
+		groups [][]string
+	}{{"mutually_exclusive", mutuallyExclusive}, {"required_together", requiredTogether}} {
+		for i, group := range kind.groups {
+			names := make(map[string]bool)
+			for _, name := range group {
+				if name == "" {
+					return fmt.Errorf("%s group %d has an empty flag name", kind.field, i+1)
+				}
+				names[name] = true
+			}
+			if len(names) < 2 {
+				return fmt.Errorf("%s group %d must list at least two different flags", kind.field, i+1)
+			}
+		}
+	}
+	return nil
+}
+
 func validateFlags(flags []Flag) error {
 	seenNames := make(map[string]bool)
 	seenShorthands := make(map[string]bool)
@@ -103,6 +176,9 @@ func validateFlags(flags []Flag) error {
 			if len(flag.Shorthand) != 1 {
 				return fmt.Errorf("flag shorthand must be a single character: %s", flag.Shorthand)
 			}
+			if !isShorthandChar(flag.Shorthand[0]) {
+				return fmt.Errorf("flag shorthand must be an ASCII letter or digit: %s", flag.Shorthand)
+			}
 			if seenShorthands[flag.Shorthand] {
 				return fmt.Errorf("duplicate flag shorthand: %s", flag.Shorthand)
 			}
@@ -129,7 +205,7 @@ func validateFlags(flags []Flag) error {
 			// Slice types
 			"intSlice": true, "int32Slice": true, "int64Slice": true,
 			"uintSlice": true, "float32Slice": true, "float64Slice": true,
-			"boolSlice": true, "durationSlice": true,
+			"boolSlice": true, "durationSlice": true, "stringArray": true,
 
 			// Map types
 			"stringToString": true, "stringToInt64": true,
@@ -154,7 +230,74 @@ func validateFlags(flags []Flag) error {
 				ValidTypes:  validTypesList,
 			}
 		}
+
+		if err := validateDefault(flag); err != nil {
+			return err
+		}
+
+		switch flag.Completion {
+		case "", CompletionNone, CompletionFile, CompletionDir, CompletionCustom:
+		default:
+			return fmt.Errorf("flag '%s': invalid completion '%s' (expected none, file, dir or custom)", flag.Name, flag.Completion)
+		}
 	}
 
 	return nil
 }
+
+// isShorthandChar reports whether c can be used as a flag shorthand
+func isShorthandChar(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// validateDefault checks that a flag's default value can be parsed as its type
+func validateDefault(flag Flag) error {
+	if flag.Default == "" {
+		return nil
+	}
+
+	var err error
+	switch flag.Type {
+	case "bool":
+		_, err = strconv.ParseBool(flag.Default)
+	case "int", "int64", "count":
+		_, err = strconv.ParseInt(flag.Default, 0, 64)
+	case "int8":
+		_, err = strconv.ParseInt(flag.Default, 0, 8)
+	case "int16":
+		_, err = strconv.ParseInt(flag.Default, 0, 16)
+	case "int32":
+		_, err = strconv.ParseInt(flag.Default, 0, 32)
+	case "uint", "uint64":
+		_, err = strconv.ParseUint(flag.Default, 0, 64)
+	case "uint8":
+		_, err = strconv.ParseUint(flag.Default, 0, 8)
+	case "uint16":
+		_, err = strconv.ParseUint(flag.Default, 0, 16)
+	case "uint32":
+		_, err = strconv.ParseUint(flag.Default, 0, 32)
+	case "float32":
+		_, err = strconv.ParseFloat(flag.Default, 32)
+	case "float64":
+		_, err = strconv.ParseFloat(flag.Default, 64)
+	case "duration":
+		_, err = time.ParseDuration(flag.Default)
+	case "ip":
+		// pflag prints a nil IP as "<nil>"
+		if flag.Default != "<nil>" && net.ParseIP(flag.Default) == nil {
+			err = fmt.Errorf("invalid IP address")
+		}
+	default:
+		// Slice and map values are printed in brackets by pflag
+		if strings.HasSuffix(flag.Type, "Slice") || strings.HasSuffix(flag.Type, "Array") || strings.HasPrefix(flag.Type, "stringTo") {
+			if !strings.HasPrefix(flag.Default, "[") || !strings.HasSuffix(flag.Default, "]") {
+				err = fmt.Errorf("expected a bracketed list such as [a,b]")
+			}
+		}
+	}
+
+	if err != nil {
+		return fmt.Errorf("flag '%s': default '%s' is not a valid %s", flag.Name, flag.Default, flag.Type)
+	}
+	return nil
+}
@@ -160,6 +160,211 @@ flags:
 			wantErr:     true,
 			errContains: "flag shorthand must be a single character: cfg",
 		},
+		{
+			name: "invalid_non_alphanumeric_shorthand",
+			yamlContent: `
+use: testcli
+short: Test CLI
+flags:
+  - name: config
+    shorthand: "?"
+    type: string
+    usage: Config file
+`,
+			wantErr:     true,
+			errContains: "flag shorthand must be an ASCII letter or digit: ?",
+		},
+		{
+			name: "duplicate_sort_order",
+			yamlContent: `
+use: testcli
+short: Test CLI
+commands:
+  - use: get
+    short: Get
+    sort_order: 1
+  - use: describe
+    short: Describe
+    sort_order: 1
+`,
+			wantErr:     true,
+			errContains: "commands 'get' and 'describe' have the same sort_order 1",
+		},
+		{
+			name: "negative_sort_order",
+			yamlContent: `
+use: testcli
+short: Test CLI
+commands:
+  - use: remote
+    short: Remote
+    commands:
+      - use: add
+        short: Add
+        sort_order: -1
+`,
+			wantErr:     true,
+			errContains: "command 'testcli remote' subcommands: command 'add': sort_order must be positive, got -1",
+		},
+		{
+			name: "flag_groups",
+			yamlContent: `
+use: testcli
+short: Test CLI
+mutually_exclusive:
+  - [json, yaml]
+commands:
+  - use: login
+    short: Login
+    required_together:
+      - [username, password]
+`,
+			wantErr: false,
+		},
+		{
+			name: "single_flag_group",
+			yamlContent: `
+use: testcli
+short: Test CLI
+commands:
+  - use: login
+    short: Login
+    mutually_exclusive:
+      - [token, username]
+      - [password, password]
+`,
+			wantErr:     true,
+			errContains: "command 'testcli login': mutually_exclusive group 2 must list at least two different flags",
+		},
+		{
+			name: "flag_completion",
+			yamlContent: `
+use: testcli
+short: Test CLI
+flags:
+  - name: config
+    type: string
+    usage: Config file
+    completion: file
+`,
+			wantErr: false,
+		},
+		{
+			name: "flag_defaults",
+			yamlContent: `
+use: testcli
+short: Test CLI
+flags:
+  - name: port
+    type: int
+    usage: Port
+    default: "8080"
+  - name: timeout
+    type: duration
+    usage: Timeout
+    default: 30s
+  - name: tags
+    type: stringSlice
+    usage: Tags
+    default: "[a,b]"
+  - name: rules
+    type: stringArray
+    usage: Rules
+    default: "[]"
+  - name: bind
+    type: ip
+    usage: Bind address
+    default: "<nil>"
+`,
+			wantErr: false,
+		},
+		{
+			name: "invalid_int_default",
+			yamlContent: `
+use: testcli
+short: Test CLI
+flags:
+  - name: port
+    type: int
+    usage: Port
+    default: abc
+`,
+			wantErr:     true,
+			errContains: "flag 'port': default 'abc' is not a valid int",
+		},
+		{
+			name: "out_of_range_default",
+			yamlContent: `
+use: testcli
+short: Test CLI
+commands:
+  - use: serve
+    short: Serve
+    flags:
+      - name: level
+        type: uint8
+        usage: Level
+        default: "300"
+`,
+			wantErr:     true,
+			errContains: "flag 'level': default '300' is not a valid uint8",
+		},
+		{
+			name: "invalid_slice_default",
+			yamlContent: `
+use: testcli
+short: Test CLI
+flags:
+  - name: tags
+    type: stringSlice
+    usage: Tags
+    default: a,b
+`,
+			wantErr:     true,
+			errContains: "flag 'tags': default 'a,b' is not a valid stringSlice",
+		},
+		{
+			name: "invalid_flag_completion",
+			yamlContent: `
+use: testcli
+short: Test CLI
+flags:
+  - name: config
+    type: string
+    usage: Config file
+    completion: filename
+`,
+			wantErr:     true,
+			errContains: "flag 'config': invalid completion 'filename'",
+		},
+		{
+			name: "command_aliases",
+			yamlContent: `
+use: testcli
+short: Test CLI
+aliases: [tc]
+commands:
+  - use: delete
+    short: Delete a resource
+    aliases:
+      - del
+      - rm
+  - use: get
+    short: Get a resource
+`,
+			wantErr: false,
+			validate: func(t *testing.T, c *Contract) {
+				if len(c.Aliases) != 1 || c.Aliases[0] != "tc" {
+					t.Errorf("Aliases = %v, want [tc]", c.Aliases)
+				}
+				if got := c.Commands[0].Aliases; len(got) != 2 || got[0] != "del" || got[1] != "rm" {
+					t.Errorf("Commands[0].Aliases = %v, want [del rm]", got)
+				}
+				if c.Commands[1].Aliases != nil {
+					t.Errorf("Commands[1].Aliases = %v, want none", c.Commands[1].Aliases)
+				}
+			},
+		},
 		{
 			name: "nested_commands",
 			yamlContent: `
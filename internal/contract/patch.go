@@ -0,0 +1,203 @@
+package contract
+
+import "strings"
+
+// ContractPatch is a list of changes to a contract, applied in order by
+// Contract.Apply. Build one by chaining its methods from NewPatch:
+//
+//	patch := NewPatch().SetVersion("2.0.0").DeprecateFlag("", "verbose", "use --log-level")
+//	updated := c.Apply(patch)
+//
+// Commands are given by path: the names of the commands below the root,
+// separated by spaces, e.g. "db migrate", with "" for the root command.
+// Changes to a command the contract doesn't have are skipped.
+//
+// The methods return a new patch and leave the one they are called on
+// unchanged, so a patch can be shared as the base of others.
+type ContractPatch struct {
+	ops []patchOp
+}
+
+// patchOp is one change of a ContractPatch, made to a copy of the contract
+type patchOp func(c *Contract)
+
+// NewPatch returns an empty patch
+func NewPatch() ContractPatch {
+	return ContractPatch{}
+}
+
+// with returns a copy of the patch with op added
+func (p ContractPatch) with(op patchOp) ContractPatch {
+	// The full slice expression makes append copy, so patches built from
+	// the same base don't share their operations
+	return ContractPatch{ops: append(p.ops[:len(p.ops):len(p.ops)], op)}
+}
+
+// SetVersion sets the contract's version field
+func (p ContractPatch) SetVersion(version string) ContractPatch {
+	return p.with(func(c *Contract) {
+		c.Version = version
+	})
+}
+
+// DeprecateFlag sets the deprecation message of the flag flagName of the
+// command at commandPath. It is skipped if the command has no such flag.
+func (p ContractPatch) DeprecateFlag(commandPath, flagName, message string) ContractPatch {
+	return p.with(func(c *Contract) {
+		flags, _ := findPatchTarget(c, commandPath)
+		if flags == nil {
+			return
+		}
+		for i := range *flags {
+			if (*flags)[i].Name == flagName {
+				(*flags)[i].Deprecated = message
+			}
+		}
+	})
+}
+
+// RemoveCommand removes the command at path, with its subcommands. The root
+// command can't be removed.
+func (p ContractPatch) RemoveCommand(path string) ContractPatch {
+	return p.with(func(c *Contract) {
+		names := strings.Fields(path)
+		if len(names) == 0 {
+			return
+		}
+		_, commands := findPatchTarget(c, strings.Join(names[:len(names)-1], " "))
+		if commands == nil {
+			return
+		}
+		name := names[len(names)-1]
+		var kept []Command
+		for _, cmd := range *commands {
+			if CommandName(cmd.Use) != name {
+				kept = append(kept, cmd)
+			}
+		}
+		*commands = kept
+	})
+}
+
+// AddFlag adds the flag to the command at commandPath, replacing its flag
+// of the same name if it has one
+func (p ContractPatch) AddFlag(commandPath string, flag Flag) ContractPatch {
+	flag = cloneFlags([]Flag{flag})[0]
+	return p.with(func(c *Contract) {
+		flags, _ := findPatchTarget(c, commandPath)
+		if flags == nil {
+			return
+		}
+		// Each contract the patch is applied to gets its own copy
+		added := cloneFlags([]Flag{flag})[0]
+		for i := range *flags {
+			if (*flags)[i].Name == flag.Name {
+				(*flags)[i] = added
+				return
+			}
+		}
+		*flags = append(*flags, added)
+	})
+}
+
+// Apply returns a copy of the contract with the changes of the patch made
+// in order. The original contract is not modified.
+func (c *Contract) Apply(patch ContractPatch) *Contract {
+	patched := cloneContract(c)
+	for _, op := range patch.ops {
+		op(patched)
+	}
+	return patched
+}
+
+// findPatchTarget returns the flags and subcommands of the command at path,
+// or nil if the contract has no such command
+func findPatchTarget(c *Contract, path string) (*[]Flag, *[]Command) {
+	flags, commands := &c.Flags, &c.Commands
+	for _, name := range strings.Fields(path) {
+		var found *Command
+		for i := range *commands {
+			if CommandName((*commands)[i].Use) == name {
+				found = &(*commands)[i]
+				break
+			}
+		}
+		if found == nil {
+			return nil, nil
+		}
+		flags, commands = &found.Flags, &found.Commands
+	}
+	return flags, commands
+}
+
+// cloneContract returns a deep copy of the contract, so a patch can change
+// it without changing the original
+func cloneContract(c *Contract) *Contract {
+	cloned := *c
+	cloned.Flags = cloneFlags(c.Flags)
+	cloned.Aliases = cloneStrings(c.Aliases)
+	cloned.MutuallyExclusive = cloneFlagGroups(c.MutuallyExclusive)
+	cloned.RequiredTogether = cloneFlagGroups(c.RequiredTogether)
+	cloned.Commands = cloneCommands(c.Commands)
+	if c.Include != nil {
+		cloned.Include = append([]Include(nil), c.Include...)
+	}
+	return &cloned
+}
+
+// cloneCommands returns deep copies of the commands for cloneContract
+func cloneCommands(commands []Command) []Command {
+	if commands == nil {
+		return nil
+	}
+	result := make([]Command, len(commands))
+	for i, cmd := range commands {
+		cmd.Flags = cloneFlags(cmd.Flags)
+		cmd.Aliases = cloneStrings(cmd.Aliases)
+		cmd.MutuallyExclusive = cloneFlagGroups(cmd.MutuallyExclusive)
+		cmd.RequiredTogether = cloneFlagGroups(cmd.RequiredTogether)
+		cmd.Commands = cloneCommands(cmd.Commands)
+		if cmd.Runnable != nil {
+			runnable := *cmd.Runnable
+			cmd.Runnable = &runnable
+		}
+		if cmd.Include != nil {
+			cmd.Include = append([]Include(nil), cmd.Include...)
+		}
+		result[i] = cmd
+	}
+	return result
+}
+
+// cloneFlags returns deep copies of the flags for cloneContract
+func cloneFlags(flags []Flag) []Flag {
+	if flags == nil {
+		return nil
+	}
+	result := make([]Flag, len(flags))
+	for i, flag := range flags {
+		flag.Enum = cloneStrings(flag.Enum)
+		result[i] = flag
+	}
+	return result
+}
+
+// cloneFlagGroups returns a deep copy of flag groups for cloneContract
+func cloneFlagGroups(groups [][]string) [][]string {
+	if groups == nil {
+		return nil
+	}
+	result := make([][]string, len(groups))
+	for i, group := range groups {
+		result[i] = cloneStrings(group)
+	}
+	return result
+}
+
+// cloneStrings returns a copy of s, or nil if s is nil
+func cloneStrings(s []string) []string {
+	if s == nil {
+		return nil
+	}
+	return append([]string(nil), s...)
+}
@@ -0,0 +1,148 @@
+package contract
+
+import (
+	"reflect"
+	"testing"
+)
+
+// patchTestContract returns a contract with a root flag and nested commands
+func patchTestContract() *Contract {
+	return &Contract{
+		Use:     "app",
+		Short:   "An app",
+		Version: "1.4.2",
+		Flags: []Flag{
+			{Name: "verbose", Usage: "Verbose output", Type: "bool", Persistent: true},
+		},
+		Commands: []Command{
+			{
+				Use:   "db <command>",
+				Short: "Manage the database",
+				Commands: []Command{
+					{
+						Use:   "migrate [version]",
+						Short: "Migrate the database",
+						Flags: []Flag{{Name: "dry-run", Usage: "Print the migrations", Type: "bool"}},
+					},
+					{Use: "seed", Short: "Seed the database"},
+				},
+			},
+			{
+				Use:   "serve",
+				Short: "Serve the app",
+				Flags: []Flag{{Name: "format", Usage: "Log format", Type: "string", Enum: []string{"json", "text"}}},
+			},
+		},
+	}
+}
+
+func TestContractApply(t *testing.T) {
+	t.Run("set version", func(t *testing.T) {
+		patched := patchTestContract().Apply(NewPatch().SetVersion("2.0.0"))
+		if patched.Version != "2.0.0" {
+			t.Errorf("Version = %q, want 2.0.0", patched.Version)
+		}
+	})
+
+	t.Run("deprecate flag", func(t *testing.T) {
+		patched := patchTestContract().Apply(NewPatch().
+			DeprecateFlag("", "verbose", "use --log-level").
+			DeprecateFlag("db migrate", "dry-run", "use plan"))
+		if got := patched.Flags[0].Deprecated; got != "use --log-level" {
+			t.Errorf("root --verbose Deprecated = %q", got)
+		}
+		if got := patched.Commands[0].Commands[0].Flags[0].Deprecated; got != "use plan" {
+			t.Errorf("db migrate --dry-run Deprecated = %q", got)
+		}
+	})
+
+	t.Run("remove command", func(t *testing.T) {
+		patched := patchTestContract().Apply(NewPatch().RemoveCommand("db seed").RemoveCommand("serve"))
+		if len(patched.Commands) != 1 || patched.Commands[0].Use != "db <command>" {
+			t.Fatalf("Commands = %+v, want only db", patched.Commands)
+		}
+		if sub := patched.Commands[0].Commands; len(sub) != 1 || sub[0].Use != "migrate [version]" {
+			t.Errorf("db Commands = %+v, want only migrate", sub)
+		}
+	})
+
+	t.Run("add flag", func(t *testing.T) {
+		patched := patchTestContract().Apply(NewPatch().
+			AddFlag("serve", Flag{Name: "port", Usage: "Port to listen on", Type: "int", Default: "8080"}).
+			AddFlag("serve", Flag{Name: "format", Usage: "Output format", Type: "string"}))
+		flags := patched.Commands[1].Flags
+		want := []Flag{
+			{Name: "format", Usage: "Output format", Type: "string"},
+			{Name: "port", Usage: "Port to listen on", Type: "int", Default: "8080"},
+		}
+		if !reflect.DeepEqual(flags, want) {
+			t.Errorf("serve Flags = %+v, want %+v", flags, want)
+		}
+	})
+
+	t.Run("missing targets are skipped", func(t *testing.T) {
+		original := patchTestContract()
+		patched := original.Apply(NewPatch().
+			DeprecateFlag("db", "verbose", "gone").
+			DeprecateFlag("deploy", "verbose", "gone").
+			RemoveCommand("db rollback").
+			RemoveCommand("").
+			AddFlag("deploy", Flag{Name: "force", Type: "bool"}))
+		if !reflect.DeepEqual(patched, original) {
+			t.Errorf("Apply() = %+v, want the contract unchanged", patched)
+		}
+	})
+
+	t.Run("combined", func(t *testing.T) {
+		patched := patchTestContract().Apply(NewPatch().
+			SetVersion("2.0.0").
+			AddFlag("db", Flag{Name: "dsn", Usage: "Database URL", Type: "string"}).
+			DeprecateFlag("db", "dsn", "use DATABASE_URL").
+			RemoveCommand("db migrate"))
+
+		want := patchTestContract()
+		want.Version = "2.0.0"
+		want.Commands[0].Flags = []Flag{{Name: "dsn", Usage: "Database URL", Type: "string", Deprecated: "use DATABASE_URL"}}
+		want.Commands[0].Commands = want.Commands[0].Commands[1:]
+		if !reflect.DeepEqual(patched, want) {
+			t.Errorf("Apply() = %+v, want %+v", patched, want)
+		}
+	})
+}
+
+func TestContractApply_Immutable(t *testing.T) {
+	original := patchTestContract()
+	patch := NewPatch().
+		SetVersion("2.0.0").
+		DeprecateFlag("", "verbose", "use --log-level").
+		RemoveCommand("db seed").
+		AddFlag("db migrate", Flag{Name: "steps", Type: "int"})
+
+	patched := original.Apply(patch)
+	patched.Commands[1].Flags[0].Enum[0] = "yaml"
+
+	if !reflect.DeepEqual(original, patchTestContract()) {
+		t.Errorf("Apply() modified the original contract: %+v", original)
+	}
+
+	// Applying the same patch again gives the same contract
+	if again := original.Apply(patch); again.Commands[1].Flags[0].Enum[0] != "json" || len(again.Commands[0].Commands[0].Flags) != 2 {
+		t.Errorf("second Apply() = %+v", again)
+	}
+}
+
+func TestContractPatch_SharedBase(t *testing.T) {
+	base := NewPatch().SetVersion("2.0.0")
+	deprecate := base.DeprecateFlag("", "verbose", "use --log-level")
+	remove := base.RemoveCommand("serve")
+
+	if got := patchTestContract().Apply(base); got.Flags[0].Deprecated != "" || len(got.Commands) != 2 {
+		t.Errorf("base patch was changed by the patches built from it: %+v", got)
+	}
+	if got := patchTestContract().Apply(deprecate); got.Flags[0].Deprecated == "" || len(got.Commands) != 2 {
+		t.Errorf("deprecate patch = %+v, want only the flag deprecated", got)
+	}
+	if got := patchTestContract().Apply(remove); got.Flags[0].Deprecated != "" || len(got.Commands) != 1 {
+		t.Errorf("remove patch = %+v, want only serve removed", got)
+	}
+}
@@ -0,0 +1,136 @@
+package contract
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultHTTPTimeout limits how long downloading a contract from a URL can
+// take
+const DefaultHTTPTimeout = 10 * time.Second
+
+// IsURL reports whether a contract path is an http:// or https:// URL
+func IsURL(contractPath string) bool {
+	return strings.HasPrefix(contractPath, "http://") || strings.HasPrefix(contractPath, "https://")
+}
+
+// Fetcher downloads contracts given by URL to temporary files, which the
+// functions taking a contract path then read like any other file
+type Fetcher struct {
+	// Client sends the requests. Defaults to a client with
+	// DefaultHTTPTimeout.
+	Client *http.Client
+
+	// Header is sent with every request, e.g. for an Authorization header
+	Header http.Header
+
+	// NoCache downloads a URL each time it is read. By default it is
+	// downloaded once and its file reused until Cleanup.
+	NoCache bool
+
+	mu    sync.Mutex
+	cache map[string]string
+	files []string
+}
+
+// DefaultFetcher downloads the URLs given to Load, LoadV2 and the other
+// functions of this package taking a contract path
+var DefaultFetcher = &Fetcher{}
+
+// Fetch downloads the contract at rawURL and returns the path of the file
+// it was written to
+func (f *Fetcher) Fetch(rawURL string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if cached, ok := f.cache[rawURL]; ok && !f.NoCache {
+		return cached, nil
+	}
+
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("invalid contract URL '%s': %w", rawURL, err)
+	}
+	for name, values := range f.Header {
+		req.Header[name] = values
+	}
+
+	client := f.Client
+	if client == nil {
+		client = &http.Client{Timeout: DefaultHTTPTimeout}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to download contract: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to download contract from %s: %s", rawURL, resp.Status)
+	}
+
+	// Keep the extension so the file reads like the original
+	ext := ".yaml"
+	if parsed, err := url.Parse(rawURL); err == nil && path.Ext(parsed.Path) != "" {
+		ext = path.Ext(parsed.Path)
+	}
+	file, err := os.CreateTemp("", "cliguard-contract-*"+ext)
+	if err != nil {
+		return "", fmt.Errorf("failed to create file for downloaded contract: %w", err)
+	}
+	defer file.Close()
+	f.files = append(f.files, file.Name())
+	if _, err := io.Copy(file, resp.Body); err != nil {
+		return "", fmt.Errorf("failed to download contract from %s: %w", rawURL, err)
+	}
+
+	if !f.NoCache {
+		if f.cache == nil {
+			f.cache = make(map[string]string)
+		}
+		f.cache[rawURL] = file.Name()
+	}
+	return file.Name(), nil
+}
+
+// Cleanup removes the files the fetcher downloaded
+func (f *Fetcher) Cleanup() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, name := range f.files {
+		os.Remove(name)
+	}
+	f.files = nil
+	f.cache = nil
+}
+
+// ParseHTTPHeader parses a header given as "Name: value", such as
+// "Authorization: Bearer abc123"
+func ParseHTTPHeader(header string) (name, value string, err error) {
+	name, value, found := strings.Cut(header, ":")
+	name = strings.TrimSpace(name)
+	if !found || name == "" || strings.ContainsAny(name, " \t") {
+		return "", "", fmt.Errorf("invalid HTTP header '%s' (expected 'Name: value')", header)
+	}
+	return name, strings.TrimSpace(value), nil
+}
+
+// localPath returns the file to read the contract at contractPath from:
+// its download for a URL, or its absolute path
+func localPath(contractPath string) (string, error) {
+	if IsURL(contractPath) {
+		return DefaultFetcher.Fetch(contractPath)
+	}
+	absPath, err := filepath.Abs(contractPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve contract path: %w", err)
+	}
+	return absPath, nil
+}
@@ -0,0 +1,199 @@
+package contract
+
+import (
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/errors"
+)
+
+// serveContract starts a server returning body for /cliguard.yaml and 404 for
+// anything else, and counts the requests it gets
+func serveContract(t *testing.T, body string, requests *int32, check func(r *http.Request)) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		if check != nil {
+			check(r)
+		}
+		if r.URL.Path != "/cliguard.yaml" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// useFetcher replaces DefaultFetcher with f for the test
+func useFetcher(t *testing.T, f *Fetcher) {
+	t.Helper()
+	original := DefaultFetcher
+	DefaultFetcher = f
+	t.Cleanup(func() {
+		f.Cleanup()
+		DefaultFetcher = original
+	})
+}
+
+func TestLoad_URL(t *testing.T) {
+	var requests int32
+	server := serveContract(t, "use: myapp\nshort: My app\ncommands:\n  - use: serve\n", &requests, func(r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer abc123" {
+			t.Errorf("Authorization header = %q, want Bearer abc123", got)
+		}
+	})
+	useFetcher(t, &Fetcher{Header: http.Header{"Authorization": {"Bearer abc123"}}})
+
+	url := server.URL + "/cliguard.yaml"
+	c, err := Load(url)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if c.Use != "myapp" || len(c.Commands) != 1 || c.Commands[0].Use != "serve" {
+		t.Errorf("Load() = %+v", c)
+	}
+
+	// The download is reused by the other functions taking a contract path
+	if IsV2File(url) {
+		t.Error("IsV2File() = true, want false")
+	}
+	if _, err := CheckFields(url, false); err != nil {
+		t.Errorf("CheckFields() error = %v", err)
+	}
+	if _, err := LoadLineIndex(url, ""); err != nil {
+		t.Errorf("LoadLineIndex() error = %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("requests = %d, want 1", requests)
+	}
+}
+
+func TestLoad_URLNoCache(t *testing.T) {
+	var requests int32
+	server := serveContract(t, "use: myapp\n", &requests, nil)
+	useFetcher(t, &Fetcher{NoCache: true})
+
+	url := server.URL + "/cliguard.yaml"
+	for i := 0; i < 2; i++ {
+		if _, err := Load(url); err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+	}
+	if requests != 2 {
+		t.Errorf("requests = %d, want 2", requests)
+	}
+}
+
+func TestLoad_URLV2(t *testing.T) {
+	var requests int32
+	server := serveContract(t, "roots:\n  app:\n    use: app\n", &requests, nil)
+	useFetcher(t, &Fetcher{})
+
+	url := server.URL + "/cliguard.yaml"
+	if !IsV2File(url) {
+		t.Fatal("IsV2File() = false, want true")
+	}
+	c, err := LoadV2(url)
+	if err != nil {
+		t.Fatalf("LoadV2() error = %v", err)
+	}
+	if c.Roots["app"] == nil || c.Roots["app"].Use != "app" {
+		t.Errorf("LoadV2() = %+v", c)
+	}
+}
+
+func TestLoad_URLErrors(t *testing.T) {
+	var requests int32
+	server := serveContract(t, "short: no use\n", &requests, nil)
+	useFetcher(t, &Fetcher{})
+
+	_, err := Load(server.URL + "/missing.yaml")
+	if err == nil || err.Error() != "failed to download contract from "+server.URL+"/missing.yaml: 404 Not Found" {
+		t.Errorf("Load() error = %v, want 404", err)
+	}
+
+	// Invalid contracts are reported with their URL, not the downloaded file
+	url := server.URL + "/cliguard.yaml"
+	_, err = Load(url)
+	var invalid errors.InvalidContractError
+	if !stderrors.As(err, &invalid) || invalid.Path != url {
+		t.Errorf("Load() error = %v, want an invalid contract error naming %s", err, url)
+	}
+}
+
+func TestFetcher_Cleanup(t *testing.T) {
+	var requests int32
+	server := serveContract(t, "use: myapp\n", &requests, nil)
+	f := &Fetcher{}
+
+	path, err := f.Fetch(server.URL + "/cliguard.yaml")
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if !strings.HasSuffix(path, ".yaml") {
+		t.Errorf("Fetch() = %s, want a .yaml file", path)
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != "use: myapp\n" {
+		t.Errorf("downloaded file = %q, %v", data, err)
+	}
+
+	f.Cleanup()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("downloaded file still exists after Cleanup: %v", err)
+	}
+	if _, err := f.Fetch(server.URL + "/cliguard.yaml"); err != nil {
+		t.Fatalf("Fetch() after Cleanup error = %v", err)
+	}
+	f.Cleanup()
+	if requests != 2 {
+		t.Errorf("requests = %d, want 2", requests)
+	}
+}
+
+func TestParseHTTPHeader(t *testing.T) {
+	tests := []struct {
+		header    string
+		wantName  string
+		wantValue string
+		wantErr   bool
+	}{
+		{header: "Authorization: Bearer abc123", wantName: "Authorization", wantValue: "Bearer abc123"},
+		{header: "X-Token:abc", wantName: "X-Token", wantValue: "abc"},
+		{header: "X-Empty:", wantName: "X-Empty", wantValue: ""},
+		{header: "Bearer abc123", wantErr: true},
+		{header: ": abc", wantErr: true},
+		{header: "Bad Name: abc", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			name, value, err := ParseHTTPHeader(tt.header)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseHTTPHeader() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if name != tt.wantName || value != tt.wantValue {
+				t.Errorf("ParseHTTPHeader() = %q, %q, want %q, %q", name, value, tt.wantName, tt.wantValue)
+			}
+		})
+	}
+}
+
+func TestIsURL(t *testing.T) {
+	for path, want := range map[string]bool{
+		"https://example.com/cliguard.yaml": true,
+		"http://example.com/cliguard.yaml":  true,
+		"cliguard.yaml":                     false,
+		"/abs/http://x.yaml":                false,
+	} {
+		if got := IsURL(path); got != want {
+			t.Errorf("IsURL(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
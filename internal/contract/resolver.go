@@ -0,0 +1,220 @@
+package contract
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/errors"
+)
+
+// extendsResolver resolves the Extends fields of the commands of a contract
+// and of the contract files they reference
+type extendsResolver struct {
+	// mainPath is the absolute path of the contract being loaded
+	mainPath string
+
+	// contracts are the contract files loaded so far, by absolute path
+	contracts map[string]*Contract
+
+	// stack holds the commands whose extends are being resolved, to
+	// detect cycles
+	stack []string
+}
+
+// resolveContractExtends resolves the extends of the commands of the
+// contract loaded from absPath, leaving no Extends set. Its includes must
+// be resolved already, so that commands can extend included ones.
+func resolveContractExtends(contract *Contract, absPath string) error {
+	r := &extendsResolver{
+		mainPath:  absPath,
+		contracts: map[string]*Contract{absPath: contract},
+	}
+	return r.resolveCommands(absPath, "", contract.Commands)
+}
+
+// resolveCommands resolves the extends of commands, the subcommands of the
+// command at parentPath in file, and of their subcommands
+func (r *extendsResolver) resolveCommands(file, parentPath string, commands []Command) error {
+	for i := range commands {
+		if err := r.resolveCommand(file, JoinPath(parentPath, CommandName(commands[i].Use)), &commands[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveCommand resolves the extends of the command at path in file, then
+// those of its subcommands
+func (r *extendsResolver) resolveCommand(file, path string, cmd *Command) error {
+	if err := r.merge(file, path, cmd); err != nil {
+		return err
+	}
+	return r.resolveCommands(file, path, cmd.Commands)
+}
+
+// merge adds the flags and subcommands of the command cmd extends to its
+// own and clears its Extends
+func (r *extendsResolver) merge(file, path string, cmd *Command) error {
+	if cmd.Extends == "" {
+		return nil
+	}
+	key := r.key(file, path)
+	for _, resolving := range r.stack {
+		if resolving == key {
+			return errors.InvalidContractError{
+				Path:    file,
+				Message: fmt.Sprintf("extends cycle: %s", strings.Join(append(r.stack, key), " -> ")),
+			}
+		}
+	}
+	r.stack = append(r.stack, key)
+	defer func() { r.stack = r.stack[:len(r.stack)-1] }()
+
+	flags, commands, err := r.target(file, path, cmd.Extends)
+	if err != nil {
+		return err
+	}
+	for _, flag := range cloneFlags(flags) {
+		if !hasFlag(cmd.Flags, flag.Name) {
+			cmd.Flags = append(cmd.Flags, flag)
+		}
+	}
+	for _, sub := range cloneCommands(commands) {
+		if !hasCommand(cmd.Commands, CommandName(sub.Use)) {
+			cmd.Commands = append(cmd.Commands, sub)
+		}
+	}
+	cmd.Extends = ""
+	return nil
+}
+
+// target returns the flags and subcommands, with their extends resolved, of
+// the command that ref names. ref is the Extends of the command at path in
+// file.
+func (r *extendsResolver) target(file, path, ref string) ([]Flag, []Command, error) {
+	refFile, refPath := file, ref
+	if i := strings.Index(ref, "#"); i >= 0 || isContractFileName(ref) {
+		name := ref
+		refPath = ""
+		if i >= 0 {
+			name, refPath = ref[:i], ref[i+1:]
+		}
+		if name != "" {
+			refFile = name
+			if !filepath.IsAbs(refFile) {
+				refFile = filepath.Join(filepath.Dir(file), refFile)
+			}
+		}
+	}
+
+	c, err := r.load(refFile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	names := strings.Fields(refPath)
+	if len(names) == 0 {
+		if err := r.resolveCommands(refFile, "", c.Commands); err != nil {
+			return nil, nil, err
+		}
+		return c.Flags, c.Commands, nil
+	}
+
+	// Each command on the way is merged first, so that the path may go
+	// through commands it adds
+	commands := c.Commands
+	var found *Command
+	for depth, name := range names {
+		found = nil
+		for i := range commands {
+			if CommandName(commands[i].Use) == name {
+				found = &commands[i]
+				break
+			}
+		}
+		if found == nil {
+			return nil, nil, errors.InvalidContractError{
+				Path:    file,
+				Message: fmt.Sprintf("command '%s' extends '%s', which is not in the contract", path, ref),
+			}
+		}
+		if err := r.merge(refFile, strings.Join(names[:depth+1], " "), found); err != nil {
+			return nil, nil, err
+		}
+		commands = found.Commands
+	}
+	if err := r.resolveCommands(refFile, strings.Join(names, " "), found.Commands); err != nil {
+		return nil, nil, err
+	}
+	return found.Flags, found.Commands, nil
+}
+
+// load returns the contract file at path with its includes resolved,
+// reading it the first time it is named
+func (r *extendsResolver) load(path string) (*Contract, error) {
+	if c, ok := r.contracts[path]; ok {
+		return c, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.WrapContractNotFound(path, err)
+	}
+	var c Contract
+	if err := decodeContractFile(data, path, &c); err != nil {
+		return nil, err
+	}
+	if err := resolveContractIncludes(&c, path); err != nil {
+		return nil, err
+	}
+	r.contracts[path] = &c
+	return &c, nil
+}
+
+// key identifies the command at path in file in cycle errors: its path for
+// commands of the contract being loaded, and the file and path otherwise
+func (r *extendsResolver) key(file, path string) string {
+	if file == r.mainPath {
+		return path
+	}
+	return file + "#" + path
+}
+
+// isContractFileName reports whether ref names a contract file rather than
+// a command of the same file
+func isContractFileName(ref string) bool {
+	switch strings.ToLower(filepath.Ext(ref)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	}
+	return false
+}
+
+// hasFlag reports whether flags has a flag named name
+func hasFlag(flags []Flag, name string) bool {
+	for _, flag := range flags {
+		if flag.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// hasCommand reports whether commands has a command named name
+func hasCommand(commands []Command, name string) bool {
+	for _, cmd := range commands {
+		if CommandName(cmd.Use) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// joinCommandPath appends name to a command path
+func JoinPath(parentPath, name string) string {
+	if parentPath == "" {
+		return name
+	}
+	return parentPath + " " + name
+}
@@ -0,0 +1,272 @@
+package contract
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// flagNames returns the names of flags in order
+func flagNames(flags []Flag) []string {
+	names := make([]string, len(flags))
+	for i, flag := range flags {
+		names[i] = flag.Name
+	}
+	return names
+}
+
+func TestLoad_Extends(t *testing.T) {
+	dir := t.TempDir()
+	writeContractFiles(t, dir, map[string]string{
+		"cliguard.yaml": `use: kubectl
+short: Kubernetes CLI
+commands:
+  - use: get
+    short: Display resources
+    flags:
+      - name: namespace
+        usage: Namespace to use
+        type: string
+      - name: output
+        usage: Output format
+        type: string
+    commands:
+      - use: pods
+        short: List pods
+  - use: describe
+    short: Show details of resources
+    extends: get
+    flags:
+      - name: output
+        usage: Output format for describe
+        type: string
+      - name: show-events
+        usage: Show events
+        type: bool
+    commands:
+      - use: pods
+        short: Describe pods
+  - use: logs
+    short: Print the logs of a pod
+    extends: ./common.yaml#client
+  - use: exec
+    short: Run a command in a container
+    extends: ./common.yaml
+`,
+		"common.yaml": `use: common
+short: Shared flags
+flags:
+  - name: context
+    usage: Kubeconfig context to use
+    type: string
+commands:
+  - use: client
+    short: Client flags
+    extends: base
+    flags:
+      - name: timeout
+        usage: Request timeout
+        type: duration
+  - use: base
+    short: Base flags
+    flags:
+      - name: server
+        usage: API server address
+        type: string
+`,
+	})
+
+	c, err := Load(filepath.Join(dir, "cliguard.yaml"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	describe := c.Commands[1]
+	if describe.Extends != "" {
+		t.Errorf("Extends = %q, want it resolved", describe.Extends)
+	}
+	if got := strings.Join(flagNames(describe.Flags), ","); got != "output,show-events,namespace" {
+		t.Errorf("describe flags = %s, want its own then the inherited namespace", got)
+	}
+	if describe.Flags[0].Usage != "Output format for describe" {
+		t.Errorf("describe --output usage = %q, want the command's own flag to override", describe.Flags[0].Usage)
+	}
+	if len(describe.Commands) != 1 || describe.Commands[0].Short != "Describe pods" {
+		t.Errorf("describe commands = %+v, want only its own pods", describe.Commands)
+	}
+
+	logs := c.Commands[2]
+	if got := strings.Join(flagNames(logs.Flags), ","); got != "timeout,server" {
+		t.Errorf("logs flags = %s, want client's flags, including those it extends", got)
+	}
+
+	exec := c.Commands[3]
+	if got := strings.Join(flagNames(exec.Flags), ","); got != "context" {
+		t.Errorf("exec flags = %s, want the common root's flags", got)
+	}
+	if len(exec.Commands) != 2 || exec.Commands[0].Use != "client" || exec.Commands[1].Use != "base" {
+		t.Errorf("exec commands = %+v, want the common root's commands", exec.Commands)
+	}
+
+	// Inherited flags are copies, not shared with the extended command
+	describe.Flags[2].Usage = "changed"
+	if c.Commands[0].Flags[0].Usage != "Namespace to use" {
+		t.Errorf("get --namespace usage = %q, want it unchanged", c.Commands[0].Flags[0].Usage)
+	}
+}
+
+func TestLoad_ExtendsNestedPath(t *testing.T) {
+	dir := t.TempDir()
+	writeContractFiles(t, dir, map[string]string{
+		"cliguard.yaml": `use: app
+short: App
+commands:
+  - use: restore
+    short: Restore a backup
+    extends: db backup
+  - use: db
+    short: Manage the database
+    extends: ./db.yaml
+`,
+		"db.yaml": `use: db
+short: Database commands
+commands:
+  - use: backup
+    short: Back up the database
+    flags:
+      - name: output
+        usage: Output file
+        type: string
+`,
+	})
+
+	// restore extends a command that db only gets from its own extends
+	c, err := Load(filepath.Join(dir, "cliguard.yaml"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := strings.Join(flagNames(c.Commands[0].Flags), ","); got != "output" {
+		t.Errorf("restore flags = %s, want db backup's --output", got)
+	}
+}
+
+func TestLoad_ExtendsErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		wantErr string
+	}{
+		{
+			name: "cycle",
+			files: map[string]string{"cliguard.yaml": `use: app
+short: App
+commands:
+  - use: a
+    short: A
+    extends: b
+  - use: b
+    short: B
+    extends: c
+  - use: c
+    short: C
+    extends: a
+`},
+			wantErr: "extends cycle: a -> b -> c -> a",
+		},
+		{
+			name: "ancestor",
+			files: map[string]string{"cliguard.yaml": `use: app
+short: App
+commands:
+  - use: db
+    short: Database
+    commands:
+      - use: migrate
+        short: Migrate
+        extends: db
+`},
+			wantErr: "extends cycle: db migrate",
+		},
+		{
+			name: "cycle across files",
+			files: map[string]string{
+				"cliguard.yaml": `use: app
+short: App
+commands:
+  - use: a
+    short: A
+    extends: ./other.yaml#b
+`,
+				"other.yaml": `commands:
+  - use: b
+    short: B
+    extends: ./cliguard.yaml#a
+`,
+			},
+			wantErr: "extends cycle: a -> ",
+		},
+		{
+			name: "missing command",
+			files: map[string]string{"cliguard.yaml": `use: app
+short: App
+commands:
+  - use: a
+    short: A
+    extends: db migrate
+`},
+			wantErr: "command 'a' extends 'db migrate', which is not in the contract",
+		},
+		{
+			name: "missing file",
+			files: map[string]string{"cliguard.yaml": `use: app
+short: App
+commands:
+  - use: a
+    short: A
+    extends: ./missing.yaml
+`},
+			wantErr: "missing.yaml",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeContractFiles(t, dir, tt.files)
+			_, err := Load(filepath.Join(dir, "cliguard.yaml"))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Load() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadV2_Extends(t *testing.T) {
+	dir := t.TempDir()
+	writeContractFiles(t, dir, map[string]string{
+		"cliguard.yaml": `version: 2.0.0
+roots:
+  app:
+    use: app
+    short: App
+    commands:
+      - use: get
+        short: Get
+        flags:
+          - name: namespace
+            usage: Namespace
+            type: string
+      - use: delete
+        short: Delete
+        extends: get
+`,
+	})
+
+	c, err := LoadV2(filepath.Join(dir, "cliguard.yaml"))
+	if err != nil {
+		t.Fatalf("LoadV2() error = %v", err)
+	}
+	if got := strings.Join(flagNames(c.Roots["app"].Commands[1].Flags), ","); got != "namespace" {
+		t.Errorf("delete flags = %s, want get's --namespace", got)
+	}
+}
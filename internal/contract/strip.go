@@ -0,0 +1,136 @@
+package contract
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// StripAction selects what a StripRule removes from a contract
+type StripAction string
+
+const (
+	// StripFlag removes flags whose usage matches the rule's pattern
+	StripFlag StripAction = "flag"
+
+	// StripCommand removes commands, including their subcommands, whose
+	// short description matches the rule's pattern
+	StripCommand StripAction = "command"
+)
+
+// StripRule removes the flags or commands of a contract that match Pattern,
+// or the commands named CommandUse
+type StripRule struct {
+	Pattern *regexp.Regexp
+	Action  StripAction
+
+	// CommandUse, if set, removes the commands at any level whose name, the
+	// first word of their use line, is CommandUse, with their subcommands.
+	// Pattern and Action are not used.
+	// Example: "help" for Cobra's "help [command]"
+	CommandUse string
+}
+
+// HelpCommandRule returns the rule removing the help command Cobra adds to
+// every CLI with subcommands
+func HelpCommandRule() StripRule {
+	return StripRule{CommandUse: "help"}
+}
+
+// CompletionCommandRule returns the rule removing the completion command
+// Cobra adds to every CLI with subcommands, and its bash, zsh, fish and
+// powershell subcommands
+func CompletionCommandRule() StripRule {
+	return StripRule{CommandUse: "completion"}
+}
+
+// DefaultStripRules returns the rules that remove what Cobra adds to every CLI:
+//
+//	flag     ^help for \S+$                              the --help flag
+//	flag     ^version for \S+$                           the --version flag
+//	command  ^Generate the autocompletion script for     the completion command
+func DefaultStripRules() []StripRule {
+	return []StripRule{
+		{Pattern: regexp.MustCompile(`^help for \S+$`), Action: StripFlag},
+		{Pattern: regexp.MustCompile(`^version for \S+$`), Action: StripFlag},
+		{Pattern: regexp.MustCompile(`^Generate the autocompletion script for`), Action: StripCommand},
+	}
+}
+
+// ParseStripRule parses a rule in the form "action:pattern", e.g.
+// "flag:^Enable debug output$" or "command:^Internal"
+func ParseStripRule(rule string) (StripRule, error) {
+	action, pattern, ok := strings.Cut(rule, ":")
+	if !ok {
+		return StripRule{}, fmt.Errorf("invalid strip rule '%s': expected 'action:pattern'", rule)
+	}
+
+	switch StripAction(action) {
+	case StripFlag, StripCommand:
+	default:
+		return StripRule{}, fmt.Errorf("invalid strip rule '%s': action must be '%s' or '%s'", rule, StripFlag, StripCommand)
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return StripRule{}, fmt.Errorf("invalid strip rule '%s': %w", rule, err)
+	}
+	return StripRule{Pattern: re, Action: StripAction(action)}, nil
+}
+
+// StripDefaults returns a copy of the contract without the flags and commands
+// matched by rules. The original contract is not modified.
+func StripDefaults(c *Contract, rules []StripRule) *Contract {
+	stripped := *c
+	stripped.Flags = stripFlags(c.Flags, rules)
+	stripped.Commands = stripCommands(c.Commands, rules)
+	return &stripped
+}
+
+// stripCommands returns the commands not matched by a StripCommand rule,
+// with their flags and subcommands stripped
+func stripCommands(commands []Command, rules []StripRule) []Command {
+	var result []Command
+	for _, cmd := range commands {
+		if matchesRule(cmd.Short, StripCommand, rules) || matchesCommandUse(cmd.Use, rules) {
+			continue
+		}
+		cmd.Flags = stripFlags(cmd.Flags, rules)
+		cmd.Commands = stripCommands(cmd.Commands, rules)
+		result = append(result, cmd)
+	}
+	return result
+}
+
+// stripFlags returns the flags not matched by a StripFlag rule
+func stripFlags(flags []Flag, rules []StripRule) []Flag {
+	var result []Flag
+	for _, flag := range flags {
+		if !matchesRule(flag.Usage, StripFlag, rules) {
+			result = append(result, flag)
+		}
+	}
+	return result
+}
+
+// matchesRule reports whether any rule with the given action matches text
+func matchesRule(text string, action StripAction, rules []StripRule) bool {
+	for _, rule := range rules {
+		if rule.CommandUse == "" && rule.Action == action && rule.Pattern.MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesCommandUse reports whether any CommandUse rule names the command
+// with the use line use
+func matchesCommandUse(use string, rules []StripRule) bool {
+	name, _, _ := strings.Cut(strings.TrimSpace(use), " ")
+	for _, rule := range rules {
+		if rule.CommandUse != "" && rule.CommandUse == name {
+			return true
+		}
+	}
+	return false
+}
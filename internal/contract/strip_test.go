@@ -0,0 +1,158 @@
+package contract
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestStripDefaults(t *testing.T) {
+	original := &Contract{
+		Use: "myapp",
+		Flags: []Flag{
+			{Name: "help", Usage: "help for myapp", Type: "bool"},
+			{Name: "version", Usage: "version for myapp", Type: "bool"},
+			{Name: "verbose", Usage: "Verbose output", Type: "bool"},
+		},
+		Commands: []Command{
+			{
+				Use:   "completion",
+				Short: "Generate the autocompletion script for the specified shell",
+				Commands: []Command{
+					{Use: "bash", Short: "Generate the autocompletion script for bash"},
+				},
+			},
+			{
+				Use:   "serve",
+				Short: "Start the server",
+				Flags: []Flag{
+					{Name: "help", Usage: "help for serve", Type: "bool"},
+					{Name: "port", Usage: "Port number", Type: "int"},
+				},
+				Commands: []Command{
+					{Use: "status", Short: "Show status", Flags: []Flag{{Name: "help", Usage: "help for status", Type: "bool"}}},
+				},
+			},
+		},
+	}
+
+	got := StripDefaults(original, DefaultStripRules())
+
+	want := &Contract{
+		Use:   "myapp",
+		Flags: []Flag{{Name: "verbose", Usage: "Verbose output", Type: "bool"}},
+		Commands: []Command{
+			{
+				Use:      "serve",
+				Short:    "Start the server",
+				Flags:    []Flag{{Name: "port", Usage: "Port number", Type: "int"}},
+				Commands: []Command{{Use: "status", Short: "Show status"}},
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("StripDefaults() = %+v, want %+v", got, want)
+	}
+
+	// The input is left untouched
+	if len(original.Flags) != 3 || len(original.Commands) != 2 || len(original.Commands[1].Flags) != 2 {
+		t.Error("StripDefaults() modified the original contract")
+	}
+}
+
+func TestStripDefaults_CustomRules(t *testing.T) {
+	rule, err := ParseStripRule("command:^Internal")
+	if err != nil {
+		t.Fatalf("ParseStripRule() error = %v", err)
+	}
+
+	c := &Contract{
+		Use: "myapp",
+		Flags: []Flag{
+			{Name: "help", Usage: "help for myapp", Type: "bool"},
+		},
+		Commands: []Command{
+			{Use: "debug", Short: "Internal debugging tools"},
+			{Use: "serve", Short: "Start the server"},
+		},
+	}
+
+	// Only the given rules apply
+	got := StripDefaults(c, []StripRule{rule})
+	if len(got.Flags) != 1 || len(got.Commands) != 1 || got.Commands[0].Use != "serve" {
+		t.Errorf("StripDefaults() = %+v", got)
+	}
+}
+
+func TestStripDefaults_CommandUse(t *testing.T) {
+	c := &Contract{
+		Use: "myapp",
+		Commands: []Command{
+			{Use: "help [command]", Short: "Help about any command"},
+			{Use: "completion", Short: "Generate the autocompletion script for the specified shell", Commands: []Command{
+				{Use: "bash", Short: "Generate the autocompletion script for bash"},
+			}},
+			{Use: "db", Short: "Manage the database", Commands: []Command{
+				{Use: "help [command]", Short: "Help about any command"},
+				{Use: "migrate", Short: "Run migrations"},
+			}},
+			{Use: "helper", Short: "Not Cobra's help command"},
+		},
+	}
+
+	got := StripDefaults(c, []StripRule{HelpCommandRule(), CompletionCommandRule()})
+	var uses []string
+	for _, cmd := range got.Commands {
+		uses = append(uses, cmd.Use)
+	}
+	if strings.Join(uses, ",") != "db,helper" {
+		t.Errorf("commands = %v, want db and helper", uses)
+	}
+	if db := got.Commands[0]; len(db.Commands) != 1 || db.Commands[0].Use != "migrate" {
+		t.Errorf("db commands = %+v, want only migrate", db.Commands)
+	}
+
+	// Without them, only the completion command matches the defaults
+	got = StripDefaults(c, DefaultStripRules())
+	if len(got.Commands) != 3 || got.Commands[0].Use != "help [command]" {
+		t.Errorf("StripDefaults() = %+v, want help kept", got.Commands)
+	}
+}
+
+func TestParseStripRule(t *testing.T) {
+	tests := []struct {
+		rule        string
+		wantAction  StripAction
+		errContains string
+	}{
+		{rule: "flag:^Enable debug output$", wantAction: StripFlag},
+		{rule: "command:^Internal: tools", wantAction: StripCommand},
+		{rule: "no-separator", errContains: "expected 'action:pattern'"},
+		{rule: "arg:^x", errContains: "action must be"},
+		{rule: "flag:(unclosed", errContains: "error parsing regexp"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			got, err := ParseStripRule(tt.rule)
+			if tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("ParseStripRule() error = %v, want error containing %q", err, tt.errContains)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseStripRule() error = %v", err)
+			}
+			if got.Action != tt.wantAction {
+				t.Errorf("Action = %q, want %q", got.Action, tt.wantAction)
+			}
+		})
+	}
+
+	// Only the first ':' separates the action, so patterns may contain colons
+	rule, _ := ParseStripRule("command:^Internal: tools")
+	if rule.Pattern.String() != "^Internal: tools" {
+		t.Errorf("Pattern = %q", rule.Pattern.String())
+	}
+}
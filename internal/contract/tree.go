@@ -0,0 +1,103 @@
+package contract
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteTree writes the contract as an indented tree of commands and flags.
+// Flags are listed before subcommands at each level.
+//
+// Example output:
+//
+//	myapp: My application
+//	├── --config, -c <string> [persistent] Config file path
+//	└── serve (aliases: s): Start the server
+//	    └── --port <int> Port to listen on
+func WriteTree(w io.Writer, c *Contract) error {
+	root := treeNode{
+		label:    commandLabel(c.Use, c.Short, c.Aliases, false),
+		children: treeChildren(c.Flags, c.Commands),
+	}
+
+	if _, err := fmt.Fprintln(w, root.label); err != nil {
+		return err
+	}
+	return writeTreeChildren(w, root.children, "")
+}
+
+// treeNode is a line in the rendered tree and the lines nested below it
+type treeNode struct {
+	label    string
+	children []treeNode
+}
+
+// treeChildren builds the nodes for a command's flags and subcommands
+func treeChildren(flags []Flag, commands []Command) []treeNode {
+	var nodes []treeNode
+	for _, f := range flags {
+		nodes = append(nodes, treeNode{label: flagLabel(f)})
+	}
+	for _, cmd := range commands {
+		nodes = append(nodes, treeNode{
+			label:    commandLabel(cmd.Use, cmd.Short, cmd.Aliases, cmd.Hidden),
+			children: treeChildren(cmd.Flags, cmd.Commands),
+		})
+	}
+	return nodes
+}
+
+// writeTreeChildren writes nodes with box-drawing connectors under the given prefix
+func writeTreeChildren(w io.Writer, nodes []treeNode, prefix string) error {
+	for i, node := range nodes {
+		connector, childPrefix := "├── ", prefix+"│   "
+		if i == len(nodes)-1 {
+			connector, childPrefix = "└── ", prefix+"    "
+		}
+
+		if _, err := fmt.Fprintf(w, "%s%s%s\n", prefix, connector, node.label); err != nil {
+			return err
+		}
+		if err := writeTreeChildren(w, node.children, childPrefix); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// commandLabel formats a command line such as "serve (aliases: s) [hidden]: Start the server"
+func commandLabel(use, short string, aliases []string, hidden bool) string {
+	var b strings.Builder
+	b.WriteString(use)
+	if len(aliases) > 0 {
+		fmt.Fprintf(&b, " (aliases: %s)", strings.Join(aliases, ", "))
+	}
+	if hidden {
+		b.WriteString(" [hidden]")
+	}
+	if short != "" {
+		fmt.Fprintf(&b, ": %s", short)
+	}
+	return b.String()
+}
+
+// flagLabel formats a flag line such as "--config, -c <string> [persistent] Config file (env: APP_CONFIG)"
+func flagLabel(f Flag) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "--%s", f.Name)
+	if f.Shorthand != "" {
+		fmt.Fprintf(&b, ", -%s", f.Shorthand)
+	}
+	fmt.Fprintf(&b, " <%s>", f.Type)
+	if f.Persistent {
+		b.WriteString(" [persistent]")
+	}
+	if f.Usage != "" {
+		fmt.Fprintf(&b, " %s", f.Usage)
+	}
+	if f.Env != "" {
+		fmt.Fprintf(&b, " (env: %s)", f.Env)
+	}
+	return b.String()
+}
@@ -0,0 +1,72 @@
+package contract
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteTree(t *testing.T) {
+	c := &Contract{
+		Use:   "myapp",
+		Short: "My application",
+		Flags: []Flag{
+			{Name: "config", Shorthand: "c", Type: "string", Usage: "Config file path", Persistent: true},
+		},
+		Commands: []Command{
+			{
+				Use:     "serve",
+				Short:   "Start the server",
+				Aliases: []string{"s", "start"},
+				Flags: []Flag{
+					{Name: "port", Type: "int", Usage: "Port to listen on"},
+				},
+				Commands: []Command{
+					{Use: "http", Short: "Start HTTP server"},
+				},
+			},
+			{Use: "debug", Short: "Debugging tools", Hidden: true},
+		},
+	}
+
+	want := `myapp: My application
+├── --config, -c <string> [persistent] Config file path
+├── serve (aliases: s, start): Start the server
+│   ├── --port <int> Port to listen on
+│   └── http: Start HTTP server
+└── debug [hidden]: Debugging tools
+`
+
+	var buf bytes.Buffer
+	if err := WriteTree(&buf, c); err != nil {
+		t.Fatalf("WriteTree() error = %v", err)
+	}
+	if buf.String() != want {
+		t.Errorf("WriteTree() =\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestWriteTree_RootOnly(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteTree(&buf, &Contract{Use: "tiny"}); err != nil {
+		t.Fatalf("WriteTree() error = %v", err)
+	}
+	if buf.String() != "tiny\n" {
+		t.Errorf("WriteTree() = %q, want %q", buf.String(), "tiny\n")
+	}
+}
+
+func TestWriteTree_Env(t *testing.T) {
+	c := &Contract{
+		Use:   "myapp",
+		Flags: []Flag{{Name: "token", Type: "string", Usage: "API token", Env: "API_TOKEN"}},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteTree(&buf, c); err != nil {
+		t.Fatalf("WriteTree() error = %v", err)
+	}
+	want := "myapp\n└── --token <string> API token (env: API_TOKEN)\n"
+	if buf.String() != want {
+		t.Errorf("WriteTree() = %q, want %q", buf.String(), want)
+	}
+}
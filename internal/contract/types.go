@@ -22,34 +22,55 @@ type Contract struct {
 	// Use is the command name as it appears when invoked (required).
 	// For the root command, this is the application name.
 	// Example: "git" for the git CLI
-	Use string `yaml:"use"`
+	Use string `json:"use" yaml:"use"`
 
 	// Short is a brief one-line description shown in help listings (required).
 	// Should be concise and start with a capital letter.
 	// Example: "Fast, scalable, distributed revision control system"
-	Short string `yaml:"short"`
+	Short string `json:"short" yaml:"short"`
 
 	// Long is a detailed description shown in the help command (optional).
 	// Can be multiple paragraphs and include usage examples.
-	Long string `yaml:"long,omitempty"`
+	Long string `json:"long,omitempty" yaml:"long,omitempty"`
 
 	// Flags defines the command-line flags available on this command (optional).
 	// These are flags specific to this command, not inherited by subcommands
 	// unless marked as Persistent.
-	Flags []Flag `yaml:"flags,omitempty"`
+	Flags []Flag `json:"flags,omitempty" yaml:"flags,omitempty"`
 	
 	// Aliases are alternative names for this command (optional).
 	// Users can invoke the command using any of these aliases.
 	// Example: ["s", "start"] for a "serve" command
-	Aliases []string `yaml:"aliases,omitempty"`
+	Aliases []string `json:"aliases,omitempty" yaml:"aliases,omitempty"`
 	
 	// Example provides usage examples for this command (optional).
 	// Can be multi-line text showing common usage patterns.
-	Example string `yaml:"example,omitempty"`
+	Example string `json:"example,omitempty" yaml:"example,omitempty"`
+
+	// Version is the semantic version of the CLI that the contract
+	// describes (optional), e.g. "1.4.2". It is only compared with the
+	// version the CLI prints when validating with --expect-version.
+	Version string `json:"version,omitempty" yaml:"version,omitempty"`
+
+	// MutuallyExclusive lists groups of flags that can't be used together
+	// (optional), as marked with cmd.MarkFlagsMutuallyExclusive. The order of
+	// the groups and of the flags in each group doesn't matter.
+	// Example: [[json, yaml]]
+	MutuallyExclusive [][]string `json:"mutually_exclusive,omitempty" yaml:"mutually_exclusive,omitempty"`
+
+	// RequiredTogether lists groups of flags that must be used together
+	// (optional), as marked with cmd.MarkFlagsRequiredTogether.
+	// Example: [[username, password]]
+	RequiredTogether [][]string `json:"required_together,omitempty" yaml:"required_together,omitempty"`
 	
 	// Commands lists all subcommands available under this command (optional).
 	// Each subcommand can have its own flags and nested subcommands.
-	Commands []Command `yaml:"commands,omitempty"`
+	Commands []Command `json:"commands,omitempty" yaml:"commands,omitempty"`
+
+	// Include references fragment files whose commands are added to
+	// Commands when the contract is loaded (optional).
+	// Example: [{$ref: ./db-contract.yaml}]
+	Include []Include `json:"include,omitempty" yaml:"include,omitempty"`
 }
 
 // Command represents a subcommand in the contract.
@@ -74,34 +95,83 @@ type Command struct {
 	// Use is the command name and usage pattern (required).
 	// Can include arguments: "serve <port>" or just the name: "serve"
 	// Example: "clone [flags] <repository> [<directory>]"
-	Use string `yaml:"use"`
+	Use string `json:"use" yaml:"use"`
 
 	// Short is a brief one-line description for command listings (required).
 	// Should be concise and start with a capital letter.
 	// Example: "Clone a repository into a new directory"
-	Short string `yaml:"short"`
+	Short string `json:"short" yaml:"short"`
 
 	// Long is a detailed description shown in the help command (optional).
 	// Can include multiple paragraphs, usage examples, and notes.
-	Long string `yaml:"long,omitempty"`
+	Long string `json:"long,omitempty" yaml:"long,omitempty"`
 
 	// Flags defines command-specific flags (optional).
 	// These flags are only available when this command is invoked.
-	Flags []Flag `yaml:"flags,omitempty"`
+	Flags []Flag `json:"flags,omitempty" yaml:"flags,omitempty"`
 	
 	// Aliases are alternative names for this command (optional).
 	// Users can invoke the command using any of these aliases.
 	// Example: ["s", "start"] for a "serve" command
-	Aliases []string `yaml:"aliases,omitempty"`
+	Aliases []string `json:"aliases,omitempty" yaml:"aliases,omitempty"`
 	
 	// Example provides usage examples for this command (optional).
 	// Can be multi-line text showing common usage patterns.
-	Example string `yaml:"example,omitempty"`
+	Example string `json:"example,omitempty" yaml:"example,omitempty"`
+
+	// Hidden indicates the command is hidden from help output (optional).
+	// Hidden commands remain executable but are not listed in usage.
+	// Default: false (command is visible)
+	Hidden bool `json:"hidden,omitempty" yaml:"hidden,omitempty"`
+
+	// Runnable indicates whether the command sets a run function (optional).
+	// Commands that only group subcommands, like "db" in "myapp db migrate",
+	// are not runnable. Generated contracts only include it with
+	// --runnable-only.
+	// Default: not checked
+	Runnable *bool `json:"runnable,omitempty" yaml:"runnable,omitempty"`
+
+	// GroupID is the help group the command is listed under (optional).
+	// Corresponds to cobra.Command.GroupID.
+	// Example: "management" for commands shown under "Management Commands:"
+	GroupID string `json:"group_id,omitempty" yaml:"group_id,omitempty"`
+
+	// MutuallyExclusive lists groups of flags that can't be used together
+	// (optional), as marked with cmd.MarkFlagsMutuallyExclusive. The order of
+	// the groups and of the flags in each group doesn't matter.
+	// Example: [[json, yaml]]
+	MutuallyExclusive [][]string `json:"mutually_exclusive,omitempty" yaml:"mutually_exclusive,omitempty"`
+
+	// RequiredTogether lists groups of flags that must be used together
+	// (optional), as marked with cmd.MarkFlagsRequiredTogether.
+	// Example: [[username, password]]
+	RequiredTogether [][]string `json:"required_together,omitempty" yaml:"required_together,omitempty"`
+
+	// SortOrder is the command's position among its siblings in the CLI's
+	// command listing (optional). Commands with a sort order must be listed
+	// in ascending order; commands without one may appear anywhere. Cobra
+	// has no such setting, so generated contracts never include it.
+	// Only checked by validate --strict-sort-order.
+	// Example: 1 for a command that must be listed first
+	// Default: 0 (position not checked)
+	SortOrder int `json:"sort_order,omitempty" yaml:"sort_order,omitempty"`
 	
 	// Commands lists nested subcommands under this command (optional).
 	// Allows building complex command hierarchies.
 	// Example: "git remote add" where "add" is nested under "remote"
-	Commands []Command `yaml:"commands,omitempty"`
+	Commands []Command `json:"commands,omitempty" yaml:"commands,omitempty"`
+
+	// Include references fragment files whose commands are added to
+	// Commands when the contract is loaded (optional).
+	Include []Include `json:"include,omitempty" yaml:"include,omitempty"`
+
+	// Extends names a command whose flags and subcommands are added to this
+	// command's when the contract is loaded (optional). It is a command path
+	// in the same file, a contract file for its root's flags and commands,
+	// or a file and a command path separated by '#'. Flags and subcommands
+	// the command defines itself override those of the same name.
+	// Example: "get" or "./common.yaml#resource"
+	Extends string `json:"extends,omitempty" yaml:"extends,omitempty"`
 }
 
 // Flag represents a command flag in the contract.
@@ -120,31 +190,86 @@ type Command struct {
 //   - Unsigned: uint, uint8, uint16, uint32, uint64
 //   - Float: float32, float64
 //   - Duration: duration (time.Duration)
-//   - Slices: stringSlice, intSlice, boolSlice
+//   - Slices: stringSlice, stringArray, intSlice, boolSlice
 //   - Special: count (incremental counter)
 type Flag struct {
 	// Name is the long form of the flag (required).
 	// Used with double dash: --name
 	// Example: "verbose" for --verbose
-	Name string `yaml:"name"`
+	Name string `json:"name" yaml:"name"`
 
-	// Shorthand is the single-letter abbreviation (optional).
+	// Shorthand is the single-letter abbreviation (optional). Must be an
+	// ASCII letter or digit.
 	// Used with single dash: -s
 	// Example: "v" for -v
-	Shorthand string `yaml:"shorthand,omitempty"`
+	Shorthand string `json:"shorthand,omitempty" yaml:"shorthand,omitempty"`
 
 	// Usage is the help text shown for this flag (required).
 	// Should be concise and describe what the flag does.
 	// Example: "Enable verbose output"
-	Usage string `yaml:"usage"`
+	Usage string `json:"usage" yaml:"usage"`
 
 	// Type specifies the flag's data type (required).
 	// Must be a valid pflag type name.
 	// Common types: string, bool, int, float64, duration, stringSlice
-	Type string `yaml:"type"`
+	Type string `json:"type" yaml:"type"`
 
 	// Persistent indicates if the flag is inherited by subcommands (optional).
 	// When true, this flag is available to all nested subcommands.
 	// Default: false (flag is local to the command)
-	Persistent bool `yaml:"persistent,omitempty"`
+	Persistent bool `json:"persistent,omitempty" yaml:"persistent,omitempty"`
+
+	// Completion is the shell completion the flag must provide (optional).
+	// One of: none, file (cobra.MarkFlagFilename), dir (cobra.MarkFlagDirname)
+	// or custom (cmd.RegisterFlagCompletionFunc).
+	// Default: not checked
+	Completion string `json:"completion,omitempty" yaml:"completion,omitempty"`
+
+	// Default is the flag's default value as printed by pflag (optional).
+	// Must be parseable as the flag's type. Slice and map defaults use
+	// pflag's bracket form, e.g. "[a,b]".
+	// Example: "8080" for an int port flag
+	// Default: not checked (an empty string cannot be asserted)
+	Default string `json:"default,omitempty" yaml:"default,omitempty"`
+
+	// Required indicates the flag must be marked required with
+	// cmd.MarkFlagRequired (optional).
+	// Default: false (the flag must not be marked required)
+	Required bool `json:"required,omitempty" yaml:"required,omitempty"`
+
+	// Enum lists the values the flag's completion function must offer
+	// (optional). Order does not matter.
+	// Example: ["us-east-1", "eu-west-1"] for a --region flag
+	// Default: not checked
+	Enum []string `json:"enum,omitempty" yaml:"enum,omitempty"`
+
+	// Deprecated is the message the flag was deprecated with using
+	// cmd.Flags().MarkDeprecated (optional). A flag without one must not be
+	// deprecated.
+	// Example: "use --output instead"
+	// Default: "" (the flag is not deprecated)
+	Deprecated string `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
+
+	// Hidden indicates the flag must be hidden with cmd.Flags().MarkHidden
+	// (optional). Hidden flags the contract doesn't list are not reported;
+	// listed ones hidden when Hidden is not set, or visible when it is, are
+	// reported as warnings. Deprecated flags are hidden by pflag too, but
+	// are not Hidden here.
+	// Default: false
+	Hidden bool `json:"hidden,omitempty" yaml:"hidden,omitempty"`
+
+	// Env is the environment variable the flag falls back to when it is not
+	// given (optional), bound with viper.BindEnv or read with os.Getenv as
+	// the flag's default. Validation warns when the CLI's source doesn't
+	// bind it.
+	// Example: "API_TOKEN" for a --token flag
+	Env string `json:"env,omitempty" yaml:"env,omitempty"`
 }
+
+// Flag completion kinds for Flag.Completion
+const (
+	CompletionNone   = "none"
+	CompletionFile   = "file"
+	CompletionDir    = "dir"
+	CompletionCustom = "custom"
+)
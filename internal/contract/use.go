@@ -0,0 +1,39 @@
+package contract
+
+import "strings"
+
+// UseNamesOnly returns a copy of the contract in which the Use of the root
+// and of every command is only the command name, without the argument
+// pattern: "clone [flags] <repo>" becomes "clone". Validate such a contract
+// with validator.Options.UseNameOnly. The original contract is not modified.
+func UseNamesOnly(c *Contract) *Contract {
+	named := *c
+	named.Use = CommandName(c.Use)
+	named.Commands = commandNamesOnly(c.Commands)
+	return &named
+}
+
+// commandNamesOnly returns copies of the commands, and recursively their
+// subcommands, with Use reduced to the command name
+func commandNamesOnly(commands []Command) []Command {
+	if commands == nil {
+		return nil
+	}
+	result := make([]Command, len(commands))
+	for i, cmd := range commands {
+		cmd.Use = CommandName(cmd.Use)
+		cmd.Commands = commandNamesOnly(cmd.Commands)
+		result[i] = cmd
+	}
+	return result
+}
+
+// CommandName returns the command name from a cobra.Command.Use string:
+// its first word, as cobra.Command.Name does
+func CommandName(use string) string {
+	fields := strings.Fields(use)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
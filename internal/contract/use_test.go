@@ -0,0 +1,67 @@
+package contract
+
+import "testing"
+
+func TestExtractCommandName(t *testing.T) {
+	tests := []struct {
+		use  string
+		want string
+	}{
+		{use: "serve", want: "serve"},
+		{use: "clone [flags] <repo>", want: "clone"},
+		{use: "get <resource> [name]", want: "get"},
+		{use: "  add   <file>...", want: "add"},
+		{use: "run\t[args]", want: "run"},
+		// Cobra has no syntax for aliases in Use; like cobra.Command.Name,
+		// the whole first word is the name
+		{use: "remove|rm <name>", want: "remove|rm"},
+		{use: "", want: ""},
+		{use: "   ", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.use, func(t *testing.T) {
+			if got := CommandName(tt.use); got != tt.want {
+				t.Errorf("CommandName(%q) = %q, want %q", tt.use, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUseNamesOnly(t *testing.T) {
+	original := &Contract{
+		Use: "git [flags]",
+		Commands: []Command{
+			{
+				Use:     "remote <command>",
+				Aliases: []string{"r"},
+				Commands: []Command{
+					{Use: "add <name> <url>"},
+				},
+			},
+			{Use: "status"},
+		},
+	}
+
+	named := UseNamesOnly(original)
+
+	if named.Use != "git" {
+		t.Errorf("root Use = %q, want %q", named.Use, "git")
+	}
+	if got := named.Commands[0].Use; got != "remote" {
+		t.Errorf("command Use = %q, want %q", got, "remote")
+	}
+	if got := named.Commands[0].Commands[0].Use; got != "add" {
+		t.Errorf("subcommand Use = %q, want %q", got, "add")
+	}
+	if got := named.Commands[1].Use; got != "status" {
+		t.Errorf("command Use = %q, want %q", got, "status")
+	}
+	if got := named.Commands[0].Aliases; len(got) != 1 || got[0] != "r" {
+		t.Errorf("Aliases = %v, want them kept", got)
+	}
+
+	if original.Use != "git [flags]" || original.Commands[0].Commands[0].Use != "add <name> <url>" {
+		t.Errorf("UseNamesOnly modified the original contract: %+v", original)
+	}
+}
@@ -0,0 +1,203 @@
+package contract
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// ContractV2Version is the version written to generated v2 contracts
+const ContractV2Version = "2.0.0"
+
+// SchemaURLV2 is the JSON schema URL for v2 contracts
+const SchemaURLV2 = "https://cliguard.io/schema/v2"
+
+// ContractV2 is the multi-root contract format. Each root describes one CLI
+// (e.g. one binary in a repository that builds several), keyed by name.
+//
+// A file is treated as v2 when it has a top-level 'roots' key; otherwise it
+// is a v1 Contract.
+//
+// Example YAML:
+//
+//	version: 2.0.0
+//	schema: https://cliguard.io/schema/v2
+//	tags: [public]
+//	roots:
+//	  myapp:
+//	    use: myapp
+//	    short: My application
+//	  myapp-admin:
+//	    use: myapp-admin
+//	    short: Administration tool
+type ContractV2 struct {
+	// Version is the contract format version (optional).
+	// Must have major version 2 if present.
+	Version string `json:"version" yaml:"version"`
+
+	// Schema is the JSON schema URL describing the format (optional)
+	Schema string `json:"schema,omitempty" yaml:"schema,omitempty"`
+
+	// Tags are free-form labels for the contract (optional).
+	// Example: ["public", "stable"]
+	Tags []string `json:"tags" yaml:"tags"`
+
+	// Roots maps a name to each root command's contract (required)
+	Roots map[string]*Contract `json:"roots" yaml:"roots"`
+}
+
+// ConvertToV2 wraps a v1 contract as the single root of a v2 contract,
+// keyed by the root command's name
+func ConvertToV2(c *Contract) *ContractV2 {
+	return &ContractV2{
+		Version: ContractV2Version,
+		Schema:  SchemaURLV2,
+		Tags:    []string{},
+		Roots:   map[string]*Contract{c.Use: c},
+	}
+}
+
+// DefaultRootName is the name MigrateV1ToV2 gives the root of a migrated contract
+const DefaultRootName = "default"
+
+// MigrateV1ToV2 converts a v1 contract to the v2 format, as the root named
+// DefaultRootName. The version, schema and tags fields the v2 format adds
+// are populated; tags start empty.
+func MigrateV1ToV2(v1 *Contract) *ContractV2 {
+	v2 := ConvertToV2(v1)
+	v2.Roots = map[string]*Contract{DefaultRootName: v1}
+	return v2
+}
+
+// RootNames returns the names of the contract's roots in sorted order
+func (c *ContractV2) RootNames() []string {
+	names := make([]string, 0, len(c.Roots))
+	for name := range c.Roots {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Root returns the root contract whose command name matches use. If the
+// contract has a single root, it is returned regardless of its name.
+func (c *ContractV2) Root(use string) (*Contract, error) {
+	for _, name := range c.RootNames() {
+		if root := c.Roots[name]; root.Use == use || CommandName(root.Use) == use {
+			return root, nil
+		}
+	}
+	if len(c.Roots) == 1 {
+		for _, root := range c.Roots {
+			return root, nil
+		}
+	}
+	return nil, fmt.Errorf("no root for command '%s' in contract (roots: %s)", use, strings.Join(c.RootNames(), ", "))
+}
+
+// DetectVersion returns the contract format version of YAML data: 2 if it
+// has a top-level 'roots' key, 1 otherwise (including unparseable data)
+func DetectVersion(data []byte) int {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil || len(doc.Content) == 0 {
+		return 1
+	}
+
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return 1
+	}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == "roots" {
+			return 2
+		}
+	}
+	return 1
+}
+
+// IsV2File reports whether the file at path is a v2 contract. Unreadable
+// files are reported as v1 so that the v1 loader produces the error.
+func IsV2File(path string) bool {
+	path, err := localPath(path)
+	if err != nil {
+		return false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	return DetectVersion(data) == 2
+}
+
+// LoadV2 reads and parses a v2 contract file, adding the commands of the
+// fragments its roots include
+func LoadV2(contractPath string) (*ContractV2, error) {
+	if contractPath == "" {
+		return nil, fmt.Errorf("contract path cannot be empty")
+	}
+
+	absPath, err := localPath(contractPath)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(absPath)
+	if err != nil {
+		return nil, errors.WrapContractNotFound(absPath, err)
+	}
+
+	var contract ContractV2
+	if err := decodeContractFile(data, absPath, &contract); err != nil {
+		return nil, err
+	}
+
+	for _, name := range contract.RootNames() {
+		if root := contract.Roots[name]; root != nil {
+			if err := resolveContractIncludes(root, absPath); err != nil {
+				return nil, err
+			}
+			if err := resolveContractExtends(root, absPath); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := validateV2(&contract); err != nil {
+		return nil, errors.InvalidContractError{
+			Path:    displayPath(contractPath, absPath),
+			Message: err.Error(),
+		}
+	}
+
+	return &contract, nil
+}
+
+// validateV2 performs basic validation on a v2 contract
+func validateV2(contract *ContractV2) error {
+	if contract.Version != "" {
+		major, _, _ := strings.Cut(strings.TrimPrefix(contract.Version, "v"), ".")
+		if major != "2" {
+			return fmt.Errorf("unsupported contract version '%s' (expected 2.x)", contract.Version)
+		}
+	}
+
+	if len(contract.Roots) == 0 {
+		return fmt.Errorf("'roots' must contain at least one root command")
+	}
+
+	for _, name := range contract.RootNames() {
+		root := contract.Roots[name]
+		if root == nil {
+			return fmt.Errorf("root '%s': contract cannot be empty", name)
+		}
+		if err := validate(root); err != nil {
+			return fmt.Errorf("root '%s': %w", name, err)
+		}
+	}
+
+	return nil
+}
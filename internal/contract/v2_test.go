@@ -0,0 +1,181 @@
+package contract
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestDetectVersion(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want int
+	}{
+		{"v1 contract", "use: myapp\nshort: My app\n", 1},
+		{"v2 contract", "version: 2.0.0\nroots:\n  myapp:\n    use: myapp\n", 2},
+		{"nested roots key", "use: myapp\ncommands:\n  - use: roots\n", 1},
+		{"empty", "", 1},
+		{"invalid yaml", "use: [unclosed", 1},
+		{"not a mapping", "- roots\n", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectVersion([]byte(tt.data)); got != tt.want {
+				t.Errorf("DetectVersion() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadV2(t *testing.T) {
+	tests := []struct {
+		name        string
+		yamlContent string
+		errContains string
+	}{
+		{
+			name: "valid multi-root contract",
+			yamlContent: `
+version: 2.0.0
+schema: https://cliguard.io/schema/v2
+tags: [public]
+roots:
+  myapp:
+    use: myapp
+    flags:
+      - name: port
+        type: int
+  myapp-admin:
+    use: myapp-admin
+`,
+		},
+		{
+			name:        "no roots",
+			yamlContent: "version: 2.0.0\nroots: {}\n",
+			errContains: "at least one root",
+		},
+		{
+			name:        "unsupported version",
+			yamlContent: "version: 3.0.0\nroots:\n  myapp:\n    use: myapp\n",
+			errContains: "unsupported contract version",
+		},
+		{
+			name:        "invalid root",
+			yamlContent: "roots:\n  myapp:\n    short: Missing use\n",
+			errContains: "root 'myapp'",
+		},
+		{
+			name:        "invalid flag in root",
+			yamlContent: "roots:\n  myapp:\n    use: myapp\n    flags:\n      - name: port\n        type: integer\n",
+			errContains: "Invalid flag type 'integer'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "cliguard.yaml")
+			if err := os.WriteFile(path, []byte(tt.yamlContent), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := LoadV2(path)
+			if tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Fatalf("LoadV2() error = %v, want error containing %q", err, tt.errContains)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadV2() error = %v", err)
+			}
+			if names := got.RootNames(); len(names) != 2 || names[0] != "myapp" || names[1] != "myapp-admin" {
+				t.Errorf("RootNames() = %v", names)
+			}
+			if len(got.Tags) != 1 || got.Tags[0] != "public" {
+				t.Errorf("Tags = %v, want [public]", got.Tags)
+			}
+		})
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		if _, err := LoadV2(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+			t.Error("LoadV2() expected error for missing file")
+		}
+	})
+}
+
+func TestContractV2_Root(t *testing.T) {
+	multi := &ContractV2{Roots: map[string]*Contract{
+		"app":   {Use: "myapp"},
+		"admin": {Use: "myapp-admin"},
+	}}
+
+	root, err := multi.Root("myapp-admin")
+	if err != nil || root.Use != "myapp-admin" {
+		t.Errorf("Root(myapp-admin) = %v, %v", root, err)
+	}
+
+	if _, err := multi.Root("other"); err == nil || !strings.Contains(err.Error(), "admin, app") {
+		t.Errorf("Root(other) error = %v, want error listing roots", err)
+	}
+
+	// A single root is used whatever the CLI is called
+	single := &ContractV2{Roots: map[string]*Contract{"app": {Use: "myapp"}}}
+	if root, err := single.Root("renamed"); err != nil || root.Use != "myapp" {
+		t.Errorf("Root(renamed) = %v, %v", root, err)
+	}
+}
+
+func TestConvertToV2(t *testing.T) {
+	v2 := ConvertToV2(&Contract{Use: "myapp", Short: "My app"})
+
+	data, err := yaml.Marshal(v2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `version: 2.0.0
+schema: https://cliguard.io/schema/v2
+tags: []
+roots:
+    myapp:
+        use: myapp
+        short: My app
+`
+	if string(data) != want {
+		t.Errorf("ConvertToV2() YAML =\n%s\nwant:\n%s", data, want)
+	}
+	if DetectVersion(data) != 2 {
+		t.Error("converted contract should be detected as v2")
+	}
+}
+
+func TestMigrateV1ToV2(t *testing.T) {
+	v1 := &Contract{Use: "myapp", Short: "My app"}
+	v2 := MigrateV1ToV2(v1)
+
+	data, err := yaml.Marshal(v2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `version: 2.0.0
+schema: https://cliguard.io/schema/v2
+tags: []
+roots:
+    default:
+        use: myapp
+        short: My app
+`
+	if string(data) != want {
+		t.Errorf("MigrateV1ToV2() YAML =\n%s\nwant:\n%s", data, want)
+	}
+	if root, err := v2.Root("myapp"); err != nil || root != v1 {
+		t.Errorf("Root(%q) = %v, %v; want the migrated contract", "myapp", root, err)
+	}
+}
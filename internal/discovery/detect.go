@@ -116,6 +116,14 @@ func detectFrameworkInFile(filePath string, fs filesystem.FileSystem) (string, e
 					strings.Contains(importPath, "github.com/alecthomas")) {
 				return "kingpin", nil
 			}
+
+			// Check for Kong, confirmed by a parser being created, since
+			// packages may import it only for its types
+			if importPath == "github.com/alecthomas/kong" &&
+				(strings.Contains(string(content), "kong.Parse(") ||
+					strings.Contains(string(content), "kong.New(")) {
+				return "kong", nil
+			}
 		}
 	}
 
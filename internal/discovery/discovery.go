@@ -2,6 +2,7 @@ package discovery
 
 import (
 	"bufio"
+	"encoding/json"
 	"fmt"
 	"go/ast"
 	"go/parser"
@@ -35,26 +36,73 @@ func NewDiscoverer(projectPath string, fs filesystem.FileSystem) *Discoverer {
 	}
 }
 
-// DiscoverEntrypoints finds potential CLI entrypoints in the project
-func (d *Discoverer) DiscoverEntrypoints() ([]EntrypointCandidate, error) {
+// DiscovererResult is the outcome of DiscoverEntrypoints: the candidates
+// found, and what was searched to find them, which explains why an expected
+// entrypoint was not found
+type DiscovererResult struct {
+	// Candidates are the potential entrypoints, most likely first
+	Candidates []EntrypointCandidate
+
+	// FilesScanned is the number of Go files found, excluding tests
+	FilesScanned int
+
+	// FilesAnalyzed is the number of scanned files that import a supported
+	// CLI framework. Only these are searched for entrypoint patterns.
+	FilesAnalyzed int
+
+	// SkippedDirectories are the vendor and hidden directories that were not
+	// searched, relative to the project path
+	SkippedDirectories []string
+
+	// Errors are the files that were skipped because they could not be read
+	// or parsed
+	Errors []DiscoveryError
+}
+
+// DiscoveryError is a file DiscoverEntrypoints could not read or parse
+type DiscoveryError struct {
+	// FilePath is the file's path relative to the project path
+	FilePath string
+	Err      error
+}
+
+// Error implements the error interface
+func (e DiscoveryError) Error() string {
+	return fmt.Sprintf("%s: %v", e.FilePath, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e DiscoveryError) Unwrap() error {
+	return e.Err
+}
+
+// DiscoverEntrypoints finds potential CLI entrypoints in the project. Files
+// that cannot be read or parsed are skipped and listed in the result's
+// Errors.
+func (d *Discoverer) DiscoverEntrypoints() (*DiscovererResult, error) {
 	var candidates []EntrypointCandidate
 
 	// First, find all Go files in the project
-	goFiles, err := d.findGoFiles()
+	goFiles, skipped, err := d.findGoFiles()
 	if err != nil {
 		return nil, fmt.Errorf("failed to find Go files: %w", err)
 	}
-
-	// Debug: print found files
-	// fmt.Printf("Found %d Go files\n", len(goFiles))
+	result := &DiscovererResult{
+		FilesScanned:       len(goFiles),
+		SkippedDirectories: skipped,
+	}
 
 	// Check each file for patterns
 	for _, file := range goFiles {
-		fileCandidates, err := d.analyzeFile(file)
+		fileCandidates, analyzed, err := d.analyzeFile(file)
 		if err != nil {
 			// Continue with other files even if one fails
+			result.Errors = append(result.Errors, DiscoveryError{FilePath: file, Err: err})
 			continue
 		}
+		if analyzed {
+			result.FilesAnalyzed++
+		}
 		candidates = append(candidates, fileCandidates...)
 	}
 
@@ -88,12 +136,57 @@ func (d *Discoverer) DiscoverEntrypoints() ([]EntrypointCandidate, error) {
 		return candidates[i].FilePath < candidates[j].FilePath
 	})
 
-	return candidates, nil
+	result.Candidates = candidates
+	return result, nil
+}
+
+// FilterByConfidence returns the candidates with a confidence of at least
+// minConfidence, in their order
+func FilterByConfidence(candidates []EntrypointCandidate, minConfidence int) []EntrypointCandidate {
+	var filtered []EntrypointCandidate
+	for _, candidate := range candidates {
+		if candidate.Confidence >= minConfidence {
+			filtered = append(filtered, candidate)
+		}
+	}
+	return filtered
+}
+
+// FilterByFramework returns the candidates found for one of frameworks, the
+// names of GetCLIPatterns such as "cobra" or "urfave/cli", in their order.
+// With no frameworks, all candidates are returned.
+func FilterByFramework(candidates []EntrypointCandidate, frameworks []string) []EntrypointCandidate {
+	if len(frameworks) == 0 {
+		return candidates
+	}
+	wanted := make(map[string]bool, len(frameworks))
+	for _, framework := range frameworks {
+		wanted[framework] = true
+	}
+	var filtered []EntrypointCandidate
+	for _, candidate := range candidates {
+		if wanted[candidate.Framework] {
+			filtered = append(filtered, candidate)
+		}
+	}
+	return filtered
+}
+
+// FrameworkNames returns the names of the frameworks discovery finds
+// entrypoints for, in the order of GetCLIPatterns
+func FrameworkNames() []string {
+	var names []string
+	for _, pattern := range GetCLIPatterns() {
+		names = append(names, pattern.Name)
+	}
+	return names
 }
 
-// findGoFiles finds all Go files in the project
-func (d *Discoverer) findGoFiles() ([]string, error) {
+// findGoFiles finds all Go files in the project, except tests, and the
+// directories it skipped
+func (d *Discoverer) findGoFiles() ([]string, []string, error) {
 	var goFiles []string
+	var skipped []string
 
 	// Debug project path
 	// fmt.Printf("Walking project path: %s\n", d.projectPath)
@@ -105,6 +198,9 @@ func (d *Discoverer) findGoFiles() ([]string, error) {
 
 		// Skip vendor and hidden directories
 		if info.IsDir() && (strings.HasPrefix(info.Name(), ".") || info.Name() == "vendor") {
+			if relPath, err := filepath.Rel(d.projectPath, path); err == nil {
+				skipped = append(skipped, relPath)
+			}
 			return filepath.SkipDir
 		}
 
@@ -127,24 +223,25 @@ func (d *Discoverer) findGoFiles() ([]string, error) {
 		return nil
 	})
 
-	return goFiles, err
+	return goFiles, skipped, err
 }
 
-// analyzeFile analyzes a single Go file for entrypoint patterns
-func (d *Discoverer) analyzeFile(filePath string) ([]EntrypointCandidate, error) {
+// analyzeFile analyzes a single Go file for entrypoint patterns. It reports
+// whether the file imports a CLI framework, in which case it was searched.
+func (d *Discoverer) analyzeFile(filePath string) ([]EntrypointCandidate, bool, error) {
 	var candidates []EntrypointCandidate
 
 	absPath := filepath.Join(d.projectPath, filePath)
 	content, err := d.fs.ReadFile(absPath)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
 	// Parse the file to get package information
 	fset := token.NewFileSet()
 	node, err := parser.ParseFile(fset, absPath, content, parser.ImportsOnly)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
 	// Check imports to determine which patterns to apply
@@ -152,7 +249,7 @@ func (d *Discoverer) analyzeFile(filePath string) ([]EntrypointCandidate, error)
 	applicablePatterns := d.getApplicablePatterns(imports)
 
 	if len(applicablePatterns) == 0 {
-		return candidates, nil
+		return candidates, false, nil
 	}
 
 	// Get the module path for this specific file
@@ -202,7 +299,7 @@ func (d *Discoverer) analyzeFile(filePath string) ([]EntrypointCandidate, error)
 		}
 	}
 
-	return candidates, nil
+	return candidates, true, nil
 }
 
 // extractImports extracts import paths from the parsed file
@@ -372,20 +469,26 @@ func (d *Discoverer) extractFunctionSignature(content string, lineNumber int) st
 	return ""
 }
 
-// formatGenerateCommand creates a ready-to-use cliguard generate command for a candidate
-func formatGenerateCommand(candidate EntrypointCandidate, projectPath string) string {
-	entrypoint := candidate.PackagePath
-	
-	// For Cobra CLIs, try to determine the correct function name
-	if candidate.Framework == "cobra" {
-		functionName := determineCObraFunctionName(candidate)
-		if functionName != "" {
-			entrypoint = candidate.PackagePath + "." + functionName
+// Entrypoint returns the --entrypoint to pass for the candidate: its package
+// path, followed for Cobra CLIs by the function that returns the root command
+// if it can be determined
+func (c EntrypointCandidate) Entrypoint() string {
+	if c.Framework == "cobra" {
+		if functionName := determineCObraFunctionName(c); functionName != "" {
+			return c.PackagePath + "." + functionName
 		}
 	}
+	return c.PackagePath
+}
+
+// formatGenerateCommand creates a ready-to-use cliguard generate command for a candidate
+func formatGenerateCommand(candidate EntrypointCandidate, projectPath string) string {
+	entrypoint := candidate.Entrypoint()
 
 	if projectPath == "" {
 		projectPath = "."
+	} else if filepath.IsAbs(projectPath) {
+		projectPath = makeRelativeIfPossible(projectPath)
 	}
 
 	cmd := fmt.Sprintf("cliguard generate --project-path %s --entrypoint \"%s\"", projectPath, entrypoint)
@@ -398,6 +501,21 @@ func formatGenerateCommand(candidate EntrypointCandidate, projectPath string) st
 	return cmd
 }
 
+// makeRelativeIfPossible returns absPath relative to the working directory
+// when it is the working directory or below it, so suggested commands stay
+// short and can be shared. Other paths are returned unchanged.
+func makeRelativeIfPossible(absPath string) string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return absPath
+	}
+	relPath, err := filepath.Rel(cwd, absPath)
+	if err != nil || relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+		return absPath
+	}
+	return relPath
+}
+
 // determineCObraFunctionName determines the appropriate function name for Cobra entrypoints
 func determineCObraFunctionName(candidate EntrypointCandidate) string {
 	// If we have a function signature, try to extract the function name
@@ -450,8 +568,14 @@ func determineCObraFunctionName(candidate EntrypointCandidate) string {
 	return ""
 }
 
-// PrintCandidates prints the discovered candidates in a user-friendly format
-func PrintCandidates(w io.Writer, candidates []EntrypointCandidate, projectPath string, force bool) {
+// PrintCandidates prints the discovered candidates in a user-friendly format.
+// If verbose is set, it also prints what was searched (see PrintDiagnostics).
+func PrintCandidates(w io.Writer, result *DiscovererResult, projectPath string, force, verbose bool) {
+	if verbose {
+		defer PrintDiagnostics(w, result)
+	}
+
+	candidates := result.Candidates
 	if len(candidates) == 0 {
 		fmt.Fprintln(w, "No CLI entrypoints found.")
 		fmt.Fprintln(w, "Try specifying the entrypoint manually with --entrypoint flag.")
@@ -514,3 +638,61 @@ func PrintCandidates(w io.Writer, candidates []EntrypointCandidate, projectPath
 		}
 	}
 }
+
+// PrintDiagnostics prints how many files discovery scanned and analyzed,
+// the directories it skipped and the files it could not parse
+func PrintDiagnostics(w io.Writer, result *DiscovererResult) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Discovery diagnostics:")
+	fmt.Fprintf(w, "  Go files scanned: %d\n", result.FilesScanned)
+	fmt.Fprintf(w, "  Files importing a CLI framework: %d\n", result.FilesAnalyzed)
+	if len(result.SkippedDirectories) > 0 {
+		fmt.Fprintf(w, "  Skipped directories: %s\n", strings.Join(result.SkippedDirectories, ", "))
+	}
+	if len(result.Errors) > 0 {
+		fmt.Fprintf(w, "  Files skipped due to errors (%d):\n", len(result.Errors))
+		for _, err := range result.Errors {
+			fmt.Fprintf(w, "    %s\n", err.Error())
+		}
+	}
+}
+
+// candidateJSON is the JSON form of an EntrypointCandidate written by
+// FormatCandidatesJSON
+type candidateJSON struct {
+	FilePath          string `json:"filePath"`
+	LineNumber        int    `json:"lineNumber"`
+	Line              string `json:"line"`
+	Framework         string `json:"framework"`
+	Pattern           string `json:"pattern"`
+	Confidence        int    `json:"confidence"`
+	FunctionSignature string `json:"functionSignature"`
+	PackagePath       string `json:"packagePath"`
+	Command           string `json:"command"`
+}
+
+// FormatCandidatesJSON renders the discovered candidates as a JSON array for
+// tooling. Each element carries the candidate's fields and its ready-to-use
+// generate command.
+func FormatCandidatesJSON(candidates []EntrypointCandidate, projectPath string) ([]byte, error) {
+	entries := make([]candidateJSON, 0, len(candidates))
+	for _, candidate := range candidates {
+		entries = append(entries, candidateJSON{
+			FilePath:          candidate.FilePath,
+			LineNumber:        candidate.LineNumber,
+			Line:              candidate.Line,
+			Framework:         candidate.Framework,
+			Pattern:           candidate.Pattern,
+			Confidence:        candidate.Confidence,
+			FunctionSignature: candidate.FunctionSignature,
+			PackagePath:       candidate.PackagePath,
+			Command:           formatGenerateCommand(candidate, projectPath),
+		})
+	}
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal candidates to JSON: %w", err)
+	}
+	return append(data, '\n'), nil
+}
@@ -2,6 +2,7 @@ package discovery
 
 import (
 	"bytes"
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"strings"
@@ -40,6 +41,15 @@ func (m *MockFileSystem) Stat(name string) (os.FileInfo, error) {
 	return nil, os.ErrNotExist
 }
 
+func (m *MockFileSystem) CopyFile(src, dst string) error {
+	data, err := m.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	m.Files[dst] = data
+	return nil
+}
+
 func TestDiscoverEntrypoints(t *testing.T) {
 	tests := []struct {
 		name              string
@@ -108,6 +118,31 @@ func main() {
 			expectedFirst:     "app := &cli.App{",
 			expectedFramework: "urfave/cli",
 		},
+		{
+			name: "kong framework",
+			files: map[string]string{
+				"/project/go.mod": `module github.com/test/project
+
+go 1.21
+`,
+				"/project/main.go": `package main
+
+import "github.com/alecthomas/kong"
+
+var cli struct {
+	Verbose bool ` + "`help:\"Enable verbose output\"`" + `
+}
+
+func main() {
+	ctx := kong.Parse(&cli)
+	ctx.FatalIfErrorf(ctx.Run())
+}
+`,
+			},
+			expectedCount:     2, // kong.Parse and ctx.Run
+			expectedFirst:     "ctx := kong.Parse(&cli)",
+			expectedFramework: "kong",
+		},
 		{
 			name: "standard flag package",
 			files: map[string]string{
@@ -184,10 +219,11 @@ func main() {
 			discoverer := NewDiscoverer(tempDir, nil)
 
 			// Discover entrypoints
-			candidates, err := discoverer.DiscoverEntrypoints()
+			result, err := discoverer.DiscoverEntrypoints()
 			if err != nil {
 				t.Fatalf("DiscoverEntrypoints() error = %v", err)
 			}
+			candidates := result.Candidates
 
 			// Check count
 			if len(candidates) != tt.expectedCount {
@@ -214,6 +250,171 @@ func main() {
 	}
 }
 
+func TestDiscoverEntrypoints_KongFixture(t *testing.T) {
+	projectPath := filepath.Join("..", "..", "test-suite", "frameworks", "kong")
+
+	result, err := NewDiscoverer(projectPath, nil).DiscoverEntrypoints()
+	if err != nil {
+		t.Fatalf("DiscoverEntrypoints() error = %v", err)
+	}
+	var lines []string
+	for _, c := range result.Candidates {
+		if c.Framework != "kong" {
+			t.Errorf("candidate %q framework = %q, want kong", c.Line, c.Framework)
+		}
+		lines = append(lines, c.Line)
+	}
+	if got := strings.Join(lines, "\n"); !strings.Contains(got, "kong.New(&CLI{}") {
+		t.Errorf("candidates = %q, want the kong.New call", got)
+	}
+
+	framework, err := DetectEntrypointFramework(projectPath, "github.com/cliguard/test/kong/cmd.NewParser", nil)
+	if err != nil {
+		t.Fatalf("DetectEntrypointFramework() error = %v", err)
+	}
+	if framework != "kong" {
+		t.Errorf("DetectEntrypointFramework() = %q, want kong", framework)
+	}
+}
+
+func TestDetectFrameworkInFile_Kong(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "kong.Parse",
+			content: "package main\n\nimport \"github.com/alecthomas/kong\"\n\nvar cli struct{}\n\nfunc main() {\n\tkong.Parse(&cli)\n}\n",
+			want:    "kong",
+		},
+		{
+			name:    "kong.New",
+			content: "package cmd\n\nimport \"github.com/alecthomas/kong\"\n\nfunc NewParser() (*kong.Kong, error) {\n\treturn kong.New(&struct{}{})\n}\n",
+			want:    "kong",
+		},
+		{
+			name:    "types only",
+			content: "package cmd\n\nimport \"github.com/alecthomas/kong\"\n\nvar vars = kong.Vars{}\n",
+			want:    "",
+		},
+		{
+			name:    "kingpin",
+			content: "package main\n\nimport \"github.com/alecthomas/kingpin/v2\"\n\nvar app = kingpin.New(\"app\", \"\")\n",
+			want:    "kingpin",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := &MockFileSystem{Files: map[string][]byte{"/project/main.go": []byte(tt.content)}}
+			got, err := detectFrameworkInFile("/project/main.go", fs)
+			if err != nil {
+				t.Fatalf("detectFrameworkInFile() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("detectFrameworkInFile() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiscoverEntrypoints_Diagnostics(t *testing.T) {
+	tempDir := t.TempDir()
+	files := map[string]string{
+		"main.go": `package main
+
+import "github.com/spf13/cobra"
+
+func NewRootCmd() *cobra.Command {
+	return &cobra.Command{Use: "app"}
+}
+`,
+		"util/util.go":       "package util\n\nfunc Helper() {}\n",
+		"util/util_test.go":  "package util\n",
+		"broken/broken.go":   "this is not go\n",
+		"vendor/dep/dep.go":  "package dep\n",
+		".hidden/hidden.go":  "package hidden\n",
+		"nested/.cache/c.go": "package cache\n",
+		"nested/vendor/v.go": "package v\n",
+		"nested/nested.go":   "package nested\n",
+	}
+	for relPath, content := range files {
+		fullPath := filepath.Join(tempDir, relPath)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write file %s: %v", fullPath, err)
+		}
+	}
+
+	result, err := NewDiscoverer(tempDir, nil).DiscoverEntrypoints()
+	if err != nil {
+		t.Fatalf("DiscoverEntrypoints() error = %v", err)
+	}
+
+	if len(result.Candidates) == 0 {
+		t.Errorf("Expected candidates, got none")
+	}
+	// main.go, util.go, broken.go and nested.go
+	if result.FilesScanned != 4 {
+		t.Errorf("FilesScanned = %d, want 4", result.FilesScanned)
+	}
+	if result.FilesAnalyzed != 1 {
+		t.Errorf("FilesAnalyzed = %d, want 1", result.FilesAnalyzed)
+	}
+
+	wantSkipped := []string{".hidden", filepath.Join("nested", ".cache"), filepath.Join("nested", "vendor"), "vendor"}
+	if strings.Join(result.SkippedDirectories, ",") != strings.Join(wantSkipped, ",") {
+		t.Errorf("SkippedDirectories = %v, want %v", result.SkippedDirectories, wantSkipped)
+	}
+
+	if len(result.Errors) != 1 {
+		t.Fatalf("Expected 1 error, got %v", result.Errors)
+	}
+	if result.Errors[0].FilePath != filepath.Join("broken", "broken.go") {
+		t.Errorf("Error file = %q, want broken/broken.go", result.Errors[0].FilePath)
+	}
+	if !strings.HasPrefix(result.Errors[0].Error(), filepath.Join("broken", "broken.go")+": ") {
+		t.Errorf("Error() = %q, want it to start with the file path", result.Errors[0].Error())
+	}
+}
+
+func TestPrintCandidates_Verbose(t *testing.T) {
+	result := &DiscovererResult{
+		FilesScanned:       12,
+		FilesAnalyzed:      3,
+		SkippedDirectories: []string{".git", "vendor"},
+		Errors: []DiscoveryError{
+			{FilePath: "broken.go", Err: os.ErrPermission},
+		},
+	}
+
+	var buf bytes.Buffer
+	PrintCandidates(&buf, result, ".", false, true)
+	output := buf.String()
+	for _, want := range []string{
+		"No CLI entrypoints found.",
+		"Discovery diagnostics:",
+		"Go files scanned: 12",
+		"Files importing a CLI framework: 3",
+		"Skipped directories: .git, vendor",
+		"Files skipped due to errors (1):",
+		"broken.go: permission denied",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q.\nFull output:\n%s", want, output)
+		}
+	}
+
+	buf.Reset()
+	PrintCandidates(&buf, result, ".", false, false)
+	if strings.Contains(buf.String(), "Discovery diagnostics:") {
+		t.Errorf("Expected no diagnostics without verbose, got:\n%s", buf.String())
+	}
+}
+
 func TestPrintCandidates(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -287,7 +488,7 @@ func TestPrintCandidates(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			var buf bytes.Buffer
-			PrintCandidates(&buf, tt.candidates, ".", false)
+			PrintCandidates(&buf, &DiscovererResult{Candidates: tt.candidates}, ".", false, false)
 
 			output := buf.String()
 			for _, want := range tt.wantOutput {
@@ -300,6 +501,135 @@ func TestPrintCandidates(t *testing.T) {
 	}
 }
 
+func TestFilterByConfidence(t *testing.T) {
+	candidates := []EntrypointCandidate{
+		{Line: "func NewRootCmd() *cobra.Command", Confidence: 95},
+		{Line: "rootCmd := &cobra.Command{", Confidence: 70},
+		{Line: "cmd.AddCommand(serveCmd)", Confidence: 40},
+	}
+
+	if got := FilterByConfidence(candidates, 0); len(got) != 3 {
+		t.Errorf("FilterByConfidence(0) kept %d candidates, want all 3", len(got))
+	}
+	got := FilterByConfidence(candidates, 70)
+	if len(got) != 2 || got[0].Confidence != 95 || got[1].Confidence != 70 {
+		t.Errorf("FilterByConfidence(70) = %+v, want the first two in order", got)
+	}
+	if got := FilterByConfidence(candidates, 100); len(got) != 0 {
+		t.Errorf("FilterByConfidence(100) = %+v, want none", got)
+	}
+}
+
+func TestFilterByFramework(t *testing.T) {
+	candidates := []EntrypointCandidate{
+		{Line: "func NewRootCmd() *cobra.Command", Framework: "cobra"},
+		{Line: "flag.Parse()", Framework: "flag"},
+		{Line: "app := &cli.App{", Framework: "urfave/cli"},
+	}
+
+	if got := FilterByFramework(candidates, nil); len(got) != 3 {
+		t.Errorf("FilterByFramework(nil) kept %d candidates, want all 3", len(got))
+	}
+	got := FilterByFramework(candidates, []string{"urfave/cli", "cobra"})
+	if len(got) != 2 || got[0].Framework != "cobra" || got[1].Framework != "urfave/cli" {
+		t.Errorf("FilterByFramework(urfave/cli, cobra) = %+v, want cobra and urfave/cli in order", got)
+	}
+	if got := FilterByFramework(candidates, []string{"kong"}); len(got) != 0 {
+		t.Errorf("FilterByFramework(kong) = %+v, want none", got)
+	}
+}
+
+func TestFormatCandidatesJSON(t *testing.T) {
+	candidates := []EntrypointCandidate{
+		{
+			FilePath:          "cmd/root.go",
+			LineNumber:        10,
+			Line:              "func NewRootCmd() *cobra.Command {",
+			Framework:         "cobra",
+			Pattern:           "Function returning root cobra.Command",
+			Confidence:        95,
+			FunctionSignature: "func NewRootCmd() *cobra.Command",
+			PackagePath:       "github.com/test/project/cmd",
+		},
+	}
+
+	output, err := FormatCandidatesJSON(candidates, ".")
+	if err != nil {
+		t.Fatalf("FormatCandidatesJSON() error = %v", err)
+	}
+
+	var decoded []map[string]interface{}
+	if err := json.Unmarshal(output, &decoded); err != nil {
+		t.Fatalf("output is not valid JSON: %v\n%s", err, output)
+	}
+	if len(decoded) != 1 {
+		t.Fatalf("expected 1 candidate, got %d", len(decoded))
+	}
+
+	want := map[string]interface{}{
+		"filePath":          "cmd/root.go",
+		"lineNumber":        float64(10),
+		"line":              "func NewRootCmd() *cobra.Command {",
+		"framework":         "cobra",
+		"pattern":           "Function returning root cobra.Command",
+		"confidence":        float64(95),
+		"functionSignature": "func NewRootCmd() *cobra.Command",
+		"packagePath":       "github.com/test/project/cmd",
+		"command":           `cliguard generate --project-path . --entrypoint "github.com/test/project/cmd.NewRootCmd"`,
+	}
+	for key, value := range want {
+		if decoded[0][key] != value {
+			t.Errorf("%s = %v, want %v", key, decoded[0][key], value)
+		}
+	}
+	if strings.Contains(string(output), "Suggested entrypoint") {
+		t.Error("JSON output should not contain the suggested entrypoint summary")
+	}
+
+	empty, err := FormatCandidatesJSON(nil, ".")
+	if err != nil {
+		t.Fatalf("FormatCandidatesJSON(nil) error = %v", err)
+	}
+	if strings.TrimSpace(string(empty)) != "[]" {
+		t.Errorf("expected empty array, got %q", empty)
+	}
+}
+
+func TestMakeRelativeIfPossible(t *testing.T) {
+	workDir := t.TempDir()
+	outside := t.TempDir()
+	t.Chdir(workDir)
+
+	tests := []struct {
+		name    string
+		absPath string
+		want    string
+	}{
+		{"working directory", workDir, "."},
+		{"subdirectory", filepath.Join(workDir, "apps", "tool"), filepath.Join("apps", "tool")},
+		{"dot-prefixed subdirectory", filepath.Join(workDir, "..tool"), "..tool"},
+		{"parent directory", filepath.Dir(workDir), filepath.Dir(workDir)},
+		{"outside the working directory", outside, outside},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := makeRelativeIfPossible(tt.absPath); got != tt.want {
+				t.Errorf("makeRelativeIfPossible(%q) = %q, want %q", tt.absPath, got, tt.want)
+			}
+		})
+	}
+
+	candidate := EntrypointCandidate{
+		Framework:         "cobra",
+		FunctionSignature: "func NewRootCmd() *cobra.Command",
+		PackagePath:       "github.com/test/project/cmd",
+	}
+	got := formatGenerateCommand(candidate, filepath.Join(workDir, "tool"))
+	if want := `cliguard generate --project-path tool --entrypoint "github.com/test/project/cmd.NewRootCmd"`; got != want {
+		t.Errorf("formatGenerateCommand() = %q, want %q", got, want)
+	}
+}
+
 func TestGetModulePath(t *testing.T) {
 	mockFS := &MockFileSystem{
 		Files: map[string][]byte{
@@ -0,0 +1,215 @@
+package discovery
+
+import (
+	"go/ast"
+	"go/token"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// flagDefinerPattern matches the names of the pflag.FlagSet methods that
+// define a flag with a default value, such as StringVarP
+var flagDefinerPattern = regexp.MustCompile(`^(Bool|String|Int|Int8|Int16|Int32|Int64|Uint|Uint8|Uint16|Uint32|Uint64|Float32|Float64|Duration|StringSlice|StringArray|IntSlice|BoolSlice)(Var)?P?$`)
+
+// FindEnvBindings scans the project's Go files, excluding tests, for flags
+// that fall back to an environment variable and returns the variable of
+// each, keyed by flag name. Two patterns are found:
+//
+//	viper.BindEnv("token", "API_TOKEN")
+//	cmd.Flags().String("token", os.Getenv("API_TOKEN"), "API token")
+//
+// A BindEnv key is taken to be the flag name, unless the key is bound to
+// another flag with viper.BindPFlag(key, cmd.Flags().Lookup("flag")).
+// BindEnv with only a key binds the key in upper case, prefixed with the
+// viper.SetEnvPrefix prefix if the project sets one; key replacers are not
+// applied. Calls on viper instances are found as well as on the package.
+// Only constant names are found, and if several commands define a flag
+// with the same name, their bindings are merged: the first one found wins.
+func FindEnvBindings(projectPath string) (map[string]string, error) {
+	s := &envScanner{keyFlags: make(map[string]string)}
+	err := WalkGoFiles(projectPath, func(path string, node *ast.File) error {
+		s.scan(node)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.bindings(), nil
+}
+
+// envBinding is a key bound to an environment variable. env is empty
+// for a key bound by name only.
+type envBinding struct {
+	key string
+	env string
+}
+
+// envScanner collects the environment variable bindings of a project
+type envScanner struct {
+	// keyFlags maps viper keys to the flags bound with BindPFlag
+	keyFlags map[string]string
+	// keyEnvs are the BindEnv calls, in the order found
+	keyEnvs []envBinding
+	// flagEnvs are the flags with an os.Getenv default, in the order found
+	flagEnvs []envBinding
+	// prefix is the argument of SetEnvPrefix
+	prefix string
+}
+
+// scan collects the bindings in a file
+func (s *envScanner) scan(node *ast.File) {
+	osName := importName(node, "os")
+
+	ast.Inspect(node, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+
+		switch method := sel.Sel.Name; {
+		case method == "BindEnv":
+			args := stringArgs(call.Args)
+			if len(args) == 0 || args[0] == "" {
+				return true
+			}
+			binding := envBinding{key: args[0]}
+			if len(args) > 1 {
+				binding.env = args[1]
+			}
+			s.keyEnvs = append(s.keyEnvs, binding)
+		case method == "BindPFlag" && len(call.Args) == 2:
+			key, ok := stringLiteral(call.Args[0])
+			if !ok {
+				return true
+			}
+			if flag, ok := lookupFlagName(call.Args[1]); ok {
+				s.keyFlags[key] = flag
+			}
+		case method == "SetEnvPrefix" && len(call.Args) == 1:
+			if prefix, ok := stringLiteral(call.Args[0]); ok {
+				s.prefix = prefix
+			}
+		case flagDefinerPattern.MatchString(method) && osName != "":
+			s.scanFlagDefinition(call, osName)
+		}
+		return true
+	})
+}
+
+// scanFlagDefinition records a flag whose default is an os.Getenv call, in
+// flag definitions such as flags.StringVarP(&token, "token", "t",
+// os.Getenv("API_TOKEN"), "API token")
+func (s *envScanner) scanFlagDefinition(call *ast.CallExpr, osName string) {
+	name := ""
+	for _, arg := range call.Args {
+		if value, ok := stringLiteral(arg); ok && name == "" {
+			name = value
+			continue
+		}
+		getenv, ok := arg.(*ast.CallExpr)
+		if !ok || len(getenv.Args) != 1 {
+			continue
+		}
+		sel, ok := getenv.Fun.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "Getenv" {
+			continue
+		}
+		if pkg, ok := sel.X.(*ast.Ident); !ok || pkg.Name != osName {
+			continue
+		}
+		env, ok := stringLiteral(getenv.Args[0])
+		if ok && name != "" && env != "" {
+			s.flagEnvs = append(s.flagEnvs, envBinding{key: name, env: env})
+		}
+		return
+	}
+}
+
+// bindings resolves the collected bindings to flag names
+func (s *envScanner) bindings() map[string]string {
+	result := make(map[string]string)
+	add := func(flag, env string) {
+		if _, found := result[flag]; !found {
+			result[flag] = env
+		}
+	}
+
+	for _, binding := range s.keyEnvs {
+		flag := binding.key
+		if bound, ok := s.keyFlags[binding.key]; ok {
+			flag = bound
+		}
+		env := binding.env
+		if env == "" {
+			env = binding.key
+			if s.prefix != "" {
+				env = s.prefix + "_" + env
+			}
+			env = strings.ToUpper(env)
+		}
+		add(flag, env)
+	}
+	for _, binding := range s.flagEnvs {
+		add(binding.key, binding.env)
+	}
+	return result
+}
+
+// lookupFlagName returns the flag name of a call such as
+// cmd.Flags().Lookup("token")
+func lookupFlagName(expr ast.Expr) (string, bool) {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 {
+		return "", false
+	}
+	if sel, ok := call.Fun.(*ast.SelectorExpr); !ok || sel.Sel.Name != "Lookup" {
+		return "", false
+	}
+	return stringLiteral(call.Args[0])
+}
+
+// importName returns the name the file imports path under, or "" if it
+// doesn't import it
+func importName(node *ast.File, path string) string {
+	for _, imp := range node.Imports {
+		if p, err := strconv.Unquote(imp.Path.Value); err != nil || p != path {
+			continue
+		}
+		if imp.Name != nil {
+			return imp.Name.Name
+		}
+		return path[strings.LastIndex(path, "/")+1:]
+	}
+	return ""
+}
+
+// stringArgs returns the leading string literal arguments of a call
+func stringArgs(args []ast.Expr) []string {
+	var values []string
+	for _, arg := range args {
+		value, ok := stringLiteral(arg)
+		if !ok {
+			break
+		}
+		values = append(values, value)
+	}
+	return values
+}
+
+// stringLiteral returns the value of a string literal expression
+func stringLiteral(expr ast.Expr) (string, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	value, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return "", false
+	}
+	return value, true
+}
@@ -0,0 +1,92 @@
+package discovery
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindEnvBindings(t *testing.T) {
+	tempDir := t.TempDir()
+
+	files := map[string]string{
+		"cmd/root.go": `package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func NewRootCmd() *cobra.Command {
+	cmd := &cobra.Command{Use: "app"}
+	cmd.PersistentFlags().String("config", "", "Config file")
+	cmd.PersistentFlags().String("log-level", "info", "Log level")
+	viper.SetEnvPrefix("app")
+	viper.BindEnv("config", "APP_CONFIG_FILE")
+	viper.BindPFlag("logging.level", cmd.PersistentFlags().Lookup("log-level"))
+	viper.BindEnv("logging.level", "APP_LOG_LEVEL")
+	return cmd
+}
+`,
+		"cmd/serve.go": `package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var token string
+
+func newServeCmd() *cobra.Command {
+	cmd := &cobra.Command{Use: "serve"}
+	cmd.Flags().StringVarP(&token, "token", "t", os.Getenv("API_TOKEN"), "API token")
+	cmd.Flags().String("region", os.Getenv("REGION"), "Region")
+	cmd.Flags().Int("port", 8080, "Port")
+	v := viper.New()
+	v.BindEnv("region")
+	return cmd
+}
+`,
+		"cmd/serve_test.go": `package cmd
+
+import "github.com/spf13/viper"
+
+func init() { viper.BindEnv("port", "TEST_PORT") }
+`,
+		"vendor/lib/lib.go": `package lib
+
+import "github.com/spf13/viper"
+
+func init() { viper.BindEnv("port", "VENDORED_PORT") }
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(tempDir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	bindings, err := FindEnvBindings(tempDir)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{
+		"config":    "APP_CONFIG_FILE",
+		"log-level": "APP_LOG_LEVEL",
+		"region":    "APP_REGION",
+		"token":     "API_TOKEN",
+	}, bindings)
+}
+
+func TestFindEnvBindings_NoBindings(t *testing.T) {
+	tempDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644))
+
+	bindings, err := FindEnvBindings(tempDir)
+	require.NoError(t, err)
+	assert.Empty(t, bindings)
+}
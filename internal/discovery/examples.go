@@ -0,0 +1,190 @@
+package discovery
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ExtractExamplesFromTests scans the project's *_test.go files for CLI
+// invocations and returns them as usage examples keyed by command path.
+//
+// Two invocation styles are recognised:
+//   - cmd.SetArgs([]string{"serve", "--port", "8080"})
+//   - os.Args = []string{"mycli", "serve", "--port", "8080"}
+//
+// The command path is made of the leading arguments up to the first flag
+// (e.g. "serve" above, or "" for the root command). Since the extractor does
+// not know the command tree, trailing positional arguments may end up in the
+// path; callers should resolve the path against the actual commands.
+//
+// Example strings contain the arguments only, without the program name.
+// When several invocations share a command path, they are joined with
+// newlines in the order they were found. Invocations that use non-literal
+// arguments or only request help are skipped.
+func ExtractExamplesFromTests(projectPath string) (map[string]string, error) {
+	examples := make(map[string][]string)
+	seen := make(map[string]bool)
+
+	err := filepath.Walk(projectPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		// Skip vendor and hidden directories
+		if info.IsDir() && path != projectPath && (strings.HasPrefix(info.Name(), ".") || info.Name() == "vendor") {
+			return filepath.SkipDir
+		}
+
+		if !strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+
+		fset := token.NewFileSet()
+		node, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			// Continue with other files even if one fails to parse
+			return nil
+		}
+
+		for _, args := range extractInvocations(node) {
+			if len(args) == 0 || isHelpInvocation(args) {
+				continue
+			}
+
+			example := formatExampleArgs(args)
+			if seen[example] {
+				continue
+			}
+			seen[example] = true
+
+			cmdPath := commandPathFromArgs(args)
+			examples[cmdPath] = append(examples[cmdPath], example)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]string, len(examples))
+	for cmdPath, lines := range examples {
+		result[cmdPath] = strings.Join(lines, "\n")
+	}
+	return result, nil
+}
+
+// extractInvocations returns the argument lists of all SetArgs calls and
+// os.Args assignments found in the file
+func extractInvocations(node *ast.File) [][]string {
+	var invocations [][]string
+
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch stmt := n.(type) {
+		case *ast.CallExpr:
+			sel, ok := stmt.Fun.(*ast.SelectorExpr)
+			if !ok || sel.Sel.Name != "SetArgs" || len(stmt.Args) != 1 {
+				return true
+			}
+			if args, ok := stringSliceLiteral(stmt.Args[0]); ok {
+				invocations = append(invocations, args)
+			}
+
+		case *ast.AssignStmt:
+			if len(stmt.Lhs) != 1 || len(stmt.Rhs) != 1 || !isOSArgs(stmt.Lhs[0]) {
+				return true
+			}
+			// The first element is the program name
+			if args, ok := stringSliceLiteral(stmt.Rhs[0]); ok && len(args) > 0 {
+				invocations = append(invocations, args[1:])
+			}
+		}
+		return true
+	})
+
+	return invocations
+}
+
+// isOSArgs reports whether the expression is os.Args
+func isOSArgs(expr ast.Expr) bool {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Args" {
+		return false
+	}
+	ident, ok := sel.X.(*ast.Ident)
+	return ok && ident.Name == "os"
+}
+
+// stringSliceLiteral returns the values of a []string{...} literal made only of string constants
+func stringSliceLiteral(expr ast.Expr) ([]string, bool) {
+	lit, ok := expr.(*ast.CompositeLit)
+	if !ok {
+		return nil, false
+	}
+
+	arrayType, ok := lit.Type.(*ast.ArrayType)
+	if !ok || arrayType.Len != nil {
+		return nil, false
+	}
+	if elt, ok := arrayType.Elt.(*ast.Ident); !ok || elt.Name != "string" {
+		return nil, false
+	}
+
+	values := make([]string, 0, len(lit.Elts))
+	for _, elt := range lit.Elts {
+		basic, ok := elt.(*ast.BasicLit)
+		if !ok || basic.Kind != token.STRING {
+			return nil, false
+		}
+		value, err := strconv.Unquote(basic.Value)
+		if err != nil {
+			return nil, false
+		}
+		values = append(values, value)
+	}
+
+	return values, true
+}
+
+// isHelpInvocation reports whether the arguments only ask for help output
+func isHelpInvocation(args []string) bool {
+	if len(args) > 0 && args[0] == "help" {
+		return true
+	}
+	for _, arg := range args {
+		if arg == "--help" || arg == "-h" {
+			return true
+		}
+	}
+	return false
+}
+
+// commandPathFromArgs returns the leading non-flag arguments joined by spaces
+func commandPathFromArgs(args []string) string {
+	var parts []string
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			break
+		}
+		parts = append(parts, arg)
+	}
+	return strings.Join(parts, " ")
+}
+
+// formatExampleArgs joins the arguments into a shell-like string, quoting
+// arguments that contain whitespace or are empty
+func formatExampleArgs(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg == "" || strings.ContainsAny(arg, " \t\n\"'") {
+			quoted[i] = strconv.Quote(arg)
+		} else {
+			quoted[i] = arg
+		}
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,80 @@
+package discovery
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractExamplesFromTests(t *testing.T) {
+	tempDir := t.TempDir()
+
+	files := map[string]string{
+		"cmd/root_test.go": `package cmd
+
+import (
+	"os"
+	"testing"
+)
+
+func TestServe(t *testing.T) {
+	rootCmd := NewRootCmd()
+	rootCmd.SetArgs([]string{"serve", "--port", "8080"})
+	rootCmd.SetArgs([]string{"serve", "--host", "local host"})
+	rootCmd.SetArgs([]string{"--help"})
+	rootCmd.SetArgs([]string{"config", "set", name})
+}
+
+func TestMain(t *testing.T) {
+	os.Args = []string{"mycli", "version"}
+	os.Args = []string{"mycli", "--verbose"}
+}
+`,
+		"cmd/root.go": `package cmd
+
+func setup() {
+	rootCmd.SetArgs([]string{"not", "a", "test"})
+}
+`,
+		"vendor/dep/dep_test.go": `package dep
+
+func TestDep(t *testing.T) {
+	rootCmd.SetArgs([]string{"vendored"})
+}
+`,
+	}
+
+	for path, content := range files {
+		fullPath := filepath.Join(tempDir, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(fullPath), 0755))
+		require.NoError(t, os.WriteFile(fullPath, []byte(content), 0644))
+	}
+
+	examples, err := ExtractExamplesFromTests(tempDir)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{
+		"serve":   "serve --port 8080\nserve --host \"local host\"",
+		"version": "version",
+		"":        "--verbose",
+	}, examples)
+}
+
+func TestCommandPathFromArgs(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"serve", "--port", "8080"}, "serve"},
+		{[]string{"db", "migrate", "up"}, "db migrate up"},
+		{[]string{"--verbose", "serve"}, ""},
+		{nil, ""},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, commandPathFromArgs(tt.args))
+	}
+}
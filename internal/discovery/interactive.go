@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"fmt"
 	"io"
+	"path/filepath"
 	"strconv"
 	"strings"
 )
@@ -84,17 +85,17 @@ func (s *InteractiveSelector) SelectCandidate(candidates []EntrypointCandidate)
 	}
 }
 
-// FormatSelectedEntrypoint formats the selected entrypoint for display
-func FormatSelectedEntrypoint(candidate *EntrypointCandidate) string {
-	entrypoint := candidate.PackagePath
-	
-	// For Cobra CLIs, try to determine the correct function name
-	if candidate.Framework == "cobra" {
-		functionName := determineCObraFunctionName(*candidate)
-		if functionName != "" {
-			entrypoint = candidate.PackagePath + "." + functionName
-		}
+// FormatSelectedEntrypoint formats the generate flags of the selected
+// entrypoint for display. projectPath, if set, is made relative to the
+// working directory when it is below it, as in the commands discover
+// suggests, and is absolute otherwise.
+func FormatSelectedEntrypoint(candidate *EntrypointCandidate, projectPath string) string {
+	entrypoint := candidate.Entrypoint()
+	if projectPath == "" {
+		return fmt.Sprintf("--entrypoint %s", entrypoint)
+	}
+	if absPath, err := filepath.Abs(projectPath); err == nil {
+		projectPath = makeRelativeIfPossible(absPath)
 	}
-	
-	return fmt.Sprintf("--entrypoint %s", entrypoint)
+	return fmt.Sprintf("--project-path %s --entrypoint %s", projectPath, entrypoint)
 }
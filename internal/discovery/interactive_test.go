@@ -2,6 +2,9 @@ package discovery
 
 import (
 	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -197,10 +200,39 @@ func TestFormatSelectedEntrypoint(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := FormatSelectedEntrypoint(&tt.candidate)
+			result := FormatSelectedEntrypoint(&tt.candidate, "")
 			if result != tt.expected {
 				t.Errorf("Expected %q, got %q", tt.expected, result)
 			}
 		})
 	}
 }
+
+func TestFormatSelectedEntrypoint_ProjectPath(t *testing.T) {
+	candidate := EntrypointCandidate{
+		Framework:         "cobra",
+		FunctionSignature: "func NewRootCmd() *cobra.Command",
+		PackagePath:       "github.com/test/project/cmd",
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Paths below the working directory are relative, as in the commands
+	// discover suggests
+	for _, projectPath := range []string{"./project", filepath.Join(wd, "project")} {
+		result := FormatSelectedEntrypoint(&candidate, projectPath)
+		expected := "--project-path project --entrypoint github.com/test/project/cmd.NewRootCmd"
+		if result != expected {
+			t.Errorf("Expected %q for %q, got %q", expected, projectPath, result)
+		}
+	}
+
+	outside := filepath.Join(filepath.Dir(wd), "other")
+	result := FormatSelectedEntrypoint(&candidate, outside)
+	expected := fmt.Sprintf("--project-path %s --entrypoint github.com/test/project/cmd.NewRootCmd", outside)
+	if result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+}
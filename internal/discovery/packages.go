@@ -0,0 +1,142 @@
+package discovery
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// cobraImportPath is the import path of the Cobra package
+const cobraImportPath = "github.com/spf13/cobra"
+
+// FindCommandPackages scans the project's Go files, excluding tests, for
+// cobra.Command literals and returns the packages that define each command,
+// keyed by command name (the first word of its Use). Packages are given as
+// slash-separated directories relative to the project path, "." for the
+// project root, in sorted order.
+//
+// Only literals with a constant Use are found, such as
+//
+//	cmd := &cobra.Command{Use: "migrate [version]", Short: "Run migrations"}
+//
+// so commands built another way, like Cobra's own help and completion
+// commands, are missing. Several packages may define a command with the
+// same name; callers should resolve those against the command tree.
+func FindCommandPackages(projectPath string) (map[string][]string, error) {
+	found := make(map[string]map[string]bool)
+
+	err := WalkGoFiles(projectPath, func(path string, node *ast.File) error {
+		pkg, err := filepath.Rel(projectPath, filepath.Dir(path))
+		if err != nil {
+			return err
+		}
+		pkg = filepath.ToSlash(pkg)
+		for _, name := range commandLiteralNames(node) {
+			if found[name] == nil {
+				found[name] = make(map[string]bool)
+			}
+			found[name][pkg] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string][]string, len(found))
+	for name, pkgs := range found {
+		for pkg := range pkgs {
+			result[name] = append(result[name], pkg)
+		}
+		sort.Strings(result[name])
+	}
+	return result, nil
+}
+
+// WalkGoFiles parses the project's Go files, excluding tests and the vendor
+// and hidden directories, and calls fn with the path and syntax tree of
+// each, in lexical order. Files that fail to parse are skipped.
+func WalkGoFiles(projectPath string, fn func(path string, node *ast.File) error) error {
+	return filepath.Walk(projectPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		// Skip vendor and hidden directories
+		if info.IsDir() && path != projectPath && (strings.HasPrefix(info.Name(), ".") || info.Name() == "vendor") {
+			return filepath.SkipDir
+		}
+
+		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+
+		fset := token.NewFileSet()
+		node, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			// Continue with other files even if one fails to parse
+			return nil
+		}
+		return fn(path, node)
+	})
+}
+
+// commandLiteralNames returns the command names of the cobra.Command
+// literals in the file that have a constant Use
+func commandLiteralNames(node *ast.File) []string {
+	cobraName := ""
+	for _, imp := range node.Imports {
+		if path, err := strconv.Unquote(imp.Path.Value); err == nil && path == cobraImportPath {
+			cobraName = "cobra"
+			if imp.Name != nil {
+				cobraName = imp.Name.Name
+			}
+		}
+	}
+	if cobraName == "" || cobraName == "_" {
+		return nil
+	}
+
+	var names []string
+	ast.Inspect(node, func(n ast.Node) bool {
+		lit, ok := n.(*ast.CompositeLit)
+		if !ok {
+			return true
+		}
+		sel, ok := lit.Type.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "Command" {
+			return true
+		}
+		if pkg, ok := sel.X.(*ast.Ident); !ok || pkg.Name != cobraName {
+			return true
+		}
+
+		for _, elt := range lit.Elts {
+			kv, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+			if key, ok := kv.Key.(*ast.Ident); !ok || key.Name != "Use" {
+				continue
+			}
+			value, ok := kv.Value.(*ast.BasicLit)
+			if !ok || value.Kind != token.STRING {
+				continue
+			}
+			use, err := strconv.Unquote(value.Value)
+			if err != nil {
+				continue
+			}
+			if fields := strings.Fields(use); len(fields) > 0 {
+				names = append(names, fields[0])
+			}
+		}
+		return true
+	})
+	return names
+}
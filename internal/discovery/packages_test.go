@@ -0,0 +1,84 @@
+package discovery
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindCommandPackages(t *testing.T) {
+	tempDir := t.TempDir()
+
+	files := map[string]string{
+		"main.go": `package main
+
+import "github.com/spf13/cobra"
+
+func main() {
+	root := &cobra.Command{Use: "app", Short: "App"}
+	_ = root.Execute()
+}
+`,
+		"cmd/db/db.go": `package db
+
+import "github.com/spf13/cobra"
+
+func NewDBCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "db",
+		Short: "Manage the database",
+	}
+	cmd.AddCommand(&cobra.Command{Use: "list [filter]", Short: "List tables"})
+	return cmd
+}
+`,
+		"cmd/user/user.go": `package user
+
+import c "github.com/spf13/cobra"
+
+var use = "dynamic"
+
+func NewUserCmd() *c.Command {
+	cmd := &c.Command{Use: "user"}
+	cmd.AddCommand(&c.Command{Use: "list"}, &c.Command{Use: use})
+	return cmd
+}
+`,
+		"cmd/user/user_test.go": `package user
+
+import "github.com/spf13/cobra"
+
+var testCmd = &cobra.Command{Use: "fixture"}
+`,
+		"internal/other/other.go": `package other
+
+type Command struct{ Use string }
+
+var cmd = Command{Use: "not-cobra"}
+`,
+		"vendor/lib/lib.go": `package lib
+
+import "github.com/spf13/cobra"
+
+var cmd = &cobra.Command{Use: "vendored"}
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(tempDir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	packages, err := FindCommandPackages(tempDir)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string][]string{
+		"app":  {"."},
+		"db":   {"cmd/db"},
+		"list": {"cmd/db", "cmd/user"},
+		"user": {"cmd/user"},
+	}, packages)
+}
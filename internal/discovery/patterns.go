@@ -121,6 +121,32 @@ func GetCLIPatterns() []Pattern {
 				"cmd/*.go",
 			},
 		},
+		{
+			Name:        "kong",
+			Description: "Kong CLI framework",
+			Imports:     []string{"github.com/alecthomas/kong"},
+			CodePatterns: []CodePattern{
+				{
+					Pattern:     `kong\.Parse\s*\(`,
+					Description: "Kong command-line parsing",
+					Confidence:  90,
+				},
+				{
+					Pattern:     `kong\.New\s*\(`,
+					Description: "Kong parser creation",
+					Confidence:  90,
+				},
+				{
+					Pattern:     `ctx\.Run\s*\(`,
+					Description: "Kong command execution",
+					Confidence:  60,
+				},
+			},
+			FilePaths: []string{
+				"main.go",
+				"cmd/*.go",
+			},
+		},
 	}
 }
 
@@ -0,0 +1,74 @@
+package discovery
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// ScriptMinConfidence is the confidence, in percent, a candidate needs to
+// get a generate command in the script written by FormatAsShellScript
+const ScriptMinConfidence = 70
+
+// FormatAsShellScript renders a bash script that generates a contract for
+// each candidate with a confidence of at least ScriptMinConfidence, written
+// as cliguard.yaml next to the file the candidate was found in. The paths
+// are relative to the project, so the script runs from the project root.
+//
+// Candidates are expected in the order DiscoverEntrypoints returns them:
+// when several are found in one directory, only the first gets a command,
+// since they would write the same contract file. A failing command removes
+// its partial contract and the script goes on with the others, exiting with
+// status 1 at the end.
+func FormatAsShellScript(candidates []EntrypointCandidate) string {
+	var b strings.Builder
+	b.WriteString("#!/usr/bin/env bash\n")
+	b.WriteString("# Generated by cliguard discover --output-script. Review the commands,\n")
+	b.WriteString("# then run this script from the project root with bash.\n")
+	b.WriteString("set -e\n")
+
+	written := make(map[string]bool)
+	var commands []string
+	for _, candidate := range candidates {
+		if candidate.Confidence < ScriptMinConfidence {
+			continue
+		}
+		outputPath := filepath.ToSlash(filepath.Join(filepath.Dir(candidate.FilePath), "cliguard.yaml"))
+		if written[outputPath] {
+			continue
+		}
+		written[outputPath] = true
+
+		quoted := shellQuote(outputPath)
+		commands = append(commands, fmt.Sprintf(`# %s:%d (%s, confidence: %d%%)
+if ! %s > %s; then
+    echo "Failed to generate" %s >&2
+    rm -f %s
+    failed=1
+fi
+`, candidate.FilePath, candidate.LineNumber, candidate.Framework, candidate.Confidence,
+			formatGenerateCommand(candidate, "."), quoted, quoted, quoted))
+	}
+
+	if len(commands) == 0 {
+		fmt.Fprintf(&b, "\n# No entrypoints with a confidence of %d%% or more were found.\n", ScriptMinConfidence)
+		return b.String()
+	}
+
+	b.WriteString("\nfailed=0\n")
+	for _, command := range commands {
+		b.WriteString("\n")
+		b.WriteString(command)
+	}
+	b.WriteString("\nexit $failed\n")
+	return b.String()
+}
+
+// shellQuote quotes s for a POSIX shell, unless it only has characters
+// that need no quoting
+func shellQuote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,102 @@
+package discovery
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestFormatAsShellScript(t *testing.T) {
+	candidates := []EntrypointCandidate{
+		{
+			FilePath:          "cmd/root.go",
+			LineNumber:        12,
+			Framework:         "cobra",
+			Pattern:           "Function returning root cobra.Command",
+			Confidence:        95,
+			FunctionSignature: "func NewRootCmd() *cobra.Command {",
+			PackagePath:       "example.com/app/cmd",
+		},
+		{
+			// Same directory as the first, so it would overwrite its contract
+			FilePath:    "cmd/execute.go",
+			LineNumber:  8,
+			Framework:   "cobra",
+			Pattern:     "Cobra Execute function",
+			Confidence:  90,
+			Line:        "func Execute() {",
+			PackagePath: "example.com/app/cmd",
+		},
+		{
+			FilePath:          "tools/my gen/main.go",
+			LineNumber:        5,
+			Framework:         "cobra",
+			Pattern:           "Function returning root cobra.Command",
+			Confidence:        70,
+			FunctionSignature: "func NewGenCmd() *cobra.Command {",
+			PackagePath:       "example.com/app/tools/my gen",
+		},
+		{
+			FilePath:    "main.go",
+			LineNumber:  3,
+			Framework:   "flag",
+			Pattern:     "flag.Parse call",
+			Confidence:  60,
+			PackagePath: "example.com/app",
+		},
+	}
+
+	want := `#!/usr/bin/env bash
+# Generated by cliguard discover --output-script. Review the commands,
+# then run this script from the project root with bash.
+set -e
+
+failed=0
+
+# cmd/root.go:12 (cobra, confidence: 95%)
+if ! cliguard generate --project-path . --entrypoint "example.com/app/cmd.NewRootCmd" > cmd/cliguard.yaml; then
+    echo "Failed to generate" cmd/cliguard.yaml >&2
+    rm -f cmd/cliguard.yaml
+    failed=1
+fi
+
+# tools/my gen/main.go:5 (cobra, confidence: 70%)
+if ! cliguard generate --project-path . --entrypoint "example.com/app/tools/my gen.NewGenCmd" > 'tools/my gen/cliguard.yaml'; then
+    echo "Failed to generate" 'tools/my gen/cliguard.yaml' >&2
+    rm -f 'tools/my gen/cliguard.yaml'
+    failed=1
+fi
+
+exit $failed
+`
+	got := FormatAsShellScript(candidates)
+	if got != want {
+		t.Errorf("FormatAsShellScript() =\n%s\nwant:\n%s", got, want)
+	}
+
+	// The script must at least parse
+	if bash, err := exec.LookPath("bash"); err == nil {
+		script := filepath.Join(t.TempDir(), "generate.sh")
+		if err := os.WriteFile(script, []byte(got), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if output, err := exec.Command(bash, "-n", script).CombinedOutput(); err != nil {
+			t.Errorf("bash -n failed: %v\n%s", err, output)
+		}
+	}
+}
+
+func TestFormatAsShellScript_NoConfidentCandidates(t *testing.T) {
+	got := FormatAsShellScript([]EntrypointCandidate{{FilePath: "main.go", Framework: "flag", Confidence: 50}})
+	want := `#!/usr/bin/env bash
+# Generated by cliguard discover --output-script. Review the commands,
+# then run this script from the project root with bash.
+set -e
+
+# No entrypoints with a confidence of 70% or more were found.
+`
+	if got != want {
+		t.Errorf("FormatAsShellScript() =\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,176 @@
+// Package doctor diagnoses common problems with a cliguard setup, such as a
+// missing Go toolchain or contract file, and repairs the ones it can.
+//
+// Example:
+//
+//	d := doctor.New(doctor.Config{ProjectPath: "."})
+//	for _, result := range d.Diagnose() {
+//	    fmt.Printf("[%s] %s: %s\n", result.Status, result.Check, result.Message)
+//	}
+package doctor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/executor"
+	"github.com/hiAndrewQuinn/cliguard/internal/filesystem"
+)
+
+// Status indicates the outcome of a check
+type Status string
+
+const (
+	StatusOK      Status = "ok"
+	StatusWarning Status = "warning"
+	StatusError   Status = "error"
+)
+
+// Check identifies a diagnostic
+type Check string
+
+const (
+	CheckGo       Check = "go"
+	CheckGoSum    Check = "go-sum"
+	CheckContract Check = "contract"
+	CheckPath     Check = "path"
+	CheckCache    Check = "cache"
+)
+
+// DiagnosticResult is the outcome of a single check
+type DiagnosticResult struct {
+	Check   Check
+	Status  Status
+	Message string
+}
+
+// Config contains the dependencies of a Doctor. Zero values are replaced
+// with the real implementations by New.
+type Config struct {
+	// ProjectPath is the Go project to diagnose
+	ProjectPath string
+
+	// CacheDir is cliguard's cache directory. Defaults to cliguard under
+	// os.UserCacheDir.
+	CacheDir string
+
+	FileSystem filesystem.FileSystem
+	Executor   executor.CommandExecutor
+
+	// LookPath finds executables on $PATH. Defaults to exec.LookPath.
+	LookPath func(string) (string, error)
+
+	// MkdirAll creates directories. Defaults to os.MkdirAll.
+	MkdirAll func(string, os.FileMode) error
+}
+
+// Doctor runs the diagnostics and fixes for a project
+type Doctor struct {
+	config Config
+}
+
+// New creates a Doctor with the given configuration
+func New(config Config) *Doctor {
+	if config.FileSystem == nil {
+		config.FileSystem = &filesystem.OSFileSystem{}
+	}
+	if config.Executor == nil {
+		config.Executor = &executor.OSExecutor{}
+	}
+	if config.LookPath == nil {
+		config.LookPath = exec.LookPath
+	}
+	if config.MkdirAll == nil {
+		config.MkdirAll = os.MkdirAll
+	}
+	if config.CacheDir == "" {
+		if cacheDir, err := os.UserCacheDir(); err == nil {
+			config.CacheDir = filepath.Join(cacheDir, "cliguard")
+		}
+	}
+	return &Doctor{config: config}
+}
+
+// Diagnose runs every check and returns the results in a fixed order
+func (d *Doctor) Diagnose() []DiagnosticResult {
+	goResult := d.checkGo()
+	results := []DiagnosticResult{goResult}
+	if goResult.Status == StatusOK {
+		results = append(results, d.checkGoSum())
+	}
+	return append(results, d.checkContract(), d.checkPath(), d.checkCache())
+}
+
+// checkGo checks that the Go toolchain is installed
+func (d *Doctor) checkGo() DiagnosticResult {
+	path, err := d.config.LookPath("go")
+	if err != nil {
+		return DiagnosticResult{CheckGo, StatusError, "Go is not installed or not on $PATH; install it from https://go.dev/dl/"}
+	}
+	return DiagnosticResult{CheckGo, StatusOK, "found " + path}
+}
+
+// checkGoSum checks that go.mod and go.sum match the project's imports
+func (d *Doctor) checkGoSum() DiagnosticResult {
+	if _, err := d.config.FileSystem.Stat(filepath.Join(d.config.ProjectPath, "go.mod")); err != nil {
+		return DiagnosticResult{CheckGoSum, StatusError, "no go.mod found in " + d.config.ProjectPath + "; cliguard inspects Go modules"}
+	}
+
+	cmd := d.config.Executor.Command("go", "mod", "tidy", "-diff")
+	cmd.SetDir(d.config.ProjectPath)
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		return DiagnosticResult{CheckGoSum, StatusOK, "go.mod and go.sum are up to date"}
+	}
+
+	// go mod tidy -diff fails with a diff of the files it would change
+	if strings.Contains(string(output), "go.sum") || strings.Contains(string(output), "go.mod") {
+		return DiagnosticResult{CheckGoSum, StatusWarning, "go.mod or go.sum is out of date"}
+	}
+	return DiagnosticResult{CheckGoSum, StatusError, fmt.Sprintf("could not check go.sum: %v", err)}
+}
+
+// checkContract checks that the project has a cliguard.yaml
+func (d *Doctor) checkContract() DiagnosticResult {
+	path := filepath.Join(d.config.ProjectPath, "cliguard.yaml")
+	_, err := d.config.FileSystem.Stat(path)
+	switch {
+	case err == nil:
+		return DiagnosticResult{CheckContract, StatusOK, "found " + path}
+	case errors.Is(err, os.ErrNotExist):
+		return DiagnosticResult{CheckContract, StatusWarning, path + " is missing"}
+	default:
+		return DiagnosticResult{CheckContract, StatusError, fmt.Sprintf("cannot read %s: %v", path, err)}
+	}
+}
+
+// checkPath checks that cliguard itself is on $PATH
+func (d *Doctor) checkPath() DiagnosticResult {
+	path, err := d.config.LookPath("cliguard")
+	if err != nil {
+		return DiagnosticResult{CheckPath, StatusWarning, "cliguard is not on $PATH"}
+	}
+	return DiagnosticResult{CheckPath, StatusOK, "found " + path}
+}
+
+// checkCache checks that cliguard's cache directory exists
+func (d *Doctor) checkCache() DiagnosticResult {
+	if d.config.CacheDir == "" {
+		return DiagnosticResult{CheckCache, StatusError, "cannot determine the user cache directory"}
+	}
+	info, err := d.config.FileSystem.Stat(d.config.CacheDir)
+	switch {
+	case err == nil && info.IsDir():
+		return DiagnosticResult{CheckCache, StatusOK, "found " + d.config.CacheDir}
+	case err == nil:
+		return DiagnosticResult{CheckCache, StatusError, d.config.CacheDir + " exists but is not a directory"}
+	case errors.Is(err, os.ErrNotExist):
+		return DiagnosticResult{CheckCache, StatusWarning, d.config.CacheDir + " is missing"}
+	default:
+		return DiagnosticResult{CheckCache, StatusError, fmt.Sprintf("cannot read %s: %v", d.config.CacheDir, err)}
+	}
+}
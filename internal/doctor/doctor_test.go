@@ -0,0 +1,118 @@
+package doctor
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/executor"
+	"github.com/hiAndrewQuinn/cliguard/internal/filesystem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// healthyConfig returns a Config for a project where every check passes
+func healthyConfig() (Config, *filesystem.MockFileSystem, *executor.MockExecutor) {
+	fs := filesystem.NewMockFileSystem()
+	fs.Files["/project/go.mod"] = []byte("module github.com/test/mycli\n")
+	fs.Files["/project/cliguard.yaml"] = []byte("use: mycli\n")
+	fs.Directories["/home/test/.cache/cliguard"] = true
+
+	exec := &executor.MockExecutor{
+		Results: map[string]executor.MockResult{
+			"go mod tidy -diff": {},
+		},
+	}
+
+	config := Config{
+		ProjectPath: "/project",
+		CacheDir:    "/home/test/.cache/cliguard",
+		FileSystem:  fs,
+		Executor:    exec,
+		LookPath: func(name string) (string, error) {
+			return "/usr/local/bin/" + name, nil
+		},
+	}
+	return config, fs, exec
+}
+
+func statusByCheck(results []DiagnosticResult) map[Check]Status {
+	statuses := make(map[Check]Status)
+	for _, result := range results {
+		statuses[result.Check] = result.Status
+	}
+	return statuses
+}
+
+func TestDoctor_Diagnose_Healthy(t *testing.T) {
+	config, _, exec := healthyConfig()
+
+	results := New(config).Diagnose()
+	require.Len(t, results, 5)
+	for _, result := range results {
+		assert.Equal(t, StatusOK, result.Status, "%s: %s", result.Check, result.Message)
+	}
+	require.Len(t, exec.Commands, 1)
+	assert.Equal(t, "/project", exec.Commands[0].Dir)
+}
+
+func TestDoctor_Diagnose_Problems(t *testing.T) {
+	config, fs, exec := healthyConfig()
+	delete(fs.Files, "/project/cliguard.yaml")
+	delete(fs.Directories, "/home/test/.cache/cliguard")
+	exec.Results["go mod tidy -diff"] = executor.MockResult{
+		Output: []byte("--- current/go.sum\n+++ tidy/go.sum\n"),
+		Error:  errors.New("exit status 1"),
+	}
+	config.LookPath = func(name string) (string, error) {
+		if name == "cliguard" {
+			return "", errors.New("not found")
+		}
+		return "/usr/local/bin/" + name, nil
+	}
+
+	statuses := statusByCheck(New(config).Diagnose())
+	assert.Equal(t, map[Check]Status{
+		CheckGo:       StatusOK,
+		CheckGoSum:    StatusWarning,
+		CheckContract: StatusWarning,
+		CheckPath:     StatusWarning,
+		CheckCache:    StatusWarning,
+	}, statuses)
+}
+
+func TestDoctor_Diagnose_GoMissing(t *testing.T) {
+	config, _, exec := healthyConfig()
+	config.LookPath = func(name string) (string, error) {
+		return "", errors.New("not found")
+	}
+
+	results := New(config).Diagnose()
+	statuses := statusByCheck(results)
+	assert.Equal(t, StatusError, statuses[CheckGo])
+	assert.NotContains(t, statuses, CheckGoSum, "go.sum cannot be checked without Go")
+	assert.Empty(t, exec.Commands)
+}
+
+func TestDoctor_Diagnose_PermissionError(t *testing.T) {
+	config, fs, _ := healthyConfig()
+	fs.StatErrors["/project/cliguard.yaml"] = os.ErrPermission
+
+	statuses := statusByCheck(New(config).Diagnose())
+	assert.Equal(t, StatusError, statuses[CheckContract])
+}
+
+func TestDoctor_Fix(t *testing.T) {
+	config, _, _ := healthyConfig()
+	results := []DiagnosticResult{
+		{Check: CheckGo, Status: StatusError, Message: "Go is not installed"},
+		{Check: CheckPath, Status: StatusWarning, Message: "cliguard is not on $PATH"},
+		{Check: CheckContract, Status: StatusOK, Message: "found"},
+	}
+
+	outcomes := New(config).Fix(results)
+	require.Len(t, outcomes, 2)
+	assert.Nil(t, outcomes[0].Fixer, "missing Go cannot be fixed")
+	assert.IsType(t, &PathFixer{}, outcomes[1].Fixer)
+	assert.NoError(t, outcomes[1].Err)
+}
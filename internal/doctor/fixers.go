@@ -0,0 +1,186 @@
+package doctor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/executor"
+	"github.com/hiAndrewQuinn/cliguard/internal/filesystem"
+)
+
+// Fixer repairs the problem reported by a diagnostic
+type Fixer interface {
+	// CanFix reports whether the fixer repairs the problem in result
+	CanFix(result DiagnosticResult) bool
+
+	// Fix repairs the problem
+	Fix() error
+
+	// Description says what Fix does, e.g. "ran go mod tidy"
+	Description() string
+}
+
+// FixOutcome is the result of trying to fix one problem. Fixer is nil when
+// no fixer can repair the problem.
+type FixOutcome struct {
+	Result DiagnosticResult
+	Fixer  Fixer
+	Err    error
+}
+
+// Fixers returns the registered fixers, configured for the doctor's project
+func (d *Doctor) Fixers() []Fixer {
+	return []Fixer{
+		&GoModTidyFixer{ProjectPath: d.config.ProjectPath, Executor: d.config.Executor},
+		&ContractTemplateFixer{ProjectPath: d.config.ProjectPath, FileSystem: d.config.FileSystem},
+		&PathFixer{},
+		&CacheDirFixer{Dir: d.config.CacheDir, MkdirAll: d.config.MkdirAll},
+	}
+}
+
+// FixerFor returns the first registered fixer that can repair result, or
+// nil if there is none
+func (d *Doctor) FixerFor(result DiagnosticResult) Fixer {
+	for _, fixer := range d.Fixers() {
+		if fixer.CanFix(result) {
+			return fixer
+		}
+	}
+	return nil
+}
+
+// Fix runs the fixer for each problem in results. Results with StatusOK are
+// skipped.
+func (d *Doctor) Fix(results []DiagnosticResult) []FixOutcome {
+	var outcomes []FixOutcome
+	for _, result := range results {
+		if result.Status == StatusOK {
+			continue
+		}
+		outcome := FixOutcome{Result: result, Fixer: d.FixerFor(result)}
+		if outcome.Fixer != nil {
+			outcome.Err = outcome.Fixer.Fix()
+		}
+		outcomes = append(outcomes, outcome)
+	}
+	return outcomes
+}
+
+// GoModTidyFixer runs go mod tidy when go.mod or go.sum is out of date
+type GoModTidyFixer struct {
+	ProjectPath string
+	Executor    executor.CommandExecutor
+}
+
+// CanFix reports whether result is an out-of-date go.sum
+func (f *GoModTidyFixer) CanFix(result DiagnosticResult) bool {
+	return result.Check == CheckGoSum && result.Status == StatusWarning
+}
+
+// Fix runs go mod tidy in the project
+func (f *GoModTidyFixer) Fix() error {
+	cmd := f.Executor.Command("go", "mod", "tidy")
+	cmd.SetDir(f.ProjectPath)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("go mod tidy failed: %w\nOutput: %s", err, output)
+	}
+	return nil
+}
+
+// Description says what Fix does
+func (f *GoModTidyFixer) Description() string {
+	return "ran go mod tidy"
+}
+
+// ContractTemplateFixer creates a template cliguard.yaml when it is missing
+type ContractTemplateFixer struct {
+	ProjectPath string
+	FileSystem  filesystem.FileSystem
+}
+
+// CanFix reports whether result is a missing contract
+func (f *ContractTemplateFixer) CanFix(result DiagnosticResult) bool {
+	return result.Check == CheckContract && result.Status == StatusWarning
+}
+
+// Fix writes a minimal contract named after the project's module
+func (f *ContractTemplateFixer) Fix() error {
+	path := filepath.Join(f.ProjectPath, "cliguard.yaml")
+	template := fmt.Sprintf(`# Cliguard contract file
+# Replace this template with the contract of your CLI:
+#   cliguard generate --project-path . --entrypoint <package>.NewRootCmd --output-file cliguard.yaml
+use: %s
+short: ""
+`, f.commandName())
+	if err := f.FileSystem.WriteFile(path, []byte(template), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// commandName guesses the CLI's name from the last element of the module
+// path, falling back to the project directory name
+func (f *ContractTemplateFixer) commandName() string {
+	data, err := f.FileSystem.ReadFile(filepath.Join(f.ProjectPath, "go.mod"))
+	if err == nil {
+		for _, line := range strings.Split(string(data), "\n") {
+			if module, ok := strings.CutPrefix(strings.TrimSpace(line), "module "); ok {
+				return filepath.Base(strings.Trim(strings.TrimSpace(module), `"`))
+			}
+		}
+	}
+	if abs, err := filepath.Abs(f.ProjectPath); err == nil {
+		return filepath.Base(abs)
+	}
+	return "app"
+}
+
+// Description says what Fix does
+func (f *ContractTemplateFixer) Description() string {
+	return "created a template " + filepath.Join(f.ProjectPath, "cliguard.yaml")
+}
+
+// PathFixer explains how to put cliguard on $PATH. Changing the user's shell
+// configuration is left to them, so Fix only succeeds.
+type PathFixer struct{}
+
+// CanFix reports whether result is cliguard missing from $PATH
+func (f *PathFixer) CanFix(result DiagnosticResult) bool {
+	return result.Check == CheckPath && result.Status == StatusWarning
+}
+
+// Fix does nothing; Description carries the instructions
+func (f *PathFixer) Fix() error {
+	return nil
+}
+
+// Description gives the instructions for adding cliguard to $PATH
+func (f *PathFixer) Description() string {
+	return `add Go's bin directory to $PATH, e.g. in your shell profile: export PATH="$PATH:$(go env GOPATH)/bin"`
+}
+
+// CacheDirFixer creates cliguard's cache directory when it is missing
+type CacheDirFixer struct {
+	Dir      string
+	MkdirAll func(string, os.FileMode) error
+}
+
+// CanFix reports whether result is a missing cache directory
+func (f *CacheDirFixer) CanFix(result DiagnosticResult) bool {
+	return result.Check == CheckCache && result.Status == StatusWarning
+}
+
+// Fix creates the cache directory
+func (f *CacheDirFixer) Fix() error {
+	if err := f.MkdirAll(f.Dir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", f.Dir, err)
+	}
+	return nil
+}
+
+// Description says what Fix does
+func (f *CacheDirFixer) Description() string {
+	return "created " + f.Dir
+}
@@ -0,0 +1,74 @@
+package doctor
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/executor"
+	"github.com/hiAndrewQuinn/cliguard/internal/filesystem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGoModTidyFixer(t *testing.T) {
+	exec := &executor.MockExecutor{
+		Results: map[string]executor.MockResult{"go mod tidy": {}},
+	}
+	fixer := &GoModTidyFixer{ProjectPath: "/project", Executor: exec}
+
+	assert.True(t, fixer.CanFix(DiagnosticResult{Check: CheckGoSum, Status: StatusWarning}))
+	assert.False(t, fixer.CanFix(DiagnosticResult{Check: CheckGoSum, Status: StatusError}))
+	assert.False(t, fixer.CanFix(DiagnosticResult{Check: CheckContract, Status: StatusWarning}))
+
+	require.NoError(t, fixer.Fix())
+	require.Len(t, exec.Commands, 1)
+	assert.Equal(t, executor.MockCommand{Name: "go", Args: []string{"mod", "tidy"}, Dir: "/project"}, exec.Commands[0])
+
+	exec.Results["go mod tidy"] = executor.MockResult{Output: []byte("no network"), Error: errors.New("exit status 1")}
+	err := fixer.Fix()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no network")
+}
+
+func TestContractTemplateFixer(t *testing.T) {
+	fs := filesystem.NewMockFileSystem()
+	fs.Files["/project/go.mod"] = []byte("module github.com/test/mycli\n\ngo 1.24\n")
+	fixer := &ContractTemplateFixer{ProjectPath: "/project", FileSystem: fs}
+
+	assert.True(t, fixer.CanFix(DiagnosticResult{Check: CheckContract, Status: StatusWarning}))
+	assert.False(t, fixer.CanFix(DiagnosticResult{Check: CheckContract, Status: StatusError}))
+
+	require.NoError(t, fixer.Fix())
+	assert.Contains(t, string(fs.Files["/project/cliguard.yaml"]), "use: mycli\n")
+	assert.Equal(t, "created a template /project/cliguard.yaml", fixer.Description())
+}
+
+func TestPathFixer(t *testing.T) {
+	fixer := &PathFixer{}
+
+	assert.True(t, fixer.CanFix(DiagnosticResult{Check: CheckPath, Status: StatusWarning}))
+	assert.False(t, fixer.CanFix(DiagnosticResult{Check: CheckGo, Status: StatusError}))
+	assert.NoError(t, fixer.Fix())
+	assert.Contains(t, fixer.Description(), "$PATH")
+}
+
+func TestCacheDirFixer(t *testing.T) {
+	var created string
+	fixer := &CacheDirFixer{
+		Dir: "/home/test/.cache/cliguard",
+		MkdirAll: func(path string, perm os.FileMode) error {
+			created = path
+			return nil
+		},
+	}
+
+	assert.True(t, fixer.CanFix(DiagnosticResult{Check: CheckCache, Status: StatusWarning}))
+	assert.False(t, fixer.CanFix(DiagnosticResult{Check: CheckCache, Status: StatusError}))
+
+	require.NoError(t, fixer.Fix())
+	assert.Equal(t, "/home/test/.cache/cliguard", created)
+
+	fixer.MkdirAll = func(string, os.FileMode) error { return os.ErrPermission }
+	assert.ErrorIs(t, fixer.Fix(), os.ErrPermission)
+}
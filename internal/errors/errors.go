@@ -1,12 +1,25 @@
 package errors
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 )
 
+// ErrValidationFailed is returned when the CLI structure does not match the
+// contract. The validation report has already been printed by the time this
+// error is returned, so callers only need to set a non-zero exit code.
+var ErrValidationFailed = errors.New("validation failed")
+
+// ErrBreakingChanges is returned by compare when the new CLI breaks
+// invocations of the old one, and by validate --generate-on-mismatch when
+// the CLI lost commands or flags of the contract. As with
+// ErrValidationFailed, the differences have already been printed; callers
+// exit with status 2.
+var ErrBreakingChanges = errors.New("breaking changes found")
+
 // ContractNotFoundError indicates the contract file could not be found
 type ContractNotFoundError struct {
 	Path         string
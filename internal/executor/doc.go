@@ -29,6 +29,17 @@
 //	    return "", fmt.Errorf("unexpected command: %s", name)
 //	}
 //
+// # Dry Runs
+//
+// DryRunExecutor records commands instead of running them, which shows what
+// an operation would do:
+//
+//	dryRun := executor.NewDryRunExecutor([]byte("{}"))
+//	// ... pass dryRun wherever a CommandExecutor is accepted ...
+//	for _, record := range dryRun.Records() {
+//	    fmt.Println("Would run:", record)
+//	}
+//
 // # Command Execution
 //
 // The executor handles:
@@ -0,0 +1,76 @@
+package executor
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// CommandRecord is a command recorded by DryRunExecutor
+type CommandRecord struct {
+	Name string
+	Args []string
+	Dir  string
+}
+
+// String returns the command line, e.g. "go mod init cliguard-inspector"
+func (r CommandRecord) String() string {
+	return strings.Join(append([]string{r.Name}, r.Args...), " ")
+}
+
+// DryRunExecutor records commands instead of running them. Every command
+// succeeds and returns Output.
+type DryRunExecutor struct {
+	// Output is returned by every command's Output and CombinedOutput
+	Output []byte
+
+	mu      sync.Mutex
+	records []CommandRecord
+}
+
+// NewDryRunExecutor creates a DryRunExecutor whose commands return output
+func NewDryRunExecutor(output []byte) *DryRunExecutor {
+	return &DryRunExecutor{Output: output}
+}
+
+// Command creates a command that is recorded when run
+func (d *DryRunExecutor) Command(name string, args ...string) Command {
+	return &dryRunCommand{executor: d, record: CommandRecord{Name: name, Args: args}}
+}
+
+// CommandContext creates a command that is recorded when run. Nothing runs,
+// so the context is ignored.
+func (d *DryRunExecutor) CommandContext(ctx context.Context, name string, args ...string) Command {
+	return d.Command(name, args...)
+}
+
+// Records returns the commands run so far, in order
+func (d *DryRunExecutor) Records() []CommandRecord {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]CommandRecord(nil), d.records...)
+}
+
+// dryRunCommand implements the Command interface for DryRunExecutor
+type dryRunCommand struct {
+	executor *DryRunExecutor
+	record   CommandRecord
+}
+
+// SetDir sets the working directory that is recorded
+func (c *dryRunCommand) SetDir(dir string) {
+	c.record.Dir = dir
+}
+
+// Output records the command and returns the executor's fake output
+func (c *dryRunCommand) Output() ([]byte, error) {
+	c.executor.mu.Lock()
+	defer c.executor.mu.Unlock()
+	c.executor.records = append(c.executor.records, c.record)
+	return c.executor.Output, nil
+}
+
+// CombinedOutput records the command and returns the executor's fake output
+func (c *dryRunCommand) CombinedOutput() ([]byte, error) {
+	return c.Output()
+}
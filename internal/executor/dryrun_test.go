@@ -0,0 +1,46 @@
+package executor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDryRunExecutor_RecordsCommands(t *testing.T) {
+	dryRun := NewDryRunExecutor([]byte("{}"))
+
+	initCmd := dryRun.Command("go", "mod", "init", "cliguard-inspector")
+	initCmd.SetDir("/tmp/inspect")
+	output, err := initCmd.CombinedOutput()
+	require.NoError(t, err)
+	assert.Equal(t, "{}", string(output))
+
+	runCmd := dryRun.CommandContext(context.Background(), "go", "run", "inspector.go")
+	_, err = runCmd.Output()
+	require.NoError(t, err)
+
+	records := dryRun.Records()
+	require.Len(t, records, 2)
+	assert.Equal(t, CommandRecord{Name: "go", Args: []string{"mod", "init", "cliguard-inspector"}, Dir: "/tmp/inspect"}, records[0])
+	assert.Equal(t, "go run inspector.go", records[1].String())
+}
+
+func TestDryRunExecutor_NotRecordedUntilRun(t *testing.T) {
+	dryRun := NewDryRunExecutor(nil)
+	dryRun.Command("go", "build")
+	assert.Empty(t, dryRun.Records())
+}
+
+func TestDryRunExecutor_WithTimeoutExecutor(t *testing.T) {
+	dryRun := NewDryRunExecutor([]byte("ok"))
+	cmd := NewTimeoutExecutor(dryRun, time.Second).Command("go", "version")
+
+	output, err := cmd.Output()
+	require.NoError(t, err)
+	assert.Equal(t, "ok", string(output))
+	require.Len(t, dryRun.Records(), 1)
+	assert.Equal(t, "go version", dryRun.Records()[0].String())
+}
@@ -4,12 +4,45 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 )
 
 // MockExecutor is a mock implementation for testing
 type MockExecutor struct {
 	Commands []MockCommand
 	Results  map[string]MockResult
+
+	delays map[string]mockDelay
+}
+
+// mockDelay is how long a mocked command takes to run
+type mockDelay struct {
+	duration time.Duration
+
+	// interruptible ends the delay as soon as the command's context is done
+	interruptible bool
+}
+
+// SetDelay makes the command with the given key, such as "go run
+// inspector.go", take delay to run. The delay is not cut short, like a
+// process that ignores signals, but a command created with CommandContext
+// returns its context's error afterwards if the context was done by then.
+func (m *MockExecutor) SetDelay(commandKey string, delay time.Duration) {
+	m.setDelay(commandKey, mockDelay{duration: delay})
+}
+
+// SetContextDelay makes the command with the given key take delay to run,
+// like SetDelay, except that a command created with CommandContext returns
+// its context's error as soon as the context is done
+func (m *MockExecutor) SetContextDelay(commandKey string, delay time.Duration) {
+	m.setDelay(commandKey, mockDelay{duration: delay, interruptible: true})
+}
+
+func (m *MockExecutor) setDelay(commandKey string, delay mockDelay) {
+	if m.delays == nil {
+		m.delays = make(map[string]mockDelay)
+	}
+	m.delays[commandKey] = delay
 }
 
 // MockCommand represents a recorded command execution
@@ -69,6 +102,9 @@ func (c *mockCommand) Output() ([]byte, error) {
 	})
 
 	key := c.commandKey()
+	if err := c.wait(key); err != nil {
+		return nil, err
+	}
 	if result, ok := c.executor.Results[key]; ok {
 		return result.Output, result.Error
 	}
@@ -82,6 +118,30 @@ func (c *mockCommand) CombinedOutput() ([]byte, error) {
 	return c.Output()
 }
 
+// wait sleeps for the delay set for the command, if any. It returns the
+// context's error if the command's context is done by the end of the delay,
+// or with SetContextDelay, as soon as it is.
+func (c *mockCommand) wait(key string) error {
+	delay, ok := c.executor.delays[key]
+	if !ok {
+		return nil
+	}
+	if c.ctx == nil {
+		time.Sleep(delay.duration)
+		return nil
+	}
+	if delay.interruptible {
+		select {
+		case <-c.ctx.Done():
+			return c.ctx.Err()
+		case <-time.After(delay.duration):
+		}
+		return nil
+	}
+	time.Sleep(delay.duration)
+	return c.ctx.Err()
+}
+
 // commandKey generates a unique key for the command
 func (c *mockCommand) commandKey() string {
 	parts := []string{c.name}
@@ -0,0 +1,78 @@
+package executor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMockExecutor_SetDelay(t *testing.T) {
+	mockExec := &MockExecutor{
+		Results: map[string]MockResult{
+			"go build": {Output: []byte("ok")},
+			"go vet":   {Output: []byte("ok")},
+		},
+	}
+	mockExec.SetDelay("go build", 50*time.Millisecond)
+
+	start := time.Now()
+	output, err := mockExec.Command("go", "build").CombinedOutput()
+	require.NoError(t, err)
+	assert.Equal(t, "ok", string(output))
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+
+	// Other commands are not delayed
+	start = time.Now()
+	_, err = mockExec.Command("go", "vet").Output()
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), 50*time.Millisecond)
+
+	// The command is recorded before it finishes
+	assert.Len(t, mockExec.Commands, 2)
+}
+
+func TestMockExecutor_SetDelay_ContextDone(t *testing.T) {
+	mockExec := &MockExecutor{
+		Results: map[string]MockResult{
+			"sleep 5": {Output: []byte("done\n")},
+		},
+	}
+	mockExec.SetDelay("sleep 5", 100*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	// The delay runs to the end, but the command reports the context's error
+	start := time.Now()
+	output, err := mockExec.CommandContext(ctx, "sleep", "5").Output()
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Nil(t, output)
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+}
+
+func TestMockExecutor_SetContextDelay(t *testing.T) {
+	mockExec := &MockExecutor{
+		Results: map[string]MockResult{
+			"sleep 5": {Output: []byte("done\n")},
+		},
+	}
+	mockExec.SetContextDelay("sleep 5", 2*time.Second)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	output, err := mockExec.CommandContext(ctx, "sleep", "5").Output()
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Nil(t, output)
+	assert.Less(t, time.Since(start), 1*time.Second)
+
+	// Without a context the full delay applies
+	mockExec.SetContextDelay("sleep 5", 20*time.Millisecond)
+	output, err = mockExec.Command("sleep", "5").Output()
+	require.NoError(t, err)
+	assert.Equal(t, "done\n", string(output))
+}
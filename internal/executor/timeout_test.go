@@ -74,76 +74,13 @@ func TestTimeoutExecutor_CombinedOutput(t *testing.T) {
 	assert.Equal(t, "hello\n", string(output))
 }
 
-// SlowMockExecutor simulates slow command execution
-type SlowMockExecutor struct {
-	*MockExecutor
-	delay time.Duration
-}
-
-func (s *SlowMockExecutor) Command(name string, args ...string) Command {
-	return &slowMockCommand{
-		mockCommand: s.MockExecutor.Command(name, args...).(*mockCommand),
-		delay:       s.delay,
-	}
-}
-
-func (s *SlowMockExecutor) CommandContext(ctx context.Context, name string, args ...string) Command {
-	return &slowMockCommand{
-		mockCommand: s.MockExecutor.CommandContext(ctx, name, args...).(*mockCommand),
-		delay:       s.delay,
-		ctx:         ctx,
-	}
-}
-
-type slowMockCommand struct {
-	*mockCommand
-	delay time.Duration
-	ctx   context.Context
-}
-
-func (s *slowMockCommand) Output() ([]byte, error) {
-	// Simulate slow execution
-	if s.ctx != nil {
-		// Respect context cancellation
-		select {
-		case <-s.ctx.Done():
-			return nil, s.ctx.Err()
-		case <-time.After(s.delay):
-			// Continue to normal execution
-		}
-	} else {
-		time.Sleep(s.delay)
-	}
-
-	return s.mockCommand.Output()
-}
-
-func (s *slowMockCommand) CombinedOutput() ([]byte, error) {
-	// Simulate slow execution
-	if s.ctx != nil {
-		// Respect context cancellation
-		select {
-		case <-s.ctx.Done():
-			return nil, s.ctx.Err()
-		case <-time.After(s.delay):
-			// Continue to normal execution
-		}
-	} else {
-		time.Sleep(s.delay)
-	}
-
-	return s.mockCommand.CombinedOutput()
-}
-
 func TestTimeoutExecutor_Command_Timeout(t *testing.T) {
-	mockExec := &SlowMockExecutor{
-		MockExecutor: &MockExecutor{
-			Results: map[string]MockResult{
-				"sleep 5": {Output: []byte("done\n"), Error: nil},
-			},
+	mockExec := &MockExecutor{
+		Results: map[string]MockResult{
+			"sleep 5": {Output: []byte("done\n"), Error: nil},
 		},
-		delay: 2 * time.Second, // Simulate 2 second delay
 	}
+	mockExec.SetContextDelay("sleep 5", 2*time.Second) // Simulate 2 second delay
 
 	// Create timeout executor with 500ms timeout
 	timeoutExec := NewTimeoutExecutor(mockExec, 500*time.Millisecond)
@@ -161,14 +98,12 @@ func TestTimeoutExecutor_Command_Timeout(t *testing.T) {
 }
 
 func TestTimeoutExecutor_CombinedOutput_Timeout(t *testing.T) {
-	mockExec := &SlowMockExecutor{
-		MockExecutor: &MockExecutor{
-			Results: map[string]MockResult{
-				"sleep 5": {Output: []byte("done\n"), Error: nil},
-			},
+	mockExec := &MockExecutor{
+		Results: map[string]MockResult{
+			"sleep 5": {Output: []byte("done\n"), Error: nil},
 		},
-		delay: 2 * time.Second, // Simulate 2 second delay
 	}
+	mockExec.SetContextDelay("sleep 5", 2*time.Second) // Simulate 2 second delay
 
 	// Create timeout executor with 500ms timeout
 	timeoutExec := NewTimeoutExecutor(mockExec, 500*time.Millisecond)
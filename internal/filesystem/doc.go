@@ -40,6 +40,24 @@
 //   - Remove: Delete files or directories
 //   - Walk: Traverse directory trees
 //   - Stat: Get file information
+//   - CopyFile: Copy a file's contents to another path
+//
+// # Watching Files
+//
+// A Watcher reports changes to files, such as the sources of a CLI being
+// edited. FSNotifyWatcher receives the changes from the operating system
+// through fsnotify, PollingWatcher checks the files at an interval, and
+// MockWatcher sends pre-fed events for tests:
+//
+//	watcher := filesystem.NewFSNotifyWatcher()
+//	events, err := watcher.Watch([]string{"cmd"})
+//	if err != nil {
+//	    return err
+//	}
+//	defer watcher.Close()
+//	for event := range events {
+//	    fmt.Println(event) // WRITE "cmd/root.go"
+//	}
 //
 // # Path Handling
 //
@@ -73,4 +91,13 @@
 //   - Respects file permissions
 //   - Provides safe defaults for file creation
 //   - Handles temporary files securely
+//
+// When paths come from untrusted input, use SecureFileSystem to confine all
+// operations to a root directory:
+//
+//	fs, err := filesystem.NewSecureFileSystem("/srv/contracts")
+//	if err != nil {
+//	    return err
+//	}
+//	_, err = fs.ReadFile("../../etc/passwd") // returns PathTraversalError
 package filesystem
@@ -1,6 +1,7 @@
 package filesystem
 
 import (
+	"io"
 	"os"
 )
 
@@ -11,6 +12,7 @@ type FileSystem interface {
 	WriteFile(name string, data []byte, perm os.FileMode) error
 	ReadFile(name string) ([]byte, error)
 	Stat(name string) (os.FileInfo, error)
+	CopyFile(src, dst string) error
 }
 
 // OSFileSystem is the real implementation using os package
@@ -40,3 +42,27 @@ func (fs *OSFileSystem) ReadFile(name string) ([]byte, error) {
 func (fs *OSFileSystem) Stat(name string) (os.FileInfo, error) {
 	return os.Stat(name)
 }
+
+// CopyFile copies the contents of src to dst, creating dst with the
+// permissions of src or truncating it if it exists
+func (fs *OSFileSystem) CopyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,183 @@
+package filesystem
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// FSNotifyWatcher is a Watcher that receives changes from the operating
+// system through fsnotify, so they are reported as they happen instead of
+// at the next poll. Like a PollingWatcher, it watches directories with all
+// the files below them, except in hidden directories such as .git, and
+// directories created while watching are watched too. A watched file is
+// watched through its directory, so it still is after an editor replaces
+// it. A single change can be reported as several events, e.g. the Create of
+// a file and the Write of its contents. Errors fsnotify reports while
+// watching, such as an overflow of its event queue, are dropped.
+type FSNotifyWatcher struct {
+	mu       sync.Mutex
+	watcher  *fsnotify.Watcher
+	dirs     map[string]bool // directories watched with all their files
+	files    map[string]bool // files watched through their directory
+	watching bool
+	closed   bool
+	done     chan struct{}
+	stopped  chan struct{}
+}
+
+// NewFSNotifyWatcher creates a watcher using fsnotify. The fsnotify watcher
+// is only created by Watch, which returns its error.
+func NewFSNotifyWatcher() *FSNotifyWatcher {
+	return &FSNotifyWatcher{}
+}
+
+// Watch starts watching the paths, which must exist. A watcher watches one
+// set of paths; create another to watch more.
+func (w *FSNotifyWatcher) Watch(paths []string) (<-chan Event, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	switch {
+	case w.closed:
+		return nil, fmt.Errorf("watcher is closed")
+	case w.watching:
+		return nil, fmt.Errorf("watcher is already watching")
+	}
+
+	for _, path := range paths {
+		if _, err := os.Stat(path); err != nil {
+			return nil, fmt.Errorf("cannot watch %s: %w", path, err)
+		}
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file watcher: %w", err)
+	}
+	w.watcher = watcher
+	w.dirs = make(map[string]bool)
+	w.files = make(map[string]bool)
+	for _, path := range paths {
+		if _, err := w.add(filepath.Clean(path)); err != nil {
+			watcher.Close()
+			return nil, fmt.Errorf("cannot watch %s: %w", path, err)
+		}
+	}
+
+	w.watching = true
+	w.done = make(chan struct{})
+	w.stopped = make(chan struct{})
+	events := make(chan Event)
+	go func() {
+		defer close(w.stopped)
+		defer close(events)
+		for {
+			var received []Event
+			select {
+			case <-w.done:
+				return
+			case _, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				continue
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				received = w.translate(event)
+			}
+			for _, event := range received {
+				select {
+				case events <- event:
+				case <-w.done:
+					return
+				}
+			}
+		}
+	}()
+	return events, nil
+}
+
+// translate returns the events to report for an fsnotify event. A created
+// directory is watched, and instead of itself the files already in it are
+// reported as created, since they could have been written before it was.
+func (w *FSNotifyWatcher) translate(event fsnotify.Event) []Event {
+	if !w.dirs[filepath.Dir(event.Name)] && !w.files[event.Name] {
+		// Another file in the directory of a watched file
+		return nil
+	}
+	if event.Has(fsnotify.Create) {
+		if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+			if strings.HasPrefix(info.Name(), ".") {
+				return nil
+			}
+			files, _ := w.add(event.Name)
+			events := make([]Event, 0, len(files))
+			for _, file := range files {
+				events = append(events, Event{Path: file, Op: Create})
+			}
+			return events
+		}
+	}
+
+	var op Op
+	for _, o := range []struct {
+		fsnotify fsnotify.Op
+		op       Op
+	}{{fsnotify.Create, Create}, {fsnotify.Write, Write}, {fsnotify.Remove, Remove}, {fsnotify.Rename, Rename}, {fsnotify.Chmod, Chmod}} {
+		if event.Has(o.fsnotify) {
+			op |= o.op
+		}
+	}
+	if op == 0 {
+		return nil
+	}
+	return []Event{{Path: event.Name, Op: op}}
+}
+
+// Close stops watching and closes the event channel
+func (w *FSNotifyWatcher) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	if !w.watching {
+		return nil
+	}
+	close(w.done)
+	<-w.stopped
+	return w.watcher.Close()
+}
+
+// add watches root: the directory of a file, or a directory and every
+// directory below it except hidden ones. It returns the files below a
+// directory.
+func (w *FSNotifyWatcher) add(root string) ([]string, error) {
+	var files []string
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			// Removed while walking
+			return nil
+		}
+		if !info.IsDir() {
+			if path == root {
+				w.files[path] = true
+				return w.watcher.Add(filepath.Dir(path))
+			}
+			files = append(files, path)
+			return nil
+		}
+		if path != root && strings.HasPrefix(info.Name(), ".") {
+			return filepath.SkipDir
+		}
+		w.dirs[path] = true
+		return w.watcher.Add(path)
+	})
+	return files, err
+}
@@ -0,0 +1,136 @@
+package filesystem
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// waitForEventAfter reads events until one for path includes op. fsnotify
+// can report a change in several events, e.g. the Write after the Create of
+// a file, so events for path and for the paths in earlier are skipped;
+// events for other paths fail the test.
+func waitForEventAfter(t *testing.T, events <-chan Event, path string, op Op, earlier ...string) {
+	t.Helper()
+	for {
+		event := nextEvent(t, events)
+		if event.Path == path && event.Op.Has(op) {
+			return
+		}
+		skipped := event.Path == path
+		for _, p := range earlier {
+			skipped = skipped || event.Path == p
+		}
+		if !skipped {
+			t.Fatalf("unexpected event %s while waiting for %s %q", event, op, path)
+		}
+	}
+}
+
+func TestFSNotifyWatcher(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "root.go")
+	if err := os.WriteFile(existing, []byte("package cmd\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	watcher := NewFSNotifyWatcher()
+	events, err := watcher.Watch([]string{dir})
+	if err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+	defer watcher.Close()
+
+	// Files in hidden directories are not watched
+	if err := os.WriteFile(filepath.Join(dir, ".git", "HEAD"), []byte("ref\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	created := filepath.Join(dir, "serve.go")
+	if err := os.WriteFile(created, []byte("package cmd\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	waitForEventAfter(t, events, created, Create)
+
+	if err := os.WriteFile(existing, []byte("package cmd\n\nfunc Execute() {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	waitForEventAfter(t, events, existing, Write, created)
+
+	if err := os.Chmod(existing, 0600); err != nil {
+		t.Fatal(err)
+	}
+	waitForEventAfter(t, events, existing, Chmod)
+
+	if err := os.Remove(created); err != nil {
+		t.Fatal(err)
+	}
+	waitForEventAfter(t, events, created, Remove, existing)
+
+	// Directories created while watching are watched too
+	sub := filepath.Join(dir, "sub")
+	if err := os.Mkdir(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+	nested := filepath.Join(sub, "db.go")
+	if err := os.WriteFile(nested, []byte("package sub\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	waitForEventAfter(t, events, nested, Create)
+
+	if err := watcher.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if _, ok := <-events; ok {
+		t.Error("event channel still open after Close()")
+	}
+	if _, err := watcher.Watch([]string{dir}); err == nil {
+		t.Error("Watch() after Close() succeeded")
+	}
+}
+
+func TestFSNotifyWatcher_File(t *testing.T) {
+	dir := t.TempDir()
+	contract := filepath.Join(dir, "cliguard.yaml")
+	if err := os.WriteFile(contract, []byte("use: myapp\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	watcher := NewFSNotifyWatcher()
+	events, err := watcher.Watch([]string{contract})
+	if err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+	defer watcher.Close()
+
+	// Other files in the directory are not watched
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("todo\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// Editors often save by renaming a new file over the old one
+	replacement := filepath.Join(dir, "notes.txt")
+	if err := os.WriteFile(replacement, []byte("use: myapp\nshort: My app\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(replacement, contract); err != nil {
+		t.Fatal(err)
+	}
+	waitForEventAfter(t, events, contract, Create)
+
+	if err := os.WriteFile(contract, []byte("use: myapp\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	waitForEventAfter(t, events, contract, Write)
+}
+
+func TestFSNotifyWatcher_MissingPath(t *testing.T) {
+	watcher := NewFSNotifyWatcher()
+	defer watcher.Close()
+	_, err := watcher.Watch([]string{filepath.Join(t.TempDir(), "missing")})
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Watch() error = %v, want not exist", err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"time"
 )
 
@@ -88,6 +89,16 @@ func (fs *MockFileSystem) Stat(name string) (os.FileInfo, error) {
 	return nil, os.ErrNotExist
 }
 
+// CopyFile copies a mock file. Like WriteFile, the parent directory of dst
+// must exist.
+func (fs *MockFileSystem) CopyFile(src, dst string) error {
+	data, err := fs.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	return fs.WriteFile(dst, append([]byte(nil), data...), 0644)
+}
+
 // directoryExists checks if a directory exists in the mock filesystem
 func (fs *MockFileSystem) directoryExists(path string) bool {
 	if path == "/" || path == "." {
@@ -127,3 +138,72 @@ func (m *mockFileInfo) Mode() os.FileMode  { return 0644 }
 func (m *mockFileInfo) ModTime() time.Time { return time.Now() }
 func (m *mockFileInfo) IsDir() bool        { return m.isDir }
 func (m *mockFileInfo) Sys() interface{}   { return nil }
+
+// MockWatcher is a Watcher for testing. Watch sends the pre-fed Events in
+// order, and Send simulates further changes.
+type MockWatcher struct {
+	Events   []Event
+	WatchErr error // Error to return from Watch
+
+	// Paths are the paths given to Watch
+	Paths []string
+
+	mu     sync.Mutex
+	events chan Event
+	closed bool
+}
+
+// mockWatcherBuffer is how many events a MockWatcher holds beyond the
+// pre-fed ones before Send blocks
+const mockWatcherBuffer = 16
+
+// NewMockWatcher creates a mock watcher that sends events once watching
+func NewMockWatcher(events ...Event) *MockWatcher {
+	return &MockWatcher{Events: events}
+}
+
+// Watch records the paths and returns a channel holding the pre-fed events
+func (w *MockWatcher) Watch(paths []string) (<-chan Event, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.WatchErr != nil {
+		return nil, w.WatchErr
+	}
+	if w.events != nil {
+		return nil, fmt.Errorf("watcher is already watching")
+	}
+	w.Paths = paths
+	w.events = make(chan Event, len(w.Events)+mockWatcherBuffer)
+	for _, event := range w.Events {
+		w.events <- event
+	}
+	if w.closed {
+		close(w.events)
+	}
+	return w.events, nil
+}
+
+// Send sends an event, as if the file changed. It does nothing before Watch
+// or after Close.
+func (w *MockWatcher) Send(event Event) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.events == nil || w.closed {
+		return
+	}
+	w.events <- event
+}
+
+// Close closes the event channel
+func (w *MockWatcher) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	if w.events != nil {
+		close(w.events)
+	}
+	return nil
+}
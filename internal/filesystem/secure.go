@@ -0,0 +1,208 @@
+package filesystem
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// PathTraversalError indicates a path resolves outside the allowed root directory
+type PathTraversalError struct {
+	Path    string
+	RootDir string
+}
+
+func (e PathTraversalError) Error() string {
+	return fmt.Sprintf("path '%s' is outside the allowed directory '%s'", e.Path, e.RootDir)
+}
+
+// SecureFileSystem wraps OSFileSystem and rejects any path that resolves
+// outside its root directory, either lexically (via ".." components or an
+// absolute path) or through a symbolic link.
+//
+// Relative paths are resolved against the root directory rather than the
+// process working directory. This makes SecureFileSystem suitable when
+// cliguard is embedded in a service that accepts user-provided paths.
+type SecureFileSystem struct {
+	rootDir string
+	fs      *OSFileSystem
+}
+
+// NewSecureFileSystem creates a SecureFileSystem rooted at rootDir.
+// The root directory must exist; symbolic links in it are resolved so that
+// later checks compare real locations.
+func NewSecureFileSystem(rootDir string) (*SecureFileSystem, error) {
+	absRoot, err := filepath.Abs(rootDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve root directory '%s': %w", rootDir, err)
+	}
+
+	realRoot, err := filepath.EvalSymlinks(absRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve root directory '%s': %w", rootDir, err)
+	}
+
+	info, err := os.Stat(realRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat root directory '%s': %w", rootDir, err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("root '%s' is not a directory", rootDir)
+	}
+
+	return &SecureFileSystem{rootDir: realRoot, fs: &OSFileSystem{}}, nil
+}
+
+// RootDir returns the resolved root directory
+func (s *SecureFileSystem) RootDir() string {
+	return s.rootDir
+}
+
+// MkdirTemp creates a temporary directory inside the root directory.
+// An empty dir creates it directly under the root instead of os.TempDir.
+func (s *SecureFileSystem) MkdirTemp(dir, pattern string) (string, error) {
+	if dir == "" {
+		dir = s.rootDir
+	}
+	resolved, err := s.resolve(dir)
+	if err != nil {
+		return "", err
+	}
+	return s.fs.MkdirTemp(resolved, pattern)
+}
+
+// RemoveAll removes a path inside the root directory and any children it contains.
+// Removing the root directory itself is not allowed.
+func (s *SecureFileSystem) RemoveAll(path string) error {
+	resolved, err := s.resolve(path)
+	if err != nil {
+		return err
+	}
+	if resolved == s.rootDir {
+		return PathTraversalError{Path: path, RootDir: s.rootDir}
+	}
+	return s.fs.RemoveAll(resolved)
+}
+
+// WriteFile writes data to a file inside the root directory
+func (s *SecureFileSystem) WriteFile(name string, data []byte, perm os.FileMode) error {
+	resolved, err := s.resolve(name)
+	if err != nil {
+		return err
+	}
+	return s.fs.WriteFile(resolved, data, perm)
+}
+
+// ReadFile reads the contents of a file inside the root directory
+func (s *SecureFileSystem) ReadFile(name string) ([]byte, error) {
+	resolved, err := s.resolve(name)
+	if err != nil {
+		return nil, err
+	}
+	return s.fs.ReadFile(resolved)
+}
+
+// Stat returns file info for a path inside the root directory
+func (s *SecureFileSystem) Stat(name string) (os.FileInfo, error) {
+	resolved, err := s.resolve(name)
+	if err != nil {
+		return nil, err
+	}
+	return s.fs.Stat(resolved)
+}
+
+// CopyFile copies a file inside the root directory to another path inside it
+func (s *SecureFileSystem) CopyFile(src, dst string) error {
+	resolvedSrc, err := s.resolve(src)
+	if err != nil {
+		return err
+	}
+	resolvedDst, err := s.resolve(dst)
+	if err != nil {
+		return err
+	}
+	return s.fs.CopyFile(resolvedSrc, resolvedDst)
+}
+
+// resolve returns the absolute, symlink-free form of name, or a
+// PathTraversalError if it lies outside the root directory
+func (s *SecureFileSystem) resolve(name string) (string, error) {
+	path := name
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(s.rootDir, path)
+	}
+	path = filepath.Clean(path)
+
+	// Lexical check first so that ".." components are rejected even when
+	// the target does not exist
+	if !s.contains(path) {
+		return "", PathTraversalError{Path: name, RootDir: s.rootDir}
+	}
+
+	real, err := evalExistingSymlinks(path)
+	if err != nil {
+		return "", err
+	}
+	if !s.contains(real) {
+		return "", PathTraversalError{Path: name, RootDir: s.rootDir}
+	}
+
+	return real, nil
+}
+
+// contains reports whether the clean absolute path is the root directory or below it
+func (s *SecureFileSystem) contains(path string) bool {
+	rel, err := filepath.Rel(s.rootDir, path)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// maxSymlinkHops bounds how many dangling symlinks evalExistingSymlinks follows
+const maxSymlinkHops = 255
+
+// evalExistingSymlinks resolves symbolic links in the longest existing
+// prefix of path and appends the remaining, not-yet-existing components.
+// This lets files that are about to be created be checked as well.
+// Dangling symlinks are followed to their target, since writing through
+// them would create the target.
+func evalExistingSymlinks(path string) (string, error) {
+	var missing []string
+	current := path
+	hops := 0
+	for {
+		real, err := filepath.EvalSymlinks(current)
+		if err == nil {
+			parts := append([]string{real}, missing...)
+			return filepath.Join(parts...), nil
+		}
+		if !os.IsNotExist(err) {
+			return "", err
+		}
+
+		if info, lerr := os.Lstat(current); lerr == nil && info.Mode()&os.ModeSymlink != 0 {
+			hops++
+			if hops > maxSymlinkHops {
+				return "", fmt.Errorf("too many symbolic links resolving '%s'", path)
+			}
+			target, err := os.Readlink(current)
+			if err != nil {
+				return "", err
+			}
+			if !filepath.IsAbs(target) {
+				target = filepath.Join(filepath.Dir(current), target)
+			}
+			current = filepath.Clean(target)
+			continue
+		}
+
+		parent := filepath.Dir(current)
+		if parent == current {
+			return path, nil
+		}
+		missing = append([]string{filepath.Base(current)}, missing...)
+		current = parent
+	}
+}
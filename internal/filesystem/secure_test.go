@@ -0,0 +1,353 @@
+package filesystem
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var _ FileSystem = (*SecureFileSystem)(nil)
+
+// setupSecureRoot creates a root directory with some content and a sibling
+// directory outside of it that the tests try to reach
+func setupSecureRoot(t *testing.T) (root, outside string) {
+	t.Helper()
+
+	base := t.TempDir()
+	root = filepath.Join(base, "root")
+	outside = filepath.Join(base, "outside")
+
+	for _, dir := range []string{root, filepath.Join(root, "sub"), outside} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", dir, err)
+		}
+	}
+
+	files := map[string]string{
+		filepath.Join(root, "cliguard.yaml"):   "use: root",
+		filepath.Join(root, "sub", "nested"):   "nested",
+		filepath.Join(root, "..hidden"):        "dots in name",
+		filepath.Join(outside, "secret"):       "secret",
+		filepath.Join(base, "root-sibling.go"): "sibling",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", path, err)
+		}
+	}
+
+	return root, outside
+}
+
+func symlinkOrSkip(t *testing.T, target, link string) {
+	t.Helper()
+	if err := os.Symlink(target, link); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+}
+
+func TestNewSecureFileSystem(t *testing.T) {
+	root, _ := setupSecureRoot(t)
+
+	t.Run("existing directory", func(t *testing.T) {
+		fs, err := NewSecureFileSystem(root)
+		if err != nil {
+			t.Fatalf("NewSecureFileSystem() error = %v", err)
+		}
+		if !filepath.IsAbs(fs.RootDir()) {
+			t.Errorf("RootDir() = %q, want absolute path", fs.RootDir())
+		}
+	})
+
+	t.Run("missing directory", func(t *testing.T) {
+		if _, err := NewSecureFileSystem(filepath.Join(root, "missing")); err == nil {
+			t.Error("NewSecureFileSystem() expected error for missing directory")
+		}
+	})
+
+	t.Run("file instead of directory", func(t *testing.T) {
+		if _, err := NewSecureFileSystem(filepath.Join(root, "cliguard.yaml")); err == nil {
+			t.Error("NewSecureFileSystem() expected error for regular file")
+		}
+	})
+
+	t.Run("root behind symlink", func(t *testing.T) {
+		link := filepath.Join(filepath.Dir(root), "root-link")
+		symlinkOrSkip(t, root, link)
+
+		fs, err := NewSecureFileSystem(link)
+		if err != nil {
+			t.Fatalf("NewSecureFileSystem() error = %v", err)
+		}
+		data, err := fs.ReadFile("cliguard.yaml")
+		if err != nil {
+			t.Fatalf("ReadFile() error = %v", err)
+		}
+		if string(data) != "use: root" {
+			t.Errorf("ReadFile() = %q, want %q", data, "use: root")
+		}
+	})
+}
+
+func TestSecureFileSystem_ReadFile(t *testing.T) {
+	root, outside := setupSecureRoot(t)
+
+	// Symlinks used by the test cases
+	symlinkOrSkip(t, outside, filepath.Join(root, "escape-dir"))
+	symlinkOrSkip(t, filepath.Join(outside, "secret"), filepath.Join(root, "escape-file"))
+	symlinkOrSkip(t, "../outside/secret", filepath.Join(root, "escape-relative"))
+	symlinkOrSkip(t, filepath.Join(root, "sub", "nested"), filepath.Join(root, "inside-link"))
+	symlinkOrSkip(t, "nested", filepath.Join(root, "sub", "inside-relative"))
+	symlinkOrSkip(t, filepath.Join(root, "escape-file"), filepath.Join(root, "chained-escape"))
+
+	tests := []struct {
+		name          string
+		path          string
+		want          string
+		wantTraversal bool
+		wantNotExist  bool
+	}{
+		{name: "relative file", path: "cliguard.yaml", want: "use: root"},
+		{name: "absolute file inside root", path: filepath.Join(root, "cliguard.yaml"), want: "use: root"},
+		{name: "nested file", path: "sub/nested", want: "nested"},
+		{name: "dot components", path: "./sub/./nested", want: "nested"},
+		{name: "parent component staying inside", path: "sub/../cliguard.yaml", want: "use: root"},
+		{name: "file name starting with dots", path: "..hidden", want: "dots in name"},
+		{name: "symlink inside root", path: "inside-link", want: "nested"},
+		{name: "relative symlink inside root", path: "sub/inside-relative", want: "nested"},
+		{name: "missing file inside root", path: "missing.yaml", wantNotExist: true},
+
+		{name: "parent directory", path: "..", wantTraversal: true},
+		{name: "parent traversal", path: "../outside/secret", wantTraversal: true},
+		{name: "deep parent traversal", path: "../../../../../../etc/passwd", wantTraversal: true},
+		{name: "traversal after subdirectory", path: "sub/../../outside/secret", wantTraversal: true},
+		{name: "traversal to missing file", path: "../does-not-exist", wantTraversal: true},
+		{name: "absolute path outside root", path: filepath.Join(outside, "secret"), wantTraversal: true},
+		{name: "absolute system path", path: "/etc/passwd", wantTraversal: true},
+		{name: "sibling with root as prefix", path: root + "-sibling.go", wantTraversal: true},
+		{name: "symlinked directory escaping root", path: "escape-dir/secret", wantTraversal: true},
+		{name: "symlinked file escaping root", path: "escape-file", wantTraversal: true},
+		{name: "relative symlink escaping root", path: "escape-relative", wantTraversal: true},
+		{name: "chained symlinks escaping root", path: "chained-escape", wantTraversal: true},
+		{name: "missing file behind escaping symlink", path: "escape-dir/missing", wantTraversal: true},
+	}
+
+	fs, err := NewSecureFileSystem(root)
+	if err != nil {
+		t.Fatalf("NewSecureFileSystem() error = %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := fs.ReadFile(tt.path)
+
+			var traversal PathTraversalError
+			isTraversal := errors.As(err, &traversal)
+			if isTraversal != tt.wantTraversal {
+				t.Fatalf("ReadFile(%q) error = %v, wantTraversal %v", tt.path, err, tt.wantTraversal)
+			}
+			if tt.wantTraversal {
+				if traversal.Path != tt.path {
+					t.Errorf("PathTraversalError.Path = %q, want %q", traversal.Path, tt.path)
+				}
+				return
+			}
+
+			if tt.wantNotExist {
+				if !os.IsNotExist(err) {
+					t.Errorf("ReadFile(%q) error = %v, want not-exist error", tt.path, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ReadFile(%q) unexpected error = %v", tt.path, err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("ReadFile(%q) = %q, want %q", tt.path, data, tt.want)
+			}
+		})
+	}
+}
+
+func TestSecureFileSystem_WriteFile(t *testing.T) {
+	root, outside := setupSecureRoot(t)
+	symlinkOrSkip(t, outside, filepath.Join(root, "escape-dir"))
+	symlinkOrSkip(t, filepath.Join(outside, "created-through-link"), filepath.Join(root, "dangling"))
+	symlinkOrSkip(t, filepath.Join(root, "sub", "created-inside"), filepath.Join(root, "dangling-inside"))
+
+	fs, err := NewSecureFileSystem(root)
+	if err != nil {
+		t.Fatalf("NewSecureFileSystem() error = %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		path          string
+		wantTraversal bool
+		checkPath     string
+	}{
+		{name: "new file inside root", path: "new.yaml", checkPath: filepath.Join(root, "new.yaml")},
+		{name: "new file in subdirectory", path: "sub/new.yaml", checkPath: filepath.Join(root, "sub", "new.yaml")},
+		{name: "dangling symlink inside root", path: "dangling-inside", checkPath: filepath.Join(root, "sub", "created-inside")},
+		{name: "parent traversal", path: "../outside/written", wantTraversal: true, checkPath: filepath.Join(outside, "written")},
+		{name: "absolute path outside root", path: filepath.Join(outside, "abs"), wantTraversal: true, checkPath: filepath.Join(outside, "abs")},
+		{name: "through symlinked directory", path: "escape-dir/via-link", wantTraversal: true, checkPath: filepath.Join(outside, "via-link")},
+		{name: "through dangling symlink", path: "dangling", wantTraversal: true, checkPath: filepath.Join(outside, "created-through-link")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := fs.WriteFile(tt.path, []byte("data"), 0644)
+
+			var traversal PathTraversalError
+			if errors.As(err, &traversal) != tt.wantTraversal {
+				t.Fatalf("WriteFile(%q) error = %v, wantTraversal %v", tt.path, err, tt.wantTraversal)
+			}
+
+			_, statErr := os.Stat(tt.checkPath)
+			if tt.wantTraversal {
+				if statErr == nil {
+					t.Errorf("WriteFile(%q) created %s outside the root", tt.path, tt.checkPath)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("WriteFile(%q) unexpected error = %v", tt.path, err)
+			}
+			if statErr != nil {
+				t.Errorf("WriteFile(%q) did not create %s: %v", tt.path, tt.checkPath, statErr)
+			}
+		})
+	}
+}
+
+func TestSecureFileSystem_CopyFile(t *testing.T) {
+	root, outside := setupSecureRoot(t)
+	fs, err := NewSecureFileSystem(root)
+	if err != nil {
+		t.Fatalf("NewSecureFileSystem() error = %v", err)
+	}
+
+	if err := fs.CopyFile("cliguard.yaml", "sub/copy.yaml"); err != nil {
+		t.Fatalf("CopyFile() error = %v", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(root, "sub", "copy.yaml")); err != nil || string(data) != "use: root" {
+		t.Errorf("copied file = %q, %v; want the contents of cliguard.yaml", data, err)
+	}
+
+	var traversal PathTraversalError
+	if err := fs.CopyFile("cliguard.yaml", "../outside/copy.yaml"); !errors.As(err, &traversal) {
+		t.Errorf("CopyFile() to outside the root error = %v, want PathTraversalError", err)
+	}
+	if _, err := os.Stat(filepath.Join(outside, "copy.yaml")); err == nil {
+		t.Error("CopyFile() created a file outside the root")
+	}
+	if err := fs.CopyFile("../outside/secret", "stolen"); !errors.As(err, &traversal) {
+		t.Errorf("CopyFile() from outside the root error = %v, want PathTraversalError", err)
+	}
+}
+
+func TestOSFileSystem_CopyFile(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "go.sum")
+	if err := os.WriteFile(src, []byte("github.com/spf13/cobra v1.9.1 h1:abc=\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	dst := filepath.Join(dir, "copy")
+	if err := os.WriteFile(dst, []byte("longer content to be truncated away"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	fs := &OSFileSystem{}
+	if err := fs.CopyFile(src, dst); err != nil {
+		t.Fatalf("CopyFile() error = %v", err)
+	}
+	data, err := os.ReadFile(dst)
+	if err != nil || string(data) != "github.com/spf13/cobra v1.9.1 h1:abc=\n" {
+		t.Errorf("copied file = %q, %v; want the source contents", data, err)
+	}
+
+	fresh := filepath.Join(dir, "fresh")
+	if err := fs.CopyFile(src, fresh); err != nil {
+		t.Fatalf("CopyFile() error = %v", err)
+	}
+	if info, err := os.Stat(fresh); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("new file mode = %v, %v; want the source's 0600", info.Mode().Perm(), err)
+	}
+
+	if err := fs.CopyFile(filepath.Join(dir, "missing"), dst); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("CopyFile() of a missing file error = %v, want os.ErrNotExist", err)
+	}
+}
+
+func TestSecureFileSystem_StatAndRemoveAll(t *testing.T) {
+	root, outside := setupSecureRoot(t)
+
+	fs, err := NewSecureFileSystem(root)
+	if err != nil {
+		t.Fatalf("NewSecureFileSystem() error = %v", err)
+	}
+
+	if _, err := fs.Stat("sub"); err != nil {
+		t.Errorf("Stat(sub) error = %v", err)
+	}
+
+	var traversal PathTraversalError
+	if _, err := fs.Stat("../outside"); !errors.As(err, &traversal) {
+		t.Errorf("Stat(../outside) error = %v, want PathTraversalError", err)
+	}
+
+	if err := fs.RemoveAll("../outside"); !errors.As(err, &traversal) {
+		t.Errorf("RemoveAll(../outside) error = %v, want PathTraversalError", err)
+	}
+	if _, err := os.Stat(outside); err != nil {
+		t.Errorf("RemoveAll removed directory outside root: %v", err)
+	}
+
+	for _, path := range []string{"", ".", root, "sub/.."} {
+		if err := fs.RemoveAll(path); !errors.As(err, &traversal) {
+			t.Errorf("RemoveAll(%q) error = %v, want PathTraversalError", path, err)
+		}
+	}
+
+	if err := fs.RemoveAll("sub"); err != nil {
+		t.Errorf("RemoveAll(sub) error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "sub")); !os.IsNotExist(err) {
+		t.Errorf("RemoveAll(sub) did not remove directory")
+	}
+}
+
+func TestSecureFileSystem_MkdirTemp(t *testing.T) {
+	root, _ := setupSecureRoot(t)
+
+	fs, err := NewSecureFileSystem(root)
+	if err != nil {
+		t.Fatalf("NewSecureFileSystem() error = %v", err)
+	}
+
+	for _, dir := range []string{"", "sub"} {
+		tempDir, err := fs.MkdirTemp(dir, "cliguard-")
+		if err != nil {
+			t.Fatalf("MkdirTemp(%q) error = %v", dir, err)
+		}
+		if !fs.contains(tempDir) {
+			t.Errorf("MkdirTemp(%q) = %q, want path inside %q", dir, tempDir, fs.RootDir())
+		}
+	}
+
+	var traversal PathTraversalError
+	if _, err := fs.MkdirTemp(os.TempDir(), "cliguard-"); !errors.As(err, &traversal) {
+		t.Errorf("MkdirTemp(os.TempDir()) error = %v, want PathTraversalError", err)
+	}
+}
+
+func TestPathTraversalError_Error(t *testing.T) {
+	err := PathTraversalError{Path: "../etc/passwd", RootDir: "/srv/app"}
+	want := "path '../etc/passwd' is outside the allowed directory '/srv/app'"
+	if err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
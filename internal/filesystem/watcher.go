@@ -0,0 +1,214 @@
+package filesystem
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultPollInterval is how often a PollingWatcher checks for changes
+const DefaultPollInterval = 500 * time.Millisecond
+
+// Op is a kind of file system change. An event can combine several, e.g.
+// Write|Chmod.
+type Op uint32
+
+// The changes an Event can report
+const (
+	Create Op = 1 << iota
+	Write
+	Remove
+	Rename
+	Chmod
+)
+
+// String returns the names of the changes, e.g. "WRITE|CHMOD"
+func (op Op) String() string {
+	var names []string
+	for _, o := range []struct {
+		op   Op
+		name string
+	}{{Create, "CREATE"}, {Write, "WRITE"}, {Remove, "REMOVE"}, {Rename, "RENAME"}, {Chmod, "CHMOD"}} {
+		if op&o.op != 0 {
+			names = append(names, o.name)
+		}
+	}
+	if len(names) == 0 {
+		return "NONE"
+	}
+	return strings.Join(names, "|")
+}
+
+// Has reports whether op includes other
+func (op Op) Has(other Op) bool {
+	return op&other != 0
+}
+
+// Event is a change to a watched file
+type Event struct {
+	Path string
+	Op   Op
+}
+
+// String describes the event, e.g. `WRITE "cmd/root.go"`
+func (e Event) String() string {
+	return fmt.Sprintf("%s %q", e.Op, e.Path)
+}
+
+// Watcher reports changes to files. Watch starts watching and returns the
+// channel events are sent on, which is closed by Close.
+type Watcher interface {
+	Watch(paths []string) (<-chan Event, error)
+	Close() error
+}
+
+// PollingWatcher is a Watcher that compares the size, modification time and
+// mode of the watched files at each interval. Directories are watched with
+// all the files below them, except in hidden directories such as .git.
+// Polling can't tell a rename from a removal, so a renamed file is reported
+// as the Remove of the old path and the Create of the new one.
+type PollingWatcher struct {
+	interval time.Duration
+
+	mu       sync.Mutex
+	watching bool
+	closed   bool
+	done     chan struct{}
+	stopped  chan struct{}
+}
+
+// fileState is what a PollingWatcher compares to detect a change
+type fileState struct {
+	size    int64
+	modTime time.Time
+	mode    os.FileMode
+}
+
+// NewPollingWatcher creates a watcher checking for changes at each interval,
+// or at DefaultPollInterval if interval is 0 or less
+func NewPollingWatcher(interval time.Duration) *PollingWatcher {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	return &PollingWatcher{interval: interval}
+}
+
+// Watch starts watching the paths, which must exist. A watcher watches one
+// set of paths; create another to watch more.
+func (w *PollingWatcher) Watch(paths []string) (<-chan Event, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	switch {
+	case w.closed:
+		return nil, fmt.Errorf("watcher is closed")
+	case w.watching:
+		return nil, fmt.Errorf("watcher is already watching")
+	}
+
+	for _, path := range paths {
+		if _, err := os.Stat(path); err != nil {
+			return nil, fmt.Errorf("cannot watch %s: %w", path, err)
+		}
+	}
+	previous := snapshot(paths)
+
+	w.watching = true
+	w.done = make(chan struct{})
+	w.stopped = make(chan struct{})
+	events := make(chan Event)
+	go func() {
+		defer close(w.stopped)
+		defer close(events)
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-w.done:
+				return
+			case <-ticker.C:
+			}
+			current := snapshot(paths)
+			for _, event := range diffSnapshots(previous, current) {
+				select {
+				case events <- event:
+				case <-w.done:
+					return
+				}
+			}
+			previous = current
+		}
+	}()
+	return events, nil
+}
+
+// Close stops watching and closes the event channel
+func (w *PollingWatcher) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	if w.watching {
+		close(w.done)
+		<-w.stopped
+	}
+	return nil
+}
+
+// snapshot returns the state of the files at or below the paths. Files that
+// can't be read, such as ones removed while walking, are left out.
+func snapshot(paths []string) map[string]fileState {
+	files := make(map[string]fileState)
+	for _, root := range paths {
+		_ = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return nil
+			}
+			if info.IsDir() {
+				if path != root && strings.HasPrefix(info.Name(), ".") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			files[path] = fileState{size: info.Size(), modTime: info.ModTime(), mode: info.Mode()}
+			return nil
+		})
+	}
+	return files
+}
+
+// diffSnapshots returns the changes from previous to current, sorted by path
+func diffSnapshots(previous, current map[string]fileState) []Event {
+	var events []Event
+	for path, state := range current {
+		old, ok := previous[path]
+		if !ok {
+			events = append(events, Event{Path: path, Op: Create})
+			continue
+		}
+		var op Op
+		if old.size != state.size || !old.modTime.Equal(state.modTime) {
+			op |= Write
+		}
+		if old.mode != state.mode {
+			op |= Chmod
+		}
+		if op != 0 {
+			events = append(events, Event{Path: path, Op: op})
+		}
+	}
+	for path := range previous {
+		if _, ok := current[path]; !ok {
+			events = append(events, Event{Path: path, Op: Remove})
+		}
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Path < events[j].Path
+	})
+	return events
+}
@@ -0,0 +1,161 @@
+package filesystem
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var (
+	_ Watcher = (*PollingWatcher)(nil)
+	_ Watcher = (*FSNotifyWatcher)(nil)
+	_ Watcher = (*MockWatcher)(nil)
+)
+
+// nextEvent returns the next event from events, failing the test if none
+// arrives within a second
+func nextEvent(t *testing.T, events <-chan Event) Event {
+	t.Helper()
+	select {
+	case event, ok := <-events:
+		if !ok {
+			t.Fatal("event channel closed")
+		}
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("no event within a second")
+		return Event{}
+	}
+}
+
+// waitForEvent reads events until one for path includes op. Polling can
+// report a write in several steps, so other events for path are skipped;
+// events for other paths fail the test.
+func waitForEvent(t *testing.T, events <-chan Event, path string, op Op) {
+	t.Helper()
+	for {
+		event := nextEvent(t, events)
+		if event.Path != path {
+			t.Fatalf("unexpected event %s while waiting for %s %q", event, op, path)
+		}
+		if event.Op.Has(op) {
+			return
+		}
+	}
+}
+
+func TestPollingWatcher(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "root.go")
+	if err := os.WriteFile(existing, []byte("package cmd\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	watcher := NewPollingWatcher(10 * time.Millisecond)
+	events, err := watcher.Watch([]string{dir})
+	if err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+	defer watcher.Close()
+
+	// Files in hidden directories are not watched
+	if err := os.WriteFile(filepath.Join(dir, ".git", "HEAD"), []byte("ref\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	created := filepath.Join(dir, "serve.go")
+	if err := os.WriteFile(created, []byte("package cmd\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	waitForEvent(t, events, created, Create)
+
+	if err := os.WriteFile(existing, []byte("package cmd\n\nfunc Execute() {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	waitForEvent(t, events, existing, Write)
+
+	if err := os.Chmod(existing, 0600); err != nil {
+		t.Fatal(err)
+	}
+	waitForEvent(t, events, existing, Chmod)
+
+	if err := os.Remove(created); err != nil {
+		t.Fatal(err)
+	}
+	waitForEvent(t, events, created, Remove)
+
+	if err := watcher.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if _, ok := <-events; ok {
+		t.Error("event channel still open after Close()")
+	}
+	if _, err := watcher.Watch([]string{dir}); err == nil {
+		t.Error("Watch() after Close() succeeded")
+	}
+}
+
+func TestPollingWatcher_MissingPath(t *testing.T) {
+	watcher := NewPollingWatcher(0)
+	defer watcher.Close()
+	_, err := watcher.Watch([]string{filepath.Join(t.TempDir(), "missing")})
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Watch() error = %v, want not exist", err)
+	}
+}
+
+func TestMockWatcher(t *testing.T) {
+	watcher := NewMockWatcher(Event{Path: "cmd/root.go", Op: Write})
+	events, err := watcher.Watch([]string{"cmd"})
+	if err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+	if len(watcher.Paths) != 1 || watcher.Paths[0] != "cmd" {
+		t.Errorf("Paths = %v, want [cmd]", watcher.Paths)
+	}
+
+	if got := nextEvent(t, events); got != (Event{Path: "cmd/root.go", Op: Write}) {
+		t.Errorf("event = %s, want the pre-fed WRITE", got)
+	}
+	watcher.Send(Event{Path: "cmd/serve.go", Op: Create})
+	if got := nextEvent(t, events); got != (Event{Path: "cmd/serve.go", Op: Create}) {
+		t.Errorf("event = %s, want the sent CREATE", got)
+	}
+
+	watcher.Close()
+	watcher.Send(Event{Path: "cmd/serve.go", Op: Remove}) // ignored
+	if _, ok := <-events; ok {
+		t.Error("event channel still open after Close()")
+	}
+}
+
+func TestMockWatcher_WatchErr(t *testing.T) {
+	watcher := &MockWatcher{WatchErr: errors.New("too many open files")}
+	if _, err := watcher.Watch([]string{"."}); err == nil || err.Error() != "too many open files" {
+		t.Errorf("Watch() error = %v", err)
+	}
+}
+
+func TestOp_String(t *testing.T) {
+	tests := []struct {
+		op   Op
+		want string
+	}{
+		{Create, "CREATE"},
+		{Write | Chmod, "WRITE|CHMOD"},
+		{Rename, "RENAME"},
+		{0, "NONE"},
+	}
+	for _, tt := range tests {
+		if got := tt.op.String(); got != tt.want {
+			t.Errorf("Op(%d).String() = %q, want %q", tt.op, got, tt.want)
+		}
+	}
+	if got := (Event{Path: "cmd/root.go", Op: Write}).String(); got != `WRITE "cmd/root.go"` {
+		t.Errorf("Event.String() = %s", got)
+	}
+}
@@ -0,0 +1,207 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/contract"
+)
+
+// Graph formats, as accepted by Graph.Render
+const (
+	GraphFormatDOT      = "dot"
+	GraphFormatMermaid  = "mermaid"
+	GraphFormatPlantUML = "puml"
+)
+
+// Graph renders a contract's command tree as a diagram. Each command is a
+// node labelled with its use line, short description and flag count, with
+// an edge from its parent command.
+type Graph struct {
+	// IncludeFlags adds a leaf node for each flag, connected to its command
+	// by a dashed edge
+	IncludeFlags bool
+}
+
+// RenderDOT renders the contract's command tree as a Graphviz DOT graph
+func RenderDOT(c *contract.Contract) string {
+	return Graph{}.DOT(c)
+}
+
+// RenderMermaid renders the contract's command tree as a Mermaid flowchart
+func RenderMermaid(c *contract.Contract) string {
+	return Graph{}.Mermaid(c)
+}
+
+// RenderPlantUML renders the contract's command tree as a PlantUML diagram
+func RenderPlantUML(c *contract.Contract) string {
+	return Graph{}.PlantUML(c)
+}
+
+// Render renders the contract's command tree in format, one of the
+// GraphFormat constants
+func (g Graph) Render(c *contract.Contract, format string) (string, error) {
+	switch format {
+	case GraphFormatDOT:
+		return g.DOT(c), nil
+	case GraphFormatMermaid:
+		return g.Mermaid(c), nil
+	case GraphFormatPlantUML:
+		return g.PlantUML(c), nil
+	default:
+		return "", fmt.Errorf("unsupported graph format '%s': must be dot, mermaid or puml", format)
+	}
+}
+
+// DOT renders the contract's command tree as a Graphviz DOT graph
+func (g Graph) DOT(c *contract.Contract) string {
+	var sb strings.Builder
+	sb.WriteString("digraph cli {\n")
+	sb.WriteString("  rankdir=LR;\n")
+	sb.WriteString("  node [shape=box];\n")
+	for _, n := range g.nodes(c) {
+		label := dotLabel(n.lines)
+		if n.flag {
+			fmt.Fprintf(&sb, "  %s [label=%s, shape=ellipse];\n", n.id, label)
+		} else {
+			fmt.Fprintf(&sb, "  %s [label=%s];\n", n.id, label)
+		}
+		if n.parent == "" {
+			continue
+		}
+		if n.flag {
+			fmt.Fprintf(&sb, "  %s -> %s [style=dashed];\n", n.parent, n.id)
+		} else {
+			fmt.Fprintf(&sb, "  %s -> %s;\n", n.parent, n.id)
+		}
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// Mermaid renders the contract's command tree as a Mermaid flowchart, which
+// GitHub renders in markdown files inside a ```mermaid block
+func (g Graph) Mermaid(c *contract.Contract) string {
+	var sb strings.Builder
+	sb.WriteString("flowchart LR\n")
+	for _, n := range g.nodes(c) {
+		label := mermaidLabel(n.lines)
+		if n.flag {
+			fmt.Fprintf(&sb, "  %s([%s])\n", n.id, label)
+		} else {
+			fmt.Fprintf(&sb, "  %s[%s]\n", n.id, label)
+		}
+		if n.parent == "" {
+			continue
+		}
+		if n.flag {
+			fmt.Fprintf(&sb, "  %s -.-> %s\n", n.parent, n.id)
+		} else {
+			fmt.Fprintf(&sb, "  %s --> %s\n", n.parent, n.id)
+		}
+	}
+	return sb.String()
+}
+
+// PlantUML renders the contract's command tree as a PlantUML diagram
+func (g Graph) PlantUML(c *contract.Contract) string {
+	var sb strings.Builder
+	sb.WriteString("@startuml\n")
+	sb.WriteString("left to right direction\n")
+	for _, n := range g.nodes(c) {
+		label := pumlLabel(n.lines)
+		if n.flag {
+			fmt.Fprintf(&sb, "card %s as %s\n", label, n.id)
+		} else {
+			fmt.Fprintf(&sb, "rectangle %s as %s\n", label, n.id)
+		}
+		if n.parent == "" {
+			continue
+		}
+		if n.flag {
+			fmt.Fprintf(&sb, "%s ..> %s\n", n.parent, n.id)
+		} else {
+			fmt.Fprintf(&sb, "%s --> %s\n", n.parent, n.id)
+		}
+	}
+	sb.WriteString("@enduml\n")
+	return sb.String()
+}
+
+// graphNode is a command or flag node of a rendered graph
+type graphNode struct {
+	id string
+	// parent is the id of the parent command's node, empty for the root
+	parent string
+	// lines are the lines of the node's label
+	lines []string
+	flag  bool
+}
+
+// nodes returns the graph's nodes depth first, each after its parent. Node
+// ids are numbered in that order, as command names aren't valid ids in
+// every format.
+func (g Graph) nodes(c *contract.Contract) []graphNode {
+	var nodes []graphNode
+	var addCommand func(parent, use, short string, flags []contract.Flag, commands []contract.Command)
+	addCommand = func(parent, use, short string, flags []contract.Flag, commands []contract.Command) {
+		id := fmt.Sprintf("cmd%d", len(nodes))
+		lines := []string{use}
+		if short != "" {
+			lines = append(lines, short)
+		}
+		lines = append(lines, flagCount(len(flags)))
+		nodes = append(nodes, graphNode{id: id, parent: parent, lines: lines})
+
+		if g.IncludeFlags {
+			for _, flag := range flags {
+				nodes = append(nodes, graphNode{
+					id:     fmt.Sprintf("flag%d", len(nodes)),
+					parent: id,
+					lines:  []string{fmt.Sprintf("--%s (%s)", flag.Name, flag.Type)},
+					flag:   true,
+				})
+			}
+		}
+		for _, cmd := range commands {
+			addCommand(id, cmd.Use, cmd.Short, cmd.Flags, cmd.Commands)
+		}
+	}
+	addCommand("", c.Use, c.Short, c.Flags, c.Commands)
+	return nodes
+}
+
+// flagCount describes the number of flags in a node label, e.g. "2 flags"
+func flagCount(n int) string {
+	if n == 1 {
+		return "1 flag"
+	}
+	return fmt.Sprintf("%d flags", n)
+}
+
+// dotLabel returns a quoted DOT label of lines
+func dotLabel(lines []string) string {
+	escaper := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	escaped := make([]string, len(lines))
+	for i, line := range lines {
+		escaped[i] = escaper.Replace(line)
+	}
+	return `"` + strings.Join(escaped, `\n`) + `"`
+}
+
+// mermaidLabel returns a quoted Mermaid label of lines. Mermaid has no
+// escape character; quotes are written as the #quot; entity instead.
+func mermaidLabel(lines []string) string {
+	return `"` + strings.ReplaceAll(strings.Join(lines, "<br/>"), `"`, "#quot;") + `"`
+}
+
+// pumlLabel returns a quoted PlantUML label of lines. PlantUML can't escape
+// quotes in labels, so they are replaced with single quotes.
+func pumlLabel(lines []string) string {
+	escaper := strings.NewReplacer(`\`, `\\`, `"`, `'`)
+	escaped := make([]string, len(lines))
+	for i, line := range lines {
+		escaped[i] = escaper.Replace(line)
+	}
+	return `"` + strings.Join(escaped, `\n`) + `"`
+}
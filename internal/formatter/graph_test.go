@@ -0,0 +1,143 @@
+package formatter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/contract"
+)
+
+// graphContract is the contract rendered by the graph tests
+var graphContract = &contract.Contract{
+	Use:   "myapp",
+	Short: "My application",
+	Flags: []contract.Flag{{Name: "config", Type: "string", Persistent: true}},
+	Commands: []contract.Command{
+		{Use: "db", Short: `Manage the "main" database`, Commands: []contract.Command{
+			{Use: "migrate [version]", Short: "Run migrations", Flags: []contract.Flag{
+				{Name: "dry-run", Type: "bool"},
+				{Name: "steps", Type: "int"},
+			}},
+		}},
+		{Use: "serve", Short: "Start the server"},
+	},
+}
+
+func TestRenderDOT(t *testing.T) {
+	got := RenderDOT(graphContract)
+	want := `digraph cli {
+  rankdir=LR;
+  node [shape=box];
+  cmd0 [label="myapp\nMy application\n1 flag"];
+  cmd1 [label="db\nManage the \"main\" database\n0 flags"];
+  cmd0 -> cmd1;
+  cmd2 [label="migrate [version]\nRun migrations\n2 flags"];
+  cmd1 -> cmd2;
+  cmd3 [label="serve\nStart the server\n0 flags"];
+  cmd0 -> cmd3;
+}
+`
+	if got != want {
+		t.Errorf("RenderDOT() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRenderMermaid(t *testing.T) {
+	got := RenderMermaid(graphContract)
+	want := `flowchart LR
+  cmd0["myapp<br/>My application<br/>1 flag"]
+  cmd1["db<br/>Manage the #quot;main#quot; database<br/>0 flags"]
+  cmd0 --> cmd1
+  cmd2["migrate [version]<br/>Run migrations<br/>2 flags"]
+  cmd1 --> cmd2
+  cmd3["serve<br/>Start the server<br/>0 flags"]
+  cmd0 --> cmd3
+`
+	if got != want {
+		t.Errorf("RenderMermaid() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRenderPlantUML(t *testing.T) {
+	got := RenderPlantUML(graphContract)
+	for _, want := range []string{
+		"@startuml\n",
+		`rectangle "myapp\nMy application\n1 flag" as cmd0`,
+		`rectangle "db\nManage the 'main' database\n0 flags" as cmd1`,
+		`rectangle "migrate [version]\nRun migrations\n2 flags" as cmd2`,
+		`rectangle "serve\nStart the server\n0 flags" as cmd3`,
+		"cmd0 --> cmd1\n",
+		"cmd1 --> cmd2\n",
+		"cmd0 --> cmd3\n",
+		"@enduml\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("RenderPlantUML() missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestGraph_IncludeFlags(t *testing.T) {
+	g := Graph{IncludeFlags: true}
+
+	tests := []struct {
+		format string
+		want   []string
+	}{
+		{
+			format: GraphFormatDOT,
+			want: []string{
+				`flag1 [label="--config (string)", shape=ellipse];`,
+				"cmd0 -> flag1 [style=dashed];",
+				`flag4 [label="--dry-run (bool)", shape=ellipse];`,
+				"cmd3 -> flag4 [style=dashed];",
+				`flag5 [label="--steps (int)", shape=ellipse];`,
+				"cmd3 -> flag5 [style=dashed];",
+			},
+		},
+		{
+			format: GraphFormatMermaid,
+			want: []string{
+				`flag1(["--config (string)"])`,
+				"cmd0 -.-> flag1",
+				`flag4(["--dry-run (bool)"])`,
+				`flag5(["--steps (int)"])`,
+				"cmd3 -.-> flag5",
+			},
+		},
+		{
+			format: GraphFormatPlantUML,
+			want: []string{
+				`card "--config (string)" as flag1`,
+				"cmd0 ..> flag1",
+				`card "--steps (int)" as flag5`,
+				"cmd3 ..> flag5",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			got, err := g.Render(graphContract, tt.format)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("Render() missing %q:\n%s", want, got)
+				}
+			}
+		})
+	}
+
+	if got := RenderDOT(graphContract); strings.Contains(got, "--config") {
+		t.Errorf("RenderDOT() includes flag nodes without IncludeFlags:\n%s", got)
+	}
+}
+
+func TestGraph_Render_UnsupportedFormat(t *testing.T) {
+	_, err := Graph{}.Render(graphContract, "svg")
+	if err == nil || err.Error() != "unsupported graph format 'svg': must be dot, mermaid or puml" {
+		t.Errorf("Render() error = %v, want unsupported format", err)
+	}
+}
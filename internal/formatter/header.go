@@ -0,0 +1,78 @@
+// Package formatter renders the header comment written at the top of
+// generated contracts, and diagrams of a contract's command tree.
+//
+// The header is a Go text/template rendered with HeaderData, with each line
+// of the result written as a YAML comment:
+//
+//	header, err := formatter.RenderHeader("Generated from {{.Entrypoint}} at {{.Timestamp}}", formatter.HeaderData{
+//	    Entrypoint: "github.com/org/repo/cmd.NewRootCmd",
+//	    Timestamp:  time.Now().UTC().Format(time.RFC3339),
+//	})
+//	// # Generated from github.com/org/repo/cmd.NewRootCmd at 2024-05-01T12:00:00Z
+//	// #
+//
+// Graph renders the command tree as Graphviz DOT, Mermaid or PlantUML, with
+// a node for each command and, optionally, each flag:
+//
+//	dot := formatter.RenderDOT(c)
+//	mermaid, err := formatter.Graph{IncludeFlags: true}.Render(c, formatter.GraphFormatMermaid)
+package formatter
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// DefaultHeaderTemplate is the header written when no other is given. Lines
+// for an unknown project or entrypoint are left out.
+const DefaultHeaderTemplate = `Generated by cliguard v{{.Version}}
+{{- if .Project}}
+Project: {{.Project}}
+{{- end}}
+{{- if .Entrypoint}}
+Entrypoint: {{.Entrypoint}}
+{{- end}}
+Generated at: {{.Timestamp}}`
+
+// HeaderData holds the values a header template can use
+type HeaderData struct {
+	// Version is the version of cliguard, e.g. "0.1.0"
+	Version string
+
+	// Project is the module path of the inspected project, if known
+	Project string
+
+	// Entrypoint is the function that creates the root command, if given
+	Entrypoint string
+
+	// Timestamp is the time the contract was generated, in RFC 3339 format
+	Timestamp string
+}
+
+// RenderHeader executes the header template text with data and returns the
+// result as YAML comment lines, ending with an empty comment line that
+// separates the header from the contract. Referring to a field HeaderData
+// doesn't have is an error.
+func RenderHeader(text string, data HeaderData) (string, error) {
+	tmpl, err := template.New("header").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid header template: %w", err)
+	}
+	var rendered strings.Builder
+	if err := tmpl.Execute(&rendered, data); err != nil {
+		return "", fmt.Errorf("failed to render header template: %w", err)
+	}
+
+	var sb strings.Builder
+	for _, line := range strings.Split(strings.TrimRight(rendered.String(), "\n"), "\n") {
+		line = strings.TrimRight(line, " \t")
+		if line == "" {
+			sb.WriteString("#\n")
+			continue
+		}
+		sb.WriteString("# " + line + "\n")
+	}
+	sb.WriteString("#\n")
+	return sb.String(), nil
+}
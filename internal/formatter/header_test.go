@@ -0,0 +1,75 @@
+package formatter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderHeader(t *testing.T) {
+	data := HeaderData{
+		Version:    "1.2.3",
+		Project:    "github.com/org/repo",
+		Entrypoint: "github.com/org/repo/cmd.NewRootCmd",
+		Timestamp:  "2024-05-01T12:00:00Z",
+	}
+
+	tests := []struct {
+		name string
+		text string
+		data HeaderData
+		want string
+	}{
+		{
+			name: "default",
+			text: DefaultHeaderTemplate,
+			data: data,
+			want: `# Generated by cliguard v1.2.3
+# Project: github.com/org/repo
+# Entrypoint: github.com/org/repo/cmd.NewRootCmd
+# Generated at: 2024-05-01T12:00:00Z
+#
+`,
+		},
+		{
+			name: "default without project and entrypoint",
+			text: DefaultHeaderTemplate,
+			data: HeaderData{Version: "1.2.3", Timestamp: "2024-05-01T12:00:00Z"},
+			want: `# Generated by cliguard v1.2.3
+# Generated at: 2024-05-01T12:00:00Z
+#
+`,
+		},
+		{
+			name: "custom",
+			text: "Generated from {{.Entrypoint}} at {{.Timestamp}}",
+			data: data,
+			want: "# Generated from github.com/org/repo/cmd.NewRootCmd at 2024-05-01T12:00:00Z\n#\n",
+		},
+		{
+			name: "blank lines and trailing newline",
+			text: "Contract for {{.Project}}\n\nReview before merging.  \n",
+			data: data,
+			want: "# Contract for github.com/org/repo\n#\n# Review before merging.\n#\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderHeader(tt.text, tt.data)
+			if err != nil {
+				t.Fatalf("RenderHeader() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("RenderHeader() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderHeader_Errors(t *testing.T) {
+	for _, text := range []string{"{{.Entrypoint", "{{.Date}}"} {
+		if _, err := RenderHeader(text, HeaderData{}); err == nil || !strings.Contains(err.Error(), "header template") {
+			t.Errorf("RenderHeader(%q) error = %v, want a header template error", text, err)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+// Package github posts cliguard reports to GitHub pull requests.
+//
+// Example:
+//
+//	client, err := github.NewClientFromEnv(os.Getenv)
+//	if err != nil {
+//	    return err
+//	}
+//	err = client.PostPRComment(report)
+package github
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Environment variables read by NewClientFromEnv. GITHUB_TOKEN,
+// GITHUB_REPOSITORY and GITHUB_API_URL are set by GitHub Actions;
+// GITHUB_PR_NUMBER has to be set by the workflow, e.g. to
+// ${{ github.event.pull_request.number }}.
+const (
+	EnvToken      = "GITHUB_TOKEN"
+	EnvRepository = "GITHUB_REPOSITORY"
+	EnvPRNumber   = "GITHUB_PR_NUMBER"
+	EnvAPIURL     = "GITHUB_API_URL"
+)
+
+// DefaultAPIURL is the GitHub REST API used when GITHUB_API_URL is not set
+const DefaultAPIURL = "https://api.github.com"
+
+// Client posts comments to one pull request
+type Client struct {
+	// APIURL is the base URL of the REST API, e.g. https://api.github.com
+	APIURL string
+
+	Token string
+
+	// Repository is the repository in owner/name form
+	Repository string
+
+	PRNumber int
+
+	HTTPClient *http.Client
+}
+
+// NewClientFromEnv creates a client from the GitHub Actions environment,
+// using getenv (usually os.Getenv) to read the variables
+func NewClientFromEnv(getenv func(string) string) (*Client, error) {
+	client := &Client{
+		APIURL:     strings.TrimSuffix(getenv(EnvAPIURL), "/"),
+		Token:      getenv(EnvToken),
+		Repository: getenv(EnvRepository),
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+	}
+	if client.APIURL == "" {
+		client.APIURL = DefaultAPIURL
+	}
+
+	var missing []string
+	for _, name := range []string{EnvToken, EnvRepository, EnvPRNumber} {
+		if getenv(name) == "" {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("posting a GitHub comment requires %s to be set", strings.Join(missing, ", "))
+	}
+
+	if !strings.Contains(client.Repository, "/") {
+		return nil, fmt.Errorf("invalid %s '%s' (expected owner/name)", EnvRepository, client.Repository)
+	}
+	number, err := strconv.Atoi(getenv(EnvPRNumber))
+	if err != nil || number <= 0 {
+		return nil, fmt.Errorf("invalid %s '%s' (expected a pull request number)", EnvPRNumber, getenv(EnvPRNumber))
+	}
+	client.PRNumber = number
+	return client, nil
+}
+
+// PostPRComment adds a comment with the given markdown body to the pull
+// request. Pull request comments are issue comments in the GitHub API.
+func (c *Client) PostPRComment(body string) error {
+	payload, err := json.Marshal(map[string]string{"body": body})
+	if err != nil {
+		return fmt.Errorf("failed to encode comment: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/repos/%s/issues/%d/comments", c.APIURL, c.Repository, c.PRNumber)
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post comment to %s#%d: %w", c.Repository, c.PRNumber, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("failed to post comment to %s#%d: GitHub returned %s: %s",
+			c.Repository, c.PRNumber, resp.Status, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
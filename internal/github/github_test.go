@@ -0,0 +1,111 @@
+package github
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func env(values map[string]string) func(string) string {
+	return func(name string) string {
+		return values[name]
+	}
+}
+
+func TestNewClientFromEnv(t *testing.T) {
+	client, err := NewClientFromEnv(env(map[string]string{
+		EnvToken:      "secret",
+		EnvRepository: "owner/repo",
+		EnvPRNumber:   "42",
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, DefaultAPIURL, client.APIURL)
+	assert.Equal(t, "secret", client.Token)
+	assert.Equal(t, "owner/repo", client.Repository)
+	assert.Equal(t, 42, client.PRNumber)
+
+	client, err = NewClientFromEnv(env(map[string]string{
+		EnvToken:      "secret",
+		EnvRepository: "owner/repo",
+		EnvPRNumber:   "42",
+		EnvAPIURL:     "https://github.example.com/api/v3/",
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, "https://github.example.com/api/v3", client.APIURL)
+}
+
+func TestNewClientFromEnv_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantErr string
+	}{
+		{
+			name:    "nothing set",
+			env:     map[string]string{},
+			wantErr: "requires GITHUB_TOKEN, GITHUB_REPOSITORY, GITHUB_PR_NUMBER to be set",
+		},
+		{
+			name:    "no pull request",
+			env:     map[string]string{EnvToken: "secret", EnvRepository: "owner/repo"},
+			wantErr: "requires GITHUB_PR_NUMBER to be set",
+		},
+		{
+			name:    "invalid repository",
+			env:     map[string]string{EnvToken: "secret", EnvRepository: "repo", EnvPRNumber: "1"},
+			wantErr: "invalid GITHUB_REPOSITORY 'repo'",
+		},
+		{
+			name:    "invalid pull request number",
+			env:     map[string]string{EnvToken: "secret", EnvRepository: "owner/repo", EnvPRNumber: "refs/pull/1"},
+			wantErr: "invalid GITHUB_PR_NUMBER 'refs/pull/1'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewClientFromEnv(env(tt.env))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestClient_PostPRComment(t *testing.T) {
+	var got struct {
+		method, path, auth string
+		body               map[string]string
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got.method, got.path, got.auth = r.Method, r.URL.Path, r.Header.Get("Authorization")
+		_ = json.NewDecoder(r.Body).Decode(&got.body)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	client := &Client{APIURL: server.URL, Token: "secret", Repository: "owner/repo", PRNumber: 7}
+	require.NoError(t, client.PostPRComment("## ✅ passed"))
+
+	assert.Equal(t, http.MethodPost, got.method)
+	assert.Equal(t, "/repos/owner/repo/issues/7/comments", got.path)
+	assert.Equal(t, "Bearer secret", got.auth)
+	assert.Equal(t, map[string]string{"body": "## ✅ passed"}, got.body)
+}
+
+func TestClient_PostPRCommentError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"message": "Resource not accessible by integration"}`))
+	}))
+	defer server.Close()
+
+	client := &Client{APIURL: server.URL, Token: "secret", Repository: "owner/repo", PRNumber: 7}
+	err := client.PostPRComment("body")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "owner/repo#7: GitHub returned 403 Forbidden")
+	assert.Contains(t, err.Error(), "Resource not accessible by integration")
+}
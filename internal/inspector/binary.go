@@ -0,0 +1,206 @@
+package inspector
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/executor"
+)
+
+// maxHelpDepth limits how deep BinaryInspector follows subcommands, guarding
+// against CLIs whose help output lists a command as its own subcommand
+const maxHelpDepth = 16
+
+// defaultCompletionShort is the Short of the completion command Cobra adds to
+// every CLI with subcommands
+const defaultCompletionShort = "Generate the autocompletion script for the specified shell"
+
+// BinaryConfig configures inspection of a compiled CLI binary
+type BinaryConfig struct {
+	// BinaryPath is the path to the compiled CLI
+	BinaryPath string
+
+	// Timeout applies to each help invocation. A zero timeout means none.
+	Timeout time.Duration
+
+	Executor executor.CommandExecutor
+}
+
+// BinaryInspector reconstructs a CLI's structure from its Cobra help output
+// when the source code is not available. It runs "<binary> --help", then
+// "<binary> <command> --help" for each listed subcommand, recursively.
+//
+// Help output omits some information that source inspection reports: the
+// root command's Short when it also has a Long, hidden commands and flags,
+// command group IDs and flag completions. The resulting structure should be
+// reviewed before it is used as a contract.
+type BinaryInspector struct {
+	config BinaryConfig
+}
+
+// NewBinaryInspector creates a new BinaryInspector with the given configuration
+func NewBinaryInspector(config BinaryConfig) *BinaryInspector {
+	if config.Executor == nil {
+		config.Executor = &executor.OSExecutor{}
+	}
+	if config.Timeout > 0 {
+		config.Executor = executor.NewTimeoutExecutor(config.Executor, config.Timeout)
+	}
+	return &BinaryInspector{config: config}
+}
+
+// InspectBinary reconstructs the CLI structure of a compiled Cobra binary from
+// its help output. A timeout of 0 means no timeout will be applied.
+func InspectBinary(binaryPath string, timeout time.Duration) (*InspectedCLI, error) {
+	return NewBinaryInspector(BinaryConfig{
+		BinaryPath: binaryPath,
+		Timeout:    timeout,
+	}).Inspect()
+}
+
+// Inspect runs the binary's help for every command and assembles the results
+func (b *BinaryInspector) Inspect() (*InspectedCLI, error) {
+	page, err := b.help(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	use := strings.TrimSuffix(page.UseLine, " [flags]")
+	if use == "" {
+		use = page.CommandPath
+	}
+	if use == "" {
+		use = strings.TrimSuffix(filepath.Base(b.config.BinaryPath), filepath.Ext(b.config.BinaryPath))
+	}
+
+	cli := &InspectedCLI{
+		Use:     use,
+		Aliases: page.Aliases,
+		Example: page.Example,
+	}
+	// The root command is not listed anywhere, so its Short is only known
+	// when it has no Long
+	if strings.Contains(page.Description, "\n") {
+		cli.Long = page.Description
+	} else {
+		cli.Short = page.Description
+	}
+
+	commands, childPages, err := b.inspectSubcommands(nil, page, 1)
+	if err != nil {
+		return nil, err
+	}
+	cli.Commands = commands
+	cli.Flags = markPersistent(visibleFlags(page.Flags), childPages)
+
+	return cli, nil
+}
+
+// inspectSubcommands inspects the subcommands listed on a help page. It also
+// returns their help pages, which show the parent's persistent flags.
+func (b *BinaryInspector) inspectSubcommands(args []string, page *HelpPage, depth int) ([]InspectedCommand, []*HelpPage, error) {
+	if depth > maxHelpDepth {
+		return nil, nil, fmt.Errorf("command '%s' is nested more than %d levels deep", page.CommandPath, maxHelpDepth)
+	}
+
+	var commands []InspectedCommand
+	var pages []*HelpPage
+	for _, listed := range page.Commands {
+		if isDefaultCommand(listed) {
+			continue
+		}
+
+		childArgs := append(append([]string{}, args...), listed.Name)
+		childPage, err := b.help(childArgs)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		command := InspectedCommand{
+			Use:     commandUse(page.CommandPath, listed.Name, childPage),
+			Short:   listed.Short,
+			Aliases: childPage.Aliases,
+			Example: childPage.Example,
+		}
+		if childPage.Description != listed.Short {
+			command.Long = childPage.Description
+		}
+
+		subcommands, _, err := b.inspectSubcommands(childArgs, childPage, depth+1)
+		if err != nil {
+			return nil, nil, err
+		}
+		command.Commands = subcommands
+		// Like source inspection, only root flags are reported as persistent
+		command.Flags = visibleFlags(childPage.Flags)
+
+		commands = append(commands, command)
+		pages = append(pages, childPage)
+	}
+
+	return commands, pages, nil
+}
+
+// help runs "<binary> <args> --help" and parses the output
+func (b *BinaryInspector) help(args []string) (*HelpPage, error) {
+	helpArgs := append(append([]string{}, args...), "--help")
+	cmd := b.config.Executor.Command(b.config.BinaryPath, helpArgs...)
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run '%s %s': %w", b.config.BinaryPath, strings.Join(helpArgs, " "), err)
+	}
+
+	page, err := ParseHelp(string(output))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse help for '%s %s': %w", b.config.BinaryPath, strings.Join(helpArgs, " "), err)
+	}
+	return page, nil
+}
+
+// commandUse recovers cobra.Command.Use from the command's use line, which is
+// the parent's command path followed by Use (and " [flags]")
+func commandUse(parentPath, name string, page *HelpPage) string {
+	if page.UseLine == "" {
+		return name
+	}
+	use := strings.TrimPrefix(page.UseLine, parentPath+" ")
+	return strings.TrimSuffix(use, " [flags]")
+}
+
+// isDefaultCommand reports whether a listed command is one Cobra adds itself,
+// which source inspection does not report
+func isDefaultCommand(cmd HelpCommand) bool {
+	return cmd.Name == "help" || (cmd.Name == "completion" && cmd.Short == defaultCompletionShort)
+}
+
+// visibleFlags removes the --help and --version flags Cobra adds itself
+func visibleFlags(flags []InspectedFlag) []InspectedFlag {
+	var result []InspectedFlag
+	for _, flag := range flags {
+		if flag.Name == "help" && strings.HasPrefix(flag.Usage, "help for ") {
+			continue
+		}
+		if flag.Name == "version" && strings.HasPrefix(flag.Usage, "version for ") {
+			continue
+		}
+		result = append(result, flag)
+	}
+	return result
+}
+
+// markPersistent marks flags that subcommands show as inherited Global Flags
+func markPersistent(flags []InspectedFlag, childPages []*HelpPage) []InspectedFlag {
+	inherited := make(map[string]bool)
+	for _, page := range childPages {
+		for _, flag := range page.GlobalFlags {
+			inherited[flag.Name] = true
+		}
+	}
+	for i := range flags {
+		flags[i].Persistent = inherited[flags[i].Name]
+	}
+	return flags
+}
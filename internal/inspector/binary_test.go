@@ -0,0 +1,137 @@
+package inspector
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/executor"
+)
+
+func helpResult(output string) executor.MockResult {
+	return executor.MockResult{Output: []byte(output)}
+}
+
+func TestBinaryInspector_Inspect(t *testing.T) {
+	mock := &executor.MockExecutor{Results: map[string]executor.MockResult{
+		"./myapp --help": helpResult(`My application
+
+Usage:
+  myapp [command]
+
+Available Commands:
+  cluster     Manage clusters
+  completion  Generate the autocompletion script for the specified shell
+  help        Help about any command
+
+Flags:
+  -h, --help      help for myapp
+  -v, --verbose   Verbose output
+      --version   version for myapp
+`),
+		"./myapp cluster --help": helpResult(`Manage clusters
+
+Usage:
+  myapp cluster [command]
+
+Available Commands:
+  node        Manage nodes
+
+Flags:
+  -h, --help   help for cluster
+
+Global Flags:
+  -v, --verbose   Verbose output
+`),
+		"./myapp cluster node --help": helpResult(`Manage nodes
+
+Usage:
+  myapp cluster node [command]
+
+Available Commands:
+  drain       Drain a node
+
+Flags:
+  -h, --help   help for node
+
+Global Flags:
+  -v, --verbose   Verbose output
+`),
+		"./myapp cluster node drain --help": helpResult(`Evict all pods from a node
+before maintenance.
+
+Usage:
+  myapp cluster node drain NODE [flags]
+
+Aliases:
+  drain, d
+
+Flags:
+      --force   Continue even if there are unmanaged pods
+  -h, --help    help for drain
+
+Global Flags:
+  -v, --verbose   Verbose output
+`),
+	}}
+
+	cli, err := NewBinaryInspector(BinaryConfig{BinaryPath: "./myapp", Executor: mock}).Inspect()
+	if err != nil {
+		t.Fatalf("Inspect() error = %v", err)
+	}
+
+	if cli.Use != "myapp" || cli.Short != "My application" || cli.Long != "" {
+		t.Errorf("root = %q, short %q, long %q", cli.Use, cli.Short, cli.Long)
+	}
+	if len(cli.Flags) != 1 || cli.Flags[0].Name != "verbose" || !cli.Flags[0].Persistent {
+		t.Errorf("root flags = %+v, want persistent --verbose only", cli.Flags)
+	}
+
+	// The default completion and help commands are skipped
+	if len(cli.Commands) != 1 || cli.Commands[0].Use != "cluster" {
+		t.Fatalf("root commands = %+v, want cluster only", cli.Commands)
+	}
+	cluster := cli.Commands[0]
+	if cluster.Short != "Manage clusters" || cluster.Long != "" || len(cluster.Flags) != 0 {
+		t.Errorf("cluster = %+v", cluster)
+	}
+
+	if len(cluster.Commands) != 1 || len(cluster.Commands[0].Commands) != 1 {
+		t.Fatalf("cluster subcommands = %+v, want node > drain", cluster.Commands)
+	}
+	drain := cluster.Commands[0].Commands[0]
+	if drain.Use != "drain NODE" || drain.Short != "Drain a node" {
+		t.Errorf("drain use = %q, short = %q", drain.Use, drain.Short)
+	}
+	if drain.Long != "Evict all pods from a node\nbefore maintenance." {
+		t.Errorf("drain long = %q", drain.Long)
+	}
+	if len(drain.Aliases) != 1 || drain.Aliases[0] != "d" {
+		t.Errorf("drain aliases = %v, want [d]", drain.Aliases)
+	}
+	if len(drain.Flags) != 1 || drain.Flags[0].Name != "force" || drain.Flags[0].Persistent {
+		t.Errorf("drain flags = %+v, want local --force only", drain.Flags)
+	}
+}
+
+func TestBinaryInspector_Errors(t *testing.T) {
+	t.Run("binary fails", func(t *testing.T) {
+		mock := &executor.MockExecutor{Results: map[string]executor.MockResult{
+			"./myapp --help": {Error: errors.New("exit status 1")},
+		}}
+		_, err := NewBinaryInspector(BinaryConfig{BinaryPath: "./myapp", Executor: mock}).Inspect()
+		if err == nil || !strings.Contains(err.Error(), "failed to run './myapp --help'") {
+			t.Errorf("Inspect() error = %v", err)
+		}
+	})
+
+	t.Run("not cobra help", func(t *testing.T) {
+		mock := &executor.MockExecutor{Results: map[string]executor.MockResult{
+			"./myapp --help": helpResult("usage: myapp [-h]\n"),
+		}}
+		_, err := NewBinaryInspector(BinaryConfig{BinaryPath: "./myapp", Executor: mock}).Inspect()
+		if err == nil || !strings.Contains(err.Error(), "failed to parse help") {
+			t.Errorf("Inspect() error = %v", err)
+		}
+	})
+}
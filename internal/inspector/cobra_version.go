@@ -0,0 +1,151 @@
+package inspector
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/filesystem"
+)
+
+// cobraModulePath is the module path of the Cobra library
+const cobraModulePath = "github.com/spf13/cobra"
+
+// ModernCobraVersion is the first Cobra version whose newer features
+// (command groups) are extracted by the inspector.
+var ModernCobraVersion = Version{Major: 1, Minor: 7, Patch: 0}
+
+// FlagCompletionLookupVersion is the first Cobra version providing
+// Command.GetFlagCompletionFunc, which the inspector uses to detect flags
+// registered with RegisterFlagCompletionFunc.
+var FlagCompletionLookupVersion = Version{Major: 1, Minor: 8, Patch: 0}
+
+// Version is a semantic version (major.minor.patch). Pre-release and build
+// metadata are ignored. The zero value means the version is unknown.
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+// ParseVersion parses a version such as "v1.7.0", "1.7" or "v1.8.0-rc.1"
+func ParseVersion(s string) (Version, error) {
+	trimmed := strings.TrimPrefix(strings.TrimSpace(s), "v")
+
+	// Drop pre-release and build metadata
+	if i := strings.IndexAny(trimmed, "-+"); i >= 0 {
+		trimmed = trimmed[:i]
+	}
+
+	parts := strings.Split(trimmed, ".")
+	if len(parts) == 0 || len(parts) > 3 || parts[0] == "" {
+		return Version{}, fmt.Errorf("invalid version %q (expected major.minor.patch)", s)
+	}
+
+	var numbers [3]int
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return Version{}, fmt.Errorf("invalid version %q (expected major.minor.patch)", s)
+		}
+		numbers[i] = n
+	}
+
+	return Version{Major: numbers[0], Minor: numbers[1], Patch: numbers[2]}, nil
+}
+
+// String returns the version in "vMAJOR.MINOR.PATCH" form
+func (v Version) String() string {
+	return fmt.Sprintf("v%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// IsZero reports whether the version is unknown
+func (v Version) IsZero() bool {
+	return v == Version{}
+}
+
+// Compare returns -1, 0 or +1 depending on whether v is lower than, equal
+// to, or greater than other
+func (v Version) Compare(other Version) int {
+	for _, d := range [3]int{v.Major - other.Major, v.Minor - other.Minor, v.Patch - other.Patch} {
+		if d < 0 {
+			return -1
+		}
+		if d > 0 {
+			return 1
+		}
+	}
+	return 0
+}
+
+// AtLeast reports whether v is greater than or equal to other
+func (v Version) AtLeast(other Version) bool {
+	return v.Compare(other) >= 0
+}
+
+// DetectCobraVersion reads the Cobra version a project depends on from its
+// go.mod, falling back to the highest version listed in go.sum.
+func DetectCobraVersion(projectPath string) (Version, error) {
+	return detectCobraVersion(&filesystem.OSFileSystem{}, projectPath)
+}
+
+// detectCobraVersion implements DetectCobraVersion using the given filesystem
+func detectCobraVersion(fs filesystem.FileSystem, projectPath string) (Version, error) {
+	if content, err := fs.ReadFile(filepath.Join(projectPath, "go.mod")); err == nil {
+		if v, ok := cobraVersionFromGoMod(content); ok {
+			return v, nil
+		}
+	}
+
+	if content, err := fs.ReadFile(filepath.Join(projectPath, "go.sum")); err == nil {
+		if v, ok := cobraVersionFromGoSum(content); ok {
+			return v, nil
+		}
+	}
+
+	return Version{}, fmt.Errorf("could not find %s in go.mod or go.sum of %s", cobraModulePath, projectPath)
+}
+
+// cobraVersionFromGoMod finds the Cobra requirement in go.mod content
+func cobraVersionFromGoMod(content []byte) (Version, bool) {
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		// Handle both "require mod ver" and "mod ver" inside a require block
+		if len(fields) >= 3 && fields[0] == "require" {
+			fields = fields[1:]
+		}
+		if len(fields) >= 2 && fields[0] == cobraModulePath {
+			if v, err := ParseVersion(fields[1]); err == nil {
+				return v, true
+			}
+		}
+	}
+	return Version{}, false
+}
+
+// cobraVersionFromGoSum finds the highest Cobra version listed in go.sum content
+func cobraVersionFromGoSum(content []byte) (Version, bool) {
+	var highest Version
+	found := false
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || fields[0] != cobraModulePath {
+			continue
+		}
+		v, err := ParseVersion(strings.TrimSuffix(fields[1], "/go.mod"))
+		if err != nil {
+			continue
+		}
+		if !found || v.AtLeast(highest) {
+			highest = v
+			found = true
+		}
+	}
+	return highest, found
+}
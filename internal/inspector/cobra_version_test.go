@@ -0,0 +1,155 @@
+package inspector
+
+import (
+	"testing"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/filesystem"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Version
+		wantErr bool
+	}{
+		{"v1.7.0", Version{1, 7, 0}, false},
+		{"1.9.1", Version{1, 9, 1}, false},
+		{"v1.8", Version{1, 8, 0}, false},
+		{"v1.8.0-rc.1", Version{1, 8, 0}, false},
+		{"v0.0.0-20230101120000-abcdef123456", Version{0, 0, 0}, false},
+		{"", Version{}, true},
+		{"v1.x.0", Version{}, true},
+		{"1.2.3.4", Version{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseVersion(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseVersion(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseVersion(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVersion_Compare(t *testing.T) {
+	tests := []struct {
+		a, b Version
+		want int
+	}{
+		{Version{1, 7, 0}, Version{1, 7, 0}, 0},
+		{Version{1, 6, 9}, Version{1, 7, 0}, -1},
+		{Version{1, 10, 0}, Version{1, 7, 0}, 1},
+		{Version{2, 0, 0}, Version{1, 99, 99}, 1},
+		{Version{1, 7, 1}, Version{1, 7, 2}, -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a.String()+"_"+tt.b.String(), func(t *testing.T) {
+			if got := tt.a.Compare(tt.b); got != tt.want {
+				t.Errorf("Compare() = %d, want %d", got, tt.want)
+			}
+			if got := tt.a.AtLeast(tt.b); got != (tt.want >= 0) {
+				t.Errorf("AtLeast() = %v, want %v", got, tt.want >= 0)
+			}
+		})
+	}
+}
+
+func TestDetectCobraVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		want    Version
+		wantErr bool
+	}{
+		{
+			name: "require block in go.mod",
+			files: map[string]string{
+				"/project/go.mod": "module example.com/app\n\nrequire (\n\tgithub.com/spf13/cobra v1.9.1\n\tgithub.com/spf13/pflag v1.0.6 // indirect\n)\n",
+			},
+			want: Version{1, 9, 1},
+		},
+		{
+			name: "single-line require in go.mod",
+			files: map[string]string{
+				"/project/go.mod": "module example.com/app\n\nrequire github.com/spf13/cobra v1.2.1\n",
+			},
+			want: Version{1, 2, 1},
+		},
+		{
+			name: "fallback to highest go.sum entry",
+			files: map[string]string{
+				"/project/go.mod": "module example.com/app\n",
+				"/project/go.sum": "github.com/spf13/cobra v1.6.1 h1:abc=\ngithub.com/spf13/cobra v1.6.1/go.mod h1:def=\ngithub.com/spf13/cobra v1.7.0/go.mod h1:ghi=\n",
+			},
+			want: Version{1, 7, 0},
+		},
+		{
+			name: "cobra not used",
+			files: map[string]string{
+				"/project/go.mod": "module example.com/app\n",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := filesystem.NewMockFileSystem()
+			for path, content := range tt.files {
+				fs.Files[path] = []byte(content)
+			}
+
+			got, err := detectCobraVersion(fs, "/project")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("detectCobraVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("detectCobraVersion() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInspector_generateInspectorCode_CobraVersion(t *testing.T) {
+	info := &EntrypointInfo{
+		ImportPath:   "github.com/test/repo/cmd",
+		ImportAlias:  "userCmd",
+		FunctionName: "NewRootCmd",
+	}
+
+	tests := []struct {
+		name                    string
+		version                 Version
+		wantGroupID             bool
+		wantFlagCompletionCheck bool
+	}{
+		{"unknown version", Version{}, false, false},
+		{"legacy cobra", Version{1, 2, 0}, false, false},
+		{"modern cobra", Version{1, 7, 0}, true, false},
+		{"cobra with completion lookup", Version{1, 8, 0}, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := &Inspector{config: Config{CobraVersion: tt.version}}
+			code, err := i.generateInspectorCode(info)
+			if err != nil {
+				t.Fatalf("generateInspectorCode() error = %v", err)
+			}
+			if got := contains(code, "command.GroupID = cmd.GroupID"); got != tt.wantGroupID {
+				t.Errorf("generated code extracts GroupID = %v, want %v", got, tt.wantGroupID)
+			}
+			if got := contains(code, "cmd.GetFlagCompletionFunc(flag.Name)"); got != tt.wantFlagCompletionCheck {
+				t.Errorf("generated code looks up completion funcs = %v, want %v", got, tt.wantFlagCompletionCheck)
+			}
+			if !contains(code, "cobra.BashCompFilenameExt") {
+				t.Errorf("generated code should always detect filename completion annotations")
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package inspector
+
+import "github.com/hiAndrewQuinn/cliguard/internal/contract"
+
+// CommandCount returns the number of commands under the root command, at
+// any depth. The root command itself is not counted.
+func (c *InspectedCLI) CommandCount() int {
+	return inspectedTree.CountCommands(c.Commands)
+}
+
+// FlagCount returns the number of flags of the root command and of every
+// command under it. With countPersistent, each command also counts the
+// persistent flags it inherits from its ancestors, as its help lists them;
+// otherwise each flag is counted once, on the command that defines it.
+func (c *InspectedCLI) FlagCount(countPersistent bool) int {
+	return inspectedTree.CountFlags(c.Flags, c.Commands, countPersistent)
+}
+
+// MaxDepth returns the nesting level of the deepest command: 0 for a root
+// command without subcommands, 1 if it only has direct subcommands, and so on
+func (c *InspectedCLI) MaxDepth() int {
+	return inspectedTree.MaxDepth(c.Commands)
+}
+
+// inspectedTree counts inspected commands as contracts are counted
+var inspectedTree = contract.CommandTree[InspectedCommand, InspectedFlag]{
+	Commands:   func(cmd InspectedCommand) []InspectedCommand { return cmd.Commands },
+	Flags:      func(cmd InspectedCommand) []InspectedFlag { return cmd.Flags },
+	FlagName:   func(flag InspectedFlag) string { return flag.Name },
+	Persistent: func(flag InspectedFlag) bool { return flag.Persistent },
+}
@@ -0,0 +1,47 @@
+package inspector
+
+import "testing"
+
+func TestInspectedCLICounts(t *testing.T) {
+	c := &InspectedCLI{
+		Use: "myapp",
+		Flags: []InspectedFlag{
+			{Name: "config", Persistent: true},
+			{Name: "verbose", Persistent: true},
+			{Name: "output"},
+		},
+		Commands: []InspectedCommand{
+			{Use: "serve", Flags: []InspectedFlag{{Name: "port"}, {Name: "config"}}, Commands: []InspectedCommand{
+				{Use: "http", Flags: []InspectedFlag{{Name: "tls", Persistent: true}}, Commands: []InspectedCommand{
+					{Use: "h2"},
+				}},
+			}},
+			{Use: "db", Commands: []InspectedCommand{
+				{Use: "migrate", Flags: []InspectedFlag{{Name: "dry-run"}}},
+			}},
+		},
+	}
+
+	if got := c.CommandCount(); got != 5 {
+		t.Errorf("CommandCount() = %d, want 5", got)
+	}
+	// myapp 3, serve 2, http 1, migrate 1
+	if got := c.FlagCount(false); got != 7 {
+		t.Errorf("FlagCount(false) = %d, want 7", got)
+	}
+	// myapp 3, serve 2 + --verbose (its --config shadows the root's),
+	// http 1 + 2, h2 3 inherited, db 2 inherited, migrate 1 + 2
+	if got := c.FlagCount(true); got != 17 {
+		t.Errorf("FlagCount(true) = %d, want 17", got)
+	}
+	if got := c.MaxDepth(); got != 3 {
+		t.Errorf("MaxDepth() = %d, want 3", got)
+	}
+}
+
+func TestInspectedCLICounts_RootOnly(t *testing.T) {
+	c := &InspectedCLI{Use: "tiny", Flags: []InspectedFlag{{Name: "verbose", Persistent: true}}}
+	if c.CommandCount() != 0 || c.FlagCount(true) != 1 || c.FlagCount(false) != 1 || c.MaxDepth() != 0 {
+		t.Errorf("counts = %d, %d, %d, %d; want 0, 1, 1, 0", c.CommandCount(), c.FlagCount(true), c.FlagCount(false), c.MaxDepth())
+	}
+}
@@ -30,6 +30,10 @@
 // 3. Using reflection to extract the cobra command structure
 // 4. Converting the structure to an InspectedCLI representation
 //
+// StaticInspector reads the structure from the project source instead,
+// without building or running anything (see InspectProjectFromBuildOutput).
+// It only finds what is constant in the source.
+//
 // # Supported Features
 //
 // The inspector can extract:
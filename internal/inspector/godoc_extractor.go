@@ -0,0 +1,231 @@
+package inspector
+
+import (
+	"go/ast"
+	"go/doc"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// cobraImportPath is the import path of the Cobra package
+const cobraImportPath = "github.com/spf13/cobra"
+
+// ExtractGodoc scans the project's Go files, excluding tests and the vendor
+// and hidden directories, for documented declarations that build a
+// cobra.Command, and returns their doc comments as plain text keyed by
+// command name (the first word of the command's Use). A declaration is
+// either a constructor function or a package-level variable:
+//
+//	// NewServeCmd starts the HTTP server and blocks until it is stopped.
+//	func NewServeCmd() *cobra.Command {
+//	    return &cobra.Command{Use: "serve", RunE: runServe}
+//	}
+//
+// Only declarations with a single cobra.Command literal with a constant
+// Use count, since the doc comment of one building several commands
+// describes none of them in particular. Commands whose name is documented
+// differently in several places are left out.
+func ExtractGodoc(projectPath string) (map[string]string, error) {
+	found := make(map[string]map[string]bool)
+	err := walkPackages(projectPath, func(fset *token.FileSet, files []*ast.File) {
+		pkg, err := doc.NewFromFiles(fset, files, "", doc.AllDecls|doc.PreserveAST)
+		if err != nil {
+			return
+		}
+		cobraNames := make(map[*ast.File]string)
+		for _, file := range files {
+			cobraNames[file] = cobraImportName(file)
+		}
+
+		add := func(node ast.Node, text string) {
+			if strings.TrimSpace(text) == "" {
+				return
+			}
+			name, ok := singleCommandName(node, files, fset, cobraNames)
+			if !ok {
+				return
+			}
+			if found[name] == nil {
+				found[name] = make(map[string]bool)
+			}
+			found[name][strings.TrimSpace(string(pkg.Text(text)))] = true
+		}
+		for _, fn := range allFuncs(pkg) {
+			add(fn.Decl, fn.Doc)
+		}
+		for _, value := range allVars(pkg) {
+			add(value.Decl, value.Doc)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	docs := make(map[string]string, len(found))
+	for name, texts := range found {
+		if len(texts) != 1 {
+			continue
+		}
+		for text := range texts {
+			docs[name] = text
+		}
+	}
+	return docs, nil
+}
+
+// walkPackages parses the Go files of each directory of the project with
+// their comments, excluding tests and the vendor and hidden directories,
+// and calls fn with the files of each package. Files that fail to parse are
+// skipped.
+func walkPackages(projectPath string, fn func(fset *token.FileSet, files []*ast.File)) error {
+	return filepath.Walk(projectPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		if path != projectPath && (strings.HasPrefix(info.Name(), ".") || info.Name() == "vendor") {
+			return filepath.SkipDir
+		}
+
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return err
+		}
+		fset := token.NewFileSet()
+		packages := make(map[string][]*ast.File)
+		var names []string
+		for _, entry := range entries {
+			name := entry.Name()
+			if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+				continue
+			}
+			file, err := parser.ParseFile(fset, filepath.Join(path, name), nil, parser.ParseComments)
+			if err != nil {
+				continue
+			}
+			if packages[file.Name.Name] == nil {
+				names = append(names, file.Name.Name)
+			}
+			packages[file.Name.Name] = append(packages[file.Name.Name], file)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fn(fset, packages[name])
+		}
+		return nil
+	})
+}
+
+// allFuncs returns the functions and methods of a package, including those
+// go/doc attaches to the type they return
+func allFuncs(pkg *doc.Package) []*doc.Func {
+	funcs := append([]*doc.Func{}, pkg.Funcs...)
+	for _, typ := range pkg.Types {
+		funcs = append(funcs, typ.Funcs...)
+		funcs = append(funcs, typ.Methods...)
+	}
+	return funcs
+}
+
+// allVars returns the package-level variable declarations of a package,
+// including those go/doc attaches to their type
+func allVars(pkg *doc.Package) []*doc.Value {
+	vars := append([]*doc.Value{}, pkg.Vars...)
+	for _, typ := range pkg.Types {
+		vars = append(vars, typ.Vars...)
+	}
+	return vars
+}
+
+// singleCommandName returns the command name of the only cobra.Command
+// literal with a constant Use in node, and whether there is exactly one
+func singleCommandName(node ast.Node, files []*ast.File, fset *token.FileSet, cobraNames map[*ast.File]string) (string, bool) {
+	cobraName := cobraNames[fileOf(node, files, fset)]
+	if cobraName == "" || cobraName == "_" {
+		return "", false
+	}
+
+	var names []string
+	ast.Inspect(node, func(n ast.Node) bool {
+		lit, ok := n.(*ast.CompositeLit)
+		if !ok || !isCobraCommand(lit.Type, cobraName) {
+			return true
+		}
+		if name, ok := literalUse(lit); ok {
+			names = append(names, name)
+		}
+		return true
+	})
+	if len(names) != 1 {
+		return "", false
+	}
+	return names[0], true
+}
+
+// fileOf returns the file of files that contains node
+func fileOf(node ast.Node, files []*ast.File, fset *token.FileSet) *ast.File {
+	filename := fset.Position(node.Pos()).Filename
+	for _, file := range files {
+		if fset.Position(file.Pos()).Filename == filename {
+			return file
+		}
+	}
+	return nil
+}
+
+// cobraImportName returns the name Cobra is imported as in the file, or ""
+// if it is not imported
+func cobraImportName(file *ast.File) string {
+	for _, imp := range file.Imports {
+		if path, err := strconv.Unquote(imp.Path.Value); err == nil && path == cobraImportPath {
+			if imp.Name != nil {
+				return imp.Name.Name
+			}
+			return "cobra"
+		}
+	}
+	return ""
+}
+
+// isCobraCommand reports whether expr is the type cobra.Command
+func isCobraCommand(expr ast.Expr, cobraName string) bool {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Command" {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	return ok && pkg.Name == cobraName
+}
+
+// literalUse returns the command name of a cobra.Command literal, the
+// first word of its Use, if Use is a constant string
+func literalUse(lit *ast.CompositeLit) (string, bool) {
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		if key, ok := kv.Key.(*ast.Ident); !ok || key.Name != "Use" {
+			continue
+		}
+		value, ok := kv.Value.(*ast.BasicLit)
+		if !ok || value.Kind != token.STRING {
+			continue
+		}
+		use, err := strconv.Unquote(value.Value)
+		if err != nil {
+			continue
+		}
+		if fields := strings.Fields(use); len(fields) > 0 {
+			return fields[0], true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,108 @@
+package inspector
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestExtractGodoc(t *testing.T) {
+	tempDir := t.TempDir()
+
+	files := map[string]string{
+		"main.go": `package main
+
+import "github.com/spf13/cobra"
+
+// rootCmd is the base command of the app.
+var rootCmd = &cobra.Command{Use: "app"}
+
+func main() { _ = rootCmd.Execute() }
+`,
+		"cmd/serve.go": `package cmd
+
+import "github.com/spf13/cobra"
+
+// NewServeCmd starts the HTTP server and blocks until it is stopped.
+//
+// The server listens on --port.
+func NewServeCmd() *cobra.Command {
+	return &cobra.Command{Use: "serve [flags]", Short: "Start the server"}
+}
+
+// NewDBCmd builds the db command and its subcommands.
+func NewDBCmd() *cobra.Command {
+	cmd := &cobra.Command{Use: "db"}
+	cmd.AddCommand(&cobra.Command{Use: "migrate"})
+	return cmd
+}
+
+func newUndocumentedCmd() *cobra.Command {
+	return &cobra.Command{Use: "undocumented"}
+}
+`,
+		"cmd/user/user.go": `package user
+
+import c "github.com/spf13/cobra"
+
+// newListCmd lists the users.
+func newListCmd() *c.Command {
+	return &c.Command{Use: "list"}
+}
+`,
+		"cmd/group/group.go": `package group
+
+import "github.com/spf13/cobra"
+
+// newListCmd lists the groups.
+func newListCmd() *cobra.Command {
+	return &cobra.Command{Use: "list"}
+}
+
+// newVersionCmd prints the version.
+func newVersionCmd() *cobra.Command {
+	return &cobra.Command{Use: "version"}
+}
+`,
+		"cmd/group/group_test.go": `package group
+
+import "github.com/spf13/cobra"
+
+// testCmd is a fixture.
+var testCmd = &cobra.Command{Use: "fixture"}
+`,
+		"vendor/lib/lib.go": `package lib
+
+import "github.com/spf13/cobra"
+
+// newVendoredCmd is vendored.
+func newVendoredCmd() *cobra.Command {
+	return &cobra.Command{Use: "vendored"}
+}
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(tempDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	docs, err := ExtractGodoc(tempDir)
+	if err != nil {
+		t.Fatalf("ExtractGodoc() error = %v", err)
+	}
+
+	want := map[string]string{
+		"app":     "rootCmd is the base command of the app.",
+		"serve":   "NewServeCmd starts the HTTP server and blocks until it is stopped.\n\nThe server listens on --port.",
+		"version": "newVersionCmd prints the version.",
+	}
+	if !reflect.DeepEqual(docs, want) {
+		t.Errorf("ExtractGodoc() = %q, want %q", docs, want)
+	}
+}
@@ -0,0 +1,272 @@
+package inspector
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// HelpPage is the information parsed from a single command's Cobra help output
+// (the output of "myapp serve --help").
+//
+// Cobra's default help lists the command's Long description (or Short, if it
+// has no Long), followed by sections such as:
+//
+//	Usage:
+//	  myapp serve [flags]
+//
+//	Available Commands:
+//	  status      Check server status
+//
+//	Flags:
+//	  -p, --port int   Port number (default 8080)
+//
+//	Global Flags:
+//	  -v, --verbose   Verbose output
+type HelpPage struct {
+	// Description is the Long description, or Short if the command has no Long
+	Description string
+
+	// UseLine is the usage line of a runnable command (e.g. "myapp serve [flags]").
+	// Empty for commands that only group subcommands.
+	UseLine string
+
+	// CommandPath is the full command path (e.g. "myapp serve").
+	// Only known if the command is runnable or has subcommands.
+	CommandPath string
+
+	// Aliases are the command's alternative names, excluding its name
+	Aliases []string
+
+	// Example is the content of the Examples section
+	Example string
+
+	// Commands are the subcommands listed in Available Commands, Additional
+	// Commands and command group sections
+	Commands []HelpCommand
+
+	// Flags are the flags defined on the command, including its persistent flags
+	Flags []InspectedFlag
+
+	// GlobalFlags are the persistent flags inherited from parent commands
+	GlobalFlags []InspectedFlag
+}
+
+// HelpCommand is a subcommand listed in a help page
+type HelpCommand struct {
+	Name  string
+	Short string
+}
+
+// flagLinePattern matches a pflag usage line such as
+// "  -p, --port int   Port number (default 8080)" or "      --name[="x"]   Name"
+var flagLinePattern = regexp.MustCompile(`^\s+(?:-(\S), )?--([^\s\[]+)(?: ([^\s\[]+))?(?:\[=.*?\])?(?:\s{2,}(.*))?$`)
+
+// defaultSuffixPattern matches the " (default ...)" suffix pflag appends to usage
+var defaultSuffixPattern = regexp.MustCompile(`(?s)^(.*?) ?\(default (.*)\)$`)
+
+// helpTypeNames maps the type names pflag shows in help output back to flag
+// types (see pflag.UnquoteUsage). Bool flags are shown without a type.
+var helpTypeNames = map[string]string{
+	"":        "bool",
+	"float":   "float64",
+	"strings": "stringSlice",
+	"ints":    "intSlice",
+	"uints":   "uintSlice",
+	"bools":   "boolSlice",
+}
+
+// ParseHelp parses Cobra's default help output for a single command.
+// It returns an error if the output has no "Usage:" section.
+func ParseHelp(output string) (*HelpPage, error) {
+	lines := strings.Split(strings.ReplaceAll(output, "\r\n", "\n"), "\n")
+
+	usageIndex := -1
+	for i, line := range lines {
+		if line == "Usage:" {
+			usageIndex = i
+			break
+		}
+	}
+	if usageIndex == -1 {
+		return nil, fmt.Errorf("output is not Cobra help: no 'Usage:' section found")
+	}
+
+	page := &HelpPage{
+		Description: strings.TrimSpace(strings.Join(lines[:usageIndex], "\n")),
+	}
+
+	for _, section := range splitSections(lines[usageIndex:]) {
+		switch section.header {
+		case "Usage":
+			parseUsage(page, section.lines)
+		case "Aliases":
+			if len(section.lines) > 0 {
+				names := strings.Split(strings.TrimSpace(section.lines[0]), ",")
+				for _, alias := range names[1:] {
+					page.Aliases = append(page.Aliases, strings.TrimSpace(alias))
+				}
+			}
+		case "Examples":
+			page.Example = strings.TrimRight(strings.Join(section.lines, "\n"), "\n ")
+		case "Flags":
+			page.Flags = parseFlagLines(section.lines)
+		case "Global Flags":
+			page.GlobalFlags = parseFlagLines(section.lines)
+		case "Additional help topics":
+			// Help topics are non-runnable commands without subcommands; they
+			// are not part of the command tree.
+		default:
+			// "Available Commands", "Additional Commands" or a command group title
+			page.Commands = append(page.Commands, parseCommandLines(section.lines)...)
+		}
+	}
+
+	return page, nil
+}
+
+// helpSection is a titled section of help output
+type helpSection struct {
+	header string
+	lines  []string
+}
+
+// splitSections splits help output starting at "Usage:" into sections. A
+// section header is an unindented line ending in ':' that starts the output
+// or follows a blank line.
+func splitSections(lines []string) []helpSection {
+	var sections []helpSection
+	for i, line := range lines {
+		isHeader := line != "" && line[0] != ' ' && strings.HasSuffix(line, ":") &&
+			(i == 0 || lines[i-1] == "")
+		if isHeader {
+			sections = append(sections, helpSection{header: strings.TrimSuffix(line, ":")})
+			continue
+		}
+		// Skip the trailing 'Use "myapp [command] --help" for more information' hint
+		if strings.HasPrefix(line, `Use "`) && strings.HasSuffix(line, "for more information about a command.") {
+			continue
+		}
+		if len(sections) > 0 {
+			current := &sections[len(sections)-1]
+			current.lines = append(current.lines, line)
+		}
+	}
+
+	// Drop the blank lines that separate sections
+	for i := range sections {
+		content := sections[i].lines
+		for len(content) > 0 && strings.TrimSpace(content[len(content)-1]) == "" {
+			content = content[:len(content)-1]
+		}
+		sections[i].lines = content
+	}
+	return sections
+}
+
+// parseUsage extracts the use line and command path from the Usage section
+func parseUsage(page *HelpPage, lines []string) {
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if path, ok := strings.CutSuffix(line, " [command]"); ok {
+			page.CommandPath = path
+			continue
+		}
+		page.UseLine = line
+	}
+
+	if page.CommandPath == "" && page.UseLine != "" {
+		// The use line is the command path followed by the rest of cobra.Command.Use
+		page.CommandPath = strings.TrimSuffix(page.UseLine, " [flags]")
+	}
+}
+
+// parseCommandLines parses "  name   Short description" subcommand listings
+func parseCommandLines(lines []string) []HelpCommand {
+	var commands []HelpCommand
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || !strings.HasPrefix(line, "  ") {
+			continue
+		}
+		commands = append(commands, HelpCommand{
+			Name:  fields[0],
+			Short: strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), fields[0])),
+		})
+	}
+	return commands
+}
+
+// parseFlagLines parses pflag usage lines. Usage strings that span several
+// lines are continued on indented lines that do not start with a flag.
+func parseFlagLines(lines []string) []InspectedFlag {
+	var flags []InspectedFlag
+	for _, line := range lines {
+		match := flagLinePattern.FindStringSubmatch(line)
+		if match == nil {
+			if len(flags) > 0 && strings.TrimSpace(line) != "" {
+				last := &flags[len(flags)-1]
+				last.Usage += "\n" + strings.TrimSpace(line)
+			}
+			continue
+		}
+
+		flagType, known := helpTypeNames[match[3]]
+		if !known {
+			flagType = match[3]
+		}
+		flags = append(flags, InspectedFlag{
+			Name:      match[2],
+			Shorthand: match[1],
+			Usage:     strings.TrimRight(match[4], " "),
+			Type:      flagType,
+		})
+	}
+
+	// Defaults are appended after the (possibly multi-line) usage
+	for i := range flags {
+		flags[i].Usage, flags[i].Default = splitDefault(flags[i].Usage, flags[i].Type)
+	}
+	return flags
+}
+
+// splitDefault separates the " (default x)" suffix from a flag's usage. pflag
+// only prints non-zero defaults, so the zero value for the type is returned
+// when there is no suffix.
+func splitDefault(usage, flagType string) (string, string) {
+	match := defaultSuffixPattern.FindStringSubmatch(usage)
+	if match == nil {
+		return usage, zeroDefault(flagType)
+	}
+
+	defaultValue := match[2]
+	if flagType == "string" {
+		if unquoted, err := strconv.Unquote(defaultValue); err == nil {
+			defaultValue = unquoted
+		}
+	}
+	return match[1], defaultValue
+}
+
+// zeroDefault returns the default value pflag reports for a zero-valued flag
+func zeroDefault(flagType string) string {
+	switch flagType {
+	case "bool":
+		return "false"
+	case "int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64",
+		"float32", "float64", "count":
+		return "0"
+	case "duration":
+		return "0s"
+	}
+	if strings.HasSuffix(flagType, "Slice") || strings.HasSuffix(flagType, "Array") ||
+		strings.HasPrefix(flagType, "stringTo") {
+		return "[]"
+	}
+	return ""
+}
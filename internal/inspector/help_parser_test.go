@@ -0,0 +1,132 @@
+package inspector
+
+import (
+	"reflect"
+	"testing"
+)
+
+const serveHelp = `Start the HTTP server.
+Listens on the given port until interrupted.
+
+Usage:
+  myapp serve [flags]
+  myapp serve [command]
+
+Aliases:
+  serve, s, run
+
+Examples:
+  myapp serve --port 9090
+
+Available Commands:
+  status      Check server status
+
+Flags:
+  -b, --bind string            Address to bind (default "localhost")
+  -h, --help                   help for serve
+      --origins strings        Allowed CORS origins
+  -p, --port int               Port number (default 8080)
+      --read-timeout duration   Read timeout
+                                applied per request (default 30s)
+      --tls                    Enable TLS
+
+Global Flags:
+  -v, --verbose   Verbose output
+
+Use "myapp serve [command] --help" for more information about a command.
+`
+
+func TestParseHelp(t *testing.T) {
+	page, err := ParseHelp(serveHelp)
+	if err != nil {
+		t.Fatalf("ParseHelp() error = %v", err)
+	}
+
+	want := &HelpPage{
+		Description: "Start the HTTP server.\nListens on the given port until interrupted.",
+		UseLine:     "myapp serve [flags]",
+		CommandPath: "myapp serve",
+		Aliases:     []string{"s", "run"},
+		Example:     "  myapp serve --port 9090",
+		Commands:    []HelpCommand{{Name: "status", Short: "Check server status"}},
+		Flags: []InspectedFlag{
+			{Name: "bind", Shorthand: "b", Type: "string", Usage: "Address to bind", Default: "localhost"},
+			{Name: "help", Shorthand: "h", Type: "bool", Usage: "help for serve", Default: "false"},
+			{Name: "origins", Type: "stringSlice", Usage: "Allowed CORS origins", Default: "[]"},
+			{Name: "port", Shorthand: "p", Type: "int", Usage: "Port number", Default: "8080"},
+			{Name: "read-timeout", Type: "duration", Usage: "Read timeout\napplied per request", Default: "30s"},
+			{Name: "tls", Type: "bool", Usage: "Enable TLS", Default: "false"},
+		},
+		GlobalFlags: []InspectedFlag{
+			{Name: "verbose", Shorthand: "v", Type: "bool", Usage: "Verbose output", Default: "false"},
+		},
+	}
+	if !reflect.DeepEqual(page, want) {
+		t.Errorf("ParseHelp() =\n%+v\nwant:\n%+v", page, want)
+	}
+}
+
+func TestParseHelp_Groups(t *testing.T) {
+	output := `Manage the cluster
+
+Usage:
+  kubeish [command]
+
+Basic Commands:
+  create      Create a resource
+  get         Display resources
+
+Additional Commands:
+  completion  Generate the autocompletion script for the specified shell
+  help        Help about any command
+
+Flags:
+  -h, --help   help for kubeish
+`
+	page, err := ParseHelp(output)
+	if err != nil {
+		t.Fatalf("ParseHelp() error = %v", err)
+	}
+
+	if page.UseLine != "" || page.CommandPath != "kubeish" {
+		t.Errorf("UseLine = %q, CommandPath = %q", page.UseLine, page.CommandPath)
+	}
+	var names []string
+	for _, cmd := range page.Commands {
+		names = append(names, cmd.Name)
+	}
+	if want := []string{"create", "get", "completion", "help"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Commands = %v, want %v", names, want)
+	}
+}
+
+func TestParseHelp_NotCobra(t *testing.T) {
+	if _, err := ParseHelp("usage: myapp [-h] [--port PORT]\n"); err == nil {
+		t.Error("ParseHelp() expected error for non-Cobra help output")
+	}
+}
+
+func TestSplitDefault(t *testing.T) {
+	tests := []struct {
+		usage       string
+		flagType    string
+		wantUsage   string
+		wantDefault string
+	}{
+		{`Name (default "a \"b\"")`, "string", "Name", `a "b"`},
+		{"Level (default 3)", "count", "Level", "3"},
+		{"IDs (default [1,2])", "intSlice", "IDs", "[1,2]"},
+		{"Limit", "uint", "Limit", "0"},
+		{"Labels", "stringToString", "Labels", "[]"},
+		{"Address", "ip", "Address", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.usage, func(t *testing.T) {
+			usage, def := splitDefault(tt.usage, tt.flagType)
+			if usage != tt.wantUsage || def != tt.wantDefault {
+				t.Errorf("splitDefault() = %q, %q, want %q, %q", usage, def, tt.wantUsage, tt.wantDefault)
+			}
+		})
+	}
+}
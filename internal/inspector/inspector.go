@@ -10,8 +10,13 @@ const inspectorTemplate = `package main
 import (
 	"encoding/json"
 	"fmt"
+	{{- if .VersionOutput }}
+	"io"
+	{{- end }}
 	"os"
 	"reflect"
+	"sort"
+	"strings"
 	
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -30,6 +35,10 @@ type InspectedCLI struct {
 	Long     string              ` + "`json:\"long,omitempty\"`" + `
 	Aliases  []string            ` + "`json:\"aliases,omitempty\"`" + `
 	Example  string              ` + "`json:\"example,omitempty\"`" + `
+	Version  string              ` + "`json:\"version,omitempty\"`" + `
+	VersionOutput string         ` + "`json:\"version_output,omitempty\"`" + `
+	MutuallyExclusive [][]string ` + "`json:\"mutually_exclusive,omitempty\"`" + `
+	RequiredTogether  [][]string ` + "`json:\"required_together,omitempty\"`" + `
 	Flags    []InspectedFlag     ` + "`json:\"flags,omitempty\"`" + `
 	Commands []InspectedCommand  ` + "`json:\"commands,omitempty\"`" + `
 }
@@ -40,6 +49,11 @@ type InspectedCommand struct {
 	Long     string              ` + "`json:\"long,omitempty\"`" + `
 	Aliases  []string            ` + "`json:\"aliases,omitempty\"`" + `
 	Example  string              ` + "`json:\"example,omitempty\"`" + `
+	Hidden   bool                ` + "`json:\"hidden,omitempty\"`" + `
+	Runnable bool                ` + "`json:\"runnable,omitempty\"`" + `
+	GroupID  string              ` + "`json:\"group_id,omitempty\"`" + `
+	MutuallyExclusive [][]string ` + "`json:\"mutually_exclusive,omitempty\"`" + `
+	RequiredTogether  [][]string ` + "`json:\"required_together,omitempty\"`" + `
 	Flags    []InspectedFlag     ` + "`json:\"flags,omitempty\"`" + `
 	Commands []InspectedCommand  ` + "`json:\"commands,omitempty\"`" + `
 }
@@ -50,6 +64,12 @@ type InspectedFlag struct {
 	Usage      string ` + "`json:\"usage\"`" + `
 	Type       string ` + "`json:\"type\"`" + `
 	Persistent bool   ` + "`json:\"persistent\"`" + `
+	Completion string ` + "`json:\"completion,omitempty\"`" + `
+	Default    string ` + "`json:\"default,omitempty\"`" + `
+	Required   bool   ` + "`json:\"required,omitempty\"`" + `
+	Enum       []string ` + "`json:\"enum,omitempty\"`" + `
+	Deprecated string ` + "`json:\"deprecated,omitempty\"`" + `
+	Hidden     bool   ` + "`json:\"hidden,omitempty\"`" + `
 }
 
 func main() {
@@ -74,6 +94,12 @@ func main() {
 
 	// Inspect the command tree
 	cli := inspectCommand(rootCmd)
+	{{- if .VersionOutput }}
+
+	// Run the version command only now: executing the root command adds
+	// Cobra's help and completion commands to the tree
+	cli.VersionOutput = runVersion(rootCmd, []string{ {{- range .VersionArgs }}{{ printf "%q" . }}, {{ end -}} })
+	{{- end }}
 	
 	// Output as JSON
 	encoder := json.NewEncoder(os.Stdout)
@@ -97,13 +123,17 @@ func inspectCommand(cmd *cobra.Command) InspectedCLI {
 		Long:    cmd.Long,
 		Aliases: cmd.Aliases,
 		Example: cmd.Example,
+		Version: cmd.Version,
 	}
+	cli.MutuallyExclusive = getFlagGroups(cmd, mutuallyExclusiveAnnotation)
+	cli.RequiredTogether = getFlagGroups(cmd, requiredTogetherAnnotation)
 	
-	// Inspect local flags
-	localFlags := inspectFlagSet(cmd.Flags(), false)
+	// Inspect local flags. Marking flag groups merges the persistent flags
+	// into cmd.Flags(), so they are left out here.
+	localFlags := inspectFlagSet(cmd, cmd.LocalNonPersistentFlags(), false)
 	
 	// Inspect persistent flags
-	persistentFlags := inspectFlagSet(cmd.PersistentFlags(), true)
+	persistentFlags := inspectFlagSet(cmd, cmd.PersistentFlags(), true)
 	
 	// Combine flags, avoiding duplicates
 	flagMap := make(map[string]InspectedFlag)
@@ -121,17 +151,58 @@ func inspectCommand(cmd *cobra.Command) InspectedCLI {
 		cli.Flags = append(cli.Flags, f)
 	}
 	
-	// Inspect subcommands
+	// Inspect subcommands, including hidden ones (they are marked as such)
 	for _, subcmd := range cmd.Commands() {
-		if subcmd.Hidden {
-			continue
-		}
 		cli.Commands = append(cli.Commands, inspectSubcommand(subcmd))
 	}
 	
 	return cli
 }
 
+{{- if .VersionOutput }}
+
+// runVersion runs the root command with args and returns what it printed.
+// Without args, it runs --version if the command has a version, and its
+// version subcommand otherwise.
+func runVersion(rootCmd *cobra.Command, args []string) string {
+	if len(args) == 0 {
+		if rootCmd.Version != "" {
+			args = []string{"--version"}
+		} else {
+			for _, cmd := range rootCmd.Commands() {
+				if cmd.Name() == "version" {
+					args = []string{"version"}
+				}
+			}
+		}
+	}
+	if len(args) == 0 {
+		return ""
+	}
+
+	// Version commands often print with fmt, so capture os.Stdout as well
+	// as the command's output, keeping stdout for the JSON
+	r, w, err := os.Pipe()
+	if err != nil {
+		return ""
+	}
+	output := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		output <- string(data)
+	}()
+	stdout := os.Stdout
+	os.Stdout = w
+	rootCmd.SetOut(w)
+	rootCmd.SetErr(w)
+	rootCmd.SetArgs(args)
+	_ = rootCmd.Execute()
+	os.Stdout = stdout
+	_ = w.Close()
+	return <-output
+}
+{{- end }}
+
 func inspectSubcommand(cmd *cobra.Command) InspectedCommand {
 	command := InspectedCommand{
 		Use:     cmd.Use,
@@ -139,29 +210,41 @@ func inspectSubcommand(cmd *cobra.Command) InspectedCommand {
 		Long:    cmd.Long,
 		Aliases: cmd.Aliases,
 		Example: cmd.Example,
+		Hidden:  cmd.Hidden,
 	}
+	command.Runnable = cmd.Run != nil || cmd.RunE != nil ||
+		cmd.PreRun != nil || cmd.PreRunE != nil ||
+		cmd.PostRun != nil || cmd.PostRunE != nil
+	{{- if .ModernCobra }}
+	command.GroupID = cmd.GroupID
+	{{- end }}
+	command.MutuallyExclusive = getFlagGroups(cmd, mutuallyExclusiveAnnotation)
+	command.RequiredTogether = getFlagGroups(cmd, requiredTogetherAnnotation)
 	
-	// Inspect local flags only (persistent flags are inherited)
-	command.Flags = inspectFlagSet(cmd.Flags(), false)
+	// Inspect local flags only (persistent flags are inherited). Marking
+	// flag groups merges the inherited flags into cmd.Flags().
+	command.Flags = inspectFlagSet(cmd, cmd.LocalNonPersistentFlags(), false)
 	
 	// Inspect subcommands
 	for _, subcmd := range cmd.Commands() {
-		if subcmd.Hidden {
-			continue
-		}
 		command.Commands = append(command.Commands, inspectSubcommand(subcmd))
 	}
 	
 	return command
 }
 
-func inspectFlagSet(flags *pflag.FlagSet, persistent bool) []InspectedFlag {
+func inspectFlagSet(cmd *cobra.Command, flags *pflag.FlagSet, persistent bool) []InspectedFlag {
 	var inspectedFlags []InspectedFlag
 	
 	flags.VisitAll(func(flag *pflag.Flag) {
-		if flag.Hidden {
+		// MarkDeprecated also hides a flag, but it still works and is part
+		// of the CLI until it is removed
+		hidden := flag.Hidden && flag.Deprecated == ""
+		{{- if not .HiddenFlags }}
+		if hidden {
 			return
 		}
+		{{- end }}
 		
 		inspectedFlag := InspectedFlag{
 			Name:       flag.Name,
@@ -169,6 +252,12 @@ func inspectFlagSet(flags *pflag.FlagSet, persistent bool) []InspectedFlag {
 			Usage:      flag.Usage,
 			Type:       getFlagType(flag),
 			Persistent: persistent,
+			Completion: getFlagCompletion(cmd, flag),
+			Default:    flag.DefValue,
+			Required:   isFlagRequired(flag),
+			Enum:       getFlagEnum(cmd, flag),
+			Deprecated: flag.Deprecated,
+			Hidden:     hidden,
 		}
 		
 		inspectedFlags = append(inspectedFlags, inspectedFlag)
@@ -177,6 +266,109 @@ func inspectFlagSet(flags *pflag.FlagSet, persistent bool) []InspectedFlag {
 	return inspectedFlags
 }
 
+// The flag annotations Cobra records flag groups in. Each value of the
+// annotation is one group, with its flag names joined by spaces. They are
+// unexported in Cobra, and absent before Cobra v1.5.0.
+const (
+	mutuallyExclusiveAnnotation = "cobra_annotation_mutually_exclusive"
+	requiredTogetherAnnotation  = "cobra_annotation_required_if_others_set"
+)
+
+// getFlagGroups returns the flag groups cmd marked with the annotation, each
+// sorted by flag name, in sorted order. A persistent flag is shared with the
+// subcommands, and so are its annotations: groups with flags cmd doesn't
+// have were marked by a subcommand, and groups of inherited flags only are
+// recorded on the command that defines the flags, like the flags themselves.
+func getFlagGroups(cmd *cobra.Command, annotation string) [][]string {
+	hasFlag := func(name string) bool {
+		return cmd.Flags().Lookup(name) != nil || cmd.PersistentFlags().Lookup(name) != nil
+	}
+	inherited := cmd.InheritedFlags()
+	seen := make(map[string]bool)
+	var groups [][]string
+	visit := func(flag *pflag.Flag) {
+		for _, value := range flag.Annotations[annotation] {
+			group := strings.Fields(value)
+			sort.Strings(group)
+			key := strings.Join(group, " ")
+			if len(group) == 0 || seen[key] {
+				continue
+			}
+			seen[key] = true
+			owned, local := true, false
+			for _, name := range group {
+				owned = owned && hasFlag(name)
+				local = local || inherited.Lookup(name) == nil
+			}
+			if !owned || !local {
+				continue
+			}
+			groups = append(groups, group)
+		}
+	}
+	cmd.Flags().VisitAll(visit)
+	cmd.PersistentFlags().VisitAll(visit)
+	sort.Slice(groups, func(i, j int) bool {
+		return strings.Join(groups[i], " ") < strings.Join(groups[j], " ")
+	})
+	return groups
+}
+
+func isFlagRequired(flag *pflag.Flag) bool {
+	required, ok := flag.Annotations[cobra.BashCompOneRequiredFlag]
+	return ok && len(required) > 0 && required[0] == "true"
+}
+
+func getFlagEnum(cmd *cobra.Command, flag *pflag.Flag) []string {
+	{{- if .CompletionValues }}
+	completionFunc, ok := cmd.GetFlagCompletionFunc(flag.Name)
+	if !ok {
+		return nil
+	}
+	var completions []string
+	var directive cobra.ShellCompDirective
+	func() {
+		// Completion functions are written for shell sessions and may not
+		// cope with an empty command line
+		defer func() { _ = recover() }()
+		completions, directive = completionFunc(cmd, []string{}, "")
+	}()
+	// These directives return file extensions or directories, not values
+	if directive&(cobra.ShellCompDirectiveFilterFileExt|cobra.ShellCompDirectiveFilterDirs) != 0 {
+		return nil
+	}
+	var values []string
+	for _, completion := range completions {
+		// Strip the description after the tab
+		value, _, _ := strings.Cut(completion, "\t")
+		if value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+	{{- else }}
+	return nil
+	{{- end }}
+}
+
+func getFlagCompletion(cmd *cobra.Command, flag *pflag.Flag) string {
+	{{- if .FlagCompletionLookup }}
+	if _, ok := cmd.GetFlagCompletionFunc(flag.Name); ok {
+		return "custom"
+	}
+	{{- end }}
+	if _, ok := flag.Annotations[cobra.BashCompSubdirsInDir]; ok {
+		return "dir"
+	}
+	if _, ok := flag.Annotations[cobra.BashCompFilenameExt]; ok {
+		return "file"
+	}
+	if _, ok := flag.Annotations[cobra.BashCompCustom]; ok {
+		return "custom"
+	}
+	return ""
+}
+
 func getFlagType(flag *pflag.Flag) string {
 	// Get the type from the flag's value
 	flagType := reflect.TypeOf(flag.Value).String()
@@ -214,6 +406,7 @@ func getFlagType(flag *pflag.Flag) string {
 		"*pflag.float64SliceValue": "float64Slice",
 		"*pflag.boolSliceValue":    "boolSlice",
 		"*pflag.durationSliceValue": "durationSlice",
+		"*pflag.stringArrayValue":   "stringArray",
 		
 		// Map types
 		"*pflag.stringToStringValue": "stringToString",
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"path/filepath"
 	"strings"
 	"text/template"
@@ -21,8 +22,57 @@ type Config struct {
 	Timeout     time.Duration
 	FileSystem  filesystem.FileSystem
 	Executor    executor.CommandExecutor
+
+	// CobraVersion is the Cobra version used by the target project. Features
+	// added in ModernCobraVersion are only extracted for versions at least as
+	// new. If zero, the version is detected from the project's go.mod/go.sum.
+	CobraVersion Version
+
+	// CompletionValues runs each flag's registered completion function and
+	// records the values it returns in InspectedFlag.Enum. Completion
+	// functions are the target project's code and may have side effects, so
+	// they are only run when asked for. Requires FlagCompletionLookupVersion.
+	CompletionValues bool
+
+	// HiddenFlags inspects the flags hidden with MarkHidden too, marked
+	// InspectedFlag.Hidden. By default they are left out.
+	HiddenFlags bool
+
+	// VersionOutput runs the root command, once inspected, to print its
+	// version and records what it printed in InspectedCLI.VersionOutput.
+	// The command is run with VersionArgs, or without them, with --version
+	// if it has a version (cobra.Command.Version) and with its version
+	// subcommand otherwise. Like CompletionValues, this runs the target
+	// project's code, so it is only done when asked for.
+	VersionOutput bool
+
+	// VersionArgs are the arguments the root command is run with for
+	// VersionOutput, e.g. []string{"version", "--short"}
+	VersionArgs []string
+
+	// Logger receives progress messages: each phase of Inspect at debug
+	// level, and the time inspection took at info level. Defaults to
+	// slog.Default().
+	Logger *slog.Logger
+
+	// OnPhase, if set, is called with the time each phase of Inspect took,
+	// as it finishes. Phases that fail are not reported.
+	OnPhase func(phase string, elapsed time.Duration)
 }
 
+// Phases of Inspect reported to Config.OnPhase
+const (
+	// PhaseBuild sets up the temporary inspector module and resolves its
+	// dependencies
+	PhaseBuild = "go_build"
+
+	// PhaseRun compiles and runs the inspector program
+	PhaseRun = "go_run_inspector"
+
+	// PhaseParse decodes the inspector's JSON output
+	PhaseParse = "json_parse"
+)
+
 // Inspector provides CLI inspection functionality
 type Inspector struct {
 	config Config
@@ -37,6 +87,9 @@ func NewInspector(config Config) *Inspector {
 	if config.Executor == nil {
 		config.Executor = &executor.OSExecutor{}
 	}
+	if config.Logger == nil {
+		config.Logger = slog.Default()
+	}
 
 	// Wrap executor with timeout if specified
 	if config.Timeout > 0 {
@@ -58,7 +111,12 @@ type EntrypointInfo struct {
 
 // Inspect generates an inspector program and runs it to get the CLI structure
 func (i *Inspector) Inspect() (*InspectedCLI, error) {
+	start := time.Now()
+	phaseStart := start
+	logger := i.logger()
+
 	// Create a temporary directory for the inspector
+	logger.Debug("creating temp dir")
 	tempDir, err := i.config.FileSystem.MkdirTemp("", "cliguard-inspector-*")
 	if err != nil {
 		return nil, errors.TempDirError{
@@ -76,12 +134,21 @@ func (i *Inspector) Inspect() (*InspectedCLI, error) {
 		return nil, err // parseEntrypoint already returns proper error
 	}
 
+	// Detect the Cobra version if it wasn't specified
+	if i.config.CobraVersion.IsZero() {
+		if version, err := detectCobraVersion(i.config.FileSystem, i.config.ProjectPath); err == nil {
+			i.config.CobraVersion = version
+			logger.Debug("detected Cobra version", "version", version.String())
+		}
+	}
+
 	// Setup the temporary module
 	if err := i.setupTempModule(tempDir, entrypointInfo); err != nil {
 		return nil, fmt.Errorf("failed to setup temp module: %w", err)
 	}
 
 	// Generate the inspector code
+	logger.Debug("writing inspector code", "dir", tempDir)
 	inspectorCode, err := i.generateInspectorCode(entrypointInfo)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate inspector code: %w", err)
@@ -97,18 +164,26 @@ func (i *Inspector) Inspect() (*InspectedCLI, error) {
 	if err := i.getDependencies(tempDir); err != nil {
 		return nil, err // getDependencies already returns proper error
 	}
+	phaseStart = i.endPhase(PhaseBuild, phaseStart)
 
 	// Run the inspector
 	output, err := i.runInspector(tempDir)
 	if err != nil {
 		return nil, fmt.Errorf("failed to run inspector: %w", err)
 	}
+	phaseStart = i.endPhase(PhaseRun, phaseStart)
 
 	// Parse the output
 	inspectedCLI, err := i.parseInspectorOutput(output)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse inspector output: %w", err)
 	}
+	i.endPhase(PhaseParse, phaseStart)
+
+	logger.Info("inspected CLI",
+		"entrypoint", i.config.Entrypoint,
+		"commands", len(inspectedCLI.Commands),
+		"duration", time.Since(start))
 	return inspectedCLI, nil
 }
 
@@ -144,51 +219,71 @@ func (i *Inspector) parseEntrypoint(entrypoint string) (*EntrypointInfo, error)
 // setupTempModule sets up the temporary Go module
 func (i *Inspector) setupTempModule(tempDir string, info *EntrypointInfo) error {
 	// Initialize go module
+	i.logger().Debug("running go mod init", "dir", tempDir)
 	initCmd := i.config.Executor.Command("go", "mod", "init", "cliguard-inspector")
 	initCmd.SetDir(tempDir)
 	if output, err := initCmd.CombinedOutput(); err != nil {
 		return fmt.Errorf("failed to init module: %w\nOutput: %s", err, output)
 	}
 
-	// Handle imports based on the entrypoint
+	if !info.IsMainPackage && info.ImportPath == "" {
+		return nil
+	}
+
+	srcGoMod := filepath.Join(i.config.ProjectPath, "go.mod")
+	if _, err := i.config.FileSystem.Stat(srcGoMod); err != nil {
+		return nil
+	}
+	modContent, err := i.config.FileSystem.ReadFile(srcGoMod)
+	if err != nil {
+		return fmt.Errorf("failed to read target go.mod: %w", err)
+	}
+
+	moduleName := getModuleName(modContent)
+	if moduleName == "" {
+		return nil
+	}
 	if info.IsMainPackage {
-		// For main package, we need to handle it specially
-		srcGoMod := filepath.Join(i.config.ProjectPath, "go.mod")
-		if _, err := i.config.FileSystem.Stat(srcGoMod); err == nil {
-			modContent, err := i.config.FileSystem.ReadFile(srcGoMod)
-			if err != nil {
-				return fmt.Errorf("failed to read target go.mod: %w", err)
-			}
-
-			moduleName := getModuleName(modContent)
-			if moduleName != "" {
-				info.ImportPath = moduleName
-				info.ImportAlias = "userPkg"
-
-				// Add replace directive
-				if err := i.addReplaceDirective(tempDir, moduleName); err != nil {
-					return fmt.Errorf("failed to add replace directive: %w", err)
-				}
-			}
+		// For main package, import the module root
+		info.ImportPath = moduleName
+		info.ImportAlias = "userPkg"
+	}
+
+	// Point the target module at the project directory
+	if err := i.addReplaceDirective(tempDir, moduleName); err != nil {
+		return fmt.Errorf("failed to add replace directive: %w", err)
+	}
+
+	// Replace directives only apply in the main module, so the project's own
+	// ones (e.g. for local forks) must be copied for its dependencies to resolve
+	for _, directive := range extractReplaceDirectives(modContent) {
+		if directive.OldPath == moduleName {
+			continue
 		}
-	} else if info.ImportPath != "" {
-		// For non-main packages, add replace directive
-		srcGoMod := filepath.Join(i.config.ProjectPath, "go.mod")
-		if _, err := i.config.FileSystem.Stat(srcGoMod); err == nil {
-			modContent, err := i.config.FileSystem.ReadFile(srcGoMod)
-			if err != nil {
-				return fmt.Errorf("failed to read target go.mod: %w", err)
-			}
-
-			moduleName := getModuleName(modContent)
-			if moduleName != "" {
-				if err := i.addReplaceDirective(tempDir, moduleName); err != nil {
-					return fmt.Errorf("failed to add replace directive: %w", err)
-				}
-			}
+		if err := i.copyReplaceDirective(tempDir, directive); err != nil {
+			return err
 		}
 	}
 
+	// The project's go.sum already has the checksums of its dependencies,
+	// so go get doesn't have to download them again to verify them
+	if err := i.copyGoSum(tempDir); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// copyGoSum copies the target project's go.sum, if it has one, to the temp module
+func (i *Inspector) copyGoSum(tempDir string) error {
+	srcGoSum := filepath.Join(i.config.ProjectPath, "go.sum")
+	if _, err := i.config.FileSystem.Stat(srcGoSum); err != nil {
+		return nil
+	}
+	i.logger().Debug("copying go.sum", "from", srcGoSum)
+	if err := i.config.FileSystem.CopyFile(srcGoSum, filepath.Join(tempDir, "go.sum")); err != nil {
+		return fmt.Errorf("failed to copy go.sum: %w", err)
+	}
 	return nil
 }
 
@@ -204,6 +299,7 @@ func (i *Inspector) addReplaceDirective(tempDir, moduleName string) error {
 		}
 	}
 
+	i.logger().Debug("adding replace directive", "module", moduleName, "path", absProjectPath)
 	replaceCmd := i.config.Executor.Command("go", "mod", "edit", "-replace",
 		fmt.Sprintf("%s=%s", moduleName, absProjectPath))
 	replaceCmd.SetDir(tempDir)
@@ -213,6 +309,27 @@ func (i *Inspector) addReplaceDirective(tempDir, moduleName string) error {
 	return nil
 }
 
+// copyReplaceDirective adds one of the target project's replace directives
+// to go.mod, making local replacement paths absolute since they are relative
+// to the project rather than the temp module
+func (i *Inspector) copyReplaceDirective(tempDir string, directive ReplaceDirective) error {
+	if directive.IsLocal() && !filepath.IsAbs(directive.NewPath) {
+		absPath, err := filepath.Abs(filepath.Join(i.config.ProjectPath, directive.NewPath))
+		if err != nil {
+			return fmt.Errorf("failed to get absolute path: %w", err)
+		}
+		directive.NewPath = absPath
+	}
+
+	i.logger().Debug("copying replace directive", "module", directive.OldPath, "replacement", directive.NewPath)
+	replaceCmd := i.config.Executor.Command("go", "mod", "edit", "-replace", directive.editArg())
+	replaceCmd.SetDir(tempDir)
+	if output, err := replaceCmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to copy replace directive for %s: %w\nOutput: %s", directive.OldPath, err, output)
+	}
+	return nil
+}
+
 // generateInspectorCode generates the inspector Go code
 func (i *Inspector) generateInspectorCode(info *EntrypointInfo) (string, error) {
 	tmpl, err := template.New("inspector").Parse(inspectorTemplate)
@@ -222,13 +339,25 @@ func (i *Inspector) generateInspectorCode(info *EntrypointInfo) (string, error)
 
 	var buf bytes.Buffer
 	err = tmpl.Execute(&buf, struct {
-		ImportPath     string
-		ImportAlias    string
-		EntrypointFunc string
+		ImportPath           string
+		ImportAlias          string
+		EntrypointFunc       string
+		ModernCobra          bool
+		FlagCompletionLookup bool
+		CompletionValues     bool
+		HiddenFlags          bool
+		VersionOutput        bool
+		VersionArgs          []string
 	}{
-		ImportPath:     info.ImportPath,
-		ImportAlias:    info.ImportAlias,
-		EntrypointFunc: info.FunctionName,
+		ImportPath:           info.ImportPath,
+		ImportAlias:          info.ImportAlias,
+		EntrypointFunc:       info.FunctionName,
+		ModernCobra:          i.config.CobraVersion.AtLeast(ModernCobraVersion),
+		FlagCompletionLookup: i.config.CobraVersion.AtLeast(FlagCompletionLookupVersion),
+		CompletionValues:     i.config.CompletionValues && i.config.CobraVersion.AtLeast(FlagCompletionLookupVersion),
+		HiddenFlags:          i.config.HiddenFlags,
+		VersionOutput:        i.config.VersionOutput,
+		VersionArgs:          i.config.VersionArgs,
 	})
 	if err != nil {
 		return "", fmt.Errorf("failed to execute template: %w", err)
@@ -240,6 +369,7 @@ func (i *Inspector) generateInspectorCode(info *EntrypointInfo) (string, error)
 // getDependencies gets the Go module dependencies
 func (i *Inspector) getDependencies(tempDir string) error {
 	// Try to run go mod tidy with -e flag to ignore errors
+	i.logger().Debug("running go mod tidy", "dir", tempDir)
 	tidyCmd := i.config.Executor.Command("go", "mod", "tidy", "-e")
 	tidyCmd.SetDir(tempDir)
 	if _, err := tidyCmd.CombinedOutput(); err == nil {
@@ -247,6 +377,7 @@ func (i *Inspector) getDependencies(tempDir string) error {
 	}
 
 	// Fall back to regular go get
+	i.logger().Debug("go mod tidy failed, running go get", "dir", tempDir)
 	getCmd := i.config.Executor.Command("go", "get", "./...")
 	getCmd.SetDir(tempDir)
 	if output, err := getCmd.CombinedOutput(); err != nil {
@@ -261,6 +392,7 @@ func (i *Inspector) getDependencies(tempDir string) error {
 
 // runInspector runs the inspector program
 func (i *Inspector) runInspector(tempDir string) ([]byte, error) {
+	i.logger().Debug("running inspector", "dir", tempDir)
 	runCmd := i.config.Executor.Command("go", "run", "inspector.go")
 	runCmd.SetDir(tempDir)
 	output, err := runCmd.Output()
@@ -278,3 +410,22 @@ func (i *Inspector) parseInspectorOutput(output []byte) (*InspectedCLI, error) {
 	}
 	return &cli, nil
 }
+
+// endPhase reports the time since start to Config.OnPhase, and returns the
+// start of the next phase
+func (i *Inspector) endPhase(phase string, start time.Time) time.Time {
+	now := time.Now()
+	if i.config.OnPhase != nil {
+		i.config.OnPhase(phase, now.Sub(start))
+	}
+	return now
+}
+
+// logger returns the configured logger, or slog.Default() for inspectors not
+// created with NewInspector
+func (i *Inspector) logger() *slog.Logger {
+	if i.config.Logger == nil {
+		return slog.Default()
+	}
+	return i.config.Logger
+}
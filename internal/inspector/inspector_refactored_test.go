@@ -1,10 +1,14 @@
 package inspector
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/hiAndrewQuinn/cliguard/internal/executor"
 	"github.com/hiAndrewQuinn/cliguard/internal/filesystem"
@@ -106,6 +110,7 @@ func TestInspector_generateInspectorCode(t *testing.T) {
 					`rootCmd = userPkg.NewRootCmd()`,
 					`func inspectCommand(cmd *cobra.Command)`,
 					`encoding/json`,
+					`command.Runnable = cmd.Run != nil || cmd.RunE != nil ||`,
 				}
 				for _, expected := range expectedStrings {
 					if !contains(code, expected) {
@@ -145,6 +150,32 @@ func TestInspector_generateInspectorCode(t *testing.T) {
 	}
 }
 
+func TestInspector_generateInspectorCode_VersionOutput(t *testing.T) {
+	info := &EntrypointInfo{ImportPath: "github.com/test/repo", ImportAlias: "userPkg", FunctionName: "NewRootCmd"}
+
+	code, err := (&Inspector{}).generateInspectorCode(info)
+	if err != nil {
+		t.Fatalf("generateInspectorCode() error = %v", err)
+	}
+	if contains(code, "runVersion") {
+		t.Error("generated code should only run the version command with VersionOutput")
+	}
+
+	i := &Inspector{config: Config{VersionOutput: true, VersionArgs: []string{"version", "--short"}}}
+	code, err = i.generateInspectorCode(info)
+	if err != nil {
+		t.Fatalf("generateInspectorCode() error = %v", err)
+	}
+	for _, expected := range []string{
+		`cli.VersionOutput = runVersion(rootCmd, []string{"version", "--short", })`,
+		`func runVersion(rootCmd *cobra.Command, args []string) string`,
+	} {
+		if !contains(code, expected) {
+			t.Errorf("generated code missing: %s", expected)
+		}
+	}
+}
+
 func TestInspector_Inspect(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -337,7 +368,204 @@ func TestInspector_Inspect(t *testing.T) {
 	}
 }
 
+func TestInspector_Inspect_Logging(t *testing.T) {
+	newInspector := func(level slog.Level) (*Inspector, *bytes.Buffer) {
+		fs := filesystem.NewMockFileSystem()
+		fs.Files["/test/project/go.mod"] = []byte("module github.com/test/repo\n\ngo 1.21")
+		exec := &executor.MockExecutor{
+			Results: map[string]executor.MockResult{
+				"go mod init cliguard-inspector":                          {},
+				"go mod edit -replace github.com/test/repo=/test/project": {},
+				"go mod tidy -e":      {},
+				"go run inspector.go": {Output: []byte(`{"use": "myapp", "commands": [{"use": "serve"}]}`)},
+			},
+		}
+		var buf bytes.Buffer
+		inspector := NewInspector(Config{
+			ProjectPath: "/test/project",
+			Entrypoint:  "github.com/test/repo/cmd.NewRootCmd",
+			FileSystem:  fs,
+			Executor:    exec,
+			Logger:      slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: level})),
+		})
+		return inspector, &buf
+	}
+
+	t.Run("debug", func(t *testing.T) {
+		inspector, buf := newInspector(slog.LevelDebug)
+		if _, err := inspector.Inspect(); err != nil {
+			t.Fatalf("Inspect() error = %v", err)
+		}
+
+		for _, want := range []string{
+			`level=DEBUG msg="creating temp dir"`,
+			`level=DEBUG msg="running go mod init"`,
+			`level=DEBUG msg="writing inspector code"`,
+			`level=DEBUG msg="running inspector"`,
+			`level=INFO msg="inspected CLI" entrypoint=github.com/test/repo/cmd.NewRootCmd commands=1 duration=`,
+		} {
+			if !strings.Contains(buf.String(), want) {
+				t.Errorf("log output missing %q:\n%s", want, buf.String())
+			}
+		}
+	})
+
+	t.Run("info", func(t *testing.T) {
+		inspector, buf := newInspector(slog.LevelInfo)
+		if _, err := inspector.Inspect(); err != nil {
+			t.Fatalf("Inspect() error = %v", err)
+		}
+
+		if strings.Contains(buf.String(), "level=DEBUG") {
+			t.Errorf("log output contains debug entries at info level:\n%s", buf.String())
+		}
+		if !strings.Contains(buf.String(), `msg="inspected CLI"`) {
+			t.Errorf("log output missing the inspected CLI entry:\n%s", buf.String())
+		}
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		inspector, buf := newInspector(slog.LevelDebug)
+		inspector.config.Executor.(*executor.MockExecutor).Results["go run inspector.go"] = executor.MockResult{Error: errors.New("exit status 1")}
+		if _, err := inspector.Inspect(); err == nil {
+			t.Fatal("Inspect() error = nil, want error")
+		}
+
+		if !strings.Contains(buf.String(), `msg="running inspector"`) {
+			t.Errorf("log output missing the failed phase:\n%s", buf.String())
+		}
+		if strings.Contains(buf.String(), `msg="inspected CLI"`) {
+			t.Errorf("log output reports success for a failed inspection:\n%s", buf.String())
+		}
+	})
+}
+
+func TestInspector_Inspect_OnPhase(t *testing.T) {
+	newInspector := func(runResult executor.MockResult) (*Inspector, *[]string) {
+		fs := filesystem.NewMockFileSystem()
+		fs.Files["/test/project/go.mod"] = []byte("module github.com/test/repo\n\ngo 1.21")
+		var phases []string
+		inspector := NewInspector(Config{
+			ProjectPath: "/test/project",
+			Entrypoint:  "github.com/test/repo/cmd.NewRootCmd",
+			FileSystem:  fs,
+			Executor: &executor.MockExecutor{
+				Results: map[string]executor.MockResult{
+					"go mod init cliguard-inspector":                          {},
+					"go mod edit -replace github.com/test/repo=/test/project": {},
+					"go mod tidy -e":      {},
+					"go run inspector.go": runResult,
+				},
+			},
+			OnPhase: func(phase string, elapsed time.Duration) {
+				if elapsed < 0 {
+					t.Errorf("phase %s took %v", phase, elapsed)
+				}
+				phases = append(phases, phase)
+			},
+		})
+		return inspector, &phases
+	}
+
+	t.Run("success", func(t *testing.T) {
+		inspector, phases := newInspector(executor.MockResult{Output: []byte(`{"use": "myapp"}`)})
+		if _, err := inspector.Inspect(); err != nil {
+			t.Fatalf("Inspect() error = %v", err)
+		}
+		want := []string{PhaseBuild, PhaseRun, PhaseParse}
+		if strings.Join(*phases, ",") != strings.Join(want, ",") {
+			t.Errorf("phases = %v, want %v", *phases, want)
+		}
+	})
+
+	t.Run("failed phase is not reported", func(t *testing.T) {
+		inspector, phases := newInspector(executor.MockResult{Error: errors.New("exit status 1")})
+		if _, err := inspector.Inspect(); err == nil {
+			t.Fatal("Inspect() error = nil, want error")
+		}
+		if strings.Join(*phases, ",") != PhaseBuild {
+			t.Errorf("phases = %v, want only %s", *phases, PhaseBuild)
+		}
+	})
+}
+
 // Helper functions
+func TestInspector_setupTempModule_CopiesReplaceDirectives(t *testing.T) {
+	fs := filesystem.NewMockFileSystem()
+	fs.Files["/test/project/go.mod"] = []byte(`module github.com/test/repo
+
+replace (
+	github.com/test/repo => ./self
+	github.com/spf13/cobra => ../cobra
+	github.com/spf13/pflag v1.0.5 => github.com/fork/pflag v1.0.6
+)
+`)
+	exec := &executor.MockExecutor{
+		Results: map[string]executor.MockResult{
+			"go mod init cliguard-inspector":                                                  {},
+			"go mod edit -replace github.com/test/repo=/test/project":                         {},
+			"go mod edit -replace github.com/spf13/cobra=/test/cobra":                         {},
+			"go mod edit -replace github.com/spf13/pflag@v1.0.5=github.com/fork/pflag@v1.0.6": {},
+		},
+	}
+	inspector := NewInspector(Config{ProjectPath: "/test/project", FileSystem: fs, Executor: exec})
+
+	info := &EntrypointInfo{ImportPath: "github.com/test/repo/cmd", FunctionName: "NewRootCmd"}
+	if err := inspector.setupTempModule("/tmp/inspect", info); err != nil {
+		t.Fatalf("setupTempModule() error = %v", err)
+	}
+
+	var got []string
+	for _, cmd := range exec.Commands {
+		if cmd.Dir != "/tmp/inspect" {
+			t.Errorf("%s ran in %q, want the temp module", cmd.Name, cmd.Dir)
+		}
+		got = append(got, fmt.Sprint(cmd.Args))
+	}
+	want := []string{
+		"[mod init cliguard-inspector]",
+		"[mod edit -replace github.com/test/repo=/test/project]",
+		"[mod edit -replace github.com/spf13/cobra=/test/cobra]",
+		"[mod edit -replace github.com/spf13/pflag@v1.0.5=github.com/fork/pflag@v1.0.6]",
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("commands = %v, want %v", got, want)
+	}
+}
+
+func TestInspector_setupTempModule_CopiesGoSum(t *testing.T) {
+	goSum := []byte("github.com/spf13/cobra v1.9.1 h1:CXSaggrXdbHK9CF+8ywj8Amf7PBRmPCOJugH954Nnlo=\n")
+
+	for _, hasGoSum := range []bool{true, false} {
+		fs := filesystem.NewMockFileSystem()
+		fs.Directories["/tmp/inspect"] = true
+		fs.Files["/test/project/go.mod"] = []byte("module github.com/test/repo\n")
+		if hasGoSum {
+			fs.Files["/test/project/go.sum"] = goSum
+		}
+		exec := &executor.MockExecutor{
+			Results: map[string]executor.MockResult{
+				"go mod init cliguard-inspector":                          {},
+				"go mod edit -replace github.com/test/repo=/test/project": {},
+			},
+		}
+		inspector := NewInspector(Config{ProjectPath: "/test/project", FileSystem: fs, Executor: exec})
+
+		info := &EntrypointInfo{ImportPath: "github.com/test/repo/cmd", FunctionName: "NewRootCmd"}
+		if err := inspector.setupTempModule("/tmp/inspect", info); err != nil {
+			t.Fatalf("setupTempModule() error = %v", err)
+		}
+
+		got, copied := fs.Files["/tmp/inspect/go.sum"]
+		if copied != hasGoSum {
+			t.Errorf("project has go.sum: %v, temp module has go.sum: %v", hasGoSum, copied)
+		}
+		if hasGoSum && string(got) != string(goSum) {
+			t.Errorf("temp go.sum = %q, want the project's", got)
+		}
+	}
+}
+
 func compareEntrypointInfo(a, b *EntrypointInfo) bool {
 	if a == nil || b == nil {
 		return a == b
@@ -123,6 +123,30 @@ func TestInspectProject_ErrorCases(t *testing.T) {
 	}
 }
 
+func TestUseName(t *testing.T) {
+	tests := []struct {
+		use  string
+		want string
+	}{
+		{use: "serve", want: "serve"},
+		{use: "clone [flags] <repo>", want: "clone"},
+		{use: "remove|rm <name>", want: "remove|rm"},
+		{use: " list ", want: "list"},
+		{use: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		cmd := InspectedCommand{Use: tt.use}
+		if got := cmd.UseName(); got != tt.want {
+			t.Errorf("InspectedCommand{Use: %q}.UseName() = %q, want %q", tt.use, got, tt.want)
+		}
+		cli := InspectedCLI{Use: tt.use}
+		if got := cli.UseName(); got != tt.want {
+			t.Errorf("InspectedCLI{Use: %q}.UseName() = %q, want %q", tt.use, got, tt.want)
+		}
+	}
+}
+
 func TestGetFlagTypeMapping(t *testing.T) {
 	// Test the getFlagType function indirectly through the template
 	tests := []struct {
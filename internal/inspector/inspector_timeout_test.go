@@ -1,8 +1,6 @@
 package inspector
 
 import (
-	"context"
-	"strings"
 	"testing"
 	"time"
 
@@ -51,19 +49,15 @@ func TestInspectProjectWithTimeout_TimeoutError(t *testing.T) {
 	}
 
 	// Create slow mock executor that simulates long-running process
-	slowExec := &SlowTimeoutMockExecutor{
-		MockExecutor: &executor.MockExecutor{
-			Results: map[string]executor.MockResult{
-				"go mod init cliguard-inspector":           {Output: []byte("go: creating new go.mod"), Error: nil},
-				"go mod edit -replace test.com/cli=/tmp":    {Output: []byte(""), Error: nil},
-				"go mod tidy -e":                           {Output: []byte(""), Error: nil},
-				"go run inspector.go":                      {Output: []byte(`{"use":"test","short":"Test CLI","commands":[]}`), Error: nil},
-			},
-		},
-		slowCommands: map[string]time.Duration{
-			"go run inspector.go": 2 * time.Second, // Simulate long-running inspector
+	slowExec := &executor.MockExecutor{
+		Results: map[string]executor.MockResult{
+			"go mod init cliguard-inspector":           {Output: []byte("go: creating new go.mod"), Error: nil},
+			"go mod edit -replace test.com/cli=/tmp":    {Output: []byte(""), Error: nil},
+			"go mod tidy -e":                           {Output: []byte(""), Error: nil},
+			"go run inspector.go":                      {Output: []byte(`{"use":"test","short":"Test CLI","commands":[]}`), Error: nil},
 		},
 	}
+	slowExec.SetContextDelay("go run inspector.go", 2*time.Second) // Simulate long-running inspector
 
 	// Create inspector with short timeout
 	inspector := NewInspector(Config{
@@ -118,72 +112,6 @@ func TestInspector_NewInspector_WithoutTimeout(t *testing.T) {
 	assert.Equal(t, time.Duration(0), inspector.config.Timeout)
 }
 
-// SlowTimeoutMockExecutor simulates slow execution for specific commands
-type SlowTimeoutMockExecutor struct {
-	*executor.MockExecutor
-	slowCommands map[string]time.Duration
-}
-
-func (s *SlowTimeoutMockExecutor) Command(name string, args ...string) executor.Command {
-	cmd := s.MockExecutor.Command(name, args...)
-	return &slowTimeoutMockCommand{
-		Command:      cmd,
-		slowCommands: s.slowCommands,
-		commandKey:   strings.Join(append([]string{name}, args...), " "),
-	}
-}
-
-func (s *SlowTimeoutMockExecutor) CommandContext(ctx context.Context, name string, args ...string) executor.Command {
-	cmd := s.MockExecutor.CommandContext(ctx, name, args...)
-	return &slowTimeoutMockCommand{
-		Command:      cmd,
-		slowCommands: s.slowCommands,
-		commandKey:   strings.Join(append([]string{name}, args...), " "),
-		ctx:          ctx,
-	}
-}
-
-type slowTimeoutMockCommand struct {
-	executor.Command
-	slowCommands map[string]time.Duration
-	commandKey   string
-	ctx          context.Context
-}
-
-func (s *slowTimeoutMockCommand) Output() ([]byte, error) {
-	if delay, ok := s.slowCommands[s.commandKey]; ok {
-		if s.ctx != nil {
-			// Respect context cancellation
-			select {
-			case <-s.ctx.Done():
-				return nil, s.ctx.Err()
-			case <-time.After(delay):
-				// Continue to normal execution
-			}
-		} else {
-			time.Sleep(delay)
-		}
-	}
-	return s.Command.Output()
-}
-
-func (s *slowTimeoutMockCommand) CombinedOutput() ([]byte, error) {
-	if delay, ok := s.slowCommands[s.commandKey]; ok {
-		if s.ctx != nil {
-			// Respect context cancellation
-			select {
-			case <-s.ctx.Done():
-				return nil, s.ctx.Err()
-			case <-time.After(delay):
-				// Continue to normal execution
-			}
-		} else {
-			time.Sleep(delay)
-		}
-	}
-	return s.Command.CombinedOutput()
-}
-
 func TestInspectProject_BackwardsCompatibility(t *testing.T) {
 	// Test that the old function still works (calls new one with 0 timeout)
 	cli1, err1 := InspectProject(".", "main.NewRootCmd")
@@ -0,0 +1,113 @@
+package inspector
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ReplaceDirective is a replace directive from a go.mod file, e.g.
+// "replace github.com/spf13/cobra v1.8.0 => ../cobra". The versions are
+// empty when the directive does not specify them.
+type ReplaceDirective struct {
+	OldPath    string
+	OldVersion string
+	NewPath    string
+	NewVersion string
+}
+
+// IsLocal reports whether the directive replaces a module with a directory
+// rather than another module. Go treats replacement paths starting with ./
+// or ../, and absolute paths, as directories.
+func (r ReplaceDirective) IsLocal() bool {
+	return r.NewVersion == "" && (strings.HasPrefix(r.NewPath, "./") ||
+		strings.HasPrefix(r.NewPath, "../") ||
+		r.NewPath == "." || r.NewPath == ".." ||
+		filepath.IsAbs(r.NewPath))
+}
+
+// editArg formats the directive as the argument of go mod edit -replace
+func (r ReplaceDirective) editArg() string {
+	old := r.OldPath
+	if r.OldVersion != "" {
+		old += "@" + r.OldVersion
+	}
+	replacement := r.NewPath
+	if r.NewVersion != "" {
+		replacement += "@" + r.NewVersion
+	}
+	return old + "=" + replacement
+}
+
+// extractReplaceDirectives returns the replace directives in goModContent,
+// both single-line and in replace ( ... ) blocks. Malformed directives are
+// skipped; go itself reports them when the project is built.
+func extractReplaceDirectives(goModContent []byte) []ReplaceDirective {
+	var directives []ReplaceDirective
+	inBlock := false
+	for _, line := range strings.Split(string(goModContent), "\n") {
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		if inBlock {
+			if fields[0] == ")" {
+				inBlock = false
+				continue
+			}
+		} else {
+			if fields[0] != "replace" {
+				continue
+			}
+			fields = fields[1:]
+			if len(fields) == 1 && fields[0] == "(" {
+				inBlock = true
+				continue
+			}
+		}
+
+		if directive, ok := parseReplaceFields(fields); ok {
+			directives = append(directives, directive)
+		}
+	}
+	return directives
+}
+
+// parseReplaceFields parses "old [version] => new [version]"
+func parseReplaceFields(fields []string) (ReplaceDirective, bool) {
+	arrow := -1
+	for i, field := range fields {
+		if field == "=>" {
+			arrow = i
+			break
+		}
+	}
+	if arrow < 1 || arrow > 2 || len(fields)-arrow-1 < 1 || len(fields)-arrow-1 > 2 {
+		return ReplaceDirective{}, false
+	}
+
+	old, replacement := fields[:arrow], fields[arrow+1:]
+	directive := ReplaceDirective{
+		OldPath: unquoteModPath(old[0]),
+		NewPath: unquoteModPath(replacement[0]),
+	}
+	if len(old) == 2 {
+		directive.OldVersion = old[1]
+	}
+	if len(replacement) == 2 {
+		directive.NewVersion = replacement[1]
+	}
+	return directive, true
+}
+
+// unquoteModPath removes the quotes go.mod allows around paths
+func unquoteModPath(path string) string {
+	if unquoted, err := strconv.Unquote(path); err == nil {
+		return unquoted
+	}
+	return path
+}
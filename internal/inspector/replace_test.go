@@ -0,0 +1,49 @@
+package inspector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractReplaceDirectives(t *testing.T) {
+	goMod := []byte(`module github.com/test/app
+
+go 1.24
+
+require github.com/spf13/cobra v1.9.1
+
+replace github.com/spf13/cobra => ../cobra // local fork
+
+replace (
+	github.com/spf13/pflag v1.0.5 => github.com/fork/pflag v1.0.6
+	"example.com/quoted" => "/abs/path"
+	// comment
+	example.com/broken
+)
+
+replace example.com/versioned v1.0.0 => ./vendor/versioned
+`)
+
+	assert.Equal(t, []ReplaceDirective{
+		{OldPath: "github.com/spf13/cobra", NewPath: "../cobra"},
+		{OldPath: "github.com/spf13/pflag", OldVersion: "v1.0.5", NewPath: "github.com/fork/pflag", NewVersion: "v1.0.6"},
+		{OldPath: "example.com/quoted", NewPath: "/abs/path"},
+		{OldPath: "example.com/versioned", OldVersion: "v1.0.0", NewPath: "./vendor/versioned"},
+	}, extractReplaceDirectives(goMod))
+
+	assert.Empty(t, extractReplaceDirectives([]byte("module github.com/test/app\n")))
+}
+
+func TestReplaceDirective_IsLocal(t *testing.T) {
+	assert.True(t, ReplaceDirective{NewPath: "../cobra"}.IsLocal())
+	assert.True(t, ReplaceDirective{NewPath: "./cobra"}.IsLocal())
+	assert.True(t, ReplaceDirective{NewPath: "/src/cobra"}.IsLocal())
+	assert.False(t, ReplaceDirective{NewPath: "github.com/fork/cobra", NewVersion: "v1.0.0"}.IsLocal())
+}
+
+func TestReplaceDirective_editArg(t *testing.T) {
+	assert.Equal(t, "a.com/x=../x", ReplaceDirective{OldPath: "a.com/x", NewPath: "../x"}.editArg())
+	assert.Equal(t, "a.com/x@v1.0.0=b.com/x@v1.1.0",
+		ReplaceDirective{OldPath: "a.com/x", OldVersion: "v1.0.0", NewPath: "b.com/x", NewVersion: "v1.1.0"}.editArg())
+}
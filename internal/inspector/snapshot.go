@@ -0,0 +1,38 @@
+package inspector
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// WriteSnapshot writes the inspected CLI to path as indented JSON, the
+// format printed by cliguard inspect. A snapshot lets a CLI be validated
+// later without building its project (see LoadSnapshot).
+func WriteSnapshot(path string, cli *InspectedCLI) error {
+	data, err := json.MarshalIndent(cli, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal CLI snapshot: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write CLI snapshot: %w", err)
+	}
+	return nil
+}
+
+// LoadSnapshot reads a CLI snapshot written by WriteSnapshot or cliguard
+// inspect
+func LoadSnapshot(path string) (*InspectedCLI, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cli InspectedCLI
+	if err := json.Unmarshal(data, &cli); err != nil {
+		return nil, fmt.Errorf("invalid CLI snapshot %s: %w", path, err)
+	}
+	if cli.Use == "" {
+		return nil, fmt.Errorf("invalid CLI snapshot %s: the root command has no 'use'", path)
+	}
+	return &cli, nil
+}
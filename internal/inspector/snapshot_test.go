@@ -0,0 +1,63 @@
+package inspector
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	cli := &InspectedCLI{
+		Use:     "myapp",
+		Short:   "My app",
+		Aliases: []string{"app"},
+		Flags:   []InspectedFlag{{Name: "config", Type: "string", Persistent: true}},
+		Commands: []InspectedCommand{
+			{Use: "serve", Flags: []InspectedFlag{{Name: "port", Shorthand: "p", Type: "int", Default: "8080"}}},
+		},
+	}
+	path := filepath.Join(t.TempDir(), "cli.json")
+	if err := WriteSnapshot(path, cli); err != nil {
+		t.Fatalf("WriteSnapshot() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "{\n  \"use\": \"myapp\",") {
+		t.Errorf("snapshot is not indented JSON:\n%s", data)
+	}
+
+	loaded, err := LoadSnapshot(path)
+	if err != nil {
+		t.Fatalf("LoadSnapshot() error = %v", err)
+	}
+	if !reflect.DeepEqual(loaded, cli) {
+		t.Errorf("LoadSnapshot() = %+v, want %+v", loaded, cli)
+	}
+}
+
+func TestLoadSnapshot_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	tests := map[string]string{
+		"truncated.json": `{"use": "myapp",`,
+		"contract.yaml":  "use: myapp\n",
+		"empty.json":     `{"commands": []}`,
+	}
+	for name, content := range tests {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadSnapshot(path); err == nil || !strings.Contains(err.Error(), "invalid CLI snapshot") {
+			t.Errorf("LoadSnapshot(%s) error = %v, want invalid snapshot", name, err)
+		}
+	}
+
+	if _, err := LoadSnapshot(filepath.Join(dir, "missing.json")); !os.IsNotExist(err) {
+		t.Errorf("LoadSnapshot() error = %v, want not exist", err)
+	}
+}
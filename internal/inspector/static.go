@@ -0,0 +1,844 @@
+package inspector
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/errors"
+	"github.com/hiAndrewQuinn/cliguard/internal/executor"
+)
+
+// StaticConfig configures static inspection of a project's source
+type StaticConfig struct {
+	// ProjectPath is the path to the Go project
+	ProjectPath string
+
+	// Entrypoint is the function that returns the root command, or the
+	// package-level variable holding it, e.g.
+	// "github.com/user/repo/cmd.NewRootCmd"
+	Entrypoint string
+
+	// Timeout applies to listing the project's packages. A zero timeout
+	// means none.
+	Timeout time.Duration
+
+	Executor executor.CommandExecutor
+}
+
+// StaticInspector reads a CLI's structure from the project source without
+// building or running it, for projects that can't be run where they are
+// validated. The project's packages are listed with "go list -json ./...",
+// which applies build constraints without compiling, and their files are
+// parsed to find the cobra.Command literals, the AddCommand calls linking
+// them and the flags defined on them.
+//
+// Only what is constant in the source is found: the fields of command
+// literals given as string constants, and flags defined with the typed
+// pflag methods, such as StringVarP, with a constant name and usage. Flag
+// defaults, completions and flag groups are not recorded, nor are commands
+// and flags built with values computed at run time. The result is less
+// complete than the one Inspector gets by running the CLI.
+type StaticInspector struct {
+	config StaticConfig
+}
+
+// NewStaticInspector creates a new StaticInspector with the given configuration
+func NewStaticInspector(config StaticConfig) *StaticInspector {
+	if config.Executor == nil {
+		config.Executor = &executor.OSExecutor{}
+	}
+	if config.Timeout > 0 {
+		config.Executor = executor.NewTimeoutExecutor(config.Executor, config.Timeout)
+	}
+	return &StaticInspector{config: config}
+}
+
+// InspectProjectStatic reads the CLI structure of a project from its source
+// without running it (see StaticInspector). A timeout of 0 means no timeout
+// will be applied.
+func InspectProjectStatic(projectPath, entrypoint string, timeout time.Duration) (*InspectedCLI, error) {
+	return NewStaticInspector(StaticConfig{
+		ProjectPath: projectPath,
+		Entrypoint:  entrypoint,
+		Timeout:     timeout,
+	}).Inspect()
+}
+
+// Inspect lists the project's packages and reads the CLI structure from
+// their source
+func (s *StaticInspector) Inspect() (*InspectedCLI, error) {
+	// -e lists packages with errors, such as missing dependencies, instead
+	// of failing; their files can still be parsed
+	listCmd := s.config.Executor.Command("go", "list", "-e", "-json", "./...")
+	listCmd.SetDir(s.config.ProjectPath)
+	output, err := listCmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list packages: %w", err)
+	}
+	return InspectProjectFromBuildOutput(output, s.config.Entrypoint)
+}
+
+// InspectProjectFromBuildOutput reads the CLI structure from the source of
+// the packages in listOutput, the output of "go list -json", starting from
+// the entrypoint (see StaticInspector). No code is built or run.
+func InspectProjectFromBuildOutput(listOutput []byte, entrypoint string) (*InspectedCLI, error) {
+	packages, err := parseGoList(listOutput)
+	if err != nil {
+		return nil, err
+	}
+	program := newStaticProgram(packages)
+	program.analyzeAll()
+
+	root, err := program.entrypoint(entrypoint)
+	if err != nil {
+		return nil, err
+	}
+	return root.inspectedCLI(), nil
+}
+
+// listedPackage is the part of a package in "go list -json" output that
+// static inspection uses
+type listedPackage struct {
+	Dir        string
+	ImportPath string
+	Name       string
+	GoFiles    []string
+	CgoFiles   []string
+}
+
+// parseGoList decodes the stream of JSON objects "go list -json" prints
+func parseGoList(output []byte) ([]listedPackage, error) {
+	var packages []listedPackage
+	decoder := json.NewDecoder(bytes.NewReader(output))
+	for {
+		var pkg listedPackage
+		if err := decoder.Decode(&pkg); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("invalid go list output: %w", err)
+		}
+		packages = append(packages, pkg)
+	}
+	return packages, nil
+}
+
+// staticPackage is a parsed package of the project
+type staticPackage struct {
+	path   string
+	name   string
+	funcs  map[string]*staticFunc
+	consts map[string]ast.Expr
+	vars   map[string]*staticCommand
+
+	// methods holds the methods by name. A method call is only followed
+	// when one method of the package has its name, since the receiver's
+	// type isn't known.
+	methods map[string][]*staticFunc
+}
+
+// staticFunc is a function of the project, analyzed at most once
+type staticFunc struct {
+	decl  *ast.FuncDecl
+	scope *staticScope
+
+	analyzing, analyzed bool
+
+	// result is the command the function returns, if it returns one
+	result *staticCommand
+}
+
+// staticScope is where names are looked up: a file of a package, and the
+// local variables of the function being analyzed
+type staticScope struct {
+	pkg     *staticPackage
+	imports map[string]string // import name to import path
+	cobra   string            // the name Cobra is imported as, or ""
+
+	commands map[string]*staticCommand
+	flagSets map[string]staticFlagSet
+}
+
+// staticFlagSet is the flag set of a command: its Flags() or PersistentFlags()
+type staticFlagSet struct {
+	cmd        *staticCommand
+	persistent bool
+}
+
+// staticCommand is a cobra.Command literal, with what the project does to it
+type staticCommand struct {
+	use, short, long, example, version, groupID string
+	aliases                                     []string
+	hidden, runnable                            bool
+
+	flags           []InspectedFlag
+	persistentFlags []InspectedFlag
+	children        []*staticCommand
+
+	required    map[string]bool
+	deprecated  map[string]string
+	hiddenFlags map[string]bool
+}
+
+// staticProgram holds the parsed packages of a project
+type staticProgram struct {
+	packages map[string]*staticPackage
+	order    []*staticPackage
+	literals map[*ast.CompositeLit]*staticCommand
+}
+
+// newStaticProgram parses the files of the packages and records their
+// functions, constants and command variables. Files that fail to parse are
+// skipped.
+func newStaticProgram(listed []listedPackage) *staticProgram {
+	p := &staticProgram{
+		packages: make(map[string]*staticPackage),
+		literals: make(map[*ast.CompositeLit]*staticCommand),
+	}
+	fset := token.NewFileSet()
+	type parsedFile struct {
+		file  *ast.File
+		scope *staticScope
+	}
+	var files []parsedFile
+	for _, lp := range listed {
+		pkg := &staticPackage{
+			path:    lp.ImportPath,
+			name:    lp.Name,
+			funcs:   make(map[string]*staticFunc),
+			consts:  make(map[string]ast.Expr),
+			vars:    make(map[string]*staticCommand),
+			methods: make(map[string][]*staticFunc),
+		}
+		p.packages[pkg.path] = pkg
+		p.order = append(p.order, pkg)
+		for _, name := range append(append([]string(nil), lp.GoFiles...), lp.CgoFiles...) {
+			file, err := parser.ParseFile(fset, filepath.Join(lp.Dir, name), nil, 0)
+			if err != nil {
+				continue
+			}
+			files = append(files, parsedFile{file: file, scope: &staticScope{pkg: pkg, cobra: cobraImportName(file)}})
+		}
+	}
+
+	// Import names need the package names of the whole project
+	for _, f := range files {
+		f.scope.imports = p.importNames(f.file)
+		for _, decl := range f.file.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				if decl.Body == nil {
+					continue
+				}
+				fn := &staticFunc{decl: decl, scope: f.scope}
+				if decl.Recv != nil {
+					f.scope.pkg.methods[decl.Name.Name] = append(f.scope.pkg.methods[decl.Name.Name], fn)
+				} else if decl.Name.Name != "init" {
+					f.scope.pkg.funcs[decl.Name.Name] = fn
+				} else {
+					f.scope.pkg.funcs[fmt.Sprintf("init#%d", len(f.scope.pkg.funcs))] = fn
+				}
+			case *ast.GenDecl:
+				if decl.Tok != token.CONST {
+					continue
+				}
+				for _, spec := range decl.Specs {
+					value := spec.(*ast.ValueSpec)
+					for i, name := range value.Names {
+						if i < len(value.Values) {
+							f.scope.pkg.consts[name.Name] = value.Values[i]
+						}
+					}
+				}
+			}
+		}
+	}
+
+	// Command variables, once the functions they may be built by are known
+	for _, f := range files {
+		for _, decl := range f.file.Decls {
+			if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.VAR {
+				for _, spec := range gen.Specs {
+					value := spec.(*ast.ValueSpec)
+					for i, name := range value.Names {
+						if i >= len(value.Values) {
+							continue
+						}
+						if cmd := p.command(f.scope, value.Values[i]); cmd != nil {
+							f.scope.pkg.vars[name.Name] = cmd
+						}
+					}
+				}
+			}
+		}
+	}
+	return p
+}
+
+// importNames returns the names the file's imports are referred to by
+func (p *staticProgram) importNames(file *ast.File) map[string]string {
+	names := make(map[string]string)
+	for _, imp := range file.Imports {
+		importPath, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			continue
+		}
+		name := path.Base(importPath)
+		if pkg, ok := p.packages[importPath]; ok && pkg.name != "" {
+			name = pkg.name
+		}
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		names[name] = importPath
+	}
+	return names
+}
+
+// analyzeAll analyzes every function of the project, so that the commands
+// and flags set up in init functions are found as well as those set up by
+// constructors
+func (p *staticProgram) analyzeAll() {
+	for _, pkg := range p.order {
+		names := make([]string, 0, len(pkg.funcs))
+		for name := range pkg.funcs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			p.analyze(pkg.funcs[name])
+		}
+		for _, name := range sortedMethodNames(pkg.methods) {
+			for _, fn := range pkg.methods[name] {
+				p.analyze(fn)
+			}
+		}
+	}
+}
+
+// sortedMethodNames returns the method names in ascending order
+func sortedMethodNames(methods map[string][]*staticFunc) []string {
+	names := make([]string, 0, len(methods))
+	for name := range methods {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// analyze follows what the function does to commands: the commands it
+// builds and returns, the subcommands it adds and the flags it defines
+func (p *staticProgram) analyze(fn *staticFunc) {
+	if fn.analyzed || fn.analyzing {
+		return
+	}
+	fn.analyzing = true
+	defer func() { fn.analyzing, fn.analyzed = false, true }()
+
+	scope := *fn.scope
+	scope.commands = make(map[string]*staticCommand)
+	scope.flagSets = make(map[string]staticFlagSet)
+	ast.Inspect(fn.decl.Body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			if len(n.Lhs) == len(n.Rhs) {
+				for i, lhs := range n.Lhs {
+					if ident, ok := lhs.(*ast.Ident); ok {
+						p.bind(&scope, ident.Name, n.Rhs[i])
+					}
+				}
+			}
+		case *ast.ValueSpec:
+			for i, name := range n.Names {
+				if i < len(n.Values) {
+					p.bind(&scope, name.Name, n.Values[i])
+				}
+			}
+		case *ast.CallExpr:
+			p.call(&scope, n)
+		case *ast.ReturnStmt:
+			if len(n.Results) == 1 {
+				if cmd := p.command(&scope, n.Results[0]); cmd != nil {
+					fn.result = cmd
+				}
+			}
+		}
+		return true
+	})
+}
+
+// bind records the command or flag set a local variable is assigned
+func (p *staticProgram) bind(scope *staticScope, name string, value ast.Expr) {
+	if cmd := p.command(scope, value); cmd != nil {
+		scope.commands[name] = cmd
+	} else if flagSet, ok := p.flagSet(scope, value); ok {
+		scope.flagSets[name] = flagSet
+	}
+}
+
+// command returns the command expr evaluates to, or nil if it can't tell
+func (p *staticProgram) command(scope *staticScope, expr ast.Expr) *staticCommand {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return p.command(scope, e.X)
+	case *ast.UnaryExpr:
+		if e.Op == token.AND {
+			return p.command(scope, e.X)
+		}
+	case *ast.CompositeLit:
+		if scope.cobra != "" && isCobraCommand(e.Type, scope.cobra) {
+			return p.literal(scope, e)
+		}
+	case *ast.Ident:
+		if cmd, ok := scope.commands[e.Name]; ok {
+			return cmd
+		}
+		return scope.pkg.vars[e.Name]
+	case *ast.SelectorExpr:
+		if pkg := p.importedPackage(scope, e.X); pkg != nil {
+			return pkg.vars[e.Sel.Name]
+		}
+	case *ast.CallExpr:
+		if fn := p.callee(scope, e.Fun); fn != nil {
+			p.analyze(fn)
+			return fn.result
+		}
+	}
+	return nil
+}
+
+// importedPackage returns the project package expr names, if it is the
+// name of an import
+func (p *staticProgram) importedPackage(scope *staticScope, expr ast.Expr) *staticPackage {
+	ident, ok := expr.(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	if _, local := scope.commands[ident.Name]; local {
+		return nil
+	}
+	importPath, ok := scope.imports[ident.Name]
+	if !ok {
+		return nil
+	}
+	return p.packages[importPath]
+}
+
+// callee returns the project function a call calls, or nil
+func (p *staticProgram) callee(scope *staticScope, fun ast.Expr) *staticFunc {
+	switch f := fun.(type) {
+	case *ast.Ident:
+		return scope.pkg.funcs[f.Name]
+	case *ast.SelectorExpr:
+		if pkg := p.importedPackage(scope, f.X); pkg != nil {
+			return pkg.funcs[f.Sel.Name]
+		}
+		if methods := scope.pkg.methods[f.Sel.Name]; len(methods) == 1 {
+			return methods[0]
+		}
+	}
+	return nil
+}
+
+// literal returns the command of a cobra.Command literal
+func (p *staticProgram) literal(scope *staticScope, lit *ast.CompositeLit) *staticCommand {
+	if cmd, ok := p.literals[lit]; ok {
+		return cmd
+	}
+	cmd := &staticCommand{
+		required:    make(map[string]bool),
+		deprecated:  make(map[string]string),
+		hiddenFlags: make(map[string]bool),
+	}
+	p.literals[lit] = cmd
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok {
+			continue
+		}
+		switch key.Name {
+		case "Use":
+			cmd.use, _ = p.stringValue(scope, kv.Value)
+		case "Short":
+			cmd.short, _ = p.stringValue(scope, kv.Value)
+		case "Long":
+			cmd.long, _ = p.stringValue(scope, kv.Value)
+		case "Example":
+			cmd.example, _ = p.stringValue(scope, kv.Value)
+		case "Version":
+			cmd.version, _ = p.stringValue(scope, kv.Value)
+		case "GroupID":
+			cmd.groupID, _ = p.stringValue(scope, kv.Value)
+		case "Aliases":
+			if list, ok := kv.Value.(*ast.CompositeLit); ok {
+				for _, alias := range list.Elts {
+					if value, ok := p.stringValue(scope, alias); ok {
+						cmd.aliases = append(cmd.aliases, value)
+					}
+				}
+			}
+		case "Hidden":
+			value, ok := kv.Value.(*ast.Ident)
+			cmd.hidden = ok && value.Name == "true"
+		case "Run", "RunE", "PreRun", "PreRunE", "PostRun", "PostRunE":
+			if value, ok := kv.Value.(*ast.Ident); !ok || value.Name != "nil" {
+				cmd.runnable = true
+			}
+		}
+	}
+	return cmd
+}
+
+// stringValue returns the value of a constant string expression: a string
+// literal, a constant of the project, or a concatenation of them
+func (p *staticProgram) stringValue(scope *staticScope, expr ast.Expr) (string, bool) {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if e.Kind != token.STRING {
+			return "", false
+		}
+		value, err := strconv.Unquote(e.Value)
+		return value, err == nil
+	case *ast.ParenExpr:
+		return p.stringValue(scope, e.X)
+	case *ast.BinaryExpr:
+		if e.Op != token.ADD {
+			return "", false
+		}
+		left, ok := p.stringValue(scope, e.X)
+		if !ok {
+			return "", false
+		}
+		right, ok := p.stringValue(scope, e.Y)
+		return left + right, ok
+	case *ast.Ident:
+		if value, ok := scope.pkg.consts[e.Name]; ok {
+			constScope := *scope
+			constScope.commands = nil
+			return p.stringValue(&constScope, value)
+		}
+	case *ast.SelectorExpr:
+		if pkg := p.importedPackage(scope, e.X); pkg != nil {
+			if value, ok := pkg.consts[e.Sel.Name]; ok {
+				// Constants are written in their package, as literals or
+				// other constants of it
+				return p.stringValue(&staticScope{pkg: pkg}, value)
+			}
+		}
+	}
+	return "", false
+}
+
+// flagSet returns the flag set expr evaluates to, such as cmd.Flags()
+func (p *staticProgram) flagSet(scope *staticScope, expr ast.Expr) (staticFlagSet, bool) {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		flagSet, ok := scope.flagSets[e.Name]
+		return flagSet, ok
+	case *ast.CallExpr:
+		sel, ok := e.Fun.(*ast.SelectorExpr)
+		if !ok || len(e.Args) != 0 || (sel.Sel.Name != "Flags" && sel.Sel.Name != "PersistentFlags") {
+			return staticFlagSet{}, false
+		}
+		if cmd := p.command(scope, sel.X); cmd != nil {
+			return staticFlagSet{cmd: cmd, persistent: sel.Sel.Name == "PersistentFlags"}, true
+		}
+	}
+	return staticFlagSet{}, false
+}
+
+// call records what a method call does to a command or flag set
+func (p *staticProgram) call(scope *staticScope, call *ast.CallExpr) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return
+	}
+	method := sel.Sel.Name
+
+	// cobra.MarkFlagRequired(cmd.Flags(), "name")
+	if ident, ok := sel.X.(*ast.Ident); ok && scope.cobra != "" && ident.Name == scope.cobra {
+		if method == "MarkFlagRequired" && len(call.Args) == 2 {
+			if flagSet, ok := p.flagSet(scope, call.Args[0]); ok {
+				if name, ok := p.stringValue(scope, call.Args[1]); ok {
+					flagSet.cmd.required[name] = true
+				}
+			}
+		}
+		return
+	}
+
+	switch method {
+	case "AddCommand":
+		parent := p.command(scope, sel.X)
+		if parent == nil || call.Ellipsis.IsValid() {
+			return
+		}
+		for _, arg := range call.Args {
+			if child := p.command(scope, arg); child != nil && child != parent && !containsCommand(parent.children, child) {
+				parent.children = append(parent.children, child)
+			}
+		}
+		return
+	case "MarkFlagRequired", "MarkPersistentFlagRequired":
+		if cmd := p.command(scope, sel.X); cmd != nil && len(call.Args) == 1 {
+			if name, ok := p.stringValue(scope, call.Args[0]); ok {
+				cmd.required[name] = true
+			}
+		}
+		return
+	}
+
+	flagSet, ok := p.flagSet(scope, sel.X)
+	if !ok {
+		return
+	}
+	switch method {
+	case "MarkDeprecated":
+		if len(call.Args) == 2 {
+			name, ok := p.stringValue(scope, call.Args[0])
+			message, ok2 := p.stringValue(scope, call.Args[1])
+			if ok && ok2 {
+				flagSet.cmd.deprecated[name] = message
+			}
+		}
+	case "MarkHidden":
+		if len(call.Args) == 1 {
+			if name, ok := p.stringValue(scope, call.Args[0]); ok {
+				flagSet.cmd.hiddenFlags[name] = true
+			}
+		}
+	default:
+		p.defineFlag(scope, flagSet, method, call.Args)
+	}
+}
+
+// staticFlagTypes maps the typed pflag methods, without their Var and P
+// suffixes, to the flag types the inspector program reports for them (see
+// getFlagType)
+var staticFlagTypes = map[string]string{
+	"Bool": "bool", "BoolSlice": "boolSlice",
+	"Count":    "count",
+	"Duration": "duration", "DurationSlice": "durationSlice",
+	"Float32": "float32", "Float32Slice": "float32Slice",
+	"Float64": "float64", "Float64Slice": "float64Slice",
+	"Int": "int", "Int8": "int8", "Int16": "int16", "Int32": "int32", "Int64": "int64",
+	"IntSlice": "intSlice", "Int32Slice": "int32Slice", "Int64Slice": "int64Slice",
+	"Uint": "uint", "Uint8": "uint8", "Uint16": "uint16", "Uint32": "uint32", "Uint64": "uint64",
+	"UintSlice": "uintSlice",
+	"String":    "string", "StringSlice": "stringSlice", "StringArray": "stringArray",
+	"StringToString": "stringToString", "StringToInt64": "stringToInt64",
+	"IP": "ip", "IPSlice": "ipSlice", "IPMask": "ipMask", "IPNet": "ipNet",
+	"BytesHex": "bytesHex", "BytesBase64": "bytesBase64",
+}
+
+// parseFlagMethod splits a typed pflag method name such as StringVarP into
+// its flag type and whether it takes a pointer to store the value in and a
+// shorthand
+func parseFlagMethod(method string) (flagType string, hasVar, hasShorthand bool) {
+	for _, suffix := range []struct {
+		suffix               string
+		hasVar, hasShorthand bool
+	}{{"", false, false}, {"VarP", true, true}, {"Var", true, false}, {"P", false, true}} {
+		if base, ok := strings.CutSuffix(method, suffix.suffix); ok {
+			if flagType, ok := staticFlagTypes[base]; ok {
+				return flagType, suffix.hasVar, suffix.hasShorthand
+			}
+		}
+	}
+	return "", false, false
+}
+
+// defineFlag records a flag defined with a typed pflag method, whose
+// arguments are the pointer for Var methods, the name, the shorthand for P
+// methods, the default value except for counts, and the usage
+func (p *staticProgram) defineFlag(scope *staticScope, flagSet staticFlagSet, method string, args []ast.Expr) {
+	flagType, hasVar, hasShorthand := parseFlagMethod(method)
+	if flagType == "" {
+		return
+	}
+	want, nameArg := 3, 0
+	if hasVar {
+		want, nameArg = want+1, 1
+	}
+	if hasShorthand {
+		want++
+	}
+	if flagType == "count" {
+		want--
+	}
+	if len(args) != want {
+		return
+	}
+
+	flag := InspectedFlag{Type: flagType, Persistent: flagSet.persistent}
+	var ok bool
+	if flag.Name, ok = p.stringValue(scope, args[nameArg]); !ok {
+		return
+	}
+	if flag.Usage, ok = p.stringValue(scope, args[len(args)-1]); !ok {
+		return
+	}
+	if hasShorthand {
+		flag.Shorthand, _ = p.stringValue(scope, args[nameArg+1])
+	}
+
+	flags := &flagSet.cmd.flags
+	if flagSet.persistent {
+		flags = &flagSet.cmd.persistentFlags
+	}
+	for _, existing := range *flags {
+		if existing.Name == flag.Name {
+			return
+		}
+	}
+	*flags = append(*flags, flag)
+}
+
+// containsCommand reports whether commands includes cmd
+func containsCommand(commands []*staticCommand, cmd *staticCommand) bool {
+	for _, c := range commands {
+		if c == cmd {
+			return true
+		}
+	}
+	return false
+}
+
+// entrypoint returns the root command the entrypoint returns or holds
+func (p *staticProgram) entrypoint(entrypoint string) (*staticCommand, error) {
+	if entrypoint == "" {
+		return nil, fmt.Errorf("static inspection requires an entrypoint")
+	}
+	dot := strings.LastIndex(entrypoint, ".")
+	if dot <= 0 || dot == len(entrypoint)-1 {
+		return nil, errors.EntrypointParseError{
+			Entrypoint: entrypoint,
+			Reason:     "invalid format",
+		}
+	}
+	importPath, name := entrypoint[:dot], entrypoint[dot+1:]
+
+	var candidates []*staticPackage
+	for _, pkg := range p.order {
+		if pkg.path == importPath || (importPath == "main" && pkg.name == "main") {
+			candidates = append(candidates, pkg)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("package %s not found in the project", importPath)
+	}
+	for _, pkg := range candidates {
+		if fn, ok := pkg.funcs[name]; ok {
+			if fn.result == nil {
+				return nil, fmt.Errorf("%s does not return a cobra.Command literal that static inspection can follow", entrypoint)
+			}
+			return fn.result, nil
+		}
+		if cmd, ok := pkg.vars[name]; ok {
+			return cmd, nil
+		}
+	}
+	return nil, fmt.Errorf("function %s not found in package %s", name, importPath)
+}
+
+// inspectedCLI converts the root command, as the inspector program would
+func (c *staticCommand) inspectedCLI() *InspectedCLI {
+	cli := &InspectedCLI{
+		Use:     c.use,
+		Short:   c.short,
+		Long:    c.long,
+		Aliases: c.aliases,
+		Example: c.example,
+		Version: c.version,
+	}
+	// The root command's flags are its local and persistent ones
+	cli.Flags = c.inspectedFlags(c.flags)
+	for _, flag := range c.inspectedFlags(c.persistentFlags) {
+		if !hasInspectedFlag(cli.Flags, flag.Name) {
+			cli.Flags = append(cli.Flags, flag)
+		}
+	}
+	sortInspectedFlags(cli.Flags)
+	cli.Commands = c.inspectedCommands(map[*staticCommand]bool{c: true})
+	return cli
+}
+
+// inspectedCommands converts the subcommands, sorted by name as Cobra lists
+// them, leaving out those already visited, which would repeat forever
+func (c *staticCommand) inspectedCommands(visited map[*staticCommand]bool) []InspectedCommand {
+	var commands []InspectedCommand
+	for _, child := range c.children {
+		if visited[child] {
+			continue
+		}
+		visited[child] = true
+		command := InspectedCommand{
+			Use:      child.use,
+			Short:    child.short,
+			Long:     child.long,
+			Aliases:  child.aliases,
+			Example:  child.example,
+			Hidden:   child.hidden,
+			Runnable: child.runnable,
+			GroupID:  child.groupID,
+			// Like the inspector program, only the local flags of
+			// subcommands are reported
+			Flags:    child.inspectedFlags(child.flags),
+			Commands: child.inspectedCommands(visited),
+		}
+		sortInspectedFlags(command.Flags)
+		commands = append(commands, command)
+		delete(visited, child)
+	}
+	sort.SliceStable(commands, func(i, j int) bool {
+		return commands[i].UseName() < commands[j].UseName()
+	})
+	return commands
+}
+
+// inspectedFlags returns the flags with their required and deprecated
+// marks, without those hidden and not deprecated, as the inspector program
+// reports them
+func (c *staticCommand) inspectedFlags(flags []InspectedFlag) []InspectedFlag {
+	var result []InspectedFlag
+	for _, flag := range flags {
+		flag.Deprecated = c.deprecated[flag.Name]
+		if c.hiddenFlags[flag.Name] && flag.Deprecated == "" {
+			continue
+		}
+		flag.Required = c.required[flag.Name]
+		result = append(result, flag)
+	}
+	return result
+}
+
+// hasInspectedFlag reports whether flags has a flag called name
+func hasInspectedFlag(flags []InspectedFlag, name string) bool {
+	for _, flag := range flags {
+		if flag.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// sortInspectedFlags sorts flags by name, as pflag visits them
+func sortInspectedFlags(flags []InspectedFlag) {
+	sort.Slice(flags, func(i, j int) bool {
+		return flags[i].Name < flags[j].Name
+	})
+}
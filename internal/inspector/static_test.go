@@ -0,0 +1,308 @@
+package inspector
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/executor"
+)
+
+// staticTestFiles is a project with a root command built by a constructor,
+// a subcommand from another package and one added in init
+var staticTestFiles = map[string]map[string]string{
+	"cmd": {
+		"root.go": `package cmd
+
+import (
+	"fmt"
+
+	"example.com/app/internal/db"
+	"github.com/spf13/cobra"
+)
+
+const appName = "app"
+
+var verbose bool
+
+// NewRootCmd creates the root command
+func NewRootCmd() *cobra.Command {
+	rootCmd := &cobra.Command{
+		Use:     appName,
+		Short:   "An app",
+		Long:    "An app" + " that does things",
+		Version: "1.0.0",
+	}
+	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Verbose output")
+	rootCmd.Flags().String("config", "", "Config file")
+	rootCmd.Flags().String(fmt.Sprint("computed"), "", "Not found statically")
+
+	serve := newServeCmd()
+	rootCmd.AddCommand(serve, db.NewCmd(), versionCmd)
+	return rootCmd
+}
+
+func newServeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "serve [addr]",
+		Short:   "Serve the app",
+		Aliases: []string{"s", "server"},
+		RunE:    func(cmd *cobra.Command, args []string) error { return nil },
+	}
+	flags := cmd.Flags()
+	flags.IntP("port", "p", 8080, "Port to listen on")
+	flags.Count("debug", "Debug level")
+	flags.StringSlice("tag", nil, "Tags")
+	flags.Bool("old", false, "Old flag")
+	flags.MarkDeprecated("old", "use --new")
+	flags.Bool("secret", false, "Hidden flag")
+	flags.MarkHidden("secret")
+	cmd.MarkFlagRequired("port")
+	return cmd
+}
+`,
+		"version.go": `package cmd
+
+import "github.com/spf13/cobra"
+
+var versionCmd = &cobra.Command{
+	Use:    "version",
+	Short:  "Print the version",
+	Hidden: true,
+	Run:    func(cmd *cobra.Command, args []string) {},
+}
+
+var timeout int
+
+func init() {
+	versionCmd.Flags().IntVar(&timeout, "timeout", 5, "Timeout in seconds")
+}
+`,
+	},
+	"internal/db": {
+		"db.go": `package db
+
+import c "github.com/spf13/cobra"
+
+const Short = "Manage the database"
+
+func NewCmd() *c.Command {
+	cmd := &c.Command{Use: "db", Short: Short}
+	cmd.AddCommand(&c.Command{Use: "migrate", Short: "Migrate the database", Run: func(*c.Command, []string) {}})
+	c.MarkFlagRequired(cmd.Flags(), "dsn")
+	cmd.Flags().StringVarP(new(string), "dsn", "d", "", "Database URL")
+	return cmd
+}
+`,
+		"broken.go": `package db
+
+this file does not parse
+`,
+	},
+	"": {
+		"main.go": `package main
+
+import "example.com/app/cmd"
+
+func main() {
+	cmd.NewRootCmd().Execute()
+}
+`,
+	},
+}
+
+// writeStaticTestProject writes the files of staticTestFiles and returns
+// the "go list -json" output for them
+func writeStaticTestProject(t *testing.T) (string, []byte) {
+	t.Helper()
+	dir := t.TempDir()
+	var output []byte
+	for _, pkgDir := range []string{"", "cmd", "internal/db"} {
+		files := staticTestFiles[pkgDir]
+		pkg := listedPackage{
+			Dir:        filepath.Join(dir, pkgDir),
+			ImportPath: strings.TrimSuffix("example.com/app/"+pkgDir, "/"),
+			Name:       filepath.Base(pkgDir),
+		}
+		if pkgDir == "" {
+			pkg.Name = "main"
+		}
+		if err := os.MkdirAll(pkg.Dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		for name, content := range files {
+			if err := os.WriteFile(filepath.Join(pkg.Dir, name), []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			pkg.GoFiles = append(pkg.GoFiles, name)
+		}
+		data, err := json.MarshalIndent(pkg, "", "\t")
+		if err != nil {
+			t.Fatal(err)
+		}
+		output = append(output, data...)
+		output = append(output, '\n')
+	}
+	return dir, output
+}
+
+func TestInspectProjectFromBuildOutput(t *testing.T) {
+	_, output := writeStaticTestProject(t)
+
+	cli, err := InspectProjectFromBuildOutput(output, "example.com/app/cmd.NewRootCmd")
+	if err != nil {
+		t.Fatalf("InspectProjectFromBuildOutput() error = %v", err)
+	}
+
+	want := &InspectedCLI{
+		Use:     "app",
+		Short:   "An app",
+		Long:    "An app that does things",
+		Version: "1.0.0",
+		Flags: []InspectedFlag{
+			{Name: "config", Usage: "Config file", Type: "string"},
+			{Name: "verbose", Shorthand: "v", Usage: "Verbose output", Type: "bool", Persistent: true},
+		},
+		Commands: []InspectedCommand{
+			{
+				Use:   "db",
+				Short: "Manage the database",
+				Flags: []InspectedFlag{
+					{Name: "dsn", Shorthand: "d", Usage: "Database URL", Type: "string", Required: true},
+				},
+				Commands: []InspectedCommand{
+					{Use: "migrate", Short: "Migrate the database", Runnable: true},
+				},
+			},
+			{
+				Use:      "serve [addr]",
+				Short:    "Serve the app",
+				Aliases:  []string{"s", "server"},
+				Runnable: true,
+				Flags: []InspectedFlag{
+					{Name: "debug", Usage: "Debug level", Type: "count"},
+					{Name: "old", Usage: "Old flag", Type: "bool", Deprecated: "use --new"},
+					{Name: "port", Shorthand: "p", Usage: "Port to listen on", Type: "int", Required: true},
+					{Name: "tag", Usage: "Tags", Type: "stringSlice"},
+				},
+			},
+			{
+				Use:      "version",
+				Short:    "Print the version",
+				Hidden:   true,
+				Runnable: true,
+				Flags: []InspectedFlag{
+					{Name: "timeout", Usage: "Timeout in seconds", Type: "int"},
+				},
+			},
+		},
+	}
+	if !reflect.DeepEqual(cli, want) {
+		got, _ := json.MarshalIndent(cli, "", "  ")
+		t.Errorf("InspectProjectFromBuildOutput() =\n%s", got)
+	}
+}
+
+func TestInspectProjectFromBuildOutput_Entrypoints(t *testing.T) {
+	_, output := writeStaticTestProject(t)
+
+	tests := []struct {
+		name       string
+		entrypoint string
+		wantUse    string
+		wantErr    string
+	}{
+		{name: "package variable", entrypoint: "example.com/app/cmd.versionCmd", wantUse: "version"},
+		{name: "constructor in another package", entrypoint: "example.com/app/internal/db.NewCmd", wantUse: "db"},
+		{name: "empty", entrypoint: "", wantErr: "requires an entrypoint"},
+		{name: "invalid format", entrypoint: "NewRootCmd", wantErr: "invalid format"},
+		{name: "unknown package", entrypoint: "example.com/other.NewRootCmd", wantErr: "package example.com/other not found"},
+		{name: "unknown function", entrypoint: "example.com/app/cmd.NewCmd", wantErr: "function NewCmd not found"},
+		{name: "function without a command", entrypoint: "main.main", wantErr: "does not return a cobra.Command literal"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli, err := InspectProjectFromBuildOutput(output, tt.entrypoint)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if cli.Use != tt.wantUse {
+				t.Errorf("Use = %q, want %q", cli.Use, tt.wantUse)
+			}
+		})
+	}
+}
+
+func TestInspectProjectFromBuildOutput_InvalidOutput(t *testing.T) {
+	_, err := InspectProjectFromBuildOutput([]byte("{not json"), "example.com/app/cmd.NewRootCmd")
+	if err == nil || !strings.Contains(err.Error(), "invalid go list output") {
+		t.Errorf("error = %v, want invalid go list output", err)
+	}
+}
+
+func TestParseFlagMethod(t *testing.T) {
+	tests := []struct {
+		method        string
+		wantType      string
+		wantVar       bool
+		wantShorthand bool
+	}{
+		{"String", "string", false, false},
+		{"StringP", "string", false, true},
+		{"StringVar", "string", true, false},
+		{"StringVarP", "string", true, true},
+		{"IPMaskVarP", "ipMask", true, true},
+		{"Var", "", false, false},
+		{"VarP", "", false, false},
+		{"Lookup", "", false, false},
+	}
+	for _, tt := range tests {
+		flagType, hasVar, hasShorthand := parseFlagMethod(tt.method)
+		if flagType != tt.wantType || hasVar != tt.wantVar || hasShorthand != tt.wantShorthand {
+			t.Errorf("parseFlagMethod(%q) = %q, %v, %v", tt.method, flagType, hasVar, hasShorthand)
+		}
+	}
+}
+
+func TestStaticInspector_Inspect(t *testing.T) {
+	dir, output := writeStaticTestProject(t)
+	mock := &executor.MockExecutor{Results: map[string]executor.MockResult{
+		"go list -e -json ./...": {Output: output},
+	}}
+
+	cli, err := NewStaticInspector(StaticConfig{
+		ProjectPath: dir,
+		Entrypoint:  "example.com/app/cmd.NewRootCmd",
+		Executor:    mock,
+	}).Inspect()
+	if err != nil {
+		t.Fatalf("Inspect() error = %v", err)
+	}
+	if cli.Use != "app" || len(cli.Commands) != 3 {
+		t.Errorf("Inspect() = %+v", cli)
+	}
+	if len(mock.Commands) != 1 || mock.Commands[0].Dir != dir {
+		t.Errorf("commands run = %+v, want go list in %s", mock.Commands, dir)
+	}
+}
+
+func TestStaticInspector_InspectListError(t *testing.T) {
+	mock := &executor.MockExecutor{}
+	_, err := NewStaticInspector(StaticConfig{
+		ProjectPath: t.TempDir(),
+		Entrypoint:  "example.com/app/cmd.NewRootCmd",
+		Executor:    mock,
+	}).Inspect()
+	if err == nil || !strings.Contains(err.Error(), "failed to list packages") {
+		t.Errorf("Inspect() error = %v, want failed to list packages", err)
+	}
+}
@@ -1,5 +1,7 @@
 package inspector
 
+import "strings"
+
 // InspectedCLI represents the actual CLI structure found by inspection.
 // This is the result of analyzing a cobra-based CLI application to extract
 // its complete command tree, flags, and metadata.
@@ -44,6 +46,23 @@ type InspectedCLI struct {
 	
 	// Example contains usage examples for this CLI (omitempty)
 	Example string `json:"example,omitempty"`
+
+	// Version is the version from cobra.Command.Version, which Cobra
+	// offers as the --version flag
+	Version string `json:"version,omitempty"`
+
+	// VersionOutput is what the CLI printed for its version command, when
+	// inspected with Config.VersionOutput
+	VersionOutput string `json:"version_output,omitempty"`
+
+	// MutuallyExclusive holds the groups of flags marked with
+	// cmd.MarkFlagsMutuallyExclusive. Each group lists its flag names in
+	// sorted order, and the groups are sorted too.
+	MutuallyExclusive [][]string `json:"mutually_exclusive,omitempty"`
+
+	// RequiredTogether holds the groups of flags marked with
+	// cmd.MarkFlagsRequiredTogether, sorted like MutuallyExclusive
+	RequiredTogether [][]string `json:"required_together,omitempty"`
 	
 	// Commands contains all direct subcommands
 	Commands []InspectedCommand `json:"commands,omitempty"`
@@ -69,11 +88,52 @@ type InspectedCommand struct {
 	
 	// Example contains usage examples for this command (omitempty)
 	Example string `json:"example,omitempty"`
+
+	// Hidden indicates the command is hidden from help output (cobra.Command.Hidden)
+	Hidden bool `json:"hidden,omitempty"`
+
+	// Runnable indicates the command sets Run, RunE, PreRun, PreRunE,
+	// PostRun or PostRunE. Commands that only group subcommands are not
+	// runnable.
+	Runnable bool `json:"runnable,omitempty"`
+
+	// GroupID is the help group the command belongs to (cobra.Command.GroupID).
+	// Only extracted for projects using Cobra ModernCobraVersion or newer.
+	GroupID string `json:"group_id,omitempty"`
+
+	// MutuallyExclusive holds the groups of flags marked with
+	// cmd.MarkFlagsMutuallyExclusive (see InspectedCLI.MutuallyExclusive)
+	MutuallyExclusive [][]string `json:"mutually_exclusive,omitempty"`
+
+	// RequiredTogether holds the groups of flags marked with
+	// cmd.MarkFlagsRequiredTogether
+	RequiredTogether [][]string `json:"required_together,omitempty"`
 	
 	// Commands contains nested subcommands
 	Commands []InspectedCommand `json:"commands,omitempty"`
 }
 
+// UseName returns the command name of the CLI: the first word of Use,
+// without the argument pattern
+func (c *InspectedCLI) UseName() string {
+	return useName(c.Use)
+}
+
+// UseName returns the command name: the first word of Use, without the
+// argument pattern, as cobra.Command.Name returns it
+func (c *InspectedCommand) UseName() string {
+	return useName(c.Use)
+}
+
+// useName returns the first word of a cobra.Command.Use string
+func useName(use string) string {
+	fields := strings.Fields(use)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
+
 // InspectedFlag represents an actual flag found by inspection.
 // Flags are extracted from cobra/pflag flag sets with their complete metadata.
 type InspectedFlag struct {
@@ -92,4 +152,33 @@ type InspectedFlag struct {
 
 	// Persistent indicates if the flag is inherited by subcommands
 	Persistent bool `json:"persistent"`
+
+	// Completion is the shell completion registered for the flag: "file",
+	// "dir" or "custom", or empty if none. Custom completion functions are
+	// only detected for projects using Cobra FlagCompletionLookupVersion or newer.
+	Completion string `json:"completion,omitempty"`
+
+	// Default is the flag's default value as printed by pflag (pflag.Flag.DefValue)
+	Default string `json:"default,omitempty"`
+
+	// Required indicates the flag was marked required with cmd.MarkFlagRequired
+	Required bool `json:"required,omitempty"`
+
+	// Enum holds the values returned by the flag's completion function. It
+	// is only populated when Config.CompletionValues is set.
+	Enum []string `json:"enum,omitempty"`
+
+	// Deprecated is the message the flag was marked deprecated with
+	// (pflag.Flag.Deprecated), or empty if it is not deprecated
+	Deprecated string `json:"deprecated,omitempty"`
+
+	// Hidden indicates the flag was hidden with MarkHidden, not by
+	// MarkDeprecated. Hidden flags are only inspected when
+	// Config.HiddenFlags is set.
+	Hidden bool `json:"hidden,omitempty"`
+
+	// Env is the environment variable the flag falls back to. The inspector
+	// program can't see it; it is found in the project source (see
+	// discovery.FindEnvBindings) by the services that need it.
+	Env string `json:"env,omitempty"`
 }
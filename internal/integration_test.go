@@ -0,0 +1,462 @@
+//go:build integration
+
+package internal_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/contract"
+	"github.com/hiAndrewQuinn/cliguard/internal/inspector"
+	"github.com/hiAndrewQuinn/cliguard/internal/service"
+	"github.com/hiAndrewQuinn/cliguard/internal/validator"
+)
+
+// TestCliguardSelfValidation validates cliguard's own CLI against its
+// contract in test-suite/self-validation. It fails when a command or flag
+// changes without the contract being regenerated:
+//
+//	go run . generate --entrypoint github.com/hiAndrewQuinn/cliguard/cmd.NewRootCmd \
+//	    --strip-completion-command=false > test-suite/self-validation/cliguard.yaml
+//
+// Run with: go test -tags integration ./internal/
+func TestCliguardSelfValidation(t *testing.T) {
+	projectPath, err := filepath.Abs("..")
+	if err != nil {
+		t.Fatalf("failed to resolve module root: %v", err)
+	}
+
+	svc := service.NewValidateService()
+	result, err := svc.Validate(service.ValidateOptions{
+		ProjectPath:  projectPath,
+		ContractPath: filepath.Join(projectPath, "test-suite", "self-validation", "cliguard.yaml"),
+		Entrypoint:   "github.com/hiAndrewQuinn/cliguard/cmd.NewRootCmd",
+	})
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	if !result.Success {
+		for _, e := range result.Result.Errors {
+			t.Errorf("%s: %s (contract: %q, actual: %q)", e.Path, e.Message, e.Expected, e.Actual)
+		}
+		t.Fatal("cliguard's CLI does not match test-suite/self-validation/cliguard.yaml; regenerate the contract if the change is intended")
+	}
+}
+
+// TestCliguardFromBinary reconstructs cliguard's CLI from the --help output
+// of its compiled binary and checks it against the self-validation contract.
+func TestCliguardFromBinary(t *testing.T) {
+	projectPath, err := filepath.Abs("..")
+	if err != nil {
+		t.Fatalf("failed to resolve module root: %v", err)
+	}
+
+	binaryPath := filepath.Join(t.TempDir(), "cliguard")
+	build := exec.Command("go", "build", "-o", binaryPath, ".")
+	build.Dir = projectPath
+	if output, err := build.CombinedOutput(); err != nil {
+		t.Fatalf("failed to build cliguard: %v\n%s", err, output)
+	}
+
+	inspected, err := inspector.InspectBinary(binaryPath, 0)
+	if err != nil {
+		t.Fatalf("InspectBinary() error = %v", err)
+	}
+
+	known, err := contract.Load(filepath.Join(projectPath, "test-suite", "self-validation", "cliguard.yaml"))
+	if err != nil {
+		t.Fatalf("failed to load contract: %v", err)
+	}
+	// Help shows the root's Long description only, so its Short cannot be recovered
+	known.Short = ""
+	// Help does not show which flags are required
+	clearRequired(known.Flags)
+	clearRequiredInCommands(known.Commands)
+	// ...nor flag groups
+	known.MutuallyExclusive, known.RequiredTogether = nil, nil
+	clearFlagGroups(known.Commands)
+	// ...nor deprecated flags, which it hides
+	known.Flags = withoutDeprecated(known.Flags)
+	removeDeprecatedInCommands(known.Commands)
+	// ...nor flag completions
+	clearCompletions(known.Flags)
+	clearCompletionsInCommands(known.Commands)
+	// The completion command keeps Cobra's short description, so InspectBinary
+	// takes it for the default one and leaves it out
+	known.Commands = withoutCommand(known.Commands, "completion")
+
+	result := validator.Validate(known, inspected)
+	for _, e := range result.Errors {
+		t.Errorf("%s: %s (contract: %q, actual: %q)", e.Path, e.Message, e.Expected, e.Actual)
+	}
+}
+
+// clearRequired unmarks flags as required
+func clearRequired(flags []contract.Flag) {
+	for i := range flags {
+		flags[i].Required = false
+	}
+}
+
+// clearRequiredInCommands unmarks the flags of commands and their
+// subcommands as required
+func clearRequiredInCommands(commands []contract.Command) {
+	for i := range commands {
+		clearRequired(commands[i].Flags)
+		clearRequiredInCommands(commands[i].Commands)
+	}
+}
+
+// clearFlagGroups removes the flag groups of commands and their subcommands
+func clearFlagGroups(commands []contract.Command) {
+	for i := range commands {
+		commands[i].MutuallyExclusive, commands[i].RequiredTogether = nil, nil
+		clearFlagGroups(commands[i].Commands)
+	}
+}
+
+// clearCompletions removes the completions of flags
+func clearCompletions(flags []contract.Flag) {
+	for i := range flags {
+		flags[i].Completion = ""
+	}
+}
+
+// clearCompletionsInCommands removes the completions of the flags of
+// commands and their subcommands
+func clearCompletionsInCommands(commands []contract.Command) {
+	for i := range commands {
+		clearCompletions(commands[i].Flags)
+		clearCompletionsInCommands(commands[i].Commands)
+	}
+}
+
+// withoutCommand returns the commands other than the one named name
+func withoutCommand(commands []contract.Command, name string) []contract.Command {
+	var kept []contract.Command
+	for _, cmd := range commands {
+		if cmd.Use != name {
+			kept = append(kept, cmd)
+		}
+	}
+	return kept
+}
+
+// withoutDeprecated returns the flags that are not deprecated
+func withoutDeprecated(flags []contract.Flag) []contract.Flag {
+	var kept []contract.Flag
+	for _, flag := range flags {
+		if flag.Deprecated == "" {
+			kept = append(kept, flag)
+		}
+	}
+	return kept
+}
+
+// removeDeprecatedInCommands removes the deprecated flags of commands and
+// their subcommands
+func removeDeprecatedInCommands(commands []contract.Command) {
+	for i := range commands {
+		commands[i].Flags = withoutDeprecated(commands[i].Flags)
+		removeDeprecatedInCommands(commands[i].Commands)
+	}
+}
+
+// TestInspectProjectWithLocalReplace inspects a project whose go.mod replaces
+// a dependency with a sibling directory. The dependency cannot be downloaded,
+// so inspection only succeeds if the replace directive is carried over.
+func TestInspectProjectWithLocalReplace(t *testing.T) {
+	root := t.TempDir()
+	goSum, err := os.ReadFile(filepath.Join("..", "go.sum"))
+	if err != nil {
+		t.Fatalf("failed to read go.sum: %v", err)
+	}
+
+	files := map[string]string{
+		"greeting/go.mod": "module example.com/greeting\n\ngo 1.24\n",
+		"greeting/greeting.go": `package greeting
+
+func Short() string { return "Say hello" }
+`,
+		"app/go.mod": `module example.com/app
+
+go 1.24
+
+require (
+	example.com/greeting v0.0.0
+	github.com/spf13/cobra v1.9.1
+)
+
+replace example.com/greeting => ../greeting
+`,
+		"app/go.sum": string(goSum),
+		"app/cmd/root.go": `package cmd
+
+import (
+	"example.com/greeting"
+	"github.com/spf13/cobra"
+)
+
+func NewRootCmd() *cobra.Command {
+	return &cobra.Command{Use: "hello", Short: greeting.Short()}
+}
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cli, err := inspector.InspectProject(filepath.Join(root, "app"), "example.com/app/cmd.NewRootCmd")
+	if err != nil {
+		t.Fatalf("InspectProject() error = %v", err)
+	}
+	if cli.Short != "Say hello" {
+		t.Errorf("Short = %q, want %q", cli.Short, "Say hello")
+	}
+}
+
+// TestGenerateWithValidation generates a contract for the completions fixture
+// and checks that flag enums are read from its completion functions.
+func TestGenerateWithValidation(t *testing.T) {
+	projectPath, err := filepath.Abs(filepath.Join("..", "test-suite", "edge-cases", "completions"))
+	if err != nil {
+		t.Fatalf("failed to resolve fixture: %v", err)
+	}
+
+	output, err := service.NewGenerateService().Generate(service.GenerateOptions{
+		ProjectPath:    projectPath,
+		Entrypoint:     "github.com/cliguard/test/completions/cmd.NewRootCmd",
+		WithValidation: true,
+		StripDefaults:  true,
+	})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	contractPath := filepath.Join(t.TempDir(), "cliguard.yaml")
+	if err := os.WriteFile(contractPath, []byte(output), 0644); err != nil {
+		t.Fatal(err)
+	}
+	generated, err := contract.Load(contractPath)
+	if err != nil {
+		t.Fatalf("failed to load generated contract: %v\n%s", err, output)
+	}
+
+	enums := make(map[string][]string)
+	for _, flag := range generated.Commands[0].Flags {
+		enums[flag.Name] = flag.Enum
+	}
+	want := map[string][]string{
+		"manifest": nil,
+		"region":   {"us-east-1", "eu-west-1", "ap-southeast-2"},
+		"tier":     {"free", "pro"},
+	}
+	if !reflect.DeepEqual(enums, want) {
+		t.Errorf("flag enums = %v, want %v", enums, want)
+	}
+
+	result, err := service.NewValidateService().Validate(service.ValidateOptions{
+		ProjectPath:  projectPath,
+		ContractPath: filepath.Join(projectPath, "contract.yaml"),
+		Entrypoint:   "github.com/cliguard/test/completions/cmd.NewRootCmd",
+	})
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	for _, e := range result.Result.Errors {
+		t.Errorf("%s: %s (contract: %q, actual: %q)", e.Path, e.Message, e.Expected, e.Actual)
+	}
+}
+
+// TestDeprecatedFlags checks that the deprecation messages of the flag-types
+// fixture are inspected, and that removing one from the contract fails
+// validation.
+func TestDeprecatedFlags(t *testing.T) {
+	projectPath, err := filepath.Abs(filepath.Join("..", "test-suite", "edge-cases", "flag-types"))
+	if err != nil {
+		t.Fatalf("failed to resolve fixture: %v", err)
+	}
+
+	cli, err := inspector.InspectProject(projectPath, "github.com/cliguard/test/flagtypes/cmd.NewRootCmd")
+	if err != nil {
+		t.Fatalf("InspectProject() error = %v", err)
+	}
+	expected, err := contract.Load(filepath.Join(projectPath, "contract.yaml"))
+	if err != nil {
+		t.Fatalf("failed to load contract: %v", err)
+	}
+	for _, e := range validator.Validate(expected, cli).Errors {
+		t.Errorf("%s: %s (contract: %q, actual: %q)", e.Path, e.Message, e.Expected, e.Actual)
+	}
+
+	for i, flag := range expected.Flags {
+		if flag.Name == "deprecated-flag" {
+			expected.Flags[i].Deprecated = ""
+		}
+	}
+	result := validator.Validate(expected, cli)
+	if len(result.Errors) != 1 || result.Errors[0].Path != "--deprecated-flag" || result.Errors[0].Actual != "deprecated: use --new-flag instead" {
+		t.Errorf("errors = %+v, want a deprecation mismatch for --deprecated-flag", result.Errors)
+	}
+}
+
+// TestHiddenAndDeprecatedFlags generates a contract for the flag-types
+// fixture including its hidden and deprecated flags, checks that both are
+// recorded, and validates the fixture against it.
+func TestHiddenAndDeprecatedFlags(t *testing.T) {
+	projectPath, err := filepath.Abs(filepath.Join("..", "test-suite", "edge-cases", "flag-types"))
+	if err != nil {
+		t.Fatalf("failed to resolve fixture: %v", err)
+	}
+	const entrypoint = "github.com/cliguard/test/flagtypes/cmd.NewRootCmd"
+
+	output, err := service.NewGenerateService().Generate(service.GenerateOptions{
+		ProjectPath:        projectPath,
+		Entrypoint:         entrypoint,
+		IncludeHiddenFlags: true,
+	})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	contractPath := filepath.Join(t.TempDir(), "cliguard.yaml")
+	if err := os.WriteFile(contractPath, []byte(output), 0644); err != nil {
+		t.Fatal(err)
+	}
+	generated, err := contract.Load(contractPath)
+	if err != nil {
+		t.Fatalf("failed to load generated contract: %v\n%s", err, output)
+	}
+
+	flags := make(map[string]contract.Flag)
+	for _, flag := range generated.Flags {
+		flags[flag.Name] = flag
+	}
+	if flag, ok := flags["hidden-flag"]; !ok || !flag.Hidden || flag.Deprecated != "" {
+		t.Errorf("--hidden-flag = %+v, want it hidden\n%s", flag, output)
+	}
+	if flag, ok := flags["deprecated-flag"]; !ok || flag.Hidden || flag.Deprecated != "use --new-flag instead" {
+		t.Errorf("--deprecated-flag = %+v, want it deprecated and not hidden\n%s", flag, output)
+	}
+	if !strings.Contains(output, "hidden: true") || !strings.Contains(output, "deprecated: use --new-flag instead") {
+		t.Errorf("generated contract does not record the hidden and deprecated flags:\n%s", output)
+	}
+
+	result, err := service.NewValidateService().Validate(service.ValidateOptions{
+		ProjectPath:  projectPath,
+		ContractPath: contractPath,
+		Entrypoint:   entrypoint,
+	})
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	for _, e := range result.Result.Errors {
+		t.Errorf("%s: %s (contract: %q, actual: %q)", e.Path, e.Message, e.Expected, e.Actual)
+	}
+	if !result.Success {
+		t.Error("the fixture does not match the contract generated from it")
+	}
+}
+
+// TestCLISnapshotRoundTrip writes a CLI snapshot while generating a contract
+// for the subcommands fixture, then checks that validating against the
+// snapshot gives the same result as validating the built fixture.
+func TestCLISnapshotRoundTrip(t *testing.T) {
+	projectPath, err := filepath.Abs(filepath.Join("..", "test-suite", "basic", "subcommands"))
+	if err != nil {
+		t.Fatalf("failed to resolve fixture: %v", err)
+	}
+	const entrypoint = "github.com/cliguard/test/subcommands/cmd.NewRootCmd"
+
+	dir := t.TempDir()
+	snapshotPath := filepath.Join(dir, "cli.json")
+	output, err := service.NewGenerateService().Generate(service.GenerateOptions{
+		ProjectPath:   projectPath,
+		Entrypoint:    entrypoint,
+		WriteSnapshot: snapshotPath,
+	})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	contractPath := filepath.Join(dir, "cliguard.yaml")
+	if err := os.WriteFile(contractPath, []byte(output), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	live, err := service.NewValidateService().Validate(service.ValidateOptions{
+		ProjectPath:  projectPath,
+		ContractPath: contractPath,
+		Entrypoint:   entrypoint,
+	})
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	fromSnapshot, err := service.NewValidateService().Validate(service.ValidateOptions{
+		ProjectPath:  projectPath,
+		ContractPath: contractPath,
+		CLISnapshot:  snapshotPath,
+	})
+	if err != nil {
+		t.Fatalf("Validate() with snapshot error = %v", err)
+	}
+
+	if !fromSnapshot.Success {
+		t.Error("the snapshot does not match the contract generated with it")
+	}
+	if !reflect.DeepEqual(live, fromSnapshot) {
+		t.Errorf("snapshot result = %+v, want the live result %+v", fromSnapshot, live)
+	}
+}
+
+// TestFlagGroups inspects the flag-groups fixture, validates it against its
+// contract and checks that dropping a group from the CLI fails validation.
+func TestFlagGroups(t *testing.T) {
+	projectPath, err := filepath.Abs(filepath.Join("..", "test-suite", "edge-cases", "flag-groups"))
+	if err != nil {
+		t.Fatalf("failed to resolve fixture: %v", err)
+	}
+
+	cli, err := inspector.InspectProject(projectPath, "github.com/cliguard/test/flaggroups/cmd.NewRootCmd")
+	if err != nil {
+		t.Fatalf("InspectProject() error = %v", err)
+	}
+	if want := [][]string{{"json", "yaml"}}; !reflect.DeepEqual(cli.MutuallyExclusive, want) {
+		t.Errorf("root MutuallyExclusive = %v, want %v", cli.MutuallyExclusive, want)
+	}
+	login := cli.Commands[0]
+	// The root's group is inherited, not repeated, and so are its flags
+	if want := [][]string{{"json", "token", "username"}, {"password", "password-stdin"}}; !reflect.DeepEqual(login.MutuallyExclusive, want) {
+		t.Errorf("login MutuallyExclusive = %v, want %v", login.MutuallyExclusive, want)
+	}
+	if want := [][]string{{"password", "username"}}; !reflect.DeepEqual(login.RequiredTogether, want) {
+		t.Errorf("login RequiredTogether = %v, want %v", login.RequiredTogether, want)
+	}
+	if len(login.Flags) != 4 {
+		t.Errorf("login flags = %+v, want its 4 own flags", login.Flags)
+	}
+
+	expected, err := contract.Load(filepath.Join(projectPath, "contract.yaml"))
+	if err != nil {
+		t.Fatalf("failed to load contract: %v", err)
+	}
+	for _, e := range validator.Validate(expected, cli).Errors {
+		t.Errorf("%s: %s (contract: %q, actual: %q)", e.Path, e.Message, e.Expected, e.Actual)
+	}
+
+	cli.Commands[0].RequiredTogether = nil
+	result := validator.Validate(expected, cli)
+	if len(result.Errors) != 1 || result.Errors[0].Path != "login" || result.Errors[0].Message != "Mismatch in required together flags" {
+		t.Errorf("errors = %+v, want a required together mismatch for login", result.Errors)
+	}
+}
@@ -0,0 +1,247 @@
+package lint
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/contract"
+)
+
+// PlaceholderUsage is the usage string MissingUsageFixer adds to flags
+const PlaceholderUsage = "TODO: add description"
+
+// LintFixer repairs one kind of lint warning
+type LintFixer interface {
+	// CanFix reports whether the fixer repairs warning
+	CanFix(warning LintWarning) bool
+
+	// Apply returns a copy of the contract with every instance of the
+	// problem fixed. The original contract is not modified.
+	Apply(c *contract.Contract) *contract.Contract
+
+	// Description says what Apply does, e.g. "capitalized short descriptions"
+	Description() string
+}
+
+// AppliedFix is a fixer that Fix applied and the warnings it repaired
+type AppliedFix struct {
+	Fixer    LintFixer
+	Warnings []LintWarning
+}
+
+// Fixers returns the fixers in the order Fix applies them, from least to
+// most destructive: adding text comes first, rewording descriptions next,
+// and renaming flags, which breaks scripts that use the old names, last.
+func Fixers() []LintFixer {
+	return []LintFixer{
+		&MissingUsageFixer{},
+		&CapitalizeShortFixer{},
+		&TrailingPeriodFixer{},
+		&TruncateShortFixer{MaxLength: MaxShortLength},
+		&KebabCaseFlagFixer{},
+	}
+}
+
+// Fix applies each fixer that can repair one of the contract's warnings and
+// returns the fixed contract and the fixes applied, in order. The contract
+// is linted again before each fixer, so a warning resolved by an earlier fix
+// (e.g. a description that is only too long because of its trailing period)
+// is not fixed twice.
+func Fix(c *contract.Contract) (*contract.Contract, []AppliedFix) {
+	var applied []AppliedFix
+	for _, fixer := range Fixers() {
+		var fixable []LintWarning
+		for _, warning := range Lint(c) {
+			if fixer.CanFix(warning) {
+				fixable = append(fixable, warning)
+			}
+		}
+		if len(fixable) == 0 {
+			continue
+		}
+		c = fixer.Apply(c)
+		applied = append(applied, AppliedFix{Fixer: fixer, Warnings: fixable})
+	}
+	return c, applied
+}
+
+// MissingUsageFixer adds PlaceholderUsage to flags without a usage string
+type MissingUsageFixer struct{}
+
+// CanFix reports whether warning is a missing usage string
+func (f *MissingUsageFixer) CanFix(warning LintWarning) bool {
+	return warning.Rule == RuleMissingUsage
+}
+
+// Apply adds the placeholder usage
+func (f *MissingUsageFixer) Apply(c *contract.Contract) *contract.Contract {
+	return rewrite(c, nil, eachFlag(func(flag contract.Flag) contract.Flag {
+		if strings.TrimSpace(flag.Usage) == "" {
+			flag.Usage = PlaceholderUsage
+		}
+		return flag
+	}))
+}
+
+// Description says what Apply does
+func (f *MissingUsageFixer) Description() string {
+	return "added placeholder usage strings"
+}
+
+// CapitalizeShortFixer capitalizes the first letter of short descriptions
+type CapitalizeShortFixer struct{}
+
+// CanFix reports whether warning is an uncapitalized short description
+func (f *CapitalizeShortFixer) CanFix(warning LintWarning) bool {
+	return warning.Rule == RuleShortCapitalization
+}
+
+// Apply capitalizes the short descriptions
+func (f *CapitalizeShortFixer) Apply(c *contract.Contract) *contract.Contract {
+	return rewrite(c, func(short string) string {
+		first, size := utf8.DecodeRuneInString(short)
+		if !unicode.IsLower(first) {
+			return short
+		}
+		return string(unicode.ToUpper(first)) + short[size:]
+	}, nil)
+}
+
+// Description says what Apply does
+func (f *CapitalizeShortFixer) Description() string {
+	return "capitalized short descriptions"
+}
+
+// TrailingPeriodFixer removes the period at the end of short descriptions
+type TrailingPeriodFixer struct{}
+
+// CanFix reports whether warning is a short description ending with a period
+func (f *TrailingPeriodFixer) CanFix(warning LintWarning) bool {
+	return warning.Rule == RuleShortTrailingPeriod
+}
+
+// Apply removes the trailing periods
+func (f *TrailingPeriodFixer) Apply(c *contract.Contract) *contract.Contract {
+	return rewrite(c, func(short string) string {
+		if !hasTrailingPeriod(short) {
+			return short
+		}
+		return strings.TrimRightFunc(strings.TrimSuffix(short, "."), unicode.IsSpace)
+	}, nil)
+}
+
+// Description says what Apply does
+func (f *TrailingPeriodFixer) Description() string {
+	return "removed trailing periods from short descriptions"
+}
+
+// TruncateShortFixer shortens short descriptions to MaxLength characters,
+// ending them with "..."
+type TruncateShortFixer struct {
+	MaxLength int
+}
+
+// CanFix reports whether warning is an over-length short description
+func (f *TruncateShortFixer) CanFix(warning LintWarning) bool {
+	return warning.Rule == RuleShortLength
+}
+
+// Apply truncates the short descriptions
+func (f *TruncateShortFixer) Apply(c *contract.Contract) *contract.Contract {
+	return rewrite(c, func(short string) string {
+		runes := []rune(short)
+		if len(runes) <= f.MaxLength {
+			return short
+		}
+		kept := strings.TrimRightFunc(string(runes[:f.MaxLength-3]), unicode.IsSpace)
+		return kept + "..."
+	}, nil)
+}
+
+// Description says what Apply does
+func (f *TruncateShortFixer) Description() string {
+	return "truncated over-length short descriptions"
+}
+
+// KebabCaseFlagFixer renames camelCase and snake_case flags to kebab-case.
+// A flag is left alone if its new name is already taken on the same command.
+type KebabCaseFlagFixer struct{}
+
+// CanFix reports whether warning is a flag name that is not kebab-case
+func (f *KebabCaseFlagFixer) CanFix(warning LintWarning) bool {
+	return warning.Rule == RuleFlagKebabCase
+}
+
+// Apply renames the flags
+func (f *KebabCaseFlagFixer) Apply(c *contract.Contract) *contract.Contract {
+	return rewrite(c, nil, renameFlags)
+}
+
+// Description says what Apply does
+func (f *KebabCaseFlagFixer) Description() string {
+	return "renamed flags to kebab-case"
+}
+
+// renameFlags returns a copy of one command's flags with kebab-case names
+func renameFlags(flags []contract.Flag) []contract.Flag {
+	taken := make(map[string]bool, len(flags))
+	for _, flag := range flags {
+		taken[flag.Name] = true
+	}
+	return eachFlag(func(flag contract.Flag) contract.Flag {
+		if kebab := KebabCase(flag.Name); kebab != flag.Name && !taken[kebab] {
+			taken[kebab] = true
+			flag.Name = kebab
+		}
+		return flag
+	})(flags)
+}
+
+// rewrite returns a copy of the contract with every short description
+// passed through short and every command's flags through flags. Either may
+// be nil.
+func rewrite(c *contract.Contract, short func(string) string, flags func([]contract.Flag) []contract.Flag) *contract.Contract {
+	fixed := *c
+	if short != nil && fixed.Short != "" {
+		fixed.Short = short(fixed.Short)
+	}
+	if flags != nil {
+		fixed.Flags = flags(c.Flags)
+	}
+	fixed.Commands = rewriteCommands(c.Commands, short, flags)
+	return &fixed
+}
+
+// rewriteCommands copies commands recursively for rewrite
+func rewriteCommands(commands []contract.Command, short func(string) string, flags func([]contract.Flag) []contract.Flag) []contract.Command {
+	if commands == nil {
+		return nil
+	}
+	result := make([]contract.Command, len(commands))
+	for i, cmd := range commands {
+		if short != nil && cmd.Short != "" {
+			cmd.Short = short(cmd.Short)
+		}
+		if flags != nil {
+			cmd.Flags = flags(cmd.Flags)
+		}
+		cmd.Commands = rewriteCommands(cmd.Commands, short, flags)
+		result[i] = cmd
+	}
+	return result
+}
+
+// eachFlag returns a rewrite function that passes each flag through fix
+func eachFlag(fix func(contract.Flag) contract.Flag) func([]contract.Flag) []contract.Flag {
+	return func(flags []contract.Flag) []contract.Flag {
+		if flags == nil {
+			return nil
+		}
+		result := make([]contract.Flag, len(flags))
+		for i, flag := range flags {
+			result[i] = fix(flag)
+		}
+		return result
+	}
+}
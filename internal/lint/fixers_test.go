@@ -0,0 +1,189 @@
+package lint
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/contract"
+)
+
+func TestFixers(t *testing.T) {
+	tests := []struct {
+		name  string
+		fixer LintFixer
+		rule  Rule
+		input *contract.Contract
+		want  *contract.Contract
+	}{
+		{
+			name:  "missing usage",
+			fixer: &MissingUsageFixer{},
+			rule:  RuleMissingUsage,
+			input: &contract.Contract{
+				Use:      "app",
+				Flags:    []contract.Flag{{Name: "output", Type: "string"}, {Name: "port", Type: "int", Usage: "Port"}},
+				Commands: []contract.Command{{Use: "serve", Flags: []contract.Flag{{Name: "host", Type: "string", Usage: "  "}}}},
+			},
+			want: &contract.Contract{
+				Use:      "app",
+				Flags:    []contract.Flag{{Name: "output", Type: "string", Usage: PlaceholderUsage}, {Name: "port", Type: "int", Usage: "Port"}},
+				Commands: []contract.Command{{Use: "serve", Flags: []contract.Flag{{Name: "host", Type: "string", Usage: PlaceholderUsage}}}},
+			},
+		},
+		{
+			name:  "capitalize short",
+			fixer: &CapitalizeShortFixer{},
+			rule:  RuleShortCapitalization,
+			input: &contract.Contract{
+				Use:      "app",
+				Short:    "my app",
+				Commands: []contract.Command{{Use: "serve", Short: "échoue"}, {Use: "run", Short: "--dry-run only"}},
+			},
+			want: &contract.Contract{
+				Use:      "app",
+				Short:    "My app",
+				Commands: []contract.Command{{Use: "serve", Short: "Échoue"}, {Use: "run", Short: "--dry-run only"}},
+			},
+		},
+		{
+			name:  "trailing period",
+			fixer: &TrailingPeriodFixer{},
+			rule:  RuleShortTrailingPeriod,
+			input: &contract.Contract{
+				Use:      "app",
+				Short:    "My app .",
+				Commands: []contract.Command{{Use: "serve", Short: "Start..."}},
+			},
+			want: &contract.Contract{
+				Use:      "app",
+				Short:    "My app",
+				Commands: []contract.Command{{Use: "serve", Short: "Start..."}},
+			},
+		},
+		{
+			name:  "truncate short",
+			fixer: &TruncateShortFixer{MaxLength: 10},
+			rule:  RuleShortLength,
+			input: &contract.Contract{
+				Use:      "app",
+				Short:    "Start the server",
+				Commands: []contract.Command{{Use: "serve", Short: "Short one"}, {Use: "run", Short: "Ünïcödé runes"}},
+			},
+			want: &contract.Contract{
+				Use:      "app",
+				Short:    "Start t...",
+				Commands: []contract.Command{{Use: "serve", Short: "Short one"}, {Use: "run", Short: "Ünïcödé..."}},
+			},
+		},
+		{
+			name:  "kebab-case flags",
+			fixer: &KebabCaseFlagFixer{},
+			rule:  RuleFlagKebabCase,
+			input: &contract.Contract{
+				Use:   "app",
+				Flags: []contract.Flag{{Name: "dryRun", Type: "bool"}, {Name: "dry-run", Type: "bool"}},
+				Commands: []contract.Command{{Use: "serve", Commands: []contract.Command{
+					{Use: "http", Flags: []contract.Flag{{Name: "listen_addr", Type: "string"}}},
+				}}},
+			},
+			want: &contract.Contract{
+				Use:   "app",
+				Flags: []contract.Flag{{Name: "dryRun", Type: "bool"}, {Name: "dry-run", Type: "bool"}},
+				Commands: []contract.Command{{Use: "serve", Commands: []contract.Command{
+					{Use: "http", Flags: []contract.Flag{{Name: "listen-addr", Type: "string"}}},
+				}}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !tt.fixer.CanFix(LintWarning{Rule: tt.rule}) {
+				t.Errorf("CanFix(%s) = false, want true", tt.rule)
+			}
+			if tt.fixer.CanFix(LintWarning{Rule: "other"}) {
+				t.Error("CanFix(other) = true, want false")
+			}
+
+			original := cloneForTest(t, tt.input)
+			got := tt.fixer.Apply(tt.input)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Apply() = %+v, want %+v", got, tt.want)
+			}
+			if !reflect.DeepEqual(tt.input, original) {
+				t.Errorf("Apply() modified its input: %+v", tt.input)
+			}
+		})
+	}
+}
+
+func TestFix(t *testing.T) {
+	// The short description is only too long because of its trailing
+	// period, so removing the period leaves nothing to truncate
+	padding := strings.Repeat("!", MaxShortLength-len("start the server"))
+	c := &contract.Contract{
+		Use:   "app",
+		Short: "start the server" + padding + ".",
+		Flags: []contract.Flag{{Name: "dryRun", Type: "bool"}},
+	}
+
+	fixed, applied := Fix(c)
+
+	want := &contract.Contract{
+		Use:   "app",
+		Short: "Start the server" + padding,
+		Flags: []contract.Flag{{Name: "dry-run", Type: "bool", Usage: PlaceholderUsage}},
+	}
+	if !reflect.DeepEqual(fixed, want) {
+		t.Errorf("Fix() = %+v, want %+v", fixed, want)
+	}
+	if remaining := Lint(fixed); len(remaining) != 0 {
+		t.Errorf("Lint() after Fix() = %+v, want none", remaining)
+	}
+
+	var descriptions []string
+	for _, fix := range applied {
+		descriptions = append(descriptions, fix.Fixer.Description())
+	}
+	wantDescriptions := []string{
+		"added placeholder usage strings",
+		"capitalized short descriptions",
+		"removed trailing periods from short descriptions",
+		"renamed flags to kebab-case",
+	}
+	if !reflect.DeepEqual(descriptions, wantDescriptions) {
+		t.Errorf("applied fixes = %v, want %v", descriptions, wantDescriptions)
+	}
+}
+
+func TestFix_Clean(t *testing.T) {
+	c := &contract.Contract{Use: "app", Short: "My app"}
+	fixed, applied := Fix(c)
+	if len(applied) != 0 || !reflect.DeepEqual(fixed, c) {
+		t.Errorf("Fix() = %+v, %+v; want the contract unchanged", fixed, applied)
+	}
+}
+
+// cloneForTest copies a contract's flags and commands deeply enough to
+// detect modifications by a fixer
+func cloneForTest(t *testing.T, c *contract.Contract) *contract.Contract {
+	t.Helper()
+	clone := *c
+	clone.Flags = append([]contract.Flag(nil), c.Flags...)
+	clone.Commands = cloneCommands(c.Commands)
+	return &clone
+}
+
+func cloneCommands(commands []contract.Command) []contract.Command {
+	if commands == nil {
+		return nil
+	}
+	result := make([]contract.Command, len(commands))
+	for i, cmd := range commands {
+		cmd.Flags = append([]contract.Flag(nil), cmd.Flags...)
+		cmd.Commands = cloneCommands(cmd.Commands)
+		result[i] = cmd
+	}
+	return result
+}
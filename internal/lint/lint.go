@@ -0,0 +1,154 @@
+// Package lint checks cliguard contracts for style issues, such as short
+// descriptions that end with a period or camelCase flag names, and fixes
+// them.
+//
+// Example:
+//
+//	c, err := contract.Load("cliguard.yaml")
+//	if err != nil {
+//	    return err
+//	}
+//	fixed, applied := lint.Fix(c)
+package lint
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/contract"
+)
+
+// Rule identifies the check that produced a warning
+type Rule string
+
+const (
+	RuleShortCapitalization Rule = "short-capitalization"
+	RuleShortLength         Rule = "short-length"
+	RuleShortTrailingPeriod Rule = "short-trailing-period"
+	RuleFlagKebabCase       Rule = "flag-kebab-case"
+	RuleMissingUsage        Rule = "missing-usage"
+)
+
+// MaxShortLength is the longest short description that fits on one line of
+// a command list in --help
+const MaxShortLength = 80
+
+// LintWarning is a single lint result
+type LintWarning struct {
+	Rule    Rule
+	Path    string
+	Message string
+}
+
+// Lint checks the contract for style issues and returns the warnings in
+// contract order: the root command, then each command depth-first.
+func Lint(c *contract.Contract) []LintWarning {
+	warnings := lintShort(c.Use, c.Short)
+	warnings = append(warnings, lintFlags(c.Use, c.Flags)...)
+	for _, cmd := range c.Commands {
+		warnings = append(warnings, lintCommand(c.Use, cmd)...)
+	}
+	return warnings
+}
+
+// lintCommand checks a subcommand and its children
+func lintCommand(parentPath string, cmd contract.Command) []LintWarning {
+	path := parentPath + " " + cmd.Use
+	warnings := lintShort(path, cmd.Short)
+	warnings = append(warnings, lintFlags(path, cmd.Flags)...)
+	for _, sub := range cmd.Commands {
+		warnings = append(warnings, lintCommand(path, sub)...)
+	}
+	return warnings
+}
+
+// lintShort checks a command's short description. Empty descriptions are
+// left to validation.
+func lintShort(path, short string) []LintWarning {
+	if short == "" {
+		return nil
+	}
+
+	var warnings []LintWarning
+	if first, _ := utf8.DecodeRuneInString(short); unicode.IsLower(first) {
+		warnings = append(warnings, LintWarning{
+			Rule:    RuleShortCapitalization,
+			Path:    path,
+			Message: "Short description should start with a capital letter",
+		})
+	}
+	if hasTrailingPeriod(short) {
+		warnings = append(warnings, LintWarning{
+			Rule:    RuleShortTrailingPeriod,
+			Path:    path,
+			Message: "Short description should not end with a period",
+		})
+	}
+	if length := utf8.RuneCountInString(short); length > MaxShortLength {
+		warnings = append(warnings, LintWarning{
+			Rule:    RuleShortLength,
+			Path:    path,
+			Message: fmt.Sprintf("Short description is %d characters long (maximum %d)", length, MaxShortLength),
+		})
+	}
+	return warnings
+}
+
+// lintFlags checks the flags defined on a single command
+func lintFlags(cmdPath string, flags []contract.Flag) []LintWarning {
+	var warnings []LintWarning
+	for _, flag := range flags {
+		path := cmdPath + " --" + flag.Name
+		if kebab := KebabCase(flag.Name); kebab != flag.Name {
+			warnings = append(warnings, LintWarning{
+				Rule:    RuleFlagKebabCase,
+				Path:    path,
+				Message: fmt.Sprintf("Flag name should be kebab-case: --%s", kebab),
+			})
+		}
+		if strings.TrimSpace(flag.Usage) == "" {
+			warnings = append(warnings, LintWarning{
+				Rule:    RuleMissingUsage,
+				Path:    path,
+				Message: "Flag has no usage description",
+			})
+		}
+	}
+	return warnings
+}
+
+// hasTrailingPeriod reports whether s ends with a single period. Ellipses
+// are allowed.
+func hasTrailingPeriod(s string) bool {
+	return strings.HasSuffix(s, ".") && !strings.HasSuffix(s, "...")
+}
+
+// KebabCase converts camelCase, snake_case and space separated names to
+// kebab-case, e.g. "dryRun" and "dry_run" become "dry-run" and
+// "listPetsByID" becomes "list-pets-by-id"
+func KebabCase(s string) string {
+	var b strings.Builder
+	runes := []rune(s)
+	separate := false
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			separate = b.Len() > 0
+			continue
+		}
+		if unicode.IsUpper(r) && b.Len() > 0 {
+			previousLower := i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]))
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if previousLower || (nextLower && unicode.IsUpper(runes[i-1])) {
+				separate = true
+			}
+		}
+		if separate {
+			b.WriteByte('-')
+			separate = false
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
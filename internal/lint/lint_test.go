@@ -0,0 +1,107 @@
+package lint
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/contract"
+)
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name     string
+		contract *contract.Contract
+		want     []Rule
+	}{
+		{
+			name: "clean contract",
+			contract: &contract.Contract{
+				Use:   "app",
+				Short: "My app...",
+				Flags: []contract.Flag{{Name: "dry-run", Type: "bool", Usage: "Print what would change"}},
+				Commands: []contract.Command{
+					{Use: "serve", Short: "Start the server", Flags: []contract.Flag{{Name: "port", Type: "int", Usage: "Port"}}},
+				},
+			},
+			want: nil,
+		},
+		{
+			name:     "empty short is not linted",
+			contract: &contract.Contract{Use: "app"},
+			want:     nil,
+		},
+		{
+			name:     "short description",
+			contract: &contract.Contract{Use: "app", Short: "my app."},
+			want:     []Rule{RuleShortCapitalization, RuleShortTrailingPeriod},
+		},
+		{
+			name:     "long short description",
+			contract: &contract.Contract{Use: "app", Short: strings.Repeat("A", MaxShortLength+1)},
+			want:     []Rule{RuleShortLength},
+		},
+		{
+			name: "flags",
+			contract: &contract.Contract{
+				Use: "app",
+				Commands: []contract.Command{
+					{Use: "serve", Flags: []contract.Flag{{Name: "listenAddr", Type: "string", Usage: " "}}},
+				},
+			},
+			want: []Rule{RuleFlagKebabCase, RuleMissingUsage},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []Rule
+			for _, warning := range Lint(tt.contract) {
+				got = append(got, warning.Rule)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lint() rules = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLint_Paths(t *testing.T) {
+	c := &contract.Contract{
+		Use: "app",
+		Commands: []contract.Command{
+			{Use: "db", Commands: []contract.Command{
+				{Use: "migrate", Short: "run migrations", Flags: []contract.Flag{{Name: "to_version", Type: "int", Usage: "Target"}}},
+			}},
+		},
+	}
+
+	want := []LintWarning{
+		{Rule: RuleShortCapitalization, Path: "app db migrate", Message: "Short description should start with a capital letter"},
+		{Rule: RuleFlagKebabCase, Path: "app db migrate --to_version", Message: "Flag name should be kebab-case: --to-version"},
+	}
+	if got := Lint(c); !reflect.DeepEqual(got, want) {
+		t.Errorf("Lint() = %+v, want %+v", got, want)
+	}
+}
+
+func TestKebabCase(t *testing.T) {
+	tests := map[string]string{
+		"dry-run":      "dry-run",
+		"dryRun":       "dry-run",
+		"dry_run":      "dry-run",
+		"listenAddr":   "listen-addr",
+		"useHTTPS":     "use-https",
+		"HTTPProxy":    "http-proxy",
+		"v2Api":        "v2-api",
+		"listPetsByID": "list-pets-by-id",
+		"Pet Store":    "pet-store",
+		"get /pets/id": "get-pets-id",
+		"--":           "",
+	}
+	for input, want := range tests {
+		if got := KebabCase(input); got != want {
+			t.Errorf("KebabCase(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package output
+
+import (
+	"fmt"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/contract"
+	"github.com/hiAndrewQuinn/cliguard/internal/validator"
+)
+
+// maxAnnotatedValue is the length, in characters, past which the values in
+// an annotation are shortened, so long descriptions fit on one line
+const maxAnnotatedValue = 60
+
+// AnnotateContract returns the contract document with a comment above the
+// line of each validation error, such as
+//
+//	# ❌ MISSING in implementation: --verbose flag
+//
+// Errors for commands and flags the contract doesn't list are written above
+// their closest parent command. See contract.Annotate.
+func AnnotateContract(data []byte, rootName string, result *validator.ValidationResult) ([]byte, error) {
+	annotations := make([]contract.Annotation, 0, len(result.Errors))
+	for _, err := range result.Errors {
+		annotations = append(annotations, contract.Annotation{Path: err.Path, Text: annotationText(err)})
+	}
+	return contract.Annotate(data, rootName, annotations)
+}
+
+// annotationText describes a validation error for a contract comment
+func annotationText(err validator.ValidationError) string {
+	switch err.Type {
+	case validator.ErrorTypeMissing:
+		return fmt.Sprintf("MISSING in implementation: %s %s", err.Path, err.Message)
+	case validator.ErrorTypeUnexpected:
+		return fmt.Sprintf("UNEXPECTED in implementation: %s %s", err.Path, err.Message)
+	default:
+		return fmt.Sprintf("MISMATCH at %s: %s (contract: %q, implementation: %q)",
+			err.Path, err.Message, shorten(err.Expected), shorten(err.Actual))
+	}
+}
+
+// shorten returns s cut to maxAnnotatedValue characters, marking the cut
+func shorten(s string) string {
+	runes := []rune(s)
+	if len(runes) <= maxAnnotatedValue {
+		return s
+	}
+	return string(runes[:maxAnnotatedValue]) + "..."
+}
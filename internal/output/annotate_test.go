@@ -0,0 +1,32 @@
+package output
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/validator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnnotateContract(t *testing.T) {
+	result := &validator.ValidationResult{}
+	result.AddError(validator.ErrorTypeMissing, "serve --port", "port", "", "flag")
+	result.AddError(validator.ErrorTypeUnexpected, "serve --host", "", "host", "flag")
+	result.AddError(validator.ErrorTypeMismatch, "serve", strings.Repeat("a", 70), "Run", "Mismatch in long description")
+
+	data := "use: app\nshort: App\ncommands:\n  - use: serve\n    flags:\n      - name: port\n        type: int\n"
+	got, err := AnnotateContract([]byte(data), "", result)
+	require.NoError(t, err)
+	assert.Equal(t, `use: app
+short: App
+commands:
+  # ❌ UNEXPECTED in implementation: serve --host flag
+  # ❌ MISMATCH at serve: Mismatch in long description (contract: "`+strings.Repeat("a", 60)+`...", implementation: "Run")
+  - use: serve
+    flags:
+      # ❌ MISSING in implementation: serve --port flag
+      - name: port
+        type: int
+`, string(got))
+}
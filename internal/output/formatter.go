@@ -0,0 +1,90 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats of the --output flags. Text is written by each command
+// itself; the other formats have an OutputFormatter.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+)
+
+// OutputFormatter serializes a value, such as a validation result or a
+// contract, in one machine-readable format
+type OutputFormatter interface {
+	Format(v interface{}) ([]byte, error)
+}
+
+// JSONFormatter writes indented JSON, ending with a newline. Characters
+// such as < and > are written as they are rather than escaped for HTML.
+type JSONFormatter struct{}
+
+// Format implements OutputFormatter
+func (JSONFormatter) Format(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// YAMLFormatter writes YAML as gopkg.in/yaml.v3 does
+type YAMLFormatter struct{}
+
+// Format implements OutputFormatter
+func (YAMLFormatter) Format(v interface{}) ([]byte, error) {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal YAML: %w", err)
+	}
+	return data, nil
+}
+
+// formatters are the formatters NewFormatter returns, keyed by format
+var formatters = map[string]OutputFormatter{
+	FormatJSON: JSONFormatter{},
+	FormatYAML: YAMLFormatter{},
+}
+
+// RegisterFormatter makes NewFormatter return formatter for format, adding
+// the format to the commands that use NewFormatter or replacing the
+// formatter it had. It is meant to be called from init functions.
+func RegisterFormatter(format string, formatter OutputFormatter) {
+	formatters[format] = formatter
+}
+
+// NewFormatter returns the formatter for format. FormatText has none, as
+// each command writes its own text.
+func NewFormatter(format string) (OutputFormatter, error) {
+	if format == FormatText {
+		return nil, fmt.Errorf("the %s output format has no formatter", FormatText)
+	}
+	formatter, ok := formatters[format]
+	if !ok {
+		return nil, fmt.Errorf("unsupported output format '%s' (supported: %s)", format, strings.Join(Formats(), ", "))
+	}
+	return formatter, nil
+}
+
+// Formats returns the supported output formats: FormatText, then the
+// formats with a formatter in alphabetical order
+func Formats() []string {
+	names := make([]string, 0, len(formatters))
+	for name := range formatters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return append([]string{FormatText}, names...)
+}
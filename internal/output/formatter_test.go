@@ -0,0 +1,72 @@
+package output
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewFormatter(t *testing.T) {
+	value := struct {
+		Name  string   `json:"name" yaml:"name"`
+		Usage string   `json:"usage" yaml:"usage"`
+		Enum  []string `json:"enum" yaml:"enum"`
+	}{Name: "format", Usage: "Output <format>", Enum: []string{"json", "text"}}
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{FormatJSON, "{\n  \"name\": \"format\",\n  \"usage\": \"Output <format>\",\n  \"enum\": [\n    \"json\",\n    \"text\"\n  ]\n}\n"},
+		{FormatYAML, "name: format\nusage: Output <format>\nenum:\n    - json\n    - text\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			formatter, err := NewFormatter(tt.format)
+			if err != nil {
+				t.Fatalf("NewFormatter() error = %v", err)
+			}
+			data, err := formatter.Format(value)
+			if err != nil {
+				t.Fatalf("Format() error = %v", err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("Format() =\n%s\nwant:\n%s", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewFormatter_Unsupported(t *testing.T) {
+	for _, format := range []string{FormatText, "toml"} {
+		if _, err := NewFormatter(format); err == nil {
+			t.Errorf("NewFormatter(%q) error = nil, want an error", format)
+		}
+	}
+	_, err := NewFormatter("toml")
+	if err == nil || !strings.Contains(err.Error(), "(supported: text, json, yaml)") {
+		t.Errorf("NewFormatter() error = %v, want the supported formats listed", err)
+	}
+}
+
+// upperFormatter formats strings in upper case
+type upperFormatter struct{}
+
+func (upperFormatter) Format(v interface{}) ([]byte, error) {
+	return []byte(strings.ToUpper(v.(string))), nil
+}
+
+func TestRegisterFormatter(t *testing.T) {
+	RegisterFormatter("upper", upperFormatter{})
+	defer delete(formatters, "upper")
+
+	formatter, err := NewFormatter("upper")
+	if err != nil {
+		t.Fatalf("NewFormatter() error = %v", err)
+	}
+	if data, _ := formatter.Format("cliguard"); string(data) != "CLIGUARD" {
+		t.Errorf("Format() = %q", data)
+	}
+	if got := strings.Join(Formats(), ", "); got != "text, json, upper, yaml" {
+		t.Errorf("Formats() = %s", got)
+	}
+}
@@ -0,0 +1,92 @@
+package output
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/validator"
+)
+
+// JUnitTestSuites is a JUnit XML report, with the subset of the format CI
+// systems such as Jenkins, CircleCI and TeamCity read
+type JUnitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []JUnitTestSuite `xml:"testsuite"`
+}
+
+// JUnitTestSuite groups the test cases of one contract
+type JUnitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []JUnitTestCase `xml:"testcase"`
+}
+
+// JUnitTestCase is a single validation error
+type JUnitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *JUnitFailure `xml:"failure,omitempty"`
+}
+
+// JUnitFailure describes how a test case failed
+type JUnitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// NewJUnitReport converts a validation result to a JUnit report with one
+// test suite named suiteName, usually the use field of the contract. Each
+// error is a failed test case named by its path, whose class name is its
+// type.
+func NewJUnitReport(result *validator.ValidationResult, suiteName string) JUnitTestSuites {
+	suite := JUnitTestSuite{Name: suiteName, TestCases: []JUnitTestCase{}}
+	for _, err := range result.Errors {
+		suite.TestCases = append(suite.TestCases, JUnitTestCase{
+			Name:      err.Path,
+			ClassName: string(err.Type),
+			Failure: &JUnitFailure{
+				Message: describeError(err),
+				Type:    string(err.Type),
+				Text:    failureText(err),
+			},
+		})
+	}
+	suite.Tests = len(suite.TestCases)
+	suite.Failures = len(suite.TestCases)
+
+	return JUnitTestSuites{
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Suites:   []JUnitTestSuite{suite},
+	}
+}
+
+// FormatJUnit renders the result as an indented JUnit XML document
+func FormatJUnit(result *validator.ValidationResult, suiteName string) ([]byte, error) {
+	data, err := xml.MarshalIndent(NewJUnitReport(result, suiteName), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JUnit report: %w", err)
+	}
+	return append([]byte(xml.Header), append(data, '\n')...), nil
+}
+
+// failureText shows the contract's and the CLI's values of a failed test
+// case, and the suggested fix if there is one
+func failureText(err validator.ValidationError) string {
+	var lines []string
+	if err.Expected != "" {
+		lines = append(lines, "Contract: "+err.Expected)
+	}
+	if err.Actual != "" {
+		lines = append(lines, "Actual:   "+err.Actual)
+	}
+	if err.Suggestion != "" {
+		lines = append(lines, "Suggested fix: "+err.Suggestion)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,57 @@
+package output
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/validator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatJUnit(t *testing.T) {
+	result := &validator.ValidationResult{}
+	result.AddErrorWithSuggestion(validator.ErrorTypeMissing, "serve --port", "port", "", "flag", `cmd.Flags().IntVar(&port, "port", 0, "")`)
+	result.AddError(validator.ErrorTypeMismatch, "serve", "Start <server>", "Run", "Mismatch in short description")
+
+	data, err := FormatJUnit(result, "myapp")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), xml.Header), "the report starts with the XML declaration")
+
+	var report JUnitTestSuites
+	require.NoError(t, xml.Unmarshal(data, &report))
+	assert.Equal(t, 2, report.Tests)
+	assert.Equal(t, 2, report.Failures)
+	require.Len(t, report.Suites, 1)
+
+	suite := report.Suites[0]
+	assert.Equal(t, "myapp", suite.Name)
+	assert.Equal(t, 2, suite.Tests)
+	assert.Equal(t, 2, suite.Failures)
+	require.Len(t, suite.TestCases, 2)
+
+	assert.Equal(t, JUnitTestCase{
+		Name:      "serve --port",
+		ClassName: "missing",
+		Failure: &JUnitFailure{
+			Message: "Missing flag: serve --port",
+			Type:    "missing",
+			Text:    "Contract: port\nSuggested fix: cmd.Flags().IntVar(&port, \"port\", 0, \"\")",
+		},
+	}, suite.TestCases[0])
+
+	mismatch := suite.TestCases[1]
+	assert.Equal(t, "serve", mismatch.Name)
+	assert.Equal(t, "mismatch", mismatch.ClassName)
+	assert.Equal(t, `Mismatch in short description at serve (expected "Start <server>", got "Run")`, mismatch.Failure.Message)
+	assert.Equal(t, "Contract: Start <server>\nActual:   Run", mismatch.Failure.Text)
+	assert.Contains(t, string(data), "Start &lt;server&gt;", "values are escaped")
+}
+
+func TestFormatJUnit_NoErrors(t *testing.T) {
+	data, err := FormatJUnit(&validator.ValidationResult{Valid: true}, "myapp")
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `<testsuites tests="0" failures="0">`)
+	assert.Contains(t, string(data), `<testsuite name="myapp" tests="0" failures="0"></testsuite>`)
+}
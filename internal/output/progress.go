@@ -0,0 +1,138 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Messages shown by ProgressWriter
+const (
+	progressMessage = "🔨 Building project..."
+	progressFailed  = "❌ Build failed"
+)
+
+// spinnerFrames are drawn after the progress message in turn
+var spinnerFrames = []rune{'⠋', '⠙', '⠹', '⠸', '⠼', '⠴', '⠦', '⠧', '⠇', '⠏'}
+
+// Progress reports that the target project is being built and inspected,
+// which can take a while for large projects
+type Progress interface {
+	// Start shows the progress indicator
+	Start()
+
+	// Stop replaces the indicator with the outcome of the build; err is the
+	// build's error, if any
+	Stop(err error)
+}
+
+// NopProgress is a Progress that shows nothing
+type NopProgress struct{}
+
+// Start does nothing
+func (NopProgress) Start() {}
+
+// Stop does nothing
+func (NopProgress) Stop(error) {}
+
+// NewProgress returns a ProgressWriter for w if it is a terminal, and a
+// NopProgress otherwise, so spinner frames don't end up in logs
+func NewProgress(w io.Writer) Progress {
+	if !IsTerminal(w) {
+		return NopProgress{}
+	}
+	return NewProgressWriter(w)
+}
+
+// IsTerminal reports whether w is a terminal
+func IsTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// ProgressWriter draws a spinner on a single line of w, using "\r" to
+// redraw it, until it is stopped. A ProgressWriter can be started again
+// after it is stopped.
+type ProgressWriter struct {
+	w        io.Writer
+	interval time.Duration
+
+	started time.Time
+	stop    chan struct{}
+	done    chan struct{}
+
+	// width is the length of the last line drawn, so that shorter lines can
+	// blank out what is left of it
+	width int
+}
+
+// NewProgressWriter creates a ProgressWriter that draws on w
+func NewProgressWriter(w io.Writer) *ProgressWriter {
+	return &ProgressWriter{w: w, interval: 100 * time.Millisecond}
+}
+
+// Start draws the spinner in a new goroutine. It does nothing if the
+// spinner is already running.
+func (p *ProgressWriter) Start() {
+	if p.stop != nil {
+		return
+	}
+	p.started = time.Now()
+	p.stop = make(chan struct{})
+	p.done = make(chan struct{})
+	go p.spin(p.stop, p.done)
+}
+
+// Stop stops the spinner and replaces it with "✅ Done" and the time since
+// Start, or "❌ Build failed" if err is not nil. It does nothing if the
+// spinner is not running.
+func (p *ProgressWriter) Stop(err error) {
+	if p.stop == nil {
+		return
+	}
+	close(p.stop)
+	<-p.done
+	p.stop = nil
+
+	if err != nil {
+		p.draw(progressFailed)
+	} else {
+		p.draw(fmt.Sprintf("✅ Done (%.1fs)", time.Since(p.started).Seconds()))
+	}
+	fmt.Fprintln(p.w)
+	p.width = 0
+}
+
+// spin redraws the spinner every interval until stop is closed
+func (p *ProgressWriter) spin(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for frame := 0; ; frame++ {
+		p.draw(fmt.Sprintf("%s %c", progressMessage, spinnerFrames[frame%len(spinnerFrames)]))
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// draw overwrites the current line with line
+func (p *ProgressWriter) draw(line string) {
+	width := utf8.RuneCountInString(line)
+	padding := ""
+	if width < p.width {
+		padding = strings.Repeat(" ", p.width-width)
+	}
+	p.width = width
+	fmt.Fprintf(p.w, "\r%s%s", line, padding)
+}
@@ -0,0 +1,72 @@
+package output
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgressWriter(t *testing.T) {
+	t.Run("done", func(t *testing.T) {
+		var buf bytes.Buffer
+		progress := NewProgressWriter(&buf)
+		progress.interval = time.Millisecond
+
+		progress.Start()
+		time.Sleep(20 * time.Millisecond)
+		progress.Stop(nil)
+
+		out := buf.String()
+		assert.True(t, strings.HasPrefix(out, "\r🔨 Building project... ⠋"), "output %q", out)
+		assert.Contains(t, out, "\r🔨 Building project... ⠙")
+		lastLine := out[strings.LastIndex(out, "\r"):]
+		assert.Regexp(t, `^\r✅ Done \(\d+\.\ds\) *\n$`, lastLine)
+		assert.NotContains(t, out, "\n\r", "spinner drawn after Stop")
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		var buf bytes.Buffer
+		progress := NewProgressWriter(&buf)
+		progress.Start()
+		progress.Stop(errors.New("build failed"))
+
+		out := buf.String()
+		lastLine := out[strings.LastIndex(out, "\r"):]
+		// Padded to blank out the rest of the spinner line
+		assert.Equal(t, "\r❌ Build failed         \n", lastLine)
+	})
+
+	t.Run("stop without start", func(t *testing.T) {
+		var buf bytes.Buffer
+		NewProgressWriter(&buf).Stop(nil)
+		assert.Empty(t, buf.String())
+	})
+
+	t.Run("restart", func(t *testing.T) {
+		var buf bytes.Buffer
+		progress := NewProgressWriter(&buf)
+		progress.Start()
+		progress.Start()
+		progress.Stop(nil)
+		progress.Start()
+		progress.Stop(nil)
+		assert.Equal(t, 2, strings.Count(buf.String(), "✅ Done"))
+	})
+}
+
+func TestNewProgress(t *testing.T) {
+	assert.Equal(t, NopProgress{}, NewProgress(&bytes.Buffer{}))
+
+	f, err := os.Create(filepath.Join(t.TempDir(), "progress.log"))
+	require.NoError(t, err)
+	defer f.Close()
+	assert.False(t, IsTerminal(f))
+	assert.Equal(t, NopProgress{}, NewProgress(f))
+}
@@ -0,0 +1,220 @@
+// Package output serializes validation results for other tools, such as
+// SARIF files for GitHub code scanning and JUnit XML for CI systems,
+// annotates contract files with them, and shows the progress of long builds
+// on the terminal.
+//
+// Example:
+//
+//	lines, _ := contract.LoadLineIndex("cliguard.yaml", "")
+//	data, err := output.FormatSARIF(result, "cliguard.yaml", lines)
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/contract"
+	"github.com/hiAndrewQuinn/cliguard/internal/validator"
+	"github.com/hiAndrewQuinn/cliguard/internal/version"
+)
+
+// SARIF format constants
+const (
+	SARIFVersion   = "2.1.0"
+	SARIFSchemaURI = "https://json.schemastore.org/sarif-2.1.0.json"
+
+	// toolInformationURI is reported as the tool's home page
+	toolInformationURI = "https://github.com/hiAndrewQuinn/cliguard"
+)
+
+// SARIF result levels
+const (
+	LevelError   = "error"
+	LevelWarning = "warning"
+)
+
+// SARIFLog is a SARIF 2.1.0 log file, with the subset of the format cliguard
+// writes
+type SARIFLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []SARIFRun `json:"runs"`
+}
+
+// SARIFRun is a single run of the tool
+type SARIFRun struct {
+	Tool    SARIFTool     `json:"tool"`
+	Results []SARIFResult `json:"results"`
+}
+
+// SARIFTool describes cliguard and the rules its results refer to
+type SARIFTool struct {
+	Driver SARIFDriver `json:"driver"`
+}
+
+// SARIFDriver is the tool component that produced the results
+type SARIFDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []SARIFRule `json:"rules"`
+}
+
+// SARIFRule is one kind of result. Each validator.ErrorType is a rule.
+type SARIFRule struct {
+	ID               string       `json:"id"`
+	ShortDescription SARIFMessage `json:"shortDescription"`
+}
+
+// SARIFResult is a single validation error
+type SARIFResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   SARIFMessage    `json:"message"`
+	Locations []SARIFLocation `json:"locations"`
+}
+
+// SARIFMessage is a plain text message
+type SARIFMessage struct {
+	Text string `json:"text"`
+}
+
+// SARIFLocation points at the part of the contract a result is about
+type SARIFLocation struct {
+	PhysicalLocation SARIFPhysicalLocation `json:"physicalLocation"`
+}
+
+// SARIFPhysicalLocation is a region of a file
+type SARIFPhysicalLocation struct {
+	ArtifactLocation SARIFArtifactLocation `json:"artifactLocation"`
+	Region           *SARIFRegion          `json:"region,omitempty"`
+}
+
+// SARIFArtifactLocation identifies a file
+type SARIFArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// SARIFRegion is the line a result starts on
+type SARIFRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// ruleDescriptions describe each error type for the rules table
+var ruleDescriptions = map[validator.ErrorType]string{
+	validator.ErrorTypeMissing:     "A command or flag in the contract is missing from the CLI",
+	validator.ErrorTypeUnexpected:  "The CLI has a command or flag that is not in the contract",
+	validator.ErrorTypeMismatch:    "A command or flag differs from the contract",
+	validator.ErrorTypeInvalidType: "A flag's type differs from the contract",
+}
+
+// NewSARIFLog converts a validation result to a SARIF log. contractURI is
+// the contract file as it should appear in the results, usually relative to
+// the repository root (see ArtifactURI). lines locates each error in the
+// contract; errors are reported without a line if it is nil.
+//
+// Unexpected commands and flags are warnings, since the CLI only gained
+// something; all other errors break the contract and are errors.
+func NewSARIFLog(result *validator.ValidationResult, contractURI string, lines contract.LineIndex) SARIFLog {
+	run := SARIFRun{
+		Tool: SARIFTool{Driver: SARIFDriver{
+			Name:           "cliguard",
+			Version:        version.Version,
+			InformationURI: toolInformationURI,
+			Rules:          []SARIFRule{},
+		}},
+		Results: []SARIFResult{},
+	}
+
+	seen := make(map[validator.ErrorType]bool)
+	for _, err := range result.Errors {
+		if !seen[err.Type] {
+			seen[err.Type] = true
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, SARIFRule{
+				ID:               string(err.Type),
+				ShortDescription: SARIFMessage{Text: ruleDescriptions[err.Type]},
+			})
+		}
+
+		location := SARIFPhysicalLocation{ArtifactLocation: SARIFArtifactLocation{URI: contractURI}}
+		if line := lines.Line(err.Path); line > 0 {
+			location.Region = &SARIFRegion{StartLine: line}
+		}
+		run.Results = append(run.Results, SARIFResult{
+			RuleID:    string(err.Type),
+			Level:     level(err.Type),
+			Message:   SARIFMessage{Text: resultMessage(err)},
+			Locations: []SARIFLocation{{PhysicalLocation: location}},
+		})
+	}
+
+	return SARIFLog{
+		Schema:  SARIFSchemaURI,
+		Version: SARIFVersion,
+		Runs:    []SARIFRun{run},
+	}
+}
+
+// FormatSARIF renders the result as an indented SARIF JSON document
+func FormatSARIF(result *validator.ValidationResult, contractURI string, lines contract.LineIndex) ([]byte, error) {
+	data, err := json.MarshalIndent(NewSARIFLog(result, contractURI, lines), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal SARIF report: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// ArtifactURI returns the URI of path for a SARIF artifact location: the
+// path relative to baseDir with forward slashes if path is inside baseDir,
+// otherwise an absolute file:// URI. URLs, such as a contract downloaded
+// over HTTP, are returned unchanged. GitHub code scanning expects paths
+// relative to the repository root.
+func ArtifactURI(path, baseDir string) string {
+	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		return path
+	}
+	if rel, err := filepath.Rel(baseDir, path); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return filepath.ToSlash(rel)
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	return "file://" + filepath.ToSlash(path)
+}
+
+// level returns the SARIF level of an error type
+func level(errorType validator.ErrorType) string {
+	if errorType == validator.ErrorTypeUnexpected {
+		return LevelWarning
+	}
+	return LevelError
+}
+
+// resultMessage describes a validation error, followed by its suggested fix
+// if there is one
+func resultMessage(err validator.ValidationError) string {
+	text := describeError(err)
+	if err.Suggestion != "" {
+		text += "\nSuggested fix: " + err.Suggestion
+	}
+	return text
+}
+
+// describeError describes a validation error on one line
+func describeError(err validator.ValidationError) string {
+	var text string
+	switch err.Type {
+	case validator.ErrorTypeMissing:
+		text = fmt.Sprintf("Missing %s: %s", err.Message, err.Path)
+	case validator.ErrorTypeUnexpected:
+		text = fmt.Sprintf("Unexpected %s: %s", err.Message, err.Path)
+	default:
+		text = fmt.Sprintf("%s at %s", err.Message, err.Path)
+		if err.Expected != "" || err.Actual != "" {
+			text += fmt.Sprintf(" (expected %q, got %q)", err.Expected, err.Actual)
+		}
+	}
+	return text
+}
@@ -0,0 +1,135 @@
+package output
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/contract"
+	"github.com/hiAndrewQuinn/cliguard/internal/validator"
+	"github.com/hiAndrewQuinn/cliguard/internal/version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatSARIF(t *testing.T) {
+	result := &validator.ValidationResult{}
+	result.AddErrorWithSuggestion(validator.ErrorTypeMissing, "serve --port", "port", "", "flag", `cmd.Flags().IntVar(&port, "port", 0, "")`)
+	result.AddError(validator.ErrorTypeUnexpected, "extra", "", "extra", "command")
+	result.AddError(validator.ErrorTypeMismatch, "serve", "Start", "Run", "Mismatch in short description")
+	lines := contract.LineIndex{"root": 1, "serve": 5, "serve --port": 8}
+
+	data, err := FormatSARIF(result, "cliguard.yaml", lines)
+	require.NoError(t, err)
+
+	var log SARIFLog
+	require.NoError(t, json.Unmarshal(data, &log))
+	assert.Equal(t, SARIFVersion, log.Version)
+	assert.Equal(t, SARIFSchemaURI, log.Schema)
+	require.Len(t, log.Runs, 1)
+
+	driver := log.Runs[0].Tool.Driver
+	assert.Equal(t, "cliguard", driver.Name)
+	assert.Equal(t, version.Version, driver.Version)
+	var ruleIDs []string
+	for _, rule := range driver.Rules {
+		ruleIDs = append(ruleIDs, rule.ID)
+		assert.NotEmpty(t, rule.ShortDescription.Text)
+	}
+	assert.Equal(t, []string{"missing", "unexpected", "mismatch"}, ruleIDs)
+
+	results := log.Runs[0].Results
+	require.Len(t, results, 3)
+	assert.Equal(t, SARIFResult{
+		RuleID:  "missing",
+		Level:   LevelError,
+		Message: SARIFMessage{Text: "Missing flag: serve --port\nSuggested fix: cmd.Flags().IntVar(&port, \"port\", 0, \"\")"},
+		Locations: []SARIFLocation{{PhysicalLocation: SARIFPhysicalLocation{
+			ArtifactLocation: SARIFArtifactLocation{URI: "cliguard.yaml"},
+			Region:           &SARIFRegion{StartLine: 8},
+		}}},
+	}, results[0])
+
+	assert.Equal(t, LevelWarning, results[1].Level)
+	assert.Equal(t, "Unexpected command: extra", results[1].Message.Text)
+	assert.Equal(t, 1, results[1].Locations[0].PhysicalLocation.Region.StartLine, "unexpected root commands point at the root")
+
+	assert.Equal(t, LevelError, results[2].Level)
+	assert.Equal(t, `Mismatch in short description at serve (expected "Start", got "Run")`, results[2].Message.Text)
+	assert.Equal(t, 5, results[2].Locations[0].PhysicalLocation.Region.StartLine)
+}
+
+func TestFormatSARIF_NoErrors(t *testing.T) {
+	data, err := FormatSARIF(&validator.ValidationResult{Valid: true}, "cliguard.yaml", nil)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"results": []`)
+	assert.Contains(t, string(data), `"rules": []`)
+}
+
+// TestFormatSARIF_Schema checks the log against the constraints of the
+// SARIF 2.1.0 schema on the properties cliguard writes: the required ones,
+// the enumerated result levels and the minimum start line
+func TestFormatSARIF_Schema(t *testing.T) {
+	result := &validator.ValidationResult{}
+	result.AddError(validator.ErrorTypeMissing, "serve", "serve", "", "command")
+	result.AddError(validator.ErrorTypeUnexpected, "extra", "", "extra", "command")
+	result.AddError(validator.ErrorTypeInvalidType, "--port", "int", "string", "Flag type mismatch")
+
+	data, err := FormatSARIF(result, "cliguard.yaml", contract.LineIndex{"root": 1, "serve": 4, "--port": 9})
+	require.NoError(t, err)
+
+	var log map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &log))
+	assert.Equal(t, SARIFSchemaURI, log["$schema"])
+	assert.Equal(t, "2.1.0", log["version"])
+
+	runs, ok := log["runs"].([]interface{})
+	require.True(t, ok, "runs must be an array")
+	require.Len(t, runs, 1)
+	run := runs[0].(map[string]interface{})
+	driver := run["tool"].(map[string]interface{})["driver"].(map[string]interface{})
+	assert.NotEmpty(t, driver["name"], "tool.driver.name is required")
+
+	ruleIDs := map[string]bool{}
+	for _, r := range driver["rules"].([]interface{}) {
+		rule := r.(map[string]interface{})
+		require.NotEmpty(t, rule["id"], "rules[].id is required")
+		ruleIDs[rule["id"].(string)] = true
+	}
+
+	results := run["results"].([]interface{})
+	require.Len(t, results, 3)
+	for _, r := range results {
+		res := r.(map[string]interface{})
+		message, ok := res["message"].(map[string]interface{})
+		require.True(t, ok, "results[].message is required")
+		assert.NotEmpty(t, message["text"])
+		assert.True(t, ruleIDs[res["ruleId"].(string)], "ruleId %v must be a rule of the driver", res["ruleId"])
+		assert.Contains(t, []string{"none", "note", "warning", "error"}, res["level"])
+
+		for _, l := range res["locations"].([]interface{}) {
+			physical := l.(map[string]interface{})["physicalLocation"].(map[string]interface{})
+			assert.NotEmpty(t, physical["artifactLocation"].(map[string]interface{})["uri"])
+			if region, ok := physical["region"].(map[string]interface{}); ok {
+				assert.GreaterOrEqual(t, region["startLine"], float64(1))
+			}
+		}
+	}
+}
+
+func TestNewSARIFLog_WithoutLines(t *testing.T) {
+	result := &validator.ValidationResult{}
+	result.AddError(validator.ErrorTypeMismatch, "root", "a", "b", "Mismatch in 'use' field")
+
+	log := NewSARIFLog(result, "cliguard.yaml", nil)
+	assert.Nil(t, log.Runs[0].Results[0].Locations[0].PhysicalLocation.Region)
+}
+
+func TestArtifactURI(t *testing.T) {
+	base := t.TempDir()
+	assert.Equal(t, "cliguard.yaml", ArtifactURI(filepath.Join(base, "cliguard.yaml"), base))
+	assert.Equal(t, "cli/cliguard.yaml", ArtifactURI(filepath.Join(base, "cli", "cliguard.yaml"), base))
+
+	outside := filepath.Join(filepath.Dir(base), "other", "cliguard.yaml")
+	assert.Equal(t, "file://"+filepath.ToSlash(outside), ArtifactURI(outside, base))
+}
@@ -0,0 +1,499 @@
+// Package repl implements cliguard's interactive shell. Each line is run as a
+// cliguard command in the same process, and flags such as --project-path are
+// remembered for later commands in the session.
+//
+// Example:
+//
+//	r := repl.New(repl.Config{
+//	    In:          os.Stdin,
+//	    Out:         os.Stdout,
+//	    NewRoot:     cmd.NewRootCmd,
+//	    HistoryPath: repl.DefaultHistoryPath(),
+//	})
+//	if err := r.Run(); err != nil {
+//	    log.Fatal(err)
+//	}
+package repl
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	cliguarderrors "github.com/hiAndrewQuinn/cliguard/internal/errors"
+	"github.com/hiAndrewQuinn/cliguard/internal/output"
+	"github.com/peterh/liner"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// ContextFlags are the flags whose values are remembered for the rest of the
+// session once given to any command or set with 'set'
+var ContextFlags = []string{"project-path", "entrypoint", "contract"}
+
+// builtins are the commands handled by the REPL itself
+var builtins = []string{"context", "exit", "help", "history", "quit", "set", "unset"}
+
+// maxHistory is the number of history entries loaded from the history file
+const maxHistory = 1000
+
+// Config configures a REPL session
+type Config struct {
+	// In is read line by line for commands. When In and Out are the
+	// process's terminal, lines are read with liner's line editing: Tab
+	// completes commands and flags, and the arrow keys browse the history.
+	In io.Reader
+
+	// Out receives the prompt and all command output
+	Out io.Writer
+
+	// NewRoot creates the command tree each line is run against. A fresh tree
+	// is created for every command so that flag values do not leak between
+	// commands.
+	NewRoot func() *cobra.Command
+
+	// HistoryPath is the file history is loaded from and appended to.
+	// If empty, history is kept for the session only.
+	HistoryPath string
+}
+
+// REPL is an interactive cliguard session
+type REPL struct {
+	config Config
+
+	// in is shared with the commands run, so that commands prompting for
+	// input (such as discover --interactive) read the lines that follow
+	in *bufio.Reader
+
+	// terminal is set when lines are read with line editing
+	terminal bool
+
+	context map[string]string
+	history []string
+}
+
+// New creates a REPL session
+func New(config Config) *REPL {
+	return &REPL{
+		config:  config,
+		in:      bufio.NewReader(config.In),
+		context: make(map[string]string),
+		// liner only reads and writes the process's terminal
+		terminal: config.In == os.Stdin && config.Out == os.Stdout && output.IsTerminal(os.Stdin) && output.IsTerminal(os.Stdout),
+	}
+}
+
+// DefaultHistoryPath returns the history file location,
+// ~/.config/cliguard/history on Linux
+func DefaultHistoryPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "cliguard", "history")
+}
+
+// Run reads and executes commands until 'exit' or the end of input
+func (r *REPL) Run() error {
+	r.loadHistory()
+
+	fmt.Fprintln(r.config.Out, "cliguard interactive mode. Type 'help' for commands, 'exit' to quit.")
+	if r.terminal {
+		fmt.Fprintln(r.config.Out, "Press Tab to complete commands and flags.")
+	}
+
+	for {
+		line, err := r.readLine(r.Prompt())
+		if errors.Is(err, liner.ErrPromptAborted) {
+			// Ctrl-C discards the line
+			continue
+		}
+		if err != nil {
+			fmt.Fprintln(r.config.Out)
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+
+		if done := r.Execute(line); done {
+			return nil
+		}
+	}
+}
+
+// readLine prints the prompt and reads the next line, with line editing on
+// a terminal
+func (r *REPL) readLine(prompt string) (string, error) {
+	if !r.terminal {
+		fmt.Fprint(r.config.Out, prompt)
+		line, err := r.in.ReadString('\n')
+		if err != nil && line == "" {
+			return "", err
+		}
+		return strings.TrimRight(line, "\r\n"), nil
+	}
+
+	// liner keeps the terminal in raw mode until it is closed, so a new one
+	// is used for each line, and commands prompting for input (such as
+	// discover --interactive) read from the terminal in its normal mode
+	state := liner.NewLiner()
+	defer state.Close()
+	state.SetCtrlCAborts(true)
+	state.SetWordCompleter(r.completeWord)
+	for _, entry := range r.history {
+		state.AppendHistory(entry)
+	}
+	return state.Prompt(prompt)
+}
+
+// Prompt returns the prompt, which shows the session's project path
+func (r *REPL) Prompt() string {
+	if path := r.context["project-path"]; path != "" {
+		return fmt.Sprintf("cliguard (%s)> ", path)
+	}
+	return "cliguard> "
+}
+
+// Context returns the flag values remembered for the session
+func (r *REPL) Context() map[string]string {
+	return r.context
+}
+
+// Execute runs a single line and reports whether the session should end
+func (r *REPL) Execute(line string) bool {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return false
+	}
+
+	// "!N" re-runs history entry N
+	if strings.HasPrefix(line, "!") {
+		n, err := strconv.Atoi(line[1:])
+		if err != nil || n < 1 || n > len(r.history) {
+			fmt.Fprintf(r.config.Out, "Error: no history entry '%s'\n", line[1:])
+			return false
+		}
+		line = r.history[n-1]
+		fmt.Fprintln(r.config.Out, line)
+	}
+	r.addHistory(line)
+
+	args, err := SplitArgs(line)
+	if err != nil {
+		fmt.Fprintf(r.config.Out, "Error: %v\n", err)
+		return false
+	}
+	if len(args) == 0 {
+		return false
+	}
+
+	switch args[0] {
+	case "exit", "quit":
+		return true
+	case "help":
+		if len(args) == 1 {
+			r.printHelp()
+			return false
+		}
+	case "history":
+		for i, entry := range r.history {
+			fmt.Fprintf(r.config.Out, "%4d  %s\n", i+1, entry)
+		}
+		return false
+	case "context":
+		r.printContext()
+		return false
+	case "set":
+		if len(args) != 3 || !isContextFlag(args[1]) {
+			fmt.Fprintf(r.config.Out, "Usage: set <%s> <value>\n", strings.Join(ContextFlags, "|"))
+			return false
+		}
+		r.context[args[1]] = args[2]
+		return false
+	case "unset":
+		if len(args) != 2 || !isContextFlag(args[1]) {
+			fmt.Fprintf(r.config.Out, "Usage: unset <%s>\n", strings.Join(ContextFlags, "|"))
+			return false
+		}
+		delete(r.context, args[1])
+		return false
+	}
+
+	r.runCommand(args)
+	return false
+}
+
+// runCommand runs a cliguard command with the session context applied
+func (r *REPL) runCommand(args []string) {
+	r.rememberContext(args)
+
+	root := r.config.NewRoot()
+	args = r.applyContext(root, args)
+
+	root.SetArgs(args)
+	root.SetIn(r.in)
+	root.SetOut(r.config.Out)
+	root.SetErr(r.config.Out)
+	root.SilenceUsage = true
+	root.SilenceErrors = true
+
+	// The validation report has already been printed
+	err := root.Execute()
+	if err != nil && !errors.Is(err, cliguarderrors.ErrValidationFailed) && !errors.Is(err, cliguarderrors.ErrBreakingChanges) {
+		fmt.Fprintf(r.config.Out, "Error: %v\n", err)
+	}
+}
+
+// rememberContext stores context flag values given on the command line
+func (r *REPL) rememberContext(args []string) {
+	for i, arg := range args {
+		name, value, hasValue := strings.Cut(strings.TrimPrefix(arg, "--"), "=")
+		if !strings.HasPrefix(arg, "--") || !isContextFlag(name) {
+			continue
+		}
+		if !hasValue {
+			if i+1 >= len(args) {
+				continue
+			}
+			value = args[i+1]
+		}
+		r.context[name] = value
+	}
+}
+
+// applyContext adds remembered flag values the command accepts but was not given
+func (r *REPL) applyContext(root *cobra.Command, args []string) []string {
+	target, _, err := root.Find(args)
+	if err != nil {
+		return args
+	}
+
+	for _, name := range ContextFlags {
+		value, ok := r.context[name]
+		if !ok || target.Flags().Lookup(name) == nil || hasFlag(args, name) {
+			continue
+		}
+		args = append(args, "--"+name, value)
+	}
+	return args
+}
+
+// Complete returns the completions for the last word of a partial line: the
+// builtins and subcommands, or the flags of the command named so far
+func (r *REPL) Complete(line string) []string {
+	words := strings.Fields(line)
+	prefix := ""
+	if len(words) > 0 && !strings.HasSuffix(line, " ") {
+		prefix = words[len(words)-1]
+		words = words[:len(words)-1]
+	}
+
+	root := r.config.NewRoot()
+	var commandWords []string
+	for _, word := range words {
+		if !strings.HasPrefix(word, "-") {
+			commandWords = append(commandWords, word)
+		}
+	}
+	target, _, err := root.Find(commandWords)
+	if err != nil {
+		return nil
+	}
+
+	var candidates []string
+	if strings.HasPrefix(prefix, "-") {
+		target.InitDefaultHelpFlag()
+		target.Flags().VisitAll(func(flag *pflag.Flag) {
+			if !flag.Hidden {
+				candidates = append(candidates, "--"+flag.Name)
+			}
+		})
+		target.InheritedFlags().VisitAll(func(flag *pflag.Flag) {
+			if !flag.Hidden {
+				candidates = append(candidates, "--"+flag.Name)
+			}
+		})
+	} else {
+		for _, sub := range target.Commands() {
+			if sub.IsAvailableCommand() {
+				candidates = append(candidates, sub.Name())
+			}
+		}
+		if target == root {
+			candidates = append(candidates, builtins...)
+		}
+	}
+
+	var matches []string
+	seen := make(map[string]bool)
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, prefix) && !seen[candidate] {
+			seen[candidate] = true
+			matches = append(matches, candidate)
+		}
+	}
+	sort.Strings(matches)
+	return matches
+}
+
+// completeWord is the completer for liner: it splits line at the cursor
+// position pos, and completes the word before it with Complete
+func (r *REPL) completeWord(line string, pos int) (head string, completions []string, tail string) {
+	runes := []rune(line)
+	before := string(runes[:pos])
+	start := strings.LastIndexAny(before, " \t") + 1
+	return before[:start], r.Complete(before), string(runes[pos:])
+}
+
+// printHelp describes the REPL builtins
+func (r *REPL) printHelp() {
+	fmt.Fprintf(r.config.Out, `Run any cliguard command without the 'cliguard' prefix, e.g.:
+  generate --project-path ./my-cli --entrypoint cmd.NewRootCmd
+  validate
+
+Once given, %s are remembered and passed to later commands.
+
+Session commands:
+  set <flag> <value>   Remember a flag value
+  unset <flag>         Forget a flag value
+  context              Show remembered flag values
+  history              List previous commands; '!N' re-runs entry N
+  help [command]       Show this help, or help for a cliguard command
+  exit, quit           Leave the REPL
+`, strings.Join(quoteAll(ContextFlags), ", "))
+}
+
+// printContext lists the remembered flag values
+func (r *REPL) printContext() {
+	if len(r.context) == 0 {
+		fmt.Fprintln(r.config.Out, "No flags remembered.")
+		return
+	}
+	for _, name := range ContextFlags {
+		if value, ok := r.context[name]; ok {
+			fmt.Fprintf(r.config.Out, "  --%s %s\n", name, value)
+		}
+	}
+}
+
+// loadHistory reads previous sessions' history, ignoring a missing file
+func (r *REPL) loadHistory() {
+	if r.config.HistoryPath == "" {
+		return
+	}
+	data, err := os.ReadFile(r.config.HistoryPath)
+	if err != nil {
+		return
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			r.history = append(r.history, line)
+		}
+	}
+	if len(r.history) > maxHistory {
+		r.history = r.history[len(r.history)-maxHistory:]
+	}
+}
+
+// addHistory records a line in the session and the history file
+func (r *REPL) addHistory(line string) {
+	r.history = append(r.history, line)
+	if r.config.HistoryPath == "" {
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.config.HistoryPath), 0700); err != nil {
+		return
+	}
+	f, err := os.OpenFile(r.config.HistoryPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	fmt.Fprintln(f, line)
+}
+
+// SplitArgs splits a command line into arguments, honouring single quotes,
+// double quotes and backslash escapes
+func SplitArgs(line string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inWord := false
+	var quote rune
+
+	runes := []rune(line)
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else if c == '\\' && quote == '"' && i+1 < len(runes) {
+				i++
+				current.WriteRune(runes[i])
+			} else {
+				current.WriteRune(c)
+			}
+		case c == '\'' || c == '"':
+			quote = c
+			inWord = true
+		case c == '\\' && i+1 < len(runes):
+			i++
+			current.WriteRune(runes[i])
+			inWord = true
+		case c == ' ' || c == '\t':
+			if inWord {
+				args = append(args, current.String())
+				current.Reset()
+				inWord = false
+			}
+		default:
+			current.WriteRune(c)
+			inWord = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if inWord {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
+
+// hasFlag reports whether args set the named long flag
+func hasFlag(args []string, name string) bool {
+	for _, arg := range args {
+		if arg == "--"+name || strings.HasPrefix(arg, "--"+name+"=") {
+			return true
+		}
+	}
+	return false
+}
+
+// isContextFlag reports whether name is one of ContextFlags
+func isContextFlag(name string) bool {
+	for _, flag := range ContextFlags {
+		if flag == name {
+			return true
+		}
+	}
+	return false
+}
+
+// quoteAll formats flag names as "--name"
+func quoteAll(names []string) []string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = "--" + name
+	}
+	return quoted
+}
@@ -0,0 +1,266 @@
+package repl
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	cliguarderrors "github.com/hiAndrewQuinn/cliguard/internal/errors"
+	"github.com/spf13/cobra"
+)
+
+// fakeRoot builds a small command tree that records the arguments it runs with
+func fakeRoot(calls *[]string) func() *cobra.Command {
+	return func() *cobra.Command {
+		root := &cobra.Command{Use: "cliguard"}
+
+		var projectPath, entrypoint string
+		validate := &cobra.Command{
+			Use: "validate",
+			RunE: func(cmd *cobra.Command, args []string) error {
+				*calls = append(*calls, fmt.Sprintf("validate project=%s entrypoint=%s", projectPath, entrypoint))
+				if projectPath == "broken" {
+					return cliguarderrors.ErrValidationFailed
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), "validated")
+				return nil
+			},
+		}
+		validate.Flags().StringVar(&projectPath, "project-path", "", "Project path")
+		validate.Flags().StringVar(&entrypoint, "entrypoint", "", "Entrypoint")
+
+		show := &cobra.Command{
+			Use: "show",
+			RunE: func(cmd *cobra.Command, args []string) error {
+				*calls = append(*calls, "show project="+projectPath)
+				return nil
+			},
+		}
+		show.Flags().StringVar(&projectPath, "project-path", "", "Project path")
+
+		var contractPath string
+		audit := &cobra.Command{
+			Use: "audit",
+			RunE: func(cmd *cobra.Command, args []string) error {
+				*calls = append(*calls, fmt.Sprintf("audit contract=%s args=%v", contractPath, args))
+				return nil
+			},
+		}
+		audit.Flags().StringVar(&contractPath, "contract", "", "Contract")
+
+		var jqFilter string
+		inspect := &cobra.Command{
+			Use: "inspect",
+			RunE: func(cmd *cobra.Command, args []string) error {
+				*calls = append(*calls, fmt.Sprintf("inspect project=%s filter=%s", projectPath, jqFilter))
+				return nil
+			},
+		}
+		inspect.Flags().StringVar(&projectPath, "project-path", "", "Project path")
+		inspect.Flags().StringVar(&jqFilter, "jq-filter", "", "jq filter")
+
+		root.AddCommand(validate, show, audit, inspect)
+		return root
+	}
+}
+
+func runSession(t *testing.T, input string, historyPath string) (string, []string) {
+	t.Helper()
+	var calls []string
+	out := new(bytes.Buffer)
+	r := New(Config{
+		In:          strings.NewReader(input),
+		Out:         out,
+		NewRoot:     fakeRoot(&calls),
+		HistoryPath: historyPath,
+	})
+	if err := r.Run(); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	return out.String(), calls
+}
+
+func TestREPL_RemembersContext(t *testing.T) {
+	input := `validate --project-path ./app --entrypoint cmd.NewRootCmd
+validate
+show
+audit
+set contract other.yaml
+audit
+unset project-path
+validate --entrypoint=main.Root
+exit
+validate
+`
+	output, calls := runSession(t, input, "")
+
+	want := []string{
+		"validate project=./app entrypoint=cmd.NewRootCmd",
+		"validate project=./app entrypoint=cmd.NewRootCmd",
+		// show only takes --project-path
+		"show project=./app",
+		// audit takes none of the remembered flags
+		"audit contract= args=[]",
+		"audit contract=other.yaml args=[]",
+		"validate project= entrypoint=main.Root",
+	}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls =\n%s\nwant:\n%s", strings.Join(calls, "\n"), strings.Join(want, "\n"))
+	}
+
+	if !strings.Contains(output, "cliguard (./app)> ") {
+		t.Errorf("prompt should show the project path:\n%s", output)
+	}
+	if strings.Count(output, "validated") != 3 {
+		t.Errorf("output = %s", output)
+	}
+}
+
+func TestREPL_Errors(t *testing.T) {
+	input := `validate --project-path broken
+frobnicate
+validate "unterminated
+set
+`
+	output, calls := runSession(t, input, "")
+
+	if len(calls) != 1 {
+		t.Errorf("calls = %v", calls)
+	}
+	// Validation failures have already printed their report
+	if strings.Contains(output, "validation failed") {
+		t.Errorf("validation failure should not be printed as an error:\n%s", output)
+	}
+	for _, want := range []string{
+		`Error: unknown command "frobnicate"`,
+		"Error: unterminated \" quote",
+		"Usage: set <project-path|entrypoint|contract> <value>",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+}
+
+func TestREPL_History(t *testing.T) {
+	historyPath := filepath.Join(t.TempDir(), "cliguard", "history")
+
+	_, calls := runSession(t, "validate --project-path ./a\nshow\n", historyPath)
+	if len(calls) != 2 {
+		t.Fatalf("calls = %v", calls)
+	}
+
+	data, err := os.ReadFile(historyPath)
+	if err != nil {
+		t.Fatalf("history file not written: %v", err)
+	}
+	if string(data) != "validate --project-path ./a\nshow\n" {
+		t.Errorf("history file = %q", data)
+	}
+
+	// A new session loads the history and can re-run entries
+	output, calls := runSession(t, "history\n!1\n!9\n", historyPath)
+	if !strings.Contains(output, "   1  validate --project-path ./a\n   2  show\n") {
+		t.Errorf("history output:\n%s", output)
+	}
+	if !reflect.DeepEqual(calls, []string{"validate project=./a entrypoint="}) {
+		t.Errorf("calls = %v", calls)
+	}
+	if !strings.Contains(output, "Error: no history entry '9'") {
+		t.Errorf("output missing history error:\n%s", output)
+	}
+}
+
+func TestREPL_Complete(t *testing.T) {
+	var calls []string
+	r := New(Config{NewRoot: fakeRoot(&calls)})
+
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{"", []string{"audit", "context", "exit", "help", "history", "inspect", "quit", "set", "show", "unset", "validate"}},
+		{"va", []string{"validate"}},
+		{"s", []string{"set", "show"}},
+		{"validate --", []string{"--entrypoint", "--help", "--project-path"}},
+		{"validate --project-path ./app --e", []string{"--entrypoint"}},
+		{"inspect --", []string{"--help", "--jq-filter", "--project-path"}},
+		{"validate ", nil},
+		{"nope --", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			if got := r.Complete(tt.line); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Complete(%q) = %v, want %v", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestREPL_CompleteWord(t *testing.T) {
+	var calls []string
+	r := New(Config{NewRoot: fakeRoot(&calls)})
+
+	tests := []struct {
+		line        string
+		pos         int
+		wantHead    string
+		wantMatches []string
+		wantTail    string
+	}{
+		{"vali", 4, "", []string{"validate"}, ""},
+		{"validate --project-path ./app --e", 33, "validate --project-path ./app ", []string{"--entrypoint"}, ""},
+		{"validate --e ./app", 12, "validate ", []string{"--entrypoint"}, " ./app"},
+		{"inspect --p", 10, "inspect ", []string{"--help", "--jq-filter", "--project-path"}, "p"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			head, matches, tail := r.completeWord(tt.line, tt.pos)
+			if head != tt.wantHead || !reflect.DeepEqual(matches, tt.wantMatches) || tail != tt.wantTail {
+				t.Errorf("completeWord(%q, %d) = %q, %v, %q, want %q, %v, %q", tt.line, tt.pos, head, matches, tail, tt.wantHead, tt.wantMatches, tt.wantTail)
+			}
+		})
+	}
+}
+
+func TestREPL_Inspect(t *testing.T) {
+	_, calls := runSession(t, "set project-path ./app\ninspect --jq-filter '.commands[].use'\n", "")
+
+	want := []string{"inspect project=./app filter=.commands[].use"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+}
+
+func TestSplitArgs(t *testing.T) {
+	tests := []struct {
+		line    string
+		want    []string
+		wantErr bool
+	}{
+		{"validate --project-path ./app", []string{"validate", "--project-path", "./app"}, false},
+		{`set project-path "my project"`, []string{"set", "project-path", "my project"}, false},
+		{`set contract 'it''s.yaml'`, []string{"set", "contract", "its.yaml"}, false},
+		{`a\ b "c\"d"`, []string{"a b", `c"d`}, false},
+		{`empty ""`, []string{"empty", ""}, false},
+		{`"unterminated`, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			got, err := SplitArgs(tt.line)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SplitArgs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitArgs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
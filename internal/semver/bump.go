@@ -0,0 +1,87 @@
+// Package semver derives the semantic version bump a release needs from how
+// its CLI differs from the contract of the previous release.
+//
+// Example:
+//
+//	level := semver.SuggestBump(result)
+//	if level == semver.BumpMajor {
+//	    fmt.Println("This release breaks existing invocations")
+//	}
+package semver
+
+import (
+	"fmt"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/validator"
+)
+
+// BumpLevel is the part of a semantic version a release has to increment
+type BumpLevel string
+
+const (
+	// BumpNone is suggested when the CLI matches the contract
+	BumpNone BumpLevel = "none"
+
+	// BumpPatch is suggested when only descriptions, aliases and other
+	// details of existing commands and flags changed
+	BumpPatch BumpLevel = "patch"
+
+	// BumpMinor is suggested when the CLI only gained commands or flags
+	BumpMinor BumpLevel = "minor"
+
+	// BumpMajor is suggested when commands or flags were removed or a flag
+	// changed type, breaking invocations that use them
+	BumpMajor BumpLevel = "major"
+)
+
+// Changes counts the differences of a validation that decide the bump
+type Changes struct {
+	// Additions are commands and flags the contract lacks
+	Additions int
+
+	// Breaking are missing commands and flags, and flags whose type changed
+	Breaking int
+
+	// Other are the remaining differences, such as changed descriptions
+	Other int
+}
+
+// CountChanges counts the result's errors. Results shortened with Top or
+// Critical are counted in full.
+func CountChanges(result *validator.ValidationResult) Changes {
+	summary := result.Summary()
+	if result.Totals != nil {
+		summary = *result.Totals
+	}
+	return Changes{
+		Additions: summary.Unexpected.Commands + summary.Unexpected.Flags,
+		Breaking: summary.Missing.Commands + summary.Missing.Flags +
+			summary.InvalidType.Commands + summary.InvalidType.Flags,
+		Other: summary.Mismatch.Commands + summary.Mismatch.Flags,
+	}
+}
+
+// SuggestBump returns the version bump for a release whose CLI validated
+// with result against the previous release's contract
+func SuggestBump(result *validator.ValidationResult) BumpLevel {
+	return CountChanges(result).Bump()
+}
+
+// Bump returns the version bump the changes need
+func (c Changes) Bump() BumpLevel {
+	switch {
+	case c.Breaking > 0:
+		return BumpMajor
+	case c.Additions > 0:
+		return BumpMinor
+	case c.Other > 0:
+		return BumpPatch
+	default:
+		return BumpNone
+	}
+}
+
+// String describes the changes, e.g. "3 additions, 0 breaking changes"
+func (c Changes) String() string {
+	return fmt.Sprintf("%s, %s", validator.Plural(c.Additions, "addition"), validator.Plural(c.Breaking, "breaking change"))
+}
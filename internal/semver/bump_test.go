@@ -0,0 +1,98 @@
+package semver
+
+import (
+	"testing"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/validator"
+)
+
+func TestSuggestBump(t *testing.T) {
+	tests := []struct {
+		name   string
+		errors []validator.ValidationError
+		want   BumpLevel
+	}{
+		{
+			name: "no errors",
+			want: BumpNone,
+		},
+		{
+			name: "only mismatches",
+			errors: []validator.ValidationError{
+				{Type: validator.ErrorTypeMismatch, Path: "serve"},
+			},
+			want: BumpPatch,
+		},
+		{
+			name: "only additions",
+			errors: []validator.ValidationError{
+				{Type: validator.ErrorTypeUnexpected, Path: "serve"},
+				{Type: validator.ErrorTypeUnexpected, Path: "serve --port"},
+				{Type: validator.ErrorTypeMismatch, Path: "serve"},
+			},
+			want: BumpMinor,
+		},
+		{
+			name: "missing flag",
+			errors: []validator.ValidationError{
+				{Type: validator.ErrorTypeUnexpected, Path: "serve"},
+				{Type: validator.ErrorTypeMissing, Path: "serve --host"},
+			},
+			want: BumpMajor,
+		},
+		{
+			name: "changed flag type",
+			errors: []validator.ValidationError{
+				{Type: validator.ErrorTypeInvalidType, Path: "serve --port"},
+			},
+			want: BumpMajor,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := &validator.ValidationResult{Valid: len(tt.errors) == 0, Errors: tt.errors}
+			if got := SuggestBump(result); got != tt.want {
+				t.Errorf("SuggestBump() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCountChanges_Totals(t *testing.T) {
+	// A shortened result lists some errors but counts them all
+	result := &validator.ValidationResult{
+		Errors: []validator.ValidationError{
+			{Type: validator.ErrorTypeUnexpected, Path: "serve"},
+		},
+		Totals: &validator.Summary{
+			Unexpected: validator.SummaryCount{Commands: 1, Flags: 2},
+			Missing:    validator.SummaryCount{Flags: 1},
+			Total:      4,
+		},
+	}
+
+	changes := CountChanges(result)
+	if changes != (Changes{Additions: 3, Breaking: 1}) {
+		t.Errorf("CountChanges() = %+v", changes)
+	}
+	if got := changes.Bump(); got != BumpMajor {
+		t.Errorf("Bump() = %s, want major", got)
+	}
+}
+
+func TestChanges_String(t *testing.T) {
+	tests := []struct {
+		changes Changes
+		want    string
+	}{
+		{Changes{}, "0 additions, 0 breaking changes"},
+		{Changes{Additions: 1, Breaking: 1}, "1 addition, 1 breaking change"},
+		{Changes{Additions: 3, Other: 2}, "3 additions, 0 breaking changes"},
+	}
+	for _, tt := range tests {
+		if got := tt.changes.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// BatchConfig describes a set of projects to validate in one invocation.
+//
+// Example YAML:
+//
+//	projects:
+//	  - name: service-a
+//	    project_path: ./service-a
+//	    contract: ./service-a/cliguard.yaml
+//	    entrypoint: github.com/org/service-a/cmd.NewRootCmd
+//	  - project_path: ./service-b
+//	    entrypoint: github.com/org/service-b/cmd.NewRootCmd
+type BatchConfig struct {
+	Projects []BatchProject `yaml:"projects"`
+}
+
+// BatchProject is a single entry in a BatchConfig
+type BatchProject struct {
+	// Name is a display name for the project (optional).
+	// Defaults to the base name of ProjectPath.
+	Name string `yaml:"name,omitempty"`
+
+	// ProjectPath is the path to the Go project (required).
+	// Relative paths are resolved against the config file's directory.
+	ProjectPath string `yaml:"project_path"`
+
+	// Contract is the path to the contract file (optional).
+	// Defaults to cliguard.yaml in the project path.
+	Contract string `yaml:"contract,omitempty"`
+
+	// Entrypoint is the function that returns the root command (optional).
+	Entrypoint string `yaml:"entrypoint,omitempty"`
+}
+
+// DisplayName returns the project's name, falling back to the base name of its path
+func (p BatchProject) DisplayName() string {
+	if p.Name != "" {
+		return p.Name
+	}
+	return filepath.Base(p.ProjectPath)
+}
+
+// LoadBatchConfig reads a batch configuration file. Relative project and
+// contract paths are resolved against the directory containing the file.
+func LoadBatchConfig(configPath string) (*BatchConfig, error) {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read batch config '%s': %w", configPath, err)
+	}
+
+	var config BatchConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse batch config '%s': %w", configPath, err)
+	}
+
+	if len(config.Projects) == 0 {
+		return nil, fmt.Errorf("batch config '%s' does not list any projects", configPath)
+	}
+
+	baseDir := filepath.Dir(configPath)
+	for i := range config.Projects {
+		project := &config.Projects[i]
+		if project.ProjectPath == "" {
+			return nil, fmt.Errorf("batch config '%s': project %d: 'project_path' cannot be empty", configPath, i+1)
+		}
+		if !filepath.IsAbs(project.ProjectPath) {
+			project.ProjectPath = filepath.Join(baseDir, project.ProjectPath)
+		}
+		if project.Contract != "" && !filepath.IsAbs(project.Contract) {
+			project.Contract = filepath.Join(baseDir, project.Contract)
+		}
+	}
+
+	return &config, nil
+}
+
+// ValidateMultiple validates several projects concurrently, running up to
+// GOMAXPROCS validations at a time. Results are returned in the same order
+// as opts.
+//
+// A project that cannot be validated (e.g. missing contract or build
+// failure) has its ValidateResult.Error set and Success=false; the other
+// projects are still validated. The returned error joins all such per-project
+// errors and is nil when every validation ran.
+//
+// Example:
+//
+//	results, err := svc.ValidateMultiple([]ValidateOptions{
+//	    {ProjectPath: "./service-a", Entrypoint: "github.com/org/service-a/cmd.NewRootCmd"},
+//	    {ProjectPath: "./service-b", Entrypoint: "github.com/org/service-b/cmd.NewRootCmd"},
+//	})
+//	for i, result := range results {
+//	    fmt.Printf("%d: success=%v\n", i, result.Success)
+//	}
+func (s *ValidateService) ValidateMultiple(opts []ValidateOptions) ([]ValidateResult, error) {
+	results := make([]ValidateResult, len(opts))
+
+	workers := runtime.GOMAXPROCS(0)
+	if workers > len(opts) {
+		workers = len(opts)
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				result, err := s.Validate(opts[i])
+				if err != nil {
+					results[i] = ValidateResult{Success: false, Error: err}
+					continue
+				}
+				results[i] = *result
+			}
+		}()
+	}
+
+	for i := range opts {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	var errs []error
+	for i, result := range results {
+		if result.Error != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", opts[i].ProjectPath, result.Error))
+		}
+	}
+
+	return results, errors.Join(errs...)
+}
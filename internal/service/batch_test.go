@@ -0,0 +1,170 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/contract"
+	"github.com/hiAndrewQuinn/cliguard/internal/inspector"
+)
+
+func TestValidateService_ValidateMultiple(t *testing.T) {
+	projects := []string{t.TempDir(), t.TempDir(), t.TempDir(), "/nonexistent/project"}
+
+	svc := &ValidateService{
+		ContractLoader: func(path string) (*contract.Contract, error) {
+			return &contract.Contract{Use: filepath.Base(filepath.Dir(path)), Short: "A CLI"}, nil
+		},
+		InspectorWithTimeout: func(projectPath, entrypoint string, timeout time.Duration) (*inspector.InspectedCLI, error) {
+			// Make the second project fail validation
+			use := filepath.Base(projectPath)
+			if projectPath == projects[1] {
+				use = "renamed"
+			}
+			// Finish earlier projects last to check that results keep the input order
+			for i, p := range projects {
+				if p == projectPath {
+					time.Sleep(time.Duration(len(projects)-i) * 5 * time.Millisecond)
+				}
+			}
+			return &inspector.InspectedCLI{Use: use, Short: "A CLI"}, nil
+		},
+	}
+
+	var opts []ValidateOptions
+	for _, p := range projects {
+		opts = append(opts, ValidateOptions{ProjectPath: p, Timeout: time.Second})
+	}
+
+	results, err := svc.ValidateMultiple(opts)
+	if err == nil {
+		t.Error("ValidateMultiple() error = nil, want error for nonexistent project")
+	}
+
+	if len(results) != len(projects) {
+		t.Fatalf("len(results) = %d, want %d", len(results), len(projects))
+	}
+
+	want := []bool{true, false, true, false}
+	for i, result := range results {
+		if result.Success != want[i] {
+			t.Errorf("results[%d].Success = %v, want %v", i, result.Success, want[i])
+		}
+	}
+
+	if results[1].Error != nil || results[1].Result == nil || len(results[1].Result.Errors) != 1 {
+		t.Errorf("results[1] = %+v, want a single validation error", results[1])
+	}
+	if results[3].Error == nil {
+		t.Error("results[3].Error = nil, want project not found error")
+	}
+}
+
+func TestValidateService_ValidateMultiple_Empty(t *testing.T) {
+	results, err := NewValidateService().ValidateMultiple(nil)
+	if err != nil {
+		t.Errorf("ValidateMultiple() error = %v, want nil", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("len(results) = %d, want 0", len(results))
+	}
+}
+
+func TestLoadBatchConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		wantErr     bool
+		wantProject BatchProject
+	}{
+		{
+			name: "relative paths resolved against config dir",
+			content: `projects:
+  - name: service-a
+    project_path: ./service-a
+    contract: contracts/a.yaml
+    entrypoint: github.com/org/service-a/cmd.NewRootCmd
+`,
+			wantProject: BatchProject{
+				Name:        "service-a",
+				ProjectPath: "service-a",
+				Contract:    "contracts/a.yaml",
+				Entrypoint:  "github.com/org/service-a/cmd.NewRootCmd",
+			},
+		},
+		{
+			name: "absolute paths kept",
+			content: `projects:
+  - project_path: /abs/service-b
+`,
+			wantProject: BatchProject{ProjectPath: "/abs/service-b"},
+		},
+		{
+			name:    "no projects",
+			content: "projects: []\n",
+			wantErr: true,
+		},
+		{
+			name: "missing project path",
+			content: `projects:
+  - name: broken
+`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid yaml",
+			content: "projects: [\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			configPath := filepath.Join(dir, "multi.yaml")
+			if err := os.WriteFile(configPath, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			config, err := LoadBatchConfig(configPath)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadBatchConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			want := tt.wantProject
+			if !filepath.IsAbs(want.ProjectPath) {
+				want.ProjectPath = filepath.Join(dir, want.ProjectPath)
+			}
+			if want.Contract != "" && !filepath.IsAbs(want.Contract) {
+				want.Contract = filepath.Join(dir, want.Contract)
+			}
+			if got := config.Projects[0]; got != want {
+				t.Errorf("Projects[0] = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestBatchProject_DisplayName(t *testing.T) {
+	tests := []struct {
+		project BatchProject
+		want    string
+	}{
+		{BatchProject{Name: "api", ProjectPath: "/src/service-a"}, "api"},
+		{BatchProject{ProjectPath: "/src/service-a"}, "service-a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.want), func(t *testing.T) {
+			if got := tt.project.DisplayName(); got != tt.want {
+				t.Errorf("DisplayName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
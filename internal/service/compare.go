@@ -0,0 +1,177 @@
+package service
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/inspector"
+	"github.com/hiAndrewQuinn/cliguard/internal/validator"
+)
+
+// CompareOptions contains options for the compare command
+type CompareOptions struct {
+	OldProjectPath string
+	OldEntrypoint  string
+	NewProjectPath string
+	NewEntrypoint  string
+	Timeout        time.Duration
+}
+
+// CompareResult holds the differences between two CLIs, split by whether
+// they break existing invocations of the old CLI
+type CompareResult struct {
+	Breaking    []validator.ValidationError
+	NonBreaking []validator.ValidationError
+}
+
+// Identical reports whether the two CLIs have the same structure
+func (r *CompareResult) Identical() bool {
+	return len(r.Breaking) == 0 && len(r.NonBreaking) == 0
+}
+
+// HasBreaking reports whether any difference breaks the old CLI's users
+func (r *CompareResult) HasBreaking() bool {
+	return len(r.Breaking) > 0
+}
+
+// CompareService compares the structure of two live CLIs without a contract
+type CompareService struct {
+	// Inspector analyzes Go projects to extract CLI structure.
+	// Defaults to running inspector.NewInspector(config).Inspect()
+	Inspector func(inspector.Config) (*inspector.InspectedCLI, error)
+}
+
+// NewCompareService creates a new CompareService with default dependencies
+func NewCompareService() *CompareService {
+	return &CompareService{
+		Inspector: func(config inspector.Config) (*inspector.InspectedCLI, error) {
+			return inspector.NewInspector(config).Inspect()
+		},
+	}
+}
+
+// Compare inspects both CLIs, treats the old one as the contract and
+// validates the new one against it
+func (s *CompareService) Compare(opts CompareOptions) (*CompareResult, error) {
+	oldCLI, err := s.Inspector(inspector.Config{
+		ProjectPath: opts.OldProjectPath,
+		Entrypoint:  opts.OldEntrypoint,
+		Timeout:     opts.Timeout,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect old CLI: %w", err)
+	}
+
+	newCLI, err := s.Inspector(inspector.Config{
+		ProjectPath: opts.NewProjectPath,
+		Entrypoint:  opts.NewEntrypoint,
+		Timeout:     opts.Timeout,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect new CLI: %w", err)
+	}
+
+	// The old CLI is its complete contract, so everything the new one adds,
+	// aliases included, is reported
+	validation := validator.ValidateWithOptions(inspectedToDisplayContract(oldCLI), newCLI, validator.Options{Strict: true})
+
+	// Flags and commands are matched through maps, so sort for stable output
+	differences := validation.Errors
+	sort.SliceStable(differences, func(i, j int) bool {
+		return differences[i].Path < differences[j].Path
+	})
+
+	result := &CompareResult{}
+	for _, difference := range differences {
+		if isBreaking(difference) {
+			result.Breaking = append(result.Breaking, difference)
+		} else {
+			result.NonBreaking = append(result.NonBreaking, difference)
+		}
+	}
+	return result, nil
+}
+
+// isBreaking reports whether a difference can break an invocation that
+// worked against the old CLI: removed commands and flags, renamed roots,
+// changed flag types, removed shorthands and aliases, persistent flags that
+// became local, and new flag groups, which reject invocations using some of
+// their flags. Additions and changes to descriptions, examples,
+// defaults and completions are reported as non-breaking.
+func isBreaking(difference validator.ValidationError) bool {
+	switch difference.Type {
+	case validator.ErrorTypeMissing, validator.ErrorTypeInvalidType:
+		return true
+	case validator.ErrorTypeUnexpected:
+		return false
+	}
+
+	switch difference.Message {
+	case "Mismatch in 'use' field":
+		return true
+	case "Flag shorthand mismatch":
+		return difference.Expected != ""
+	case "Flag persistence mismatch":
+		return difference.Expected == "persistent"
+	case "Mismatch in mutually exclusive flags", "Mismatch in required together flags":
+		expected := strings.Split(difference.Expected, "; ")
+		for _, group := range strings.Split(difference.Actual, "; ") {
+			if group != "" && !containsString(expected, group) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// containsString reports whether values contains s
+func containsString(values []string, s string) bool {
+	for _, value := range values {
+		if value == s {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteDiff writes the differences in diff form: '-' marks something the new
+// CLI removed, '+' something it added and '~' something it changed
+func (r *CompareResult) WriteDiff(w io.Writer) {
+	if r.Identical() {
+		fmt.Fprintln(w, "No differences found.")
+		return
+	}
+
+	if len(r.Breaking) > 0 {
+		fmt.Fprintln(w, "Breaking changes:")
+		for _, difference := range r.Breaking {
+			fmt.Fprintf(w, "  %s\n", formatDifference(difference))
+		}
+		fmt.Fprintln(w)
+	}
+
+	if len(r.NonBreaking) > 0 {
+		fmt.Fprintln(w, "Non-breaking changes:")
+		for _, difference := range r.NonBreaking {
+			fmt.Fprintf(w, "  %s\n", formatDifference(difference))
+		}
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintf(w, "%d breaking, %d non-breaking change(s)\n", len(r.Breaking), len(r.NonBreaking))
+}
+
+// formatDifference renders a single difference as a diff line
+func formatDifference(difference validator.ValidationError) string {
+	switch difference.Type {
+	case validator.ErrorTypeMissing:
+		return fmt.Sprintf("- %s (%s removed)", difference.Path, difference.Message)
+	case validator.ErrorTypeUnexpected:
+		return fmt.Sprintf("+ %s (%s added)", difference.Path, difference.Message)
+	default:
+		return fmt.Sprintf("~ %s: %s (%q -> %q)", difference.Path, difference.Message, difference.Expected, difference.Actual)
+	}
+}
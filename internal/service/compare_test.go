@@ -0,0 +1,143 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/inspector"
+)
+
+func compareServiceFor(oldCLI, newCLI *inspector.InspectedCLI) *CompareService {
+	return &CompareService{
+		Inspector: func(config inspector.Config) (*inspector.InspectedCLI, error) {
+			if config.ProjectPath == "/old" {
+				return oldCLI, nil
+			}
+			return newCLI, nil
+		},
+	}
+}
+
+func TestCompareService_Compare(t *testing.T) {
+	baseCLI := func() *inspector.InspectedCLI {
+		return &inspector.InspectedCLI{
+			Use:   "myapp",
+			Short: "My application",
+			Flags: []inspector.InspectedFlag{
+				{Name: "verbose", Shorthand: "v", Type: "bool", Usage: "Verbose output", Persistent: true},
+			},
+			Commands: []inspector.InspectedCommand{
+				{Use: "serve", Short: "Start the server", Aliases: []string{"s"}},
+				{Use: "build", Short: "Build the project"},
+			},
+		}
+	}
+
+	t.Run("identical", func(t *testing.T) {
+		result, err := compareServiceFor(baseCLI(), baseCLI()).Compare(CompareOptions{OldProjectPath: "/old", NewProjectPath: "/new"})
+		if err != nil {
+			t.Fatalf("Compare() error = %v", err)
+		}
+		if !result.Identical() {
+			t.Errorf("expected identical CLIs, got %+v", result)
+		}
+	})
+
+	t.Run("non-breaking", func(t *testing.T) {
+		newCLI := baseCLI()
+		newCLI.Short = "My improved application"
+		newCLI.Commands = append(newCLI.Commands, inspector.InspectedCommand{Use: "clean", Short: "Remove build output"})
+		newCLI.Commands[0].Aliases = []string{"s", "run"}
+
+		result, err := compareServiceFor(baseCLI(), newCLI).Compare(CompareOptions{OldProjectPath: "/old", NewProjectPath: "/new"})
+		if err != nil {
+			t.Fatalf("Compare() error = %v", err)
+		}
+		if result.HasBreaking() {
+			t.Errorf("expected no breaking changes, got %+v", result.Breaking)
+		}
+		if len(result.NonBreaking) != 3 {
+			t.Errorf("expected 3 non-breaking changes, got %+v", result.NonBreaking)
+		}
+	})
+
+	t.Run("breaking", func(t *testing.T) {
+		newCLI := baseCLI()
+		newCLI.Commands = newCLI.Commands[:1]
+		newCLI.Commands[0].Aliases = nil
+		newCLI.Flags[0].Type = "string"
+		newCLI.Flags[0].Persistent = false
+
+		result, err := compareServiceFor(baseCLI(), newCLI).Compare(CompareOptions{OldProjectPath: "/old", NewProjectPath: "/new"})
+		if err != nil {
+			t.Fatalf("Compare() error = %v", err)
+		}
+		if len(result.Breaking) != 4 {
+			t.Errorf("expected 4 breaking changes, got %+v", result.Breaking)
+		}
+		if len(result.NonBreaking) != 0 {
+			t.Errorf("expected no non-breaking changes, got %+v", result.NonBreaking)
+		}
+
+		var buf bytes.Buffer
+		result.WriteDiff(&buf)
+		for _, want := range []string{
+			"Breaking changes:",
+			"- build (command removed)",
+			"- serve (alias 's' removed)",
+			`~ --verbose: Flag type mismatch ("bool" -> "string")`,
+			"4 breaking, 0 non-breaking change(s)",
+		} {
+			if !strings.Contains(buf.String(), want) {
+				t.Errorf("diff missing %q:\n%s", want, buf.String())
+			}
+		}
+	})
+
+	t.Run("flag groups", func(t *testing.T) {
+		oldCLI := baseCLI()
+		oldCLI.Commands[0].MutuallyExclusive = [][]string{{"http", "https"}}
+		newCLI := baseCLI()
+		newCLI.Commands[0].MutuallyExclusive = [][]string{{"http", "https"}, {"port", "socket"}}
+
+		// A new group rejects invocations that worked
+		result, err := compareServiceFor(oldCLI, newCLI).Compare(CompareOptions{OldProjectPath: "/old", NewProjectPath: "/new"})
+		if err != nil {
+			t.Fatalf("Compare() error = %v", err)
+		}
+		if len(result.Breaking) != 1 || len(result.NonBreaking) != 0 {
+			t.Errorf("expected 1 breaking change, got %+v and %+v", result.Breaking, result.NonBreaking)
+		}
+
+		// Removing one only allows more
+		result, err = compareServiceFor(newCLI, oldCLI).Compare(CompareOptions{OldProjectPath: "/old", NewProjectPath: "/new"})
+		if err != nil {
+			t.Fatalf("Compare() error = %v", err)
+		}
+		if len(result.Breaking) != 0 || len(result.NonBreaking) != 1 {
+			t.Errorf("expected 1 non-breaking change, got %+v and %+v", result.Breaking, result.NonBreaking)
+		}
+	})
+
+	t.Run("inspection error", func(t *testing.T) {
+		svc := &CompareService{
+			Inspector: func(config inspector.Config) (*inspector.InspectedCLI, error) {
+				return nil, errors.New("build failed")
+			},
+		}
+		_, err := svc.Compare(CompareOptions{OldProjectPath: "/old", NewProjectPath: "/new"})
+		if err == nil || !strings.Contains(err.Error(), "failed to inspect old CLI") {
+			t.Errorf("Compare() error = %v, want old CLI inspection error", err)
+		}
+	})
+}
+
+func TestCompareResult_WriteDiffIdentical(t *testing.T) {
+	var buf bytes.Buffer
+	(&CompareResult{}).WriteDiff(&buf)
+	if buf.String() != "No differences found.\n" {
+		t.Errorf("WriteDiff() = %q", buf.String())
+	}
+}
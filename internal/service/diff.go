@@ -0,0 +1,360 @@
+package service
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/contract"
+)
+
+// Kinds of the elements a DiffEntry describes
+const (
+	DiffKindCommand = "command"
+	DiffKindFlag    = "flag"
+)
+
+// DiffOptions contains options for the diff command
+type DiffOptions struct {
+	// From is the path of the contract to compare against, e.g. the one on
+	// the main branch
+	From string
+
+	// To is the path of the changed contract
+	To string
+}
+
+// DiffEntry is a command or flag that was added, removed or modified
+// between two contracts
+type DiffEntry struct {
+	// Path is the command path without the root command, followed by the
+	// flag for flags: "db migrate", "db migrate --dry-run" or "--verbose".
+	// The root command itself has the path of its name.
+	Path string
+
+	// Kind is DiffKindCommand or DiffKindFlag
+	Kind string
+
+	// Changes lists the fields that differ, for modified entries
+	Changes []FieldChange
+}
+
+// FieldChange is a contract field whose value differs between two contracts.
+// Values are empty for fields that are not set.
+type FieldChange struct {
+	// Field is the field's YAML name, e.g. "short" or "type"
+	Field string
+	From  string
+	To    string
+}
+
+// DiffResult holds the structural differences between two contracts, each
+// sorted by path
+type DiffResult struct {
+	From string
+	To   string
+
+	Added    []DiffEntry
+	Removed  []DiffEntry
+	Modified []DiffEntry
+}
+
+// Identical reports whether the two contracts have the same structure
+func (r *DiffResult) Identical() bool {
+	return len(r.Added) == 0 && len(r.Removed) == 0 && len(r.Modified) == 0
+}
+
+// DiffService compares two contract files without inspecting a CLI
+type DiffService struct {
+	// ContractLoader loads contract specifications from YAML files.
+	// Defaults to contract.Load
+	ContractLoader func(string) (*contract.Contract, error)
+}
+
+// NewDiffService creates a new DiffService with default dependencies
+func NewDiffService() *DiffService {
+	return &DiffService{
+		ContractLoader: contract.Load,
+	}
+}
+
+// Diff loads both contracts and lists the commands and flags that were
+// added to, removed from or modified in the To contract. Commands are
+// matched by name, so a change to a command's argument pattern is a
+// modification of its use field; flags are matched by name.
+func (s *DiffService) Diff(opts DiffOptions) (*DiffResult, error) {
+	from, err := s.ContractLoader(opts.From)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load contract %s: %w", opts.From, err)
+	}
+	to, err := s.ContractLoader(opts.To)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load contract %s: %w", opts.To, err)
+	}
+
+	return diffContracts(from, to, opts.From, opts.To), nil
+}
+
+// diffContracts compares two loaded contracts as Diff does, naming them
+// fromName and toName
+func diffContracts(from, to *contract.Contract, fromName, toName string) *DiffResult {
+	result := &DiffResult{From: fromName, To: toName}
+	fromRoot, toRoot := rootCommand(from), rootCommand(to)
+	changes := commandChanges(&fromRoot, &toRoot)
+	if from.Version != to.Version {
+		changes = append(changes, FieldChange{Field: "version", From: from.Version, To: to.Version})
+	}
+	if len(changes) > 0 {
+		result.Modified = append(result.Modified, DiffEntry{Path: contract.CommandName(to.Use), Kind: DiffKindCommand, Changes: changes})
+	}
+	diffFlags(result, "", from.Flags, to.Flags)
+	diffCommands(result, "", from.Commands, to.Commands)
+
+	for _, entries := range [][]DiffEntry{result.Added, result.Removed, result.Modified} {
+		sort.SliceStable(entries, func(i, j int) bool {
+			return entries[i].Path < entries[j].Path
+		})
+	}
+	return result
+}
+
+// rootCommand returns the root of the contract as a command, so that it is
+// compared like the others
+func rootCommand(c *contract.Contract) contract.Command {
+	return contract.Command{
+		Use:               c.Use,
+		Short:             c.Short,
+		Long:              c.Long,
+		Aliases:           c.Aliases,
+		Example:           c.Example,
+		MutuallyExclusive: c.MutuallyExclusive,
+		RequiredTogether:  c.RequiredTogether,
+	}
+}
+
+// diffCommands adds the differences between the commands under parentPath,
+// and recursively their subcommands and flags, to result
+func diffCommands(result *DiffResult, parentPath string, from, to []contract.Command) {
+	toByName := make(map[string]*contract.Command)
+	for i := range to {
+		toByName[contract.CommandName(to[i].Use)] = &to[i]
+	}
+	fromByName := make(map[string]*contract.Command)
+	for i := range from {
+		fromByName[contract.CommandName(from[i].Use)] = &from[i]
+	}
+
+	for _, name := range sortedKeys(fromByName) {
+		path := joinDiffPath(parentPath, name)
+		toCmd, found := toByName[name]
+		if !found {
+			result.Removed = append(result.Removed, DiffEntry{Path: path, Kind: DiffKindCommand})
+			continue
+		}
+		if changes := commandChanges(fromByName[name], toCmd); len(changes) > 0 {
+			result.Modified = append(result.Modified, DiffEntry{Path: path, Kind: DiffKindCommand, Changes: changes})
+		}
+		diffFlags(result, path, fromByName[name].Flags, toCmd.Flags)
+		diffCommands(result, path, fromByName[name].Commands, toCmd.Commands)
+	}
+	for _, name := range sortedKeys(toByName) {
+		if _, found := fromByName[name]; !found {
+			result.Added = append(result.Added, DiffEntry{Path: joinDiffPath(parentPath, name), Kind: DiffKindCommand})
+		}
+	}
+}
+
+// diffFlags adds the differences between the flags of the command at
+// commandPath to result
+func diffFlags(result *DiffResult, commandPath string, from, to []contract.Flag) {
+	toByName := make(map[string]*contract.Flag)
+	for i := range to {
+		toByName[to[i].Name] = &to[i]
+	}
+	fromByName := make(map[string]*contract.Flag)
+	for i := range from {
+		fromByName[from[i].Name] = &from[i]
+	}
+
+	for _, name := range sortedKeys(fromByName) {
+		path := joinDiffPath(commandPath, "--"+name)
+		toFlag, found := toByName[name]
+		if !found {
+			result.Removed = append(result.Removed, DiffEntry{Path: path, Kind: DiffKindFlag})
+			continue
+		}
+		if changes := fieldChanges(flagFields(fromByName[name]), flagFields(toFlag)); len(changes) > 0 {
+			result.Modified = append(result.Modified, DiffEntry{Path: path, Kind: DiffKindFlag, Changes: changes})
+		}
+	}
+	for _, name := range sortedKeys(toByName) {
+		if _, found := fromByName[name]; !found {
+			result.Added = append(result.Added, DiffEntry{Path: joinDiffPath(commandPath, "--"+name), Kind: DiffKindFlag})
+		}
+	}
+}
+
+// commandChanges returns the fields of the command, without its flags and
+// subcommands, that differ
+func commandChanges(from, to *contract.Command) []FieldChange {
+	return fieldChanges(commandFields(from), commandFields(to))
+}
+
+// field is the YAML name and formatted value of a contract field
+type field struct {
+	name  string
+	value string
+}
+
+// commandFields returns the fields of a command compared by Diff, in
+// contract order
+func commandFields(cmd *contract.Command) []field {
+	return []field{
+		{"use", cmd.Use},
+		{"short", cmd.Short},
+		{"long", cmd.Long},
+		{"aliases", strings.Join(cmd.Aliases, ", ")},
+		{"example", cmd.Example},
+		{"hidden", formatBool(cmd.Hidden)},
+		{"runnable", formatBoolPtr(cmd.Runnable)},
+		{"group_id", cmd.GroupID},
+		{"mutually_exclusive", strings.Join(formatFlagGroups(cmd.MutuallyExclusive), "; ")},
+		{"required_together", strings.Join(formatFlagGroups(cmd.RequiredTogether), "; ")},
+	}
+}
+
+// flagFields returns the fields of a flag compared by Diff, in contract order
+func flagFields(flag *contract.Flag) []field {
+	return []field{
+		{"shorthand", flag.Shorthand},
+		{"usage", flag.Usage},
+		{"type", flag.Type},
+		{"persistent", formatBool(flag.Persistent)},
+		{"completion", flag.Completion},
+		{"default", flag.Default},
+		{"required", formatBool(flag.Required)},
+		{"enum", strings.Join(flag.Enum, ", ")},
+		{"deprecated", flag.Deprecated},
+		{"hidden", formatBool(flag.Hidden)},
+		{"env", flag.Env},
+	}
+}
+
+// fieldChanges returns the fields whose values differ, given the same
+// fields of two elements
+func fieldChanges(from, to []field) []FieldChange {
+	var changes []FieldChange
+	for i := range from {
+		if from[i].value != to[i].value {
+			changes = append(changes, FieldChange{Field: from[i].name, From: from[i].value, To: to[i].value})
+		}
+	}
+	return changes
+}
+
+// formatBool formats a boolean field, which is empty when false as in the
+// contract file
+func formatBool(value bool) string {
+	if value {
+		return "true"
+	}
+	return ""
+}
+
+// formatBoolPtr formats an optional boolean field, which is empty when unset
+func formatBoolPtr(value *bool) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatBool(*value)
+}
+
+// formatFlagGroups formats flag groups with their flags sorted, sorted
+// themselves, so that reordering them is not a change
+func formatFlagGroups(groups [][]string) []string {
+	formatted := make([]string, len(groups))
+	for i, group := range groups {
+		sorted := append([]string(nil), group...)
+		sort.Strings(sorted)
+		formatted[i] = strings.Join(sorted, ", ")
+	}
+	sort.Strings(formatted)
+	return formatted
+}
+
+// sortedKeys returns the keys of m in ascending order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// joinDiffPath appends name to a command path
+func joinDiffPath(parentPath, name string) string {
+	if parentPath == "" {
+		return name
+	}
+	return parentPath + " " + name
+}
+
+// WriteDiff writes the differences in the style of git diff: a '-' line for
+// each removed command or flag, a '+' line for each added one, and for each
+// modified one a context line followed by the '-' old and '+' new values of
+// its changed fields
+func (r *DiffResult) WriteDiff(w io.Writer) {
+	if r.Identical() {
+		fmt.Fprintln(w, "No differences found.")
+		return
+	}
+
+	fmt.Fprintf(w, "--- %s\n", r.From)
+	fmt.Fprintf(w, "+++ %s\n", r.To)
+
+	type line struct {
+		path string
+		text []string
+	}
+	var lines []line
+	for _, entry := range r.Removed {
+		lines = append(lines, line{entry.Path, []string{fmt.Sprintf("-%s %s", entry.Kind, entry.Path)}})
+	}
+	for _, entry := range r.Added {
+		lines = append(lines, line{entry.Path, []string{fmt.Sprintf("+%s %s", entry.Kind, entry.Path)}})
+	}
+	for _, entry := range r.Modified {
+		text := []string{fmt.Sprintf(" %s %s", entry.Kind, entry.Path)}
+		for _, change := range entry.Changes {
+			if change.From != "" {
+				text = append(text, fmt.Sprintf("-    %s: %s", change.Field, formatDiffValue(change.From)))
+			}
+			if change.To != "" {
+				text = append(text, fmt.Sprintf("+    %s: %s", change.Field, formatDiffValue(change.To)))
+			}
+		}
+		lines = append(lines, line{entry.Path, text})
+	}
+	sort.SliceStable(lines, func(i, j int) bool {
+		return lines[i].path < lines[j].path
+	})
+	for _, l := range lines {
+		for _, text := range l.text {
+			fmt.Fprintln(w, text)
+		}
+	}
+
+	fmt.Fprintf(w, "\n%d added, %d removed, %d modified\n", len(r.Added), len(r.Removed), len(r.Modified))
+}
+
+// formatDiffValue quotes values that span several lines, so that each change
+// stays on one line
+func formatDiffValue(value string) string {
+	if strings.Contains(value, "\n") {
+		return strconv.Quote(value)
+	}
+	return value
+}
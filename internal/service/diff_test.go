@@ -0,0 +1,208 @@
+package service
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/contract"
+)
+
+// diffTestContracts returns a contract and a changed copy of it
+func diffTestContracts() (from, to *contract.Contract) {
+	from = &contract.Contract{
+		Use:     "app",
+		Short:   "An app",
+		Version: "1.0.0",
+		Flags:   []contract.Flag{{Name: "verbose", Shorthand: "v", Usage: "Verbose output", Type: "bool", Persistent: true}},
+		Commands: []contract.Command{
+			{
+				Use:   "db",
+				Short: "Manage the database",
+				Commands: []contract.Command{
+					{Use: "migrate [version]", Short: "Migrate the database", Flags: []contract.Flag{{Name: "dry-run", Usage: "Print the migrations", Type: "bool"}}},
+					{Use: "seed", Short: "Seed the database"},
+				},
+			},
+			{
+				Use:   "serve",
+				Short: "Serve the app",
+				Flags: []contract.Flag{
+					{Name: "port", Usage: "Port to listen on", Type: "int", Default: "8080"},
+					{Name: "format", Usage: "Log format", Type: "string"},
+				},
+			},
+		},
+	}
+	to = &contract.Contract{
+		Use:     "app",
+		Short:   "An app",
+		Version: "1.1.0",
+		Flags:   []contract.Flag{{Name: "verbose", Shorthand: "v", Usage: "Verbose output", Type: "bool", Persistent: true}},
+		Commands: []contract.Command{
+			{
+				Use:   "db",
+				Short: "Manage the database",
+				Commands: []contract.Command{
+					{Use: "migrate <version>", Short: "Migrate the database", Flags: []contract.Flag{{Name: "dry-run", Usage: "Print the migrations", Type: "bool"}}},
+				},
+			},
+			{
+				Use:     "serve",
+				Short:   "Serve the app",
+				Aliases: []string{"s"},
+				Flags: []contract.Flag{
+					{Name: "port", Usage: "Port to listen on", Type: "string"},
+					{Name: "listen", Usage: "Address to listen on", Type: "string"},
+				},
+			},
+			{Use: "version", Short: "Print the version"},
+		},
+	}
+	return from, to
+}
+
+// newTestDiffService returns a DiffService loading the contracts by path
+func newTestDiffService(contracts map[string]*contract.Contract) *DiffService {
+	return &DiffService{
+		ContractLoader: func(path string) (*contract.Contract, error) {
+			if c, ok := contracts[path]; ok {
+				return c, nil
+			}
+			return nil, os.ErrNotExist
+		},
+	}
+}
+
+func TestDiffService_Diff(t *testing.T) {
+	from, to := diffTestContracts()
+	svc := newTestDiffService(map[string]*contract.Contract{"main.yaml": from, "branch.yaml": to})
+
+	result, err := svc.Diff(DiffOptions{From: "main.yaml", To: "branch.yaml"})
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+
+	wantAdded := []DiffEntry{
+		{Path: "serve --listen", Kind: DiffKindFlag},
+		{Path: "version", Kind: DiffKindCommand},
+	}
+	wantRemoved := []DiffEntry{
+		{Path: "db seed", Kind: DiffKindCommand},
+		{Path: "serve --format", Kind: DiffKindFlag},
+	}
+	wantModified := []DiffEntry{
+		{Path: "app", Kind: DiffKindCommand, Changes: []FieldChange{{Field: "version", From: "1.0.0", To: "1.1.0"}}},
+		{Path: "db migrate", Kind: DiffKindCommand, Changes: []FieldChange{{Field: "use", From: "migrate [version]", To: "migrate <version>"}}},
+		{Path: "serve", Kind: DiffKindCommand, Changes: []FieldChange{{Field: "aliases", To: "s"}}},
+		{Path: "serve --port", Kind: DiffKindFlag, Changes: []FieldChange{
+			{Field: "type", From: "int", To: "string"},
+			{Field: "default", From: "8080"},
+		}},
+	}
+	if !reflect.DeepEqual(result.Added, wantAdded) {
+		t.Errorf("Added = %+v, want %+v", result.Added, wantAdded)
+	}
+	if !reflect.DeepEqual(result.Removed, wantRemoved) {
+		t.Errorf("Removed = %+v, want %+v", result.Removed, wantRemoved)
+	}
+	if !reflect.DeepEqual(result.Modified, wantModified) {
+		t.Errorf("Modified = %+v, want %+v", result.Modified, wantModified)
+	}
+}
+
+func TestDiffService_Diff_Identical(t *testing.T) {
+	from, _ := diffTestContracts()
+	same, _ := diffTestContracts()
+	// Flag groups and flags in another order are the same contract
+	from.Commands[1].MutuallyExclusive = [][]string{{"port", "format"}}
+	same.Commands[1].MutuallyExclusive = [][]string{{"format", "port"}}
+	same.Commands[1].Flags[0], same.Commands[1].Flags[1] = same.Commands[1].Flags[1], same.Commands[1].Flags[0]
+
+	svc := newTestDiffService(map[string]*contract.Contract{"a.yaml": from, "b.yaml": same})
+	result, err := svc.Diff(DiffOptions{From: "a.yaml", To: "b.yaml"})
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	if !result.Identical() {
+		t.Errorf("Diff() = %+v, want no differences", result)
+	}
+}
+
+func TestDiffService_Diff_LoadError(t *testing.T) {
+	from, _ := diffTestContracts()
+	svc := newTestDiffService(map[string]*contract.Contract{"main.yaml": from})
+
+	_, err := svc.Diff(DiffOptions{From: "main.yaml", To: "missing.yaml"})
+	if err == nil || !strings.Contains(err.Error(), "failed to load contract missing.yaml") {
+		t.Errorf("Diff() error = %v, want the missing contract reported", err)
+	}
+}
+
+func TestDiffService_Diff_Files(t *testing.T) {
+	dir := t.TempDir()
+	fromPath := filepath.Join(dir, "main.yaml")
+	toPath := filepath.Join(dir, "branch.yaml")
+	if err := os.WriteFile(fromPath, []byte("use: app\nshort: An app\nflags:\n  - name: verbose\n    usage: Verbose output\n    type: bool\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(toPath, []byte("use: app\nshort: An app\ncommands:\n  - use: serve\n    short: Serve the app\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := NewDiffService().Diff(DiffOptions{From: fromPath, To: toPath})
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	if len(result.Added) != 1 || len(result.Removed) != 1 || len(result.Modified) != 0 {
+		t.Errorf("Diff() = %+v, want serve added and --verbose removed", result)
+	}
+}
+
+func TestDiffResult_WriteDiff(t *testing.T) {
+	from, to := diffTestContracts()
+	svc := newTestDiffService(map[string]*contract.Contract{"main.yaml": from, "branch.yaml": to})
+	result, err := svc.Diff(DiffOptions{From: "main.yaml", To: "branch.yaml"})
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	result.WriteDiff(&buf)
+
+	want := `--- main.yaml
++++ branch.yaml
+ command app
+-    version: 1.0.0
++    version: 1.1.0
+ command db migrate
+-    use: migrate [version]
++    use: migrate <version>
+-command db seed
+ command serve
++    aliases: s
+-flag serve --format
++flag serve --listen
+ flag serve --port
+-    type: int
++    type: string
+-    default: 8080
++command version
+
+2 added, 2 removed, 4 modified
+`
+	if got := buf.String(); got != want {
+		t.Errorf("WriteDiff() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestDiffResult_WriteDiff_Identical(t *testing.T) {
+	var buf bytes.Buffer
+	(&DiffResult{From: "a.yaml", To: "b.yaml"}).WriteDiff(&buf)
+	if got := buf.String(); got != "No differences found.\n" {
+		t.Errorf("WriteDiff() = %q", got)
+	}
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"github.com/hiAndrewQuinn/cliguard/internal/contract"
+	"github.com/hiAndrewQuinn/cliguard/internal/inspector"
+)
+
+// applyEnvBindings sets the Env of each flag of the CLI that has a binding,
+// keyed by flag name (see discovery.FindEnvBindings)
+func applyEnvBindings(cli *inspector.InspectedCLI, bindings map[string]string) {
+	applyFlagEnvBindings(cli.Flags, bindings)
+	applyCommandEnvBindings(cli.Commands, bindings)
+}
+
+// applyCommandEnvBindings is applyEnvBindings for subcommands, recursively
+func applyCommandEnvBindings(commands []inspector.InspectedCommand, bindings map[string]string) {
+	for i := range commands {
+		applyFlagEnvBindings(commands[i].Flags, bindings)
+		applyCommandEnvBindings(commands[i].Commands, bindings)
+	}
+}
+
+// applyFlagEnvBindings sets the Env of the flags that have a binding
+func applyFlagEnvBindings(flags []inspector.InspectedFlag, bindings map[string]string) {
+	for i := range flags {
+		if env, ok := bindings[flags[i].Name]; ok {
+			flags[i].Env = env
+		}
+	}
+}
+
+// contractHasEnv reports whether any flag in the contract names an
+// environment variable, so that the project source must be searched for
+// the bindings
+func contractHasEnv(c *contract.Contract) bool {
+	return flagsHaveEnv(c.Flags) || commandsHaveEnv(c.Commands)
+}
+
+// commandsHaveEnv reports whether any flag of the commands, or of their
+// subcommands, names an environment variable
+func commandsHaveEnv(commands []contract.Command) bool {
+	for _, cmd := range commands {
+		if flagsHaveEnv(cmd.Flags) || commandsHaveEnv(cmd.Commands) {
+			return true
+		}
+	}
+	return false
+}
+
+// flagsHaveEnv reports whether any of the flags names an environment variable
+func flagsHaveEnv(flags []contract.Flag) bool {
+	for _, flag := range flags {
+		if flag.Env != "" {
+			return true
+		}
+	}
+	return false
+}
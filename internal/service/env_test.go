@@ -0,0 +1,95 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/contract"
+	"github.com/hiAndrewQuinn/cliguard/internal/inspector"
+)
+
+func TestApplyEnvBindings(t *testing.T) {
+	cli := &inspector.InspectedCLI{
+		Use:   "myapp",
+		Flags: []inspector.InspectedFlag{{Name: "config"}},
+		Commands: []inspector.InspectedCommand{
+			{Use: "serve", Flags: []inspector.InspectedFlag{{Name: "token"}, {Name: "port"}}, Commands: []inspector.InspectedCommand{
+				{Use: "http", Flags: []inspector.InspectedFlag{{Name: "token"}}},
+			}},
+		},
+	}
+
+	applyEnvBindings(cli, map[string]string{"config": "APP_CONFIG", "token": "API_TOKEN"})
+
+	if cli.Flags[0].Env != "APP_CONFIG" {
+		t.Errorf("--config env = %q, want APP_CONFIG", cli.Flags[0].Env)
+	}
+	serve := cli.Commands[0]
+	if serve.Flags[0].Env != "API_TOKEN" || serve.Flags[1].Env != "" {
+		t.Errorf("serve flags = %+v, want only --token bound", serve.Flags)
+	}
+	if serve.Commands[0].Flags[0].Env != "API_TOKEN" {
+		t.Errorf("serve http --token env = %q, want API_TOKEN", serve.Commands[0].Flags[0].Env)
+	}
+}
+
+func TestContractHasEnv(t *testing.T) {
+	c := &contract.Contract{Use: "myapp", Commands: []contract.Command{
+		{Use: "serve", Commands: []contract.Command{{Use: "http", Flags: []contract.Flag{{Name: "port"}}}}},
+	}}
+	if contractHasEnv(c) {
+		t.Error("contractHasEnv() = true, want false without env fields")
+	}
+
+	c.Commands[0].Commands[0].Flags[0].Env = "PORT"
+	if !contractHasEnv(c) {
+		t.Error("contractHasEnv() = false, want true for a nested env field")
+	}
+}
+
+func TestValidateService_Validate_Env(t *testing.T) {
+	projectDir := t.TempDir()
+	source := `package cmd
+
+import "github.com/spf13/viper"
+
+func init() {
+	viper.BindEnv("token", "API_TOKEN")
+}
+`
+	if err := os.WriteFile(filepath.Join(projectDir, "root.go"), []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+	contractPath := filepath.Join(projectDir, "cliguard.yaml")
+	if err := os.WriteFile(contractPath, []byte("use: myapp\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	svc := &ValidateService{
+		ContractLoader: func(string) (*contract.Contract, error) {
+			return &contract.Contract{Use: "myapp", Flags: []contract.Flag{
+				{Name: "token", Type: "string", Env: "API_TOKEN"},
+				{Name: "region", Type: "string", Env: "REGION"},
+			}}, nil
+		},
+		Inspector: func(string, string) (*inspector.InspectedCLI, error) {
+			return &inspector.InspectedCLI{Use: "myapp", Flags: []inspector.InspectedFlag{
+				{Name: "token", Type: "string"},
+				{Name: "region", Type: "string"},
+			}}, nil
+		},
+	}
+
+	result, err := svc.Validate(ValidateOptions{ProjectPath: projectDir, Entrypoint: "cmd.NewRootCmd"})
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if !result.Success {
+		t.Errorf("Validate() errors = %+v, want none", result.Result.Errors)
+	}
+	warnings := result.Result.Warnings
+	if len(warnings) != 1 || warnings[0].Path != "--region" || warnings[0].Expected != "REGION" {
+		t.Errorf("Validate() warnings = %+v, want one for --region", warnings)
+	}
+}
@@ -1,11 +1,24 @@
 package service
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"time"
+	"unicode/utf8"
 
+	"github.com/hiAndrewQuinn/cliguard/internal/adapters"
 	"github.com/hiAndrewQuinn/cliguard/internal/contract"
+	"github.com/hiAndrewQuinn/cliguard/internal/discovery"
+	"github.com/hiAndrewQuinn/cliguard/internal/executor"
+	"github.com/hiAndrewQuinn/cliguard/internal/formatter"
 	"github.com/hiAndrewQuinn/cliguard/internal/inspector"
+	"github.com/hiAndrewQuinn/cliguard/internal/output"
+	"github.com/hiAndrewQuinn/cliguard/internal/version"
 	"gopkg.in/yaml.v3"
 )
 
@@ -14,58 +27,719 @@ type GenerateOptions struct {
 	ProjectPath string
 	Entrypoint  string
 	Timeout     time.Duration
+
+	// IncludeHiddenCommands includes commands marked Hidden in the generated
+	// contract. By default hidden commands are omitted.
+	IncludeHiddenCommands bool
+
+	// IncludeHiddenFlags includes the flags hidden with MarkHidden in the
+	// generated contract, marked hidden (see inspector.Config.HiddenFlags).
+	// By default they are omitted. Only supported when inspecting project
+	// source by running it.
+	IncludeHiddenFlags bool
+
+	// OmitDeprecatedFlags leaves the flags marked with MarkDeprecated out
+	// of the generated contract. By default they are included with their
+	// deprecation messages.
+	OmitDeprecatedFlags bool
+
+	// WithExamples populates command examples from CLI invocations found in
+	// the project's test files. Note that examples are validated, so the
+	// resulting contract only passes if the CLI defines matching examples.
+	WithExamples bool
+
+	// WithValidation runs each flag's completion function and records the
+	// values it offers as the flag's enum (see inspector.Config.CompletionValues).
+	// Only supported when inspecting project source.
+	WithValidation bool
+
+	// ExpandPersistentFlags lists the persistent flags of each command on
+	// all of its subcommands too, so that every command's flags are the
+	// complete set it accepts. Validate expanded contracts with
+	// ValidateOptions.ExpandedContract.
+	ExpandPersistentFlags bool
+
+	// CobraVersion overrides the Cobra version the inspector targets
+	// (e.g. "v1.2.0"). If empty, it is detected from the project's go.mod.
+	CobraVersion string
+
+	// ContractVersion selects the output contract format: 1 (default) for a
+	// single root command, or 2 for the multi-root format (see contract.ContractV2).
+	ContractVersion int
+
+	// OutputFormat is the format of the generated contract:
+	// output.FormatText (default) or output.FormatYAML for the YAML contract
+	// after its header comment, or output.FormatJSON for the contract as
+	// JSON, without a header. Contract files are loaded as YAML, which
+	// includes JSON, so both can be validated.
+	OutputFormat string
+
+	// OutputEncoding selects how the contract text is encoded: EncodingUTF8
+	// (default), EncodingASCII or EncodingUTF8BOM.
+	OutputEncoding string
+
+	// FromBinary is the path to a compiled CLI to inspect through its --help
+	// output instead of the project source (see inspector.BinaryInspector).
+	// Help output is less complete than source inspection, so the generated
+	// contract should be reviewed.
+	FromBinary string
+
+	// FromOpenAPI is the path to an OpenAPI 3.0 spec (YAML or JSON) of an
+	// API whose CLI was generated from it, e.g. by openapi-generator. The
+	// contract is mapped from the spec (see adapters.OpenAPIToContract)
+	// instead of inspecting a project.
+	FromOpenAPI string
+
+	// Static reads the CLI structure from the project source without
+	// building or running it (see inspector.StaticInspector), for projects
+	// that can't be run where the contract is generated. Flag defaults,
+	// completions and flag groups are not recorded, and only constant
+	// values are found, so the generated contract should be reviewed.
+	Static bool
+
+	// ToolName is the root command name of the CLI generated from FromOpenAPI.
+	// Defaults to the kebab-cased title of the spec.
+	ToolName string
+
+	// StripDefaults removes the flags and commands Cobra adds to every CLI
+	// (see contract.DefaultStripRules).
+	StripDefaults bool
+
+	// StripRules are additional "action:pattern" rules removing matching
+	// flags and commands (see contract.ParseStripRule).
+	StripRules []string
+
+	// StripHelpCommand removes the help command Cobra adds, at every level
+	// of the command tree (see contract.HelpCommandRule)
+	StripHelpCommand bool
+
+	// StripCompletionCommand removes the completion command Cobra adds and
+	// its subcommands, at every level of the command tree (see
+	// contract.CompletionCommandRule)
+	StripCompletionCommand bool
+
+	// OmitUnchanged makes GenerateToFile leave an existing contract alone if
+	// it has the same content as the new one, even if it is formatted or
+	// commented differently (see contract.NormalizedHash).
+	OmitUnchanged bool
+
+	// UseNameOnly writes only the command name in each command's Use, without
+	// the argument pattern, e.g. "clone" for "clone [flags] <repo>" (see
+	// contract.UseNamesOnly). Validate the contract with
+	// ValidateOptions.UseNameOnly.
+	UseNameOnly bool
+
+	// RunnableOnly leaves out the commands that run nothing: those without
+	// a Run, RunE, PreRun, PreRunE, PostRun or PostRunE function and
+	// without runnable subcommands. Commands that only group runnable
+	// subcommands are kept for the structure. Every command's
+	// contract.Command.Runnable is set, which makes validation skip the
+	// commands left out. Only supported when inspecting project source.
+	RunnableOnly bool
+
+	// ExtractGodoc fills in the Long description of commands that have none
+	// from the doc comment of the function or package-level variable that
+	// builds them in the project source (see inspector.ExtractGodoc). Only
+	// supported when inspecting project source.
+	ExtractGodoc bool
+
+	// WriteSnapshot is a path to write the inspected CLI to as JSON (see
+	// inspector.WriteSnapshot), so that it can be validated later with
+	// ValidateOptions.CLISnapshot without building the project (optional).
+	// The snapshot is the CLI as inspected, before hidden or non-runnable
+	// commands are left out.
+	WriteSnapshot string
+
+	// HeaderComment is a text/template rendered with formatter.HeaderData
+	// and written as the comment at the top of the contract, instead of
+	// formatter.DefaultHeaderTemplate.
+	HeaderComment string
+
+	// NoHeader leaves the header comment out of the contract
+	NoHeader bool
+
+	// GroupBySubpackage makes GenerateToFile write the commands each Go
+	// package of the project defines to a separate fragment file next to
+	// the contract, e.g. db-contract.yaml for the commands in cmd/db, which
+	// the contract includes (see contract.Include). Packages are found with
+	// discovery.FindCommandPackages. Only supported for v1 contracts
+	// generated from project source.
+	GroupBySubpackage bool
+
+	// Executor runs the commands inspection needs, e.g. an
+	// executor.DryRunExecutor to record them. Defaults to executor.OSExecutor.
+	Executor executor.CommandExecutor
 }
 
+// Output encodings supported by GenerateOptions.OutputEncoding
+const (
+	// EncodingUTF8 writes plain UTF-8
+	EncodingUTF8 = "utf8"
+
+	// EncodingASCII writes only ASCII; non-ASCII characters are written as
+	// YAML \uXXXX escapes inside double-quoted strings
+	EncodingASCII = "ascii"
+
+	// EncodingUTF8BOM writes UTF-8 prefixed with a byte order mark
+	EncodingUTF8BOM = "utf8bom"
+)
+
+// utf8BOM is the UTF-8 encoded byte order mark
+const utf8BOM = "\ufeff"
+
 // GenerateService handles the generation of contract files
-type GenerateService struct{}
+type GenerateService struct {
+	// InspectorTimeout is the timeout for inspections when
+	// GenerateOptions.Timeout is zero. If it is zero too, inspections have
+	// no timeout.
+	InspectorTimeout time.Duration
+
+	// Now returns the time recorded in the header. Defaults to time.Now.
+	Now func() time.Time
+}
 
 // NewGenerateService creates a new GenerateService
 func NewGenerateService() *GenerateService {
-	return &GenerateService{}
+	return NewGenerateServiceWithOptions(ServiceOptions{})
+}
+
+// NewGenerateServiceWithOptions creates a new GenerateService configured by opts
+func NewGenerateServiceWithOptions(opts ServiceOptions) *GenerateService {
+	opts = opts.withDefaults()
+	return &GenerateService{InspectorTimeout: opts.InspectorTimeout, Now: time.Now}
 }
 
 // Generate inspects a CLI and generates a contract YAML string
 func (s *GenerateService) Generate(opts GenerateOptions) (string, error) {
-	// Inspect the project to get the CLI structure
+	if opts.GroupBySubpackage {
+		return "", fmt.Errorf("--group-by-subpackage requires --output-file")
+	}
+
+	header, contractSpec, err := s.generate(opts)
+	if err != nil {
+		return "", err
+	}
+
+	var document interface{} = contractSpec
+	if opts.ContractVersion == 2 {
+		document = contract.ConvertToV2(contractSpec)
+	}
+	if opts.OutputFormat == output.FormatJSON {
+		data, err := output.JSONFormatter{}.Format(document)
+		if err != nil {
+			return "", err
+		}
+		return encodeOutput(string(data), opts.OutputEncoding), nil
+	}
+	return render(header, document, opts.OutputEncoding)
+}
+
+// generate checks opts, then renders the header and builds the contract
+func (s *GenerateService) generate(opts GenerateOptions) (string, *contract.Contract, error) {
+	if opts.ContractVersion != 0 && opts.ContractVersion != 1 && opts.ContractVersion != 2 {
+		return "", nil, fmt.Errorf("unsupported contract version %d (supported: 1, 2)", opts.ContractVersion)
+	}
+	switch opts.OutputEncoding {
+	case "", EncodingUTF8, EncodingASCII, EncodingUTF8BOM:
+	default:
+		return "", nil, fmt.Errorf("unsupported output encoding '%s' (supported: %s, %s, %s)",
+			opts.OutputEncoding, EncodingUTF8, EncodingASCII, EncodingUTF8BOM)
+	}
+
+	switch opts.OutputFormat {
+	case "", output.FormatText, output.FormatYAML:
+	case output.FormatJSON:
+		if opts.OutputEncoding == EncodingASCII {
+			return "", nil, fmt.Errorf("--output-encoding %s is not supported with --output %s", EncodingASCII, output.FormatJSON)
+		}
+		if opts.GroupBySubpackage {
+			return "", nil, fmt.Errorf("--group-by-subpackage writes YAML fragments; it cannot be used with --output %s", output.FormatJSON)
+		}
+	default:
+		return "", nil, fmt.Errorf("unsupported output format '%s' (supported: %s, %s, %s)",
+			opts.OutputFormat, output.FormatText, output.FormatJSON, output.FormatYAML)
+	}
+
+	if opts.FromBinary != "" && opts.FromOpenAPI != "" {
+		return "", nil, fmt.Errorf("--from-binary and --from-openapi cannot be used together")
+	}
+	if opts.Static && (opts.FromBinary != "" || opts.FromOpenAPI != "") {
+		return "", nil, fmt.Errorf("--static inspects the project source; it cannot be used with --from-binary or --from-openapi")
+	}
+	if opts.IncludeHiddenFlags && (opts.FromBinary != "" || opts.FromOpenAPI != "") {
+		return "", nil, fmt.Errorf("--include-hidden requires inspecting the project source; it cannot be used with --from-binary or --from-openapi")
+	}
+	if opts.IncludeHiddenFlags && opts.Static {
+		return "", nil, fmt.Errorf("--include-hidden cannot be used with --static")
+	}
+	if opts.WithValidation && opts.Static {
+		return "", nil, fmt.Errorf("--with-validation runs the project's completion functions; it cannot be used with --static")
+	}
+	if opts.WithValidation && (opts.FromBinary != "" || opts.FromOpenAPI != "") {
+		return "", nil, fmt.Errorf("--with-validation requires inspecting the project source; it cannot be used with --from-binary or --from-openapi")
+	}
+	if opts.RunnableOnly && (opts.FromBinary != "" || opts.FromOpenAPI != "") {
+		return "", nil, fmt.Errorf("--runnable-only requires inspecting the project source; it cannot be used with --from-binary or --from-openapi")
+	}
+	if opts.ExtractGodoc && (opts.FromBinary != "" || opts.FromOpenAPI != "") {
+		return "", nil, fmt.Errorf("--extract-godoc requires inspecting the project source; it cannot be used with --from-binary or --from-openapi")
+	}
+	if opts.WriteSnapshot != "" && opts.FromOpenAPI != "" {
+		return "", nil, fmt.Errorf("--write-snapshot cannot be used with --from-openapi")
+	}
+	if opts.NoHeader && opts.HeaderComment != "" {
+		return "", nil, fmt.Errorf("--header-comment and --no-header cannot be used together")
+	}
+
+	// Render the header first, so that an invalid template fails before
+	// the project is inspected
+	header, err := s.header(opts)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var stripRules []contract.StripRule
+	if opts.StripDefaults {
+		stripRules = contract.DefaultStripRules()
+	}
+	for _, rule := range opts.StripRules {
+		parsed, err := contract.ParseStripRule(rule)
+		if err != nil {
+			return "", nil, err
+		}
+		stripRules = append(stripRules, parsed)
+	}
+	if opts.StripHelpCommand {
+		stripRules = append(stripRules, contract.HelpCommandRule())
+	}
+	if opts.StripCompletionCommand {
+		stripRules = append(stripRules, contract.CompletionCommandRule())
+	}
+
+	var contractSpec *contract.Contract
+	if opts.FromOpenAPI != "" {
+		data, err := os.ReadFile(opts.FromOpenAPI)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to read OpenAPI spec: %w", err)
+		}
+		contractSpec, err = adapters.OpenAPIToContract(data, opts.ToolName)
+		if err != nil {
+			return "", nil, err
+		}
+	} else {
+		var err error
+		contractSpec, err = s.inspectContract(opts)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
+	if opts.WithExamples {
+		examples, err := discovery.ExtractExamplesFromTests(opts.ProjectPath)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to extract examples from tests: %w", err)
+		}
+		applyExamples(contractSpec, examples)
+	}
+
+	if opts.ExtractGodoc {
+		docs, err := inspector.ExtractGodoc(opts.ProjectPath)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to extract doc comments: %w", err)
+		}
+		applyGodoc(contractSpec, docs)
+	}
+
+	if len(stripRules) > 0 {
+		contractSpec = contract.StripDefaults(contractSpec, stripRules)
+	}
+	if opts.UseNameOnly {
+		contractSpec = contract.UseNamesOnly(contractSpec)
+	}
+
+	return header, contractSpec, nil
+}
+
+// render marshals a contract or fragment to YAML after the header, in the
+// given output encoding
+func render(header string, v interface{}, encoding string) (string, error) {
+	yamlData, err := marshalContract(v, encoding)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal contract to YAML: %w", err)
+	}
+	return encodeOutput(header+string(yamlData), encoding), nil
+}
+
+// header renders the comment written at the top of the contract
+func (s *GenerateService) header(opts GenerateOptions) (string, error) {
+	if opts.NoHeader {
+		return "", nil
+	}
+	text := opts.HeaderComment
+	if text == "" {
+		text = formatter.DefaultHeaderTemplate
+	}
+
+	now := s.Now
+	if now == nil {
+		now = time.Now
+	}
+	data := formatter.HeaderData{
+		Version:    version.Version,
+		Entrypoint: opts.Entrypoint,
+		Timestamp:  now().UTC().Format(time.RFC3339),
+	}
+	if opts.FromBinary == "" && opts.FromOpenAPI == "" {
+		data.Project = moduleName(opts.ProjectPath)
+	}
+	return formatter.RenderHeader(text, data)
+}
+
+// moduleName returns the module path in the go.mod of the project, or ""
+// if it can't be read
+func moduleName(projectPath string) string {
+	data, err := os.ReadFile(filepath.Join(projectPath, "go.mod"))
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if fields := strings.Fields(line); len(fields) >= 2 && fields[0] == "module" {
+			return strings.Trim(fields[1], `"`)
+		}
+	}
+	return ""
+}
+
+// inspectContract inspects the project (or binary) in opts and converts the
+// result to a contract
+func (s *GenerateService) inspectContract(opts GenerateOptions) (*contract.Contract, error) {
+	opts.Timeout = inspectionTimeout(opts.Timeout, s.InspectorTimeout)
+
+	config := inspector.Config{
+		ProjectPath: opts.ProjectPath,
+		Entrypoint:  opts.Entrypoint,
+		Timeout:     opts.Timeout,
+		Executor:    opts.Executor,
+
+		CompletionValues: opts.WithValidation,
+		HiddenFlags:      opts.IncludeHiddenFlags,
+	}
+
+	if opts.CobraVersion != "" {
+		version, err := inspector.ParseVersion(opts.CobraVersion)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Cobra version: %w", err)
+		}
+		config.CobraVersion = version
+	}
+
+	// Inspect the project (or binary) to get the CLI structure
 	var inspectedCLI *inspector.InspectedCLI
 	var err error
-	
-	if opts.Timeout > 0 {
-		inspectedCLI, err = inspector.InspectProjectWithTimeout(opts.ProjectPath, opts.Entrypoint, opts.Timeout)
+	if opts.FromBinary != "" {
+		inspectedCLI, err = inspector.NewBinaryInspector(inspector.BinaryConfig{
+			BinaryPath: opts.FromBinary,
+			Timeout:    opts.Timeout,
+			Executor:   opts.Executor,
+		}).Inspect()
+		if err != nil {
+			return nil, fmt.Errorf("failed to inspect binary: %w", err)
+		}
 	} else {
-		inspectedCLI, err = inspector.InspectProject(opts.ProjectPath, opts.Entrypoint)
+		if opts.Static {
+			inspectedCLI, err = inspector.NewStaticInspector(inspector.StaticConfig{
+				ProjectPath: opts.ProjectPath,
+				Entrypoint:  opts.Entrypoint,
+				Timeout:     opts.Timeout,
+				Executor:    opts.Executor,
+			}).Inspect()
+		} else {
+			inspectedCLI, err = inspector.NewInspector(config).Inspect()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to inspect project: %w", err)
+		}
+		bindings, err := discovery.FindEnvBindings(opts.ProjectPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find environment variable bindings: %w", err)
+		}
+		applyEnvBindings(inspectedCLI, bindings)
 	}
-	
-	if err != nil {
-		return "", fmt.Errorf("failed to inspect project: %w", err)
+
+	if opts.WriteSnapshot != "" {
+		if err := inspector.WriteSnapshot(opts.WriteSnapshot, inspectedCLI); err != nil {
+			return nil, err
+		}
+	}
+
+	if !opts.IncludeHiddenCommands {
+		inspectedCLI.Commands = filterHiddenCommands(inspectedCLI.Commands)
+	}
+	if opts.RunnableOnly {
+		inspectedCLI.Commands = filterNonRunnableCommands(inspectedCLI.Commands)
+	}
+	if opts.OmitDeprecatedFlags {
+		inspectedCLI.Flags = filterDeprecatedFlags(inspectedCLI.Flags)
+		filterCommandDeprecatedFlags(inspectedCLI.Commands)
 	}
 
 	// Convert inspected CLI to contract
-	contract := s.inspectedToContract(inspectedCLI)
+	contractSpec := s.inspectedToContract(inspectedCLI, opts.ExpandPersistentFlags)
+	if opts.RunnableOnly {
+		markRunnable(contractSpec.Commands, inspectedCLI.Commands)
+	}
+	return contractSpec, nil
+}
 
-	// Marshal contract to YAML
-	yamlData, err := yaml.Marshal(contract)
+// GenerateToFile generates a contract and writes it to outputPath. The file is
+// written atomically (to a temporary file that is then renamed), so a failed
+// generation never leaves a partially written contract behind. An existing
+// file keeps its permissions, and is left untouched if its text would not
+// change, or with opts.OmitUnchanged, if its content would not change. It
+// reports whether the file was written.
+//
+// With opts.GroupBySubpackage, the fragments the contract includes are
+// written to its directory in the same way, and it reports whether any file
+// was written.
+func (s *GenerateService) GenerateToFile(opts GenerateOptions, outputPath string) (bool, error) {
+	files, err := s.generateFiles(opts, outputPath)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal contract to YAML: %w", err)
+		return false, err
+	}
+
+	anyWritten := false
+	for _, file := range files {
+		if opts.OmitUnchanged && contractUnchanged(file.path, []byte(file.content)) {
+			continue
+		}
+		written, err := writeFileAtomic(file.path, []byte(file.content))
+		if err != nil {
+			return anyWritten, fmt.Errorf("failed to write contract to '%s': %w", file.path, err)
+		}
+		anyWritten = anyWritten || written
+	}
+	return anyWritten, nil
+}
+
+// generatedFile is a contract or fragment file to write
+type generatedFile struct {
+	path    string
+	content string
+}
+
+// generateFiles generates the contract to write to outputPath and, with
+// opts.GroupBySubpackage, the fragments it includes
+func (s *GenerateService) generateFiles(opts GenerateOptions, outputPath string) ([]generatedFile, error) {
+	if !opts.GroupBySubpackage {
+		content, err := s.Generate(opts)
+		if err != nil {
+			return nil, err
+		}
+		return []generatedFile{{path: outputPath, content: content}}, nil
+	}
+
+	if opts.ContractVersion == 2 {
+		return nil, fmt.Errorf("--group-by-subpackage only supports contract version 1")
+	}
+	if opts.FromBinary != "" || opts.FromOpenAPI != "" {
+		return nil, fmt.Errorf("--group-by-subpackage requires inspecting the project source; it cannot be used with --from-binary or --from-openapi")
+	}
+
+	header, contractSpec, err := s.generate(opts)
+	if err != nil {
+		return nil, err
+	}
+	packages, err := discovery.FindCommandPackages(opts.ProjectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find command packages: %w", err)
+	}
+	root, subcontracts := groupBySubpackage(contractSpec, packages)
+
+	content, err := render(header, root, opts.OutputEncoding)
+	if err != nil {
+		return nil, err
+	}
+	files := []generatedFile{{path: outputPath, content: content}}
+	for _, sub := range subcontracts {
+		content, err := render(header, sub.fragment, opts.OutputEncoding)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, generatedFile{path: filepath.Join(filepath.Dir(outputPath), sub.file), content: content})
+	}
+	return files, nil
+}
+
+// contractUnchanged reports whether the contract at path has the same
+// normalized hash as content. A missing or unparseable file counts as
+// changed, so that it is replaced.
+func contractUnchanged(path string, content []byte) bool {
+	existing, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	oldHash, err := normalizedContentHash(existing)
+	if err != nil {
+		return false
 	}
+	newHash, err := normalizedContentHash(content)
+	return err == nil && oldHash == newHash
+}
 
-	// Add comment header
-	header := `# Cliguard contract file
-# To use this contract, pipe this output to a file:
-#   cliguard generate --project-path . > cliguard.yaml
-#
-`
-	return header + string(yamlData), nil
+// normalizedContentHash parses a v1 or v2 contract document and returns its
+// normalized hash
+func normalizedContentHash(data []byte) (string, error) {
+	data = bytes.TrimPrefix(data, []byte(utf8BOM))
+	if contract.DetectVersion(data) == 2 {
+		var c contract.ContractV2
+		if err := yaml.Unmarshal(data, &c); err != nil {
+			return "", err
+		}
+		return contract.NormalizedHashV2(&c)
+	}
+	var c contract.Contract
+	if err := yaml.Unmarshal(data, &c); err != nil {
+		return "", err
+	}
+	return contract.NormalizedHash(&c)
 }
 
-// inspectedToContract converts an InspectedCLI to a Contract
-func (s *GenerateService) inspectedToContract(inspected *inspector.InspectedCLI) *contract.Contract {
+// writeFileAtomic replaces the file at path with data via a temporary file in
+// the same directory. It reports whether the file was written; a file whose
+// checksum already matches data is not rewritten, preserving its mtime.
+func writeFileAtomic(path string, data []byte) (bool, error) {
+	// Write through symlinks rather than replacing them
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+
+	perm := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		existing, err := os.ReadFile(path)
+		if err != nil {
+			return false, err
+		}
+		if sha256.Sum256(existing) == sha256.Sum256(data) {
+			return false, nil
+		}
+		perm = info.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return false, err
+	}
+	tmpPath := tmp.Name()
+	defer func() {
+		// No-op once the temporary file has been renamed
+		_ = os.Remove(tmpPath)
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return false, err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return false, err
+	}
+	if err := tmp.Close(); err != nil {
+		return false, err
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		return false, err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// marshalContract marshals a contract to YAML. For EncodingASCII, scalars
+// containing non-ASCII characters are double-quoted, since YAML only
+// interprets escape sequences inside double-quoted strings.
+func marshalContract(v interface{}, encoding string) ([]byte, error) {
+	var node yaml.Node
+	if err := node.Encode(v); err != nil {
+		return nil, err
+	}
+	if encoding == EncodingASCII {
+		quoteNonASCIIScalars(&node)
+	}
+	return yaml.Marshal(&node)
+}
+
+// quoteNonASCIIScalars switches every scalar containing non-ASCII characters
+// to double-quoted style so that encodeOutput can escape them
+func quoteNonASCIIScalars(node *yaml.Node) {
+	if node.Kind == yaml.ScalarNode && !isASCII(node.Value) {
+		node.Style = yaml.DoubleQuotedStyle
+	}
+	for _, child := range node.Content {
+		quoteNonASCIIScalars(child)
+	}
+}
+
+// encodeOutput applies the output encoding to the generated contract text
+func encodeOutput(text, encoding string) string {
+	switch encoding {
+	case EncodingASCII:
+		return escapeNonASCII(text)
+	case EncodingUTF8BOM:
+		return utf8BOM + text
+	default:
+		return text
+	}
+}
+
+// escapeNonASCII replaces non-ASCII characters with YAML escape sequences,
+// e.g. "é" becomes \u00E9 and "🚀" becomes \U0001F680
+func escapeNonASCII(text string) string {
+	if isASCII(text) {
+		return text
+	}
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r < utf8.RuneSelf:
+			b.WriteRune(r)
+		case r <= 0xFFFF:
+			fmt.Fprintf(&b, "\\u%04X", r)
+		default:
+			fmt.Fprintf(&b, "\\U%08X", r)
+		}
+	}
+	return b.String()
+}
+
+// isASCII reports whether s contains only ASCII characters
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// inspectedToContract converts an InspectedCLI to a Contract. If expand is
+// set, persistent flags are also listed on every subcommand that inherits
+// them.
+func (s *GenerateService) inspectedToContract(inspected *inspector.InspectedCLI, expand bool) *contract.Contract {
+	flags := s.inspectedFlagsToContractFlags(inspected.Flags)
+	var inherited []contract.Flag
+	if expand {
+		inherited = persistentFlags(nil, flags)
+	}
 	return &contract.Contract{
-		Use:      inspected.Use,
-		Short:    inspected.Short,
-		Long:     inspected.Long,
-		Flags:    s.inspectedFlagsToContractFlags(inspected.Flags),
-		Commands: s.inspectedCommandsToContractCommands(inspected.Commands),
+		Use:               inspected.Use,
+		Short:             inspected.Short,
+		Long:              inspected.Long,
+		Aliases:           inspected.Aliases,
+		Flags:             flags,
+		Commands:          s.inspectedCommandsToContractCommands(inspected.Commands, inherited, expand),
+		MutuallyExclusive: inspected.MutuallyExclusive,
+		RequiredTogether:  inspected.RequiredTogether,
 	}
 }
 
@@ -79,27 +753,203 @@ func (s *GenerateService) inspectedFlagsToContractFlags(flags []inspector.Inspec
 			Usage:      f.Usage,
 			Type:       f.Type,
 			Persistent: f.Persistent,
+			Completion: f.Completion,
+			Default:    f.Default,
+			Required:   f.Required,
+			Enum:       f.Enum,
+			Deprecated: f.Deprecated,
+			Hidden:     f.Hidden,
+			Env:        f.Env,
 		})
 	}
 	return contractFlags
 }
 
-// inspectedCommandsToContractCommands converts InspectedCommand slice to Command slice
-func (s *GenerateService) inspectedCommandsToContractCommands(commands []inspector.InspectedCommand) []contract.Command {
+// inspectedCommandsToContractCommands converts InspectedCommand slice to
+// Command slice. If expand is set, the inherited persistent flags of the
+// parent are added to each command, after its own flags; a command's own
+// flag shadows an inherited flag with the same name, as in Cobra.
+func (s *GenerateService) inspectedCommandsToContractCommands(commands []inspector.InspectedCommand, inherited []contract.Flag, expand bool) []contract.Command {
 	var contractCommands []contract.Command
 	for _, cmd := range commands {
-		contractCommands = append(contractCommands, s.inspectedCommandToContractCommand(cmd))
+		contractCommands = append(contractCommands, s.inspectedCommandToContractCommand(cmd, inherited, expand))
 	}
 	return contractCommands
 }
 
 // inspectedCommandToContractCommand converts a single InspectedCommand to Command
-func (s *GenerateService) inspectedCommandToContractCommand(cmd inspector.InspectedCommand) contract.Command {
+func (s *GenerateService) inspectedCommandToContractCommand(cmd inspector.InspectedCommand, inherited []contract.Flag, expand bool) contract.Command {
+	flags := s.inspectedFlagsToContractFlags(cmd.Flags)
+	var childInherited []contract.Flag
+	if expand {
+		childInherited = persistentFlags(inherited, flags)
+		flags = appendInheritedFlags(flags, inherited)
+	}
 	return contract.Command{
-		Use:      cmd.Use,
-		Short:    cmd.Short,
-		Long:     cmd.Long,
-		Flags:    s.inspectedFlagsToContractFlags(cmd.Flags),
-		Commands: s.inspectedCommandsToContractCommands(cmd.Commands),
+		Use:               cmd.Use,
+		Short:             cmd.Short,
+		Long:              cmd.Long,
+		Aliases:           cmd.Aliases,
+		Flags:             flags,
+		Hidden:            cmd.Hidden,
+		GroupID:           cmd.GroupID,
+		Commands:          s.inspectedCommandsToContractCommands(cmd.Commands, childInherited, expand),
+		MutuallyExclusive: cmd.MutuallyExclusive,
+		RequiredTogether:  cmd.RequiredTogether,
+	}
+}
+
+// persistentFlags returns the flags inherited by the subcommands of a
+// command: the command's persistent flags, followed by the flags it inherited
+// itself that none of its flags shadow
+func persistentFlags(inherited, flags []contract.Flag) []contract.Flag {
+	var result []contract.Flag
+	for _, flag := range flags {
+		if flag.Persistent {
+			result = append(result, flag)
+		}
+	}
+	return append(result, inheritedExcept(inherited, flags)...)
+}
+
+// appendInheritedFlags appends the inherited flags not shadowed by a flag in flags
+func appendInheritedFlags(flags, inherited []contract.Flag) []contract.Flag {
+	return append(flags, inheritedExcept(inherited, flags)...)
+}
+
+// inheritedExcept returns the inherited flags whose names are not in flags
+func inheritedExcept(inherited, flags []contract.Flag) []contract.Flag {
+	names := make(map[string]bool, len(flags))
+	for _, flag := range flags {
+		names[flag.Name] = true
+	}
+	var result []contract.Flag
+	for _, flag := range inherited {
+		if !names[flag.Name] {
+			result = append(result, flag)
+		}
+	}
+	return result
+}
+
+// filterHiddenCommands returns the commands with all hidden commands removed, recursively
+func filterHiddenCommands(commands []inspector.InspectedCommand) []inspector.InspectedCommand {
+	var visible []inspector.InspectedCommand
+	for _, cmd := range commands {
+		if cmd.Hidden {
+			continue
+		}
+		cmd.Commands = filterHiddenCommands(cmd.Commands)
+		visible = append(visible, cmd)
+	}
+	return visible
+}
+
+// filterDeprecatedFlags returns the flags that are not deprecated
+func filterDeprecatedFlags(flags []inspector.InspectedFlag) []inspector.InspectedFlag {
+	var kept []inspector.InspectedFlag
+	for _, flag := range flags {
+		if flag.Deprecated == "" {
+			kept = append(kept, flag)
+		}
+	}
+	return kept
+}
+
+// filterCommandDeprecatedFlags removes the deprecated flags of the commands
+// and their subcommands
+func filterCommandDeprecatedFlags(commands []inspector.InspectedCommand) {
+	for i := range commands {
+		commands[i].Flags = filterDeprecatedFlags(commands[i].Flags)
+		filterCommandDeprecatedFlags(commands[i].Commands)
+	}
+}
+
+// filterNonRunnableCommands returns the commands with those that run
+// nothing, neither themselves nor through a subcommand, removed recursively
+func filterNonRunnableCommands(commands []inspector.InspectedCommand) []inspector.InspectedCommand {
+	var runnable []inspector.InspectedCommand
+	for _, cmd := range commands {
+		cmd.Commands = filterNonRunnableCommands(cmd.Commands)
+		if !cmd.Runnable && len(cmd.Commands) == 0 {
+			continue
+		}
+		runnable = append(runnable, cmd)
+	}
+	return runnable
+}
+
+// markRunnable sets the Runnable field of the contract commands converted
+// from the inspected commands, recursively
+func markRunnable(commands []contract.Command, inspected []inspector.InspectedCommand) {
+	for i := range commands {
+		runnable := inspected[i].Runnable
+		commands[i].Runnable = &runnable
+		markRunnable(commands[i].Commands, inspected[i].Commands)
+	}
+}
+
+// applyGodoc sets the Long description of the contract's commands that
+// have none to the doc comment found for their name, keyed by command name
+func applyGodoc(c *contract.Contract, docs map[string]string) {
+	if c.Long == "" {
+		c.Long = docs[contract.CommandName(c.Use)]
+	}
+	applyGodocToCommands(c.Commands, docs)
+}
+
+// applyGodocToCommands applies docs to commands and their subcommands, see
+// applyGodoc
+func applyGodocToCommands(commands []contract.Command, docs map[string]string) {
+	for i := range commands {
+		if commands[i].Long == "" {
+			commands[i].Long = docs[contract.CommandName(commands[i].Use)]
+		}
+		applyGodocToCommands(commands[i].Commands, docs)
+	}
+}
+
+// applyExamples sets the Example field of the contract's commands from
+// examples keyed by command path. Each path is resolved against the command
+// tree, so trailing positional arguments attach the example to the deepest
+// matching command. Examples are prefixed with the root command name.
+func applyExamples(c *contract.Contract, examples map[string]string) {
+	// Iterate in a stable order so the generated contract is deterministic
+	paths := make([]string, 0, len(examples))
+	for path := range examples {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	rootName := contract.CommandName(c.Use)
+	for _, path := range paths {
+		var lines []string
+		for _, line := range strings.Split(examples[path], "\n") {
+			lines = append(lines, rootName+" "+line)
+		}
+		example := strings.Join(lines, "\n")
+
+		target := &c.Example
+		commands := c.Commands
+		for _, part := range strings.Fields(path) {
+			found := false
+			for i := range commands {
+				if contract.CommandName(commands[i].Use) == part {
+					target = &commands[i].Example
+					commands = commands[i].Commands
+					found = true
+					break
+				}
+			}
+			if !found {
+				break
+			}
+		}
+
+		if *target == "" {
+			*target = example
+		} else {
+			*target += "\n" + example
+		}
 	}
 }
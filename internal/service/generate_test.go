@@ -2,11 +2,19 @@ package service
 
 import (
 	"os"
+	"path/filepath"
 	"reflect"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/hiAndrewQuinn/cliguard/internal/contract"
+	"github.com/hiAndrewQuinn/cliguard/internal/executor"
 	"github.com/hiAndrewQuinn/cliguard/internal/inspector"
+	"github.com/hiAndrewQuinn/cliguard/internal/output"
+	"github.com/hiAndrewQuinn/cliguard/internal/validator"
+	"github.com/hiAndrewQuinn/cliguard/internal/version"
 	"gopkg.in/yaml.v3"
 )
 
@@ -120,7 +128,7 @@ func TestGenerateService_inspectedToContract(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := service.inspectedToContract(tt.inspected)
+			result := service.inspectedToContract(tt.inspected, false)
 			if !reflect.DeepEqual(result, tt.expected) {
 				t.Errorf("inspectedToContract() = %v, want %v", result, tt.expected)
 			}
@@ -239,9 +247,10 @@ func TestGenerateService_inspectedFlagsToContractFlags(t *testing.T) {
 			Persistent: true,
 		},
 		{
-			Name:  "config",
-			Usage: "Config file",
-			Type:  "string",
+			Name:       "config",
+			Usage:      "Config file",
+			Type:       "string",
+			Deprecated: "use --config-file instead",
 		},
 	}
 
@@ -282,6 +291,9 @@ func TestGenerateService_inspectedFlagsToContractFlags(t *testing.T) {
 	if result[1].Persistent {
 		t.Error("result[1].Persistent = true, want false")
 	}
+	if result[1].Deprecated != "use --config-file instead" {
+		t.Errorf("result[1].Deprecated = %q, want %q", result[1].Deprecated, "use --config-file instead")
+	}
 }
 
 func TestGenerateService_inspectedCommandsToContractCommands(t *testing.T) {
@@ -299,12 +311,15 @@ func TestGenerateService_inspectedCommandsToContractCommands(t *testing.T) {
 			},
 		},
 		{
-			Use:   "delete",
-			Short: "Delete resources",
+			Use:               "delete",
+			Short:             "Delete resources",
+			Aliases:           []string{"del", "rm"},
+			MutuallyExclusive: [][]string{{"all", "name"}},
+			RequiredTogether:  [][]string{{"force", "recursive"}},
 		},
 	}
 
-	result := service.inspectedCommandsToContractCommands(inspectedCommands)
+	result := service.inspectedCommandsToContractCommands(inspectedCommands, nil, false)
 
 	if len(result) != 2 {
 		t.Errorf("len(result) = %d, want 2", len(result))
@@ -329,7 +344,899 @@ func TestGenerateService_inspectedCommandsToContractCommands(t *testing.T) {
 	if result[1].Short != "Delete resources" {
 		t.Errorf("result[1].Short = %q, want %q", result[1].Short, "Delete resources")
 	}
+	if !reflect.DeepEqual(result[1].Aliases, []string{"del", "rm"}) {
+		t.Errorf("result[1].Aliases = %v, want [del rm]", result[1].Aliases)
+	}
+	if !reflect.DeepEqual(result[1].MutuallyExclusive, [][]string{{"all", "name"}}) || !reflect.DeepEqual(result[1].RequiredTogether, [][]string{{"force", "recursive"}}) {
+		t.Errorf("result[1] flag groups = %v and %v, want the inspected groups", result[1].MutuallyExclusive, result[1].RequiredTogether)
+	}
 	if len(result[1].Commands) != 0 {
 		t.Errorf("len(result[1].Commands) = %d, want 0", len(result[1].Commands))
 	}
 }
+
+func TestGenerateService_inspectedToContract_ExpandPersistentFlags(t *testing.T) {
+	service := NewGenerateService()
+	inspected := &inspector.InspectedCLI{
+		Use: "app",
+		Flags: []inspector.InspectedFlag{
+			{Name: "verbose", Shorthand: "v", Type: "bool", Persistent: true},
+			{Name: "version", Type: "bool"},
+		},
+		Commands: []inspector.InspectedCommand{
+			{
+				Use: "db",
+				Flags: []inspector.InspectedFlag{
+					{Name: "dsn", Type: "string", Persistent: true},
+				},
+				Commands: []inspector.InspectedCommand{
+					{
+						Use: "migrate",
+						Flags: []inspector.InspectedFlag{
+							{Name: "verbose", Type: "int"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	result := service.inspectedToContract(inspected, true)
+
+	flagNames := func(flags []contract.Flag) []string {
+		var names []string
+		for _, flag := range flags {
+			names = append(names, flag.Name)
+		}
+		return names
+	}
+	if got, want := flagNames(result.Flags), []string{"verbose", "version"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("root flags = %v, want %v", got, want)
+	}
+	db := result.Commands[0]
+	if got, want := flagNames(db.Flags), []string{"dsn", "verbose"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("db flags = %v, want %v", got, want)
+	}
+	migrate := db.Commands[0]
+	if got, want := flagNames(migrate.Flags), []string{"verbose", "dsn"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("migrate flags = %v, want %v", got, want)
+	}
+	if migrate.Flags[0].Type != "int" {
+		t.Errorf("migrate --verbose type = %q, want the command's own int flag", migrate.Flags[0].Type)
+	}
+
+	// The expanded contract validates against the CLI it was generated from
+	if got := validator.ValidateWithOptions(result, inspected, validator.Options{ExpandedContract: true}); !got.IsValid() {
+		t.Errorf("ValidateWithOptions(ExpandedContract) errors = %+v, want none", got.Errors)
+	}
+	if got := validator.Validate(result, inspected); got.IsValid() {
+		t.Error("Validate() of an expanded contract without ExpandedContract should fail")
+	}
+
+	// Without expansion, subcommands only list their own flags
+	plain := service.inspectedToContract(inspected, false)
+	if got, want := flagNames(plain.Commands[0].Commands[0].Flags), []string{"verbose"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpanded migrate flags = %v, want %v", got, want)
+	}
+}
+
+func TestFilterHiddenCommands(t *testing.T) {
+	commands := []inspector.InspectedCommand{
+		{
+			Use:   "serve",
+			Short: "Start the server",
+			Commands: []inspector.InspectedCommand{
+				{Use: "status", Short: "Show status"},
+				{Use: "pprof", Short: "Profiling endpoints", Hidden: true},
+			},
+		},
+		{
+			Use:    "debug",
+			Short:  "Debugging tools",
+			Hidden: true,
+		},
+	}
+
+	result := filterHiddenCommands(commands)
+
+	if len(result) != 1 {
+		t.Fatalf("len(result) = %d, want 1", len(result))
+	}
+	if result[0].Use != "serve" {
+		t.Errorf("result[0].Use = %q, want %q", result[0].Use, "serve")
+	}
+	if len(result[0].Commands) != 1 {
+		t.Fatalf("len(result[0].Commands) = %d, want 1", len(result[0].Commands))
+	}
+	if result[0].Commands[0].Use != "status" {
+		t.Errorf("result[0].Commands[0].Use = %q, want %q", result[0].Commands[0].Use, "status")
+	}
+
+	// The input must not be modified
+	if len(commands[0].Commands) != 2 {
+		t.Errorf("filterHiddenCommands modified its input")
+	}
+}
+
+func TestFilterNonRunnableCommands(t *testing.T) {
+	commands := []inspector.InspectedCommand{
+		{
+			Use:   "db",
+			Short: "Manage the database",
+			Commands: []inspector.InspectedCommand{
+				{Use: "migrate", Short: "Run migrations", Runnable: true},
+				{Use: "topics", Short: "Help topics", Commands: []inspector.InspectedCommand{
+					{Use: "schema", Short: "About the schema"},
+				}},
+			},
+		},
+		{Use: "environment", Short: "Help about environment variables"},
+		{Use: "serve", Short: "Start the server", Runnable: true},
+	}
+
+	result := filterNonRunnableCommands(commands)
+
+	var got []string
+	for _, cmd := range result {
+		got = append(got, cmd.Use)
+		for _, sub := range cmd.Commands {
+			got = append(got, cmd.Use+" "+sub.Use)
+		}
+	}
+	if want := []string{"db", "db migrate", "serve"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("filterNonRunnableCommands() kept %v, want %v", got, want)
+	}
+
+	// The input must not be modified
+	if len(commands[0].Commands) != 2 {
+		t.Errorf("filterNonRunnableCommands modified its input")
+	}
+}
+
+func TestMarkRunnable(t *testing.T) {
+	inspected := []inspector.InspectedCommand{
+		{Use: "db", Commands: []inspector.InspectedCommand{{Use: "migrate", Runnable: true}}},
+	}
+	commands := NewGenerateService().inspectedCommandsToContractCommands(inspected, nil, false)
+
+	markRunnable(commands, inspected)
+
+	if r := commands[0].Runnable; r == nil || *r {
+		t.Errorf("db Runnable = %v, want false", r)
+	}
+	if r := commands[0].Commands[0].Runnable; r == nil || !*r {
+		t.Errorf("db migrate Runnable = %v, want true", r)
+	}
+}
+
+func TestGenerateService_Generate_RunnableOnlyRequiresSource(t *testing.T) {
+	_, err := NewGenerateService().Generate(GenerateOptions{FromBinary: "/usr/bin/app", RunnableOnly: true})
+	if err == nil || !strings.Contains(err.Error(), "--runnable-only requires inspecting the project source") {
+		t.Errorf("Generate() error = %v, want source required", err)
+	}
+}
+
+func TestGenerateService_Generate_ExtractGodocRequiresSource(t *testing.T) {
+	_, err := NewGenerateService().Generate(GenerateOptions{FromOpenAPI: "openapi.yaml", ExtractGodoc: true})
+	if err == nil || !strings.Contains(err.Error(), "--extract-godoc requires inspecting the project source") {
+		t.Errorf("Generate() error = %v, want source required", err)
+	}
+}
+
+func TestGenerateService_Generate_Static(t *testing.T) {
+	dir := t.TempDir()
+	source := `package main
+
+import "github.com/spf13/cobra"
+
+func NewRootCmd() *cobra.Command {
+	cmd := &cobra.Command{Use: "app", Short: "An app"}
+	cmd.Flags().IntP("port", "p", 8080, "Port to listen on")
+	cmd.AddCommand(&cobra.Command{Use: "serve", Short: "Serve the app", Run: func(*cobra.Command, []string) {}})
+	return cmd
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+	listOutput := `{"Dir": "` + dir + `", "ImportPath": "example.com/app", "Name": "main", "GoFiles": ["main.go"]}`
+	mock := &executor.MockExecutor{Results: map[string]executor.MockResult{
+		"go list -e -json ./...": {Output: []byte(listOutput)},
+	}}
+
+	yamlContent, err := NewGenerateService().Generate(GenerateOptions{
+		ProjectPath: dir,
+		Entrypoint:  "main.NewRootCmd",
+		Static:      true,
+		NoHeader:    true,
+		Executor:    mock,
+	})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	var c contract.Contract
+	if err := yaml.Unmarshal([]byte(yamlContent), &c); err != nil {
+		t.Fatalf("generated contract does not parse: %v", err)
+	}
+	want := contract.Contract{
+		Use:      "app",
+		Short:    "An app",
+		Flags:    []contract.Flag{{Name: "port", Shorthand: "p", Usage: "Port to listen on", Type: "int"}},
+		Commands: []contract.Command{{Use: "serve", Short: "Serve the app"}},
+	}
+	if !reflect.DeepEqual(c, want) {
+		t.Errorf("Generate() = %+v, want %+v", c, want)
+	}
+	if len(mock.Commands) != 1 {
+		t.Errorf("commands run = %+v, want only go list", mock.Commands)
+	}
+}
+
+func TestGenerateService_Generate_StaticConflicts(t *testing.T) {
+	tests := []struct {
+		name string
+		opts GenerateOptions
+		want string
+	}{
+		{"from binary", GenerateOptions{Static: true, FromBinary: "/usr/bin/app"}, "--static inspects the project source"},
+		{"from openapi", GenerateOptions{Static: true, FromOpenAPI: "openapi.yaml"}, "--static inspects the project source"},
+		{"with validation", GenerateOptions{Static: true, WithValidation: true}, "cannot be used with --static"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewGenerateService().Generate(tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Generate() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateService_Generate_IncludeHiddenFlagsConflicts(t *testing.T) {
+	tests := []struct {
+		name string
+		opts GenerateOptions
+		want string
+	}{
+		{"from binary", GenerateOptions{IncludeHiddenFlags: true, FromBinary: "/usr/bin/app"}, "cannot be used with --from-binary or --from-openapi"},
+		{"from openapi", GenerateOptions{IncludeHiddenFlags: true, FromOpenAPI: "openapi.yaml"}, "cannot be used with --from-binary or --from-openapi"},
+		{"static", GenerateOptions{IncludeHiddenFlags: true, Static: true}, "--include-hidden cannot be used with --static"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewGenerateService().Generate(tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Generate() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterDeprecatedFlags(t *testing.T) {
+	commands := []inspector.InspectedCommand{
+		{
+			Use:   "serve",
+			Flags: []inspector.InspectedFlag{{Name: "port"}, {Name: "old-port", Deprecated: "use --port"}},
+			Commands: []inspector.InspectedCommand{
+				{Use: "tls", Flags: []inspector.InspectedFlag{{Name: "insecure", Deprecated: "it has no effect"}}},
+			},
+		},
+	}
+	filterCommandDeprecatedFlags(commands)
+
+	if flags := commands[0].Flags; len(flags) != 1 || flags[0].Name != "port" {
+		t.Errorf("serve flags = %+v, want only --port", flags)
+	}
+	if flags := commands[0].Commands[0].Flags; len(flags) != 0 {
+		t.Errorf("serve tls flags = %+v, want none", flags)
+	}
+}
+
+func TestGenerateService_Generate_WriteSnapshotRequiresInspection(t *testing.T) {
+	_, err := NewGenerateService().Generate(GenerateOptions{FromOpenAPI: "openapi.yaml", WriteSnapshot: "cli.json"})
+	if err == nil || !strings.Contains(err.Error(), "--write-snapshot cannot be used with --from-openapi") {
+		t.Errorf("Generate() error = %v, want --from-openapi rejected", err)
+	}
+}
+
+func TestApplyGodoc(t *testing.T) {
+	c := &contract.Contract{
+		Use: "mycli",
+		Commands: []contract.Command{
+			{
+				Use: "db",
+				Commands: []contract.Command{
+					{Use: "migrate [version]"},
+				},
+			},
+			{Use: "serve", Long: "Serve from the CLI's own Long."},
+		},
+	}
+
+	applyGodoc(c, map[string]string{
+		"mycli":   "NewRootCmd builds the CLI.",
+		"migrate": "newMigrateCmd runs the migrations.",
+		"serve":   "newServeCmd starts the server.",
+	})
+
+	if c.Long != "NewRootCmd builds the CLI." {
+		t.Errorf("root Long = %q", c.Long)
+	}
+	if got := c.Commands[0].Long; got != "" {
+		t.Errorf("db Long = %q, want empty", got)
+	}
+	if got := c.Commands[0].Commands[0].Long; got != "newMigrateCmd runs the migrations." {
+		t.Errorf("db migrate Long = %q", got)
+	}
+	if got := c.Commands[1].Long; got != "Serve from the CLI's own Long." {
+		t.Errorf("serve Long = %q, want the CLI's Long kept", got)
+	}
+}
+
+func TestApplyExamples(t *testing.T) {
+	c := &contract.Contract{
+		Use:   "mycli",
+		Short: "My CLI",
+		Commands: []contract.Command{
+			{
+				Use:   "db",
+				Short: "Database commands",
+				Commands: []contract.Command{
+					{Use: "migrate [version]", Short: "Run migrations"},
+				},
+			},
+			{Use: "serve", Short: "Start the server", Example: "mycli serve"},
+		},
+	}
+
+	applyExamples(c, map[string]string{
+		"":              "--verbose",
+		"db migrate 42": "db migrate 42 --dry-run",
+		"serve":         "serve --port 8080",
+		"unknown":       "unknown",
+	})
+
+	if c.Example != "mycli --verbose\nmycli unknown" {
+		t.Errorf("root Example = %q", c.Example)
+	}
+	if got := c.Commands[0].Commands[0].Example; got != "mycli db migrate 42 --dry-run" {
+		t.Errorf("db migrate Example = %q", got)
+	}
+	if got := c.Commands[1].Example; got != "mycli serve\nmycli serve --port 8080" {
+		t.Errorf("serve Example = %q", got)
+	}
+}
+
+func TestGenerateService_Generate_UnsupportedContractVersion(t *testing.T) {
+	_, err := NewGenerateService().Generate(GenerateOptions{ContractVersion: 3})
+	if err == nil || !strings.Contains(err.Error(), "unsupported contract version 3") {
+		t.Errorf("Generate() error = %v, want unsupported contract version error", err)
+	}
+}
+
+func TestGenerateService_Generate_JSON(t *testing.T) {
+	dir := t.TempDir()
+	source := `package main
+
+import "github.com/spf13/cobra"
+
+func NewRootCmd() *cobra.Command {
+	cmd := &cobra.Command{Use: "app", Short: "Runs <tasks> & jobs"}
+	cmd.Flags().IntP("port", "p", 8080, "Port to listen on")
+	return cmd
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+	listOutput := `{"Dir": "` + dir + `", "ImportPath": "example.com/app", "Name": "main", "GoFiles": ["main.go"]}`
+	mock := &executor.MockExecutor{Results: map[string]executor.MockResult{
+		"go list -e -json ./...": {Output: []byte(listOutput)},
+	}}
+
+	jsonContent, err := NewGenerateService().Generate(GenerateOptions{
+		ProjectPath:  dir,
+		Entrypoint:   "main.NewRootCmd",
+		Static:       true,
+		OutputFormat: output.FormatJSON,
+		Executor:     mock,
+	})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	want := `{
+  "use": "app",
+  "short": "Runs <tasks> & jobs",
+  "flags": [
+    {
+      "name": "port",
+      "shorthand": "p",
+      "usage": "Port to listen on",
+      "type": "int"
+    }
+  ]
+}
+`
+	if jsonContent != want {
+		t.Errorf("Generate() =\n%s\nwant:\n%s", jsonContent, want)
+	}
+
+	// The JSON contract loads like a YAML one
+	contractPath := filepath.Join(dir, "cliguard.json")
+	if err := os.WriteFile(contractPath, []byte(jsonContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := contract.Load(contractPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if loaded.Use != "app" || len(loaded.Flags) != 1 || loaded.Flags[0].Shorthand != "p" {
+		t.Errorf("Load() = %+v", loaded)
+	}
+}
+
+func TestGenerateService_Generate_OutputFormatErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		opts GenerateOptions
+		want string
+	}{
+		{"unsupported", GenerateOptions{OutputFormat: "toml"}, "unsupported output format 'toml' (supported: text, json, yaml)"},
+		{"ascii json", GenerateOptions{OutputFormat: output.FormatJSON, OutputEncoding: EncodingASCII}, "--output-encoding ascii is not supported with --output json"},
+		{"grouped json", GenerateOptions{OutputFormat: output.FormatJSON, GroupBySubpackage: true}, "cannot be used with --output json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewGenerateService().GenerateToFile(tt.opts, filepath.Join(t.TempDir(), "cliguard.json"))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("GenerateToFile() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateService_Generate_UnsupportedOutputEncoding(t *testing.T) {
+	_, err := NewGenerateService().Generate(GenerateOptions{OutputEncoding: "latin1"})
+	if err == nil || !strings.Contains(err.Error(), "unsupported output encoding 'latin1'") {
+		t.Errorf("Generate() error = %v, want unsupported output encoding error", err)
+	}
+}
+
+func TestOutputEncoding(t *testing.T) {
+	original := &contract.Contract{
+		Use:   "myapp",
+		Short: "Café 🚀 launcher",
+		Long:  "Déploie l'application\nsur le serveur",
+		Flags: []contract.Flag{
+			{Name: "port", Type: "int", Usage: "Port number"},
+		},
+	}
+
+	t.Run("ascii", func(t *testing.T) {
+		data, err := marshalContract(original, EncodingASCII)
+		if err != nil {
+			t.Fatalf("marshalContract() error = %v", err)
+		}
+		output := encodeOutput(string(data), EncodingASCII)
+
+		for i := 0; i < len(output); i++ {
+			if output[i] >= 0x80 {
+				t.Fatalf("output contains non-ASCII byte at %d:\n%s", i, output)
+			}
+		}
+		if !strings.Contains(output, `\u00E9`) || !strings.Contains(output, `\U0001F680`) {
+			t.Errorf("output missing escape sequences:\n%s", output)
+		}
+		// ASCII-only scalars keep their plain style
+		if !strings.Contains(output, "usage: Port number\n") {
+			t.Errorf("ASCII scalars should not be quoted:\n%s", output)
+		}
+
+		var decoded contract.Contract
+		if err := yaml.Unmarshal([]byte(output), &decoded); err != nil {
+			t.Fatalf("output is not valid YAML: %v", err)
+		}
+		if !reflect.DeepEqual(&decoded, original) {
+			t.Errorf("round trip = %+v, want %+v", decoded, *original)
+		}
+	})
+
+	t.Run("utf8bom", func(t *testing.T) {
+		output := encodeOutput("use: myapp\n", EncodingUTF8BOM)
+		if output != "\xef\xbb\xbfuse: myapp\n" {
+			t.Errorf("encodeOutput() = %q, want BOM-prefixed output", output)
+		}
+	})
+
+	t.Run("utf8", func(t *testing.T) {
+		for _, encoding := range []string{"", EncodingUTF8} {
+			if output := encodeOutput("short: Café\n", encoding); output != "short: Café\n" {
+				t.Errorf("encodeOutput(%q) = %q, want unchanged", encoding, output)
+			}
+		}
+	})
+}
+
+func TestGenerateService_Generate_InvalidStripRule(t *testing.T) {
+	_, err := NewGenerateService().Generate(GenerateOptions{StripRules: []string{"arg:^x"}})
+	if err == nil || !strings.Contains(err.Error(), "invalid strip rule 'arg:^x'") {
+		t.Errorf("Generate() error = %v, want invalid strip rule error", err)
+	}
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	t.Run("new file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "cliguard.yaml")
+
+		written, err := writeFileAtomic(path, []byte("use: myapp\n"))
+		if err != nil || !written {
+			t.Fatalf("writeFileAtomic() = %v, %v, want true, nil", written, err)
+		}
+		data, _ := os.ReadFile(path)
+		if string(data) != "use: myapp\n" {
+			t.Errorf("content = %q", data)
+		}
+		if info, _ := os.Stat(path); info.Mode().Perm() != 0644 {
+			t.Errorf("permissions = %v, want 0644", info.Mode().Perm())
+		}
+	})
+
+	t.Run("update preserves permissions", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "cliguard.yaml")
+		if err := os.WriteFile(path, []byte("use: old\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		written, err := writeFileAtomic(path, []byte("use: new\n"))
+		if err != nil || !written {
+			t.Fatalf("writeFileAtomic() = %v, %v, want true, nil", written, err)
+		}
+		data, _ := os.ReadFile(path)
+		if string(data) != "use: new\n" {
+			t.Errorf("content = %q", data)
+		}
+		if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+			t.Errorf("permissions = %v, want 0600", info.Mode().Perm())
+		}
+
+		// No temporary files are left behind
+		entries, _ := os.ReadDir(dir)
+		if len(entries) != 1 {
+			t.Errorf("directory contains %d entries, want 1", len(entries))
+		}
+	})
+
+	t.Run("unchanged file is not rewritten", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "cliguard.yaml")
+		if err := os.WriteFile(path, []byte("use: myapp\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		old := time.Now().Add(-time.Hour).Truncate(time.Second)
+		if err := os.Chtimes(path, old, old); err != nil {
+			t.Fatal(err)
+		}
+
+		written, err := writeFileAtomic(path, []byte("use: myapp\n"))
+		if err != nil || written {
+			t.Fatalf("writeFileAtomic() = %v, %v, want false, nil", written, err)
+		}
+		if info, _ := os.Stat(path); !info.ModTime().Equal(old) {
+			t.Errorf("mtime = %v, want %v", info.ModTime(), old)
+		}
+	})
+
+	t.Run("writes through symlinks", func(t *testing.T) {
+		dir := t.TempDir()
+		target := filepath.Join(dir, "contracts", "cliguard.yaml")
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(target, []byte("use: old\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		link := filepath.Join(dir, "cliguard.yaml")
+		if err := os.Symlink(target, link); err != nil {
+			t.Skipf("symlinks not supported: %v", err)
+		}
+
+		if _, err := writeFileAtomic(link, []byte("use: new\n")); err != nil {
+			t.Fatalf("writeFileAtomic() error = %v", err)
+		}
+		if info, _ := os.Lstat(link); info.Mode()&os.ModeSymlink == 0 {
+			t.Error("symlink was replaced by a regular file")
+		}
+		if data, _ := os.ReadFile(target); string(data) != "use: new\n" {
+			t.Errorf("target content = %q", data)
+		}
+	})
+
+	t.Run("missing directory", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "missing", "cliguard.yaml")
+		if _, err := writeFileAtomic(path, []byte("use: myapp\n")); err == nil {
+			t.Error("writeFileAtomic() expected error for missing directory")
+		}
+	})
+}
+
+func TestGenerateService_GenerateToFile_FailureLeavesFileUntouched(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cliguard.yaml")
+	if err := os.WriteFile(path, []byte("use: myapp\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := NewGenerateService().GenerateToFile(GenerateOptions{ContractVersion: 3}, path)
+	if err == nil {
+		t.Fatal("GenerateToFile() expected error")
+	}
+	if data, _ := os.ReadFile(path); string(data) != "use: myapp\n" {
+		t.Errorf("content = %q, want original contract", data)
+	}
+}
+
+func TestGenerateService_Generate_FromOpenAPI(t *testing.T) {
+	specPath := filepath.Join(t.TempDir(), "openapi.yaml")
+	spec := `openapi: 3.0.3
+info:
+  title: Pet Store
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      summary: List all pets
+      parameters:
+        - name: limit
+          in: query
+          required: true
+          schema:
+            type: integer
+`
+	if err := os.WriteFile(specPath, []byte(spec), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := NewGenerateService().Generate(GenerateOptions{FromOpenAPI: specPath, ToolName: "petctl"})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	contractPath := filepath.Join(t.TempDir(), "cliguard.yaml")
+	if err := os.WriteFile(contractPath, []byte(output), 0644); err != nil {
+		t.Fatal(err)
+	}
+	c, err := contract.Load(contractPath)
+	if err != nil {
+		t.Fatalf("generated contract does not parse: %v\n%s", err, output)
+	}
+	if c.Use != "petctl" || len(c.Commands) != 1 || c.Commands[0].Use != "list-pets" {
+		t.Fatalf("unexpected contract:\n%s", output)
+	}
+	if flag := c.Commands[0].Flags[0]; flag.Name != "limit" || flag.Type != "int" || !flag.Required {
+		t.Errorf("limit flag = %+v, want required int", flag)
+	}
+}
+
+func TestGenerateService_Generate_StripHelpAndCompletionCommands(t *testing.T) {
+	specPath := filepath.Join(t.TempDir(), "openapi.yaml")
+	spec := `openapi: 3.0.3
+info:
+  title: Pet Store
+paths:
+  /help:
+    get:
+      operationId: help
+      summary: Get help
+  /completion:
+    get:
+      operationId: completion
+      summary: Get completions
+  /pets:
+    get:
+      operationId: listPets
+      summary: List all pets
+`
+	if err := os.WriteFile(specPath, []byte(spec), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		opts GenerateOptions
+		want []string
+	}{
+		{name: "kept by default", want: []string{"completion", "help", "list-pets"}},
+		{name: "help stripped", opts: GenerateOptions{StripHelpCommand: true}, want: []string{"completion", "list-pets"}},
+		{name: "both stripped", opts: GenerateOptions{StripHelpCommand: true, StripCompletionCommand: true}, want: []string{"list-pets"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := tt.opts
+			opts.FromOpenAPI = specPath
+			opts.ToolName = "petctl"
+			output, err := NewGenerateService().Generate(opts)
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			var c contract.Contract
+			if err := yaml.Unmarshal([]byte(output), &c); err != nil {
+				t.Fatalf("generated contract does not parse: %v", err)
+			}
+			var uses []string
+			for _, cmd := range c.Commands {
+				uses = append(uses, cmd.Use)
+			}
+			sort.Strings(uses)
+			if !reflect.DeepEqual(uses, tt.want) {
+				t.Errorf("commands = %v, want %v", uses, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateService_Generate_FromOpenAPIAndBinary(t *testing.T) {
+	_, err := NewGenerateService().Generate(GenerateOptions{FromOpenAPI: "openapi.yaml", FromBinary: "mycli"})
+	if err == nil || !strings.Contains(err.Error(), "cannot be used together") {
+		t.Errorf("Generate() error = %v, want conflicting source error", err)
+	}
+}
+
+func TestGenerateService_GenerateToFile_OmitUnchanged(t *testing.T) {
+	dir := t.TempDir()
+	specPath := filepath.Join(dir, "openapi.yaml")
+	spec := `openapi: 3.0.3
+info:
+  title: Pet Store
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      summary: List all pets
+`
+	if err := os.WriteFile(specPath, []byte(spec), 0644); err != nil {
+		t.Fatal(err)
+	}
+	opts := GenerateOptions{FromOpenAPI: specPath, ToolName: "petctl", OmitUnchanged: true}
+	service := NewGenerateService()
+
+	contractPath := filepath.Join(dir, "cliguard.yaml")
+	if written, err := service.GenerateToFile(opts, contractPath); err != nil || !written {
+		t.Fatalf("GenerateToFile() = %v, %v, want true, nil for a new file", written, err)
+	}
+
+	// The same contract, formatted and commented by hand
+	edited := `# Pet Store CLI, reviewed by hand
+use:   petctl
+short: "Pet Store"
+commands:
+  - use: list-pets
+    short: List all pets
+`
+	if err := os.WriteFile(contractPath, []byte(edited), 0644); err != nil {
+		t.Fatal(err)
+	}
+	written, err := service.GenerateToFile(opts, contractPath)
+	if err != nil || written {
+		t.Fatalf("GenerateToFile() = %v, %v, want false, nil for an unchanged contract", written, err)
+	}
+	if data, _ := os.ReadFile(contractPath); string(data) != edited {
+		t.Errorf("content = %q, want the edited contract kept", data)
+	}
+
+	// Without OmitUnchanged, only identical text is left alone
+	opts.OmitUnchanged = false
+	if written, err := service.GenerateToFile(opts, contractPath); err != nil || !written {
+		t.Fatalf("GenerateToFile() = %v, %v, want true, nil without OmitUnchanged", written, err)
+	}
+
+	// A changed contract is written
+	if err := os.WriteFile(contractPath, []byte("use: petctl\nshort: Old title\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	opts.OmitUnchanged = true
+	if written, err := service.GenerateToFile(opts, contractPath); err != nil || !written {
+		t.Fatalf("GenerateToFile() = %v, %v, want true, nil for a changed contract", written, err)
+	}
+}
+
+func TestGenerateService_Generate_Header(t *testing.T) {
+	dir := t.TempDir()
+	specPath := filepath.Join(dir, "openapi.yaml")
+	spec := `openapi: 3.0.3
+info:
+  title: Pet Store
+paths: {}
+`
+	if err := os.WriteFile(specPath, []byte(spec), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	service := NewGenerateService()
+	service.Now = func() time.Time { return time.Date(2024, 5, 1, 14, 0, 0, 0, time.FixedZone("CEST", 2*60*60)) }
+	opts := GenerateOptions{FromOpenAPI: specPath, ToolName: "petctl"}
+
+	t.Run("default", func(t *testing.T) {
+		output, err := service.Generate(opts)
+		if err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+		want := "# Generated by cliguard v" + version.Version + "\n# Generated at: 2024-05-01T12:00:00Z\n#\nuse: petctl\n"
+		if !strings.HasPrefix(output, want) {
+			t.Errorf("output = %q, want prefix %q", output, want)
+		}
+	})
+
+	t.Run("custom comment", func(t *testing.T) {
+		opts := opts
+		opts.Entrypoint = "cmd.NewRootCmd"
+		opts.HeaderComment = "Generated from {{.Entrypoint}} at {{.Timestamp}}\n\nDo not edit."
+		output, err := service.Generate(opts)
+		if err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+		want := "# Generated from cmd.NewRootCmd at 2024-05-01T12:00:00Z\n#\n# Do not edit.\n#\nuse: petctl\n"
+		if !strings.HasPrefix(output, want) {
+			t.Errorf("output = %q, want prefix %q", output, want)
+		}
+	})
+
+	t.Run("no header", func(t *testing.T) {
+		opts := opts
+		opts.NoHeader = true
+		output, err := service.Generate(opts)
+		if err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+		if !strings.HasPrefix(output, "use: petctl\n") {
+			t.Errorf("output = %q, want no header", output)
+		}
+	})
+
+	t.Run("invalid template", func(t *testing.T) {
+		opts := opts
+		opts.HeaderComment = "Generated at {{.Date}}"
+		if _, err := service.Generate(opts); err == nil || !strings.Contains(err.Error(), "header template") {
+			t.Errorf("Generate() error = %v, want header template error", err)
+		}
+	})
+
+	t.Run("comment with no header", func(t *testing.T) {
+		opts := opts
+		opts.HeaderComment = "Custom"
+		opts.NoHeader = true
+		if _, err := service.Generate(opts); err == nil || !strings.Contains(err.Error(), "cannot be used together") {
+			t.Errorf("Generate() error = %v, want conflicting flags error", err)
+		}
+	})
+
+	t.Run("new timestamp with omit unchanged", func(t *testing.T) {
+		contractPath := filepath.Join(dir, "cliguard.yaml")
+		if _, err := service.GenerateToFile(opts, contractPath); err != nil {
+			t.Fatal(err)
+		}
+
+		later := *service
+		later.Now = func() time.Time { return time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC) }
+		opts := opts
+		opts.OmitUnchanged = true
+		written, err := later.GenerateToFile(opts, contractPath)
+		if err != nil || written {
+			t.Errorf("GenerateToFile() = %v, %v, want false, nil when only the header changed", written, err)
+		}
+	})
+}
+
+func TestModuleName(t *testing.T) {
+	dir := t.TempDir()
+	if got := moduleName(dir); got != "" {
+		t.Errorf("moduleName() without go.mod = %q, want empty", got)
+	}
+
+	goMod := "// Example module\nmodule github.com/org/repo\n\ngo 1.24\n"
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(goMod), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := moduleName(dir); got != "github.com/org/repo" {
+		t.Errorf("moduleName() = %q, want %q", got, "github.com/org/repo")
+	}
+}
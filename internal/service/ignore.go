@@ -0,0 +1,70 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/contract"
+	"github.com/hiAndrewQuinn/cliguard/internal/inspector"
+)
+
+// compileIgnorePatterns compiles the ValidateOptions.IgnoreCommandsRegex
+// patterns
+func compileIgnorePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	var compiled []*regexp.Regexp
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --ignore-commands-regex pattern '%s': %w", pattern, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+// ignoreCommands removes the commands whose path matches any of the
+// patterns, with their subcommands, from both the contract and the
+// inspected CLI. Neither is modified; filtered copies are returned.
+func ignoreCommands(c *contract.Contract, actual *inspector.InspectedCLI, patterns []*regexp.Regexp) (*contract.Contract, *inspector.InspectedCLI) {
+	if len(patterns) == 0 {
+		return c, actual
+	}
+
+	filteredContract := *c
+	filteredActual := *actual
+	for _, pattern := range patterns {
+		filteredContract.Commands = filterByRegex(contract.CommandName(c.Use), filteredContract.Commands, pattern)
+		filteredActual.Commands = filterInspectedByRegex(actual.UseName(), filteredActual.Commands, pattern)
+	}
+	return &filteredContract, &filteredActual
+}
+
+// filterByRegex returns the commands under the command at parentPath whose
+// path doesn't match pattern, recursively. A command's path is its parent's
+// path and its name separated by a space, e.g. "myapp db migrate".
+func filterByRegex(parentPath string, commands []contract.Command, pattern *regexp.Regexp) []contract.Command {
+	var kept []contract.Command
+	for _, cmd := range commands {
+		cmdPath := parentPath + " " + contract.CommandName(cmd.Use)
+		if pattern.MatchString(cmdPath) {
+			continue
+		}
+		cmd.Commands = filterByRegex(cmdPath, cmd.Commands, pattern)
+		kept = append(kept, cmd)
+	}
+	return kept
+}
+
+// filterInspectedByRegex is filterByRegex for the commands of an inspected CLI
+func filterInspectedByRegex(parentPath string, commands []inspector.InspectedCommand, pattern *regexp.Regexp) []inspector.InspectedCommand {
+	var kept []inspector.InspectedCommand
+	for _, cmd := range commands {
+		cmdPath := parentPath + " " + cmd.UseName()
+		if pattern.MatchString(cmdPath) {
+			continue
+		}
+		cmd.Commands = filterInspectedByRegex(cmdPath, cmd.Commands, pattern)
+		kept = append(kept, cmd)
+	}
+	return kept
+}
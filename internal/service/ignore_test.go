@@ -0,0 +1,110 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/contract"
+	"github.com/hiAndrewQuinn/cliguard/internal/inspector"
+)
+
+func TestFilterByRegex(t *testing.T) {
+	commands := []contract.Command{
+		{Use: "db", Commands: []contract.Command{
+			{Use: "migrate [version]"},
+			{Use: "export-deprecated"},
+		}},
+		{Use: "debug", Commands: []contract.Command{{Use: "dump"}}},
+		{Use: "serve"},
+	}
+
+	tests := []struct {
+		pattern string
+		want    []string
+	}{
+		{pattern: `-deprecated$`, want: []string{"myapp db", "myapp db migrate", "myapp debug", "myapp debug dump", "myapp serve"}},
+		{pattern: `debug.*`, want: []string{"myapp db", "myapp db migrate", "myapp db export-deprecated", "myapp serve"}},
+		{pattern: `^myapp db migrate$`, want: []string{"myapp db", "myapp db export-deprecated", "myapp debug", "myapp debug dump", "myapp serve"}},
+		{pattern: `^myapp db$`, want: []string{"myapp debug", "myapp debug dump", "myapp serve"}},
+		{pattern: `^migrate`, want: []string{"myapp db", "myapp db migrate", "myapp db export-deprecated", "myapp debug", "myapp debug dump", "myapp serve"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			got := commandPaths("myapp", filterByRegex("myapp", commands, regexp.MustCompile(tt.pattern)))
+			if strings.Join(got, ", ") != strings.Join(tt.want, ", ") {
+				t.Errorf("filterByRegex(%q) kept %v, want %v", tt.pattern, got, tt.want)
+			}
+		})
+	}
+
+	if len(commands[0].Commands) != 2 {
+		t.Errorf("filterByRegex() modified its input: %+v", commands)
+	}
+}
+
+// commandPaths lists the paths of commands and their subcommands, depth first
+func commandPaths(parentPath string, commands []contract.Command) []string {
+	var paths []string
+	for _, cmd := range commands {
+		cmdPath := parentPath + " " + contract.CommandName(cmd.Use)
+		paths = append(paths, cmdPath)
+		paths = append(paths, commandPaths(cmdPath, cmd.Commands)...)
+	}
+	return paths
+}
+
+func TestValidateService_Validate_IgnoreCommandsRegex(t *testing.T) {
+	projectDir := t.TempDir()
+	contractPath := filepath.Join(projectDir, "cliguard.yaml")
+	if err := os.WriteFile(contractPath, []byte("use: myapp\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	svc := &ValidateService{
+		ContractLoader: func(string) (*contract.Contract, error) {
+			return &contract.Contract{Use: "myapp", Commands: []contract.Command{
+				{Use: "serve"},
+				{Use: "import-deprecated", Short: "Removed from the CLI"},
+			}}, nil
+		},
+		Inspector: func(string, string) (*inspector.InspectedCLI, error) {
+			return &inspector.InspectedCLI{Use: "myapp", Commands: []inspector.InspectedCommand{
+				{Use: "serve"},
+				{Use: "debug", Commands: []inspector.InspectedCommand{{Use: "dump"}}},
+			}}, nil
+		},
+	}
+
+	result, err := svc.Validate(ValidateOptions{ProjectPath: projectDir, Entrypoint: "cmd.NewRootCmd"})
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if result.Success {
+		t.Fatal("Validate() should fail without ignoring: import-deprecated is missing and debug unexpected")
+	}
+
+	result, err = svc.Validate(ValidateOptions{
+		ProjectPath:         projectDir,
+		Entrypoint:          "cmd.NewRootCmd",
+		IgnoreCommandsRegex: []string{`-deprecated$`, `^myapp debug`},
+	})
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if !result.Success {
+		t.Errorf("Validate() errors = %+v, want the ignored commands left out", result.Result.Errors)
+	}
+
+	_, err = svc.Validate(ValidateOptions{
+		ProjectPath:         projectDir,
+		Entrypoint:          "cmd.NewRootCmd",
+		IgnoreCommandsRegex: []string{`debug(`},
+	})
+	if err == nil || !strings.Contains(err.Error(), "invalid --ignore-commands-regex pattern 'debug('") {
+		t.Errorf("Validate() error = %v, want invalid pattern error", err)
+	}
+}
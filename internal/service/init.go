@@ -0,0 +1,111 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// CIStep is a validate step that init can add to the CI of a project
+type CIStep struct {
+	// Description says what is added, e.g. "a validate-cli target to Makefile"
+	Description string
+
+	// Path is the file the step is written to
+	Path string
+
+	// Content is appended to Path, which is created if it doesn't exist
+	Content string
+}
+
+// DetectCISteps returns the validate steps for the CI the project at
+// projectPath uses: a validate-cli target if it has a Makefile, and a
+// workflow if it has GitHub Actions workflows. Steps the project already has,
+// a Makefile running cliguard validate or a cliguard-validate.yml workflow,
+// are left out.
+func DetectCISteps(projectPath, entrypoint string) []CIStep {
+	var steps []CIStep
+
+	makefile := filepath.Join(projectPath, "Makefile")
+	if data, err := os.ReadFile(makefile); err == nil && !strings.Contains(string(data), "cliguard validate") {
+		content := makefileTarget(entrypoint)
+		if len(data) > 0 && !strings.HasSuffix(string(data), "\n") {
+			content = "\n" + content
+		}
+		steps = append(steps, CIStep{
+			Description: "a validate-cli target to Makefile",
+			Path:        makefile,
+			Content:     content,
+		})
+	}
+
+	workflows := filepath.Join(projectPath, ".github", "workflows")
+	workflow := filepath.Join(workflows, "cliguard-validate.yml")
+	if info, err := os.Stat(workflows); err == nil && info.IsDir() {
+		if _, err := os.Stat(workflow); os.IsNotExist(err) {
+			steps = append(steps, CIStep{
+				Description: "a GitHub Actions workflow validating pull requests, .github/workflows/cliguard-validate.yml",
+				Path:        workflow,
+				Content:     gitHubWorkflow(entrypoint),
+			})
+		}
+	}
+
+	return steps
+}
+
+// AddCIStep appends the content of the step to its file
+func AddCIStep(step CIStep) error {
+	f, err := os.OpenFile(step.Path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open '%s': %w", step.Path, err)
+	}
+	if _, err := f.WriteString(step.Content); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write '%s': %w", step.Path, err)
+	}
+	return f.Close()
+}
+
+// makefileTarget is a Makefile target validating the CLI of entrypoint
+func makefileTarget(entrypoint string) string {
+	return fmt.Sprintf(`
+# Validate the CLI against its contract, cliguard.yaml
+.PHONY: validate-cli
+validate-cli:
+	cliguard validate --project-path . --entrypoint %s
+`, entrypoint)
+}
+
+// gitHubWorkflow is a GitHub Actions workflow validating the CLI of
+// entrypoint on each pull request changing Go files or the contract
+func gitHubWorkflow(entrypoint string) string {
+	return fmt.Sprintf(`name: Validate CLI Contract
+
+on:
+  pull_request:
+    paths:
+      - '**/*.go'
+      - 'go.mod'
+      - 'go.sum'
+      - 'cliguard.yaml'
+
+jobs:
+  validate:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+
+      - name: Set up Go
+        uses: actions/setup-go@v5
+        with:
+          go-version-file: go.mod
+
+      - name: Install cliguard
+        run: go install github.com/hiAndrewQuinn/cliguard@latest
+
+      - name: Validate CLI structure
+        run: cliguard validate --project-path . --entrypoint %s
+`, entrypoint)
+}
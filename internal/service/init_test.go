@@ -0,0 +1,54 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDetectCISteps(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "Makefile"), []byte("test:\n\tgo test ./..."), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, ".github", "workflows"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	steps := DetectCISteps(dir, "example.com/app/cmd.NewRootCmd")
+	if len(steps) != 2 {
+		t.Fatalf("DetectCISteps() = %+v, want a Makefile target and a workflow", steps)
+	}
+	for _, step := range steps {
+		if err := AddCIStep(step); err != nil {
+			t.Fatalf("AddCIStep() error = %v", err)
+		}
+	}
+
+	makefile, err := os.ReadFile(filepath.Join(dir, "Makefile"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(makefile), "test:\n\tgo test ./...\n\n# Validate") {
+		t.Errorf("Makefile = %q, want the target appended on a new line", makefile)
+	}
+	if !strings.Contains(string(makefile), "validate --project-path . --entrypoint example.com/app/cmd.NewRootCmd\n") {
+		t.Errorf("Makefile = %q, want the validate command", makefile)
+	}
+	workflow, err := os.ReadFile(filepath.Join(dir, ".github", "workflows", "cliguard-validate.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(workflow), "run: cliguard validate --project-path . --entrypoint example.com/app/cmd.NewRootCmd") {
+		t.Errorf("workflow = %q, want the validate step", workflow)
+	}
+
+	// Once added, the steps are not offered again
+	if steps := DetectCISteps(dir, "example.com/app/cmd.NewRootCmd"); len(steps) != 0 {
+		t.Errorf("DetectCISteps() = %+v, want none", steps)
+	}
+	if steps := DetectCISteps(t.TempDir(), "example.com/app/cmd.NewRootCmd"); len(steps) != 0 {
+		t.Errorf("DetectCISteps() = %+v, want none without a Makefile or workflows", steps)
+	}
+}
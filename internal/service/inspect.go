@@ -0,0 +1,103 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/inspector"
+	"github.com/itchyny/gojq"
+)
+
+// InspectOptions contains options for the inspect command
+type InspectOptions struct {
+	ProjectPath string
+	Entrypoint  string
+	Timeout     time.Duration
+
+	// Compact prints each JSON value on one line instead of indented
+	Compact bool
+
+	// Filter is a jq filter, such as ".commands[].use", applied to the
+	// inspection result with gojq. Each of its outputs is printed as one JSON
+	// value. Empty prints the whole result.
+	Filter string
+}
+
+// InspectService prints the raw inspection result of a CLI as JSON, for
+// piping into other tools
+type InspectService struct {
+	// Inspector analyzes Go projects to extract CLI structure.
+	// Defaults to running inspector.NewInspector(config).Inspect()
+	Inspector func(inspector.Config) (*inspector.InspectedCLI, error)
+}
+
+// NewInspectService creates a new InspectService with default dependencies
+func NewInspectService() *InspectService {
+	return &InspectService{
+		Inspector: func(config inspector.Config) (*inspector.InspectedCLI, error) {
+			return inspector.NewInspector(config).Inspect()
+		},
+	}
+}
+
+// Inspect inspects the CLI and returns its InspectedCLI as JSON, passed
+// through opts.Filter if one is set. The filter is parsed before the
+// project is inspected so that a typo fails fast.
+func (s *InspectService) Inspect(opts InspectOptions) (string, error) {
+	var query *gojq.Query
+	if opts.Filter != "" {
+		var err error
+		query, err = gojq.Parse(opts.Filter)
+		if err != nil {
+			return "", fmt.Errorf("invalid jq filter '%s': %w", opts.Filter, err)
+		}
+	}
+
+	inspectedCLI, err := s.Inspector(inspector.Config{
+		ProjectPath: opts.ProjectPath,
+		Entrypoint:  opts.Entrypoint,
+		Timeout:     opts.Timeout,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to inspect project: %w", err)
+	}
+
+	data, err := json.Marshal(inspectedCLI)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal inspected CLI to JSON: %w", err)
+	}
+
+	values := []interface{}{json.RawMessage(data)}
+	if query != nil {
+		var decoded interface{}
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			return "", fmt.Errorf("failed to decode inspected CLI JSON: %w", err)
+		}
+		values = nil
+		iter := query.Run(decoded)
+		for {
+			value, ok := iter.Next()
+			if !ok {
+				break
+			}
+			if err, ok := value.(error); ok {
+				return "", fmt.Errorf("jq filter '%s' failed: %w", opts.Filter, err)
+			}
+			values = append(values, value)
+		}
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	if !opts.Compact {
+		encoder.SetIndent("", "  ")
+	}
+	for _, value := range values {
+		if err := encoder.Encode(value); err != nil {
+			return "", fmt.Errorf("failed to encode JSON: %w", err)
+		}
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/inspector"
+)
+
+func TestInspectService_Inspect(t *testing.T) {
+	svc := &InspectService{
+		Inspector: func(config inspector.Config) (*inspector.InspectedCLI, error) {
+			if config.ProjectPath != "/test/project" || config.Entrypoint != "cmd.NewRootCmd" {
+				t.Errorf("unexpected inspector config: %+v", config)
+			}
+			return &inspector.InspectedCLI{
+				Use: "myapp",
+				Commands: []inspector.InspectedCommand{
+					{Use: "serve", Flags: []inspector.InspectedFlag{{Name: "port", Type: "int"}}},
+					{Use: "migrate"},
+				},
+			}, nil
+		},
+	}
+
+	tests := []struct {
+		name    string
+		compact bool
+		filter  string
+		want    string
+	}{
+		{
+			name:    "compact",
+			compact: true,
+			want:    `{"use":"myapp","short":"","commands":[{"use":"serve","short":"","flags":[{"name":"port","usage":"","type":"int","persistent":false}]},{"use":"migrate","short":""}]}` + "\n",
+		},
+		{
+			name:   "pretty filter",
+			filter: ".commands[0].flags[0] | keys",
+			want:   "[\n  \"name\",\n  \"persistent\",\n  \"type\",\n  \"usage\"\n]\n",
+		},
+		{
+			name:    "one value per line",
+			compact: true,
+			filter:  ".commands[].use",
+			want:    "\"serve\"\n\"migrate\"\n",
+		},
+		{
+			name:    "jq builtins",
+			compact: true,
+			filter:  `[.commands[] | select(.flags) | .use]`,
+			want:    "[\"serve\"]\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := svc.Inspect(InspectOptions{
+				ProjectPath: "/test/project",
+				Entrypoint:  "cmd.NewRootCmd",
+				Compact:     tt.compact,
+				Filter:      tt.filter,
+			})
+			if err != nil {
+				t.Fatalf("Inspect() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Inspect() =\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("pretty by default", func(t *testing.T) {
+		got, err := svc.Inspect(InspectOptions{ProjectPath: "/test/project", Entrypoint: "cmd.NewRootCmd"})
+		if err != nil {
+			t.Fatalf("Inspect() error = %v", err)
+		}
+		if !strings.HasPrefix(got, "{\n  \"use\": \"myapp\",\n") {
+			t.Errorf("Inspect() = %s", got)
+		}
+	})
+
+	t.Run("failing filter", func(t *testing.T) {
+		_, err := svc.Inspect(InspectOptions{ProjectPath: "/test/project", Entrypoint: "cmd.NewRootCmd", Filter: ".use[]"})
+		if err == nil || err.Error() != "jq filter '.use[]' failed: cannot iterate over: string (\"myapp\")" {
+			t.Errorf("Inspect() error = %v", err)
+		}
+	})
+}
+
+func TestInspectService_InvalidFilter(t *testing.T) {
+	svc := &InspectService{
+		Inspector: func(inspector.Config) (*inspector.InspectedCLI, error) {
+			t.Error("Inspector should not run with an invalid filter")
+			return nil, nil
+		},
+	}
+	_, err := svc.Inspect(InspectOptions{Filter: ".commands["})
+	if err == nil || !strings.Contains(err.Error(), "invalid jq filter '.commands['") {
+		t.Errorf("Inspect() error = %v", err)
+	}
+}
+
+func TestInspectService_InspectorError(t *testing.T) {
+	svc := &InspectService{
+		Inspector: func(inspector.Config) (*inspector.InspectedCLI, error) {
+			return nil, errors.New("build failed")
+		},
+	}
+	_, err := svc.Inspect(InspectOptions{})
+	if err == nil || err.Error() != "failed to inspect project: build failed" {
+		t.Errorf("Inspect() error = %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/contract"
+	"github.com/hiAndrewQuinn/cliguard/internal/lint"
+)
+
+// lintFixHeader is the comment written at the top of contracts fixed by lint
+const lintFixHeader = `# Cliguard contract file
+# Fixed with: cliguard lint --fix
+#
+`
+
+// LintService lints contract files and writes fixed contracts
+type LintService struct {
+	ContractLoader func(string) (*contract.Contract, error)
+}
+
+// NewLintService creates a new LintService with default dependencies
+func NewLintService() *LintService {
+	return &LintService{
+		ContractLoader: contract.Load,
+	}
+}
+
+// Lint returns the lint warnings for the contract at contractPath
+func (s *LintService) Lint(contractPath string) ([]lint.LintWarning, error) {
+	c, err := s.ContractLoader(contractPath)
+	if err != nil {
+		return nil, err
+	}
+	return lint.Lint(c), nil
+}
+
+// Fix applies the lint fixers to the contract at inputPath and writes the
+// result to outputPath atomically. outputPath may be inputPath. Nothing is
+// written when there is nothing to fix.
+func (s *LintService) Fix(inputPath, outputPath string) ([]lint.AppliedFix, error) {
+	c, err := s.ContractLoader(inputPath)
+	if err != nil {
+		return nil, err
+	}
+
+	fixed, applied := lint.Fix(c)
+	if len(applied) == 0 {
+		return nil, nil
+	}
+
+	data, err := marshalContract(fixed, EncodingUTF8)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal contract to YAML: %w", err)
+	}
+	if _, err := writeFileAtomic(outputPath, []byte(lintFixHeader+string(data))); err != nil {
+		return nil, fmt.Errorf("failed to write contract to '%s': %w", outputPath, err)
+	}
+	return applied, nil
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/contract"
+	"github.com/hiAndrewQuinn/cliguard/internal/lint"
+)
+
+func TestLintService_Fix(t *testing.T) {
+	dir := t.TempDir()
+	inputPath := filepath.Join(dir, "cliguard.yaml")
+	original := "use: app\nshort: my app.\nflags:\n  - name: dryRun\n    type: bool\n"
+	if err := os.WriteFile(inputPath, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+	outputPath := filepath.Join(dir, "fixed.yaml")
+
+	svc := NewLintService()
+	applied, err := svc.Fix(inputPath, outputPath)
+	if err != nil {
+		t.Fatalf("Fix() error = %v", err)
+	}
+	if len(applied) != 4 {
+		t.Errorf("Fix() applied %d fixes, want 4", len(applied))
+	}
+
+	data, err := os.ReadFile(inputPath)
+	if err != nil || string(data) != original {
+		t.Errorf("input file changed when writing to another output: %q", data)
+	}
+
+	fixed, err := contract.Load(outputPath)
+	if err != nil {
+		t.Fatalf("fixed contract does not parse: %v", err)
+	}
+	if fixed.Short != "My app" || fixed.Flags[0].Name != "dry-run" || fixed.Flags[0].Usage != lint.PlaceholderUsage {
+		t.Errorf("fixed contract = %+v", fixed)
+	}
+
+	warnings, err := svc.Lint(outputPath)
+	if err != nil || len(warnings) != 0 {
+		t.Errorf("Lint() of the fixed contract = %+v, %v; want no warnings", warnings, err)
+	}
+}
+
+func TestLintService_FixClean(t *testing.T) {
+	svc := &LintService{
+		ContractLoader: func(string) (*contract.Contract, error) {
+			return &contract.Contract{Use: "app", Short: "My app"}, nil
+		},
+	}
+	outputPath := filepath.Join(t.TempDir(), "fixed.yaml")
+
+	applied, err := svc.Fix("cliguard.yaml", outputPath)
+	if err != nil || len(applied) != 0 {
+		t.Errorf("Fix() = %v, %v; want no fixes", applied, err)
+	}
+	if _, err := os.Stat(outputPath); !os.IsNotExist(err) {
+		t.Error("Fix() should not write a contract with nothing to fix")
+	}
+}
+
+func TestLintService_LoadError(t *testing.T) {
+	svc := &LintService{
+		ContractLoader: func(string) (*contract.Contract, error) {
+			return nil, errors.New("file not found")
+		},
+	}
+	if _, err := svc.Lint("cliguard.yaml"); err == nil || !strings.Contains(err.Error(), "file not found") {
+		t.Errorf("Lint() error = %v, want the load error", err)
+	}
+	if _, err := svc.Fix("cliguard.yaml", "out.yaml"); err == nil {
+		t.Error("Fix() should return the load error")
+	}
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/contract"
+)
+
+// migrateHeader is the comment written at the top of migrated contracts
+const migrateHeader = `# Cliguard contract file (format v2)
+# Migrated with: cliguard migrate-contract
+#
+# Each entry under 'roots' is the contract of one root command, so a single
+# file can describe every CLI a repository builds. validate checks a CLI
+# against the root with the same 'use' (or the only root).
+#
+`
+
+// MigrateService converts contract files to the v2 format
+type MigrateService struct{}
+
+// NewMigrateService creates a new MigrateService
+func NewMigrateService() *MigrateService {
+	return &MigrateService{}
+}
+
+// Migrate reads the contract at inputPath and returns it in the v2 format
+// (see contract.MigrateV1ToV2). A v2 contract only has its missing version,
+// schema and tags fields populated, so migrating twice gives the same output.
+func (s *MigrateService) Migrate(inputPath string) (string, error) {
+	var v2 *contract.ContractV2
+	if contract.IsV2File(inputPath) {
+		loaded, err := contract.LoadV2(inputPath)
+		if err != nil {
+			return "", err
+		}
+		v2 = loaded
+		if v2.Version == "" {
+			v2.Version = contract.ContractV2Version
+		}
+		if v2.Schema == "" {
+			v2.Schema = contract.SchemaURLV2
+		}
+		if v2.Tags == nil {
+			v2.Tags = []string{}
+		}
+	} else {
+		v1, err := contract.Load(inputPath)
+		if err != nil {
+			return "", err
+		}
+		v2 = contract.MigrateV1ToV2(v1)
+	}
+
+	data, err := marshalContract(v2, EncodingUTF8)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal contract to YAML: %w", err)
+	}
+	return migrateHeader + string(data), nil
+}
+
+// MigrateToFile migrates the contract at inputPath and writes it to
+// outputPath atomically, like GenerateToFile. outputPath may be inputPath.
+func (s *MigrateService) MigrateToFile(inputPath, outputPath string) error {
+	content, err := s.Migrate(inputPath)
+	if err != nil {
+		return err
+	}
+
+	if _, err := writeFileAtomic(outputPath, []byte(content)); err != nil {
+		return fmt.Errorf("failed to write contract to '%s': %w", outputPath, err)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/contract"
+)
+
+func TestMigrateService_Migrate(t *testing.T) {
+	inputPath := filepath.Join("..", "..", "test-suite", "basic", "simple-cli", "contract.yaml")
+	v1, err := contract.Load(inputPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	svc := NewMigrateService()
+	outputPath := filepath.Join(t.TempDir(), "v2.yaml")
+	if err := svc.MigrateToFile(inputPath, outputPath); err != nil {
+		t.Fatalf("MigrateToFile() error = %v", err)
+	}
+
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "# Cliguard contract file (format v2)") {
+		t.Errorf("migrated contract should start with the v2 comment, got:\n%s", data)
+	}
+
+	v2, err := contract.LoadV2(outputPath)
+	if err != nil {
+		t.Fatalf("migrated contract does not parse: %v", err)
+	}
+	if v2.Version != contract.ContractV2Version || v2.Schema != contract.SchemaURLV2 {
+		t.Errorf("version, schema = %q, %q", v2.Version, v2.Schema)
+	}
+	if v2.Tags == nil || len(v2.Tags) != 0 {
+		t.Errorf("tags = %v, want empty", v2.Tags)
+	}
+	if !reflect.DeepEqual(v2.Roots[contract.DefaultRootName], v1) {
+		t.Errorf("roots.default = %+v, want %+v", v2.Roots[contract.DefaultRootName], v1)
+	}
+
+	// Migrating the output again changes nothing
+	again, err := svc.Migrate(outputPath)
+	if err != nil {
+		t.Fatalf("Migrate() of a v2 contract error = %v", err)
+	}
+	if again != string(data) {
+		t.Errorf("migration is not idempotent, got:\n%s\nwant:\n%s", again, data)
+	}
+}
+
+func TestMigrateService_MigrateFillsMissingV2Fields(t *testing.T) {
+	inputPath := filepath.Join(t.TempDir(), "v2.yaml")
+	if err := os.WriteFile(inputPath, []byte("roots:\n  app:\n    use: app\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := NewMigrateService().Migrate(inputPath)
+	if err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	for _, want := range []string{"version: 2.0.0", "schema: " + contract.SchemaURLV2, "tags: []", "  app:\n"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("migrated contract should contain %q, got:\n%s", want, content)
+		}
+	}
+}
+
+func TestMigrateService_MigrateMissingFile(t *testing.T) {
+	if _, err := NewMigrateService().Migrate(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("Migrate() of a missing file should fail")
+	}
+}
@@ -0,0 +1,39 @@
+package service
+
+import "time"
+
+// DefaultInspectorTimeout is the timeout for inspections that are given no
+// timeout of their own, so that a project whose build never finishes, e.g.
+// because a compiler error produces endless output, cannot hang the service
+const DefaultInspectorTimeout = 5 * time.Minute
+
+// ServiceOptions configures the services that inspect projects.
+//
+// Example:
+//
+//	svc := NewValidateServiceWithOptions(ServiceOptions{
+//	    InspectorTimeout: 10 * time.Minute,
+//	})
+type ServiceOptions struct {
+	// InspectorTimeout is the timeout for inspections when
+	// ValidateOptions.Timeout or GenerateOptions.Timeout is zero.
+	// Defaults to DefaultInspectorTimeout.
+	InspectorTimeout time.Duration
+}
+
+// withDefaults returns the options with unset fields set to their defaults
+func (o ServiceOptions) withDefaults() ServiceOptions {
+	if o.InspectorTimeout == 0 {
+		o.InspectorTimeout = DefaultInspectorTimeout
+	}
+	return o
+}
+
+// inspectionTimeout returns the timeout for an inspection: timeout, or the
+// service's inspectorTimeout if timeout is zero
+func inspectionTimeout(timeout, inspectorTimeout time.Duration) time.Duration {
+	if timeout == 0 {
+		return inspectorTimeout
+	}
+	return timeout
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/executor"
+	"github.com/hiAndrewQuinn/cliguard/internal/inspector"
+)
+
+// hangingExecutor runs commands that never finish unless their context is
+// cancelled, like a build stuck producing endless output
+type hangingExecutor struct{}
+
+func (hangingExecutor) Command(name string, args ...string) executor.Command {
+	return hangingCommand{ctx: context.Background()}
+}
+
+func (hangingExecutor) CommandContext(ctx context.Context, name string, args ...string) executor.Command {
+	return hangingCommand{ctx: ctx}
+}
+
+type hangingCommand struct {
+	ctx context.Context
+}
+
+func (hangingCommand) SetDir(string) {}
+
+func (c hangingCommand) Output() ([]byte, error) {
+	select {
+	case <-c.ctx.Done():
+		return nil, c.ctx.Err()
+	case <-time.After(10 * time.Second):
+		return nil, errors.New("command was not timed out")
+	}
+}
+
+func (c hangingCommand) CombinedOutput() ([]byte, error) {
+	return c.Output()
+}
+
+// writeTestProject writes a project with a go.mod and an empty contract
+func writeTestProject(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/app\n\ngo 1.24\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "cliguard.yaml"), []byte("use: app\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestServiceOptions_Defaults(t *testing.T) {
+	if got := NewValidateService().InspectorTimeout; got != DefaultInspectorTimeout {
+		t.Errorf("NewValidateService().InspectorTimeout = %v, want %v", got, DefaultInspectorTimeout)
+	}
+	if got := NewGenerateService().InspectorTimeout; got != DefaultInspectorTimeout {
+		t.Errorf("NewGenerateService().InspectorTimeout = %v, want %v", got, DefaultInspectorTimeout)
+	}
+	if got := NewValidateServiceWithOptions(ServiceOptions{InspectorTimeout: time.Minute}).InspectorTimeout; got != time.Minute {
+		t.Errorf("InspectorTimeout = %v, want 1m", got)
+	}
+}
+
+func TestValidateService_Validate_InspectorTimeout(t *testing.T) {
+	projectDir := writeTestProject(t)
+
+	svc := NewValidateServiceWithOptions(ServiceOptions{InspectorTimeout: 100 * time.Millisecond})
+	var gotTimeout time.Duration
+	svc.InspectorWithTimeout = func(projectPath, entrypoint string, timeout time.Duration) (*inspector.InspectedCLI, error) {
+		gotTimeout = timeout
+		return inspector.NewInspector(inspector.Config{
+			ProjectPath: projectPath,
+			Entrypoint:  entrypoint,
+			Timeout:     timeout,
+			Executor:    hangingExecutor{},
+		}).Inspect()
+	}
+	svc.Inspector = func(string, string) (*inspector.InspectedCLI, error) {
+		t.Fatal("Inspector called without a timeout")
+		return nil, nil
+	}
+
+	start := time.Now()
+	_, err := svc.Validate(ValidateOptions{
+		ProjectPath: projectDir,
+		Entrypoint:  "example.com/app/cmd.NewRootCmd",
+	})
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("Validate() error = %v, want a timeout error", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Validate() took %v, want it to stop after the 100ms timeout", elapsed)
+	}
+	if gotTimeout != 100*time.Millisecond {
+		t.Errorf("inspection timeout = %v, want the service's 100ms", gotTimeout)
+	}
+
+	// A timeout in the options takes precedence
+	_, _ = svc.Validate(ValidateOptions{
+		ProjectPath: projectDir,
+		Entrypoint:  "example.com/app/cmd.NewRootCmd",
+		Timeout:     200 * time.Millisecond,
+	})
+	if gotTimeout != 200*time.Millisecond {
+		t.Errorf("inspection timeout = %v, want ValidateOptions.Timeout 200ms", gotTimeout)
+	}
+}
+
+func TestGenerateService_Generate_InspectorTimeout(t *testing.T) {
+	svc := NewGenerateServiceWithOptions(ServiceOptions{InspectorTimeout: 100 * time.Millisecond})
+
+	start := time.Now()
+	_, err := svc.Generate(GenerateOptions{
+		ProjectPath: writeTestProject(t),
+		Entrypoint:  "example.com/app/cmd.NewRootCmd",
+		Executor:    hangingExecutor{},
+	})
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("Generate() error = %v, want a timeout error", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Generate() took %v, want it to stop after the 100ms timeout", elapsed)
+	}
+}
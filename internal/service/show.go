@@ -0,0 +1,124 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/contract"
+	"github.com/hiAndrewQuinn/cliguard/internal/discovery"
+	"github.com/hiAndrewQuinn/cliguard/internal/inspector"
+)
+
+// Output formats supported by ShowService
+const (
+	ShowFormatTree = "tree"
+	ShowFormatJSON = "json"
+)
+
+// ShowOptions contains options for the show command
+type ShowOptions struct {
+	ProjectPath string
+	Entrypoint  string
+	Timeout     time.Duration
+
+	// Format is either ShowFormatTree (default) or ShowFormatJSON
+	Format string
+}
+
+// ShowService renders the structure of a live CLI without a contract
+type ShowService struct {
+	// Inspector analyzes Go projects to extract CLI structure.
+	// Defaults to running inspector.NewInspector(config).Inspect()
+	Inspector func(inspector.Config) (*inspector.InspectedCLI, error)
+
+	// EnvFinder finds the environment variables the project's flags fall
+	// back to, keyed by flag name. Defaults to discovery.FindEnvBindings;
+	// if nil, none are shown.
+	EnvFinder func(projectPath string) (map[string]string, error)
+}
+
+// NewShowService creates a new ShowService with default dependencies
+func NewShowService() *ShowService {
+	return &ShowService{
+		Inspector: func(config inspector.Config) (*inspector.InspectedCLI, error) {
+			return inspector.NewInspector(config).Inspect()
+		},
+		EnvFinder: discovery.FindEnvBindings,
+	}
+}
+
+// Show inspects the CLI and renders it in the requested format. The tree
+// format matches contract.WriteTree so it can be compared against a contract
+// rendered the same way; the JSON format is the raw InspectedCLI.
+func (s *ShowService) Show(opts ShowOptions) (string, error) {
+	format := opts.Format
+	if format == "" {
+		format = ShowFormatTree
+	}
+	if format != ShowFormatTree && format != ShowFormatJSON {
+		return "", fmt.Errorf("unsupported format '%s' (expected %s or %s)", opts.Format, ShowFormatTree, ShowFormatJSON)
+	}
+
+	inspectedCLI, err := s.Inspector(inspector.Config{
+		ProjectPath: opts.ProjectPath,
+		Entrypoint:  opts.Entrypoint,
+		Timeout:     opts.Timeout,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to inspect project: %w", err)
+	}
+	if s.EnvFinder != nil {
+		bindings, err := s.EnvFinder(opts.ProjectPath)
+		if err != nil {
+			return "", fmt.Errorf("failed to find environment variable bindings: %w", err)
+		}
+		applyEnvBindings(inspectedCLI, bindings)
+	}
+
+	if format == ShowFormatJSON {
+		data, err := json.MarshalIndent(inspectedCLI, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal inspected CLI to JSON: %w", err)
+		}
+		return string(data) + "\n", nil
+	}
+
+	var buf bytes.Buffer
+	if err := contract.WriteTree(&buf, inspectedToDisplayContract(inspectedCLI)); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// inspectedToDisplayContract converts an InspectedCLI to a Contract for
+// rendering. Unlike GenerateService.inspectedToContract it keeps aliases and
+// hidden commands, since the goal is to show everything the CLI defines.
+func inspectedToDisplayContract(inspected *inspector.InspectedCLI) *contract.Contract {
+	generate := NewGenerateService()
+	return &contract.Contract{
+		Use:               inspected.Use,
+		Short:             inspected.Short,
+		Long:              inspected.Long,
+		Flags:             generate.inspectedFlagsToContractFlags(inspected.Flags),
+		Aliases:           inspected.Aliases,
+		Example:           inspected.Example,
+		Commands:          inspectedToDisplayCommands(generate, inspected.Commands),
+		MutuallyExclusive: inspected.MutuallyExclusive,
+		RequiredTogether:  inspected.RequiredTogether,
+	}
+}
+
+// inspectedToDisplayCommands converts inspected subcommands for rendering
+func inspectedToDisplayCommands(generate *GenerateService, commands []inspector.InspectedCommand) []contract.Command {
+	var result []contract.Command
+	for _, cmd := range commands {
+		converted := generate.inspectedCommandToContractCommand(cmd, nil, false)
+		converted.Aliases = cmd.Aliases
+		converted.Example = cmd.Example
+		converted.Commands = inspectedToDisplayCommands(generate, cmd.Commands)
+		result = append(result, converted)
+	}
+	return result
+}
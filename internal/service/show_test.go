@@ -0,0 +1,81 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/inspector"
+)
+
+func TestShowService_Show(t *testing.T) {
+	inspected := &inspector.InspectedCLI{
+		Use:   "myapp",
+		Short: "My application",
+		Flags: []inspector.InspectedFlag{
+			{Name: "verbose", Shorthand: "v", Type: "bool", Usage: "Verbose output", Persistent: true},
+		},
+		Commands: []inspector.InspectedCommand{
+			{Use: "serve", Short: "Start the server", Aliases: []string{"s"}},
+			{Use: "debug", Short: "Debugging tools", Hidden: true},
+		},
+	}
+
+	svc := &ShowService{
+		Inspector: func(config inspector.Config) (*inspector.InspectedCLI, error) {
+			if config.ProjectPath != "/test/project" || config.Entrypoint != "cmd.NewRootCmd" {
+				t.Errorf("unexpected inspector config: %+v", config)
+			}
+			return inspected, nil
+		},
+	}
+
+	t.Run("tree", func(t *testing.T) {
+		got, err := svc.Show(ShowOptions{ProjectPath: "/test/project", Entrypoint: "cmd.NewRootCmd"})
+		if err != nil {
+			t.Fatalf("Show() error = %v", err)
+		}
+		want := `myapp: My application
+├── --verbose, -v <bool> [persistent] Verbose output
+├── serve (aliases: s): Start the server
+└── debug [hidden]: Debugging tools
+`
+		if got != want {
+			t.Errorf("Show() =\n%s\nwant:\n%s", got, want)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		got, err := svc.Show(ShowOptions{ProjectPath: "/test/project", Entrypoint: "cmd.NewRootCmd", Format: ShowFormatJSON})
+		if err != nil {
+			t.Fatalf("Show() error = %v", err)
+		}
+		var decoded inspector.InspectedCLI
+		if err := json.Unmarshal([]byte(got), &decoded); err != nil {
+			t.Fatalf("Show() output is not valid JSON: %v", err)
+		}
+		if decoded.Use != "myapp" || len(decoded.Commands) != 2 || !decoded.Commands[1].Hidden {
+			t.Errorf("Show() JSON = %s", got)
+		}
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		_, err := svc.Show(ShowOptions{Format: "xml"})
+		if err == nil || !strings.Contains(err.Error(), "unsupported format") {
+			t.Errorf("Show() error = %v, want unsupported format error", err)
+		}
+	})
+
+	t.Run("inspection failure", func(t *testing.T) {
+		failing := &ShowService{
+			Inspector: func(inspector.Config) (*inspector.InspectedCLI, error) {
+				return nil, errors.New("build failed")
+			},
+		}
+		_, err := failing.Show(ShowOptions{})
+		if err == nil || !strings.Contains(err.Error(), "build failed") {
+			t.Errorf("Show() error = %v, want inspection error", err)
+		}
+	})
+}
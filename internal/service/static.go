@@ -0,0 +1,54 @@
+package service
+
+import (
+	"github.com/hiAndrewQuinn/cliguard/internal/contract"
+)
+
+// staticContract returns a copy of the contract without what static
+// inspection doesn't find (see inspector.StaticInspector): flag defaults,
+// completions and enum values, hidden flags, and flag groups. Validating the rest against
+// a statically inspected CLI then only reports real differences. The
+// contract is not modified.
+func staticContract(c *contract.Contract) *contract.Contract {
+	reduced := *c
+	reduced.Flags = staticFlags(c.Flags)
+	reduced.MutuallyExclusive = nil
+	reduced.RequiredTogether = nil
+	reduced.Commands = staticCommands(c.Commands)
+	return &reduced
+}
+
+// staticCommands is staticContract for subcommands, recursively
+func staticCommands(commands []contract.Command) []contract.Command {
+	if commands == nil {
+		return nil
+	}
+	result := make([]contract.Command, len(commands))
+	for i, cmd := range commands {
+		cmd.Flags = staticFlags(cmd.Flags)
+		cmd.MutuallyExclusive = nil
+		cmd.RequiredTogether = nil
+		cmd.Commands = staticCommands(cmd.Commands)
+		result[i] = cmd
+	}
+	return result
+}
+
+// staticFlags returns copies of the flags that aren't hidden, without
+// their defaults, completions and enum values
+func staticFlags(flags []contract.Flag) []contract.Flag {
+	if flags == nil {
+		return nil
+	}
+	result := make([]contract.Flag, 0, len(flags))
+	for _, flag := range flags {
+		if flag.Hidden {
+			continue
+		}
+		flag.Default = ""
+		flag.Completion = ""
+		flag.Enum = nil
+		result = append(result, flag)
+	}
+	return result
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/contract"
+)
+
+// subcontract is a fragment written next to the root contract by
+// GenerateOptions.GroupBySubpackage
+type subcontract struct {
+	// file is the fragment's file name, in the root contract's directory
+	file     string
+	fragment *contract.Fragment
+}
+
+// packageGrouper splits a contract into fragments by the Go package that
+// defines each command
+type packageGrouper struct {
+	// packages maps command names to the packages that define them (see
+	// discovery.FindCommandPackages)
+	packages map[string][]string
+
+	subcontracts []*subcontract
+	fileNames    map[string]int
+}
+
+// groupBySubpackage moves each command defined in another Go package than
+// its parent command into a fragment, one per package and parent command,
+// which the parent includes. A command whose package is unknown or
+// ambiguous stays with its parent. The returned contract is a copy; c is
+// not modified.
+func groupBySubpackage(c *contract.Contract, packages map[string][]string) (*contract.Contract, []*subcontract) {
+	g := &packageGrouper{packages: packages, fileNames: make(map[string]int)}
+	root := *c
+	root.Commands, root.Include = g.split(c.Commands, g.packageOf(contract.CommandName(c.Use), ""))
+	return &root, g.subcontracts
+}
+
+// split returns the commands defined in parentPkg, and includes of fragments
+// holding the others, splitting their subcommands in turn
+func (g *packageGrouper) split(commands []contract.Command, parentPkg string) ([]contract.Command, []contract.Include) {
+	var kept []contract.Command
+	var includes []contract.Include
+	byPackage := make(map[string]*subcontract)
+
+	for _, cmd := range commands {
+		pkg := g.packageOf(contract.CommandName(cmd.Use), parentPkg)
+		cmd.Commands, cmd.Include = g.split(cmd.Commands, pkg)
+		if pkg == parentPkg {
+			kept = append(kept, cmd)
+			continue
+		}
+
+		sub := byPackage[pkg]
+		if sub == nil {
+			sub = &subcontract{file: g.fileName(pkg), fragment: &contract.Fragment{}}
+			byPackage[pkg] = sub
+			g.subcontracts = append(g.subcontracts, sub)
+			includes = append(includes, contract.Include{Ref: "./" + sub.file})
+		}
+		sub.fragment.Commands = append(sub.fragment.Commands, cmd)
+	}
+	return kept, includes
+}
+
+// packageOf returns the package that defines the command named name, under
+// a parent command defined in parentPkg. Commands with the same name in
+// several packages, such as "list", are taken to be in the parent's package
+// if it is one of them.
+func (g *packageGrouper) packageOf(name, parentPkg string) string {
+	candidates := g.packages[name]
+	for _, pkg := range candidates {
+		if pkg == parentPkg {
+			return parentPkg
+		}
+	}
+	if len(candidates) == 1 {
+		return candidates[0]
+	}
+	return parentPkg
+}
+
+// fileName returns the fragment file name for pkg, named after the last
+// element of its path, e.g. "db-contract.yaml" for "cmd/db". Later
+// fragments with the same name are numbered: "db-2-contract.yaml".
+func (g *packageGrouper) fileName(pkg string) string {
+	name := path.Base(pkg)
+	if name == "." || name == "/" {
+		name = "main"
+	}
+	g.fileNames[name]++
+	if n := g.fileNames[name]; n > 1 {
+		name = fmt.Sprintf("%s-%d", name, n)
+	}
+	return name + "-contract.yaml"
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/contract"
+)
+
+func TestGroupBySubpackage(t *testing.T) {
+	c := &contract.Contract{
+		Use:   "app",
+		Short: "App",
+		Commands: []contract.Command{
+			{Use: "db", Short: "Database", Commands: []contract.Command{
+				{Use: "list", Short: "List tables"},
+				{Use: "backup [file]", Short: "Back up"},
+			}},
+			{Use: "user", Short: "Users", Commands: []contract.Command{
+				{Use: "list", Short: "List users"},
+			}},
+			{Use: "version", Short: "Version"},
+			{Use: "completion", Short: "Generated by Cobra"},
+			{Use: "sql", Short: "Another db package"},
+		},
+	}
+	packages := map[string][]string{
+		"app":     {"cmd"},
+		"version": {"cmd"},
+		"db":      {"cmd/db"},
+		"list":    {"cmd/db", "cmd/user"},
+		"backup":  {"cmd/db/backup"},
+		"user":    {"cmd/user"},
+		"sql":     {"internal/db"},
+	}
+
+	root, subcontracts := groupBySubpackage(c, packages)
+
+	wantRoot := &contract.Contract{
+		Use:   "app",
+		Short: "App",
+		Commands: []contract.Command{
+			{Use: "version", Short: "Version"},
+			{Use: "completion", Short: "Generated by Cobra"},
+		},
+		Include: []contract.Include{
+			{Ref: "./db-contract.yaml"},
+			{Ref: "./user-contract.yaml"},
+			{Ref: "./db-2-contract.yaml"},
+		},
+	}
+	if !reflect.DeepEqual(root, wantRoot) {
+		t.Errorf("root = %+v, want %+v", root, wantRoot)
+	}
+
+	files := make(map[string][]contract.Command)
+	for _, sub := range subcontracts {
+		files[sub.file] = sub.fragment.Commands
+	}
+	wantFiles := map[string][]contract.Command{
+		"backup-contract.yaml": {{Use: "backup [file]", Short: "Back up"}},
+		"db-contract.yaml": {{Use: "db", Short: "Database",
+			Commands: []contract.Command{{Use: "list", Short: "List tables"}},
+			Include:  []contract.Include{{Ref: "./backup-contract.yaml"}},
+		}},
+		"user-contract.yaml": {{Use: "user", Short: "Users",
+			Commands: []contract.Command{{Use: "list", Short: "List users"}},
+		}},
+		"db-2-contract.yaml": {{Use: "sql", Short: "Another db package"}},
+	}
+	if !reflect.DeepEqual(files, wantFiles) {
+		t.Errorf("fragments = %+v, want %+v", files, wantFiles)
+	}
+
+	if len(c.Commands) != 5 || len(c.Commands[0].Commands) != 2 {
+		t.Errorf("groupBySubpackage() modified its input: %+v", c.Commands)
+	}
+}
+
+func TestGroupBySubpackage_SinglePackage(t *testing.T) {
+	c := &contract.Contract{Use: "app", Commands: []contract.Command{{Use: "serve"}}}
+	root, subcontracts := groupBySubpackage(c, map[string][]string{"app": {"."}, "serve": {"."}})
+	if len(subcontracts) != 0 || root.Include != nil || len(root.Commands) != 1 {
+		t.Errorf("groupBySubpackage() = %+v, %d fragments; want the contract unchanged", root, len(subcontracts))
+	}
+}
+
+func TestGenerateService_Generate_GroupBySubpackageRequiresFile(t *testing.T) {
+	_, err := NewGenerateService().Generate(GenerateOptions{ProjectPath: t.TempDir(), GroupBySubpackage: true})
+	if err == nil || err.Error() != "--group-by-subpackage requires --output-file" {
+		t.Errorf("Generate() error = %v, want --output-file required", err)
+	}
+
+	_, err = NewGenerateService().GenerateToFile(GenerateOptions{ProjectPath: t.TempDir(), GroupBySubpackage: true, ContractVersion: 2}, "cliguard.yaml")
+	if err == nil || err.Error() != "--group-by-subpackage only supports contract version 1" {
+		t.Errorf("GenerateToFile() error = %v, want v2 rejected", err)
+	}
+}
@@ -0,0 +1,220 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/contract"
+)
+
+// UpdateOptions contains options for the update command
+type UpdateOptions struct {
+	// ProjectPath is the path to the Go project of the CLI (required)
+	ProjectPath string
+
+	// ContractPath is the contract file to update (optional).
+	// If empty, defaults to "cliguard.yaml" in the project directory.
+	ContractPath string
+
+	// Entrypoint is the function that creates the root command (required)
+	Entrypoint string
+
+	// Timeout for CLI inspection (optional)
+	Timeout time.Duration
+}
+
+// UpdateResult is a contract regenerated from the CLI, merged with the
+// contract it updates
+type UpdateResult struct {
+	// ContractPath is the absolute path of the contract
+	ContractPath string
+
+	// Updated is the merged contract
+	Updated *contract.Contract
+
+	// Diff lists the changes from the existing contract to Updated
+	Diff *DiffResult
+
+	// content is the contract file to write
+	content string
+}
+
+// Changed reports whether the updated contract differs from the existing one
+func (r *UpdateResult) Changed() bool {
+	return !r.Diff.Identical()
+}
+
+// UpdateService regenerates a contract from the CLI it describes
+type UpdateService struct {
+	// ContractLoader loads the existing contract. Defaults to contract.Load
+	ContractLoader func(string) (*contract.Contract, error)
+
+	// Generator builds the contract of the CLI and renders its header.
+	// Defaults to the generation of GenerateService.
+	Generator func(opts GenerateOptions) (string, *contract.Contract, error)
+}
+
+// NewUpdateService creates a new UpdateService with default dependencies
+func NewUpdateService() *UpdateService {
+	return &UpdateService{
+		ContractLoader: contract.Load,
+		Generator:      NewGenerateService().generate,
+	}
+}
+
+// Update generates the current contract of the CLI and merges it with the
+// existing contract, without writing it. The CLI's commands and flags
+// replace the contract's, but the fields generate never writes, the root
+// version and each command's sort_order, are kept. The contract is
+// generated with the options it appears to have been generated with: with
+// hidden commands or flags, enum values, runnable fields, Cobra's help and
+// completion commands or argument patterns in use fields if it has any.
+//
+// Contracts that include other files or extend commands, and v2 contracts,
+// can't be updated, since writing them back would lose their layout.
+func (s *UpdateService) Update(opts UpdateOptions) (*UpdateResult, error) {
+	absProjectPath, err := filepath.Abs(opts.ProjectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve project path '%s': %w", opts.ProjectPath, err)
+	}
+	contractPath := opts.ContractPath
+	if contractPath == "" {
+		contractPath = filepath.Join(absProjectPath, "cliguard.yaml")
+	}
+	contractPath, err = filepath.Abs(contractPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve contract path '%s': %w", opts.ContractPath, err)
+	}
+
+	if contract.IsV2File(contractPath) {
+		return nil, fmt.Errorf("update does not support v2 contracts; regenerate %s with generate --output-contract-version 2", contractPath)
+	}
+	data, err := os.ReadFile(contractPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read contract: %w", err)
+	}
+	references, err := contract.UsesReferences(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse contract %s: %w", contractPath, err)
+	}
+	if references {
+		return nil, fmt.Errorf("%s includes other files or extends commands; update would inline them, so regenerate it with generate instead", contractPath)
+	}
+
+	existing, err := s.ContractLoader(contractPath)
+	if err != nil {
+		return nil, err
+	}
+
+	header, updated, err := s.Generator(updateGenerateOptions(existing, absProjectPath, opts))
+	if err != nil {
+		return nil, err
+	}
+	keepManualFields(updated, existing)
+
+	content, err := render(header, updated, "")
+	if err != nil {
+		return nil, err
+	}
+	return &UpdateResult{
+		ContractPath: contractPath,
+		Updated:      updated,
+		Diff:         diffContracts(existing, updated, contractPath, opts.Entrypoint),
+		content:      content,
+	}, nil
+}
+
+// Write writes the updated contract to its file
+func (s *UpdateService) Write(result *UpdateResult) error {
+	if _, err := writeFileAtomic(result.ContractPath, []byte(result.content)); err != nil {
+		return fmt.Errorf("failed to write contract to '%s': %w", result.ContractPath, err)
+	}
+	return nil
+}
+
+// updateGenerateOptions returns the options to regenerate the existing
+// contract with, inferred from its contents
+func updateGenerateOptions(existing *contract.Contract, absProjectPath string, opts UpdateOptions) GenerateOptions {
+	return GenerateOptions{
+		ProjectPath:            absProjectPath,
+		Entrypoint:             opts.Entrypoint,
+		Timeout:                opts.Timeout,
+		IncludeHiddenCommands:  commandsHaveHidden(existing.Commands),
+		IncludeHiddenFlags:     contractHasHiddenFlags(existing),
+		WithValidation:         contractHasEnums(existing),
+		RunnableOnly:           commandsHaveRunnable(existing.Commands),
+		StripHelpCommand:       !hasCommandNamed(existing.Commands, "help"),
+		StripCompletionCommand: !hasCommandNamed(existing.Commands, "completion"),
+		UseNameOnly:            !commandsHaveUsePatterns(existing.Commands),
+	}
+}
+
+// keepManualFields copies the fields generate never writes from the existing
+// contract to the updated one, matching commands by name
+func keepManualFields(updated, existing *contract.Contract) {
+	if updated.Version == "" {
+		updated.Version = existing.Version
+	}
+	keepCommandSortOrders(updated.Commands, existing.Commands)
+}
+
+// keepCommandSortOrders copies the sort orders of the existing commands, and
+// of their subcommands, to the updated commands of the same names
+func keepCommandSortOrders(updated, existing []contract.Command) {
+	byName := make(map[string]*contract.Command)
+	for i := range existing {
+		byName[contract.CommandName(existing[i].Use)] = &existing[i]
+	}
+	for i := range updated {
+		if old, found := byName[contract.CommandName(updated[i].Use)]; found {
+			updated[i].SortOrder = old.SortOrder
+			keepCommandSortOrders(updated[i].Commands, old.Commands)
+		}
+	}
+}
+
+// commandsHaveHidden reports whether any of the commands or their
+// subcommands is hidden
+func commandsHaveHidden(commands []contract.Command) bool {
+	for _, cmd := range commands {
+		if cmd.Hidden || commandsHaveHidden(cmd.Commands) {
+			return true
+		}
+	}
+	return false
+}
+
+// commandsHaveRunnable reports whether any of the commands or their
+// subcommands has a runnable field, as generate --runnable-only writes
+func commandsHaveRunnable(commands []contract.Command) bool {
+	for _, cmd := range commands {
+		if cmd.Runnable != nil || commandsHaveRunnable(cmd.Commands) {
+			return true
+		}
+	}
+	return false
+}
+
+// commandsHaveUsePatterns reports whether the use field of any of the
+// commands or their subcommands has an argument pattern after the name
+func commandsHaveUsePatterns(commands []contract.Command) bool {
+	for _, cmd := range commands {
+		if len(strings.Fields(cmd.Use)) > 1 || commandsHaveUsePatterns(cmd.Commands) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasCommandNamed reports whether commands has a command named name
+func hasCommandNamed(commands []contract.Command, name string) bool {
+	for _, cmd := range commands {
+		if contract.CommandName(cmd.Use) == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/contract"
+)
+
+// writeUpdateContract writes content as the cliguard.yaml of a new project
+// directory and returns the directory
+func writeUpdateContract(t *testing.T, content string) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "cliguard.yaml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestUpdateService_Update(t *testing.T) {
+	dir := writeUpdateContract(t, `# A hand-edited contract
+use: app
+short: An app
+version: 1.2.0
+commands:
+  - use: serve
+    short: Serve the app
+    sort_order: 2
+    flags:
+      - name: port
+        usage: Port to listen on
+        type: int
+        enum: ["80", "443"]
+  - use: seed
+    short: Seed the database
+`)
+
+	var gotOpts GenerateOptions
+	svc := &UpdateService{
+		ContractLoader: contract.Load,
+		Generator: func(opts GenerateOptions) (string, *contract.Contract, error) {
+			gotOpts = opts
+			return "# Generated by cliguard\n", &contract.Contract{
+				Use:   "app",
+				Short: "An app",
+				Commands: []contract.Command{
+					{Use: "serve", Short: "Serve the app", Flags: []contract.Flag{
+						{Name: "port", Usage: "Port to listen on", Type: "int", Enum: []string{"80", "443"}},
+						{Name: "host", Usage: "Host to bind", Type: "string"},
+					}},
+					{Use: "version", Short: "Print the version"},
+				},
+			}, nil
+		},
+	}
+
+	result, err := svc.Update(UpdateOptions{ProjectPath: dir, Entrypoint: "cmd.NewRootCmd"})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	if gotOpts.Entrypoint != "cmd.NewRootCmd" || !gotOpts.WithValidation || !gotOpts.UseNameOnly || !gotOpts.StripHelpCommand || gotOpts.IncludeHiddenFlags {
+		t.Errorf("generate options = %+v, want enums inspected and use names only", gotOpts)
+	}
+	if !result.Changed() {
+		t.Fatal("Changed() = false, want the new command and flag")
+	}
+	paths := func(entries []DiffEntry) string {
+		var names []string
+		for _, entry := range entries {
+			names = append(names, entry.Path)
+		}
+		return strings.Join(names, ",")
+	}
+	if got := paths(result.Diff.Added); got != "serve --host,version" {
+		t.Errorf("added = %s, want serve --host,version", got)
+	}
+	if got := paths(result.Diff.Removed); got != "seed" {
+		t.Errorf("removed = %s, want seed", got)
+	}
+	if len(result.Diff.Modified) != 0 {
+		t.Errorf("modified = %+v, want the kept version not reported", result.Diff.Modified)
+	}
+
+	if err := svc.Write(result); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	updated, err := contract.Load(filepath.Join(dir, "cliguard.yaml"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if updated.Version != "1.2.0" || updated.Commands[0].SortOrder != 2 {
+		t.Errorf("updated contract = %+v, want the version and sort order kept", updated)
+	}
+	if len(updated.Commands) != 2 || updated.Commands[1].Use != "version" {
+		t.Errorf("updated commands = %+v, want serve and version", updated.Commands)
+	}
+}
+
+func TestUpdateService_Update_Unchanged(t *testing.T) {
+	dir := writeUpdateContract(t, "use: app\nshort: An app\ncommands:\n  - use: serve [port]\n    short: Serve the app\n")
+
+	var gotOpts GenerateOptions
+	svc := &UpdateService{
+		ContractLoader: contract.Load,
+		Generator: func(opts GenerateOptions) (string, *contract.Contract, error) {
+			gotOpts = opts
+			return "", &contract.Contract{Use: "app", Short: "An app", Commands: []contract.Command{{Use: "serve [port]", Short: "Serve the app"}}}, nil
+		},
+	}
+
+	result, err := svc.Update(UpdateOptions{ProjectPath: dir, Entrypoint: "cmd.NewRootCmd"})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if result.Changed() {
+		t.Errorf("Changed() = true, diff = %+v", result.Diff)
+	}
+	if gotOpts.UseNameOnly {
+		t.Error("UseNameOnly = true, want the argument patterns of the contract kept")
+	}
+}
+
+func TestUpdateService_Update_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		contract string
+		wantErr  string
+	}{
+		{
+			name:     "includes",
+			contract: "use: app\nshort: An app\ninclude:\n  - $ref: ./db-contract.yaml\n",
+			wantErr:  "includes other files or extends commands",
+		},
+		{
+			name:     "extends",
+			contract: "use: app\nshort: An app\ncommands:\n  - use: a\n    short: A\n    extends: b\n  - use: b\n    short: B\n",
+			wantErr:  "includes other files or extends commands",
+		},
+		{
+			name:     "v2",
+			contract: "version: 2.0.0\nroots:\n  app:\n    use: app\n    short: An app\n",
+			wantErr:  "does not support v2 contracts",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writeUpdateContract(t, tt.contract)
+			svc := &UpdateService{
+				ContractLoader: contract.Load,
+				Generator: func(opts GenerateOptions) (string, *contract.Contract, error) {
+					t.Fatal("the CLI should not be inspected")
+					return "", nil, nil
+				},
+			}
+			_, err := svc.Update(UpdateOptions{ProjectPath: dir, Entrypoint: "cmd.NewRootCmd"})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Update() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	_, err := NewUpdateService().Update(UpdateOptions{ProjectPath: t.TempDir(), Entrypoint: "cmd.NewRootCmd"})
+	if err == nil || !strings.Contains(err.Error(), "failed to read contract") {
+		t.Errorf("Update() error = %v, want the missing contract reported", err)
+	}
+}
@@ -4,9 +4,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/hiAndrewQuinn/cliguard/internal/contract"
+	"github.com/hiAndrewQuinn/cliguard/internal/discovery"
 	"github.com/hiAndrewQuinn/cliguard/internal/errors"
 	"github.com/hiAndrewQuinn/cliguard/internal/inspector"
 	"github.com/hiAndrewQuinn/cliguard/internal/validator"
@@ -26,6 +29,11 @@ type ValidateService struct {
 	// Defaults to contract.Load
 	ContractLoader func(string) (*contract.Contract, error)
 
+	// ContractLoaderV2 loads multi-root (v2) contracts, which are detected by
+	// a top-level 'roots' key. Defaults to contract.LoadV2; if nil, all
+	// contracts are loaded with ContractLoader.
+	ContractLoaderV2 func(string) (*contract.ContractV2, error)
+
 	// Inspector analyzes Go projects to extract CLI structure.
 	// Defaults to inspector.InspectProject
 	Inspector func(string, string) (*inspector.InspectedCLI, error)
@@ -33,6 +41,31 @@ type ValidateService struct {
 	// InspectorWithTimeout analyzes Go projects with timeout support.
 	// Defaults to inspector.InspectProjectWithTimeout
 	InspectorWithTimeout func(string, string, time.Duration) (*inspector.InspectedCLI, error)
+
+	// InspectorWithConfig analyzes Go projects with full inspector options.
+	// It is used instead of the inspectors above when the contract lists
+	// flag enum values, which requires running the project's completion
+	// functions. Defaults to inspector.NewInspector(config).Inspect
+	InspectorWithConfig func(inspector.Config) (*inspector.InspectedCLI, error)
+
+	// StaticInspector reads the CLI structure from the project source
+	// without running it, for ValidateOptions.Static. Defaults to
+	// inspector.InspectProjectStatic
+	StaticInspector func(string, string, time.Duration) (*inspector.InspectedCLI, error)
+
+	// SnapshotLoader reads the CLI snapshot of ValidateOptions.CLISnapshot.
+	// Defaults to inspector.LoadSnapshot
+	SnapshotLoader func(string) (*inspector.InspectedCLI, error)
+
+	// FieldChecker finds fields in the contract file that the contract
+	// format does not define, or with strict, fails on them. Defaults to
+	// contract.CheckFields; if nil, fields are not checked.
+	FieldChecker func(path string, strict bool) ([]contract.UnknownField, error)
+
+	// InspectorTimeout is the timeout for inspections when
+	// ValidateOptions.Timeout is zero. If it is zero too, inspections have
+	// no timeout.
+	InspectorTimeout time.Duration
 }
 
 // NewValidateService creates a new validation service with default dependencies.
@@ -46,10 +79,25 @@ type ValidateService struct {
 //	    Entrypoint:   "cmd.NewRootCmd",
 //	})
 func NewValidateService() *ValidateService {
+	return NewValidateServiceWithOptions(ServiceOptions{})
+}
+
+// NewValidateServiceWithOptions creates a new validation service with
+// default dependencies, configured by opts
+func NewValidateServiceWithOptions(opts ServiceOptions) *ValidateService {
+	opts = opts.withDefaults()
 	return &ValidateService{
 		ContractLoader:       contract.Load,
+		ContractLoaderV2:     contract.LoadV2,
 		Inspector:            inspector.InspectProject,
 		InspectorWithTimeout: inspector.InspectProjectWithTimeout,
+		InspectorWithConfig: func(config inspector.Config) (*inspector.InspectedCLI, error) {
+			return inspector.NewInspector(config).Inspect()
+		},
+		StaticInspector:  inspector.InspectProjectStatic,
+		SnapshotLoader:   inspector.LoadSnapshot,
+		FieldChecker:     contract.CheckFields,
+		InspectorTimeout: opts.InspectorTimeout,
 	}
 }
 
@@ -79,6 +127,106 @@ type ValidateOptions struct {
 	// Timeout for CLI inspection (optional).
 	// If zero, no timeout is applied.
 	Timeout time.Duration
+
+	// CompletionsOnly restricts validation to flag shell completions
+	// (see validator.ValidateCompletions).
+	CompletionsOnly bool
+
+	// StrictSortOrder checks that commands are listed in the order given by
+	// their sort_order in the contract (see validator.Options).
+	StrictSortOrder bool
+
+	// ExpandedContract compares against a contract generated with
+	// GenerateOptions.ExpandPersistentFlags (see validator.Options).
+	ExpandedContract bool
+
+	// ContractEntrypoint generates the contract from a second entrypoint in
+	// the project, usually a previous version of the CLI, instead of loading
+	// it from ContractPath (optional). The CLI at Entrypoint is validated
+	// against it.
+	ContractEntrypoint string
+
+	// Flip swaps the CLIs compared with ContractEntrypoint: the contract is
+	// generated from Entrypoint and the CLI at ContractEntrypoint is
+	// validated against it.
+	Flip bool
+
+	// StrictContract fails validation if the contract has fields the
+	// contract format does not define. Otherwise they are listed in
+	// ValidateResult.UnknownFields.
+	StrictContract bool
+
+	// UseNameOnly compares only the command names of the Use fields of the
+	// contract and the CLI, ignoring their argument patterns, so that
+	// rewriting "create [resource]" as "create <resource>" is no difference.
+	// Contracts generated with GenerateOptions.UseNameOnly have no argument
+	// patterns.
+	UseNameOnly bool
+
+	// ExpectVersion runs the CLI's version command and checks that the
+	// version it prints is the contract's version field (see
+	// validator.Options).
+	ExpectVersion bool
+
+	// VersionCommand is the arguments the version command is run with for
+	// ExpectVersion, e.g. "version --short". If empty, the CLI is run with
+	// --version if it has a version, and with its version subcommand
+	// otherwise.
+	VersionCommand string
+
+	// IgnoreShort skips comparing the short descriptions of the commands
+	// (see validator.Options).
+	IgnoreShort bool
+
+	// IgnoreLong skips comparing the long descriptions of the commands
+	// (see validator.Options).
+	IgnoreLong bool
+
+	// IgnoreCommandsRegex are RE2 patterns of commands to leave out of
+	// validation, with their subcommands, in both the contract and the CLI
+	// (optional). Each is matched against the full path of every command:
+	// the names of the root command and the commands leading to it,
+	// separated by spaces, e.g. "myapp db migrate". Patterns are not
+	// anchored, so "debug" ignores every command with debug in its path.
+	IgnoreCommandsRegex []string
+
+	// FailFast stops validation at the first error (see validator.Options)
+	FailFast bool
+
+	// AllowExtraCommands and AllowExtraFlags don't fail validation on
+	// commands or flags of the CLI that the contract doesn't list. They are
+	// still among the errors of ValidateResult, but Success is true if they
+	// are the only ones (see FailingErrors).
+	AllowExtraCommands bool
+	AllowExtraFlags    bool
+
+	// FocusPaths limits validation to the commands at these dot-separated
+	// command paths, e.g. "create.user", and their subcommands (see
+	// validator.Options). Other subtrees of the contract are skipped.
+	FocusPaths []string
+
+	// StrictMode validates the contract as the complete set of commands and
+	// flags (see validator.Options.Strict): hidden and deprecated ones, and
+	// commands that run nothing, fail validation too unless it lists them.
+	// Hidden flags are inspected for it, which runs the project's code.
+	StrictMode bool
+
+	// WarnOnly reports the validation errors as warnings: ValidateResult
+	// lists them as usual, but Success is true. It is meant for migration
+	// periods, when a CLI is brought in line with its contract step by step.
+	WarnOnly bool
+
+	// CLISnapshot is the path of a CLI snapshot, the JSON written by
+	// GenerateOptions.WriteSnapshot or cliguard inspect, to validate
+	// instead of inspecting the project (optional). The project is not
+	// built, so no Go toolchain is needed.
+	CLISnapshot string
+
+	// Static reads the CLI structure from the project source instead of
+	// building and running it (see inspector.StaticInspector). The flag
+	// defaults, completions, enum values and flag groups of the contract
+	// are not validated, since static inspection doesn't find them.
+	Static bool
 }
 
 // ValidateResult contains the result of validation.
@@ -103,6 +251,23 @@ type ValidateResult struct {
 	// Error contains any error that prevented validation from running
 	// (different from validation failures)
 	Error error
+
+	// ContractPath is the absolute path of the contract that was validated.
+	// It is empty if the contract was generated from
+	// ValidateOptions.ContractEntrypoint.
+	ContractPath string
+
+	// RootName is the name of the root validated against, for v2 contracts
+	RootName string
+
+	// Use is the use field of the root command of the contract validated
+	// against
+	Use string
+
+	// UnknownFields are the fields in the contract file that the contract
+	// format does not define, and so were ignored, and those it loaded under
+	// a deprecated name
+	UnknownFields []contract.UnknownField
 }
 
 // Validate performs the validation by loading the contract, inspecting the CLI,
@@ -136,6 +301,11 @@ type ValidateResult struct {
 // build failure). Validation failures are indicated by Success=false in the
 // result, not by returning an error.
 func (s *ValidateService) Validate(opts ValidateOptions) (*ValidateResult, error) {
+	ignore, err := compileIgnorePatterns(opts.IgnoreCommandsRegex)
+	if err != nil {
+		return nil, err
+	}
+
 	// Resolve project path
 	absProjectPath, err := filepath.Abs(opts.ProjectPath)
 	if err != nil {
@@ -147,11 +317,42 @@ func (s *ValidateService) Validate(opts ValidateOptions) (*ValidateResult, error
 		return nil, errors.ProjectNotFoundError{Path: absProjectPath}
 	}
 
+	if opts.CLISnapshot != "" {
+		switch {
+		case opts.ContractEntrypoint != "":
+			return nil, fmt.Errorf("--cli-snapshot cannot be used with --contract-from-entrypoint")
+		case opts.ExpectVersion:
+			// The version is checked by running the CLI
+			return nil, fmt.Errorf("--expect-version cannot be used with --cli-snapshot")
+		}
+	}
+
+	if opts.Static {
+		switch {
+		case opts.CLISnapshot != "":
+			return nil, fmt.Errorf("--static cannot be used with --cli-snapshot")
+		case opts.ExpectVersion:
+			return nil, fmt.Errorf("--expect-version runs the CLI; it cannot be used with --static")
+		case opts.CompletionsOnly:
+			return nil, fmt.Errorf("--completions-only cannot be used with --static, which doesn't find flag completions")
+		}
+	}
+
+	if opts.ContractEntrypoint != "" {
+		if opts.ContractPath != "" {
+			return nil, fmt.Errorf("--contract and --contract-from-entrypoint cannot be used together")
+		}
+		if opts.ExpectVersion {
+			return nil, fmt.Errorf("--expect-version cannot be used with --contract-from-entrypoint")
+		}
+		return s.validateEntrypoints(absProjectPath, opts, ignore)
+	}
+
 	// Determine contract path
 	contractPath := opts.ContractPath
 	if contractPath == "" {
 		contractPath = filepath.Join(absProjectPath, "cliguard.yaml")
-	} else {
+	} else if !contract.IsURL(contractPath) {
 		contractPath, err = filepath.Abs(contractPath)
 		if err != nil {
 			return nil, fmt.Errorf("failed to resolve contract path: %w", err)
@@ -159,34 +360,282 @@ func (s *ValidateService) Validate(opts ValidateOptions) (*ValidateResult, error
 	}
 
 	// Load the contract
-	contractSpec, err := s.ContractLoader(contractPath)
+	var contractSpec *contract.Contract
+	var contractV2 *contract.ContractV2
+	if s.ContractLoaderV2 != nil && contract.IsV2File(contractPath) {
+		contractV2, err = s.ContractLoaderV2(contractPath)
+	} else {
+		contractSpec, err = s.ContractLoader(contractPath)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to load contract: %w", err)
 	}
 
-	// Inspect the project
+	// Check for fields the contract format doesn't define, before spending
+	// time on inspection
+	var unknownFields []contract.UnknownField
+	if s.FieldChecker != nil {
+		unknownFields, err = s.FieldChecker(contractPath, opts.StrictContract)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load contract: %w", err)
+		}
+	}
+
+	// Inspect the project, or read the snapshot of a previous inspection
 	var actualStructure *inspector.InspectedCLI
-	
-	if opts.Timeout > 0 {
-		actualStructure, err = s.InspectorWithTimeout(absProjectPath, opts.Entrypoint, opts.Timeout)
+	if opts.CLISnapshot != "" {
+		actualStructure, err = s.SnapshotLoader(opts.CLISnapshot)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load CLI snapshot: %w", err)
+		}
 	} else {
-		actualStructure, err = s.Inspector(absProjectPath, opts.Entrypoint)
+		actualStructure, err = s.inspect(inspector.Config{
+			ProjectPath:      absProjectPath,
+			Entrypoint:       opts.Entrypoint,
+			Timeout:          opts.Timeout,
+			CompletionValues: contractHasEnums(contractSpec) || contractV2HasEnums(contractV2),
+			HiddenFlags:      opts.StrictMode || contractHasHiddenFlags(contractSpec) || contractV2HasHiddenFlags(contractV2),
+			VersionOutput:    opts.ExpectVersion,
+			VersionArgs:      strings.Fields(opts.VersionCommand),
+		}, opts.Static)
+		if err != nil {
+			return nil, err
+		}
 	}
-	
-	if err != nil {
-		return nil, errors.InspectionError{
-			ProjectPath: absProjectPath,
-			Entrypoint:  opts.Entrypoint,
-			Err:         err,
+
+	// Select the root matching the inspected CLI from a v2 contract
+	var rootName string
+	if contractV2 != nil {
+		rootUse := actualStructure.Use
+		if opts.UseNameOnly {
+			rootUse = actualStructure.UseName()
+		}
+		contractSpec, err = contractV2.Root(rootUse)
+		if err != nil {
+			return nil, fmt.Errorf("failed to select contract root: %w", err)
+		}
+		for _, name := range contractV2.RootNames() {
+			if contractV2.Roots[name] == contractSpec {
+				rootName = name
+			}
+		}
+	}
+	if opts.ExpectVersion && contractSpec.Version == "" {
+		return nil, fmt.Errorf("--expect-version requires a 'version' field in the contract")
+	}
+	if contractHasEnv(contractSpec) {
+		bindings, err := discovery.FindEnvBindings(absProjectPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find environment variable bindings: %w", err)
 		}
+		applyEnvBindings(actualStructure, bindings)
+	}
+	if opts.Static {
+		contractSpec = staticContract(contractSpec)
 	}
 
 	// Validate the actual structure against the contract
-	result := validator.Validate(contractSpec, actualStructure)
+	result := validateStructure(contractSpec, actualStructure, opts, ignore)
 
 	return &ValidateResult{
-		Success: result.IsValid(),
+		Success:      len(opts.FailingErrors(result.Errors)) == 0 || opts.WarnOnly,
+		Result:       result,
+		Error:        nil,
+		ContractPath: contractPath,
+		RootName:     rootName,
+		Use:          contractSpec.Use,
+
+		UnknownFields: unknownFields,
+	}, nil
+}
+
+// validateEntrypoints validates the CLI at opts.Entrypoint against a contract
+// generated from opts.ContractEntrypoint, or the other way around with
+// opts.Flip
+func (s *ValidateService) validateEntrypoints(absProjectPath string, opts ValidateOptions, ignore []*regexp.Regexp) (*ValidateResult, error) {
+	expectedEntrypoint, actualEntrypoint := opts.ContractEntrypoint, opts.Entrypoint
+	if opts.Flip {
+		expectedEntrypoint, actualEntrypoint = actualEntrypoint, expectedEntrypoint
+	}
+
+	expected, err := s.inspect(inspector.Config{ProjectPath: absProjectPath, Entrypoint: expectedEntrypoint, Timeout: opts.Timeout}, opts.Static)
+	if err != nil {
+		return nil, err
+	}
+	actual, err := s.inspect(inspector.Config{ProjectPath: absProjectPath, Entrypoint: actualEntrypoint, Timeout: opts.Timeout}, opts.Static)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert the expected CLI as generate would
+	expected.Commands = filterHiddenCommands(expected.Commands)
+	contractSpec := NewGenerateService().inspectedToContract(expected, opts.ExpandedContract)
+	if opts.UseNameOnly {
+		contractSpec = contract.UseNamesOnly(contractSpec)
+	}
+
+	result := validateStructure(contractSpec, actual, opts, ignore)
+	return &ValidateResult{
+		Success: len(opts.FailingErrors(result.Errors)) == 0 || opts.WarnOnly,
 		Result:  result,
-		Error:   nil,
+		Use:     contractSpec.Use,
 	}, nil
 }
+
+// inspect inspects the CLI in config, or with static, reads it from the
+// project source with StaticInspector. InspectorWithConfig is only used if
+// config asks for the project's code to be run, for completion values,
+// hidden flags or the version output.
+func (s *ValidateService) inspect(config inspector.Config, static bool) (*inspector.InspectedCLI, error) {
+	config.Timeout = inspectionTimeout(config.Timeout, s.InspectorTimeout)
+
+	var inspected *inspector.InspectedCLI
+	var err error
+	if static {
+		inspected, err = s.StaticInspector(config.ProjectPath, config.Entrypoint, config.Timeout)
+	} else if s.InspectorWithConfig != nil && (config.CompletionValues || config.HiddenFlags || config.VersionOutput) {
+		inspected, err = s.InspectorWithConfig(config)
+	} else if config.Timeout > 0 {
+		inspected, err = s.InspectorWithTimeout(config.ProjectPath, config.Entrypoint, config.Timeout)
+	} else {
+		inspected, err = s.Inspector(config.ProjectPath, config.Entrypoint)
+	}
+	if err != nil {
+		return nil, errors.InspectionError{
+			ProjectPath: config.ProjectPath,
+			Entrypoint:  config.Entrypoint,
+			Err:         err,
+		}
+	}
+	return inspected, nil
+}
+
+// validateStructure validates the actual structure against the contract
+// with the validator options in opts, leaving out the commands matching
+// ignore
+func validateStructure(contractSpec *contract.Contract, actual *inspector.InspectedCLI, opts ValidateOptions, ignore []*regexp.Regexp) *validator.ValidationResult {
+	contractSpec, actual = ignoreCommands(contractSpec, actual, ignore)
+	if opts.UseNameOnly {
+		// The validator compares the contract's Use fields as they are
+		contractSpec = contract.UseNamesOnly(contractSpec)
+	}
+	validatorOpts := validator.Options{
+		StrictSortOrder:  opts.StrictSortOrder,
+		ExpandedContract: opts.ExpandedContract,
+		UseNameOnly:      opts.UseNameOnly,
+		ExpectVersion:    opts.ExpectVersion,
+		IgnoreShort:      opts.IgnoreShort,
+		IgnoreLong:       opts.IgnoreLong,
+		FailFast:         opts.FailFast,
+		Strict:           opts.StrictMode,
+		FocusPaths:       opts.FocusPaths,
+	}
+	var result *validator.ValidationResult
+	if opts.CompletionsOnly {
+		result = validator.ValidateCompletionsWithOptions(contractSpec, actual, validatorOpts)
+	} else {
+		result = validator.ValidateWithOptions(contractSpec, actual, validatorOpts)
+	}
+	result.WarnOnly = opts.WarnOnly
+	return result
+}
+
+// FailingErrors returns the validation errors that fail validation: all of
+// errs, except unexpected commands with AllowExtraCommands and unexpected
+// flags with AllowExtraFlags
+func (o ValidateOptions) FailingErrors(errs []validator.ValidationError) []validator.ValidationError {
+	var failing []validator.ValidationError
+	for _, err := range errs {
+		if err.Type == validator.ErrorTypeUnexpected {
+			if (o.AllowExtraCommands && err.Message == "command") || (o.AllowExtraFlags && err.Message == "flag") {
+				continue
+			}
+		}
+		failing = append(failing, err)
+	}
+	return failing
+}
+
+// contractHasEnums reports whether any flag in the contract lists enum values
+func contractHasEnums(c *contract.Contract) bool {
+	if c == nil {
+		return false
+	}
+	return flagsHaveEnums(c.Flags) || commandsHaveEnums(c.Commands)
+}
+
+// contractV2HasEnums reports whether any root of the contract lists flag enum values
+func contractV2HasEnums(c *contract.ContractV2) bool {
+	if c == nil {
+		return false
+	}
+	for _, root := range c.Roots {
+		if contractHasEnums(root) {
+			return true
+		}
+	}
+	return false
+}
+
+// commandsHaveEnums reports whether any flag of the commands or their
+// subcommands lists enum values
+func commandsHaveEnums(commands []contract.Command) bool {
+	for _, cmd := range commands {
+		if flagsHaveEnums(cmd.Flags) || commandsHaveEnums(cmd.Commands) {
+			return true
+		}
+	}
+	return false
+}
+
+// flagsHaveEnums reports whether any of the flags lists enum values
+func flagsHaveEnums(flags []contract.Flag) bool {
+	for _, flag := range flags {
+		if len(flag.Enum) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// contractHasHiddenFlags reports whether any flag in the contract is hidden
+func contractHasHiddenFlags(c *contract.Contract) bool {
+	if c == nil {
+		return false
+	}
+	return flagsHaveHidden(c.Flags) || commandsHaveHiddenFlags(c.Commands)
+}
+
+// contractV2HasHiddenFlags reports whether any root of the contract has a hidden flag
+func contractV2HasHiddenFlags(c *contract.ContractV2) bool {
+	if c == nil {
+		return false
+	}
+	for _, root := range c.Roots {
+		if contractHasHiddenFlags(root) {
+			return true
+		}
+	}
+	return false
+}
+
+// commandsHaveHiddenFlags reports whether any flag of the commands or their
+// subcommands is hidden
+func commandsHaveHiddenFlags(commands []contract.Command) bool {
+	for _, cmd := range commands {
+		if flagsHaveHidden(cmd.Flags) || commandsHaveHiddenFlags(cmd.Commands) {
+			return true
+		}
+	}
+	return false
+}
+
+// flagsHaveHidden reports whether any of the flags is hidden
+func flagsHaveHidden(flags []contract.Flag) bool {
+	for _, flag := range flags {
+		if flag.Hidden {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,641 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/contract"
+	"github.com/hiAndrewQuinn/cliguard/internal/inspector"
+	"github.com/hiAndrewQuinn/cliguard/internal/validator"
+)
+
+func TestValidateService_Validate_ContractURL(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte("use: myapp\nflags:\n  - name: port\n    type: int\n"))
+	}))
+	defer server.Close()
+	defer contract.DefaultFetcher.Cleanup()
+
+	svc := &ValidateService{
+		ContractLoader:   contract.Load,
+		ContractLoaderV2: contract.LoadV2,
+		FieldChecker:     contract.CheckFields,
+		Inspector: func(string, string) (*inspector.InspectedCLI, error) {
+			return &inspector.InspectedCLI{Use: "myapp", Flags: []inspector.InspectedFlag{{Name: "port", Type: "int"}}}, nil
+		},
+	}
+	url := server.URL + "/services/myapp/cliguard.yaml"
+	result, err := svc.Validate(ValidateOptions{ProjectPath: t.TempDir(), ContractPath: url, Entrypoint: "cmd.NewRootCmd"})
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if !result.Success {
+		t.Errorf("Validate() errors = %+v", result.Result.Errors)
+	}
+	if result.ContractPath != url {
+		t.Errorf("ContractPath = %q, want %q", result.ContractPath, url)
+	}
+	if requests != 1 {
+		t.Errorf("requests = %d, want the contract downloaded once", requests)
+	}
+}
+
+func TestValidateService_Validate_CLISnapshot(t *testing.T) {
+	projectDir := t.TempDir()
+	contractPath := filepath.Join(projectDir, "cliguard.yaml")
+	if err := os.WriteFile(contractPath, []byte("use: myapp\ncommands:\n  - use: serve\n    flags:\n      - name: port\n        type: int\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cli := &inspector.InspectedCLI{
+		Use: "myapp",
+		Commands: []inspector.InspectedCommand{
+			{Use: "serve", Flags: []inspector.InspectedFlag{{Name: "port", Type: "string"}}},
+			{Use: "migrate"},
+		},
+	}
+	snapshotPath := filepath.Join(t.TempDir(), "cli.json")
+	if err := inspector.WriteSnapshot(snapshotPath, cli); err != nil {
+		t.Fatal(err)
+	}
+
+	inspections := 0
+	svc := NewValidateService()
+	svc.Inspector = func(string, string) (*inspector.InspectedCLI, error) {
+		inspections++
+		return cli, nil
+	}
+	svc.InspectorTimeout = 0
+	opts := ValidateOptions{ProjectPath: projectDir, ContractPath: contractPath, Entrypoint: "cmd.NewRootCmd"}
+
+	live, err := svc.Validate(opts)
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	opts.CLISnapshot = snapshotPath
+	fromSnapshot, err := svc.Validate(opts)
+	if err != nil {
+		t.Fatalf("Validate() with snapshot error = %v", err)
+	}
+	if inspections != 1 {
+		t.Errorf("inspections = %d, want the project inspected only without the snapshot", inspections)
+	}
+	if live.Success || len(live.Result.Errors) != 2 {
+		t.Fatalf("live validation errors = %+v, want a type change and a new command", live.Result.Errors)
+	}
+	if !reflect.DeepEqual(fromSnapshot, live) {
+		t.Errorf("validation with snapshot = %+v, want %+v", fromSnapshot.Result.Errors, live.Result.Errors)
+	}
+
+	opts.ExpectVersion = true
+	if _, err := svc.Validate(opts); err == nil || !strings.Contains(err.Error(), "--expect-version cannot be used with --cli-snapshot") {
+		t.Errorf("Validate() error = %v, want --expect-version rejected", err)
+	}
+
+	opts.ExpectVersion = false
+	opts.CLISnapshot = filepath.Join(t.TempDir(), "missing.json")
+	if _, err := svc.Validate(opts); err == nil || !strings.Contains(err.Error(), "failed to load CLI snapshot") {
+		t.Errorf("Validate() error = %v, want the missing snapshot reported", err)
+	}
+}
+
+func TestValidateService_Validate_Static(t *testing.T) {
+	projectDir := t.TempDir()
+	contractPath := filepath.Join(projectDir, "cliguard.yaml")
+	contractYAML := `use: myapp
+mutually_exclusive:
+  - [json, yaml]
+flags:
+  - name: json
+    type: bool
+  - name: yaml
+    type: bool
+commands:
+  - use: serve
+    flags:
+      - name: format
+        type: string
+        default: text
+        completion: custom
+        enum: [json, text]
+      - name: port
+        type: int
+        default: "8080"
+`
+	if err := os.WriteFile(contractPath, []byte(contractYAML), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var staticCalls []string
+	svc := NewValidateService()
+	svc.Inspector = func(string, string) (*inspector.InspectedCLI, error) {
+		t.Fatal("the project was inspected by running it")
+		return nil, nil
+	}
+	svc.InspectorWithTimeout = func(string, string, time.Duration) (*inspector.InspectedCLI, error) {
+		t.Fatal("the project was inspected by running it")
+		return nil, nil
+	}
+	svc.StaticInspector = func(projectPath, entrypoint string, timeout time.Duration) (*inspector.InspectedCLI, error) {
+		staticCalls = append(staticCalls, entrypoint)
+		return &inspector.InspectedCLI{
+			Use:   "myapp",
+			Flags: []inspector.InspectedFlag{{Name: "json", Type: "bool"}, {Name: "yaml", Type: "bool"}},
+			Commands: []inspector.InspectedCommand{{
+				Use: "serve",
+				Flags: []inspector.InspectedFlag{
+					{Name: "format", Type: "string"},
+					{Name: "port", Type: "string"},
+				},
+			}},
+		}, nil
+	}
+	opts := ValidateOptions{ProjectPath: projectDir, ContractPath: contractPath, Entrypoint: "cmd.NewRootCmd", Static: true}
+
+	result, err := svc.Validate(opts)
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	// Only the type change is found statically
+	if len(result.Result.Errors) != 1 || result.Result.Errors[0].Path != "serve --port" {
+		t.Errorf("Validate() errors = %+v, want only the port type mismatch", result.Result.Errors)
+	}
+	if !reflect.DeepEqual(staticCalls, []string{"cmd.NewRootCmd"}) {
+		t.Errorf("static inspections = %v", staticCalls)
+	}
+
+	for name, conflict := range map[string]func(*ValidateOptions){
+		"--cli-snapshot":     func(o *ValidateOptions) { o.CLISnapshot = "cli.json" },
+		"--expect-version":   func(o *ValidateOptions) { o.ExpectVersion = true },
+		"--completions-only": func(o *ValidateOptions) { o.CompletionsOnly = true },
+	} {
+		conflicting := opts
+		conflict(&conflicting)
+		if _, err := svc.Validate(conflicting); err == nil || !strings.Contains(err.Error(), name) {
+			t.Errorf("Validate() with %s error = %v, want it rejected", name, err)
+		}
+	}
+}
+
+func TestValidateService_Validate_UseNameOnly(t *testing.T) {
+	projectDir := t.TempDir()
+	contractPath := filepath.Join(projectDir, "cliguard.yaml")
+	if err := os.WriteFile(contractPath, []byte("use: myapp [flags]\ncommands:\n  - use: create [resource]\n    commands:\n      - use: set [key] [value]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	svc := NewValidateService()
+	svc.Inspector = func(string, string) (*inspector.InspectedCLI, error) {
+		return &inspector.InspectedCLI{
+			Use: "myapp",
+			Commands: []inspector.InspectedCommand{{
+				Use:      "create <resource>",
+				Commands: []inspector.InspectedCommand{{Use: "set <key> <value>"}},
+			}},
+		}, nil
+	}
+	svc.InspectorTimeout = 0
+	opts := ValidateOptions{ProjectPath: projectDir, ContractPath: contractPath, Entrypoint: "cmd.NewRootCmd", UseNameOnly: true}
+
+	result, err := svc.Validate(opts)
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if !result.Success {
+		t.Errorf("Validate() errors = %+v, want the argument patterns ignored", result.Result.Errors)
+	}
+
+	opts.UseNameOnly = false
+	if result, err = svc.Validate(opts); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if result.Success {
+		t.Error("Validate() succeeded, want the full Use fields compared")
+	}
+}
+
+func TestValidateService_Validate_ContractV2(t *testing.T) {
+	projectDir := t.TempDir()
+	contractPath := filepath.Join(projectDir, "cliguard.yaml")
+	v2Contract := `version: 2.0.0
+roots:
+  app:
+    use: myapp
+    flags:
+      - name: port
+        type: int
+  admin:
+    use: myapp-admin
+`
+	if err := os.WriteFile(contractPath, []byte(v2Contract), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	newService := func(inspected *inspector.InspectedCLI) *ValidateService {
+		return &ValidateService{
+			ContractLoader: func(string) (*contract.Contract, error) {
+				t.Error("v1 loader should not be used for a v2 contract")
+				return nil, nil
+			},
+			ContractLoaderV2: contract.LoadV2,
+			Inspector: func(string, string) (*inspector.InspectedCLI, error) {
+				return inspected, nil
+			},
+		}
+	}
+
+	t.Run("selects matching root", func(t *testing.T) {
+		svc := newService(&inspector.InspectedCLI{
+			Use:   "myapp",
+			Flags: []inspector.InspectedFlag{{Name: "port", Type: "int"}},
+		})
+		result, err := svc.Validate(ValidateOptions{ProjectPath: projectDir, Entrypoint: "cmd.NewRootCmd"})
+		if err != nil {
+			t.Fatalf("Validate() error = %v", err)
+		}
+		if !result.Success {
+			t.Errorf("Validate() errors = %+v", result.Result.Errors)
+		}
+		if result.RootName != "app" || result.ContractPath != contractPath {
+			t.Errorf("RootName, ContractPath = %q, %q; want %q, %q", result.RootName, result.ContractPath, "app", contractPath)
+		}
+	})
+
+	t.Run("reports differences against selected root", func(t *testing.T) {
+		svc := newService(&inspector.InspectedCLI{Use: "myapp-admin", Flags: []inspector.InspectedFlag{{Name: "port", Type: "int"}}})
+		result, err := svc.Validate(ValidateOptions{ProjectPath: projectDir, Entrypoint: "cmd.NewRootCmd"})
+		if err != nil {
+			t.Fatalf("Validate() error = %v", err)
+		}
+		if result.Success {
+			t.Error("Validate() should fail: myapp-admin defines no --port flag in the contract")
+		}
+	})
+
+	t.Run("no matching root", func(t *testing.T) {
+		svc := newService(&inspector.InspectedCLI{Use: "other"})
+		if _, err := svc.Validate(ValidateOptions{ProjectPath: projectDir, Entrypoint: "cmd.NewRootCmd"}); err == nil {
+			t.Error("Validate() expected error when no root matches")
+		}
+	})
+}
+
+func TestValidateService_Validate_EnumRunsCompletionFunctions(t *testing.T) {
+	projectDir := t.TempDir()
+	contractPath := filepath.Join(projectDir, "cliguard.yaml")
+	if err := os.WriteFile(contractPath, []byte("use: myapp\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var usedConfig *inspector.Config
+	newService := func(c *contract.Contract) *ValidateService {
+		return &ValidateService{
+			ContractLoader: func(string) (*contract.Contract, error) { return c, nil },
+			Inspector: func(string, string) (*inspector.InspectedCLI, error) {
+				return &inspector.InspectedCLI{Use: "myapp"}, nil
+			},
+			InspectorWithConfig: func(config inspector.Config) (*inspector.InspectedCLI, error) {
+				usedConfig = &config
+				return &inspector.InspectedCLI{Use: "myapp", Commands: []inspector.InspectedCommand{{
+					Use:   "deploy",
+					Flags: []inspector.InspectedFlag{{Name: "region", Type: "string", Enum: []string{"eu-west-1", "us-east-1"}}},
+				}}}, nil
+			},
+		}
+	}
+
+	withEnum := &contract.Contract{Use: "myapp", Commands: []contract.Command{{
+		Use:   "deploy",
+		Flags: []contract.Flag{{Name: "region", Type: "string", Enum: []string{"us-east-1", "eu-west-1"}}},
+	}}}
+	result, err := newService(withEnum).Validate(ValidateOptions{ProjectPath: projectDir, Entrypoint: "cmd.NewRootCmd"})
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if usedConfig == nil || !usedConfig.CompletionValues {
+		t.Fatalf("InspectorWithConfig config = %+v, want CompletionValues", usedConfig)
+	}
+	if !result.Success {
+		t.Errorf("Validate() errors = %+v", result.Result.Errors)
+	}
+
+	usedConfig = nil
+	if _, err := newService(&contract.Contract{Use: "myapp"}).Validate(ValidateOptions{ProjectPath: projectDir, Entrypoint: "cmd.NewRootCmd"}); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if usedConfig != nil {
+		t.Error("completion functions should only run when the contract lists enum values")
+	}
+}
+
+func TestValidateService_Validate_WarnOnly(t *testing.T) {
+	projectDir := t.TempDir()
+	svc := &ValidateService{
+		ContractLoader: func(string) (*contract.Contract, error) {
+			return &contract.Contract{Use: "myapp", Short: "My app", Flags: []contract.Flag{{Name: "verbose", Type: "bool"}}}, nil
+		},
+		Inspector: func(string, string) (*inspector.InspectedCLI, error) {
+			return &inspector.InspectedCLI{Use: "myapp", Short: "My app"}, nil
+		},
+	}
+
+	result, err := svc.Validate(ValidateOptions{ProjectPath: projectDir, ContractPath: "cliguard.yaml", Entrypoint: "cmd.NewRootCmd", WarnOnly: true})
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if !result.Success || !result.Result.WarnOnly || len(result.Result.Errors) != 1 {
+		t.Errorf("Validate() = %+v, %+v, want success with the missing flag reported", result, result.Result)
+	}
+
+	result, err = svc.Validate(ValidateOptions{ProjectPath: projectDir, ContractPath: "cliguard.yaml", Entrypoint: "cmd.NewRootCmd"})
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if result.Success || result.Result.WarnOnly {
+		t.Errorf("Validate() = %+v, want failure without warn-only", result)
+	}
+}
+
+func TestValidateService_Validate_AllowExtra(t *testing.T) {
+	projectDir := t.TempDir()
+	svc := &ValidateService{
+		ContractLoader: func(string) (*contract.Contract, error) {
+			return &contract.Contract{Use: "myapp", Short: "My app"}, nil
+		},
+		Inspector: func(string, string) (*inspector.InspectedCLI, error) {
+			return &inspector.InspectedCLI{
+				Use:      "myapp",
+				Short:    "My app",
+				Flags:    []inspector.InspectedFlag{{Name: "verbose", Type: "bool"}},
+				Commands: []inspector.InspectedCommand{{Use: "serve", Short: "Serve", Runnable: true}},
+			}, nil
+		},
+	}
+
+	tests := []struct {
+		name        string
+		opts        ValidateOptions
+		wantSuccess bool
+		wantFailing int
+	}{
+		{name: "neither allowed", wantFailing: 2},
+		{name: "extra commands", opts: ValidateOptions{AllowExtraCommands: true}, wantFailing: 1},
+		{name: "extra flags", opts: ValidateOptions{AllowExtraFlags: true}, wantFailing: 1},
+		{name: "both", opts: ValidateOptions{AllowExtraCommands: true, AllowExtraFlags: true}, wantSuccess: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := tt.opts
+			opts.ProjectPath, opts.ContractPath, opts.Entrypoint = projectDir, "cliguard.yaml", "cmd.NewRootCmd"
+			result, err := svc.Validate(opts)
+			if err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+			if len(result.Result.Errors) != 2 {
+				t.Errorf("Validate() errors = %+v, want both differences reported", result.Result.Errors)
+			}
+			if failing := opts.FailingErrors(result.Result.Errors); len(failing) != tt.wantFailing {
+				t.Errorf("FailingErrors() = %+v, want %d", failing, tt.wantFailing)
+			}
+			if result.Success != tt.wantSuccess {
+				t.Errorf("Success = %v, want %v", result.Success, tt.wantSuccess)
+			}
+		})
+	}
+}
+
+func TestValidateService_Validate_StrictMode(t *testing.T) {
+	projectDir := t.TempDir()
+	contractPath := filepath.Join(projectDir, "cliguard.yaml")
+	if err := os.WriteFile(contractPath, []byte("use: myapp\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var usedConfig *inspector.Config
+	svc := &ValidateService{
+		ContractLoader: func(string) (*contract.Contract, error) { return &contract.Contract{Use: "myapp"}, nil },
+		Inspector: func(string, string) (*inspector.InspectedCLI, error) {
+			return &inspector.InspectedCLI{Use: "myapp"}, nil
+		},
+		InspectorWithConfig: func(config inspector.Config) (*inspector.InspectedCLI, error) {
+			usedConfig = &config
+			return &inspector.InspectedCLI{
+				Use:      "myapp",
+				Flags:    []inspector.InspectedFlag{{Name: "debug", Type: "bool", Hidden: true}},
+				Commands: []inspector.InspectedCommand{{Use: "internal", Hidden: true}},
+			}, nil
+		},
+	}
+
+	result, err := svc.Validate(ValidateOptions{ProjectPath: projectDir, Entrypoint: "cmd.NewRootCmd", StrictMode: true})
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if usedConfig == nil || !usedConfig.HiddenFlags {
+		t.Fatalf("InspectorWithConfig config = %+v, want HiddenFlags", usedConfig)
+	}
+	if result.Success || !result.Result.Strict || len(result.Result.Errors) != 2 {
+		t.Errorf("Validate() = %+v, want the hidden command and flag unexpected", result.Result)
+	}
+
+	usedConfig = nil
+	result, err = svc.Validate(ValidateOptions{ProjectPath: projectDir, Entrypoint: "cmd.NewRootCmd"})
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if usedConfig != nil {
+		t.Error("hidden flags should only be inspected in strict mode or when the contract lists some")
+	}
+	if !result.Success || result.Result.Strict {
+		t.Errorf("Validate() = %+v, want success without strict mode", result.Result)
+	}
+}
+
+func TestValidateService_Validate_ExpectVersion(t *testing.T) {
+	projectDir := t.TempDir()
+	contractPath := filepath.Join(projectDir, "cliguard.yaml")
+	if err := os.WriteFile(contractPath, []byte("use: myapp\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var usedConfig *inspector.Config
+	newService := func(c *contract.Contract) *ValidateService {
+		return &ValidateService{
+			ContractLoader: func(string) (*contract.Contract, error) { return c, nil },
+			Inspector: func(string, string) (*inspector.InspectedCLI, error) {
+				return &inspector.InspectedCLI{Use: "myapp"}, nil
+			},
+			InspectorWithConfig: func(config inspector.Config) (*inspector.InspectedCLI, error) {
+				usedConfig = &config
+				return &inspector.InspectedCLI{Use: "myapp", VersionOutput: "myapp version 1.4.2\n"}, nil
+			},
+		}
+	}
+
+	result, err := newService(&contract.Contract{Use: "myapp", Version: "1.4.2"}).Validate(ValidateOptions{
+		ProjectPath:    projectDir,
+		Entrypoint:     "cmd.NewRootCmd",
+		ExpectVersion:  true,
+		VersionCommand: "version --short",
+	})
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if usedConfig == nil || !usedConfig.VersionOutput || !reflect.DeepEqual(usedConfig.VersionArgs, []string{"version", "--short"}) {
+		t.Fatalf("InspectorWithConfig config = %+v, want VersionOutput with args [version --short]", usedConfig)
+	}
+	if !result.Success {
+		t.Errorf("Validate() errors = %+v", result.Result.Errors)
+	}
+
+	result, err = newService(&contract.Contract{Use: "myapp", Version: "1.5.0"}).Validate(ValidateOptions{
+		ProjectPath:   projectDir,
+		Entrypoint:    "cmd.NewRootCmd",
+		ExpectVersion: true,
+	})
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if result.Success {
+		t.Error("Validate() should fail: the CLI reports 1.4.2, the contract 1.5.0")
+	}
+
+	_, err = newService(&contract.Contract{Use: "myapp"}).Validate(ValidateOptions{
+		ProjectPath:   projectDir,
+		Entrypoint:    "cmd.NewRootCmd",
+		ExpectVersion: true,
+	})
+	if err == nil || !strings.Contains(err.Error(), "requires a 'version' field") {
+		t.Errorf("Validate() error = %v, want missing version field error", err)
+	}
+}
+
+func TestValidateService_Validate_ContractFromEntrypoint(t *testing.T) {
+	projectDir := t.TempDir()
+	clis := map[string]*inspector.InspectedCLI{
+		"v1.NewRootCmd": {
+			Use:      "app",
+			Flags:    []inspector.InspectedFlag{{Name: "port", Type: "int"}},
+			Commands: []inspector.InspectedCommand{{Use: "debug", Hidden: true}},
+		},
+		"cmd.NewRootCmd": {
+			Use: "app",
+			Flags: []inspector.InspectedFlag{
+				{Name: "port", Type: "int"},
+				{Name: "host", Type: "string"},
+			},
+		},
+	}
+	svc := &ValidateService{
+		ContractLoader: func(string) (*contract.Contract, error) {
+			t.Error("no contract should be loaded")
+			return nil, nil
+		},
+		Inspector: func(_, entrypoint string) (*inspector.InspectedCLI, error) {
+			cli, ok := clis[entrypoint]
+			if !ok {
+				t.Fatalf("unexpected entrypoint %q", entrypoint)
+			}
+			// Return a copy, since generating the contract removes hidden commands
+			copied := *cli
+			return &copied, nil
+		},
+	}
+	opts := ValidateOptions{
+		ProjectPath:        projectDir,
+		Entrypoint:         "cmd.NewRootCmd",
+		ContractEntrypoint: "v1.NewRootCmd",
+	}
+
+	t.Run("validates entrypoint against contract entrypoint", func(t *testing.T) {
+		result, err := svc.Validate(opts)
+		if err != nil {
+			t.Fatalf("Validate() error = %v", err)
+		}
+		if result.Success {
+			t.Fatal("Validate() should fail: --host was added")
+		}
+		if len(result.Result.Errors) != 1 || result.Result.Errors[0].Path != "--host" || result.Result.Errors[0].Type != validator.ErrorTypeUnexpected {
+			t.Errorf("Validate() errors = %+v, want only unexpected --host", result.Result.Errors)
+		}
+		if result.ContractPath != "" {
+			t.Errorf("ContractPath = %q, want empty", result.ContractPath)
+		}
+	})
+
+	t.Run("flip", func(t *testing.T) {
+		flipped := opts
+		flipped.Flip = true
+		result, err := svc.Validate(flipped)
+		if err != nil {
+			t.Fatalf("Validate() error = %v", err)
+		}
+		if len(result.Result.Errors) != 1 || result.Result.Errors[0].Path != "--host" || result.Result.Errors[0].Type != validator.ErrorTypeMissing {
+			t.Errorf("Validate() errors = %+v, want only missing --host", result.Result.Errors)
+		}
+	})
+
+	t.Run("contract path is rejected", func(t *testing.T) {
+		withContract := opts
+		withContract.ContractPath = "cliguard.yaml"
+		if _, err := svc.Validate(withContract); err == nil {
+			t.Error("Validate() expected error with both --contract and --contract-from-entrypoint")
+		}
+	})
+}
+
+func TestValidateService_Validate_UnknownFields(t *testing.T) {
+	projectDir := t.TempDir()
+	contractPath := filepath.Join(projectDir, "cliguard.yaml")
+	content := `use: app
+short: App
+commands:
+  - use: serve
+    short: Serve
+    usage_example: app serve
+`
+	if err := os.WriteFile(contractPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	inspected := false
+	svc := &ValidateService{
+		ContractLoader: contract.Load,
+		Inspector: func(string, string) (*inspector.InspectedCLI, error) {
+			inspected = true
+			return &inspector.InspectedCLI{Use: "app", Short: "App", Commands: []inspector.InspectedCommand{{Use: "serve", Short: "Serve"}}}, nil
+		},
+		FieldChecker: contract.CheckFields,
+	}
+
+	t.Run("listed by default", func(t *testing.T) {
+		result, err := svc.Validate(ValidateOptions{ProjectPath: projectDir})
+		if err != nil {
+			t.Fatalf("Validate() error = %v", err)
+		}
+		if !result.Success {
+			t.Errorf("Validate() errors = %+v; unknown fields should not fail validation", result.Result.Errors)
+		}
+		want := []contract.UnknownField{{Name: "usage_example", Path: "root.commands[0]", Line: 6}}
+		if len(result.UnknownFields) != 1 || result.UnknownFields[0] != want[0] {
+			t.Errorf("UnknownFields = %+v, want %+v", result.UnknownFields, want)
+		}
+	})
+
+	t.Run("error with strict contract", func(t *testing.T) {
+		inspected = false
+		_, err := svc.Validate(ValidateOptions{ProjectPath: projectDir, StrictContract: true})
+		if err == nil || !strings.Contains(err.Error(), "field usage_example not found") {
+			t.Errorf("Validate() error = %v, want unknown field error", err)
+		}
+		if inspected {
+			t.Error("project was inspected although the contract is invalid")
+		}
+	})
+}
@@ -42,6 +42,7 @@
 //   - Usage strings must match (if specified)
 //   - Default values must match (if specified)
 //   - Required flags must be marked as required
+//   - Deprecation messages must match; flags without one must not be deprecated
 //
 // # Validation Modes
 //
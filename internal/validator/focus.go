@@ -0,0 +1,140 @@
+package validator
+
+import (
+	"strings"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/contract"
+	"github.com/hiAndrewQuinn/cliguard/internal/inspector"
+)
+
+// splitFocusPaths splits dot-separated command paths such as "create.user"
+// into the command names along them, dropping empty paths
+func splitFocusPaths(paths []string) [][]string {
+	var result [][]string
+	for _, path := range paths {
+		var names []string
+		for _, name := range strings.Split(path, ".") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, name)
+			}
+		}
+		if len(names) > 0 {
+			result = append(result, names)
+		}
+	}
+	return result
+}
+
+// focusRest returns what is left of the focus paths below the command
+// called name, and whether one of them ends at it, which keeps it whole
+func focusRest(name string, paths [][]string) ([][]string, bool) {
+	var rest [][]string
+	for _, path := range paths {
+		if path[0] != name {
+			continue
+		}
+		if len(path) == 1 {
+			return nil, true
+		}
+		rest = append(rest, path[1:])
+	}
+	return rest, false
+}
+
+// filterContract returns a copy of c with only the commands on the focus
+// paths, dot-separated command paths such as "create.user" below the root.
+// A command a path ends at is kept with its subcommands. The commands
+// leading to it, and the root, keep only their Use, visibility and the
+// subcommands towards the focus, so nothing else of them is validated.
+func filterContract(c *contract.Contract, paths []string) *contract.Contract {
+	return &contract.Contract{
+		Use:      c.Use,
+		Version:  c.Version,
+		Commands: focusContractCommands(c.Commands, splitFocusPaths(paths)),
+	}
+}
+
+func focusContractCommands(commands []contract.Command, paths [][]string) []contract.Command {
+	var result []contract.Command
+	for _, cmd := range commands {
+		rest, whole := focusRest(contract.CommandName(cmd.Use), paths)
+		switch {
+		case whole:
+			result = append(result, cmd)
+		case len(rest) > 0:
+			result = append(result, contract.Command{
+				Use:      cmd.Use,
+				Hidden:   cmd.Hidden,
+				Commands: focusContractCommands(cmd.Commands, rest),
+			})
+		}
+	}
+	return result
+}
+
+// filterInspected is filterContract for the actual CLI. Runnability is kept
+// on the commands leading to the focus, for reporting them as unexpected.
+func filterInspected(cli *inspector.InspectedCLI, paths []string) *inspector.InspectedCLI {
+	return &inspector.InspectedCLI{
+		Use:           cli.Use,
+		Version:       cli.Version,
+		VersionOutput: cli.VersionOutput,
+		Commands:      focusInspectedCommands(cli.Commands, splitFocusPaths(paths)),
+	}
+}
+
+func focusInspectedCommands(commands []inspector.InspectedCommand, paths [][]string) []inspector.InspectedCommand {
+	var result []inspector.InspectedCommand
+	for i := range commands {
+		cmd := commands[i]
+		rest, whole := focusRest(cmd.UseName(), paths)
+		switch {
+		case whole:
+			result = append(result, cmd)
+		case len(rest) > 0:
+			result = append(result, inspector.InspectedCommand{
+				Use:      cmd.Use,
+				Hidden:   cmd.Hidden,
+				Runnable: cmd.Runnable,
+				Commands: focusInspectedCommands(cmd.Commands, rest),
+			})
+		}
+	}
+	return result
+}
+
+// unmatchedFocusPaths returns the focus paths that lead to no command of
+// either the contract or the actual CLI
+func unmatchedFocusPaths(expected *contract.Contract, actual *inspector.InspectedCLI, paths []string) []string {
+	var result []string
+	for _, path := range paths {
+		names := splitFocusPaths([]string{path})
+		if len(names) == 0 {
+			continue
+		}
+		if !hasContractCommand(expected.Commands, names[0]) && !hasInspectedCommand(actual.Commands, names[0]) {
+			result = append(result, path)
+		}
+	}
+	return result
+}
+
+// hasContractCommand reports whether the command path names is in commands
+func hasContractCommand(commands []contract.Command, names []string) bool {
+	for _, cmd := range commands {
+		if contract.CommandName(cmd.Use) == names[0] {
+			return len(names) == 1 || hasContractCommand(cmd.Commands, names[1:])
+		}
+	}
+	return false
+}
+
+// hasInspectedCommand reports whether the command path names is in commands
+func hasInspectedCommand(commands []inspector.InspectedCommand, names []string) bool {
+	for i := range commands {
+		if commands[i].UseName() == names[0] {
+			return len(names) == 1 || hasInspectedCommand(commands[i].Commands, names[1:])
+		}
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/contract"
+	"github.com/hiAndrewQuinn/cliguard/internal/inspector"
+)
+
+func TestFilterContract(t *testing.T) {
+	c := &contract.Contract{
+		Use:   "myapp",
+		Short: "My app",
+		Flags: []contract.Flag{{Name: "verbose", Type: "bool", Persistent: true}},
+		Commands: []contract.Command{
+			{
+				Use:   "create [resource]",
+				Short: "Create resources",
+				Flags: []contract.Flag{{Name: "dry-run", Type: "bool"}},
+				Commands: []contract.Command{
+					{Use: "user <name>", Short: "Create a user", Commands: []contract.Command{{Use: "admin"}}},
+					{Use: "group", Short: "Create a group"},
+				},
+			},
+			{Use: "delete", Short: "Delete resources"},
+		},
+	}
+
+	got := filterContract(c, []string{"create.user"})
+	if got.Use != "myapp" || got.Short != "" || len(got.Flags) != 0 {
+		t.Errorf("root = %+v, want only its Use", got)
+	}
+	if len(got.Commands) != 1 {
+		t.Fatalf("commands = %+v, want only create", got.Commands)
+	}
+	create := got.Commands[0]
+	if create.Use != "create [resource]" || create.Short != "" || len(create.Flags) != 0 {
+		t.Errorf("create = %+v, want only its Use and the path to user", create)
+	}
+	if len(create.Commands) != 1 || create.Commands[0].Short != "Create a user" || len(create.Commands[0].Commands) != 1 {
+		t.Errorf("create commands = %+v, want user with its subcommands", create.Commands)
+	}
+
+	if got := filterContract(c, []string{"delete", "create"}); len(got.Commands) != 2 || len(got.Commands[0].Commands) != 2 {
+		t.Errorf("filterContract() = %+v, want create and delete whole", got.Commands)
+	}
+	if got := filterContract(c, []string{"missing"}); len(got.Commands) != 0 {
+		t.Errorf("filterContract() = %+v, want no commands", got.Commands)
+	}
+	if len(c.Commands[0].Flags) != 1 || c.Short != "My app" {
+		t.Error("filterContract() modified the contract")
+	}
+}
+
+func TestValidateWithOptions_FocusPaths(t *testing.T) {
+	expected := &contract.Contract{
+		Use:   "myapp",
+		Short: "My app",
+		Commands: []contract.Command{
+			{
+				Use:   "create",
+				Short: "Create resources",
+				Commands: []contract.Command{
+					{Use: "user", Short: "Create a user", Flags: []contract.Flag{{Name: "admin", Type: "bool"}}},
+					{Use: "group", Short: "Create a group"},
+				},
+			},
+			{Use: "delete", Short: "Delete resources"},
+		},
+	}
+	actual := &inspector.InspectedCLI{
+		Use:   "myapp",
+		Short: "Another app",
+		Commands: []inspector.InspectedCommand{
+			{
+				Use:   "create",
+				Short: "Make resources",
+				Commands: []inspector.InspectedCommand{
+					{Use: "user", Short: "Create a user", Flags: []inspector.InspectedFlag{{Name: "admin", Type: "bool"}}},
+				},
+			},
+			{Use: "remove", Short: "Delete resources"},
+		},
+	}
+
+	// Everything outside create user differs
+	if result := ValidateWithOptions(expected, actual, Options{}); result.IsValid() {
+		t.Fatal("ValidateWithOptions() is valid, want errors without focus paths")
+	}
+	if result := ValidateWithOptions(expected, actual, Options{FocusPaths: []string{"create.user"}}); !result.IsValid() {
+		t.Errorf("ValidateWithOptions() errors = %+v, want create user valid", result.Errors)
+	}
+
+	result := ValidateWithOptions(expected, actual, Options{FocusPaths: []string{"create.group"}})
+	if len(result.Errors) != 1 || result.Errors[0].Type != ErrorTypeMissing || result.Errors[0].Path != "create group" {
+		t.Errorf("errors = %+v, want create group missing", result.Errors)
+	}
+
+	result = ValidateWithOptions(expected, actual, Options{FocusPaths: []string{"create.user", "create.nothing"}})
+	if len(result.Errors) != 1 || result.Errors[0].Path != "create.nothing" || result.Errors[0].Message != "Missing focus path" {
+		t.Errorf("errors = %+v, want the unmatched focus path", result.Errors)
+	}
+}
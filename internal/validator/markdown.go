@@ -0,0 +1,75 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// markdownCollapseThreshold is the number of errors above which the error
+// table is collapsed into a <details> block, so long reports do not take
+// over a pull request conversation
+const markdownCollapseThreshold = 10
+
+// FormatMarkdown renders the result as GitHub-flavored markdown for pull
+// request comments: a badge line, a collapsible summary of the error
+// counts by type and a table of the errors.
+func (vr *ValidationResult) FormatMarkdown() string {
+	var b strings.Builder
+	if vr.IsValid() {
+		b.WriteString("## ✅ cliguard: validation passed\n\nThe CLI structure matches the contract.\n")
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "## ❌ cliguard: validation failed with %d error(s)\n\n", vr.errorCount())
+
+	counts := make(map[ErrorType]int)
+	var types []ErrorType
+	for _, err := range vr.Errors {
+		if counts[err.Type] == 0 {
+			types = append(types, err.Type)
+		}
+		counts[err.Type]++
+	}
+	b.WriteString("<details>\n<summary>Summary</summary>\n\n")
+	b.WriteString("| Type | Errors |\n| --- | ---: |\n")
+	for _, errorType := range types {
+		fmt.Fprintf(&b, "| %s | %d |\n", errorType, counts[errorType])
+	}
+	b.WriteString("\n</details>\n\n")
+
+	collapse := len(vr.Errors) > markdownCollapseThreshold
+	if collapse {
+		fmt.Fprintf(&b, "<details>\n<summary>All %d errors</summary>\n\n", len(vr.Errors))
+	}
+	b.WriteString("| Type | Path | Expected | Actual | Message |\n| --- | --- | --- | --- | --- |\n")
+	for _, err := range vr.Errors {
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n",
+			err.Type, markdownCode(err.Path), markdownCode(err.Expected), markdownCode(err.Actual), markdownCell(err.Message))
+	}
+	if collapse {
+		b.WriteString("\n</details>\n")
+	}
+	if count := vr.errorCount(); count > len(vr.Errors) {
+		fmt.Fprintf(&b, "\n%d of %d errors shown.\n", len(vr.Errors), count)
+	}
+	return b.String()
+}
+
+// markdownCell escapes s for use in a table cell
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.Join(strings.Fields(strings.ReplaceAll(s, "\n", " ")), " ")
+}
+
+// markdownCode formats s as inline code in a table cell. Empty values are
+// left blank.
+func markdownCode(s string) string {
+	s = markdownCell(s)
+	if s == "" {
+		return ""
+	}
+	if strings.Contains(s, "`") {
+		return "`` " + s + " ``"
+	}
+	return "`" + s + "`"
+}
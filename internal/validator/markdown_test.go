@@ -0,0 +1,48 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidationResult_FormatMarkdown(t *testing.T) {
+	want := "## ❌ cliguard: validation failed with 2 error(s)\n\n" +
+		"<details>\n<summary>Summary</summary>\n\n" +
+		"| Type | Errors |\n| --- | ---: |\n" +
+		"| missing | 1 |\n" +
+		"| mismatch | 1 |\n" +
+		"\n</details>\n\n" +
+		"| Type | Path | Expected | Actual | Message |\n| --- | --- | --- | --- | --- |\n" +
+		"| missing | `root.flags.verbose` | `verbose` |  | Missing flag |\n" +
+		"| mismatch | `root.short` | `A tool` | `A CLI tool` | Short description mismatch |\n"
+	assert.Equal(t, want, sampleResult().FormatMarkdown())
+}
+
+func TestValidationResult_FormatMarkdownPassed(t *testing.T) {
+	out := (&ValidationResult{Valid: true}).FormatMarkdown()
+	assert.True(t, strings.HasPrefix(out, "## ✅ cliguard: validation passed\n"))
+	assert.NotContains(t, out, "<details>")
+}
+
+func TestValidationResult_FormatMarkdownCollapsesLongLists(t *testing.T) {
+	result := &ValidationResult{}
+	for i := 0; i < markdownCollapseThreshold; i++ {
+		result.AddError(ErrorTypeUnexpected, fmt.Sprintf("root.flags.f%d", i), "", "", "Unexpected flag")
+	}
+	assert.NotContains(t, result.FormatMarkdown(), "<summary>All")
+
+	result.AddError(ErrorTypeUnexpected, "root.flags.last", "", "", "Unexpected flag")
+	out := result.FormatMarkdown()
+	assert.Contains(t, out, "<details>\n<summary>All 11 errors</summary>\n\n| Type | Path |")
+	assert.True(t, strings.HasSuffix(out, "| unexpected | `root.flags.last` |  |  | Unexpected flag |\n\n</details>\n"))
+}
+
+func TestMarkdownCells(t *testing.T) {
+	assert.Equal(t, `a \| b c`, markdownCell("a | b\nc"))
+	assert.Equal(t, "", markdownCode(""))
+	assert.Equal(t, "`--output`", markdownCode("--output"))
+	assert.Equal(t, "`` a`b ``", markdownCode("a`b"))
+}
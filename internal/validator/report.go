@@ -0,0 +1,106 @@
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/version"
+	"gopkg.in/yaml.v3"
+)
+
+// Report formats accepted by FormatReport
+const (
+	ReportFormatText     = "text"
+	ReportFormatJSON     = "json"
+	ReportFormatYAML     = "yaml"
+	ReportFormatMarkdown = "markdown"
+)
+
+// ReportFormatSARIF is the report format for SARIF logs. They locate errors
+// in the contract file, so they are written by output.FormatSARIF rather
+// than FormatReport.
+const ReportFormatSARIF = "sarif"
+
+// ReportFormatJUnit is the report format for JUnit XML, written by
+// output.FormatJUnit
+const ReportFormatJUnit = "junit"
+
+// Report is the machine-readable form of a ValidationResult. JSON and YAML
+// reports share this schema.
+type Report struct {
+	CliguardVersion string        `json:"cliguard_version" yaml:"cliguard_version"`
+	Valid           bool          `json:"valid" yaml:"valid"`
+	ErrorCount      int           `json:"error_count" yaml:"error_count"`
+	Errors          []ReportError `json:"errors" yaml:"errors"`
+	Warnings        []ReportError `json:"warnings,omitempty" yaml:"warnings,omitempty"`
+
+	// Summary counts all the errors found, for results shortened with Top
+	// or Critical, in which case ErrorCount is more than len(Errors)
+	Summary *Summary `json:"summary,omitempty" yaml:"summary,omitempty"`
+}
+
+// ReportError is a single validation failure in a Report
+type ReportError struct {
+	Type        ErrorType `json:"type" yaml:"type"`
+	Path        string    `json:"path" yaml:"path"`
+	Expected    string    `json:"expected,omitempty" yaml:"expected,omitempty"`
+	Actual      string    `json:"actual,omitempty" yaml:"actual,omitempty"`
+	Message     string    `json:"message" yaml:"message"`
+	Description string    `json:"description,omitempty" yaml:"description,omitempty"`
+	Suggestion  string    `json:"suggestion,omitempty" yaml:"suggestion,omitempty"`
+}
+
+// Report returns the machine-readable form of the result
+func (vr *ValidationResult) Report() Report {
+	report := Report{
+		CliguardVersion: version.Version,
+		Valid:           vr.IsValid(),
+		ErrorCount:      vr.errorCount(),
+		Errors:          []ReportError{},
+		Summary:         vr.Totals,
+	}
+	for _, err := range vr.Errors {
+		report.Errors = append(report.Errors, ReportError(err))
+	}
+	for _, warning := range vr.Warnings {
+		report.Warnings = append(report.Warnings, ReportError(warning))
+	}
+	return report
+}
+
+// MarshalYAML encodes the result as a Report
+func (vr *ValidationResult) MarshalYAML() (interface{}, error) {
+	return vr.Report(), nil
+}
+
+// MarshalJSON encodes the result as a Report
+func (vr *ValidationResult) MarshalJSON() ([]byte, error) {
+	return json.Marshal(vr.Report())
+}
+
+// FormatReport renders the result as text, json, yaml or markdown
+func (vr *ValidationResult) FormatReport(format string) (string, error) {
+	switch format {
+	case ReportFormatText, "":
+		var b strings.Builder
+		vr.WriteReport(&b)
+		return b.String(), nil
+	case ReportFormatJSON:
+		data, err := json.MarshalIndent(vr.Report(), "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal report to JSON: %w", err)
+		}
+		return string(data) + "\n", nil
+	case ReportFormatYAML:
+		data, err := yaml.Marshal(vr)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal report to YAML: %w", err)
+		}
+		return string(data), nil
+	case ReportFormatMarkdown:
+		return vr.FormatMarkdown(), nil
+	default:
+		return "", fmt.Errorf("unsupported report format '%s' (supported: %s, %s, %s, %s)", format, ReportFormatText, ReportFormatJSON, ReportFormatYAML, ReportFormatMarkdown)
+	}
+}
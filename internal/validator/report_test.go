@@ -0,0 +1,87 @@
+package validator
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func sampleResult() *ValidationResult {
+	result := &ValidationResult{}
+	result.AddErrorWithDescription(ErrorTypeMissing, "root.flags.verbose", "verbose", "", "Missing flag", "Flag missing from CLI")
+	result.AddErrorWithSuggestion(ErrorTypeMismatch, "root.short", "A tool", "A CLI tool", "Short description mismatch", "Update the contract's short description")
+	return result
+}
+
+func TestValidationResult_MarshalYAMLRoundTrip(t *testing.T) {
+	result := sampleResult()
+
+	data, err := yaml.Marshal(result)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "cliguard_version: "+version.Version)
+
+	var decoded Report
+	require.NoError(t, yaml.Unmarshal(data, &decoded))
+	assert.Equal(t, result.Report(), decoded)
+	assert.False(t, decoded.Valid)
+	assert.Equal(t, 2, decoded.ErrorCount)
+	assert.Equal(t, "Update the contract's short description", decoded.Errors[1].Suggestion)
+}
+
+func TestValidationResult_JSONAndYAMLShareSchema(t *testing.T) {
+	result := sampleResult()
+
+	jsonData, err := json.Marshal(result)
+	require.NoError(t, err)
+	yamlData, err := yaml.Marshal(result)
+	require.NoError(t, err)
+
+	var fromJSON, fromYAML map[string]interface{}
+	require.NoError(t, json.Unmarshal(jsonData, &fromJSON))
+	require.NoError(t, yaml.Unmarshal(yamlData, &fromYAML))
+
+	for key := range fromJSON {
+		assert.Contains(t, fromYAML, key)
+	}
+	assert.Len(t, fromYAML, len(fromJSON))
+}
+
+func TestValidationResult_FormatReport(t *testing.T) {
+	t.Run("valid yaml", func(t *testing.T) {
+		out, err := (&ValidationResult{}).FormatReport(ReportFormatYAML)
+		require.NoError(t, err)
+		assert.Contains(t, out, "valid: true")
+		assert.Contains(t, out, "errors: []")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		out, err := sampleResult().FormatReport(ReportFormatJSON)
+		require.NoError(t, err)
+		var decoded Report
+		require.NoError(t, json.Unmarshal([]byte(out), &decoded))
+		assert.Equal(t, 2, decoded.ErrorCount)
+	})
+
+	t.Run("text", func(t *testing.T) {
+		out, err := sampleResult().FormatReport(ReportFormatText)
+		require.NoError(t, err)
+		assert.True(t, strings.Contains(out, "Total errors: 2"))
+	})
+
+	t.Run("markdown", func(t *testing.T) {
+		out, err := sampleResult().FormatReport(ReportFormatMarkdown)
+		require.NoError(t, err)
+		assert.Equal(t, sampleResult().FormatMarkdown(), out)
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		_, err := sampleResult().FormatReport("xml")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "unsupported report format")
+	})
+}
@@ -0,0 +1,150 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/contract"
+)
+
+// SuggestionFormatter builds the fix suggestions attached to validation errors
+type SuggestionFormatter struct{}
+
+// suggestions is the formatter used by Validate
+var suggestions SuggestionFormatter
+
+// flagDefinitions maps contract flag types to the pflag definition function
+// and zero value used in missing-flag suggestions
+var flagDefinitions = map[string]struct {
+	function     string
+	defaultValue string
+}{
+	"string":      {"StringVar", `""`},
+	"bool":        {"BoolVar", "false"},
+	"int":         {"IntVar", "0"},
+	"int8":        {"Int8Var", "0"},
+	"int16":       {"Int16Var", "0"},
+	"int32":       {"Int32Var", "0"},
+	"int64":       {"Int64Var", "0"},
+	"uint":        {"UintVar", "0"},
+	"uint8":       {"Uint8Var", "0"},
+	"uint16":      {"Uint16Var", "0"},
+	"uint32":      {"Uint32Var", "0"},
+	"uint64":      {"Uint64Var", "0"},
+	"float32":     {"Float32Var", "0"},
+	"float64":     {"Float64Var", "0"},
+	"count":       {"CountVar", ""},
+	"duration":    {"DurationVar", "0"},
+	"stringSlice": {"StringSliceVar", "nil"},
+	"stringArray": {"StringArrayVar", "nil"},
+	"intSlice":    {"IntSliceVar", "nil"},
+	"ip":          {"IPVar", "nil"},
+	"ipNet":       {"IPNetVar", "net.IPNet{}"},
+	"bytesHex":    {"BytesHexVar", "nil"},
+	"bytesBase64": {"BytesBase64Var", "nil"},
+}
+
+// MissingFlag suggests the Go code that defines a flag the CLI is missing, e.g.
+//
+//	cmd.Flags().StringVar(&config, "config", "", "Config file path")
+func (SuggestionFormatter) MissingFlag(flag contract.Flag) string {
+	flagSet := "Flags"
+	if flag.Persistent {
+		flagSet = "PersistentFlags"
+	}
+
+	def, known := flagDefinitions[flag.Type]
+	function := def.function
+	if !known {
+		function = "Var"
+	}
+	if flag.Shorthand != "" {
+		function += "P"
+	}
+
+	args := []string{"&" + goIdentifier(flag.Name), fmt.Sprintf("%q", flag.Name)}
+	if flag.Shorthand != "" {
+		args = append(args, fmt.Sprintf("%q", flag.Shorthand))
+	}
+	// CountVar and custom Var flags take no default value
+	if known && def.defaultValue != "" {
+		args = append(args, defaultLiteral(flag, def.defaultValue))
+	}
+	args = append(args, fmt.Sprintf("%q", flag.Usage))
+
+	return fmt.Sprintf("cmd.%s().%s(%s)", flagSet, function, strings.Join(args, ", "))
+}
+
+// defaultLiteral returns the Go literal for the flag's contract default, or
+// zeroValue if the contract has none or it cannot be written as a literal
+func defaultLiteral(flag contract.Flag, zeroValue string) string {
+	if flag.Default == "" {
+		return zeroValue
+	}
+	switch flag.Type {
+	case "string":
+		return fmt.Sprintf("%q", flag.Default)
+	case "bool", "int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64", "float32", "float64":
+		return flag.Default
+	}
+	return zeroValue
+}
+
+// FlagTypeMismatch suggests updating the contract to the flag type the CLI uses
+func (SuggestionFormatter) FlagTypeMismatch(contractType, actualType string) string {
+	return fmt.Sprintf("Change contract flag type from '%s' to '%s'", contractType, actualType)
+}
+
+// FlagCompletion suggests the Go code that registers the expected completion for a flag
+func (SuggestionFormatter) FlagCompletion(flagName, completion string) string {
+	switch completion {
+	case contract.CompletionFile:
+		return fmt.Sprintf("cmd.MarkFlagFilename(%q)", flagName)
+	case contract.CompletionDir:
+		return fmt.Sprintf("cmd.MarkFlagDirname(%q)", flagName)
+	case contract.CompletionCustom:
+		return fmt.Sprintf("cmd.RegisterFlagCompletionFunc(%q, completionFunc)", flagName)
+	default:
+		return fmt.Sprintf("Remove the completion registered for '%s' or update the contract", flagName)
+	}
+}
+
+// FlagDeprecation suggests the Go code that deprecates a flag with the
+// contract's message, or how to resolve a deprecation the contract lacks
+func (SuggestionFormatter) FlagDeprecation(flagName, message string) string {
+	if message == "" {
+		return fmt.Sprintf("Remove the MarkDeprecated call for '%s' or add its deprecation message to the contract", flagName)
+	}
+	return fmt.Sprintf("cmd.Flags().MarkDeprecated(%q, %q)", flagName, message)
+}
+
+// MissingCommand suggests the Go code that registers a command the CLI is missing
+func (SuggestionFormatter) MissingCommand(cmd contract.Command) string {
+	return fmt.Sprintf("cmd.AddCommand(&cobra.Command{Use: %q, Short: %q})", cmd.Use, cmd.Short)
+}
+
+// goIdentifier converts a flag name such as "dry-run" into a Go variable name ("dryRun")
+func goIdentifier(name string) string {
+	var b strings.Builder
+	upperNext := false
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upperNext = b.Len() > 0
+			continue
+		}
+		if b.Len() == 0 && unicode.IsDigit(r) {
+			b.WriteRune('f')
+		}
+		if upperNext {
+			r = unicode.ToUpper(r)
+			upperNext = false
+		}
+		b.WriteRune(r)
+	}
+	if b.Len() == 0 {
+		return "value"
+	}
+	return b.String()
+}
@@ -0,0 +1,196 @@
+package validator
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/contract"
+	"github.com/hiAndrewQuinn/cliguard/internal/inspector"
+)
+
+func TestSuggestionFormatter_MissingFlag(t *testing.T) {
+	tests := []struct {
+		name string
+		flag contract.Flag
+		want string
+	}{
+		{
+			name: "string flag",
+			flag: contract.Flag{Name: "config", Type: "string", Usage: "Config file path"},
+			want: `cmd.Flags().StringVar(&config, "config", "", "Config file path")`,
+		},
+		{
+			name: "bool flag with shorthand",
+			flag: contract.Flag{Name: "verbose", Shorthand: "v", Type: "bool", Usage: "Verbose output"},
+			want: `cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Verbose output")`,
+		},
+		{
+			name: "persistent int flag",
+			flag: contract.Flag{Name: "retries", Type: "int", Usage: "Retry count", Persistent: true},
+			want: `cmd.PersistentFlags().IntVar(&retries, "retries", 0, "Retry count")`,
+		},
+		{
+			name: "hyphenated name",
+			flag: contract.Flag{Name: "dry-run", Type: "bool", Usage: "Print actions only"},
+			want: `cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print actions only")`,
+		},
+		{
+			name: "contract default",
+			flag: contract.Flag{Name: "port", Shorthand: "p", Type: "int", Usage: "Port", Default: "8080"},
+			want: `cmd.Flags().IntVarP(&port, "port", "p", 8080, "Port")`,
+		},
+		{
+			name: "contract string default",
+			flag: contract.Flag{Name: "host", Type: "string", Usage: "Host", Default: "localhost"},
+			want: `cmd.Flags().StringVar(&host, "host", "localhost", "Host")`,
+		},
+		{
+			name: "count flag has no default",
+			flag: contract.Flag{Name: "v", Type: "count", Usage: "Verbosity"},
+			want: `cmd.Flags().CountVar(&v, "v", "Verbosity")`,
+		},
+		{
+			name: "custom type",
+			flag: contract.Flag{Name: "level", Shorthand: "l", Type: "logLevel", Usage: "Log level"},
+			want: `cmd.Flags().VarP(&level, "level", "l", "Log level")`,
+		},
+		{
+			name: "usage with quotes",
+			flag: contract.Flag{Name: "format", Type: "string", Usage: `Output "format"`},
+			want: `cmd.Flags().StringVar(&format, "format", "", "Output \"format\"")`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (SuggestionFormatter{}).MissingFlag(tt.flag); got != tt.want {
+				t.Errorf("MissingFlag() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSuggestionFormatter_FlagTypeMismatch(t *testing.T) {
+	got := SuggestionFormatter{}.FlagTypeMismatch("string", "int")
+	want := "Change contract flag type from 'string' to 'int'"
+	if got != want {
+		t.Errorf("FlagTypeMismatch() = %q, want %q", got, want)
+	}
+}
+
+func TestSuggestionFormatter_FlagCompletion(t *testing.T) {
+	tests := map[string]string{
+		"file":   `cmd.MarkFlagFilename("config")`,
+		"dir":    `cmd.MarkFlagDirname("config")`,
+		"custom": `cmd.RegisterFlagCompletionFunc("config", completionFunc)`,
+		"none":   "Remove the completion registered for 'config' or update the contract",
+	}
+	for completion, want := range tests {
+		if got := (SuggestionFormatter{}).FlagCompletion("config", completion); got != want {
+			t.Errorf("FlagCompletion(%q) = %s, want %s", completion, got, want)
+		}
+	}
+}
+
+func TestSuggestionFormatter_FlagDeprecation(t *testing.T) {
+	got := SuggestionFormatter{}.FlagDeprecation("old", "use --new instead")
+	want := `cmd.Flags().MarkDeprecated("old", "use --new instead")`
+	if got != want {
+		t.Errorf("FlagDeprecation() = %s, want %s", got, want)
+	}
+
+	got = SuggestionFormatter{}.FlagDeprecation("old", "")
+	want = "Remove the MarkDeprecated call for 'old' or add its deprecation message to the contract"
+	if got != want {
+		t.Errorf("FlagDeprecation() = %s, want %s", got, want)
+	}
+}
+
+func TestSuggestionFormatter_MissingCommand(t *testing.T) {
+	got := SuggestionFormatter{}.MissingCommand(contract.Command{Use: "serve", Short: "Start the server"})
+	want := `cmd.AddCommand(&cobra.Command{Use: "serve", Short: "Start the server"})`
+	if got != want {
+		t.Errorf("MissingCommand() = %s, want %s", got, want)
+	}
+}
+
+func TestGoIdentifier(t *testing.T) {
+	tests := map[string]string{
+		"config":         "config",
+		"dry-run":        "dryRun",
+		"log.level":      "logLevel",
+		"max_open_files": "maxOpenFiles",
+		"2fa":            "f2fa",
+		"---":            "value",
+	}
+	for name, want := range tests {
+		if got := goIdentifier(name); got != want {
+			t.Errorf("goIdentifier(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestValidate_Suggestions(t *testing.T) {
+	expected := &contract.Contract{
+		Use:   "app",
+		Short: "App",
+		Flags: []contract.Flag{
+			{Name: "config", Type: "string", Usage: "Config file path"},
+			{Name: "port", Type: "string", Usage: "Port"},
+		},
+		Commands: []contract.Command{
+			{Use: "serve", Short: "Start the server"},
+		},
+	}
+	actual := &inspector.InspectedCLI{
+		Use:   "app",
+		Short: "App",
+		Flags: []inspector.InspectedFlag{
+			{Name: "port", Type: "int", Usage: "Port"},
+		},
+	}
+
+	result := Validate(expected, actual)
+
+	want := map[string]string{
+		"--config": `cmd.Flags().StringVar(&config, "config", "", "Config file path")`,
+		"--port":   "Change contract flag type from 'string' to 'int'",
+		"serve":    `cmd.AddCommand(&cobra.Command{Use: "serve", Short: "Start the server"})`,
+	}
+	for _, err := range result.Errors {
+		if suggestion, ok := want[err.Path]; ok {
+			if err.Suggestion != suggestion {
+				t.Errorf("Suggestion for %s = %q, want %q", err.Path, err.Suggestion, suggestion)
+			}
+			delete(want, err.Path)
+		}
+	}
+	for path := range want {
+		t.Errorf("no error with a suggestion reported for %s", path)
+	}
+}
+
+func TestValidationResult_PrintReport_Suggestion(t *testing.T) {
+	result := &ValidationResult{}
+	result.AddErrorWithSuggestion(ErrorTypeInvalidType, "--port", "string", "int", "Flag type mismatch",
+		"Change contract flag type from 'string' to 'int'")
+
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	result.PrintReport()
+
+	w.Close()
+	os.Stdout = oldStdout
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+
+	want := "     💡 Suggestion: Change contract flag type from 'string' to 'int'\n"
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("PrintReport() output = %q, want to contain %q", buf.String(), want)
+	}
+}
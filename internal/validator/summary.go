@@ -0,0 +1,206 @@
+package validator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultSummaryTop is the number of most critical errors Summarize lists
+// when validate --summarize is used without --top
+const DefaultSummaryTop = 5
+
+// Summary counts the errors of a validation by type and by whether they
+// are about a command or a flag
+type Summary struct {
+	Missing     SummaryCount `json:"missing" yaml:"missing"`
+	Unexpected  SummaryCount `json:"unexpected" yaml:"unexpected"`
+	Mismatch    SummaryCount `json:"mismatch" yaml:"mismatch"`
+	InvalidType SummaryCount `json:"invalid_type" yaml:"invalid_type"`
+	Total       int          `json:"total" yaml:"total"`
+}
+
+// SummaryCount is the number of errors of one type in a Summary
+type SummaryCount struct {
+	Commands int `json:"commands" yaml:"commands"`
+	Flags    int `json:"flags" yaml:"flags"`
+}
+
+// Summary counts the result's errors
+func (vr *ValidationResult) Summary() Summary {
+	var s Summary
+	for _, err := range vr.Errors {
+		s.Total++
+		var count *SummaryCount
+		switch err.Type {
+		case ErrorTypeMissing:
+			count = &s.Missing
+		case ErrorTypeUnexpected:
+			count = &s.Unexpected
+		case ErrorTypeMismatch:
+			count = &s.Mismatch
+		case ErrorTypeInvalidType:
+			count = &s.InvalidType
+		default:
+			continue
+		}
+		if isFlagPath(err.Path) {
+			count.Flags++
+		} else {
+			count.Commands++
+		}
+	}
+	return s
+}
+
+// String describes the counts in one line, e.g. "Missing: 2 commands,
+// 1 flag. Mismatches: 3 flags. Total: 6 errors." Types without errors are
+// left out.
+func (s Summary) String() string {
+	var b strings.Builder
+	for _, part := range []struct {
+		label string
+		count SummaryCount
+	}{
+		{"Missing", s.Missing},
+		{"Unexpected", s.Unexpected},
+		{"Mismatches", s.Mismatch},
+		{"Type errors", s.InvalidType},
+	} {
+		var counts []string
+		if part.count.Commands > 0 {
+			counts = append(counts, Plural(part.count.Commands, "command"))
+		}
+		if part.count.Flags > 0 {
+			counts = append(counts, Plural(part.count.Flags, "flag"))
+		}
+		if len(counts) > 0 {
+			fmt.Fprintf(&b, "%s: %s. ", part.label, strings.Join(counts, ", "))
+		}
+	}
+	fmt.Fprintf(&b, "Total: %s.", Plural(s.Total, "error"))
+	return b.String()
+}
+
+// Summarize describes the result in a few lines, for validations with too
+// many errors to read through: the error counts, then the topN most
+// critical errors, as ranked by CriticalErrors.
+func (vr *ValidationResult) Summarize(topN int) string {
+	var b strings.Builder
+	b.WriteString(vr.Summary().String())
+	b.WriteString("\n")
+
+	critical := vr.CriticalErrors(topN)
+	if len(critical) == 0 {
+		return b.String()
+	}
+	if len(critical) < len(vr.Errors) {
+		fmt.Fprintf(&b, "\nMost critical errors (%d of %d):\n", len(critical), len(vr.Errors))
+	} else {
+		b.WriteString("\nMost critical errors:\n")
+	}
+	for _, err := range critical {
+		fmt.Fprintf(&b, "   • %s: %s\n", errorLabel(err), err.Path)
+	}
+	return b.String()
+}
+
+// CriticalErrors returns the n errors with the widest impact: errors on the
+// root command and its flags first, then on first-level commands, then on
+// deeper ones. Errors of the same rank keep their report order. n <= 0
+// returns all the errors, ranked.
+func (vr *ValidationResult) CriticalErrors(n int) []ValidationError {
+	ranked := make([]ValidationError, len(vr.Errors))
+	copy(ranked, vr.Errors)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return criticality(ranked[i].Path) < criticality(ranked[j].Path)
+	})
+	if n > 0 && n < len(ranked) {
+		ranked = ranked[:n]
+	}
+	return ranked
+}
+
+// Critical returns a copy of the result that reports only its n most
+// critical errors, as ranked by CriticalErrors, with the Summary of all of
+// them
+func (vr *ValidationResult) Critical(n int) *ValidationResult {
+	return vr.withErrors(vr.CriticalErrors(n))
+}
+
+// Top returns a copy of the result that reports only its first n errors,
+// with the Summary of all of them. n <= 0 reports all the errors.
+func (vr *ValidationResult) Top(n int) *ValidationResult {
+	errs := vr.Errors
+	if n > 0 && n < len(errs) {
+		errs = errs[:n]
+	}
+	return vr.withErrors(errs)
+}
+
+// withErrors returns a copy of the result with errs in place of its errors
+// and the summary of its own
+func (vr *ValidationResult) withErrors(errs []ValidationError) *ValidationResult {
+	summary := vr.Summary()
+	shown := *vr
+	shown.Errors = errs
+	shown.Totals = &summary
+	return &shown
+}
+
+// errorCount is the number of errors the validation found, which is more
+// than the errors listed if the result was shortened with Top or Critical
+func (vr *ValidationResult) errorCount() int {
+	if vr.Totals != nil {
+		return vr.Totals.Total
+	}
+	return len(vr.Errors)
+}
+
+// criticality ranks an error path: 0 for the root command and flags
+// defined on it, 1 for first-level commands and their flags and 2 for
+// anything deeper. Arguments in use lines, such as "<file>", are not
+// counted as commands.
+func criticality(path string) int {
+	if path == "root" {
+		return 0
+	}
+	depth := 0
+	for _, field := range strings.Fields(path) {
+		if !strings.HasPrefix(field, "-") && !strings.ContainsAny(field[:1], "<[{") {
+			depth++
+		}
+	}
+	if depth > 2 {
+		return 2
+	}
+	return depth
+}
+
+// isFlagPath reports whether an error path is a flag's, such as
+// "serve --port", rather than a command's
+func isFlagPath(path string) bool {
+	fields := strings.Fields(path)
+	return len(fields) > 0 && strings.HasPrefix(fields[len(fields)-1], "--")
+}
+
+// errorLabel describes an error for a one-line listing, e.g. "Missing
+// flag" or "Flag type mismatch"
+func errorLabel(err ValidationError) string {
+	switch err.Type {
+	case ErrorTypeMissing:
+		return "Missing " + err.Message
+	case ErrorTypeUnexpected:
+		return "Unexpected " + err.Message
+	default:
+		return err.Message
+	}
+}
+
+// Plural formats a count of things, e.g. "1 flag" or "3 flags"
+func Plural(n int, thing string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", thing)
+	}
+	return fmt.Sprintf("%d %ss", n, thing)
+}
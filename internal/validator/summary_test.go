@@ -0,0 +1,107 @@
+package validator
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func summaryResult() *ValidationResult {
+	result := &ValidationResult{}
+	result.AddError(ErrorTypeMissing, "db migrate", "migrate", "", "command")
+	result.AddError(ErrorTypeMissing, "db migrate --steps", "steps", "", "flag")
+	result.AddError(ErrorTypeMissing, "serve --port", "port", "", "flag")
+	result.AddError(ErrorTypeUnexpected, "debug", "", "debug", "command")
+	result.AddError(ErrorTypeMismatch, "serve", "Serve", "Serve it", "Mismatch in short description")
+	result.AddError(ErrorTypeInvalidType, "serve --port", "int", "string", "Flag type mismatch")
+	result.AddError(ErrorTypeMismatch, "--verbose", "v", "V", "Flag shorthand mismatch")
+	result.AddError(ErrorTypeMismatch, "root", "A tool", "A CLI tool", "Mismatch in short description")
+	return result
+}
+
+func TestValidationResult_Summary(t *testing.T) {
+	summary := summaryResult().Summary()
+	assert.Equal(t, Summary{
+		Missing:     SummaryCount{Commands: 1, Flags: 2},
+		Unexpected:  SummaryCount{Commands: 1},
+		Mismatch:    SummaryCount{Commands: 2, Flags: 1},
+		InvalidType: SummaryCount{Flags: 1},
+		Total:       8,
+	}, summary)
+	assert.Equal(t, "Missing: 1 command, 2 flags. Unexpected: 1 command. Mismatches: 2 commands, 1 flag. Type errors: 1 flag. Total: 8 errors.", summary.String())
+
+	assert.Equal(t, "Total: 0 errors.", (&ValidationResult{}).Summary().String())
+}
+
+func TestValidationResult_CriticalErrors(t *testing.T) {
+	var paths []string
+	for _, err := range summaryResult().CriticalErrors(0) {
+		paths = append(paths, err.Path)
+	}
+	assert.Equal(t, []string{
+		"--verbose",
+		"root",
+		"serve --port",
+		"debug",
+		"serve",
+		"serve --port",
+		"db migrate",
+		"db migrate --steps",
+	}, paths)
+
+	assert.Len(t, summaryResult().CriticalErrors(3), 3)
+	assert.Equal(t, 1, criticality("clone <repository> [<directory>]"))
+}
+
+func TestValidationResult_Summarize(t *testing.T) {
+	out := summaryResult().Summarize(DefaultSummaryTop)
+	assert.Equal(t, `Missing: 1 command, 2 flags. Unexpected: 1 command. Mismatches: 2 commands, 1 flag. Type errors: 1 flag. Total: 8 errors.
+
+Most critical errors (5 of 8):
+   • Flag shorthand mismatch: --verbose
+   • Mismatch in short description: root
+   • Missing flag: serve --port
+   • Unexpected command: debug
+   • Mismatch in short description: serve
+`, out)
+
+	assert.Equal(t, "Total: 0 errors.\n", (&ValidationResult{}).Summarize(DefaultSummaryTop))
+}
+
+func TestValidationResult_Top(t *testing.T) {
+	result := summaryResult()
+	top := result.Top(2)
+	require.Len(t, top.Errors, 2)
+	assert.Equal(t, "db migrate", top.Errors[0].Path)
+	assert.Len(t, result.Errors, 8, "Top must not change the result")
+	assert.False(t, top.IsValid())
+
+	var text strings.Builder
+	top.WriteReport(&text)
+	assert.Contains(t, text.String(), "Total errors: 8 (2 shown)")
+	assert.Contains(t, top.FormatMarkdown(), "failed with 8 error(s)")
+	assert.Contains(t, top.FormatMarkdown(), "2 of 8 errors shown.")
+
+	out, err := top.FormatReport(ReportFormatJSON)
+	require.NoError(t, err)
+	var report map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(out), &report))
+	assert.EqualValues(t, 8, report["error_count"])
+	assert.Len(t, report["errors"], 2)
+	summary, ok := report["summary"].(map[string]interface{})
+	require.True(t, ok, "report has no summary: %s", out)
+	assert.EqualValues(t, 8, summary["total"])
+	assert.Equal(t, map[string]interface{}{"commands": 1.0, "flags": 2.0}, summary["missing"])
+
+	full, err := result.FormatReport(ReportFormatJSON)
+	require.NoError(t, err)
+	assert.NotContains(t, full, `"summary"`)
+
+	critical := result.Critical(1)
+	require.Len(t, critical.Errors, 1)
+	assert.Equal(t, "--verbose", critical.Errors[0].Path)
+	assert.Equal(t, 8, critical.Totals.Total)
+}
@@ -1,11 +1,41 @@
 package validator
 
-import "fmt"
+import (
+	"fmt"
+	"io"
+	"os"
+)
 
 // ValidationResult holds the results of validating a CLI against a contract
 type ValidationResult struct {
 	Valid  bool
 	Errors []ValidationError
+
+	// Warnings are differences that don't fail validation, because they
+	// can't be checked reliably
+	Warnings []ValidationError
+
+	// Stopped is set when validation stopped after its first error, as
+	// requested with Options.FailFast
+	Stopped bool
+
+	// Strict is set when the contract was validated as the complete set of
+	// commands and flags (see Options.Strict), so that every command or
+	// flag it doesn't list is an ErrorTypeUnexpected error
+	Strict bool
+
+	// WarnOnly is set when the errors are reported as warnings and don't
+	// fail validation, so that reports mark them with ⚠️ rather than ❌
+	WarnOnly bool
+
+	// failFast stops validation, and ignores further errors, after the
+	// first error is added
+	failFast bool
+
+	// Totals, when set, counts all the errors the validation found, of
+	// which Errors lists only some. Results shortened with Top or Critical
+	// have it.
+	Totals *Summary
 }
 
 // ValidationError represents a single validation failure
@@ -16,6 +46,7 @@ type ValidationError struct {
 	Actual      string
 	Message     string
 	Description string // Additional descriptive text for the error
+	Suggestion  string // Suggested fix, if one is known
 }
 
 // ErrorType defines the type of validation error
@@ -26,6 +57,11 @@ const (
 	ErrorTypeUnexpected  ErrorType = "unexpected"
 	ErrorTypeMismatch    ErrorType = "mismatch"
 	ErrorTypeInvalidType ErrorType = "invalid_type"
+
+	// ErrorTypeVisibility is a flag hidden when the contract lists it as
+	// visible, or the other way around. Visibility only changes what help
+	// shows, so it is reported as a warning.
+	ErrorTypeVisibility ErrorType = "visibility"
 )
 
 // IsValid returns true if there are no validation errors
@@ -35,7 +71,7 @@ func (vr *ValidationResult) IsValid() bool {
 
 // AddError adds a new validation error to the result
 func (vr *ValidationResult) AddError(errorType ErrorType, path, expected, actual, message string) {
-	vr.Errors = append(vr.Errors, ValidationError{
+	vr.add(ValidationError{
 		Type:        errorType,
 		Path:        path,
 		Expected:    expected,
@@ -43,12 +79,11 @@ func (vr *ValidationResult) AddError(errorType ErrorType, path, expected, actual
 		Message:     message,
 		Description: message, // For backward compatibility, use message as description
 	})
-	vr.Valid = false
 }
 
 // AddErrorWithDescription adds a new validation error with a separate description
 func (vr *ValidationResult) AddErrorWithDescription(errorType ErrorType, path, expected, actual, message, description string) {
-	vr.Errors = append(vr.Errors, ValidationError{
+	vr.add(ValidationError{
 		Type:        errorType,
 		Path:        path,
 		Expected:    expected,
@@ -56,14 +91,58 @@ func (vr *ValidationResult) AddErrorWithDescription(errorType ErrorType, path, e
 		Message:     message,
 		Description: description,
 	})
+}
+
+// AddWarning adds a validation warning, which doesn't make the result invalid
+func (vr *ValidationResult) AddWarning(errorType ErrorType, path, expected, actual, message string) {
+	vr.Warnings = append(vr.Warnings, ValidationError{
+		Type:        errorType,
+		Path:        path,
+		Expected:    expected,
+		Actual:      actual,
+		Message:     message,
+		Description: message,
+	})
+}
+
+// AddErrorWithSuggestion adds a new validation error with a suggested fix
+func (vr *ValidationResult) AddErrorWithSuggestion(errorType ErrorType, path, expected, actual, message, suggestion string) {
+	vr.add(ValidationError{
+		Type:        errorType,
+		Path:        path,
+		Expected:    expected,
+		Actual:      actual,
+		Message:     message,
+		Description: message,
+		Suggestion:  suggestion,
+	})
+}
+
+// add adds err to the result, unless validation has stopped
+func (vr *ValidationResult) add(err ValidationError) {
+	if vr.Stopped {
+		return
+	}
+	vr.Errors = append(vr.Errors, err)
 	vr.Valid = false
+	vr.Stopped = vr.failFast
 }
 
 // PrintReport prints a human-readable validation report
 func (vr *ValidationResult) PrintReport() {
+	vr.WriteReport(os.Stdout)
+}
+
+// WriteReport writes a human-readable validation report to w
+func (vr *ValidationResult) WriteReport(w io.Writer) {
 	// Group errors by type for better organization
 	var missingErrors, unexpectedErrors, mismatchErrors, invalidTypeErrors []ValidationError
 
+	marker, total := "❌", "Total errors"
+	if vr.WarnOnly {
+		marker, total = "⚠️ ", "Total warnings"
+	}
+
 	for _, err := range vr.Errors {
 		switch err.Type {
 		case ErrorTypeMissing:
@@ -79,51 +158,66 @@ func (vr *ValidationResult) PrintReport() {
 
 	// Print missing errors
 	if len(missingErrors) > 0 {
-		fmt.Println("\n❌ Missing items:")
+		fmt.Fprintf(w, "\n%s Missing items:\n", marker)
 		for _, err := range missingErrors {
-			fmt.Printf("   • %s: %s\n", err.Description, err.Path)
+			fmt.Fprintf(w, "   • %s: %s\n", err.Description, err.Path)
 			if err.Expected != "" {
-				fmt.Printf("     Add to contract: %s\n", err.Expected)
+				fmt.Fprintf(w, "     Add to contract: %s\n", err.Expected)
 			}
+			printSuggestion(w, err)
 		}
 	}
 
 	// Print unexpected errors
 	if len(unexpectedErrors) > 0 {
-		fmt.Println("\n❌ Unexpected items:")
+		fmt.Fprintf(w, "\n%s Unexpected items:\n", marker)
 		for _, err := range unexpectedErrors {
-			fmt.Printf("   • %s\n", err.Path)
-			fmt.Printf("     %s\n", err.Message)
+			fmt.Fprintf(w, "   • %s\n", err.Path)
+			fmt.Fprintf(w, "     %s\n", err.Message)
 			if err.Actual != "" {
-				fmt.Printf("     Found: %s\n", err.Actual)
+				fmt.Fprintf(w, "     Found: %s\n", err.Actual)
 			}
+			printSuggestion(w, err)
 		}
 	}
 
 	// Print mismatch errors
 	if len(mismatchErrors) > 0 {
-		fmt.Println("\n❌ Mismatches:")
+		fmt.Fprintf(w, "\n%s Mismatches:\n", marker)
 		for _, err := range mismatchErrors {
-			fmt.Printf("   • %s\n", err.Path)
-			fmt.Printf("     Contract: %s\n", err.Expected)
-			fmt.Printf("     Actual:   %s\n", err.Actual)
+			fmt.Fprintf(w, "   • %s\n", err.Path)
+			fmt.Fprintf(w, "     Contract: %s\n", err.Expected)
+			fmt.Fprintf(w, "     Actual:   %s\n", err.Actual)
 			if err.Description != "" {
-				fmt.Printf("     %s\n", err.Description)
+				fmt.Fprintf(w, "     %s\n", err.Description)
 			}
+			printSuggestion(w, err)
 		}
 	}
 
 	// Print invalid type errors
 	if len(invalidTypeErrors) > 0 {
-		fmt.Println("\n❌ Invalid types:")
+		fmt.Fprintf(w, "\n%s Invalid types:\n", marker)
 		for _, err := range invalidTypeErrors {
-			fmt.Printf("   • %s\n", err.Path)
-			fmt.Printf("     %s\n", err.Message)
-			fmt.Printf("     Expected type: %s\n", err.Expected)
-			fmt.Printf("     Actual type:   %s\n", err.Actual)
+			fmt.Fprintf(w, "   • %s\n", err.Path)
+			fmt.Fprintf(w, "     %s\n", err.Message)
+			fmt.Fprintf(w, "     Expected type: %s\n", err.Expected)
+			fmt.Fprintf(w, "     Actual type:   %s\n", err.Actual)
+			printSuggestion(w, err)
 		}
 	}
 
 	// Print summary
-	fmt.Printf("\nTotal errors: %d\n", len(vr.Errors))
+	if count := vr.errorCount(); count > len(vr.Errors) {
+		fmt.Fprintf(w, "\n%s: %d (%d shown)\n", total, count, len(vr.Errors))
+	} else {
+		fmt.Fprintf(w, "\n%s: %d\n", total, count)
+	}
+}
+
+// printSuggestion prints the error's suggested fix, if any
+func printSuggestion(w io.Writer, err ValidationError) {
+	if err.Suggestion != "" {
+		fmt.Fprintf(w, "     💡 Suggestion: %s\n", err.Suggestion)
+	}
 }
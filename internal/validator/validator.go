@@ -51,51 +51,276 @@ import (
 // The function returns a ValidationResult containing all validation errors found.
 // An empty Errors slice indicates successful validation.
 func Validate(expected *contract.Contract, actual *inspector.InspectedCLI) *ValidationResult {
-	result := &ValidationResult{Valid: true}
+	return ValidateWithOptions(expected, actual, Options{})
+}
+
+// Options enables optional, stricter checks in ValidateWithOptions, or
+// turns off default ones
+type Options struct {
+	// StrictSortOrder checks that commands with a contract.Command.SortOrder
+	// are listed in ascending sort order among their siblings
+	StrictSortOrder bool
+
+	// ExpandedContract treats each command's flags in the contract as the
+	// complete set it accepts, including inherited persistent flags, as
+	// generated with --include-persistent-flags. The inherited flags of the
+	// actual CLI are added to each command before comparing, and the
+	// contract's persistent flags are not tracked for shorthand conflicts.
+	ExpandedContract bool
+
+	// UseNameOnly compares each command's Use in the contract with only the
+	// command name of the actual command (see inspector.InspectedCommand.UseName),
+	// for contracts generated with --cobra-use-name-only whose Use fields
+	// omit the argument pattern
+	UseNameOnly bool
+
+	// ExpectVersion checks that the version the CLI prints (see
+	// inspector.InspectedCLI.VersionOutput) is the contract's version, by
+	// semantic versioning precedence
+	ExpectVersion bool
+
+	// IgnoreShort skips comparing the short descriptions of the commands
+	IgnoreShort bool
+
+	// IgnoreLong skips comparing the long descriptions of the commands, for
+	// CLIs whose long descriptions are formatted differently from the
+	// contract's
+	IgnoreLong bool
+
+	// FailFast stops validation after the first error, which is then the
+	// only one reported; ValidationResult.Stopped is set if it was found
+	FailFast bool
+
+	// Strict treats the contract as the complete set of commands and flags:
+	// hidden commands and flags, deprecated flags, and commands that run
+	// nothing, which are otherwise only validated when the contract lists
+	// them, are reported as unexpected too, as are command aliases the
+	// contract doesn't list. ValidationResult.Strict is set.
+	Strict bool
+
+	// FocusPaths limits validation to the commands at these dot-separated
+	// command paths below the root, e.g. "create.user", and their
+	// subcommands. Other commands, and the flags and fields of the commands
+	// leading to the focused ones, are not validated. A path leading to no
+	// command of either the contract or the CLI is reported as missing.
+	FocusPaths []string
+
+	// runnableOnly is set when the contract tracks runnability (see
+	// tracksRunnable). Commands that run nothing are then left out of it,
+	// so they aren't reported as unexpected.
+	runnableOnly bool
+}
+
+// use returns the Use of the actual command to compare with the contract
+func (o Options) use(cmd *inspector.InspectedCommand) string {
+	if o.UseNameOnly {
+		return cmd.UseName()
+	}
+	return cmd.Use
+}
+
+// ValidateInOrder is like Validate, with the errors sorted by path, then
+// type, expected and actual value, rather than in the order the checks
+// found them, for comparing results independently of check order
+func ValidateInOrder(expected *contract.Contract, actual *inspector.InspectedCLI) *ValidationResult {
+	result := Validate(expected, actual)
+	sortErrors(result.Errors)
+	return result
+}
+
+// sortErrors sorts errors by path, type, expected and actual value
+func sortErrors(errors []ValidationError) {
+	sort.SliceStable(errors, func(i, j int) bool {
+		a, b := errors[i], errors[j]
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		if a.Expected != b.Expected {
+			return a.Expected < b.Expected
+		}
+		return a.Actual < b.Actual
+	})
+}
+
+// sortedKeys returns the keys of m in ascending order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ValidateWithOptions is like Validate, with the optional checks enabled in opts
+func ValidateWithOptions(expected *contract.Contract, actual *inspector.InspectedCLI, opts Options) *ValidationResult {
+	result := &ValidationResult{Valid: true, Strict: opts.Strict, failFast: opts.FailFast}
+
+	if opts.ExpandedContract {
+		actual = expandInheritedFlags(actual)
+	}
+	opts.runnableOnly = tracksRunnable(expected.Commands)
+	if len(opts.FocusPaths) > 0 {
+		for _, path := range unmatchedFocusPaths(expected, actual, opts.FocusPaths) {
+			result.AddErrorWithDescription(ErrorTypeMissing, path, "", "",
+				"Missing focus path",
+				"No command of the contract or the CLI is at this path")
+		}
+		expected = filterContract(expected, opts.FocusPaths)
+		actual = filterInspected(actual, opts.FocusPaths)
+	}
 
 	// Validate root command
-	validateRootCommand(expected, actual, result)
+	validateRootCommand(expected, actual, opts, result)
+	if opts.ExpectVersion {
+		validateVersion(expected, actual, result)
+	}
 
 	// Validate flags
-	validateFlags("", expected.Flags, actual.Flags, result)
+	if !result.Stopped {
+		validateFlags("", expected.Flags, actual.Flags, result)
+	}
 
 	// Validate subcommands
-	validateCommands("", expected.Commands, actual.Commands, result)
+	if !result.Stopped {
+		validateCommands("", expected.Commands, actual.Commands, opts, result)
+	}
+
+	// Check the contract itself for shorthands Cobra would reject. In an
+	// expanded contract a conflict shows up as a mismatch with the inherited
+	// flag listed on the command instead.
+	if !opts.ExpandedContract && !result.Stopped {
+		validateShorthandConflicts("", expected.Commands, inheritedShorthands(nil, expected.Flags), result)
+	}
+
+	return result
+}
+
+// expandInheritedFlags returns a copy of actual in which each subcommand's
+// flags include the persistent flags it inherits. A command's own flag
+// shadows an inherited flag with the same name, as in Cobra.
+func expandInheritedFlags(actual *inspector.InspectedCLI) *inspector.InspectedCLI {
+	expanded := *actual
+	expanded.Commands = expandInheritedCommandFlags(actual.Commands, inheritedInspectedFlags(nil, actual.Flags))
+	return &expanded
+}
+
+// expandInheritedCommandFlags adds the inherited flags to each command and,
+// recursively, to its subcommands
+func expandInheritedCommandFlags(commands []inspector.InspectedCommand, inherited []inspector.InspectedFlag) []inspector.InspectedCommand {
+	if commands == nil {
+		return nil
+	}
+	result := make([]inspector.InspectedCommand, len(commands))
+	for i, cmd := range commands {
+		childInherited := inheritedInspectedFlags(inherited, cmd.Flags)
+		cmd.Flags = append(append([]inspector.InspectedFlag(nil), cmd.Flags...), unshadowedFlags(inherited, cmd.Flags)...)
+		cmd.Commands = expandInheritedCommandFlags(cmd.Commands, childInherited)
+		result[i] = cmd
+	}
+	return result
+}
 
+// inheritedInspectedFlags returns the flags inherited by the subcommands of
+// a command with the given flags
+func inheritedInspectedFlags(inherited, flags []inspector.InspectedFlag) []inspector.InspectedFlag {
+	var result []inspector.InspectedFlag
+	for _, flag := range flags {
+		if flag.Persistent {
+			result = append(result, flag)
+		}
+	}
+	return append(result, unshadowedFlags(inherited, flags)...)
+}
+
+// unshadowedFlags returns the inherited flags whose names are not in flags
+func unshadowedFlags(inherited, flags []inspector.InspectedFlag) []inspector.InspectedFlag {
+	names := make(map[string]bool, len(flags))
+	for _, flag := range flags {
+		names[flag.Name] = true
+	}
+	var result []inspector.InspectedFlag
+	for _, flag := range inherited {
+		if !names[flag.Name] {
+			result = append(result, flag)
+		}
+	}
 	return result
 }
 
-func validateRootCommand(expected *contract.Contract, actual *inspector.InspectedCLI, result *ValidationResult) {
+// inheritedShorthands returns the shorthand-to-flag-name map seen by the
+// subcommands of a command: the inherited map plus the command's own
+// persistent flags
+func inheritedShorthands(inherited map[string]string, flags []contract.Flag) map[string]string {
+	shorthands := make(map[string]string, len(inherited))
+	for shorthand, name := range inherited {
+		shorthands[shorthand] = name
+	}
+	for _, flag := range flags {
+		if flag.Persistent && flag.Shorthand != "" {
+			shorthands[flag.Shorthand] = flag.Name
+		}
+	}
+	return shorthands
+}
+
+// validateShorthandConflicts reports flags whose shorthand is already taken
+// by a different persistent flag inherited from an ancestor. Cobra panics
+// when it merges such flag sets, so no CLI can satisfy the contract. Sibling
+// commands may reuse shorthands, since their flag sets are never merged.
+func validateShorthandConflicts(parentPath string, commands []contract.Command, inherited map[string]string, result *ValidationResult) {
+	for _, cmd := range commands {
+		cmdPath := contract.JoinPath(parentPath, cmd.Use)
+		for _, flag := range cmd.Flags {
+			owner, taken := inherited[flag.Shorthand]
+			if flag.Shorthand == "" || !taken || owner == flag.Name {
+				continue
+			}
+			result.AddErrorWithDescription(ErrorTypeMismatch, contract.JoinPath(cmdPath, "--"+flag.Name),
+				"--"+owner, "--"+flag.Name,
+				"Flag shorthand conflict",
+				fmt.Sprintf("Shorthand -%s is already used by the inherited persistent flag --%s", flag.Shorthand, owner))
+		}
+		validateShorthandConflicts(cmdPath, cmd.Commands, inheritedShorthands(inherited, cmd.Flags), result)
+	}
+}
+
+func validateRootCommand(expected *contract.Contract, actual *inspector.InspectedCLI, opts Options, result *ValidationResult) {
 	// Validate Use field
-	if expected.Use != actual.Use {
-		result.AddError(ErrorTypeMismatch, "root", expected.Use, actual.Use, "Mismatch in 'use' field")
+	actualUse := actual.Use
+	if opts.UseNameOnly {
+		actualUse = actual.UseName()
+	}
+	if expected.Use != actualUse {
+		result.AddError(ErrorTypeMismatch, "root", expected.Use, actualUse, "Mismatch in 'use' field")
 	}
 
 	// Validate Short description
-	if expected.Short != "" && expected.Short != actual.Short {
+	if !opts.IgnoreShort && expected.Short != "" && expected.Short != actual.Short {
 		result.AddError(ErrorTypeMismatch, "root", expected.Short, actual.Short, "Mismatch in short description")
 	}
 
 	// Validate Long description if specified
-	if expected.Long != "" && expected.Long != actual.Long {
+	if !opts.IgnoreLong && expected.Long != "" && expected.Long != actual.Long {
 		result.AddError(ErrorTypeMismatch, "root", expected.Long, actual.Long, "Mismatch in long description")
 	}
 
-	// Validate aliases if specified
-	if len(expected.Aliases) > 0 && !slicesEqual(expected.Aliases, actual.Aliases) {
-		result.AddError(ErrorTypeMismatch, "root", 
-			strings.Join(expected.Aliases, ", "), 
-			strings.Join(actual.Aliases, ", "), 
-			"Mismatch in command aliases")
-	}
+	validateAliases("root", expected.Aliases, actual.Aliases, result)
 
 	// Validate example if specified
 	if expected.Example != "" && expected.Example != actual.Example {
 		result.AddError(ErrorTypeMismatch, "root", expected.Example, actual.Example, "Mismatch in command example")
 	}
+
+	// Validate flag groups if specified
+	validateFlagGroups("root", expected.MutuallyExclusive, actual.MutuallyExclusive, "Mismatch in mutually exclusive flags", result)
+	validateFlagGroups("root", expected.RequiredTogether, actual.RequiredTogether, "Mismatch in required together flags", result)
 }
 
-func validateCommands(parentPath string, expected []contract.Command, actual []inspector.InspectedCommand, result *ValidationResult) {
+func validateCommands(parentPath string, expected []contract.Command, actual []inspector.InspectedCommand, opts Options, result *ValidationResult) {
 	// Create maps for easier lookup
 	expectedMap := make(map[string]*contract.Command)
 	for i := range expected {
@@ -104,68 +329,172 @@ func validateCommands(parentPath string, expected []contract.Command, actual []i
 
 	actualMap := make(map[string]*inspector.InspectedCommand)
 	for i := range actual {
-		actualMap[actual[i].Use] = &actual[i]
+		actualMap[opts.use(&actual[i])] = &actual[i]
 	}
 
 	// Check for missing commands
 	for _, exp := range expected {
-		cmdPath := joinPath(parentPath, exp.Use)
+		cmdPath := contract.JoinPath(parentPath, exp.Use)
 		if _, found := actualMap[exp.Use]; !found {
-			result.AddError(ErrorTypeMissing, cmdPath, exp.Use, "", "command")
+			result.AddErrorWithSuggestion(ErrorTypeMissing, cmdPath, exp.Use, "", "command", suggestions.MissingCommand(exp))
 		}
 	}
 
-	// Check for unexpected commands
-	for _, act := range actual {
-		cmdPath := joinPath(parentPath, act.Use)
-		if _, found := expectedMap[act.Use]; !found {
-			result.AddError(ErrorTypeUnexpected, cmdPath, "", act.Use, "command")
+	// Check for unexpected commands. Unless strict, hidden commands are
+	// only validated when the contract tracks them, and so are commands
+	// that run nothing, neither themselves nor through a subcommand, in
+	// contracts generated with --runnable-only.
+	for i := range actual {
+		act := &actual[i]
+		cmdPath := contract.JoinPath(parentPath, opts.use(act))
+		if _, found := expectedMap[opts.use(act)]; !found {
+			if !opts.Strict && (act.Hidden || (opts.runnableOnly && !runsAnything(act))) {
+				continue
+			}
+			result.AddError(ErrorTypeUnexpected, cmdPath, "", opts.use(act), "command")
 		}
 	}
 
-	// Validate matching commands
-	for use, exp := range expectedMap {
+	// Validate matching commands, in sorted order so that the errors are
+	// reported in the same order on every run
+	for _, use := range sortedKeys(expectedMap) {
+		if result.Stopped {
+			return
+		}
 		if act, found := actualMap[use]; found {
-			cmdPath := joinPath(parentPath, use)
-			validateCommand(cmdPath, exp, act, result)
+			cmdPath := contract.JoinPath(parentPath, use)
+			validateCommand(cmdPath, expectedMap[use], act, opts, result)
 		}
 	}
+
+	if opts.StrictSortOrder {
+		validateSortOrder(parentPath, expected, actual, opts, result)
+	}
 }
 
-func validateCommand(path string, expected *contract.Command, actual *inspector.InspectedCommand, result *ValidationResult) {
+// validateSortOrder checks that the commands with a sort order appear in the
+// actual listing in ascending sort order. Missing commands are skipped, since
+// they are reported already.
+func validateSortOrder(parentPath string, expected []contract.Command, actual []inspector.InspectedCommand, opts Options, result *ValidationResult) {
+	index := make(map[string]int)
+	for i := range actual {
+		index[opts.use(&actual[i])] = i
+	}
+
+	var ordered []contract.Command
+	for _, exp := range expected {
+		if _, found := index[exp.Use]; found && exp.SortOrder > 0 {
+			ordered = append(ordered, exp)
+		}
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].SortOrder < ordered[j].SortOrder
+	})
+
+	expectedOrder := make([]string, len(ordered))
+	for i, cmd := range ordered {
+		expectedOrder[i] = cmd.Use
+	}
+	actualOrder := append([]string(nil), expectedOrder...)
+	sort.SliceStable(actualOrder, func(i, j int) bool {
+		return index[actualOrder[i]] < index[actualOrder[j]]
+	})
+
+	if strings.Join(expectedOrder, ", ") != strings.Join(actualOrder, ", ") {
+		path := parentPath
+		if path == "" {
+			path = "root"
+		}
+		result.AddError(ErrorTypeMismatch, path,
+			strings.Join(expectedOrder, ", "),
+			strings.Join(actualOrder, ", "),
+			"Command order mismatch")
+	}
+}
+
+func validateCommand(path string, expected *contract.Command, actual *inspector.InspectedCommand, opts Options, result *ValidationResult) {
 	// Validate Use field (should already match, but just in case)
-	if expected.Use != actual.Use {
-		result.AddError(ErrorTypeMismatch, path, expected.Use, actual.Use, "Mismatch in 'use' field")
+	if expected.Use != opts.use(actual) {
+		result.AddError(ErrorTypeMismatch, path, expected.Use, opts.use(actual), "Mismatch in 'use' field")
 	}
 
 	// Validate Short description
-	if expected.Short != "" && expected.Short != actual.Short {
+	if !opts.IgnoreShort && expected.Short != "" && expected.Short != actual.Short {
 		result.AddError(ErrorTypeMismatch, path, expected.Short, actual.Short, "Mismatch in short description")
 	}
 
 	// Validate Long description if specified
-	if expected.Long != "" && expected.Long != actual.Long {
+	if !opts.IgnoreLong && expected.Long != "" && expected.Long != actual.Long {
 		result.AddError(ErrorTypeMismatch, path, expected.Long, actual.Long, "Mismatch in long description")
 	}
 
-	// Validate aliases if specified
-	if len(expected.Aliases) > 0 && !slicesEqual(expected.Aliases, actual.Aliases) {
-		result.AddError(ErrorTypeMismatch, path, 
-			strings.Join(expected.Aliases, ", "), 
-			strings.Join(actual.Aliases, ", "), 
-			"Mismatch in command aliases")
-	}
+	validateAliases(path, expected.Aliases, actual.Aliases, result)
 
 	// Validate example if specified
 	if expected.Example != "" && expected.Example != actual.Example {
 		result.AddError(ErrorTypeMismatch, path, expected.Example, actual.Example, "Mismatch in command example")
 	}
 
+	// Validate help group if specified
+	if expected.GroupID != "" && expected.GroupID != actual.GroupID {
+		result.AddError(ErrorTypeMismatch, path, expected.GroupID, actual.GroupID, "Mismatch in command group")
+	}
+
+	// Validate flag groups if specified
+	validateFlagGroups(path, expected.MutuallyExclusive, actual.MutuallyExclusive, "Mismatch in mutually exclusive flags", result)
+	validateFlagGroups(path, expected.RequiredTogether, actual.RequiredTogether, "Mismatch in required together flags", result)
+
+	// Validate visibility
+	if expected.Hidden != actual.Hidden {
+		result.AddError(ErrorTypeMismatch, path, visibility(expected.Hidden), visibility(actual.Hidden), "Command visibility mismatch")
+	}
+
+	// Validate runnability if specified
+	if expected.Runnable != nil && *expected.Runnable != actual.Runnable {
+		result.AddError(ErrorTypeMismatch, path, runnability(*expected.Runnable), runnability(actual.Runnable), "Command runnability mismatch")
+	}
+
+	if result.Stopped {
+		return
+	}
+
 	// Validate flags
 	validateFlags(path, expected.Flags, actual.Flags, result)
 
 	// Validate subcommands recursively
-	validateCommands(path, expected.Commands, actual.Commands, result)
+	if !result.Stopped {
+		validateCommands(path, expected.Commands, actual.Commands, opts, result)
+	}
+}
+
+// validateFlagGroups checks that a command has the flag groups the contract
+// lists, if it lists any, ignoring the order of the groups and of the flags
+// in each group. The groups are reported as "a, b; c, d".
+func validateFlagGroups(path string, expected, actual [][]string, message string, result *ValidationResult) {
+	if len(expected) == 0 {
+		return
+	}
+	expectedGroups := flagGroupNames(expected)
+	actualGroups := flagGroupNames(actual)
+	if !slicesEqual(expectedGroups, actualGroups) {
+		result.AddError(ErrorTypeMismatch, path,
+			strings.Join(expectedGroups, "; "),
+			strings.Join(actualGroups, "; "),
+			message)
+	}
+}
+
+// flagGroupNames returns each flag group as its sorted flag names joined by
+// ", ", in sorted order
+func flagGroupNames(groups [][]string) []string {
+	names := make([]string, len(groups))
+	for i, group := range groups {
+		sorted := append([]string(nil), group...)
+		sort.Strings(sorted)
+		names[i] = strings.Join(sorted, ", ")
+	}
+	sort.Strings(names)
+	return names
 }
 
 func validateFlags(parentPath string, expected []contract.Flag, actual []inspector.InspectedFlag, result *ValidationResult) {
@@ -182,25 +511,33 @@ func validateFlags(parentPath string, expected []contract.Flag, actual []inspect
 
 	// Check for missing flags
 	for _, exp := range expected {
-		flagPath := joinPath(parentPath, "--"+exp.Name)
+		flagPath := contract.JoinPath(parentPath, "--"+exp.Name)
 		if _, found := actualMap[exp.Name]; !found {
-			result.AddError(ErrorTypeMissing, flagPath, exp.Name, "", "flag")
+			result.AddErrorWithSuggestion(ErrorTypeMissing, flagPath, exp.Name, "", "flag", suggestions.MissingFlag(exp))
 		}
 	}
 
-	// Check for unexpected flags
+	// Check for unexpected flags. Like hidden commands, flags hidden from
+	// help (with MarkHidden or MarkDeprecated) are only validated when the
+	// contract lists them, unless the result is strict.
 	for _, act := range actual {
-		flagPath := joinPath(parentPath, "--"+act.Name)
+		flagPath := contract.JoinPath(parentPath, "--"+act.Name)
 		if _, found := expectedMap[act.Name]; !found {
+			if !result.Strict && (act.Hidden || act.Deprecated != "") {
+				continue
+			}
 			result.AddError(ErrorTypeUnexpected, flagPath, "", act.Name, "flag")
 		}
 	}
 
-	// Validate matching flags
-	for name, exp := range expectedMap {
+	// Validate matching flags, in sorted order
+	for _, name := range sortedKeys(expectedMap) {
+		if result.Stopped {
+			return
+		}
 		if act, found := actualMap[name]; found {
-			flagPath := joinPath(parentPath, "--"+name)
-			validateFlag(flagPath, exp, act, result)
+			flagPath := contract.JoinPath(parentPath, "--"+name)
+			validateFlag(flagPath, expectedMap[name], act, result)
 		}
 	}
 }
@@ -218,7 +555,8 @@ func validateFlag(path string, expected *contract.Flag, actual *inspector.Inspec
 
 	// Validate type
 	if expected.Type != actual.Type {
-		result.AddError(ErrorTypeInvalidType, path, expected.Type, actual.Type, "Flag type mismatch")
+		result.AddErrorWithSuggestion(ErrorTypeInvalidType, path, expected.Type, actual.Type, "Flag type mismatch",
+			suggestions.FlagTypeMismatch(expected.Type, actual.Type))
 	}
 
 	// Validate persistence
@@ -233,6 +571,202 @@ func validateFlag(path string, expected *contract.Flag, actual *inspector.Inspec
 		}
 		result.AddError(ErrorTypeMismatch, path, expectedPersistence, actualPersistence, "Flag persistence mismatch")
 	}
+
+	// Validate default value if specified
+	if expected.Default != "" && expected.Default != actual.Default {
+		result.AddError(ErrorTypeMismatch, path, expected.Default, actual.Default, "Flag default value mismatch")
+	}
+
+	// Required is always checked: a flag that stops being required and one
+	// that starts to be both change how the CLI must be called
+	if expected.Required != actual.Required {
+		result.AddError(ErrorTypeMismatch, path, requirement(expected.Required), requirement(actual.Required), "Flag required mismatch")
+	}
+
+	// Validate completion values if specified
+	if len(expected.Enum) > 0 && !slicesEqual(expected.Enum, actual.Enum) {
+		result.AddError(ErrorTypeMismatch, path,
+			strings.Join(expected.Enum, ", "),
+			strings.Join(actual.Enum, ", "),
+			"Flag enum mismatch")
+	}
+
+	// Deprecation is always checked: un-deprecating a flag and deprecating
+	// one without updating the contract are both mistakes
+	if expected.Deprecated != actual.Deprecated {
+		result.AddErrorWithSuggestion(ErrorTypeMismatch, path, deprecation(expected.Deprecated), deprecation(actual.Deprecated), "Flag deprecation mismatch",
+			suggestions.FlagDeprecation(expected.Name, expected.Deprecated))
+	}
+
+	// A listed flag should be hidden exactly when the contract says so.
+	// The flag still works either way, so a difference is only a warning.
+	if expected.Hidden != actual.Hidden {
+		result.AddWarning(ErrorTypeVisibility, path, visibility(expected.Hidden), visibility(actual.Hidden), "Flag visibility mismatch")
+	}
+
+	// Validate the environment variable fallback if specified. Bindings are
+	// found by reading the project source, which can miss some, so a
+	// difference is only a warning.
+	if expected.Env != "" && expected.Env != actual.Env {
+		result.AddWarning(ErrorTypeMismatch, path, expected.Env, actual.Env, "Environment variable binding not found")
+	}
+
+	validateFlagCompletion(path, expected, actual, result)
+}
+
+// deprecation describes a flag's deprecation message for error reports
+func deprecation(message string) string {
+	if message == "" {
+		return "not deprecated"
+	}
+	return "deprecated: " + message
+}
+
+// validateFlagCompletion checks the flag's shell completion if the contract specifies one
+func validateFlagCompletion(path string, expected *contract.Flag, actual *inspector.InspectedFlag, result *ValidationResult) {
+	if expected.Completion == "" {
+		return
+	}
+
+	actualCompletion := actual.Completion
+	if actualCompletion == "" {
+		actualCompletion = contract.CompletionNone
+	}
+	if expected.Completion != actualCompletion {
+		result.AddErrorWithSuggestion(ErrorTypeMismatch, path, expected.Completion, actualCompletion, "Flag completion mismatch",
+			suggestions.FlagCompletion(expected.Name, expected.Completion))
+	}
+}
+
+// ValidateCompletions checks only the shell completion of flags that exist in
+// both the contract and the CLI. Missing or unexpected commands and flags are
+// ignored; use Validate to report those.
+func ValidateCompletions(expected *contract.Contract, actual *inspector.InspectedCLI) *ValidationResult {
+	return ValidateCompletionsWithOptions(expected, actual, Options{})
+}
+
+// ValidateCompletionsWithOptions is like ValidateCompletions, matching
+// commands as set in opts. Only Options.UseNameOnly and Options.FailFast
+// apply.
+func ValidateCompletionsWithOptions(expected *contract.Contract, actual *inspector.InspectedCLI, opts Options) *ValidationResult {
+	result := &ValidationResult{Valid: true, failFast: opts.FailFast}
+	if len(opts.FocusPaths) > 0 {
+		expected = filterContract(expected, opts.FocusPaths)
+		actual = filterInspected(actual, opts.FocusPaths)
+	}
+
+	validateCompletionFlags("", expected.Flags, actual.Flags, result)
+	validateCompletionCommands("", expected.Commands, actual.Commands, opts, result)
+
+	return result
+}
+
+func validateCompletionCommands(parentPath string, expected []contract.Command, actual []inspector.InspectedCommand, opts Options, result *ValidationResult) {
+	actualMap := make(map[string]*inspector.InspectedCommand)
+	for i := range actual {
+		actualMap[opts.use(&actual[i])] = &actual[i]
+	}
+
+	for i := range expected {
+		exp := &expected[i]
+		if act, found := actualMap[exp.Use]; found {
+			cmdPath := contract.JoinPath(parentPath, exp.Use)
+			validateCompletionFlags(cmdPath, exp.Flags, act.Flags, result)
+			validateCompletionCommands(cmdPath, exp.Commands, act.Commands, opts, result)
+		}
+	}
+}
+
+func validateCompletionFlags(parentPath string, expected []contract.Flag, actual []inspector.InspectedFlag, result *ValidationResult) {
+	actualMap := make(map[string]*inspector.InspectedFlag)
+	for i := range actual {
+		actualMap[actual[i].Name] = &actual[i]
+	}
+
+	for i := range expected {
+		exp := &expected[i]
+		if act, found := actualMap[exp.Name]; found {
+			validateFlagCompletion(contract.JoinPath(parentPath, "--"+exp.Name), exp, act, result)
+		}
+	}
+}
+
+// validateAliases checks that the actual command has each alias the
+// contract lists. Aliases the contract doesn't list are only reported in
+// strict mode.
+func validateAliases(path string, expected, actual []string, result *ValidationResult) {
+	for _, alias := range expected {
+		if !containsAlias(actual, alias) {
+			result.AddError(ErrorTypeMissing, path, alias, "", fmt.Sprintf("alias '%s'", alias))
+		}
+	}
+	if !result.Strict {
+		return
+	}
+	for _, alias := range actual {
+		if !containsAlias(expected, alias) {
+			result.AddError(ErrorTypeUnexpected, path, "", alias, fmt.Sprintf("alias '%s'", alias))
+		}
+	}
+}
+
+// containsAlias reports whether aliases contains alias
+func containsAlias(aliases []string, alias string) bool {
+	for _, a := range aliases {
+		if a == alias {
+			return true
+		}
+	}
+	return false
+}
+
+// visibility returns a human-readable label for a command's or flag's hidden state
+func visibility(hidden bool) string {
+	if hidden {
+		return "hidden"
+	}
+	return "visible"
+}
+
+// requirement returns a human-readable label for a flag's required state
+func requirement(required bool) string {
+	if required {
+		return "required"
+	}
+	return "optional"
+}
+
+// runnability returns a human-readable label for a command's runnable state
+func runnability(runnable bool) string {
+	if runnable {
+		return "runnable"
+	}
+	return "not runnable"
+}
+
+// runsAnything reports whether cmd or any of its subcommands is runnable
+func runsAnything(cmd *inspector.InspectedCommand) bool {
+	if cmd.Runnable {
+		return true
+	}
+	for i := range cmd.Commands {
+		if runsAnything(&cmd.Commands[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// tracksRunnable reports whether any of the commands, or their subcommands,
+// sets contract.Command.Runnable, as contracts generated with
+// --runnable-only do
+func tracksRunnable(commands []contract.Command) bool {
+	for _, cmd := range commands {
+		if cmd.Runnable != nil || tracksRunnable(cmd.Commands) {
+			return true
+		}
+	}
+	return false
 }
 
 // slicesEqual compares two string slices for equality, ignoring order.
@@ -265,10 +799,3 @@ func slicesEqual(a, b []string) bool {
 	
 	return true
 }
-
-func joinPath(parent, child string) string {
-	if parent == "" {
-		return child
-	}
-	return fmt.Sprintf("%s %s", parent, strings.TrimSpace(child))
-}
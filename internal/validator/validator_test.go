@@ -1,6 +1,10 @@
 package validator
 
 import (
+	"bytes"
+	"reflect"
+	"sort"
+	"strings"
 	"testing"
 
 	"github.com/hiAndrewQuinn/cliguard/internal/contract"
@@ -288,6 +292,228 @@ func TestValidate(t *testing.T) {
 				{Type: ErrorTypeUnexpected, Path: "serve --host", Actual: "host"},
 			},
 		},
+		{
+			name: "hidden_command_match",
+			expected: &contract.Contract{
+				Use:   "testcli",
+				Short: "Test CLI",
+				Commands: []contract.Command{
+					{Use: "debug", Short: "Debug", Hidden: true},
+				},
+			},
+			actual: &inspector.InspectedCLI{
+				Use:   "testcli",
+				Short: "Test CLI",
+				Commands: []inspector.InspectedCommand{
+					{Use: "debug", Short: "Debug", Hidden: true},
+				},
+			},
+			wantErrs: nil,
+		},
+		{
+			name: "hidden_command_made_visible",
+			expected: &contract.Contract{
+				Use:   "testcli",
+				Short: "Test CLI",
+				Commands: []contract.Command{
+					{Use: "debug", Short: "Debug", Hidden: true},
+				},
+			},
+			actual: &inspector.InspectedCLI{
+				Use:   "testcli",
+				Short: "Test CLI",
+				Commands: []inspector.InspectedCommand{
+					{Use: "debug", Short: "Debug"},
+				},
+			},
+			wantErrs: []ValidationError{
+				{Type: ErrorTypeMismatch, Path: "debug", Expected: "hidden", Actual: "visible"},
+			},
+		},
+		{
+			name: "flag_completion_match",
+			expected: &contract.Contract{
+				Use:   "testcli",
+				Short: "Test CLI",
+				Flags: []contract.Flag{
+					{Name: "config", Type: "string", Completion: "file"},
+					{Name: "name", Type: "string", Completion: "none"},
+					{Name: "format", Type: "string"},
+				},
+			},
+			actual: &inspector.InspectedCLI{
+				Use:   "testcli",
+				Short: "Test CLI",
+				Flags: []inspector.InspectedFlag{
+					{Name: "config", Type: "string", Completion: "file"},
+					{Name: "name", Type: "string"},
+					{Name: "format", Type: "string", Completion: "custom"},
+				},
+			},
+			wantErrs: nil,
+		},
+		{
+			name: "flag_completion_mismatch",
+			expected: &contract.Contract{
+				Use:   "testcli",
+				Short: "Test CLI",
+				Flags: []contract.Flag{
+					{Name: "config", Type: "string", Completion: "file"},
+					{Name: "output", Type: "string", Completion: "none"},
+				},
+			},
+			actual: &inspector.InspectedCLI{
+				Use:   "testcli",
+				Short: "Test CLI",
+				Flags: []inspector.InspectedFlag{
+					{Name: "config", Type: "string"},
+					{Name: "output", Type: "string", Completion: "dir"},
+				},
+			},
+			wantErrs: []ValidationError{
+				{Type: ErrorTypeMismatch, Path: "--config", Expected: "file", Actual: "none"},
+				{Type: ErrorTypeMismatch, Path: "--output", Expected: "none", Actual: "dir"},
+			},
+		},
+		{
+			name: "flag_default_mismatch",
+			expected: &contract.Contract{
+				Use:   "testcli",
+				Short: "Test CLI",
+				Flags: []contract.Flag{
+					{Name: "port", Type: "int", Default: "8080"},
+					{Name: "host", Type: "string"},
+				},
+			},
+			actual: &inspector.InspectedCLI{
+				Use:   "testcli",
+				Short: "Test CLI",
+				Flags: []inspector.InspectedFlag{
+					{Name: "port", Type: "int", Default: "9090"},
+					{Name: "host", Type: "string", Default: "localhost"},
+				},
+			},
+			wantErrs: []ValidationError{
+				{Type: ErrorTypeMismatch, Path: "--port", Expected: "8080", Actual: "9090"},
+			},
+		},
+		{
+			name: "flag_required_mismatch",
+			expected: &contract.Contract{
+				Use:   "testcli",
+				Short: "Test CLI",
+				Flags: []contract.Flag{
+					{Name: "token", Type: "string", Required: true},
+					{Name: "region", Type: "string"},
+				},
+			},
+			actual: &inspector.InspectedCLI{
+				Use:   "testcli",
+				Short: "Test CLI",
+				Flags: []inspector.InspectedFlag{
+					{Name: "token", Type: "string"},
+					{Name: "region", Type: "string", Required: true},
+				},
+			},
+			wantErrs: []ValidationError{
+				{Type: ErrorTypeMismatch, Path: "--token", Expected: "required", Actual: "optional"},
+				{Type: ErrorTypeMismatch, Path: "--region", Expected: "optional", Actual: "required"},
+			},
+		},
+		{
+			name: "flag_deprecation_mismatch",
+			expected: &contract.Contract{
+				Use:   "testcli",
+				Short: "Test CLI",
+				Flags: []contract.Flag{
+					{Name: "old-output", Type: "string", Deprecated: "use --output instead"},
+					{Name: "format", Type: "string", Deprecated: "use --output instead"},
+					{Name: "output", Type: "string"},
+					{Name: "legacy", Type: "bool", Deprecated: "it has no effect"},
+				},
+			},
+			actual: &inspector.InspectedCLI{
+				Use:   "testcli",
+				Short: "Test CLI",
+				Flags: []inspector.InspectedFlag{
+					{Name: "old-output", Type: "string"},
+					{Name: "format", Type: "string", Deprecated: "use --output"},
+					{Name: "output", Type: "string", Deprecated: "use --out instead"},
+					{Name: "legacy", Type: "bool", Deprecated: "it has no effect"},
+				},
+			},
+			wantErrs: []ValidationError{
+				{Type: ErrorTypeMismatch, Path: "--old-output", Expected: "deprecated: use --output instead", Actual: "not deprecated"},
+				{Type: ErrorTypeMismatch, Path: "--format", Expected: "deprecated: use --output instead", Actual: "deprecated: use --output"},
+				{Type: ErrorTypeMismatch, Path: "--output", Expected: "not deprecated", Actual: "deprecated: use --out instead"},
+			},
+		},
+		{
+			name: "flag_hidden",
+			expected: &contract.Contract{
+				Use:   "testcli",
+				Short: "Test CLI",
+				Flags: []contract.Flag{
+					{Name: "debug", Type: "bool", Hidden: true},
+					{Name: "trace", Type: "bool", Hidden: true},
+					{Name: "output", Type: "string"},
+				},
+			},
+			actual: &inspector.InspectedCLI{
+				Use:   "testcli",
+				Short: "Test CLI",
+				Flags: []inspector.InspectedFlag{
+					{Name: "debug", Type: "bool", Hidden: true},
+					{Name: "trace", Type: "bool"},
+					{Name: "output", Type: "string", Hidden: true},
+					{Name: "internal", Type: "bool", Hidden: true},
+					{Name: "legacy", Type: "bool", Deprecated: "it has no effect"},
+				},
+			},
+			// Visibility differences are warnings, see TestValidate_FlagVisibility
+			wantErrs: nil,
+		},
+		{
+			name: "flag_enum_mismatch",
+			expected: &contract.Contract{
+				Use:   "testcli",
+				Short: "Test CLI",
+				Flags: []contract.Flag{
+					{Name: "region", Type: "string", Enum: []string{"us-east-1", "eu-west-1"}},
+					{Name: "zone", Type: "string", Enum: []string{"a", "b"}},
+				},
+			},
+			actual: &inspector.InspectedCLI{
+				Use:   "testcli",
+				Short: "Test CLI",
+				Flags: []inspector.InspectedFlag{
+					{Name: "region", Type: "string", Enum: []string{"us-east-1"}},
+					{Name: "zone", Type: "string", Enum: []string{"b", "a"}},
+				},
+			},
+			wantErrs: []ValidationError{
+				{Type: ErrorTypeMismatch, Path: "--region", Expected: "us-east-1, eu-west-1", Actual: "us-east-1"},
+			},
+		},
+		{
+			name: "untracked_hidden_command_ignored",
+			expected: &contract.Contract{
+				Use:   "testcli",
+				Short: "Test CLI",
+				Commands: []contract.Command{
+					{Use: "serve", Short: "Serve"},
+				},
+			},
+			actual: &inspector.InspectedCLI{
+				Use:   "testcli",
+				Short: "Test CLI",
+				Commands: []inspector.InspectedCommand{
+					{Use: "serve", Short: "Serve"},
+					{Use: "debug", Short: "Debug", Hidden: true},
+				},
+			},
+			wantErrs: nil,
+		},
 	}
 
 	for _, tt := range tests {
@@ -331,6 +557,637 @@ func TestValidate(t *testing.T) {
 	}
 }
 
+// unorderedContract and unorderedCLI differ in many commands and flags, so
+// that map iteration order would show in the order of the errors
+func unorderedContract() (*contract.Contract, *inspector.InspectedCLI) {
+	expected := &contract.Contract{Use: "app", Short: "App"}
+	actual := &inspector.InspectedCLI{Use: "app", Short: "App"}
+	for _, name := range []string{"alpha", "bravo", "charlie", "delta", "echo", "foxtrot", "golf", "hotel"} {
+		expected.Flags = append(expected.Flags, contract.Flag{Name: name, Type: "string", Usage: name})
+		actual.Flags = append(actual.Flags, inspector.InspectedFlag{Name: name, Type: "int", Usage: name})
+
+		cmd := contract.Command{Use: name, Short: name, Flags: []contract.Flag{{Name: "out", Type: "string", Usage: "Output"}}}
+		act := inspector.InspectedCommand{Use: name, Short: "changed", Flags: []inspector.InspectedFlag{{Name: "out", Type: "bool", Usage: "Output"}}}
+		expected.Commands = append(expected.Commands, cmd)
+		actual.Commands = append(actual.Commands, act)
+	}
+	return expected, actual
+}
+
+func TestValidateResultsAreDeterministic(t *testing.T) {
+	expected, actual := unorderedContract()
+
+	first := Validate(expected, actual)
+	if len(first.Errors) != 24 {
+		t.Fatalf("Validate() returned %d errors, want 24: %+v", len(first.Errors), first.Errors)
+	}
+	for i := 0; i < 100; i++ {
+		if result := Validate(expected, actual); !reflect.DeepEqual(result, first) {
+			t.Fatalf("Validate() run %d errors = %+v, want the same as the first run: %+v", i, result.Errors, first.Errors)
+		}
+	}
+}
+
+func TestValidateInOrder(t *testing.T) {
+	expected, actual := unorderedContract()
+	expected.Commands = expected.Commands[:2]
+	expected.Flags = nil
+	actual.Flags = nil
+	actual.Commands = append(actual.Commands[:1], inspector.InspectedCommand{Use: "zulu", Short: "zulu"})
+
+	result := ValidateInOrder(expected, actual)
+	var got []ValidationError
+	for _, err := range result.Errors {
+		got = append(got, ValidationError{Type: err.Type, Path: err.Path, Expected: err.Expected, Actual: err.Actual})
+	}
+	want := []ValidationError{
+		{Type: ErrorTypeMismatch, Path: "alpha", Expected: "alpha", Actual: "changed"},
+		{Type: ErrorTypeInvalidType, Path: "alpha --out", Expected: "string", Actual: "bool"},
+		{Type: ErrorTypeMissing, Path: "bravo", Expected: "bravo"},
+		{Type: ErrorTypeUnexpected, Path: "zulu", Actual: "zulu"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ValidateInOrder() errors = %+v, want %+v", got, want)
+	}
+}
+
+func TestValidateWithOptions_StrictSortOrder(t *testing.T) {
+	expected := &contract.Contract{
+		Use:   "kubectl",
+		Short: "Kubernetes CLI",
+		Commands: []contract.Command{
+			{Use: "get", Short: "get", SortOrder: 1},
+			{Use: "describe", Short: "describe", SortOrder: 2},
+			{Use: "config", Short: "config", Commands: []contract.Command{
+				{Use: "view", Short: "view", SortOrder: 1},
+				{Use: "set", Short: "set", SortOrder: 2},
+			}},
+			{Use: "delete", Short: "delete", SortOrder: 3},
+		},
+	}
+	inspected := func(top []string, config []string) *inspector.InspectedCLI {
+		cli := &inspector.InspectedCLI{Use: "kubectl", Short: "Kubernetes CLI"}
+		for _, use := range top {
+			cmd := inspector.InspectedCommand{Use: use, Short: use}
+			if use == "config" {
+				for _, sub := range config {
+					cmd.Commands = append(cmd.Commands, inspector.InspectedCommand{Use: sub, Short: sub})
+				}
+			}
+			cli.Commands = append(cli.Commands, cmd)
+		}
+		return cli
+	}
+
+	// Cobra's default alphabetical listing
+	sorted := inspected([]string{"config", "delete", "describe", "get"}, []string{"set", "view"})
+	if result := Validate(expected, sorted); !result.IsValid() {
+		t.Errorf("Validate() should not check sort order, got %+v", result.Errors)
+	}
+
+	result := ValidateWithOptions(expected, sorted, Options{StrictSortOrder: true})
+	want := []ValidationError{
+		{Type: ErrorTypeMismatch, Path: "root", Expected: "get, describe, delete", Actual: "delete, describe, get"},
+		{Type: ErrorTypeMismatch, Path: "config", Expected: "view, set", Actual: "set, view"},
+	}
+	if len(result.Errors) != len(want) {
+		t.Fatalf("ValidateWithOptions() errors = %+v, want %+v", result.Errors, want)
+	}
+	for _, wantErr := range want {
+		found := false
+		for _, gotErr := range result.Errors {
+			found = found || errorsMatch(wantErr, gotErr)
+		}
+		if !found {
+			t.Errorf("Expected error not found: %+v", wantErr)
+		}
+	}
+
+	// Commands without a sort order may appear anywhere
+	ordered := inspected([]string{"get", "config", "describe", "delete"}, []string{"view", "set"})
+	if result := ValidateWithOptions(expected, ordered, Options{StrictSortOrder: true}); !result.IsValid() {
+		t.Errorf("ValidateWithOptions() errors = %+v, want none", result.Errors)
+	}
+}
+
+func TestValidateWithOptions_ExpandedContract(t *testing.T) {
+	actual := &inspector.InspectedCLI{
+		Use:   "app",
+		Short: "App",
+		Flags: []inspector.InspectedFlag{
+			{Name: "verbose", Shorthand: "v", Type: "bool", Persistent: true},
+		},
+		Commands: []inspector.InspectedCommand{
+			{
+				Use:   "run",
+				Short: "Run",
+				Flags: []inspector.InspectedFlag{
+					{Name: "dry-run", Type: "bool"},
+				},
+			},
+		},
+	}
+	expanded := &contract.Contract{
+		Use:   "app",
+		Short: "App",
+		Flags: []contract.Flag{
+			{Name: "verbose", Shorthand: "v", Type: "bool", Persistent: true},
+		},
+		Commands: []contract.Command{
+			{
+				Use:   "run",
+				Short: "Run",
+				Flags: []contract.Flag{
+					{Name: "dry-run", Type: "bool"},
+					// Listed again for the subcommand, which Cobra would
+					// reject as a shorthand conflict in a regular contract
+					{Name: "verbose", Shorthand: "v", Type: "bool", Persistent: true},
+				},
+			},
+		},
+	}
+
+	if result := ValidateWithOptions(expanded, actual, Options{ExpandedContract: true}); !result.IsValid() {
+		t.Errorf("ValidateWithOptions() errors = %+v, want none", result.Errors)
+	}
+
+	result := Validate(expanded, actual)
+	if result.IsValid() {
+		t.Fatal("Validate() of an expanded contract should fail")
+	}
+	found := false
+	for _, err := range result.Errors {
+		found = found || (err.Type == ErrorTypeMissing && err.Path == "run --verbose")
+	}
+	if !found {
+		t.Errorf("Validate() errors = %+v, want missing run --verbose", result.Errors)
+	}
+
+	// An unexpanded contract is missing the inherited flags
+	result = ValidateWithOptions(&contract.Contract{
+		Use:   "app",
+		Short: "App",
+		Flags: expanded.Flags,
+		Commands: []contract.Command{
+			{Use: "run", Short: "Run", Flags: []contract.Flag{{Name: "dry-run", Type: "bool"}}},
+		},
+	}, actual, Options{ExpandedContract: true})
+	found = false
+	for _, err := range result.Errors {
+		found = found || (err.Type == ErrorTypeUnexpected && err.Path == "run --verbose")
+	}
+	if !found {
+		t.Errorf("ValidateWithOptions() errors = %+v, want unexpected run --verbose", result.Errors)
+	}
+}
+
+func TestValidateWithOptions_UseNameOnly(t *testing.T) {
+	actual := &inspector.InspectedCLI{
+		Use:   "git [flags]",
+		Short: "Git",
+		Commands: []inspector.InspectedCommand{
+			{
+				Use:   "clone [flags] <repo>",
+				Short: "Clone a repository",
+				Commands: []inspector.InspectedCommand{
+					{Use: "shallow <depth>", Short: "Shallow clone"},
+				},
+			},
+			{Use: "status", Short: "Show status"},
+		},
+	}
+	named := &contract.Contract{
+		Use:   "git",
+		Short: "Git",
+		Commands: []contract.Command{
+			{
+				Use:   "clone",
+				Short: "Clone a repository",
+				Commands: []contract.Command{
+					{Use: "shallow", Short: "Shallow clone"},
+				},
+			},
+			{Use: "status", Short: "Show status"},
+		},
+	}
+
+	if result := ValidateWithOptions(named, actual, Options{UseNameOnly: true}); !result.IsValid() {
+		t.Errorf("ValidateWithOptions() errors = %+v, want none", result.Errors)
+	}
+
+	// Without the option the full Use is compared
+	result := Validate(named, actual)
+	if result.IsValid() {
+		t.Fatal("Validate() of a name-only contract should fail")
+	}
+	found := false
+	for _, err := range result.Errors {
+		found = found || (err.Type == ErrorTypeMissing && err.Path == "clone")
+	}
+	if !found {
+		t.Errorf("Validate() errors = %+v, want missing clone", result.Errors)
+	}
+
+	// A renamed command is still reported
+	actual.Commands[1].Use = "state"
+	result = ValidateWithOptions(named, actual, Options{UseNameOnly: true})
+	found = false
+	for _, err := range result.Errors {
+		found = found || (err.Type == ErrorTypeUnexpected && err.Path == "state")
+	}
+	if !found {
+		t.Errorf("ValidateWithOptions() errors = %+v, want unexpected state", result.Errors)
+	}
+}
+
+func TestValidateWithOptions_IgnoreDescriptions(t *testing.T) {
+	expected := &contract.Contract{
+		Use:   "app",
+		Short: "An app",
+		Long:  "App does things.\n\nIt does them well.",
+		Commands: []contract.Command{
+			{Use: "serve", Short: "Serve the app", Long: "Serve the app over HTTP."},
+		},
+	}
+	actual := &inspector.InspectedCLI{
+		Use:   "app",
+		Short: "The app",
+		Long:  "App does things. It does them well.",
+		Commands: []inspector.InspectedCommand{
+			{Use: "serve", Short: "Serve it", Long: "Serve the app\nover HTTP."},
+		},
+	}
+
+	tests := []struct {
+		name string
+		opts Options
+		want []string // messages of the errors, by path
+	}{
+		{
+			name: "compare both",
+			want: []string{
+				"root: Mismatch in short description",
+				"root: Mismatch in long description",
+				"serve: Mismatch in short description",
+				"serve: Mismatch in long description",
+			},
+		},
+		{
+			name: "ignore long",
+			opts: Options{IgnoreLong: true},
+			want: []string{"root: Mismatch in short description", "serve: Mismatch in short description"},
+		},
+		{
+			name: "ignore short",
+			opts: Options{IgnoreShort: true},
+			want: []string{"root: Mismatch in long description", "serve: Mismatch in long description"},
+		},
+		{
+			name: "ignore both",
+			opts: Options{IgnoreShort: true, IgnoreLong: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ValidateWithOptions(expected, actual, tt.opts)
+			var got []string
+			for _, err := range result.Errors {
+				got = append(got, err.Path+": "+err.Message)
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("errors = %q, want %q", got, tt.want)
+			}
+			if result.IsValid() != (len(tt.want) == 0) {
+				t.Errorf("IsValid() = %v with errors %q", result.IsValid(), got)
+			}
+		})
+	}
+
+	// Structural differences are still reported
+	actual.Commands[0].Use = "server"
+	if result := ValidateWithOptions(expected, actual, Options{IgnoreShort: true, IgnoreLong: true}); result.IsValid() {
+		t.Error("ValidateWithOptions() ignoring descriptions should still report a renamed command")
+	}
+}
+
+func TestValidate_Runnable(t *testing.T) {
+	runnable, notRunnable := true, false
+	expected := &contract.Contract{
+		Use:   "app",
+		Short: "App",
+		Commands: []contract.Command{
+			{Use: "db", Short: "Database", Runnable: &notRunnable, Commands: []contract.Command{
+				{Use: "migrate", Short: "Migrate", Runnable: &runnable},
+			}},
+		},
+	}
+	actual := &inspector.InspectedCLI{
+		Use:   "app",
+		Short: "App",
+		Commands: []inspector.InspectedCommand{
+			{Use: "db", Short: "Database", Commands: []inspector.InspectedCommand{
+				{Use: "migrate", Short: "Migrate", Runnable: true},
+				{Use: "topics", Short: "Help topics"},
+			}},
+			{Use: "environment", Short: "Help about environment variables"},
+		},
+	}
+
+	// Commands that run nothing are left out of the contract
+	if result := Validate(expected, actual); !result.IsValid() {
+		t.Errorf("Validate() errors = %+v, want none", result.Errors)
+	}
+
+	actual.Commands[0].Runnable = true
+	actual.Commands[0].Commands[0].Runnable = false
+	actual.Commands = append(actual.Commands, inspector.InspectedCommand{Use: "serve", Short: "Serve", Runnable: true})
+	result := Validate(expected, actual)
+	want := []ValidationError{
+		{Type: ErrorTypeUnexpected, Path: "serve", Actual: "serve"},
+		{Type: ErrorTypeMismatch, Path: "db", Expected: "not runnable", Actual: "runnable"},
+		{Type: ErrorTypeMismatch, Path: "db migrate", Expected: "runnable", Actual: "not runnable"},
+	}
+	if len(result.Errors) != len(want) {
+		t.Fatalf("Validate() errors = %+v, want %+v", result.Errors, want)
+	}
+	for i, wantErr := range want {
+		if !errorsMatch(wantErr, result.Errors[i]) {
+			t.Errorf("error %d = %+v, want %+v", i, result.Errors[i], wantErr)
+		}
+	}
+
+	// Without runnable fields, non-runnable commands are validated as usual
+	expected.Commands[0].Runnable = nil
+	expected.Commands[0].Commands[0].Runnable = nil
+	actual.Commands = actual.Commands[:2]
+	result = Validate(expected, actual)
+	if len(result.Errors) != 2 {
+		t.Errorf("Validate() errors = %+v, want topics and environment unexpected", result.Errors)
+	}
+}
+
+func TestValidate_FlagVisibility(t *testing.T) {
+	expected := &contract.Contract{
+		Use:   "app",
+		Short: "App",
+		Flags: []contract.Flag{
+			{Name: "debug", Type: "bool", Hidden: true},
+			{Name: "trace", Type: "bool", Hidden: true},
+			{Name: "output", Type: "string"},
+		},
+	}
+	actual := &inspector.InspectedCLI{
+		Use:   "app",
+		Short: "App",
+		Flags: []inspector.InspectedFlag{
+			{Name: "debug", Type: "bool", Hidden: true},
+			{Name: "trace", Type: "bool"},
+			{Name: "output", Type: "string", Hidden: true},
+		},
+	}
+
+	result := Validate(expected, actual)
+	if !result.IsValid() {
+		t.Fatalf("Validate() errors = %+v, want none: visibility differences are warnings", result.Errors)
+	}
+	want := []ValidationError{
+		{Type: ErrorTypeVisibility, Path: "--output", Expected: "visible", Actual: "hidden"},
+		{Type: ErrorTypeVisibility, Path: "--trace", Expected: "hidden", Actual: "visible"},
+	}
+	if len(result.Warnings) != len(want) {
+		t.Fatalf("Validate() warnings = %+v, want %+v", result.Warnings, want)
+	}
+	for _, w := range want {
+		found := false
+		for _, got := range result.Warnings {
+			found = found || errorsMatch(w, got)
+		}
+		if !found {
+			t.Errorf("Validate() warnings = %+v, want %+v", result.Warnings, w)
+		}
+	}
+}
+
+func TestValidate_Env(t *testing.T) {
+	expected := &contract.Contract{
+		Use:   "app",
+		Short: "App",
+		Flags: []contract.Flag{
+			{Name: "token", Type: "string", Usage: "API token", Env: "API_TOKEN"},
+			{Name: "region", Type: "string", Usage: "Region", Env: "APP_REGION"},
+		},
+	}
+	actual := &inspector.InspectedCLI{
+		Use:   "app",
+		Short: "App",
+		Flags: []inspector.InspectedFlag{
+			{Name: "token", Type: "string", Usage: "API token", Env: "API_TOKEN"},
+			{Name: "region", Type: "string", Usage: "Region"},
+		},
+	}
+
+	result := Validate(expected, actual)
+	if !result.IsValid() {
+		t.Fatalf("Validate() errors = %+v, want none: a missing binding is only a warning", result.Errors)
+	}
+	want := ValidationError{Type: ErrorTypeMismatch, Path: "--region", Expected: "APP_REGION", Actual: ""}
+	if len(result.Warnings) != 1 || !errorsMatch(want, result.Warnings[0]) {
+		t.Fatalf("Validate() warnings = %+v, want %+v", result.Warnings, want)
+	}
+
+	report, err := result.FormatReport(ReportFormatJSON)
+	if err != nil {
+		t.Fatalf("FormatReport() error = %v", err)
+	}
+	if !strings.Contains(report, `"warnings"`) || !strings.Contains(report, "APP_REGION") {
+		t.Errorf("FormatReport() = %s, want the warning included", report)
+	}
+}
+
+func TestValidate_FlagGroups(t *testing.T) {
+	expected := &contract.Contract{
+		Use:               "app",
+		Short:             "App",
+		MutuallyExclusive: [][]string{{"yaml", "json"}},
+		Commands: []contract.Command{
+			{
+				Use:               "login",
+				Short:             "Log in",
+				MutuallyExclusive: [][]string{{"password", "password-stdin"}, {"token", "username"}},
+				RequiredTogether:  [][]string{{"username", "password"}},
+			},
+			{Use: "logout", Short: "Log out"},
+		},
+	}
+	actual := &inspector.InspectedCLI{
+		Use:               "app",
+		Short:             "App",
+		MutuallyExclusive: [][]string{{"json", "yaml"}},
+		Commands: []inspector.InspectedCommand{
+			{
+				Use:               "login",
+				Short:             "Log in",
+				MutuallyExclusive: [][]string{{"token", "username"}, {"password", "password-stdin"}},
+				RequiredTogether:  [][]string{{"password", "username"}},
+			},
+			// Groups are only checked when the contract lists some
+			{Use: "logout", Short: "Log out", MutuallyExclusive: [][]string{{"all", "session"}}},
+		},
+	}
+
+	// The order of the groups and of their flags doesn't matter
+	if result := Validate(expected, actual); !result.IsValid() {
+		t.Errorf("Validate() errors = %+v, want none", result.Errors)
+	}
+
+	actual.MutuallyExclusive = nil
+	actual.Commands[0].MutuallyExclusive = [][]string{{"password", "password-stdin"}}
+	actual.Commands[0].RequiredTogether = [][]string{{"password", "token", "username"}}
+	result := Validate(expected, actual)
+	want := []ValidationError{
+		{Type: ErrorTypeMismatch, Path: "root", Expected: "json, yaml", Actual: "", Message: "Mismatch in mutually exclusive flags"},
+		{Type: ErrorTypeMismatch, Path: "login", Expected: "password, password-stdin; token, username", Actual: "password, password-stdin", Message: "Mismatch in mutually exclusive flags"},
+		{Type: ErrorTypeMismatch, Path: "login", Expected: "password, username", Actual: "password, token, username", Message: "Mismatch in required together flags"},
+	}
+	if len(result.Errors) != len(want) {
+		t.Fatalf("Validate() errors = %+v, want %+v", result.Errors, want)
+	}
+	for i, wantErr := range want {
+		if !errorsMatch(wantErr, result.Errors[i]) || result.Errors[i].Message != wantErr.Message {
+			t.Errorf("error %d = %+v, want %+v", i, result.Errors[i], wantErr)
+		}
+	}
+}
+
+func TestValidate_FailFast(t *testing.T) {
+	expected := &contract.Contract{
+		Use:   "app",
+		Short: "App",
+		Flags: []contract.Flag{{Name: "config", Type: "string"}},
+		Commands: []contract.Command{
+			{Use: "serve", Short: "Serve", Flags: []contract.Flag{{Name: "port", Type: "int"}}},
+			{Use: "db", Short: "Database"},
+		},
+	}
+	actual := &inspector.InspectedCLI{
+		Use:   "myapp",
+		Short: "My app",
+		Flags: []inspector.InspectedFlag{{Name: "config", Type: "bool"}},
+		Commands: []inspector.InspectedCommand{
+			{Use: "serve", Short: "Start serving", Flags: []inspector.InspectedFlag{{Name: "port", Type: "string"}}},
+		},
+	}
+
+	full := Validate(expected, actual)
+	if len(full.Errors) < 5 || full.Stopped {
+		t.Fatalf("Validate() errors = %+v, stopped = %v; want them all", full.Errors, full.Stopped)
+	}
+
+	result := ValidateWithOptions(expected, actual, Options{FailFast: true})
+	if result.IsValid() || !result.Stopped {
+		t.Fatalf("ValidateWithOptions() valid = %v, stopped = %v; want a stopped failure", result.IsValid(), result.Stopped)
+	}
+	if len(result.Errors) != 1 || !errorsMatch(full.Errors[0], result.Errors[0]) {
+		t.Errorf("ValidateWithOptions() errors = %+v, want only %+v", result.Errors, full.Errors[0])
+	}
+
+	// A CLI matching the contract is validated in full
+	result = ValidateWithOptions(expected, &inspector.InspectedCLI{
+		Use:   "app",
+		Short: "App",
+		Flags: []inspector.InspectedFlag{{Name: "config", Type: "string"}},
+		Commands: []inspector.InspectedCommand{
+			{Use: "serve", Short: "Serve", Flags: []inspector.InspectedFlag{{Name: "port", Type: "int"}}},
+			{Use: "db", Short: "Database"},
+		},
+	}, Options{FailFast: true})
+	if !result.IsValid() || result.Stopped {
+		t.Errorf("ValidateWithOptions() errors = %+v, stopped = %v; want none", result.Errors, result.Stopped)
+	}
+}
+
+func TestValidate_ShorthandConflicts(t *testing.T) {
+	expected := &contract.Contract{
+		Use:   "testcli",
+		Short: "Test CLI",
+		Flags: []contract.Flag{
+			{Name: "verbose", Shorthand: "v", Usage: "Verbose output", Type: "bool", Persistent: true},
+		},
+		Commands: []contract.Command{
+			{
+				Use:   "serve",
+				Short: "Start server",
+				Flags: []contract.Flag{
+					{Name: "output", Shorthand: "o", Usage: "Output file", Type: "string"},
+				},
+				Commands: []contract.Command{
+					{
+						Use:   "status",
+						Short: "Show server status",
+						Flags: []contract.Flag{
+							{Name: "validate", Shorthand: "v", Usage: "Validate config", Type: "bool"},
+						},
+					},
+				},
+			},
+			{
+				// Siblings may reuse shorthands
+				Use:   "build",
+				Short: "Build project",
+				Flags: []contract.Flag{
+					{Name: "outdir", Shorthand: "o", Usage: "Output directory", Type: "string"},
+				},
+			},
+		},
+	}
+
+	actual := &inspector.InspectedCLI{
+		Use:   "testcli",
+		Short: "Test CLI",
+		Flags: []inspector.InspectedFlag{
+			{Name: "verbose", Shorthand: "v", Usage: "Verbose output", Type: "bool", Persistent: true},
+		},
+		Commands: []inspector.InspectedCommand{
+			{
+				Use:   "serve",
+				Short: "Start server",
+				Flags: []inspector.InspectedFlag{
+					{Name: "output", Shorthand: "o", Usage: "Output file", Type: "string"},
+				},
+				Commands: []inspector.InspectedCommand{
+					{
+						Use:   "status",
+						Short: "Show server status",
+						Flags: []inspector.InspectedFlag{
+							{Name: "validate", Shorthand: "v", Usage: "Validate config", Type: "bool"},
+						},
+					},
+				},
+			},
+			{
+				Use:   "build",
+				Short: "Build project",
+				Flags: []inspector.InspectedFlag{
+					{Name: "outdir", Shorthand: "o", Usage: "Output directory", Type: "string"},
+				},
+			},
+		},
+	}
+
+	result := Validate(expected, actual)
+	if len(result.Errors) != 1 {
+		t.Fatalf("expected 1 error, got %d: %+v", len(result.Errors), result.Errors)
+	}
+
+	got := result.Errors[0]
+	if got.Type != ErrorTypeMismatch || got.Path != "serve status --validate" {
+		t.Errorf("unexpected error: %+v", got)
+	}
+	if got.Expected != "--verbose" || got.Actual != "--validate" {
+		t.Errorf("Expected/Actual = %q/%q, want --verbose/--validate", got.Expected, got.Actual)
+	}
+}
+
 func TestValidationResult_PrintReport(t *testing.T) {
 	// This is mainly for coverage - actual output is tested via integration tests
 	result := &ValidationResult{
@@ -346,6 +1203,69 @@ func TestValidationResult_PrintReport(t *testing.T) {
 	result.PrintReport()
 }
 
+func TestValidationResult_WriteReport_WarnOnly(t *testing.T) {
+	result := &ValidationResult{
+		WarnOnly: true,
+		Errors: []ValidationError{
+			{Type: ErrorTypeMissing, Path: "test --flag", Expected: "flag", Message: "flag", Description: "Missing flag"},
+		},
+	}
+
+	var buf bytes.Buffer
+	result.WriteReport(&buf)
+	report := buf.String()
+	if !strings.Contains(report, "⚠️  Missing items:") || !strings.Contains(report, "Total warnings: 1") {
+		t.Errorf("WriteReport() = %q, want the errors reported as warnings", report)
+	}
+	if strings.Contains(report, "❌") {
+		t.Errorf("WriteReport() = %q, want no ❌ markers", report)
+	}
+}
+
+func TestValidateCompletions(t *testing.T) {
+	expected := &contract.Contract{
+		Use:   "testcli",
+		Short: "Expected description",
+		Commands: []contract.Command{
+			{
+				Use: "export",
+				Flags: []contract.Flag{
+					{Name: "output-dir", Type: "string", Completion: "dir"},
+					{Name: "missing", Type: "string", Completion: "file"},
+				},
+			},
+			{Use: "missing-command"},
+		},
+	}
+	actual := &inspector.InspectedCLI{
+		Use:   "testcli",
+		Short: "Different description",
+		Commands: []inspector.InspectedCommand{
+			{
+				Use: "export",
+				Flags: []inspector.InspectedFlag{
+					{Name: "output-dir", Type: "int", Completion: "file"},
+					{Name: "extra", Type: "bool"},
+				},
+			},
+		},
+	}
+
+	result := ValidateCompletions(expected, actual)
+
+	// Only the completion mismatch is reported; structural differences are ignored
+	if len(result.Errors) != 1 {
+		t.Fatalf("ValidateCompletions() errors = %+v, want 1 error", result.Errors)
+	}
+	want := ValidationError{Type: ErrorTypeMismatch, Path: "export --output-dir", Expected: "dir", Actual: "file"}
+	if !errorsMatch(want, result.Errors[0]) {
+		t.Errorf("ValidateCompletions() error = %+v, want %+v", result.Errors[0], want)
+	}
+	if result.Errors[0].Suggestion != `cmd.MarkFlagDirname("output-dir")` {
+		t.Errorf("Suggestion = %q", result.Errors[0].Suggestion)
+	}
+}
+
 func errorsMatch(want, got ValidationError) bool {
 	// For matching, we only care about Type, Path, and Expected/Actual values
 	return want.Type == got.Type &&
@@ -353,3 +1273,90 @@ func errorsMatch(want, got ValidationError) bool {
 		want.Expected == got.Expected &&
 		want.Actual == got.Actual
 }
+
+func TestValidate_Aliases(t *testing.T) {
+	expected := &contract.Contract{
+		Use:     "testcli",
+		Aliases: []string{"tc"},
+		Commands: []contract.Command{
+			{Use: "delete", Aliases: []string{"del", "rm"}},
+			{Use: "get"},
+		},
+	}
+	actual := &inspector.InspectedCLI{
+		Use:     "testcli",
+		Aliases: []string{"tc"},
+		Commands: []inspector.InspectedCommand{
+			{Use: "delete", Aliases: []string{"remove", "del"}},
+			{Use: "get", Aliases: []string{"g"}},
+		},
+	}
+
+	result := ValidateInOrder(expected, actual)
+	want := []ValidationError{{Type: ErrorTypeMissing, Path: "delete", Expected: "rm"}}
+	if len(result.Errors) != len(want) || !errorsMatch(want[0], result.Errors[0]) {
+		t.Fatalf("Validate() errors = %+v, want %+v", result.Errors, want)
+	}
+	if result.Errors[0].Message != "alias 'rm'" {
+		t.Errorf("Message = %q, want the alias named", result.Errors[0].Message)
+	}
+
+	result = ValidateWithOptions(expected, actual, Options{Strict: true})
+	sortErrors(result.Errors)
+	want = []ValidationError{
+		{Type: ErrorTypeMissing, Path: "delete", Expected: "rm"},
+		{Type: ErrorTypeUnexpected, Path: "delete", Actual: "remove"},
+		{Type: ErrorTypeUnexpected, Path: "get", Actual: "g"},
+	}
+	if len(result.Errors) != len(want) {
+		t.Fatalf("ValidateWithOptions() errors = %+v, want %+v", result.Errors, want)
+	}
+	for i := range want {
+		if !errorsMatch(want[i], result.Errors[i]) {
+			t.Errorf("error %d = %+v, want %+v", i, result.Errors[i], want[i])
+		}
+	}
+}
+
+func TestValidateWithOptions_Strict(t *testing.T) {
+	runnable, notRunnable := true, false
+	expected := &contract.Contract{
+		Use: "testcli",
+		Commands: []contract.Command{
+			{Use: "db", Runnable: &notRunnable, Commands: []contract.Command{{Use: "migrate", Runnable: &runnable}}},
+		},
+	}
+	actual := &inspector.InspectedCLI{
+		Use: "testcli",
+		Flags: []inspector.InspectedFlag{
+			{Name: "debug", Type: "bool", Hidden: true},
+			{Name: "legacy", Type: "bool", Deprecated: "it has no effect"},
+		},
+		Commands: []inspector.InspectedCommand{
+			{Use: "db", Commands: []inspector.InspectedCommand{{Use: "migrate", Runnable: true}}},
+			{Use: "internal", Hidden: true, Runnable: true},
+			{Use: "topics"},
+		},
+	}
+
+	if result := ValidateWithOptions(expected, actual, Options{}); !result.IsValid() || result.Strict {
+		t.Fatalf("ValidateWithOptions() errors = %+v, want none without Strict", result.Errors)
+	}
+
+	result := ValidateWithOptions(expected, actual, Options{Strict: true})
+	if result.IsValid() || !result.Strict {
+		t.Fatalf("ValidateWithOptions() = %+v, want a strict, invalid result", result)
+	}
+	var paths []string
+	for _, err := range result.Errors {
+		if err.Type != ErrorTypeUnexpected {
+			t.Errorf("error %+v, want only unexpected errors", err)
+		}
+		paths = append(paths, err.Path)
+	}
+	want := []string{"--debug", "--legacy", "internal", "topics"}
+	sort.Strings(paths)
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("unexpected paths = %v, want %v", paths, want)
+	}
+}
@@ -0,0 +1,133 @@
+package validator
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/contract"
+	"github.com/hiAndrewQuinn/cliguard/internal/inspector"
+)
+
+// semverPattern matches a semantic version with an optional "v" prefix,
+// pre-release and build metadata, e.g. "v1.4.2-rc.1+build.5"
+var semverPattern = regexp.MustCompile(`v?(\d+)\.(\d+)\.(\d+)(?:-([0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*))?(?:\+[0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*)?`)
+
+// semver is a parsed semantic version. Build metadata is dropped, since it
+// doesn't take part in comparisons.
+type semver struct {
+	text       string
+	numbers    [3]int
+	prerelease []string
+}
+
+// parseSemver parses a version such as "1.4.2" or "v1.4.2-rc.1"
+func parseSemver(s string) (semver, bool) {
+	s = strings.TrimSpace(s)
+	if loc := semverPattern.FindStringIndex(s); loc == nil || loc[0] != 0 || loc[1] != len(s) {
+		return semver{}, false
+	}
+	return findSemver(s)
+}
+
+// findSemver returns the first semantic version in text, such as the
+// output of "mycli --version"
+func findSemver(text string) (semver, bool) {
+	match := semverPattern.FindStringSubmatch(text)
+	if match == nil {
+		return semver{}, false
+	}
+	version := semver{text: match[0]}
+	for i := range version.numbers {
+		n, err := strconv.Atoi(match[i+1])
+		if err != nil {
+			return semver{}, false
+		}
+		version.numbers[i] = n
+	}
+	if match[4] != "" {
+		version.prerelease = strings.Split(match[4], ".")
+	}
+	return version, true
+}
+
+// compareSemver compares versions by semantic versioning precedence,
+// returning -1, 0 or +1. A pre-release has lower precedence than its
+// release, and pre-release identifiers are compared one by one: numerically
+// if both are numbers, with numbers lower than other identifiers, and
+// otherwise in ASCII order.
+func compareSemver(a, b semver) int {
+	for i := range a.numbers {
+		if c := compareInts(a.numbers[i], b.numbers[i]); c != 0 {
+			return c
+		}
+	}
+
+	switch {
+	case len(a.prerelease) == 0 && len(b.prerelease) == 0:
+		return 0
+	case len(a.prerelease) == 0:
+		return 1
+	case len(b.prerelease) == 0:
+		return -1
+	}
+	for i := 0; i < len(a.prerelease) && i < len(b.prerelease); i++ {
+		x, errX := strconv.Atoi(a.prerelease[i])
+		y, errY := strconv.Atoi(b.prerelease[i])
+		var c int
+		switch {
+		case errX == nil && errY == nil:
+			c = compareInts(x, y)
+		case errX == nil:
+			c = -1
+		case errY == nil:
+			c = 1
+		default:
+			c = strings.Compare(a.prerelease[i], b.prerelease[i])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return compareInts(len(a.prerelease), len(b.prerelease))
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// validateVersion compares the contract's version with the first semantic
+// version in the CLI's version output, or in cobra.Command.Version if the
+// CLI printed nothing. Versions that differ only in a "v" prefix or in build
+// metadata match.
+func validateVersion(expected *contract.Contract, actual *inspector.InspectedCLI, result *ValidationResult) {
+	want, ok := parseSemver(expected.Version)
+	if !ok {
+		result.AddErrorWithDescription(ErrorTypeMismatch, "root", expected.Version, "",
+			"Invalid version in contract",
+			"The contract's version must be a semantic version such as 1.4.2")
+		return
+	}
+
+	output := strings.TrimSpace(actual.VersionOutput)
+	if output == "" {
+		output = actual.Version
+	}
+	got, ok := findSemver(output)
+	if !ok {
+		result.AddErrorWithDescription(ErrorTypeMissing, "root", expected.Version, output,
+			"Version not found",
+			"The CLI's version output contains no semantic version; use --version-command to choose how it is run")
+		return
+	}
+
+	if compareSemver(want, got) != 0 {
+		result.AddError(ErrorTypeMismatch, "root", expected.Version, got.text, "Mismatch in version")
+	}
+}
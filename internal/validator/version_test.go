@@ -0,0 +1,147 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/hiAndrewQuinn/cliguard/internal/contract"
+	"github.com/hiAndrewQuinn/cliguard/internal/inspector"
+)
+
+func TestParseSemver(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"1.4.2", true},
+		{"v1.4.2", true},
+		{" 1.4.2-rc.1+build.5 ", true},
+		{"1.4", false},
+		{"mycli 1.4.2", false},
+		{"1.4.2 (commit abc)", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if _, got := parseSemver(tt.input); got != tt.want {
+			t.Errorf("parseSemver(%q) ok = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestFindSemver(t *testing.T) {
+	tests := []struct {
+		output string
+		want   string
+	}{
+		{"mycli version 1.4.2", "1.4.2"},
+		{"mycli v2.0.0-beta.3 (commit abc1234, built with go1.24)", "v2.0.0-beta.3"},
+		{"Version: 0.9.1+20240101\nGo: go1.24.4", "0.9.1+20240101"},
+		{"mycli dev", ""},
+	}
+	for _, tt := range tests {
+		got, ok := findSemver(tt.output)
+		if ok != (tt.want != "") || got.text != tt.want {
+			t.Errorf("findSemver(%q) = %q, %v; want %q", tt.output, got.text, ok, tt.want)
+		}
+	}
+}
+
+func TestCompareSemver(t *testing.T) {
+	// Ordered by precedence, as in the semver specification's example
+	ordered := []string{
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+		"1.0.1",
+		"1.2.0",
+		"10.0.0",
+	}
+	for i := range ordered {
+		for j := range ordered {
+			a, _ := parseSemver(ordered[i])
+			b, _ := parseSemver(ordered[j])
+			want := compareInts(i, j)
+			if got := compareSemver(a, b); got != want {
+				t.Errorf("compareSemver(%s, %s) = %d, want %d", ordered[i], ordered[j], got, want)
+			}
+		}
+	}
+
+	a, _ := parseSemver("v1.4.2+linux")
+	b, _ := parseSemver("1.4.2")
+	if got := compareSemver(a, b); got != 0 {
+		t.Errorf("compareSemver(v1.4.2+linux, 1.4.2) = %d, want 0", got)
+	}
+}
+
+func TestValidateWithOptions_ExpectVersion(t *testing.T) {
+	tests := []struct {
+		name        string
+		version     string
+		actual      *inspector.InspectedCLI
+		wantMessage string
+		wantActual  string
+	}{
+		{
+			name:    "version output matches",
+			version: "1.4.2",
+			actual:  &inspector.InspectedCLI{Use: "app", VersionOutput: "app version v1.4.2\n"},
+		},
+		{
+			name:    "falls back to cobra Version",
+			version: "1.4.2",
+			actual:  &inspector.InspectedCLI{Use: "app", Version: "1.4.2"},
+		},
+		{
+			name:        "mismatch",
+			version:     "1.4.2",
+			actual:      &inspector.InspectedCLI{Use: "app", VersionOutput: "app 1.5.0-rc.1 (commit abc)"},
+			wantMessage: "Mismatch in version",
+			wantActual:  "1.5.0-rc.1",
+		},
+		{
+			name:        "no version in output",
+			version:     "1.4.2",
+			actual:      &inspector.InspectedCLI{Use: "app", VersionOutput: "app dev build"},
+			wantMessage: "Version not found",
+			wantActual:  "app dev build",
+		},
+		{
+			name:        "invalid contract version",
+			version:     "latest",
+			actual:      &inspector.InspectedCLI{Use: "app", Version: "1.4.2"},
+			wantMessage: "Invalid version in contract",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expected := &contract.Contract{Use: "app", Version: tt.version}
+			result := ValidateWithOptions(expected, tt.actual, Options{ExpectVersion: true})
+
+			if tt.wantMessage == "" {
+				if !result.IsValid() {
+					t.Errorf("ValidateWithOptions() errors = %+v, want none", result.Errors)
+				}
+				return
+			}
+			if len(result.Errors) != 1 {
+				t.Fatalf("ValidateWithOptions() errors = %+v, want one", result.Errors)
+			}
+			got := result.Errors[0]
+			if got.Message != tt.wantMessage || got.Actual != tt.wantActual || got.Expected != tt.version {
+				t.Errorf("error = %+v, want %q with actual %q", got, tt.wantMessage, tt.wantActual)
+			}
+		})
+	}
+
+	// Without the option the version is not checked
+	expected := &contract.Contract{Use: "app", Version: "1.4.2"}
+	if result := Validate(expected, &inspector.InspectedCLI{Use: "app", Version: "2.0.0"}); !result.IsValid() {
+		t.Errorf("Validate() errors = %+v, want the version ignored", result.Errors)
+	}
+}
@@ -0,0 +1,8 @@
+// Package version holds the version of cliguard reported in machine-readable
+// output. Release builds set it with:
+//
+//	go build -ldflags "-X github.com/hiAndrewQuinn/cliguard/internal/version.Version=1.2.3"
+package version
+
+// Version is cliguard's semantic version
+var Version = "0.1.0"
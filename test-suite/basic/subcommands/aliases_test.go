@@ -0,0 +1,90 @@
+//go:build integration
+
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const entrypoint = "github.com/cliguard/test/subcommands/cmd.NewRootCmd"
+
+// TestAliasesRoundTrip generates a contract for this CLI, checks that it
+// records the aliases of list and delete, and validates the CLI against it.
+// The internal packages of cliguard can't be imported from this module, so
+// the test builds cliguard from the repository root and runs it.
+func TestAliasesRoundTrip(t *testing.T) {
+	projectPath, err := filepath.Abs(".")
+	if err != nil {
+		t.Fatalf("failed to resolve project path: %v", err)
+	}
+	cliguard := buildCliguard(t)
+
+	generate := exec.Command(cliguard, "generate", "--project-path", projectPath, "--entrypoint", entrypoint, "--output", "json")
+	output, err := generate.Output()
+	if err != nil {
+		t.Fatalf("cliguard generate failed: %v\n%s", err, stderr(err))
+	}
+
+	var generated struct {
+		Commands []struct {
+			Use     string   `json:"use"`
+			Aliases []string `json:"aliases"`
+		} `json:"commands"`
+	}
+	if err := json.Unmarshal(output, &generated); err != nil {
+		t.Fatalf("failed to parse generated contract: %v\n%s", err, output)
+	}
+	aliases := make(map[string][]string)
+	for _, cmd := range generated.Commands {
+		aliases[cmd.Use] = cmd.Aliases
+	}
+	want := map[string][]string{
+		"config": nil,
+		"create": nil,
+		"delete": {"del", "rm"},
+		"list":   {"ls"},
+	}
+	if !reflect.DeepEqual(aliases, want) {
+		t.Errorf("command aliases = %v, want %v\n%s", aliases, want, output)
+	}
+
+	// JSON is YAML too, so the contract loads as it was generated
+	contractPath := filepath.Join(t.TempDir(), "cliguard.json")
+	if err := os.WriteFile(contractPath, output, 0644); err != nil {
+		t.Fatal(err)
+	}
+	validate := exec.Command(cliguard, "validate", "--project-path", projectPath, "--entrypoint", entrypoint, "--contract", contractPath)
+	if report, err := validate.CombinedOutput(); err != nil {
+		t.Errorf("the CLI does not match the contract generated from it: %v\n%s", err, report)
+	}
+}
+
+// buildCliguard builds cliguard from the repository root into a temporary
+// directory and returns the path of the binary
+func buildCliguard(t *testing.T) string {
+	t.Helper()
+	root, err := filepath.Abs(filepath.Join("..", "..", ".."))
+	if err != nil {
+		t.Fatalf("failed to resolve repository root: %v", err)
+	}
+	binary := filepath.Join(t.TempDir(), "cliguard")
+	build := exec.Command("go", "build", "-o", binary, ".")
+	build.Dir = root
+	if output, err := build.CombinedOutput(); err != nil {
+		t.Fatalf("failed to build cliguard: %v\n%s", err, output)
+	}
+	return binary
+}
+
+// stderr returns what a failed command wrote to stderr, if err has it
+func stderr(err error) []byte {
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		return exitErr.Stderr
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+// NewRootCmd creates a root command whose flags register completion functions
+func NewRootCmd() *cobra.Command {
+	rootCmd := &cobra.Command{
+		Use:   "completions",
+		Short: "A CLI with flag completion functions",
+	}
+
+	deployCmd := &cobra.Command{
+		Use:   "deploy",
+		Short: "Deploy to a region",
+		Run:   func(cmd *cobra.Command, args []string) {},
+	}
+	deployCmd.Flags().String("region", "us-east-1", "AWS region")
+	deployCmd.Flags().String("tier", "", "Service tier")
+	deployCmd.Flags().String("manifest", "", "Deployment manifest")
+
+	_ = deployCmd.RegisterFlagCompletionFunc("region", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"us-east-1", "eu-west-1", "ap-southeast-2"}, cobra.ShellCompDirectiveNoFileComp
+	})
+	// Completions may carry descriptions after a tab
+	_ = deployCmd.RegisterFlagCompletionFunc("tier", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"free\tNo SLA", "pro\t99.9% SLA"}, cobra.ShellCompDirectiveNoFileComp
+	})
+	// File extension filters are not flag values
+	_ = deployCmd.RegisterFlagCompletionFunc("manifest", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"yaml", "json"}, cobra.ShellCompDirectiveFilterFileExt
+	})
+
+	rootCmd.AddCommand(deployCmd)
+	return rootCmd
+}
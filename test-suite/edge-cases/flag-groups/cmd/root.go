@@ -0,0 +1,32 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+// NewRootCmd creates a root command whose flags are marked mutually
+// exclusive or required together
+func NewRootCmd() *cobra.Command {
+	rootCmd := &cobra.Command{
+		Use:   "flaggroups",
+		Short: "A CLI with flag groups",
+	}
+	rootCmd.PersistentFlags().Bool("json", false, "Print JSON")
+	rootCmd.PersistentFlags().Bool("yaml", false, "Print YAML")
+	rootCmd.MarkFlagsMutuallyExclusive("json", "yaml")
+
+	loginCmd := &cobra.Command{
+		Use:   "login",
+		Short: "Log in to the registry",
+		Run:   func(cmd *cobra.Command, args []string) {},
+	}
+	loginCmd.Flags().String("username", "", "Registry username")
+	loginCmd.Flags().String("password", "", "Registry password")
+	loginCmd.Flags().Bool("password-stdin", false, "Read the password from stdin")
+	loginCmd.Flags().String("token", "", "Access token")
+	loginCmd.MarkFlagsRequiredTogether("username", "password")
+	loginCmd.MarkFlagsMutuallyExclusive("password", "password-stdin")
+
+	// Groups may include inherited flags, once the parent is set
+	rootCmd.AddCommand(loginCmd)
+	loginCmd.MarkFlagsMutuallyExclusive("token", "username", "json")
+	return rootCmd
+}
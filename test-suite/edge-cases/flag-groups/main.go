@@ -0,0 +1,13 @@
+package main
+
+import (
+	"os"
+
+	"github.com/cliguard/test/flaggroups/cmd"
+)
+
+func main() {
+	if err := cmd.NewRootCmd().Execute(); err != nil {
+		os.Exit(1)
+	}
+}
\ No newline at end of file
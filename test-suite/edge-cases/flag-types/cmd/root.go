@@ -138,5 +138,9 @@ func newTestSubCmd() *cobra.Command {
 	cmd.Flags().StringVar(&localFlag, "local-string", "local", "Local string flag")
 	cmd.Flags().IntVar(&localInt, "local-int", 42, "Local int flag")
 
+	// Deprecated flags keep working until they are removed
+	cmd.Flags().Int("old-int", 0, "Old int flag")
+	cmd.Flags().MarkDeprecated("old-int", "use --local-int instead")
+
 	return cmd
 }
\ No newline at end of file
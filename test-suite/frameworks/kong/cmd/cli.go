@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/alecthomas/kong"
+)
+
+// CLI is the command-line interface of the kong fixture
+type CLI struct {
+	Verbose bool `help:"Enable verbose output" short:"v"`
+
+	Serve ServeCmd `cmd:"" help:"Start the server"`
+}
+
+// ServeCmd starts the server
+type ServeCmd struct {
+	Port int `help:"Port to listen on" default:"8080"`
+}
+
+// Run runs the serve command
+func (c *ServeCmd) Run() error {
+	fmt.Printf("Listening on :%d\n", c.Port)
+	return nil
+}
+
+// NewParser creates the Kong parser of the CLI
+func NewParser() (*kong.Kong, error) {
+	return kong.New(&CLI{}, kong.Name("kong-cli"), kong.Description("A Kong CLI"))
+}
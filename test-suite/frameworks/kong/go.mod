@@ -0,0 +1,5 @@
+module github.com/cliguard/test/kong
+
+go 1.24.4
+
+require github.com/alecthomas/kong v1.12.1
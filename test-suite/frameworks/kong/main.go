@@ -0,0 +1,17 @@
+package main
+
+import (
+	"os"
+
+	"github.com/cliguard/test/kong/cmd"
+)
+
+func main() {
+	parser, err := cmd.NewParser()
+	if err != nil {
+		os.Exit(1)
+	}
+	ctx, err := parser.Parse(os.Args[1:])
+	parser.FatalIfErrorf(err)
+	parser.FatalIfErrorf(ctx.Run())
+}
@@ -105,6 +105,7 @@ run_test "Subcommands CLI" "$SCRIPT_DIR/basic/subcommands" "github.com/cliguard/
 
 # Edge case tests
 run_test "Flag Types CLI" "$SCRIPT_DIR/edge-cases/flag-types" "github.com/cliguard/test/flagtypes/cmd.NewRootCmd" "fail-validate"
+run_test "Flag Groups CLI" "$SCRIPT_DIR/edge-cases/flag-groups" "github.com/cliguard/test/flaggroups/cmd.NewRootCmd"
 
 # Breaking change tests
 run_breaking_test "Breaking Changes" \
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func NewRootCmd() *cobra.Command {
+	var configFile string
+	rootCmd := &cobra.Command{
+		Use:   "completion-cli",
+		Short: "A test CLI with shell completion annotations",
+	}
+	rootCmd.PersistentFlags().StringVar(&configFile, "config", "", "config file")
+	_ = rootCmd.MarkPersistentFlagFilename("config", "yaml", "yml")
+
+	var outputDir, format, name string
+	exportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export data",
+		Run: func(cmd *cobra.Command, args []string) {
+			fmt.Printf("Exporting %s as %s to %s\n", name, format, outputDir)
+		},
+	}
+	exportCmd.Flags().StringVar(&outputDir, "output-dir", ".", "directory to write to")
+	exportCmd.Flags().StringVar(&format, "format", "json", "output format")
+	exportCmd.Flags().StringVar(&name, "name", "", "export name")
+	_ = exportCmd.MarkFlagDirname("output-dir")
+	_ = exportCmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"json", "yaml"}, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	rootCmd.AddCommand(exportCmd)
+
+	return rootCmd
+}
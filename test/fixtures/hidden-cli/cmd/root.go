@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func NewRootCmd() *cobra.Command {
+	rootCmd := &cobra.Command{
+		Use:   "hidden-cli",
+		Short: "A test CLI with hidden commands",
+	}
+
+	statusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show status",
+		Run: func(cmd *cobra.Command, args []string) {
+			fmt.Println("OK")
+		},
+	}
+
+	var verbose bool
+	debugCmd := &cobra.Command{
+		Use:    "debug",
+		Short:  "Internal debugging tools",
+		Hidden: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			fmt.Printf("Debug mode (verbose=%v)\n", verbose)
+		},
+	}
+	debugCmd.Flags().BoolVar(&verbose, "verbose", false, "verbose debug output")
+
+	rootCmd.AddCommand(statusCmd)
+	rootCmd.AddCommand(debugCmd)
+
+	return rootCmd
+}
@@ -0,0 +1,13 @@
+package backup
+
+import "github.com/spf13/cobra"
+
+func NewBackupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Back up the database",
+		Run:   func(cmd *cobra.Command, args []string) { cmd.Println("backup complete") },
+	}
+	cmd.Flags().StringP("output", "o", "backup.sql", "Output file")
+	return cmd
+}
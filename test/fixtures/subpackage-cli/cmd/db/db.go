@@ -0,0 +1,22 @@
+package db
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/test/subpackage-cli/cmd/db/backup"
+)
+
+func NewDBCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "db",
+		Short: "Manage the database",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List tables",
+		Run:   func(cmd *cobra.Command, args []string) { cmd.Println("users") },
+	})
+	cmd.AddCommand(backup.NewBackupCmd())
+
+	return cmd
+}
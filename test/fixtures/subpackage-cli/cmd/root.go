@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/test/subpackage-cli/cmd/db"
+	"github.com/test/subpackage-cli/cmd/user"
+)
+
+func NewRootCmd() *cobra.Command {
+	rootCmd := &cobra.Command{
+		Use:   "subpackage-cli",
+		Short: "A test CLI with commands in several packages",
+	}
+	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "Enable verbose output")
+
+	rootCmd.AddCommand(db.NewDBCmd())
+	rootCmd.AddCommand(user.NewUserCmd())
+	rootCmd.AddCommand(&cobra.Command{
+		Use:   "version",
+		Short: "Print the version",
+		Run:   func(cmd *cobra.Command, args []string) { cmd.Println("subpackage-cli 1.0.0") },
+	})
+
+	return rootCmd
+}
@@ -0,0 +1,28 @@
+package user
+
+import "github.com/spf13/cobra"
+
+func NewUserCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "user",
+		Short: "Manage users",
+	}
+
+	// Shares its name with db list, in another package
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List users",
+		Run:   func(cmd *cobra.Command, args []string) { cmd.Println("alice") },
+	})
+
+	create := &cobra.Command{
+		Use:   "create [username]",
+		Short: "Create a user",
+		Args:  cobra.ExactArgs(1),
+		Run:   func(cmd *cobra.Command, args []string) { cmd.Printf("Creating user: %s\n", args[0]) },
+	}
+	create.Flags().String("email", "", "User email address")
+	cmd.AddCommand(create)
+
+	return cmd
+}
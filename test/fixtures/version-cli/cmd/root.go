@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// Version is printed by --version. The version subcommand reports the
+// build's pre-release instead, so tests can tell which one ran.
+const Version = "1.4.2"
+
+func NewRootCmd() *cobra.Command {
+	rootCmd := &cobra.Command{
+		Use:     "version-cli",
+		Short:   "A test CLI that reports its version",
+		Version: Version,
+	}
+
+	rootCmd.AddCommand(&cobra.Command{
+		Use:   "version",
+		Short: "Print the build version",
+		Run: func(cmd *cobra.Command, args []string) {
+			// Printed with fmt rather than cmd.Println, as many CLIs do
+			fmt.Println("version-cli v1.5.0-rc.1 (commit 3f2a9c1)")
+		},
+	})
+
+	return rootCmd
+}